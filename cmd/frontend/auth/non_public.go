@@ -67,6 +67,12 @@ var (
 		router.SignOut:           {},
 		router.ResetPasswordInit: {},
 		router.ResetPasswordCode: {},
+		// Access to snippet share links is checked by the handler (with the link's secret token).
+		router.SnippetShareLink: {},
+		// Access to feeds is checked by the handler (with the feed token, which is an access token).
+		router.FeedSavedSearch: {},
+		router.FeedRepoCommits: {},
+		router.FeedCampaign:    {},
 	}
 	anonymousAccessibleUIRoutes = map[string]struct{}{
 		uirouter.RouteSignIn:        {},
@@ -110,6 +116,11 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		return true
 	}
 
+	// Permission is checked by github token, like LSIF uploads
+	if strings.HasPrefix(req.URL.Path, "/.api/coverage/upload") {
+		return true
+	}
+
 	// This is just a redirect to a public download
 	if strings.HasPrefix(req.URL.Path, "/.api/src-cli") {
 		return true
@@ -124,6 +135,11 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		return true
 	}
 
+	// Requests from Slack are authenticated by their signature in the handler itself.
+	if strings.HasPrefix(req.URL.Path, "/.api/slack/") {
+		return true
+	}
+
 	apiRouteName := matchedRouteName(req, router.Router())
 	if apiRouteName == router.UI {
 		// Test against UI router. (Some of its handlers inject private data into the title or meta tags.)
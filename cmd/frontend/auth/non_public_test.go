@@ -38,6 +38,11 @@ func TestAllowAnonymousRequest(t *testing.T) {
 		{req: req("POST", "/"), want: false},
 		{req: req("POST", "/-/sign-in"), want: true},
 		{req: req("GET", "/sign-in"), want: true},
+		{req: req("GET", "/-/snippet/abc"), want: true},
+		{req: req("POST", "/-/snippet/abc"), want: true},
+		{req: req("POST", "/.api/coverage/upload"), want: true},
+		{req: req("POST", "/.api/slack/commands"), want: true},
+		{req: req("GET", "/-/slack/link"), want: false},
 		{req: req("GET", "/doesntexist"), want: false},
 		{req: req("POST", "/doesntexist"), want: false},
 		{req: req("GET", "/doesnt/exist"), want: false},
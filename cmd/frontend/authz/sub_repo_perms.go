@@ -0,0 +1,230 @@
+package authz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// PathRule restricts read access to all paths of a repository under Prefix. A path is governed
+// by the rule with the longest matching prefix; paths not governed by any rule are readable by
+// everyone who can read the repository.
+type PathRule struct {
+	// Prefix is the repository-relative directory or file path the rule applies to, without a
+	// leading or trailing slash (e.g. "services/billing").
+	Prefix string
+	// Allow is whether the user the rule was computed for may read paths under Prefix.
+	Allow bool
+}
+
+// SubRepoPerms is the set of path rules of a single repository as they apply to a single user.
+// The zero value (and a nil pointer) grants access to every path.
+type SubRepoPerms struct {
+	Rules []PathRule
+}
+
+// Restricted returns true if there is at least one path the user may not read.
+func (p *SubRepoPerms) Restricted() bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Rules {
+		if !r.Allow {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed returns true if the user may read the file or directory at the given
+// repository-relative path.
+func (p *SubRepoPerms) Allowed(name string) bool {
+	if p == nil {
+		return true
+	}
+	name = CleanRulePath(name)
+
+	allow, longest := true, -1
+	for _, r := range p.Rules {
+		if len(r.Prefix) > longest && pathHasPrefix(name, r.Prefix) {
+			allow, longest = r.Allow, len(r.Prefix)
+		}
+	}
+	return allow
+}
+
+// AllowedDir returns true if the user may list the directory at the given repository-relative
+// path. Unlike Allowed, it also returns true for the ancestors of readable paths nested in an
+// otherwise unreadable directory, so that those paths stay reachable when browsing.
+func (p *SubRepoPerms) AllowedDir(name string) bool {
+	if p.Allowed(name) {
+		return true
+	}
+	name = CleanRulePath(name)
+	for _, r := range p.Rules {
+		if r.Allow && (name == "" || strings.HasPrefix(r.Prefix, name+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedTree returns true if the user may read the file or directory at the given
+// repository-relative path and, if it is a directory, everything below it.
+func (p *SubRepoPerms) AllowedTree(name string) bool {
+	if p == nil {
+		return true
+	}
+	if !p.Allowed(name) {
+		return false
+	}
+	name = CleanRulePath(name)
+	for _, r := range p.Rules {
+		if !r.Allow && (name == "" || strings.HasPrefix(r.Prefix, name+"/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge returns the combination of p and other, under which a path is readable only if it is
+// readable according to both.
+func (p *SubRepoPerms) Merge(other *SubRepoPerms) *SubRepoPerms {
+	if p == nil {
+		return other
+	}
+	if other == nil {
+		return p
+	}
+
+	// A path readable under both inputs must be readable under the longest prefix rule of each. We
+	// compute that by re-evaluating every prefix of either input against both.
+	prefixes := make(map[string]struct{}, len(p.Rules)+len(other.Rules))
+	for _, r := range p.Rules {
+		prefixes[r.Prefix] = struct{}{}
+	}
+	for _, r := range other.Rules {
+		prefixes[r.Prefix] = struct{}{}
+	}
+
+	merged := &SubRepoPerms{Rules: make([]PathRule, 0, len(prefixes))}
+	for prefix := range prefixes {
+		merged.Rules = append(merged.Rules, PathRule{
+			Prefix: prefix,
+			Allow:  p.Allowed(prefix) && other.Allowed(prefix),
+		})
+	}
+	return merged
+}
+
+// CleanRulePath normalizes a repository-relative path for comparison with PathRule prefixes.
+func CleanRulePath(name string) string {
+	name = path.Clean("/" + name)
+	return strings.Trim(name, "/")
+}
+
+func pathHasPrefix(name, prefix string) bool {
+	return prefix == "" || name == prefix || strings.HasPrefix(name, prefix+"/")
+}
+
+// SubRepoPermsProvider is a source of path-level permission rules within repositories, which
+// further restrict access to repositories that the user is already authorized to view by the
+// repository-level authz providers.
+type SubRepoPermsProvider interface {
+	// SubRepoPerms returns the path rules of repo as they apply to user. The user is nil for
+	// anonymous users. A nil return value means that the provider has no rules for the repository.
+	//
+	// Implementations should only use the user and repo parameters to compute the rules, and
+	// should not try to get the currently authenticated user from ctx.
+	SubRepoPerms(ctx context.Context, user *types.User, repo *types.Repo) (*SubRepoPerms, error)
+}
+
+var (
+	subRepoPermsProvidersMu sync.RWMutex
+	subRepoPermsProviders   []SubRepoPermsProvider
+)
+
+// SetSubRepoPermsProviders sets the current sub-repository permissions providers. It is
+// concurrency-safe.
+func SetSubRepoPermsProviders(ps []SubRepoPermsProvider) {
+	subRepoPermsProvidersMu.Lock()
+	defer subRepoPermsProvidersMu.Unlock()
+	subRepoPermsProviders = ps
+}
+
+// GetSubRepoPermsProviders returns the current sub-repository permissions providers. It is
+// concurrency-safe.
+func GetSubRepoPermsProviders() []SubRepoPermsProvider {
+	subRepoPermsProvidersMu.RLock()
+	defer subRepoPermsProvidersMu.RUnlock()
+
+	if subRepoPermsProviders == nil {
+		return nil
+	}
+	ps := make([]SubRepoPermsProvider, len(subRepoPermsProviders))
+	copy(ps, subRepoPermsProviders)
+	return ps
+}
+
+// PathRulesFileName is the path of the file within a repository from which the in-repository
+// sub-repository permissions provider reads path rules.
+const PathRulesFileName = ".sourcegraph/path-permissions"
+
+// PathRulesEntry is a single entry of a path rules file. It restricts read access to paths under
+// Prefix to the listed users and organizations.
+type PathRulesEntry struct {
+	Prefix    string
+	Usernames []string
+	OrgNames  []string
+}
+
+// ParsePathRulesFile parses the contents of a path rules file. Each non-empty line that is not a
+// comment (starting with "#") consists of a path prefix followed by the principals that may read
+// it, separated by whitespace. Principals are either "@username" or "@org:orgname":
+//
+//   # Only the billing team may read billing code.
+//   services/billing    @org:billing @alice
+//   services/billing/api @org:billing @org:frontend
+//
+// A prefix without any principals is readable by nobody except site admins.
+func ParsePathRulesFile(data []byte) ([]PathRulesEntry, error) {
+	var entries []PathRulesEntry
+	seen := map[string]int{}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		entry := PathRulesEntry{Prefix: CleanRulePath(fields[0])}
+		for _, f := range fields[1:] {
+			switch {
+			case strings.HasPrefix(f, "@org:"):
+				if len(f) == len("@org:") {
+					return nil, fmt.Errorf("line %d: empty organization name", line)
+				}
+				entry.OrgNames = append(entry.OrgNames, strings.TrimPrefix(f, "@org:"))
+			case strings.HasPrefix(f, "@") && len(f) > len("@"):
+				entry.Usernames = append(entry.Usernames, strings.TrimPrefix(f, "@"))
+			default:
+				return nil, fmt.Errorf("line %d: invalid principal %q (must be @username or @org:name)", line, f)
+			}
+		}
+
+		if i, ok := seen[entry.Prefix]; ok {
+			return nil, fmt.Errorf("line %d: duplicate rule for path %q (first defined on line %d)", line, entry.Prefix, i)
+		}
+		seen[entry.Prefix] = line
+		entries = append(entries, entry)
+	}
+	return entries, s.Err()
+}
@@ -0,0 +1,126 @@
+package authz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubRepoPerms_Allowed(t *testing.T) {
+	perms := &SubRepoPerms{Rules: []PathRule{
+		{Prefix: "secret", Allow: false},
+		{Prefix: "secret/public", Allow: true},
+		{Prefix: "docs/internal.md", Allow: false},
+	}}
+
+	for _, tc := range []struct {
+		path                    string
+		allowed, dir, wholeTree bool
+	}{
+		{path: "", allowed: true, dir: true, wholeTree: false},
+		{path: "/", allowed: true, dir: true, wholeTree: false},
+		{path: "README.md", allowed: true, dir: true, wholeTree: true},
+		{path: "secret", allowed: false, dir: true, wholeTree: false},
+		{path: "secret/", allowed: false, dir: true, wholeTree: false},
+		{path: "secret/key.pem", allowed: false, dir: false, wholeTree: false},
+		{path: "/secret/../secret/key.pem", allowed: false, dir: false, wholeTree: false},
+		{path: "secretive/file", allowed: true, dir: true, wholeTree: true},
+		{path: "secret/public", allowed: true, dir: true, wholeTree: true},
+		{path: "secret/public/a/b.go", allowed: true, dir: true, wholeTree: true},
+		{path: "secret/publicity", allowed: false, dir: false, wholeTree: false},
+		{path: "docs", allowed: true, dir: true, wholeTree: false},
+		{path: "docs/internal.md", allowed: false, dir: false, wholeTree: false},
+		{path: "docs/external.md", allowed: true, dir: true, wholeTree: true},
+	} {
+		if got := perms.Allowed(tc.path); got != tc.allowed {
+			t.Errorf("Allowed(%q): got %v, want %v", tc.path, got, tc.allowed)
+		}
+		if got := perms.AllowedDir(tc.path); got != tc.dir {
+			t.Errorf("AllowedDir(%q): got %v, want %v", tc.path, got, tc.dir)
+		}
+		if got := perms.AllowedTree(tc.path); got != tc.wholeTree {
+			t.Errorf("AllowedTree(%q): got %v, want %v", tc.path, got, tc.wholeTree)
+		}
+	}
+
+	var unrestricted *SubRepoPerms
+	if !unrestricted.Allowed("secret") || !unrestricted.AllowedTree("") || unrestricted.Restricted() {
+		t.Error("nil SubRepoPerms must allow everything")
+	}
+	if !perms.Restricted() {
+		t.Error("want perms to be restricted")
+	}
+}
+
+func TestSubRepoPerms_Merge(t *testing.T) {
+	a := &SubRepoPerms{Rules: []PathRule{
+		{Prefix: "a", Allow: false},
+		{Prefix: "a/b", Allow: true},
+	}}
+	b := &SubRepoPerms{Rules: []PathRule{
+		{Prefix: "a/b/c", Allow: false},
+		{Prefix: "d", Allow: true},
+	}}
+	merged := a.Merge(b)
+
+	for path, want := range map[string]bool{
+		"":        true,
+		"a":       false,
+		"a/x":     false,
+		"a/b":     true,
+		"a/b/x":   true,
+		"a/b/c":   false,
+		"a/b/c/x": false,
+		"d":       true,
+		"e":       true,
+	} {
+		if got := merged.Allowed(path); got != want {
+			t.Errorf("Allowed(%q): got %v, want %v", path, got, want)
+		}
+		if got, want := merged.Allowed(path), a.Allowed(path) && b.Allowed(path); got != want {
+			t.Errorf("Allowed(%q) differs from allowed by both inputs: got %v, want %v", path, got, want)
+		}
+	}
+
+	if got := (*SubRepoPerms)(nil).Merge(b); got != b {
+		t.Errorf("nil.Merge(b): got %v, want b", got)
+	}
+	if got := a.Merge(nil); got != a {
+		t.Errorf("a.Merge(nil): got %v, want a", got)
+	}
+}
+
+func TestParsePathRulesFile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		entries, err := ParsePathRulesFile([]byte(`
+# Only the billing team may read billing code.
+services/billing/     @org:billing @alice
+/services/billing/api @org:billing   @org:frontend
+
+vendor/secret
+`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []PathRulesEntry{
+			{Prefix: "services/billing", Usernames: []string{"alice"}, OrgNames: []string{"billing"}},
+			{Prefix: "services/billing/api", OrgNames: []string{"billing", "frontend"}},
+			{Prefix: "vendor/secret"},
+		}
+		if !reflect.DeepEqual(entries, want) {
+			t.Errorf("got %+v, want %+v", entries, want)
+		}
+	})
+
+	for name, data := range map[string]string{
+		"invalid principal": "a alice",
+		"empty username":    "a @",
+		"empty org name":    "a @org:",
+		"duplicate prefix":  "a @alice\n/a/ @bob",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePathRulesFile([]byte(data)); err == nil {
+				t.Error("got nil error")
+			}
+		})
+	}
+}
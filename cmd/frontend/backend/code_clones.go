@@ -0,0 +1,415 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/clones"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// maxCloneIndexFileSize is the size of the largest file that is indexed for code clone
+	// detection. Larger files are usually generated.
+	maxCloneIndexFileSize = 512 * 1024
+
+	// maxCloneIndexFiles is the maximum number of files that are indexed per update of a repository.
+	maxCloneIndexFiles = 20000
+
+	// maxCodeCloneMatches is the maximum number of indexed fingerprints that are compared with a
+	// file when looking for its clones.
+	maxCodeCloneMatches = 10000
+
+	// minCodeCloneFingerprints is the minimum number of fingerprints that a region must share with
+	// a file to be reported as a clone. Single shared fingerprints are mostly boilerplate.
+	minCodeCloneFingerprints = 2
+
+	// codeCloneRegionGap is the maximum number of lines between two matching fingerprints of a file
+	// that are part of the same clone region.
+	codeCloneRegionGap = 10
+
+	// maxCodeCloneClusters is the number of clusters in the site-wide report of the largest clone
+	// clusters (before permission filtering).
+	maxCodeCloneClusters = 100
+)
+
+// codeCloneClustersCache caches the site-wide report of the largest clone clusters, because it is
+// computed from all fingerprints of the index.
+var codeCloneClustersCache = rcache.NewWithTTL("code_clone_clusters:v1", 60*60)
+
+// CodeCloneDetectionEnabled reports whether the "codeCloneDetection" experimental feature is
+// enabled.
+func CodeCloneDetectionEnabled() bool {
+	c := conf.Get()
+	return c.ExperimentalFeatures != nil && c.ExperimentalFeatures.CodeCloneDetection == "enabled"
+}
+
+// UpdateCloneIndex updates the code clone index of the repository to the head of its default
+// branch. Only the files that changed since the last indexed commit are indexed, unless the
+// repository wasn't indexed before (or the last indexed commit no longer exists).
+func UpdateCloneIndex(ctx context.Context, repo *types.Repo) error {
+	// 🚨 SECURITY: The index contains all files. The permissions of the actor are checked when
+	// clones are looked up (see FindCodeClones and LargestCodeCloneClusters).
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	gitRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return err
+	}
+	head, err := git.ResolveRevision(ctx, *gitRepo, nil, "HEAD", &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if vcs.IsRepoNotExist(err) || gitserver.IsRevisionNotFound(err) {
+		return nil // not cloned yet, or empty
+	} else if err != nil {
+		return err
+	}
+
+	lastCommit, err := db.CodeClones.GetRepoCommit(ctx, repo.ID)
+	if err != nil || lastCommit == head {
+		return err
+	}
+
+	if lastCommit != "" {
+		// The last indexed commit may no longer exist (e.g., because of a force push), in which
+		// case the changed files can't be determined.
+		_, err := git.ResolveRevision(ctx, *gitRepo, nil, string(lastCommit), &git.ResolveRevisionOptions{NoEnsureRevision: true})
+		if gitserver.IsRevisionNotFound(err) {
+			lastCommit = ""
+		} else if err != nil {
+			return err
+		}
+	}
+
+	u := &db.CloneIndexUpdate{CommitID: head}
+	var paths []string
+	if lastCommit != "" {
+		if paths, u.RemovedPaths, err = changedFiles(ctx, *gitRepo, lastCommit, head); err != nil {
+			return err
+		}
+	} else {
+		u.Reset = true
+		fis, err := git.ReadDir(ctx, *gitRepo, head, "", true)
+		if err != nil {
+			return err
+		}
+		for _, fi := range fis {
+			if fi.Mode().IsRegular() && fi.Size() <= maxCloneIndexFileSize {
+				paths = append(paths, fi.Name())
+			}
+		}
+	}
+
+	for _, path := range paths {
+		if !clones.Supported(path) {
+			continue
+		}
+		if len(u.Files) == maxCloneIndexFiles {
+			log15.Warn("Too many files to index for code clone detection.", "repo", repo.Name, "limit", maxCloneIndexFiles)
+			break
+		}
+		content, err := git.ReadFile(ctx, *gitRepo, head, path, maxCloneIndexFileSize+1)
+		if err != nil {
+			return err
+		}
+		if len(content) > maxCloneIndexFileSize {
+			continue
+		}
+		if fps := clones.FileFingerprints(path, content); len(fps) > 0 {
+			u.Files = append(u.Files, &db.CloneIndexFile{Path: path, Fingerprints: fps})
+		}
+	}
+	return db.CodeClones.UpdateRepo(ctx, repo.ID, u)
+}
+
+// changedFiles returns the files that were added or modified between two commits, and all files
+// that were added, modified or deleted (including both paths of renamed files).
+func changedFiles(ctx context.Context, repo gitserver.Repo, base, head api.CommitID) (changed, all []string, err error) {
+	rdr, err := git.ExecReader(ctx, repo, []string{"diff", "--name-status", "-z", string(base), string(head), "--"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rdr.Close()
+	out, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return nil, nil, err
+	}
+	changed, all = parseDiffNameStatus(out)
+	return changed, all, nil
+}
+
+// parseDiffNameStatus parses the output of `git diff --name-status -z`.
+func parseDiffNameStatus(out []byte) (changed, all []string) {
+	// The output is a sequence of a status (such as "M", "D" or "R100") followed by one path, or
+	// two paths for renames and copies, all terminated by NUL.
+	fields := strings.Split(string(bytes.TrimSuffix(out, []byte{0})), "\x00")
+	for i := 0; i < len(fields); {
+		status := fields[i]
+		if status == "" {
+			break
+		}
+		n := 1
+		if status[0] == 'R' || status[0] == 'C' {
+			n = 2
+		}
+		if i+n >= len(fields) {
+			break
+		}
+		paths := fields[i+1 : i+1+n]
+		all = append(all, paths...)
+		if status[0] != 'D' {
+			changed = append(changed, paths[n-1])
+		}
+		i += 1 + n
+	}
+	return changed, all
+}
+
+// A CodeClone is a region of an indexed file that duplicates part of another file.
+type CodeClone struct {
+	Repo      *types.Repo
+	CommitID  api.CommitID // the indexed commit
+	Path      string
+	StartLine int
+	EndLine   int
+
+	// Fingerprints is the number of fingerprints that the region shares with the other file (or,
+	// in a clone cluster, with the other regions).
+	Fingerprints int
+}
+
+// FindCodeClones returns the regions of indexed files that duplicate parts of the file at the
+// commit (or of its lines between startLine and endLine, if they are nonzero), those that share
+// the most fingerprints first. The file itself isn't included, and neither are regions that the
+// current actor may not view.
+//
+// 🚨 SECURITY: The caller must ensure that the actor may view the file.
+func FindCodeClones(ctx context.Context, repo *types.Repo, commit api.CommitID, path string, startLine, endLine int) ([]*CodeClone, error) {
+	if !clones.Supported(path) {
+		return nil, nil
+	}
+	gitRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	content, err := git.ReadFile(ctx, *gitRepo, commit, path, maxCloneIndexFileSize+1)
+	if err != nil || len(content) > maxCloneIndexFileSize {
+		return nil, err
+	}
+	fps := clones.FileFingerprints(path, content)
+	if startLine > 0 || endLine > 0 {
+		if endLine <= 0 {
+			endLine = math.MaxInt32
+		}
+		fps = clones.FilterLines(fps, startLine, endLine)
+	}
+	if len(fps) == 0 {
+		return nil, nil
+	}
+	seen := make(map[int64]bool, len(fps))
+	hashes := make([]int64, 0, len(fps))
+	for _, fp := range fps {
+		if !seen[fp.Hash] {
+			seen[fp.Hash] = true
+			hashes = append(hashes, fp.Hash)
+		}
+	}
+
+	matches, err := db.CodeClones.ListMatches(ctx, hashes, maxCodeCloneMatches)
+	if err != nil {
+		return nil, err
+	}
+
+	type fileKey struct {
+		repoID api.RepoID
+		path   string
+	}
+	byFile := map[fileKey][]*db.CloneMatch{}
+	var files []fileKey
+	for _, m := range matches {
+		k := fileKey{m.RepoID, m.Path}
+		if m.RepoID == repo.ID && m.Path == path {
+			continue
+		}
+		if _, ok := byFile[k]; !ok {
+			files = append(files, k)
+		}
+		byFile[k] = append(byFile[k], m)
+	}
+
+	filter := newCodeCloneFilter()
+	var found []*CodeClone
+	for _, k := range files {
+		for _, region := range cloneRegions(byFile[k]) {
+			if region.Fingerprints < minCodeCloneFingerprints {
+				continue
+			}
+			// 🚨 SECURITY: Omit regions of repositories and files that the actor may not view.
+			r, err := filter.repo(ctx, k.repoID, k.path)
+			if err != nil {
+				return nil, err
+			}
+			if r == nil {
+				break
+			}
+			region.Repo = r
+			found = append(found, region)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Fingerprints != b.Fingerprints {
+			return a.Fingerprints > b.Fingerprints
+		}
+		if a.Repo.Name != b.Repo.Name {
+			return a.Repo.Name < b.Repo.Name
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.StartLine < b.StartLine
+	})
+	return found, nil
+}
+
+// cloneRegions merges the matching fingerprints of a file into regions, such that fingerprints that
+// are at most codeCloneRegionGap lines apart are in the same region. The regions' Repo is not set.
+func cloneRegions(matches []*db.CloneMatch) []*CodeClone {
+	sort.Slice(matches, func(i, j int) bool { return matches[i].StartLine < matches[j].StartLine })
+
+	var (
+		regions []*CodeClone
+		cur     *CodeClone
+		hashes  map[int64]bool
+	)
+	for _, m := range matches {
+		if cur == nil || m.StartLine > cur.EndLine+codeCloneRegionGap {
+			cur = &CodeClone{CommitID: m.CommitID, Path: m.Path, StartLine: m.StartLine, EndLine: m.EndLine}
+			hashes = map[int64]bool{}
+			regions = append(regions, cur)
+		}
+		if m.EndLine > cur.EndLine {
+			cur.EndLine = m.EndLine
+		}
+		if !hashes[m.Hash] {
+			hashes[m.Hash] = true
+			cur.Fingerprints++
+		}
+	}
+	return regions
+}
+
+// A CodeCloneCluster is a set of regions of files that share the same fingerprints.
+type CodeCloneCluster struct {
+	Fingerprints int // the number of shared fingerprints
+	Clones       []*CodeClone
+}
+
+// LargestCodeCloneClusters returns the site-wide clusters of clones that share the most
+// fingerprints, largest first. Only regions that the current actor may view are included, and
+// clusters with fewer than two such regions are omitted.
+func LargestCodeCloneClusters(ctx context.Context) ([]*CodeCloneCluster, error) {
+	var clusters []*db.CloneCluster
+	if b, ok := codeCloneClustersCache.Get("clusters"); ok {
+		if err := json.Unmarshal(b, &clusters); err != nil {
+			log15.Warn("Failed to unmarshal cached JSON code clone clusters.", "err", err)
+			clusters = nil
+		}
+	}
+	if clusters == nil {
+		var err error
+		clusters, err = db.CodeClones.ListLargestClusters(ctx, maxCodeCloneClusters)
+		if err != nil {
+			return nil, err
+		}
+		if b, err := json.Marshal(clusters); err == nil {
+			codeCloneClustersCache.Set("clusters", b)
+		}
+	}
+
+	// 🚨 SECURITY: Omit the regions that the actor may not view. This happens after the cache
+	// lookup because the cache is shared by all users.
+	return filterCodeCloneClusters(ctx, clusters)
+}
+
+// filterCodeCloneClusters returns the clusters with only the regions that the current actor may
+// view, omitting clusters with fewer than two such regions.
+func filterCodeCloneClusters(ctx context.Context, clusters []*db.CloneCluster) ([]*CodeCloneCluster, error) {
+	filter := newCodeCloneFilter()
+	var visible []*CodeCloneCluster
+	for _, c := range clusters {
+		cluster := &CodeCloneCluster{Fingerprints: c.Fingerprints}
+		for _, region := range c.Regions {
+			r, err := filter.repo(ctx, region.RepoID, region.Path)
+			if err != nil {
+				return nil, err
+			}
+			if r == nil {
+				continue
+			}
+			cluster.Clones = append(cluster.Clones, &CodeClone{
+				Repo:         r,
+				CommitID:     region.CommitID,
+				Path:         region.Path,
+				StartLine:    region.StartLine,
+				EndLine:      region.EndLine,
+				Fingerprints: c.Fingerprints,
+			})
+		}
+		if len(cluster.Clones) >= 2 {
+			visible = append(visible, cluster)
+		}
+	}
+	return visible, nil
+}
+
+// codeCloneFilter checks whether the current actor may view the files of code clones. It caches
+// the repositories and their permissions.
+type codeCloneFilter struct {
+	repos map[api.RepoID]*types.Repo // nil if the actor may not view the repository
+	perms map[api.RepoID]*authz.SubRepoPerms
+}
+
+func newCodeCloneFilter() *codeCloneFilter {
+	return &codeCloneFilter{repos: map[api.RepoID]*types.Repo{}, perms: map[api.RepoID]*authz.SubRepoPerms{}}
+}
+
+// repo returns the repository if the actor may view the file of the repository, and nil otherwise.
+func (f *codeCloneFilter) repo(ctx context.Context, repoID api.RepoID, path string) (*types.Repo, error) {
+	repo, ok := f.repos[repoID]
+	if !ok {
+		var err error
+		repo, err = db.Repos.Get(ctx, repoID)
+		if errcode.IsNotFound(err) {
+			repo = nil
+		} else if err != nil {
+			return nil, err
+		}
+		if repo != nil {
+			perms, err := SubRepoPerms(ctx, repo)
+			if err != nil {
+				return nil, err
+			}
+			f.perms[repoID] = perms
+		}
+		f.repos[repoID] = repo
+	}
+	if repo == nil || !f.perms[repoID].Allowed(path) {
+		return nil, nil
+	}
+	return repo, nil
+}
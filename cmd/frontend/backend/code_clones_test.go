@@ -0,0 +1,151 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/clones"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestParseDiffNameStatus(t *testing.T) {
+	out := []byte("M\x00a.go\x00D\x00b.go\x00R087\x00c.go\x00d.go\x00A\x00e.go\x00")
+	changed, all := parseDiffNameStatus(out)
+	if want := []string{"a.go", "d.go", "e.go"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("got changed %q, want %q", changed, want)
+	}
+	if want := []string{"a.go", "b.go", "c.go", "d.go", "e.go"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("got all %q, want %q", all, want)
+	}
+	if changed, all := parseDiffNameStatus(nil); changed != nil || all != nil {
+		t.Errorf("got %q and %q for empty output", changed, all)
+	}
+}
+
+// mockCodeCloneRepos mocks three repositories: 1 and 2 may be viewed (except for the "secrets"
+// directory of repository 2), and 3 may not.
+func mockCodeCloneRepos() {
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		if id == 3 {
+			return nil, &errcode.Mock{IsNotFound: true}
+		}
+		return &types.Repo{ID: id, Name: api.RepoName(fmt.Sprintf("github.com/acme/%d", id))}, nil
+	}
+	Mocks.SubRepoPerms = func(_ context.Context, repo *types.Repo) (*authz.SubRepoPerms, error) {
+		if repo.ID == 2 {
+			return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secrets", Allow: false}}}, nil
+		}
+		return nil, nil
+	}
+}
+
+// 🚨 SECURITY: This tests that clones in repositories and files that the actor may not view are
+// not revealed.
+func TestFindCodeClones(t *testing.T) {
+	ctx := testContext()
+	mockCodeCloneRepos()
+
+	var src strings.Builder
+	src.WriteString("package a\n\nfunc f(a, b int) int {\n")
+	for i, op := range []string{"+", "-", "*", "/", "%", "&", "|", "^", "<<", ">>"} {
+		fmt.Fprintf(&src, "\tif a > %d {\n\t\tb = b %s a\n\t}\n", i, op)
+	}
+	src.WriteString("\treturn b\n}\n")
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		if commit != "c" || name != "a.go" {
+			t.Errorf("got commit %q and file %q", commit, name)
+		}
+		return []byte(src.String()), nil
+	}
+	fps := clones.FileFingerprints("a.go", []byte(src.String()))
+	if len(fps) < 3 {
+		t.Fatalf("got %d fingerprints, want at least 3", len(fps))
+	}
+
+	match := func(repoID api.RepoID, path string, line int, hash int64) *db.CloneMatch {
+		return &db.CloneMatch{
+			CloneRegion: db.CloneRegion{RepoID: repoID, CommitID: "i", Path: path, StartLine: line, EndLine: line + 5},
+			Hash:        hash,
+		}
+	}
+	db.Mocks.CodeClones.ListMatches = func(_ context.Context, hashes []int64, limit int) ([]*db.CloneMatch, error) {
+		return []*db.CloneMatch{
+			// The file itself.
+			match(1, "a.go", 3, fps[0].Hash),
+			match(1, "a.go", 6, fps[1].Hash),
+			// Two regions of b.go, of which only the first shares enough fingerprints.
+			match(1, "b.go", 10, fps[0].Hash),
+			match(1, "b.go", 13, fps[1].Hash),
+			match(1, "b.go", 16, fps[1].Hash),
+			match(1, "b.go", 100, fps[2].Hash),
+			// A region that shares more fingerprints.
+			match(2, "c.go", 1, fps[0].Hash),
+			match(2, "c.go", 4, fps[1].Hash),
+			match(2, "c.go", 7, fps[2].Hash),
+			// Regions that the actor may not view.
+			match(2, "secrets/d.go", 1, fps[0].Hash),
+			match(2, "secrets/d.go", 4, fps[1].Hash),
+			match(3, "e.go", 1, fps[0].Hash),
+			match(3, "e.go", 4, fps[1].Hash),
+		}, nil
+	}
+
+	found, err := FindCodeClones(ctx, &types.Repo{ID: 1, Name: "github.com/acme/1"}, "c", "a.go", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range found {
+		got = append(got, fmt.Sprintf("%s %s %s:%d-%d %d", c.Repo.Name, c.CommitID, c.Path, c.StartLine, c.EndLine, c.Fingerprints))
+	}
+	if want := []string{
+		"github.com/acme/2 i c.go:1-12 3",
+		"github.com/acme/1 i b.go:10-21 2",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Lines without fingerprints have no clones.
+	db.Mocks.CodeClones.ListMatches = func(context.Context, []int64, int) ([]*db.CloneMatch, error) {
+		t.Error("ListMatches must not be called")
+		return nil, nil
+	}
+	if found, err := FindCodeClones(ctx, &types.Repo{ID: 1}, "c", "a.go", 1, 2); err != nil || len(found) != 0 {
+		t.Errorf("got %v (err %v), want no clones", found, err)
+	}
+}
+
+// 🚨 SECURITY: This tests that regions of clone clusters that the actor may not view are not
+// revealed.
+func TestFilterCodeCloneClusters(t *testing.T) {
+	ctx := testContext()
+	mockCodeCloneRepos()
+
+	region := func(repoID api.RepoID, path string) *db.CloneRegion {
+		return &db.CloneRegion{RepoID: repoID, CommitID: "i", Path: path, StartLine: 1, EndLine: 9}
+	}
+	clusters := []*db.CloneCluster{
+		{Fingerprints: 9, Regions: []*db.CloneRegion{region(1, "a.go"), region(2, "secrets/a.go"), region(3, "a.go")}},
+		{Fingerprints: 5, Regions: []*db.CloneRegion{region(1, "b.go"), region(2, "b.go"), region(3, "b.go")}},
+	}
+	got, err := filterCodeCloneClusters(ctx, clusters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Fingerprints != 5 || len(got[0].Clones) != 2 {
+		t.Fatalf("got %+v, want only the second cluster with 2 regions", got)
+	}
+	for _, c := range got[0].Clones {
+		if c.Repo.ID == 3 || c.Fingerprints != 5 {
+			t.Errorf("got region %+v", c)
+		}
+	}
+}
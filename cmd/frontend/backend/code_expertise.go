@@ -0,0 +1,367 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// codeExpertiseHalfLife is the age at which the lines of a commit (or a reviewed pull request)
+	// count half as much toward expertise as new ones.
+	codeExpertiseHalfLife = 180 * 24 * time.Hour
+
+	// maxCodeExpertiseAge is the age of the oldest commits that are indexed. Older commits count
+	// for little anyway, given the half-life.
+	maxCodeExpertiseAge = 3 * 365 * 24 * time.Hour
+
+	// maxCodeExpertiseCommits is the maximum number of commits that are indexed per update of a
+	// repository.
+	maxCodeExpertiseCommits = 50000
+
+	// maxCodeExpertiseCommitLines is the maximum number of lines that a commit counts for in a
+	// directory, so that commits that add generated or vendored files don't dominate.
+	maxCodeExpertiseCommitLines = 1000
+
+	// codeExpertiseReviewLines is the number of authored lines that a reviewed pull request counts
+	// as.
+	codeExpertiseReviewLines = 25
+
+	// maxCodeExperts is the maximum number of people that CodeExperts returns.
+	maxCodeExperts = 100
+)
+
+// defaultCodeExpertiseExcludePatterns is the default value of the
+// "codeExpertise.excludePatterns" site configuration property.
+var defaultCodeExpertiseExcludePatterns = []string{`\[bot\]`, `(?i)^(dependabot|renovate|greenkeeper)`}
+
+func init() {
+	conf.ContributeValidator(func(c conf.Unified) (problems conf.Problems) {
+		for _, p := range c.CodeExpertiseExcludePatterns {
+			if _, err := regexp.Compile(p); err != nil {
+				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("codeExpertise.excludePatterns: not a valid regexp: %s. See the valid syntax: https://golang.org/pkg/regexp/", p)))
+			}
+		}
+		return
+	})
+}
+
+// codeExpertiseExcludePatterns returns the compiled "codeExpertise.excludePatterns".
+var codeExpertiseExcludePatterns = conf.Cached(func() interface{} {
+	patterns := conf.Get().CodeExpertiseExcludePatterns
+	if patterns == nil {
+		patterns = defaultCodeExpertiseExcludePatterns
+	}
+	var res []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			// Skip if there's an error. A user-visible validation error will appear due to the ContributeValidator call above.
+			log15.Error("Site config: unable to compile code expertise exclude pattern", "pattern", p)
+			continue
+		}
+		res = append(res, re)
+	}
+	return res
+})
+
+// CodeExpertiseEnabled reports whether the "codeExpertise" experimental feature is enabled.
+func CodeExpertiseEnabled() bool {
+	c := conf.Get()
+	return c.ExperimentalFeatures != nil && c.ExperimentalFeatures.CodeExpertise == "enabled"
+}
+
+// UpdateCodeExpertise updates the code expertise index of the repository to the head of its
+// default branch. Only the commits since the last indexed commit are indexed, unless the
+// repository wasn't indexed before (or the last indexed commit no longer exists). Commits that
+// are older than maxCodeExpertiseAge are removed from the index.
+func UpdateCodeExpertise(ctx context.Context, repo *types.Repo) error {
+	// 🚨 SECURITY: The index contains all commits. The permissions of the actor are checked when
+	// experts are looked up (see CodeExperts).
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	gitRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return err
+	}
+	head, err := git.ResolveRevision(ctx, *gitRepo, nil, "HEAD", &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if vcs.IsRepoNotExist(err) || gitserver.IsRevisionNotFound(err) {
+		return nil // not cloned yet, or empty
+	} else if err != nil {
+		return err
+	}
+
+	lastCommit, err := db.CodeExpertise.GetRepoCommit(ctx, repo.ID)
+	if err != nil || lastCommit == head {
+		return err
+	}
+	if lastCommit != "" {
+		_, err := git.ResolveRevision(ctx, *gitRepo, nil, string(lastCommit), &git.ResolveRevisionOptions{NoEnsureRevision: true})
+		if gitserver.IsRevisionNotFound(err) {
+			lastCommit = "" // e.g., because of a force push
+		} else if err != nil {
+			return err
+		}
+	}
+
+	since := time.Now().Add(-maxCodeExpertiseAge)
+	u := &db.CodeExpertiseUpdate{CommitID: head, Before: since}
+	args := []string{
+		"log", "--no-merges", "--numstat", "-z", "--format=%x1e%H%x00%an%x00%ae%x00%at",
+		"--max-count=" + strconv.Itoa(maxCodeExpertiseCommits),
+		"--since=" + since.UTC().Format(time.RFC3339),
+	}
+	if lastCommit != "" {
+		args = append(args, string(lastCommit)+".."+string(head))
+	} else {
+		u.Reset = true
+		args = append(args, string(head))
+	}
+	rdr, err := git.ExecReader(ctx, *gitRepo, append(args, "--"))
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+	out, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return err
+	}
+	u.Commits = parseExpertiseLog(out)
+	return db.CodeExpertise.UpdateRepo(ctx, repo.ID, u)
+}
+
+// parseExpertiseLog parses the output of `git log --numstat -z` with the format
+// "%x1e%H%x00%an%x00%ae%x00%at". Commits that didn't add lines to text files are omitted.
+func parseExpertiseLog(out []byte) []*db.ExpertiseCommit {
+	var commits []*db.ExpertiseCommit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		// Each record is the formatted commit followed by its numstat entries, all terminated by
+		// NUL. An entry of a renamed file has an empty path followed by the old and new paths.
+		fields := strings.Split(record, "\x00")
+		if len(fields) < 4 {
+			continue
+		}
+		at, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		c := &db.ExpertiseCommit{
+			ID:          api.CommitID(fields[0]),
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			AuthoredAt:  time.Unix(at, 0).UTC(),
+			Lines:       map[string]int{},
+		}
+		entries := fields[4:]
+		for i := 0; i < len(entries); i++ {
+			parts := strings.SplitN(strings.TrimPrefix(entries[i], "\n"), "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			name := parts[2]
+			if name == "" && i+2 < len(entries) {
+				name = entries[i+2]
+				i += 2
+			}
+			added, err := strconv.Atoi(parts[0])
+			if err != nil || added == 0 {
+				continue // binary file, or only deleted lines
+			}
+			dir := path.Dir(name)
+			if dir == "." {
+				dir = ""
+			}
+			c.Lines[dir] += added
+		}
+		if len(c.Lines) == 0 {
+			continue
+		}
+		for dir, n := range c.Lines {
+			if n > maxCodeExpertiseCommitLines {
+				c.Lines[dir] = maxCodeExpertiseCommitLines
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// A CodeExpert is a person with expertise in a directory of a repository.
+type CodeExpert struct {
+	Name  string      // the Git author name or code host login
+	Email string      // the Git author email, if known
+	User  *types.User // the Sourcegraph user, if known
+
+	AuthoredLines        int
+	ReviewedPullRequests int
+	Score                float64
+	LastActiveAt         time.Time
+
+	userID     int32    // the ID of User, which is looked up for the top experts only
+	identities []string // the names, emails and logins to match against the exclude patterns
+}
+
+// CodeExperts returns the people with the most expertise in the directory (and its
+// subdirectories) of the repository, highest score first, according to the code expertise index
+// of its default branch. A person's score is the number of lines they authored in the directory,
+// plus codeExpertiseReviewLines for each pull request that changed the directory and that they
+// reviewed, all weighted by age. Lines in files that the actor may not view aren't counted.
+// Deleted users and people matching the "codeExpertise.excludePatterns" are omitted.
+//
+// 🚨 SECURITY: The caller must ensure that the actor may view the directory.
+func CodeExperts(ctx context.Context, repo *types.Repo, dir string) ([]*CodeExpert, error) {
+	opt := db.ExpertiseListOptions{Dir: dir, HalfLife: codeExpertiseHalfLife}
+
+	// 🚨 SECURITY: Only count the lines of directories whose files the actor may view.
+	perms, err := SubRepoPerms(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if perms.Restricted() {
+		dirs, err := db.CodeExpertise.ListDirs(ctx, repo.ID, dir)
+		if err != nil {
+			return nil, err
+		}
+		opt.Dirs = []string{}
+		for _, d := range dirs {
+			if codeExpertiseDirAllowed(perms, d) {
+				opt.Dirs = append(opt.Dirs, d)
+			}
+		}
+	}
+
+	authors, err := db.CodeExpertise.ListAuthors(ctx, repo.ID, opt)
+	if err != nil {
+		return nil, err
+	}
+	reviewers, err := db.CodeExpertise.ListReviewers(ctx, repo.ID, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the authors and reviewers who are the same Sourcegraph user.
+	byKey := map[string]*CodeExpert{}
+	get := func(key string, userID int32, name string) *CodeExpert {
+		if userID != 0 {
+			key = fmt.Sprintf("user:%d", userID)
+		}
+		e, ok := byKey[key]
+		if !ok {
+			e = &CodeExpert{Name: name, userID: userID}
+			byKey[key] = e
+		}
+		e.identities = append(e.identities, name)
+		return e
+	}
+	for _, a := range authors {
+		e := get("email:"+a.Email, a.UserID, a.Name)
+		if e.Email == "" {
+			e.Email = a.Email
+		}
+		e.identities = append(e.identities, a.Email)
+		e.AuthoredLines += a.Lines
+		e.Score += a.Score
+		if a.LastActiveAt.After(e.LastActiveAt) {
+			e.LastActiveAt = a.LastActiveAt
+		}
+	}
+	for _, r := range reviewers {
+		if r.UserDeleted {
+			continue
+		}
+		e := get("account:"+r.AccountID, r.UserID, r.Login)
+		e.ReviewedPullRequests += r.PullRequests
+		e.Score += codeExpertiseReviewLines * r.Score
+		if r.LastActiveAt.After(e.LastActiveAt) {
+			e.LastActiveAt = r.LastActiveAt
+		}
+	}
+
+	excluded := codeExpertiseExcludePatterns().([]*regexp.Regexp)
+	var experts []*CodeExpert
+	for _, e := range byKey {
+		if !codeExpertExcluded(excluded, e.identities) {
+			experts = append(experts, e)
+		}
+	}
+	sort.Slice(experts, func(i, j int) bool {
+		if experts[i].Score != experts[j].Score {
+			return experts[i].Score > experts[j].Score
+		}
+		return experts[i].Name < experts[j].Name
+	})
+
+	// Look up the Sourcegraph users of the top experts. Users that were deleted in the meantime and
+	// users whose username matches an exclude pattern are omitted.
+	var top []*CodeExpert
+	for _, e := range experts {
+		if len(top) == maxCodeExperts {
+			break
+		}
+		if e.userID != 0 {
+			user, err := db.Users.GetByID(ctx, e.userID)
+			if errcode.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			if codeExpertExcluded(excluded, []string{user.Username}) {
+				continue
+			}
+			e.User = user
+		}
+		top = append(top, e)
+	}
+	return top, nil
+}
+
+func codeExpertExcluded(patterns []*regexp.Regexp, identities []string) bool {
+	for _, re := range patterns {
+		for _, s := range identities {
+			if re.MatchString(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// codeExpertiseDirAllowed reports whether the actor may view all files directly in the directory
+// (not including its subdirectories). It errs on the side of caution: a directory is not allowed
+// if any file or subdirectory directly in it may not be viewed.
+func codeExpertiseDirAllowed(perms *authz.SubRepoPerms, dir string) bool {
+	if !perms.Allowed(dir) {
+		return false
+	}
+	for _, r := range perms.Rules {
+		if r.Allow || r.Prefix == "" {
+			continue
+		}
+		parent := path.Dir(r.Prefix)
+		if parent == "." {
+			parent = ""
+		}
+		if parent == dir {
+			return false
+		}
+	}
+	return true
+}
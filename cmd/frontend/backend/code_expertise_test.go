@@ -0,0 +1,131 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestParseExpertiseLog(t *testing.T) {
+	out := []byte("" +
+		// A commit that changed no lines.
+		"\x1ec1\x00A B\x00a@b.c\x001600000000\x00" +
+		// A commit that added a binary file, changed a file and renamed another one.
+		"\x1ec2\x00A B\x00a@b.c\x001600000100\x00\n-\t-\tbin\x001\t0\td/e/f.go\x002\t0\t\x00r.go\x00d/r2.go\x00" +
+		// A commit that only deleted lines, and one that added many.
+		"\x1ec3\x00C D\x00c@d.e\x001600000200\x00\n0\t5\tg.go\x00" +
+		"\x1ec4\x00C D\x00c@d.e\x001600000300\x00\n5000\t0\tvendor/h.go\x003\t1\ti.go\x00",
+	)
+	got := parseExpertiseLog(out)
+	want := []*db.ExpertiseCommit{
+		{ID: "c2", AuthorName: "A B", AuthorEmail: "a@b.c", AuthoredAt: time.Unix(1600000100, 0).UTC(), Lines: map[string]int{"d/e": 1, "d": 2}},
+		{ID: "c4", AuthorName: "C D", AuthorEmail: "c@d.e", AuthoredAt: time.Unix(1600000300, 0).UTC(), Lines: map[string]int{"vendor": maxCodeExpertiseCommitLines, "": 3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCodeExpertiseDirAllowed(t *testing.T) {
+	perms := &authz.SubRepoPerms{Rules: []authz.PathRule{
+		{Prefix: "a/secrets", Allow: false},
+		{Prefix: "b", Allow: false},
+		{Prefix: "b/public", Allow: true},
+	}}
+	for dir, want := range map[string]bool{
+		"":            false, // contains b, which may be a file
+		"a":           false, // contains a/secrets
+		"a/other":     true,
+		"a/secrets":   false,
+		"a/secrets/x": false,
+		"b":           false,
+		"b/public":    true,
+		"c":           true,
+	} {
+		if got := codeExpertiseDirAllowed(perms, dir); got != want {
+			t.Errorf("%q: got %v, want %v", dir, got, want)
+		}
+	}
+}
+
+// 🚨 SECURITY: This tests that lines in directories that the actor may not view aren't counted,
+// and that deleted users and excluded people are omitted.
+func TestCodeExperts(t *testing.T) {
+	ctx := testContext()
+	repo := &types.Repo{ID: 1, Name: "github.com/acme/a"}
+	now := time.Now()
+
+	Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "pkg/billing/secrets", Allow: false}}}, nil
+	}
+	db.Mocks.CodeExpertise.ListDirs = func(_ context.Context, repoID api.RepoID, dir string) ([]string, error) {
+		if repoID != 1 || dir != "pkg/billing" {
+			t.Errorf("got repo %d and dir %q", repoID, dir)
+		}
+		return []string{"pkg/billing", "pkg/billing/api", "pkg/billing/secrets"}, nil
+	}
+	checkOptions := func(opt db.ExpertiseListOptions) {
+		// pkg/billing is omitted because pkg/billing/secrets may be a file directly in it.
+		if want := []string{"pkg/billing/api"}; opt.Dir != "pkg/billing" || !reflect.DeepEqual(opt.Dirs, want) {
+			t.Errorf("got options %+v, want dirs %q", opt, want)
+		}
+	}
+	db.Mocks.CodeExpertise.ListAuthors = func(_ context.Context, _ api.RepoID, opt db.ExpertiseListOptions) ([]*db.ExpertiseAuthor, error) {
+		checkOptions(opt)
+		return []*db.ExpertiseAuthor{
+			{Email: "alice@example.com", Name: "Alice", UserID: 1, Lines: 100, Score: 50, LastActiveAt: now.Add(-time.Hour)},
+			{Email: "carol@example.com", Name: "Carol", Lines: 40, Score: 40, LastActiveAt: now},
+			{Email: "bot@example.com", Name: "ci-bot[bot]", Lines: 1000, Score: 1000, LastActiveAt: now},
+			{Email: "gone@example.com", Name: "Gone", UserID: 3, Lines: 500, Score: 500, LastActiveAt: now},
+			{Email: "dep@example.com", Name: "Dep", UserID: 4, Lines: 500, Score: 500, LastActiveAt: now},
+		}, nil
+	}
+	db.Mocks.CodeExpertise.ListReviewers = func(_ context.Context, _ api.RepoID, opt db.ExpertiseListOptions) ([]*db.ExpertiseReviewer, error) {
+		checkOptions(opt)
+		return []*db.ExpertiseReviewer{
+			{AccountID: "1", Login: "alice", UserID: 1, PullRequests: 2, Score: 1, LastActiveAt: now},
+			{AccountID: "2", Login: "bob", UserID: 2, PullRequests: 1, Score: 1, LastActiveAt: now},
+			{AccountID: "5", Login: "eve", UserID: 5, UserDeleted: true, PullRequests: 9, Score: 9, LastActiveAt: now},
+		}, nil
+	}
+	db.Mocks.Users.GetByID = func(_ context.Context, id int32) (*types.User, error) {
+		switch id {
+		case 3:
+			return nil, &errcode.Mock{IsNotFound: true}
+		case 4:
+			return &types.User{ID: id, Username: "dependabot"}, nil
+		}
+		return &types.User{ID: id, Username: fmt.Sprintf("user%d", id)}, nil
+	}
+
+	experts, err := CodeExperts(ctx, repo, "pkg/billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range experts {
+		var userID int32
+		if e.User != nil {
+			userID = e.User.ID
+		}
+		got = append(got, fmt.Sprintf("%s <%s> user=%d lines=%d prs=%d score=%.0f", e.Name, e.Email, userID, e.AuthoredLines, e.ReviewedPullRequests, e.Score))
+	}
+	if want := []string{
+		"Alice <alice@example.com> user=1 lines=100 prs=2 score=75",
+		"Carol <carol@example.com> user=0 lines=40 prs=0 score=40",
+		"bob <> user=2 lines=0 prs=1 score=25",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(experts) > 0 && !experts[0].LastActiveAt.Equal(now) {
+		t.Errorf("got last active at %s, want %s", experts[0].LastActiveAt, now)
+	}
+}
@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxCoverageAncestors is the maximum number of ancestors of a commit that are searched for a
+// coverage report when the commit itself has none.
+const maxCoverageAncestors = 100
+
+// NearestCoverageReport returns the coverage report of the commit or, if it has none, the coverage
+// report of its nearest ancestor (in `git log` order) that has one. It returns nil if none of the
+// commit's recent ancestors have a coverage report.
+//
+// Coverage reports are usually only uploaded for some commits (e.g., those built by CI on the
+// default branch), so the nearest ancestor's report is a useful approximation for the others.
+func NearestCoverageReport(ctx context.Context, repo *types.Repo, commit api.CommitID) (*db.CoverageReport, error) {
+	if report, err := db.CoverageReports.GetByCommit(ctx, repo.ID, commit); err == nil {
+		return report, nil
+	} else if _, ok := err.(db.CoverageReportNotFoundError); !ok {
+		return nil, err
+	}
+
+	gitRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	ancestors, err := git.Commits(ctx, *gitRepo, git.CommitsOptions{Range: string(commit), N: maxCoverageAncestors})
+	if err != nil {
+		return nil, err
+	}
+	commitIDs := make([]api.CommitID, len(ancestors))
+	for i, c := range ancestors {
+		commitIDs[i] = c.ID
+	}
+	reports, err := db.CoverageReports.List(ctx, db.CoverageReportsListOptions{RepoID: repo.ID, CommitIDs: commitIDs})
+	if err != nil {
+		return nil, err
+	}
+	byCommit := make(map[api.CommitID]*db.CoverageReport, len(reports))
+	for _, r := range reports {
+		byCommit[r.CommitID] = r
+	}
+	for _, id := range commitIDs {
+		if r, ok := byCommit[id]; ok {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
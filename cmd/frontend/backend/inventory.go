@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -86,3 +87,45 @@ func InventoryContext(repo gitserver.Repo, commitID api.CommitID, forceEnhancedL
 
 	return invCtx, nil
 }
+
+// restrictInventoryContext returns a copy of invCtx that skips the files and directories that may
+// not be read according to perms.
+//
+// The inventories of trees are cached by their Git object ID regardless of the user, so only the
+// trees that may be read entirely use the cache.
+func restrictInventoryContext(invCtx inventory.Context, perms *authz.SubRepoPerms) inventory.Context {
+	if !perms.Restricted() {
+		return invCtx
+	}
+
+	readTree := invCtx.ReadTree
+	invCtx.ReadTree = func(ctx context.Context, path string) ([]os.FileInfo, error) {
+		entries, err := readTree(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		allowed := entries[:0]
+		for _, e := range entries {
+			if (e.IsDir() && perms.AllowedDir(e.Name())) || (!e.IsDir() && perms.Allowed(e.Name())) {
+				allowed = append(allowed, e)
+			}
+		}
+		return allowed, nil
+	}
+	if cacheGet := invCtx.CacheGet; cacheGet != nil {
+		invCtx.CacheGet = func(e os.FileInfo) (inventory.Inventory, bool) {
+			if !perms.AllowedTree(e.Name()) {
+				return inventory.Inventory{}, false
+			}
+			return cacheGet(e)
+		}
+	}
+	if cacheSet := invCtx.CacheSet; cacheSet != nil {
+		invCtx.CacheSet = func(e os.FileInfo, inv inventory.Inventory) {
+			if perms.AllowedTree(e.Name()) {
+				cacheSet(e, inv)
+			}
+		}
+	}
+	return invCtx
+}
@@ -0,0 +1,71 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+func TestRestrictInventoryContext(t *testing.T) {
+	files := map[string]string{
+		"pub/a.go":     "package a",
+		"secret/b.m":   "@interface X:NSObject {}",
+		"secret/ok.go": "package ok",
+	}
+	trees := map[string][]os.FileInfo{
+		"": {
+			&util.FileInfo{Name_: "pub", Mode_: os.ModeDir},
+			&util.FileInfo{Name_: "secret", Mode_: os.ModeDir},
+		},
+		"pub":    {&util.FileInfo{Name_: "pub/a.go", Size_: 9}},
+		"secret": {&util.FileInfo{Name_: "secret/b.m", Size_: 24}, &util.FileInfo{Name_: "secret/ok.go", Size_: 10}},
+	}
+	// The cache has the inventory of the whole repository, computed for a user without
+	// restrictions.
+	cache := map[string]inventory.Inventory{
+		"": {Languages: []inventory.Lang{{Name: "Objective-C", TotalBytes: 24, TotalLines: 1}}},
+	}
+	invCtx := inventory.Context{
+		ReadTree: func(ctx context.Context, path string) ([]os.FileInfo, error) {
+			return trees[path], nil
+		},
+		NewFileReader: func(ctx context.Context, path string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader([]byte(files[path]))), nil
+		},
+		CacheGet: func(e os.FileInfo) (inventory.Inventory, bool) {
+			inv, ok := cache[e.Name()]
+			return inv, ok
+		},
+		CacheSet: func(e os.FileInfo, inv inventory.Inventory) {
+			cache[e.Name()] = inv
+		},
+	}
+
+	perms := &authz.SubRepoPerms{Rules: []authz.PathRule{
+		{Prefix: "secret", Allow: false},
+		{Prefix: "secret/ok.go", Allow: true},
+	}}
+	restricted := restrictInventoryContext(invCtx, perms)
+	inv, err := restricted.Entries(context.Background(), &util.FileInfo{Name_: "", Mode_: os.ModeDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := inventory.Inventory{Languages: []inventory.Lang{{Name: "Go", TotalBytes: 19, TotalLines: 2}}}
+	if !reflect.DeepEqual(inv, want) {
+		t.Errorf("got inventory %+v, want %+v", inv, want)
+	}
+	if _, ok := cache["secret"]; ok {
+		t.Error("cached the inventory of a partially readable tree")
+	}
+	if _, ok := cache["pub"]; !ok {
+		t.Error("did not cache the inventory of a readable tree")
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	opentracing "github.com/opentracing/opentracing-go"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -14,6 +16,8 @@ var Mocks MockServices
 
 type MockServices struct {
 	Repos MockRepos
+
+	SubRepoPerms func(ctx context.Context, repo *types.Repo) (*authz.SubRepoPerms, error)
 }
 
 // testContext creates a new context.Context for use by tests
@@ -0,0 +1,179 @@
+package backend
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// MaxRepoAccessRequestRepos is the maximum number of repositories that a single repo access request
+// can grant access to.
+const MaxRepoAccessRequestRepos = 1000
+
+// RepoAccessRequestsEnabled reports whether users can request access to repositories.
+func RepoAccessRequestsEnabled() bool {
+	c := conf.Get().PermissionsAccessRequests
+	return c != nil && c.Enabled
+}
+
+// RepoAccessMaxDuration returns the maximum duration of the access granted by an approved request,
+// or 0 if access may be granted permanently.
+func RepoAccessMaxDuration() (time.Duration, error) {
+	c := conf.Get().PermissionsAccessRequests
+	if c == nil || c.MaxDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.MaxDuration)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid site configuration permissions.accessRequests.maxDuration")
+	}
+	return d, nil
+}
+
+// repoAccessPatternRegexp returns a regular expression that matches the repository names matched by
+// the repo access request pattern, which is either a repository name or a glob pattern in which *
+// matches any sequence of characters other than /.
+func repoAccessPatternRegexp(pattern string) string {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return "^" + strings.Join(parts, "[^/]*") + "$"
+}
+
+// ReposMatchingAccessPattern returns the repositories matched by the repo access request pattern,
+// regardless of whether the current user may view them.
+//
+// 🚨 SECURITY: The returned repositories must not be revealed to users who may not view them.
+func ReposMatchingAccessPattern(ctx context.Context, pattern string) ([]*types.Repo, error) {
+	// Repositories the user can't view are exactly what access is requested for, so list them as
+	// the internal actor.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+	return db.Repos.List(ctx, db.ReposListOptions{
+		IncludePatterns: []string{repoAccessPatternRegexp(pattern)},
+		LimitOffset:     &db.LimitOffset{Limit: MaxRepoAccessRequestRepos},
+	})
+}
+
+// repoAccessApprovers returns the configured approvers entries that apply to the repository.
+func repoAccessApprovers(repo *types.Repo) ([]*schema.RepoAccessApprovers, error) {
+	c := conf.Get().PermissionsAccessRequests
+	if c == nil {
+		return nil, nil
+	}
+	var approvers []*schema.RepoAccessApprovers
+	for _, a := range c.Approvers {
+		re, err := regexp.Compile(a.Repos)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid site configuration permissions.accessRequests.approvers repos pattern %q", a.Repos)
+		}
+		if re.MatchString(string(repo.Name)) {
+			approvers = append(approvers, a)
+		}
+	}
+	return approvers, nil
+}
+
+// RepoAccessApproverIDs returns the IDs of the users who can approve access to at least one of the
+// repositories: the site admins, and the users and members of the organizations configured as
+// approvers of the repository.
+func RepoAccessApproverIDs(ctx context.Context, repos []*types.Repo) ([]int32, error) {
+	admins, err := db.Users.List(ctx, &db.UsersListOptions{OnlySiteAdmins: true})
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[int32]struct{}, len(admins))
+	for _, u := range admins {
+		ids[u.ID] = struct{}{}
+	}
+
+	var usernames, orgNames []string
+	for _, repo := range repos {
+		approvers, err := repoAccessApprovers(repo)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range approvers {
+			usernames = append(usernames, a.Users...)
+			orgNames = append(orgNames, a.Orgs...)
+		}
+	}
+
+	if len(usernames) > 0 {
+		users, err := db.Users.GetByUsernames(ctx, usernames...)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			ids[u.ID] = struct{}{}
+		}
+	}
+	seenOrgs := make(map[string]bool, len(orgNames))
+	for _, name := range orgNames {
+		if seenOrgs[name] {
+			continue
+		}
+		seenOrgs[name] = true
+		org, err := db.Orgs.GetByName(ctx, name)
+		if _, ok := err.(*db.OrgNotFoundError); ok {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		members, err := db.OrgMembers.GetByOrgID(ctx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			ids[m.UserID] = struct{}{}
+		}
+	}
+
+	result := make([]int32, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// CanApproveRepoAccess reports whether the user can approve access to the repository.
+func CanApproveRepoAccess(ctx context.Context, user *types.User, repo *types.Repo) (bool, error) {
+	if user.SiteAdmin {
+		return true, nil
+	}
+	approvers, err := repoAccessApprovers(repo)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range approvers {
+		for _, username := range a.Users {
+			if username == user.Username {
+				return true, nil
+			}
+		}
+		for _, name := range a.Orgs {
+			org, err := db.Orgs.GetByName(ctx, name)
+			if _, ok := err.(*db.OrgNotFoundError); ok {
+				continue
+			} else if err != nil {
+				return false, err
+			}
+			if _, err := db.OrgMembers.GetByOrgIDAndUserID(ctx, org.ID, user.ID); err == nil {
+				return true, nil
+			} else if _, ok := err.(*db.ErrOrgMemberNotFound); !ok {
+				return false, err
+			}
+		}
+	}
+	return false, nil
+}
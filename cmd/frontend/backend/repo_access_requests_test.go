@@ -0,0 +1,132 @@
+package backend
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRepoAccessPatternRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "github.com/acme/api",
+			match:   []string{"github.com/acme/api"},
+			noMatch: []string{"github.com/acme/api2", "github.com/acme/apix", "xgithub.com/acme/api"},
+		},
+		{
+			pattern: "github.com/acme/payments-*",
+			match:   []string{"github.com/acme/payments-", "github.com/acme/payments-api"},
+			noMatch: []string{"github.com/acme/payments", "github.com/acme/payments-api/x"},
+		},
+		{
+			pattern: "github.com/*/*",
+			match:   []string{"github.com/acme/api"},
+			noMatch: []string{"gitlab.com/acme/api", "github.com/acme"},
+		},
+	}
+	for _, test := range tests {
+		re := regexp.MustCompile(repoAccessPatternRegexp(test.pattern))
+		for _, name := range test.match {
+			if !re.MatchString(name) {
+				t.Errorf("%q: want match for %q", test.pattern, name)
+			}
+		}
+		for _, name := range test.noMatch {
+			if re.MatchString(name) {
+				t.Errorf("%q: want no match for %q", test.pattern, name)
+			}
+		}
+	}
+}
+
+func mockRepoAccessRequestsConfig() func() {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		PermissionsAccessRequests: &schema.PermissionsAccessRequests{
+			Enabled: true,
+			Approvers: []*schema.RepoAccessApprovers{
+				{Repos: "^github\\.com/acme/payments-", Users: []string{"alice"}},
+				{Repos: "^github\\.com/acme/", Users: []string{"bob"}, Orgs: []string{"missing"}},
+			},
+		},
+	}})
+	return func() { conf.Mock(nil) }
+}
+
+func TestRepoAccessApproverIDs(t *testing.T) {
+	ctx := testContext()
+	defer mockRepoAccessRequestsConfig()()
+
+	db.Mocks.Users.List = func(ctx context.Context, opt *db.UsersListOptions) ([]*types.User, error) {
+		if !opt.OnlySiteAdmins {
+			t.Errorf("want only site admins to be listed")
+		}
+		return []*types.User{{ID: 1, SiteAdmin: true}}, nil
+	}
+	db.Mocks.Users.GetByUsernames = func(ctx context.Context, usernames ...string) ([]*types.User, error) {
+		ids := map[string]int32{"alice": 2, "bob": 3}
+		var users []*types.User
+		for _, username := range usernames {
+			users = append(users, &types.User{ID: ids[username], Username: username})
+		}
+		return users, nil
+	}
+	db.Mocks.Orgs.GetByName = func(ctx context.Context, name string) (*types.Org, error) {
+		return nil, &db.OrgNotFoundError{Message: name}
+	}
+
+	tests := map[string][]int32{
+		"github.com/acme/payments-api": {1, 2, 3},
+		"github.com/acme/web":          {1, 3},
+		"github.com/other/web":         {1},
+	}
+	for name, want := range tests {
+		ids, err := RepoAccessApproverIDs(ctx, []*types.Repo{{Name: api.RepoName(name)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("%s: got approvers %v, want %v", name, ids, want)
+		}
+	}
+}
+
+func TestCanApproveRepoAccess(t *testing.T) {
+	ctx := testContext()
+	defer mockRepoAccessRequestsConfig()()
+
+	db.Mocks.Orgs.GetByName = func(ctx context.Context, name string) (*types.Org, error) {
+		return nil, &db.OrgNotFoundError{Message: name}
+	}
+
+	tests := []struct {
+		user *types.User
+		repo string
+		want bool
+	}{
+		{user: &types.User{ID: 1, SiteAdmin: true}, repo: "github.com/other/web", want: true},
+		{user: &types.User{ID: 2, Username: "alice"}, repo: "github.com/acme/payments-api", want: true},
+		{user: &types.User{ID: 2, Username: "alice"}, repo: "github.com/acme/web", want: false},
+		{user: &types.User{ID: 3, Username: "bob"}, repo: "github.com/acme/payments-api", want: true},
+		{user: &types.User{ID: 4, Username: "carol"}, repo: "github.com/acme/web", want: false},
+	}
+	for _, test := range tests {
+		got, err := CanApproveRepoAccess(ctx, test.user, &types.Repo{Name: api.RepoName(test.repo)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%s on %s: got %v, want %v", test.user.Username, test.repo, got, test.want)
+		}
+	}
+}
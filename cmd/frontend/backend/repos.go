@@ -168,6 +168,13 @@ func (s *repos) GetInventory(ctx context.Context, repo *types.Repo, commitID api
 		return nil, err
 	}
 
+	// 🚨 SECURITY: Only count the files that the user may read.
+	perms, err := SubRepoPerms(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	invCtx = restrictInventoryContext(invCtx, perms)
+
 	root, err := git.Stat(ctx, *cachedRepo, commitID, "")
 	if err != nil {
 		return nil, err
@@ -0,0 +1,103 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const (
+	// MaxSnippetShareLinkLines is the maximum number of lines of a snippet share link.
+	MaxSnippetShareLinkLines = 500
+
+	// maxSnippetFileBytes is the maximum size of the files whose snippets can be shared.
+	maxSnippetFileBytes = 10 * 1024 * 1024
+
+	defaultSnippetShareLinkMaxDuration = 7 * 24 * time.Hour
+)
+
+// ErrSnippetShareLinksDisabled occurs when snippet share links are not enabled in site configuration.
+var ErrSnippetShareLinksDisabled = errors.New("snippet share links are not enabled (site configuration snippetShareLinks)")
+
+// CanShareSnippets reports whether the site configuration permits sharing snippets of the
+// repository's files.
+func CanShareSnippets(repo api.RepoName) (bool, error) {
+	c := conf.Get().SnippetShareLinks
+	if c == nil {
+		return false, ErrSnippetShareLinksDisabled
+	}
+	for _, pattern := range c.Repos {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid site configuration snippetShareLinks.repos pattern %q", pattern)
+		}
+		if re.MatchString(string(repo)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SnippetShareLinkMaxDuration returns the maximum duration until a snippet share link expires.
+func SnippetShareLinkMaxDuration() (time.Duration, error) {
+	c := conf.Get().SnippetShareLinks
+	if c == nil || c.MaxDuration == "" {
+		return defaultSnippetShareLinkMaxDuration, nil
+	}
+	d, err := time.ParseDuration(c.MaxDuration)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid site configuration snippetShareLinks.maxDuration")
+	}
+	return d, nil
+}
+
+// SnippetShareLinkRequirePassword reports whether snippet share links must have a password.
+func SnippetShareLinkRequirePassword() bool {
+	c := conf.Get().SnippetShareLinks
+	return c != nil && c.RequirePassword
+}
+
+// ReadSnippet returns the lines startLine through endLine (1-based, inclusive) of the file at the
+// given commit, without a trailing newline. It returns an error if the file is binary or doesn't
+// have those lines.
+//
+// 🚨 SECURITY: The caller must ensure that the actor may read the file (or that it is shared with
+// them by a snippet share link).
+func ReadSnippet(ctx context.Context, repo *types.Repo, commit api.CommitID, path string, startLine, endLine int32) ([]byte, error) {
+	if startLine < 1 || endLine < startLine {
+		return nil, fmt.Errorf("invalid line range %d-%d", startLine, endLine)
+	}
+	if endLine-startLine+1 > MaxSnippetShareLinkLines {
+		return nil, fmt.Errorf("snippets may have at most %d lines", MaxSnippetShareLinkLines)
+	}
+
+	gitRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	content, err := git.ReadFile(ctx, *gitRepo, commit, path, maxSnippetFileBytes)
+	if err != nil {
+		return nil, err
+	}
+	if highlight.IsBinary(content) {
+		return nil, errors.New("snippets of binary files can't be shared")
+	}
+
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if int(endLine) > len(lines) {
+		return nil, fmt.Errorf("line range %d-%d is outside of the file (which has %d lines)", startLine, endLine, len(lines))
+	}
+	return bytes.TrimSuffix(bytes.Join(lines[startLine-1:endLine], nil), []byte("\n")), nil
+}
@@ -0,0 +1,237 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// SubRepoPerms returns the path rules of repo as they apply to the current actor, combined from
+// all enabled sub-repository permissions providers. A nil return value means that the actor may
+// read every path of the repository.
+//
+// 🚨 SECURITY: Every code path that returns the names or contents of files in a repository must
+// filter them with the rules returned by this function. Callers must already have verified that
+// the actor may view the repository itself (i.e. repo was returned by db.Repos).
+func SubRepoPerms(ctx context.Context, repo *types.Repo) (*authz.SubRepoPerms, error) {
+	if Mocks.SubRepoPerms != nil {
+		return Mocks.SubRepoPerms(ctx, repo)
+	}
+
+	providers := subRepoPermsProviders()
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	a := actor.FromContext(ctx)
+	if a.Internal {
+		return nil, nil
+	}
+
+	var user *types.User
+	if a.IsAuthenticated() {
+		var err error
+		user, err = db.Users.GetByCurrentAuthUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if user.SiteAdmin {
+			return nil, nil
+		}
+	}
+
+	var perms *authz.SubRepoPerms
+	for _, p := range providers {
+		pp, err := p.SubRepoPerms(ctx, user, repo)
+		if err != nil {
+			// 🚨 SECURITY: Fail closed. We don't know which paths the user may read.
+			return nil, errors.Wrap(err, "computing sub-repository permissions")
+		}
+		perms = perms.Merge(pp)
+	}
+	return perms, nil
+}
+
+// CheckSubRepoPath returns an error that is indistinguishable from a nonexistent file if the
+// current actor may not read the file or directory at the given path of repo.
+func CheckSubRepoPath(ctx context.Context, repo *types.Repo, name string, isDir bool) error {
+	perms, err := SubRepoPerms(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if (isDir && !perms.AllowedDir(name)) || (!isDir && !perms.Allowed(name)) {
+		return &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return nil
+}
+
+// subRepoPermsProviders returns the sub-repository permissions providers enabled in the site
+// configuration, followed by any providers registered with authz.SetSubRepoPermsProviders.
+func subRepoPermsProviders() []authz.SubRepoPermsProvider {
+	cfg := conf.Get().PermissionsSubRepository
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	providers := []authz.SubRepoPermsProvider{explicitSubRepoPermsProvider{}}
+	if cfg.RulesFile {
+		providers = append(providers, rulesFileSubRepoPermsProvider)
+	}
+	return append(providers, authz.GetSubRepoPermsProviders()...)
+}
+
+// explicitSubRepoPermsProvider provides the path rules configured via the GraphQL API.
+type explicitSubRepoPermsProvider struct{}
+
+func (explicitSubRepoPermsProvider) SubRepoPerms(ctx context.Context, user *types.User, repo *types.Repo) (*authz.SubRepoPerms, error) {
+	rules, err := db.SubRepoPerms.ListByRepo(ctx, repo.ID)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	var orgIDs map[int32]bool
+	if user != nil {
+		orgs, err := db.Orgs.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		orgIDs = make(map[int32]bool, len(orgs))
+		for _, org := range orgs {
+			orgIDs[org.ID] = true
+		}
+	}
+
+	perms := &authz.SubRepoPerms{Rules: make([]authz.PathRule, 0, len(rules))}
+	for _, r := range rules {
+		allow := false
+		if user != nil {
+			for _, id := range r.UserIDs {
+				allow = allow || id == user.ID
+			}
+			for _, id := range r.OrgIDs {
+				allow = allow || orgIDs[id]
+			}
+		}
+		perms.Rules = append(perms.Rules, authz.PathRule{Prefix: r.PathPrefix, Allow: allow})
+	}
+	return perms, nil
+}
+
+// rulesFileSubRepoPermsProvider provides the path rules from the authz.PathRulesFileName file on
+// the default branch of each repository.
+var rulesFileSubRepoPermsProvider = &rulesFileProvider{
+	cache: map[api.RepoID]*rulesFileCacheEntry{},
+	ttl:   30 * time.Second,
+}
+
+type rulesFileProvider struct {
+	mu    sync.Mutex
+	cache map[api.RepoID]*rulesFileCacheEntry
+	ttl   time.Duration
+}
+
+type rulesFileCacheEntry struct {
+	commit  api.CommitID
+	entries []authz.PathRulesEntry
+	expires time.Time
+}
+
+// maxRulesFileSize is the maximum size of a path rules file that we read.
+const maxRulesFileSize = 1 << 20
+
+func (p *rulesFileProvider) SubRepoPerms(ctx context.Context, user *types.User, repo *types.Repo) (*authz.SubRepoPerms, error) {
+	entries, err := p.entries(ctx, repo)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+
+	var orgNames map[string]bool
+	if user != nil {
+		orgs, err := db.Orgs.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		orgNames = make(map[string]bool, len(orgs))
+		for _, org := range orgs {
+			orgNames[org.Name] = true
+		}
+	}
+
+	perms := &authz.SubRepoPerms{Rules: make([]authz.PathRule, 0, len(entries))}
+	for _, e := range entries {
+		allow := false
+		if user != nil {
+			for _, name := range e.Usernames {
+				allow = allow || name == user.Username
+			}
+			for _, name := range e.OrgNames {
+				allow = allow || orgNames[name]
+			}
+		}
+		perms.Rules = append(perms.Rules, authz.PathRule{Prefix: e.Prefix, Allow: allow})
+	}
+	return perms, nil
+}
+
+func (p *rulesFileProvider) entries(ctx context.Context, repo *types.Repo) ([]authz.PathRulesEntry, error) {
+	p.mu.Lock()
+	e, ok := p.cache[repo.ID]
+	p.mu.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.entries, nil
+	}
+
+	cachedRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := git.ResolveRevision(ctx, *cachedRepo, nil, "HEAD", &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		if gitserver.IsRevisionNotFound(err) {
+			return nil, nil // empty repository
+		}
+		return nil, err
+	}
+
+	if ok && e.commit == commit {
+		p.store(repo.ID, commit, e.entries)
+		return e.entries, nil
+	}
+
+	data, err := git.ReadFile(ctx, *cachedRepo, commit, authz.PathRulesFileName, maxRulesFileSize)
+	if err != nil {
+		if os.IsNotExist(err) {
+			p.store(repo.ID, commit, nil)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entries, err := authz.ParsePathRulesFile(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s at %s", authz.PathRulesFileName, commit)
+	}
+	p.store(repo.ID, commit, entries)
+	return entries, nil
+}
+
+func (p *rulesFileProvider) store(repoID api.RepoID, commit api.CommitID, entries []authz.PathRulesEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache[repoID] = &rulesFileCacheEntry{
+		commit:  commit,
+		entries: entries,
+		expires: time.Now().Add(p.ttl),
+	}
+}
@@ -0,0 +1,180 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func mockSubRepoPermsConfig(rulesFile bool) func() {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		PermissionsSubRepository: &schema.PermissionsSubRepository{Enabled: true, RulesFile: rulesFile},
+	}})
+	return func() { conf.Mock(nil) }
+}
+
+func TestSubRepoPerms_disabled(t *testing.T) {
+	ctx := testContext()
+	db.Mocks.SubRepoPerms.ListByRepo = func(context.Context, api.RepoID) ([]*types.SubRepoPathRule, error) {
+		t.Fatal("ListByRepo should not be called when sub-repository permissions are disabled")
+		return nil, nil
+	}
+
+	perms, err := SubRepoPerms(ctx, &types.Repo{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perms.Restricted() {
+		t.Errorf("got restricted perms %+v", perms)
+	}
+}
+
+func TestSubRepoPerms_explicit(t *testing.T) {
+	ctx := testContext()
+	defer mockSubRepoPermsConfig(false)()
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, Username: "alice"}, nil
+	}
+	db.Mocks.Orgs.GetByUserID = func(ctx context.Context, userID int32) ([]*types.Org, error) {
+		return []*types.Org{{ID: 10, Name: "billing"}}, nil
+	}
+	db.Mocks.SubRepoPerms.ListByRepo = func(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error) {
+		return []*types.SubRepoPathRule{
+			{PathPrefix: "billing", OrgIDs: []int32{10}},
+			{PathPrefix: "secret", UserIDs: []int32{2}},
+			{PathPrefix: "secret/alice", UserIDs: []int32{1}},
+		}, nil
+	}
+
+	repo := &types.Repo{ID: 1}
+	perms, err := SubRepoPerms(ctx, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]bool{
+		"README.md":          true,
+		"billing/invoice.go": true,
+		"secret/key.pem":     false,
+		"secret/alice/notes": true,
+	} {
+		if got := perms.Allowed(path); got != want {
+			t.Errorf("Allowed(%q): got %v, want %v", path, got, want)
+		}
+	}
+
+	if err := CheckSubRepoPath(ctx, repo, "secret/key.pem", false); !os.IsNotExist(err) {
+		t.Errorf("CheckSubRepoPath: got err %v, want not exist", err)
+	}
+	if err := CheckSubRepoPath(ctx, repo, "secret/bob", true); !os.IsNotExist(err) {
+		t.Errorf("CheckSubRepoPath: got err %v, want not exist", err)
+	}
+	// The parent of a readable path must stay listable.
+	if err := CheckSubRepoPath(ctx, repo, "secret", true); err != nil {
+		t.Errorf("CheckSubRepoPath: got err %v, want nil", err)
+	}
+
+	t.Run("site admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		perms, err := SubRepoPerms(ctx, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perms.Restricted() {
+			t.Errorf("got restricted perms %+v for site admin", perms)
+		}
+	})
+}
+
+func TestSubRepoPerms_rulesFile(t *testing.T) {
+	ctx := testContext()
+	defer mockSubRepoPermsConfig(true)()
+	defer git.ResetMocks()
+
+	rulesFileSubRepoPermsProvider.cache = map[api.RepoID]*rulesFileCacheEntry{}
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, Username: "alice"}, nil
+	}
+	db.Mocks.Orgs.GetByUserID = func(ctx context.Context, userID int32) ([]*types.Org, error) {
+		return nil, nil
+	}
+	db.Mocks.SubRepoPerms.ListByRepo = func(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error) {
+		return nil, nil
+	}
+	git.Mocks.ResolveRevision = func(spec string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		return "deadbeef", nil
+	}
+	var reads int
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		reads++
+		if name != ".sourcegraph/path-permissions" {
+			t.Errorf("got name %q", name)
+		}
+		return []byte("private @bob\nprivate/alice @alice\n"), nil
+	}
+
+	repo := &types.Repo{ID: 2, Name: "r"}
+	for i := 0; i < 2; i++ {
+		perms, err := SubRepoPerms(ctx, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perms.Allowed("private/x") || !perms.Allowed("private/alice/x") || !perms.Allowed("public") {
+			t.Errorf("got unexpected perms %+v", perms)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("got %d reads of the rules file, want 1 (cached)", reads)
+	}
+
+	t.Run("invalid rules file fails closed", func(t *testing.T) {
+		git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+			return []byte("private alice"), nil
+		}
+		if _, err := SubRepoPerms(ctx, &types.Repo{ID: 3, Name: "r3"}); err == nil {
+			t.Error("got nil error")
+		}
+	})
+
+	t.Run("no rules file", func(t *testing.T) {
+		git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		perms, err := SubRepoPerms(ctx, &types.Repo{ID: 4, Name: "r4"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perms.Restricted() {
+			t.Errorf("got restricted perms %+v", perms)
+		}
+	})
+}
+
+func TestSubRepoPerms_providerError(t *testing.T) {
+	ctx := testContext()
+	defer mockSubRepoPermsConfig(false)()
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.SubRepoPerms.ListByRepo = func(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error) {
+		return nil, errors.New("x")
+	}
+	if _, err := SubRepoPerms(ctx, &types.Repo{ID: 1}); err == nil {
+		t.Error("got nil error")
+	}
+	if err := CheckSubRepoPath(ctx, &types.Repo{ID: 1}, "a", false); err == nil {
+		t.Error("got nil error")
+	}
+}
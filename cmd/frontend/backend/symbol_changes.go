@@ -0,0 +1,285 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Symbol change types.
+const (
+	SymbolAdded    = "ADDED"
+	SymbolRemoved  = "REMOVED"
+	SymbolModified = "MODIFIED"
+)
+
+// SymbolChange describes a symbol (such as a function, type, or method) that was added, removed,
+// or modified between two commits.
+type SymbolChange struct {
+	Type string // SymbolAdded, SymbolRemoved, or SymbolModified
+
+	// OldSymbol is the symbol in the base commit (nil if it was added), and NewSymbol is the symbol
+	// in the head commit (nil if it was removed).
+	OldSymbol, NewSymbol *protocol.Symbol
+}
+
+// maxSymbolChangesFiles is the maximum number of changed files whose symbols are compared. The
+// symbol changes of larger diffs are computed for the first maxSymbolChangesFiles files only.
+const maxSymbolChangesFiles = 200
+
+// maxSymbolsPerCommit is the maximum number of symbols that are listed in the changed files of
+// each commit.
+const maxSymbolsPerCommit = 50000
+
+// emptyTreeSHA is `git hash-object -t tree /dev/null`, which is diffed against when there is no
+// base commit.
+const emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+var symbolChangesCache = rcache.New("symbol_changes:v1")
+
+// SymbolChanges returns the symbols that were added, removed, or modified between the base and head
+// commits of repo, in the files that the current user may read. The base commit is diffed directly
+// against the head commit (callers comparing branches must pass their merge base). An empty base
+// means that head is a root commit.
+//
+// The changes are computed by running the symbols parser on both versions of each changed file.
+// Symbols are matched by name, kind, and container, and a matched symbol is modified if its line
+// range (up to the next symbol that it doesn't contain) intersects a changed line.
+func SymbolChanges(ctx context.Context, repo *types.Repo, base, head api.CommitID) ([]*SymbolChange, error) {
+	if (base != "" && !git.IsAbsoluteRevision(string(base))) || !git.IsAbsoluteRevision(string(head)) {
+		return nil, errors.Errorf("refusing to compute symbol changes for non-absolute commit IDs %q and %q", base, head)
+	}
+
+	var changes []*SymbolChange
+	cacheKey := fmt.Sprintf("%d:%s:%s", repo.ID, base, head)
+	if b, ok := symbolChangesCache.Get(cacheKey); ok {
+		if err := json.Unmarshal(b, &changes); err != nil {
+			log15.Warn("Failed to unmarshal cached JSON symbol changes.", "repo", repo.Name, "base", base, "head", head, "err", err)
+			changes = nil
+		}
+	}
+	if changes == nil {
+		var err error
+		changes, err = computeSymbolChanges(ctx, repo, base, head)
+		if err != nil {
+			return nil, err
+		}
+		if b, err := json.Marshal(changes); err == nil {
+			symbolChangesCache.Set(cacheKey, b)
+		}
+	}
+
+	// 🚨 SECURITY: Omit the symbols of files that the user may not read. This happens after the
+	// cache lookup because the cache is shared by all users.
+	perms, err := SubRepoPerms(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !perms.Restricted() {
+		return changes, nil
+	}
+	filtered := changes[:0:0]
+	for _, c := range changes {
+		if (c.OldSymbol == nil || perms.Allowed(c.OldSymbol.Path)) && (c.NewSymbol == nil || perms.Allowed(c.NewSymbol.Path)) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+func computeSymbolChanges(ctx context.Context, repo *types.Repo, base, head api.CommitID) ([]*SymbolChange, error) {
+	cachedRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	diffBase := string(base)
+	if diffBase == "" {
+		diffBase = emptyTreeSHA
+	}
+	rdr, err := git.ExecReader(ctx, *cachedRepo, []string{
+		"diff",
+		"--find-renames",
+		"--full-index",
+		"--no-prefix",
+		"--unified=0", // hunks contain only the changed lines
+		diffBase,
+		string(head),
+		"--",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	var fileDiffs []*diff.FileDiff
+	dr := diff.NewMultiFileDiffReader(rdr)
+	for len(fileDiffs) < maxSymbolChangesFiles {
+		fileDiff, err := dr.ReadFile()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fileDiffs = append(fileDiffs, fileDiff)
+	}
+	if len(fileDiffs) == 0 {
+		return []*SymbolChange{}, nil
+	}
+
+	listSymbols := func(commit api.CommitID, paths []string) ([]protocol.Symbol, error) {
+		if commit == "" || len(paths) == 0 {
+			return nil, nil
+		}
+		quoted := make([]string, len(paths))
+		for i, p := range paths {
+			quoted[i] = regexp.QuoteMeta(p)
+		}
+		return Symbols.ListTags(ctx, search.SymbolsParameters{
+			Repo:            repo.Name,
+			CommitID:        commit,
+			IsCaseSensitive: true,
+			IncludePatterns: []string{"^(" + strings.Join(quoted, "|") + ")$"},
+			First:           maxSymbolsPerCommit,
+		})
+	}
+	var oldPaths, newPaths []string
+	for _, fd := range fileDiffs {
+		if fd.OrigName != "/dev/null" {
+			oldPaths = append(oldPaths, fd.OrigName)
+		}
+		if fd.NewName != "/dev/null" {
+			newPaths = append(newPaths, fd.NewName)
+		}
+	}
+	oldSymbols, err := listSymbols(base, oldPaths)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing symbols of base commit")
+	}
+	newSymbols, err := listSymbols(head, newPaths)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing symbols of head commit")
+	}
+	return diffSymbols(fileDiffs, oldSymbols, newSymbols), nil
+}
+
+// diffSymbols returns the symbol changes in fileDiffs (which must have no context lines), given the
+// symbols of the old and new versions of the changed files.
+func diffSymbols(fileDiffs []*diff.FileDiff, oldSymbols, newSymbols []protocol.Symbol) []*SymbolChange {
+	oldByPath := symbolsByPath(oldSymbols)
+	newByPath := symbolsByPath(newSymbols)
+
+	changes := []*SymbolChange{}
+	for _, fd := range fileDiffs {
+		var oldLines, newLines []lineRange
+		for _, h := range fd.Hunks {
+			if h.OrigLines > 0 {
+				oldLines = append(oldLines, lineRange{int(h.OrigStartLine), int(h.OrigStartLine + h.OrigLines - 1)})
+			}
+			if h.NewLines > 0 {
+				newLines = append(newLines, lineRange{int(h.NewStartLine), int(h.NewStartLine + h.NewLines - 1)})
+			}
+		}
+
+		oldFile := symbolRanges(oldByPath[fd.OrigName])
+		newFile := symbolRanges(newByPath[fd.NewName])
+
+		// Match symbols by name, kind, and container. Symbols with the same key (such as overloaded
+		// methods) are matched in order of appearance.
+		unmatched := map[symbolKey][]rangedSymbol{}
+		for _, s := range oldFile {
+			k := keyOf(s.Symbol)
+			unmatched[k] = append(unmatched[k], s)
+		}
+		for _, s := range newFile {
+			s := s
+			k := keyOf(s.Symbol)
+			if len(unmatched[k]) == 0 {
+				changes = append(changes, &SymbolChange{Type: SymbolAdded, NewSymbol: &s.Symbol})
+				continue
+			}
+			old := unmatched[k][0]
+			unmatched[k] = unmatched[k][1:]
+			if old.intersects(oldLines) || s.intersects(newLines) {
+				changes = append(changes, &SymbolChange{Type: SymbolModified, OldSymbol: &old.Symbol, NewSymbol: &s.Symbol})
+			}
+		}
+		for _, s := range oldFile {
+			k := keyOf(s.Symbol)
+			if len(unmatched[k]) > 0 && unmatched[k][0].Line == s.Line {
+				s := unmatched[k][0]
+				unmatched[k] = unmatched[k][1:]
+				changes = append(changes, &SymbolChange{Type: SymbolRemoved, OldSymbol: &s.Symbol})
+			}
+		}
+	}
+	return changes
+}
+
+type symbolKey struct{ name, kind, parent string }
+
+func keyOf(s protocol.Symbol) symbolKey {
+	return symbolKey{name: s.Name, kind: s.Kind, parent: s.Parent}
+}
+
+// lineRange is an inclusive range of 1-based line numbers.
+type lineRange struct{ start, end int }
+
+type rangedSymbol struct {
+	protocol.Symbol
+	lineRange
+}
+
+func (s rangedSymbol) intersects(lines []lineRange) bool {
+	for _, l := range lines {
+		if l.start <= s.end && s.start <= l.end {
+			return true
+		}
+	}
+	return false
+}
+
+func symbolsByPath(symbols []protocol.Symbol) map[string][]protocol.Symbol {
+	m := map[string][]protocol.Symbol{}
+	for _, s := range symbols {
+		m[s.Path] = append(m[s.Path], s)
+	}
+	return m
+}
+
+// symbolRanges returns the symbols of a file, sorted by line, with their line ranges. The symbols
+// parser only reports the line on which a symbol is defined, so a symbol's range is assumed to end
+// before the next symbol that it doesn't contain (or at the end of the file).
+func symbolRanges(symbols []protocol.Symbol) []rangedSymbol {
+	sorted := make([]protocol.Symbol, len(symbols))
+	copy(sorted, symbols)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Line < sorted[j].Line })
+
+	ranged := make([]rangedSymbol, len(sorted))
+	for i, s := range sorted {
+		end := math.MaxInt32
+		for _, next := range sorted[i+1:] {
+			if next.Line > s.Line && next.Parent != s.Name {
+				end = next.Line - 1
+				break
+			}
+		}
+		ranged[i] = rangedSymbol{Symbol: s, lineRange: lineRange{start: s.Line, end: end}}
+	}
+	return ranged
+}
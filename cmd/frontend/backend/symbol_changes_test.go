@@ -0,0 +1,186 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitservertest"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	symbolsclient "github.com/sourcegraph/sourcegraph/internal/symbols"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestDiffSymbols(t *testing.T) {
+	// a.go: line 3 was changed and line 9 was added at the end.
+	// b.go: was deleted.
+	// c.go: was added.
+	fileDiffs, err := diff.ParseMultiFileDiff([]byte(`diff --git a.go a.go
+--- a.go
++++ a.go
+@@ -3 +3 @@ func A() {
+-	return 1
++	return 2
+@@ -8,0 +9 @@ func (T) M() {}
++func D() {}
+diff --git b.go b.go
+deleted file mode 100644
+--- b.go
++++ /dev/null
+@@ -1 +0,0 @@
+-func B() {}
+diff --git c.go c.go
+new file mode 100644
+--- /dev/null
++++ c.go
+@@ -0,0 +1 @@
++func C() {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sym := func(path, name, kind, parent string, line int) protocol.Symbol {
+		return protocol.Symbol{Path: path, Name: name, Kind: kind, Parent: parent, Line: line}
+	}
+	oldSymbols := []protocol.Symbol{
+		sym("a.go", "A", "func", "", 2),
+		sym("a.go", "T", "type", "", 6),
+		sym("a.go", "M", "method", "T", 8),
+		sym("b.go", "B", "func", "", 1),
+	}
+	newSymbols := []protocol.Symbol{
+		sym("a.go", "A", "func", "", 2),
+		sym("a.go", "T", "type", "", 6),
+		sym("a.go", "M", "method", "T", 8),
+		sym("a.go", "D", "func", "", 9),
+		sym("c.go", "C", "func", "", 1),
+	}
+	changes := diffSymbols(fileDiffs, oldSymbols, newSymbols)
+
+	type change struct{ Type, Old, New string }
+	var got []change
+	for _, c := range changes {
+		var g change
+		g.Type = c.Type
+		if c.OldSymbol != nil {
+			g.Old = c.OldSymbol.Path + ":" + c.OldSymbol.Name
+		}
+		if c.NewSymbol != nil {
+			g.New = c.NewSymbol.Path + ":" + c.NewSymbol.Name
+		}
+		got = append(got, g)
+	}
+	want := []change{
+		{Type: SymbolModified, Old: "a.go:A", New: "a.go:A"},
+		{Type: SymbolAdded, New: "a.go:D"},
+		{Type: SymbolRemoved, Old: "b.go:B"},
+		{Type: SymbolAdded, New: "c.go:C"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSymbolRanges(t *testing.T) {
+	got := symbolRanges([]protocol.Symbol{
+		{Name: "M", Parent: "T", Line: 3},
+		{Name: "T", Line: 1},
+		{Name: "F", Line: 7},
+	})
+	want := map[string]lineRange{
+		"T": {start: 1, end: 6}, // contains M
+		"M": {start: 3, end: 6},
+		"F": {start: 7, end: 1<<31 - 1},
+	}
+	for _, s := range got {
+		if s.lineRange != want[s.Name] {
+			t.Errorf("%s: got range %+v, want %+v", s.Name, s.lineRange, want[s.Name])
+		}
+	}
+}
+
+// TestComputeSymbolChanges runs the diff through gitserver (whose command whitelist it must pass)
+// against a real repository.
+func TestComputeSymbolChanges(t *testing.T) {
+	gitserverServer := gitservertest.Start(t)
+	defer gitserverServer.Close()
+	head := gitserverServer.MakeRepository(t, "example.com/symbols",
+		`printf 'package a\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() {}\n' > a.go`,
+		"git add . && git commit -qm base",
+		`printf 'package a\n\nfunc A() int {\n\treturn 2\n}\n' > a.go`,
+		`printf 'package a\n\nfunc C() {}\n' > c.go`,
+		"git add . && git commit -qm head",
+	)
+	repo := &types.Repo{ID: 1, Name: "example.com/symbols"}
+	base, err := git.ResolveRevision(context.Background(), gitserver.Repo{Name: repo.Name}, nil, string(head)+"~1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The symbols service is faked with the symbols of each commit.
+	symbolsByCommit := map[api.CommitID][]protocol.Symbol{
+		base: {
+			{Name: "A", Kind: "func", Path: "a.go", Line: 3, Language: "Go"},
+			{Name: "B", Kind: "func", Path: "a.go", Line: 7, Language: "Go"},
+		},
+		head: {
+			{Name: "A", Kind: "func", Path: "a.go", Line: 3, Language: "Go"},
+			{Name: "C", Kind: "func", Path: "c.go", Line: 3, Language: "Go"},
+		},
+	}
+	symbols := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args search.SymbolsParameters
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var result protocol.SearchResult
+		for _, s := range symbolsByCommit[args.CommitID] {
+			for _, pattern := range args.IncludePatterns {
+				if regexp.MustCompile(pattern).MatchString(s.Path) {
+					result.Symbols = append(result.Symbols, s)
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer symbols.Close()
+	defer func(orig *symbolsclient.Client) { symbolsclient.DefaultClient = orig }(symbolsclient.DefaultClient)
+	symbolsclient.DefaultClient = &symbolsclient.Client{URL: symbols.URL, HTTPClient: http.DefaultClient}
+
+	changes, err := computeSymbolChanges(context.Background(), repo, base, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*SymbolChange{
+		{Type: SymbolModified, OldSymbol: &symbolsByCommit[base][0], NewSymbol: &symbolsByCommit[head][0]},
+		{Type: SymbolRemoved, OldSymbol: &symbolsByCommit[base][1]},
+		{Type: SymbolAdded, NewSymbol: &symbolsByCommit[head][1]},
+	}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("symbol changes mismatch (-want +got):\n%s", diff)
+	}
+
+	// A root commit is diffed against the empty tree.
+	changes, err = computeSymbolChanges(context.Background(), repo, "", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []*SymbolChange{
+		{Type: SymbolAdded, NewSymbol: &symbolsByCommit[base][0]},
+		{Type: SymbolAdded, NewSymbol: &symbolsByCommit[base][1]},
+	}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("root commit symbol changes mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,281 @@
+package backend
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const (
+	// maxWatchFeedCommits is the maximum number of commits added to the feed of a watch per update,
+	// so that a huge push (or a newly watched history) doesn't flood the feed.
+	maxWatchFeedCommits = 100
+
+	// maxWatchFeedEntryPaths is the maximum number of paths recorded per feed entry.
+	maxWatchFeedEntryPaths = 100
+
+	// maxWatchDigestEntries is the maximum number of feed entries listed in an email digest.
+	maxWatchDigestEntries = 100
+)
+
+// NormalizeWatchPathPattern validates a watch's path pattern and returns it in the form stored in
+// the database. The pattern is a glob relative to the repository root, in which "*" doesn't match
+// "/" and "**" matches any number of directories. Patterns without wildcards match the path and
+// everything below it.
+func NormalizeWatchPathPattern(pattern string) (string, error) {
+	pattern = strings.Trim(strings.TrimSpace(pattern), "/")
+	if strings.ContainsAny(pattern, "\x00\n") {
+		return "", errors.New("invalid path pattern")
+	}
+	for _, c := range strings.Split(pattern, "/") {
+		if c == "." || c == ".." || (c == "" && pattern != "") {
+			return "", errors.Errorf("invalid path pattern %q (must not contain empty, . or .. components)", pattern)
+		}
+	}
+	return pattern, nil
+}
+
+// watchPathspecs returns the git pathspecs that match the paths of a watch's path pattern.
+func watchPathspecs(pattern string) []string {
+	if pattern == "" {
+		return nil
+	}
+	return []string{":(glob)" + pattern}
+}
+
+// UpdateWatchFeed adds the commits that changed the repository's default branch since its last
+// update to the activity feeds of the repository's watches. The first update of a repository only
+// records its current head, so watches never backfill old commits.
+func UpdateWatchFeed(ctx context.Context, repoID api.RepoID) error {
+	// 🚨 SECURITY: The feed entries are computed for all users of the watches. Each user's
+	// permissions are checked when the entries are read (see FilterWatchFeedEntries).
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	repo, err := db.Repos.Get(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	gitRepo, err := CachedGitRepo(ctx, repo)
+	if err != nil {
+		return err
+	}
+	head, err := git.ResolveRevision(ctx, *gitRepo, nil, "HEAD", &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if vcs.IsRepoNotExist(err) || gitserver.IsRevisionNotFound(err) {
+		return nil // not cloned yet, or empty
+	} else if err != nil {
+		return err
+	}
+
+	lastHead, err := db.Watches.GetRepoHead(ctx, repoID)
+	if err != nil || lastHead == head {
+		return err
+	}
+	if lastHead != "" {
+		if err := addWatchFeedEntries(ctx, repo, *gitRepo, lastHead, head); err != nil {
+			return err
+		}
+	}
+	return db.Watches.SetRepoHead(ctx, repoID, head)
+}
+
+func addWatchFeedEntries(ctx context.Context, repo *types.Repo, gitRepo gitserver.Repo, lastHead, head api.CommitID) error {
+	watches, err := db.Watches.List(ctx, db.WatchesListOptions{RepoID: repo.ID})
+	if err != nil {
+		return err
+	}
+
+	// Watches with the same path pattern share the commits.
+	byPattern := map[string][]*db.Watch{}
+	for _, w := range watches {
+		byPattern[w.PathPattern] = append(byPattern[w.PathPattern], w)
+	}
+	for pattern, watches := range byPattern {
+		commits, err := git.CommitsWithFiles(ctx, gitRepo, string(lastHead)+".."+string(head), watchPathspecs(pattern), maxWatchFeedCommits)
+		if gitserver.IsRevisionNotFound(err) {
+			// The last head no longer exists (e.g., because the repository was recloned after a
+			// force push), so the new commits can't be determined. Start over from the new head.
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(commits) == 0 {
+			continue
+		}
+
+		entries := make([]*db.WatchFeedEntry, len(commits))
+		for i, c := range commits {
+			paths := c.Files
+			if len(paths) > maxWatchFeedEntryPaths {
+				paths = paths[:maxWatchFeedEntryPaths]
+			}
+			if paths == nil {
+				paths = []string{}
+			}
+			entries[i] = &db.WatchFeedEntry{
+				CommitID:   c.ID,
+				Subject:    strings.SplitN(c.Message, "\n", 2)[0],
+				AuthorName: c.Author.Name,
+				Paths:      paths,
+			}
+		}
+		for _, w := range watches {
+			if err := db.Watches.AddFeedEntries(ctx, w.ID, entries); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// A WatchFeedItem is an activity feed entry that the current actor may view.
+type WatchFeedItem struct {
+	Entry *db.WatchFeedEntry // with only the paths that the actor may view
+	Repo  *types.Repo
+}
+
+// FilterWatchFeedEntries returns the activity feed entries that the current actor may view, with
+// only the paths that the actor may view.
+//
+// 🚨 SECURITY: Feed entries are computed regardless of the permissions of the watch's user, so they
+// must be filtered with this function before they are revealed.
+func FilterWatchFeedEntries(ctx context.Context, entries []*db.WatchFeedEntry) ([]*WatchFeedItem, error) {
+	type repoPerms struct {
+		repo  *types.Repo // nil if the actor may not view the repository
+		perms *authz.SubRepoPerms
+	}
+	repos := map[api.RepoID]*repoPerms{}
+
+	var items []*WatchFeedItem
+	for _, e := range entries {
+		rp, ok := repos[e.RepoID]
+		if !ok {
+			rp = &repoPerms{}
+			repo, err := db.Repos.Get(ctx, e.RepoID)
+			if err != nil && !errcode.IsNotFound(err) {
+				return nil, err
+			}
+			if repo != nil {
+				perms, err := SubRepoPerms(ctx, repo)
+				if err != nil {
+					return nil, err
+				}
+				rp.repo, rp.perms = repo, perms
+			}
+			repos[e.RepoID] = rp
+		}
+		if rp.repo == nil {
+			continue
+		}
+
+		entry := *e
+		if rp.perms.Restricted() {
+			entry.Paths = nil
+			for _, p := range e.Paths {
+				if rp.perms.Allowed(p) {
+					entry.Paths = append(entry.Paths, p)
+				}
+			}
+			// 🚨 SECURITY: Even the commit's existence is hidden if the actor may not view any of
+			// the files that it changed.
+			if len(entry.Paths) == 0 {
+				continue
+			}
+		}
+		items = append(items, &WatchFeedItem{Entry: &entry, Repo: rp.repo})
+	}
+	return items, nil
+}
+
+// SendWatchDigest emails the user a digest of the activity feed entries of the user's watches with
+// email digests that haven't been emailed yet, and marks them as emailed. Nothing is sent if the
+// user has no verified primary email address or may not view any of the entries.
+func SendWatchDigest(ctx context.Context, userID int32) error {
+	// 🚨 SECURITY: The entries are filtered with the permissions of the user who receives them.
+	ctx = actor.WithActor(ctx, actor.FromUser(userID))
+
+	entries, err := db.Watches.ListFeedEntries(ctx, db.WatchFeedEntriesListOptions{UserID: userID, OnlyDigest: true})
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	items, err := FilterWatchFeedEntries(ctx, entries)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		if err := sendWatchDigestEmail(ctx, userID, items); err != nil {
+			return err
+		}
+	}
+	return db.Watches.MarkEmailed(ctx, userID, entries[0].ID)
+}
+
+func sendWatchDigestEmail(ctx context.Context, userID int32, items []*WatchFeedItem) error {
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, userID)
+	if errcode.IsNotFound(err) || (err == nil && !verified) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	type commit struct {
+		Repo, Subject, Author, URL string
+	}
+	data := struct {
+		Count   int
+		More    int
+		Commits []commit
+	}{Count: len(items)}
+	for _, item := range items {
+		if len(data.Commits) == maxWatchDigestEntries {
+			data.More = len(items) - maxWatchDigestEntries
+			break
+		}
+		u := globals.ExternalURL().ResolveReference(&url.URL{Path: "/" + string(item.Repo.Name) + "/-/commit/" + string(item.Entry.CommitID)})
+		data.Commits = append(data.Commits, commit{
+			Repo:    string(item.Repo.Name),
+			Subject: item.Entry.Subject,
+			Author:  item.Entry.AuthorName,
+			URL:     u.String(),
+		})
+	}
+	return txemail.Send(ctx, txemail.Message{
+		To:       []string{email},
+		Template: watchDigestEmailTemplates,
+		Data:     data,
+	})
+}
+
+var watchDigestEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Count}} new {{if eq .Count 1}}commit{{else}}commits{{end}} in code you watch on Sourcegraph`,
+	Text: `
+New commits changed the repositories and paths you watch on Sourcegraph:
+{{range .Commits}}
+  {{.Repo}}: {{.Subject}} ({{.Author}})
+  {{.URL}}
+{{end}}{{if .More}}
+...and {{.More}} more.
+{{end}}`,
+	HTML: `
+<p>New commits changed the repositories and paths you watch on Sourcegraph:</p>
+
+<ul>
+{{range .Commits}}  <li><strong>{{.Repo}}</strong>: <a href="{{.URL}}">{{.Subject}}</a> ({{.Author}})</li>
+{{end}}</ul>
+{{if .More}}
+<p>...and {{.More}} more.</p>
+{{end}}`,
+})
@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestNormalizeWatchPathPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{pattern: "", want: ""},
+		{pattern: " /pkg/auth/ ", want: "pkg/auth"},
+		{pattern: "pkg/auth/**", want: "pkg/auth/**"},
+		{pattern: "**/*.go", want: "**/*.go"},
+		{pattern: "pkg//auth", wantErr: true},
+		{pattern: "pkg/../secrets", wantErr: true},
+		{pattern: "./pkg", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := NormalizeWatchPathPattern(test.pattern)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: got error %v, want error %v", test.pattern, err, test.wantErr)
+		} else if got != test.want {
+			t.Errorf("%q: got %q, want %q", test.pattern, got, test.want)
+		}
+	}
+}
+
+// 🚨 SECURITY: This tests that activity feed entries don't reveal repositories or files that the
+// actor may not view.
+func TestFilterWatchFeedEntries(t *testing.T) {
+	ctx := testContext()
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		if id == 2 {
+			return nil, &errcode.Mock{IsNotFound: true}
+		}
+		return &types.Repo{ID: id, Name: "github.com/acme/a"}, nil
+	}
+	Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secrets", Allow: false}}}, nil
+	}
+
+	entries := []*db.WatchFeedEntry{
+		{ID: 4, RepoID: 1, Paths: []string{"secrets/key", "README"}},
+		{ID: 3, RepoID: 1, Paths: []string{"secrets/key"}},
+		{ID: 2, RepoID: 2, Paths: []string{"README"}},
+		{ID: 1, RepoID: 1, Paths: []string{}},
+	}
+	items, err := FilterWatchFeedEntries(ctx, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	if items[0].Entry.ID != 4 || items[0].Repo.ID != 1 {
+		t.Errorf("got item %+v", items[0])
+	}
+	if want := []string{"README"}; !reflect.DeepEqual(items[0].Entry.Paths, want) {
+		t.Errorf("got paths %q, want %q", items[0].Entry.Paths, want)
+	}
+	if len(entries[0].Paths) != 2 {
+		t.Error("entries must not be modified")
+	}
+
+	// Without sub-repository permissions, all entries of viewable repositories are returned.
+	Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) { return nil, nil }
+	items, err = FilterWatchFeedEntries(ctx, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Errorf("got %d items, want 3", len(items))
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 )
 
 // AccessToken describes an access token. The actual token (that a caller must supply to
@@ -49,6 +50,7 @@ type accessTokens struct{}
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
 // specified user (i.e., that the actor is either the user or a site admin).
 func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "Create")
 	if Mocks.AccessTokens.Create != nil {
 		return Mocks.AccessTokens.Create(subjectUserID, scopes, note, creatorUserID)
 	}
@@ -65,10 +67,15 @@ func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes [
 		return 0, "", errors.New("access tokens without scopes are not supported")
 	}
 
-	if err := dbconn.Global.QueryRowContext(ctx,
-		// Include users table query (with "FOR UPDATE") to ensure that subject/creator users have
-		// not been deleted. If they were deleted, the query will return an error.
-		`
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if err := Quotas.Check(ctx, tx, QuotaNamespace{UserID: subjectUserID}, QuotaAccessTokens, 1); err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx,
+			// Include users table query (with "FOR UPDATE") to ensure that subject/creator users have
+			// not been deleted. If they were deleted, the query will return an error.
+			`
 WITH subject_user AS (
   SELECT id FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE
 ),
@@ -81,8 +88,10 @@ insert_values AS (
 )
 INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id) SELECT * FROM insert_values RETURNING id
 `,
-		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID,
-	).Scan(&id); err != nil {
+			subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID,
+		).Scan(&id)
+	})
+	if err != nil {
 		return 0, "", err
 	}
 	return id, token, nil
@@ -96,6 +105,7 @@ INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_u
 // 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
 // non-deleted access token.
 func (s *accessTokens) Lookup(ctx context.Context, tokenHexEncoded string, requiredScope string) (subjectUserID int32, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "Lookup")
 	if Mocks.AccessTokens.Lookup != nil {
 		return Mocks.AccessTokens.Lookup(tokenHexEncoded, requiredScope)
 	}
@@ -135,6 +145,7 @@ RETURNING t.subject_user_id
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to view this access token.
 func (s *accessTokens) GetByID(ctx context.Context, id int64) (*AccessToken, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "GetByID")
 	if Mocks.AccessTokens.GetByID != nil {
 		return Mocks.AccessTokens.GetByID(id)
 	}
@@ -176,6 +187,7 @@ func (o AccessTokensListOptions) sqlConditions() []*sqlf.Query {
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
 // options.
 func (s *accessTokens) List(ctx context.Context, opt AccessTokensListOptions) ([]*AccessToken, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "List")
 	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
 }
 
@@ -212,6 +224,7 @@ created_at DESC
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to count the tokens.
 func (s *accessTokens) Count(ctx context.Context, opt AccessTokensListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "Count")
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM access_tokens WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
 	var count int
 	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
@@ -224,6 +237,7 @@ func (s *accessTokens) Count(ctx context.Context, opt AccessTokensListOptions) (
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to delete the token.
 func (s *accessTokens) DeleteByID(ctx context.Context, id int64, subjectUserID int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "DeleteByID")
 	if Mocks.AccessTokens.DeleteByID != nil {
 		return Mocks.AccessTokens.DeleteByID(id, subjectUserID)
 	}
@@ -233,6 +247,7 @@ func (s *accessTokens) DeleteByID(ctx context.Context, id int64, subjectUserID i
 // DeleteByToken deletes an access token given the secret token value itself (i.e., the same value
 // that an API client would use to authenticate).
 func (s *accessTokens) DeleteByToken(ctx context.Context, tokenHexEncoded string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "AccessTokens", "DeleteByToken")
 	token, err := hex.DecodeString(tokenHexEncoded)
 	if err != nil {
 		return errors.Wrap(err, "AccessTokens.DeleteByToken")
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/clones"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A CloneIndexUpdate is an update of the code clone index of a repository to a new commit.
+type CloneIndexUpdate struct {
+	CommitID     api.CommitID
+	Reset        bool              // remove all files of the repository from the index first
+	RemovedPaths []string          // the files that were deleted or changed since the last indexed commit
+	Files        []*CloneIndexFile // the new or changed files that have fingerprints
+}
+
+// A CloneIndexFile is an indexed file and its fingerprints.
+type CloneIndexFile struct {
+	Path         string
+	Fingerprints []clones.Fingerprint
+}
+
+// A CloneRegion is a region of an indexed file.
+type CloneRegion struct {
+	RepoID    api.RepoID
+	CommitID  api.CommitID // the indexed commit of the repository
+	Path      string
+	StartLine int
+	EndLine   int
+}
+
+// A CloneMatch is an indexed fingerprint that matches one of a set of hashes.
+type CloneMatch struct {
+	CloneRegion // the region of the fingerprint's tokens
+	Hash        int64
+}
+
+// A CloneCluster is a set of regions of indexed files that share the same fingerprints.
+type CloneCluster struct {
+	Fingerprints int // the number of shared fingerprints
+	Regions      []*CloneRegion
+}
+
+// codeClones provides access to the `clone_index_repos`, `clone_index_files`, and
+// `clone_fingerprints` tables.
+type codeClones struct{}
+
+// GetRepoCommit returns the indexed commit of the repository, or the empty string if it hasn't
+// been indexed.
+func (*codeClones) GetRepoCommit(ctx context.Context, repoID api.RepoID) (api.CommitID, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeClones", "GetRepoCommit")
+	var commitID api.CommitID
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT commit_id FROM clone_index_repos WHERE repo_id=$1", repoID).Scan(&commitID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return commitID, err
+}
+
+// UpdateRepo applies the update to the index of the repository.
+func (*codeClones) UpdateRepo(ctx context.Context, repoID api.RepoID, u *CloneIndexUpdate) error {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeClones", "UpdateRepo")
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if u.Reset {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM clone_index_files WHERE repo_id=$1", repoID); err != nil {
+				return err
+			}
+		} else if len(u.RemovedPaths) > 0 {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM clone_index_files WHERE repo_id=$1 AND path = ANY($2)", repoID, pq.Array(u.RemovedPaths)); err != nil {
+				return err
+			}
+		}
+
+		for _, f := range u.Files {
+			var fileID int64
+			if err := tx.QueryRowContext(ctx, `
+INSERT INTO clone_index_files(repo_id, path) VALUES($1, $2)
+ON CONFLICT (repo_id, path) DO UPDATE SET path=EXCLUDED.path
+RETURNING id`, repoID, f.Path).Scan(&fileID); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM clone_fingerprints WHERE file_id=$1", fileID); err != nil {
+				return err
+			}
+
+			hashes := make([]int64, len(f.Fingerprints))
+			startLines := make([]int64, len(f.Fingerprints))
+			endLines := make([]int64, len(f.Fingerprints))
+			for i, fp := range f.Fingerprints {
+				hashes[i], startLines[i], endLines[i] = fp.Hash, int64(fp.StartLine), int64(fp.EndLine)
+			}
+			if _, err := tx.ExecContext(ctx, `
+INSERT INTO clone_fingerprints(file_id, hash, start_line, end_line)
+SELECT $1, unnest($2::bigint[]), unnest($3::integer[]), unnest($4::integer[])`,
+				fileID, pq.Array(hashes), pq.Array(startLines), pq.Array(endLines),
+			); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `
+INSERT INTO clone_index_repos(repo_id, commit_id) VALUES($1, $2)
+ON CONFLICT (repo_id) DO UPDATE SET commit_id=EXCLUDED.commit_id, updated_at=now()`,
+			repoID, u.CommitID,
+		)
+		return err
+	})
+}
+
+// ListMatches lists at most limit indexed fingerprints (of all repositories) whose hash is one of
+// the given hashes.
+//
+// 🚨 SECURITY: The caller must filter the matches by the permissions of the actor.
+func (*codeClones) ListMatches(ctx context.Context, hashes []int64, limit int) ([]*CloneMatch, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeClones", "ListMatches")
+	if Mocks.CodeClones.ListMatches != nil {
+		return Mocks.CodeClones.ListMatches(ctx, hashes, limit)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT f.repo_id, r.commit_id, f.path, fp.start_line, fp.end_line, fp.hash
+FROM clone_fingerprints fp
+JOIN clone_index_files f ON f.id=fp.file_id
+JOIN clone_index_repos r ON r.repo_id=f.repo_id
+WHERE fp.hash = ANY(%s)
+ORDER BY f.repo_id, f.path, fp.start_line
+LIMIT %d`,
+		pq.Array(hashes), limit,
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*CloneMatch
+	for rows.Next() {
+		var m CloneMatch
+		if err := rows.Scan(&m.RepoID, &m.CommitID, &m.Path, &m.StartLine, &m.EndLine, &m.Hash); err != nil {
+			return nil, err
+		}
+		matches = append(matches, &m)
+	}
+	return matches, rows.Err()
+}
+
+// ListLargestClusters lists the limit clusters of regions (of at least two files) that share the
+// most fingerprints. The region of each file spans all of its shared fingerprints. This scans all
+// fingerprints of the index, so callers should cache the result.
+//
+// 🚨 SECURITY: The caller must filter the regions by the permissions of the actor.
+func (*codeClones) ListLargestClusters(ctx context.Context, limit int) ([]*CloneCluster, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeClones", "ListLargestClusters")
+	if Mocks.CodeClones.ListLargestClusters != nil {
+		return Mocks.CodeClones.ListLargestClusters(ctx, limit)
+	}
+
+	// Fingerprints that are shared by the same set of files form a cluster.
+	rows, err := dbconn.Global.QueryContext(ctx, `
+WITH shared AS (
+	SELECT hash, array_agg(DISTINCT file_id ORDER BY file_id) AS file_ids
+	FROM clone_fingerprints
+	GROUP BY hash
+	HAVING COUNT(DISTINCT file_id) > 1
+), clusters AS (
+	SELECT row_number() OVER (ORDER BY COUNT(*) DESC, file_ids) AS id, file_ids, array_agg(hash) AS hashes, COUNT(*) AS size
+	FROM shared
+	GROUP BY file_ids
+	ORDER BY size DESC, file_ids
+	LIMIT $1
+)
+SELECT c.id, c.size, f.repo_id, r.commit_id, f.path, MIN(fp.start_line), MAX(fp.end_line)
+FROM clusters c
+JOIN clone_index_files f ON f.id = ANY(c.file_ids)
+JOIN clone_index_repos r ON r.repo_id=f.repo_id
+JOIN clone_fingerprints fp ON fp.file_id=f.id AND fp.hash = ANY(c.hashes)
+GROUP BY c.id, c.size, f.repo_id, r.commit_id, f.path
+ORDER BY c.id, f.repo_id, f.path`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		clusters []*CloneCluster
+		lastID   int64
+	)
+	for rows.Next() {
+		var (
+			id int64
+			c  CloneCluster
+			r  CloneRegion
+		)
+		if err := rows.Scan(&id, &c.Fingerprints, &r.RepoID, &r.CommitID, &r.Path, &r.StartLine, &r.EndLine); err != nil {
+			return nil, err
+		}
+		if id != lastID {
+			clusters = append(clusters, &c)
+			lastID = id
+		}
+		last := clusters[len(clusters)-1]
+		last.Regions = append(last.Regions, &r)
+	}
+	return clusters, rows.Err()
+}
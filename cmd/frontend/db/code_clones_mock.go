@@ -0,0 +1,8 @@
+package db
+
+import "context"
+
+type MockCodeClones struct {
+	ListMatches         func(ctx context.Context, hashes []int64, limit int) ([]*CloneMatch, error)
+	ListLargestClusters func(ctx context.Context, limit int) ([]*CloneCluster, error)
+}
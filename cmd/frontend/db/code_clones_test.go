@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/clones"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestCodeClones(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	var repoIDs []api.RepoID
+	for _, name := range []api.RepoName{"github.com/acme/a", "github.com/acme/b"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+		repo, err := Repos.GetByName(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repoIDs = append(repoIDs, repo.ID)
+	}
+	a, b := repoIDs[0], repoIDs[1]
+
+	if commitID, err := CodeClones.GetRepoCommit(ctx, a); err != nil || commitID != "" {
+		t.Fatalf("got commit %q (err %v), want none", commitID, err)
+	}
+
+	fps := func(hashes ...int64) []clones.Fingerprint {
+		var fps []clones.Fingerprint
+		for i, h := range hashes {
+			fps = append(fps, clones.Fingerprint{Hash: h, StartLine: 10 * (i + 1), EndLine: 10*(i+1) + 5})
+		}
+		return fps
+	}
+	if err := CodeClones.UpdateRepo(ctx, a, &CloneIndexUpdate{
+		CommitID: "a1",
+		Reset:    true,
+		Files: []*CloneIndexFile{
+			{Path: "x.go", Fingerprints: fps(1, 2, 3)},
+			{Path: "y.go", Fingerprints: fps(9)},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CodeClones.UpdateRepo(ctx, b, &CloneIndexUpdate{
+		CommitID: "b1",
+		Reset:    true,
+		Files:    []*CloneIndexFile{{Path: "z.go", Fingerprints: fps(7, 1, 2)}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if commitID, err := CodeClones.GetRepoCommit(ctx, a); err != nil || commitID != "a1" {
+		t.Fatalf("got commit %q (err %v), want a1", commitID, err)
+	}
+
+	matches, err := CodeClones.ListMatches(ctx, []int64{2, 9}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*CloneMatch{
+		{CloneRegion: CloneRegion{RepoID: a, CommitID: "a1", Path: "x.go", StartLine: 20, EndLine: 25}, Hash: 2},
+		{CloneRegion: CloneRegion{RepoID: a, CommitID: "a1", Path: "y.go", StartLine: 10, EndLine: 15}, Hash: 9},
+		{CloneRegion: CloneRegion{RepoID: b, CommitID: "b1", Path: "z.go", StartLine: 30, EndLine: 35}, Hash: 2},
+	}; !reflect.DeepEqual(matches, want) {
+		t.Errorf("got matches %+v, want %+v", matches, want)
+	}
+
+	clusters, err := CodeClones.ListLargestClusters(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*CloneCluster{{
+		Fingerprints: 2,
+		Regions: []*CloneRegion{
+			{RepoID: a, CommitID: "a1", Path: "x.go", StartLine: 10, EndLine: 25},
+			{RepoID: b, CommitID: "b1", Path: "z.go", StartLine: 20, EndLine: 35},
+		},
+	}}; !reflect.DeepEqual(clusters, want) {
+		t.Errorf("got clusters %+v, want %+v", clusters, want)
+	}
+
+	// An incremental update replaces changed files and removes deleted files.
+	if err := CodeClones.UpdateRepo(ctx, a, &CloneIndexUpdate{
+		CommitID:     "a2",
+		RemovedPaths: []string{"x.go", "y.go"},
+		Files:        []*CloneIndexFile{{Path: "x.go", Fingerprints: fps(3)}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if matches, err := CodeClones.ListMatches(ctx, []int64{1, 2, 3, 9}, 10); err != nil {
+		t.Fatal(err)
+	} else if len(matches) != 3 || matches[0].CommitID != "a2" || matches[0].Hash != 3 {
+		t.Errorf("got matches %+v, want x.go at a2 and z.go", matches)
+	}
+	if clusters, err := CodeClones.ListLargestClusters(ctx, 10); err != nil || len(clusters) != 0 {
+		t.Errorf("got clusters %+v (err %v), want none", clusters, err)
+	}
+}
@@ -0,0 +1,269 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A CodeExpertiseUpdate is an update of the code expertise index of a repository to a new commit.
+type CodeExpertiseUpdate struct {
+	CommitID api.CommitID
+	Reset    bool               // remove all commits of the repository from the index first
+	Before   time.Time          // remove the commits of the repository that were authored before this time
+	Commits  []*ExpertiseCommit // the new commits
+}
+
+// An ExpertiseCommit is an indexed commit and the number of lines that it added to the files
+// directly in each directory ("" for the root directory).
+type ExpertiseCommit struct {
+	ID          api.CommitID
+	AuthorName  string
+	AuthorEmail string
+	AuthoredAt  time.Time
+	Lines       map[string]int
+}
+
+// ExpertiseListOptions specifies the indexed commits that ListAuthors and ListReviewers rank
+// people by.
+type ExpertiseListOptions struct {
+	// Dir is the directory whose lines (including those in its subdirectories) are counted.
+	Dir string
+	// Dirs, if non-nil, restricts the counted lines to those in these directories (not including
+	// their subdirectories).
+	Dirs []string
+	// HalfLife is the age at which the lines of a commit (or a reviewed pull request) count half
+	// as much as new ones.
+	HalfLife time.Duration
+}
+
+// An ExpertiseAuthor is a commit author (identified by their email) and the lines they authored.
+type ExpertiseAuthor struct {
+	Email        string
+	Name         string // the name of their most recent commit
+	UserID       int32  // the user with the verified email, or 0 if there is none
+	Lines        int
+	Score        float64 // the lines weighted by the age of their commits
+	LastActiveAt time.Time
+}
+
+// An ExpertiseReviewer is a pull request reviewer (identified by their code host account) and the
+// pull requests they reviewed.
+type ExpertiseReviewer struct {
+	AccountID    string
+	Login        string // the login of their most recent review
+	UserID       int32  // the user with the code host account, or 0 if there is none
+	UserDeleted  bool   // whether that user was deleted
+	PullRequests int
+	Score        float64 // the pull requests weighted by their age
+	LastActiveAt time.Time
+}
+
+// codeExpertise provides access to the `code_expertise_repos` and `code_expertise_lines` tables.
+type codeExpertise struct{}
+
+// GetRepoCommit returns the indexed commit of the repository, or the empty string if it hasn't
+// been indexed.
+func (*codeExpertise) GetRepoCommit(ctx context.Context, repoID api.RepoID) (api.CommitID, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeExpertise", "GetRepoCommit")
+	var commitID api.CommitID
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT commit_id FROM code_expertise_repos WHERE repo_id=$1", repoID).Scan(&commitID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return commitID, err
+}
+
+// UpdateRepo applies the update to the index of the repository.
+func (*codeExpertise) UpdateRepo(ctx context.Context, repoID api.RepoID, u *CodeExpertiseUpdate) error {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeExpertise", "UpdateRepo")
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if u.Reset {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM code_expertise_lines WHERE repo_id=$1", repoID); err != nil {
+				return err
+			}
+		}
+		if !u.Before.IsZero() {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM code_expertise_lines WHERE repo_id=$1 AND authored_at < $2", repoID, u.Before); err != nil {
+				return err
+			}
+		}
+
+		var (
+			commitIDs, dirs, names, emails []string
+			authoredAts                    []string
+			lines                          []int64
+		)
+		for _, c := range u.Commits {
+			for dir, n := range c.Lines {
+				commitIDs = append(commitIDs, string(c.ID))
+				dirs = append(dirs, dir)
+				names = append(names, c.AuthorName)
+				emails = append(emails, strings.ToLower(c.AuthorEmail))
+				authoredAts = append(authoredAts, c.AuthoredAt.UTC().Format(time.RFC3339))
+				lines = append(lines, int64(n))
+			}
+		}
+		if len(commitIDs) > 0 {
+			if _, err := tx.ExecContext(ctx, `
+INSERT INTO code_expertise_lines(repo_id, commit_id, dir, author_name, author_email, authored_at, lines)
+SELECT $1, unnest($2::text[]), unnest($3::text[]), unnest($4::text[]), unnest($5::text[]), unnest($6::timestamptz[]), unnest($7::integer[])
+ON CONFLICT DO NOTHING`,
+				repoID, pq.Array(commitIDs), pq.Array(dirs), pq.Array(names), pq.Array(emails), pq.Array(authoredAts), pq.Array(lines),
+			); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `
+INSERT INTO code_expertise_repos(repo_id, commit_id) VALUES($1, $2)
+ON CONFLICT (repo_id) DO UPDATE SET commit_id=EXCLUDED.commit_id, updated_at=now()`,
+			repoID, u.CommitID,
+		)
+		return err
+	})
+}
+
+// ListDirs lists the indexed directories of the repository that are dir or below it.
+func (*codeExpertise) ListDirs(ctx context.Context, repoID api.RepoID, dir string) ([]string, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeExpertise", "ListDirs")
+	if Mocks.CodeExpertise.ListDirs != nil {
+		return Mocks.CodeExpertise.ListDirs(ctx, repoID, dir)
+	}
+
+	q := sqlf.Sprintf("SELECT DISTINCT dir FROM code_expertise_lines l WHERE l.repo_id=%s AND %s ORDER BY dir", repoID, expertiseDirCond(dir, nil))
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dirs []string
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs, rows.Err()
+}
+
+// ListAuthors lists the authors of the indexed commits of the repository that added lines to the
+// directory, along with the Sourcegraph users with their emails.
+//
+// 🚨 SECURITY: The caller must ensure that the actor may view the counted directories.
+func (*codeExpertise) ListAuthors(ctx context.Context, repoID api.RepoID, opt ExpertiseListOptions) ([]*ExpertiseAuthor, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeExpertise", "ListAuthors")
+	if Mocks.CodeExpertise.ListAuthors != nil {
+		return Mocks.CodeExpertise.ListAuthors(ctx, repoID, opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT
+	l.author_email,
+	(array_agg(l.author_name ORDER BY l.authored_at DESC))[1],
+	COALESCE(u.id, 0),
+	SUM(l.lines),
+	SUM(l.lines * power(0.5, EXTRACT(EPOCH FROM now() - l.authored_at) / %s)),
+	MAX(l.authored_at)
+FROM code_expertise_lines l
+LEFT JOIN user_emails ue ON ue.email=l.author_email AND ue.verified_at IS NOT NULL
+LEFT JOIN users u ON u.id=ue.user_id AND u.deleted_at IS NULL
+WHERE l.repo_id=%s AND %s
+GROUP BY l.author_email, u.id`,
+		opt.HalfLife.Seconds(), repoID, expertiseDirCond(opt.Dir, opt.Dirs),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var authors []*ExpertiseAuthor
+	for rows.Next() {
+		var a ExpertiseAuthor
+		if err := rows.Scan(&a.Email, &a.Name, &a.UserID, &a.Lines, &a.Score, &a.LastActiveAt); err != nil {
+			return nil, err
+		}
+		authors = append(authors, &a)
+	}
+	return authors, rows.Err()
+}
+
+// ListReviewers lists the reviewers of the merged pull requests that introduced the indexed
+// commits of the repository that added lines to the directory, along with the Sourcegraph users
+// with their code host accounts. Each pull request counts once per reviewer.
+//
+// 🚨 SECURITY: The caller must ensure that the actor may view the counted directories.
+func (*codeExpertise) ListReviewers(ctx context.Context, repoID api.RepoID, opt ExpertiseListOptions) ([]*ExpertiseReviewer, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CodeExpertise", "ListReviewers")
+	if Mocks.CodeExpertise.ListReviewers != nil {
+		return Mocks.CodeExpertise.ListReviewers(ctx, repoID, opt)
+	}
+
+	q := sqlf.Sprintf(`
+WITH prs AS (
+	SELECT DISTINCT pr.id, pr.merged_at
+	FROM code_expertise_lines l
+	JOIN repo_pull_request_commits prc ON prc.repo_id=l.repo_id AND prc.commit_id=l.commit_id
+	JOIN repo_pull_requests pr ON pr.id=prc.pull_request_id
+	WHERE l.repo_id=%s AND %s
+)
+SELECT
+	rv.account_id,
+	(array_agg(rv.login ORDER BY prs.merged_at DESC))[1],
+	COALESCE(u.id, 0),
+	COALESCE(u.deleted_at IS NOT NULL, false),
+	COUNT(*),
+	SUM(power(0.5, EXTRACT(EPOCH FROM now() - prs.merged_at) / %s)),
+	MAX(prs.merged_at)
+FROM prs
+JOIN repo_pull_request_reviewers rv ON rv.pull_request_id=prs.id
+JOIN repo r ON r.id=%s
+LEFT JOIN LATERAL (
+	SELECT user_id FROM user_external_accounts
+	WHERE service_type=r.external_service_type AND service_id=r.external_service_id AND account_id=rv.account_id
+	ORDER BY deleted_at IS NOT NULL, id DESC
+	LIMIT 1
+) ea ON true
+LEFT JOIN users u ON u.id=ea.user_id
+GROUP BY rv.account_id, u.id`,
+		repoID, expertiseDirCond(opt.Dir, opt.Dirs), opt.HalfLife.Seconds(), repoID,
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviewers []*ExpertiseReviewer
+	for rows.Next() {
+		var r ExpertiseReviewer
+		if err := rows.Scan(&r.AccountID, &r.Login, &r.UserID, &r.UserDeleted, &r.PullRequests, &r.Score, &r.LastActiveAt); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, &r)
+	}
+	return reviewers, rows.Err()
+}
+
+// expertiseDirCond returns the condition on the code_expertise_lines row l that it is in one of
+// dirs (if non-nil), or else in dir or below it.
+func expertiseDirCond(dir string, dirs []string) *sqlf.Query {
+	if dirs != nil {
+		return sqlf.Sprintf("l.dir = ANY(%s)", pq.Array(dirs))
+	}
+	if dir == "" {
+		return sqlf.Sprintf("TRUE")
+	}
+	likeEscaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return sqlf.Sprintf("(l.dir=%s OR l.dir LIKE %s)", dir, likeEscaper.Replace(dir)+"/%")
+}
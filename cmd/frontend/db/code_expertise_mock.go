@@ -0,0 +1,13 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type MockCodeExpertise struct {
+	ListDirs      func(ctx context.Context, repoID api.RepoID, dir string) ([]string, error)
+	ListAuthors   func(ctx context.Context, repoID api.RepoID, opt ExpertiseListOptions) ([]*ExpertiseAuthor, error)
+	ListReviewers func(ctx context.Context, repoID api.RepoID, opt ExpertiseListOptions) ([]*ExpertiseReviewer, error)
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestCodeExpertise(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{
+		Name:         "github.com/acme/a",
+		Enabled:      true,
+		ExternalRepo: api.ExternalRepoSpec{ID: "a", ServiceType: "github", ServiceID: "https://github.com/"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "github.com/acme/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := Users.Create(ctx, NewUser{Username: "alice", Email: "alice@example.com", EmailIsVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := Users.Create(ctx, NewUser{Username: "bob", Email: "bob@example.com", EmailIsVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if commitID, err := CodeExpertise.GetRepoCommit(ctx, repo.ID); err != nil || commitID != "" {
+		t.Fatalf("got commit %q (err %v), want none", commitID, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := CodeExpertise.UpdateRepo(ctx, repo.ID, &CodeExpertiseUpdate{
+		CommitID: "c3",
+		Reset:    true,
+		Commits: []*ExpertiseCommit{
+			{ID: "c1", AuthorName: "Alice", AuthorEmail: "Alice@example.com", AuthoredAt: now.Add(-2 * time.Hour), Lines: map[string]int{"": 10, "pkg/billing": 100}},
+			{ID: "c2", AuthorName: "Alice A.", AuthorEmail: "alice@example.com", AuthoredAt: now.Add(-time.Hour), Lines: map[string]int{"pkg/billing/api": 20}},
+			{ID: "c3", AuthorName: "Carol", AuthorEmail: "carol@example.com", AuthoredAt: now, Lines: map[string]int{"pkg/billing_old": 5, "pkg/billing": 3}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if commitID, err := CodeExpertise.GetRepoCommit(ctx, repo.ID); err != nil || commitID != "c3" {
+		t.Fatalf("got commit %q (err %v), want c3", commitID, err)
+	}
+
+	dirs, err := CodeExpertise.ListDirs(ctx, repo.ID, "pkg/billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"pkg/billing", "pkg/billing/api"}; !reflect.DeepEqual(dirs, want) {
+		t.Errorf("got dirs %q, want %q", dirs, want)
+	}
+
+	opt := ExpertiseListOptions{Dir: "pkg/billing", HalfLife: time.Hour}
+	authors, err := CodeExpertise.ListAuthors(ctx, repo.ID, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].Email < authors[j].Email })
+	if len(authors) != 2 {
+		t.Fatalf("got %d authors, want 2", len(authors))
+	}
+	if a := authors[0]; a.Email != "alice@example.com" || a.Name != "Alice A." || a.UserID != alice.ID || a.Lines != 120 || !a.LastActiveAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("got author %+v", a)
+	} else if want := 100.0/4 + 20.0/2; math.Abs(a.Score-want) > 0.5 {
+		t.Errorf("got score %f, want about %f", a.Score, want)
+	}
+	if a := authors[1]; a.Email != "carol@example.com" || a.UserID != 0 || a.Lines != 3 {
+		t.Errorf("got author %+v", a)
+	}
+
+	// Only the lines in the given directories are counted.
+	opt.Dirs = []string{"pkg/billing/api"}
+	if authors, err := CodeExpertise.ListAuthors(ctx, repo.ID, opt); err != nil {
+		t.Fatal(err)
+	} else if len(authors) != 1 || authors[0].Lines != 20 {
+		t.Errorf("got authors %+v, want only Alice's lines in pkg/billing/api", authors)
+	}
+	opt.Dirs = nil
+
+	// Bob reviewed a pull request that introduced c1 and c2, and an unknown user reviewed one that
+	// introduced c3.
+	for _, q := range []string{
+		`INSERT INTO repo_pull_requests(id, repo_id, number, title, url, merged_at, updated_at) VALUES (1, $1, 1, 'a', 'u', now() - interval '1 hour', now()), (2, $1, 2, 'b', 'u', now(), now())`,
+		`INSERT INTO repo_pull_request_commits(pull_request_id, repo_id, commit_id) VALUES (1, $1, 'c1'), (1, $1, 'c2'), (2, $1, 'c3')`,
+		`INSERT INTO repo_pull_request_reviewers(pull_request_id, account_id, login) VALUES (1, '7', 'bob'), (2, '8', 'dan')`,
+	} {
+		if _, err := dbconn.Global.ExecContext(ctx, q, repo.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, `INSERT INTO user_external_accounts(user_id, service_type, service_id, account_id, client_id) VALUES ($1, 'github', 'https://github.com/', '7', 'x')`, bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	reviewers, err := CodeExpertise.ListReviewers(ctx, repo.ID, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(reviewers, func(i, j int) bool { return reviewers[i].AccountID < reviewers[j].AccountID })
+	if len(reviewers) != 2 {
+		t.Fatalf("got %d reviewers, want 2", len(reviewers))
+	}
+	if r := reviewers[0]; r.Login != "bob" || r.UserID != bob.ID || r.UserDeleted || r.PullRequests != 1 || math.Abs(r.Score-0.5) > 0.01 {
+		t.Errorf("got reviewer %+v", r)
+	}
+	if r := reviewers[1]; r.Login != "dan" || r.UserID != 0 || r.PullRequests != 1 {
+		t.Errorf("got reviewer %+v", r)
+	}
+
+	// Deleted users are reported as such.
+	if err := Users.Delete(ctx, bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if reviewers, err := CodeExpertise.ListReviewers(ctx, repo.ID, opt); err != nil {
+		t.Fatal(err)
+	} else if len(reviewers) != 2 || !(reviewers[0].UserDeleted || reviewers[1].UserDeleted) {
+		t.Errorf("got reviewers %+v, want Bob to be deleted", reviewers)
+	}
+
+	// Old commits are removed.
+	if err := CodeExpertise.UpdateRepo(ctx, repo.ID, &CodeExpertiseUpdate{CommitID: "c4", Before: now.Add(-90 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if dirs, err := CodeExpertise.ListDirs(ctx, repo.ID, ""); err != nil {
+		t.Fatal(err)
+	} else if want := []string{"pkg/billing", "pkg/billing/api", "pkg/billing_old"}; !reflect.DeepEqual(dirs, want) {
+		t.Errorf("got dirs %q, want %q", dirs, want)
+	}
+}
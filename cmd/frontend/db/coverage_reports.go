@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/coverage"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A CoverageReport is the code coverage of a commit of a repository, merged from all of the coverage
+// reports uploaded for the commit.
+type CoverageReport struct {
+	ID         int64
+	RepoID     api.RepoID
+	CommitID   api.CommitID
+	LinesFound int32 // the number of instrumented lines
+	LinesHit   int32 // the number of instrumented lines that were executed at least once
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// A CoverageFile is the per-line coverage of a file of a coverage report.
+type CoverageFile struct {
+	ReportID   int64
+	Path       string
+	Format     coverage.Format
+	Lines      []coverage.Line
+	LinesFound int32
+	LinesHit   int32
+}
+
+// coverageReports provides access to the `coverage_reports` and `coverage_files` tables.
+type coverageReports struct{}
+
+// CoverageReportNotFoundError occurs when a coverage report (or a file of it) is not found.
+type CoverageReportNotFoundError struct {
+	args []interface{}
+}
+
+// NotFound implements errcode.NotFounder.
+func (err CoverageReportNotFoundError) NotFound() bool { return true }
+
+func (err CoverageReportNotFoundError) Error() string {
+	return fmt.Sprintf("coverage report not found: %v", err.args)
+}
+
+// Upload adds the files of an uploaded coverage report to the coverage report of the commit
+// (creating it if needed), and returns the updated report. Files that were already uploaded for the
+// commit are replaced.
+//
+// 🚨 SECURITY: The caller must ensure that the uploader may upload coverage reports for the
+// repository.
+func (s *coverageReports) Upload(ctx context.Context, repoID api.RepoID, commitID api.CommitID, format coverage.Format, files []*coverage.File) (*CoverageReport, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CoverageReports", "Upload")
+	if Mocks.CoverageReports.Upload != nil {
+		return Mocks.CoverageReports.Upload(ctx, repoID, commitID, format, files)
+	}
+
+	var r CoverageReport
+	err := dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, `
+INSERT INTO coverage_reports(repo_id, commit_id) VALUES($1, $2)
+ON CONFLICT (repo_id, commit_id) DO UPDATE SET updated_at=now()
+RETURNING id`, repoID, commitID).Scan(&r.ID); err != nil {
+			return err
+		}
+		for _, f := range files {
+			if _, err := tx.ExecContext(ctx, `
+INSERT INTO coverage_files(report_id, path, format, lines, lines_found, lines_hit) VALUES($1, $2, $3, $4, $5, $6)
+ON CONFLICT (report_id, path) DO UPDATE SET format=EXCLUDED.format, lines=EXCLUDED.lines, lines_found=EXCLUDED.lines_found, lines_hit=EXCLUDED.lines_hit`,
+				r.ID, f.Path, format, coverage.EncodeLines(f.Lines), len(f.Lines), f.LinesHit(),
+			); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx, `
+UPDATE coverage_reports SET
+	lines_found=(SELECT COALESCE(SUM(lines_found), 0) FROM coverage_files WHERE report_id=$1),
+	lines_hit=(SELECT COALESCE(SUM(lines_hit), 0) FROM coverage_files WHERE report_id=$1)
+WHERE id=$1`, r.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByCommit(ctx, repoID, commitID)
+}
+
+// GetByCommit retrieves the coverage report (if any) of the commit.
+func (s *coverageReports) GetByCommit(ctx context.Context, repoID api.RepoID, commitID api.CommitID) (*CoverageReport, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CoverageReports", "GetByCommit")
+	if Mocks.CoverageReports.GetByCommit != nil {
+		return Mocks.CoverageReports.GetByCommit(ctx, repoID, commitID)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("repo_id=%d", repoID), sqlf.Sprintf("commit_id=%s", commitID)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, CoverageReportNotFoundError{[]interface{}{repoID, commitID}}
+	}
+	return results[0], nil
+}
+
+// CoverageReportsListOptions contains options for listing coverage reports.
+type CoverageReportsListOptions struct {
+	RepoID    api.RepoID     // only list reports of this repository (required)
+	CommitIDs []api.CommitID // only list reports of these commits (if set)
+	*LimitOffset
+}
+
+func (o CoverageReportsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("repo_id=%d", o.RepoID)}
+	if o.CommitIDs != nil {
+		ids := make([]string, len(o.CommitIDs))
+		for i, id := range o.CommitIDs {
+			ids[i] = string(id)
+		}
+		conds = append(conds, sqlf.Sprintf("commit_id = ANY(%s)", pq.Array(ids)))
+	}
+	return conds
+}
+
+// List lists the coverage reports that satisfy the options, most recently created first.
+func (s *coverageReports) List(ctx context.Context, opt CoverageReportsListOptions) ([]*CoverageReport, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CoverageReports", "List")
+	if Mocks.CoverageReports.List != nil {
+		return Mocks.CoverageReports.List(ctx, opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (*coverageReports) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*CoverageReport, error) {
+	q := sqlf.Sprintf(`
+SELECT id, repo_id, commit_id, lines_found, lines_hit, created_at, updated_at FROM coverage_reports
+WHERE (%s)
+ORDER BY created_at DESC, id DESC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*CoverageReport
+	for rows.Next() {
+		var r CoverageReport
+		if err := rows.Scan(&r.ID, &r.RepoID, &r.CommitID, &r.LinesFound, &r.LinesHit, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &r)
+	}
+	return results, rows.Err()
+}
+
+// GetFile retrieves the per-line coverage of a file of the coverage report.
+func (*coverageReports) GetFile(ctx context.Context, reportID int64, path string) (*CoverageFile, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "CoverageReports", "GetFile")
+	if Mocks.CoverageReports.GetFile != nil {
+		return Mocks.CoverageReports.GetFile(ctx, reportID, path)
+	}
+
+	f := CoverageFile{ReportID: reportID, Path: path}
+	var lines []byte
+	if err := dbconn.Global.QueryRowContext(ctx, "SELECT format, lines, lines_found, lines_hit FROM coverage_files WHERE report_id=$1 AND path=$2", reportID, path).Scan(&f.Format, &lines, &f.LinesFound, &f.LinesHit); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, CoverageReportNotFoundError{[]interface{}{reportID, path}}
+		}
+		return nil, err
+	}
+	var err error
+	if f.Lines, err = coverage.DecodeLines(lines); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
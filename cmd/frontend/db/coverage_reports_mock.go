@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/coverage"
+)
+
+type MockCoverageReports struct {
+	Upload      func(ctx context.Context, repoID api.RepoID, commitID api.CommitID, format coverage.Format, files []*coverage.File) (*CoverageReport, error)
+	GetByCommit func(ctx context.Context, repoID api.RepoID, commitID api.CommitID) (*CoverageReport, error)
+	List        func(ctx context.Context, opt CoverageReportsListOptions) ([]*CoverageReport, error)
+	GetFile     func(ctx context.Context, reportID int64, path string) (*CoverageFile, error)
+}
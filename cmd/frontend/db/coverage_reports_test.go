@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/coverage"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestCoverageReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "github.com/acme/a", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "github.com/acme/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitID := api.CommitID("0123456789012345678901234567890123456789")
+
+	// Uploads for the same commit are merged, and re-uploaded files are replaced.
+	if _, err := CoverageReports.Upload(ctx, repo.ID, commitID, coverage.GoProfile, []*coverage.File{
+		{Path: "a.go", Lines: []coverage.Line{{Number: 1, Hits: 1}, {Number: 2, Hits: 0}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	report, err := CoverageReports.Upload(ctx, repo.ID, commitID, coverage.LCOV, []*coverage.File{
+		{Path: "b.ts", Lines: []coverage.Line{{Number: 1, Hits: 0}, {Number: 5, Hits: 3}, {Number: 9, Hits: 0}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.LinesFound != 5 || report.LinesHit != 2 {
+		t.Errorf("got %d/%d lines hit, want 2/5", report.LinesHit, report.LinesFound)
+	}
+
+	t.Run("GetFile", func(t *testing.T) {
+		f, err := CoverageReports.GetFile(ctx, report.ID, "b.ts")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &CoverageFile{ReportID: report.ID, Path: "b.ts", Format: coverage.LCOV, Lines: []coverage.Line{{Number: 1, Hits: 0}, {Number: 5, Hits: 3}, {Number: 9, Hits: 0}}, LinesFound: 3, LinesHit: 1}
+		if !reflect.DeepEqual(f, want) {
+			t.Errorf("got %+v, want %+v", f, want)
+		}
+		if _, err := CoverageReports.GetFile(ctx, report.ID, "c.go"); !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		reports, err := CoverageReports.List(ctx, CoverageReportsListOptions{RepoID: repo.ID, CommitIDs: []api.CommitID{commitID, "x"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reports) != 1 || reports[0].ID != report.ID {
+			t.Errorf("got %+v, want [%+v]", reports, report)
+		}
+	})
+}
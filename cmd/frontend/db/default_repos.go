@@ -12,6 +12,7 @@ import (
 type defaultRepos struct{}
 
 func (s *defaultRepos) List(ctx context.Context) (results []*types.Repo, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DefaultRepos", "List")
 	const q = `
 SELECT default_repos.repo_id, repo.name
 FROM default_repos
@@ -33,6 +33,7 @@ func (e *ErrCommentNotFound) Error() string {
 }
 
 func (c *discussionComments) Create(ctx context.Context, newComment *types.DiscussionComment) (*types.DiscussionComment, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionComments", "Create")
 	if Mocks.DiscussionComments.Create != nil {
 		return Mocks.DiscussionComments.Create(ctx, newComment)
 	}
@@ -103,6 +104,7 @@ type DiscussionCommentsUpdateOptions struct {
 }
 
 func (c *discussionComments) Update(ctx context.Context, commentID int64, opts *DiscussionCommentsUpdateOptions) (*types.DiscussionComment, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionComments", "Update")
 	if Mocks.DiscussionComments.Update != nil {
 		return Mocks.DiscussionComments.Update(ctx, commentID, opts)
 	}
@@ -203,6 +205,7 @@ type DiscussionCommentsListOptions struct {
 }
 
 func (c *discussionComments) List(ctx context.Context, opts *DiscussionCommentsListOptions) ([]*types.DiscussionComment, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionComments", "List")
 	if Mocks.DiscussionComments.List != nil {
 		return Mocks.DiscussionComments.List(ctx, opts)
 	}
@@ -215,6 +218,7 @@ func (c *discussionComments) List(ctx context.Context, opts *DiscussionCommentsL
 }
 
 func (c *discussionComments) Get(ctx context.Context, commentID int64) (*types.DiscussionComment, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionComments", "Get")
 	if Mocks.DiscussionComments.Get != nil {
 		return Mocks.DiscussionComments.Get(commentID)
 	}
@@ -232,6 +236,7 @@ func (c *discussionComments) Get(ctx context.Context, commentID int64) (*types.D
 }
 
 func (c *discussionComments) Count(ctx context.Context, opts *DiscussionCommentsListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionComments", "Count")
 	if Mocks.DiscussionComments.Count != nil {
 		return Mocks.DiscussionComments.Count(ctx, opts)
 	}
@@ -24,6 +24,7 @@ type discussionMailReplyTokens struct{}
 // is passed to this method. Anyone with the token has access to reply to the
 // specified thread as the specified user, at ANY point in the future.
 func (*discussionMailReplyTokens) Generate(ctx context.Context, userID int32, threadID int64) (string, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionMailReplyTokens", "Generate")
 	if Mocks.DiscussionMailReplyTokens.Generate != nil {
 		return Mocks.DiscussionMailReplyTokens.Generate(ctx, userID, threadID)
 	}
@@ -63,6 +64,7 @@ var ErrInvalidToken = errors.New("invalid token")
 // Get returns the user and thread ID found for the given token. If there
 // is none, the token is invalid and ErrInvalidToken is returned.
 func (*discussionMailReplyTokens) Get(ctx context.Context, token string) (userID int32, threadID int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionMailReplyTokens", "Get")
 	if Mocks.DiscussionMailReplyTokens.Get != nil {
 		return Mocks.DiscussionMailReplyTokens.Get(ctx, token)
 	}
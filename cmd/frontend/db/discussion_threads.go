@@ -41,6 +41,7 @@ func (e *ErrThreadNotFound) Error() string {
 }
 
 func (t *discussionThreads) Create(ctx context.Context, newThread *types.DiscussionThread) (*types.DiscussionThread, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionThreads", "Create")
 	if Mocks.DiscussionThreads.Create != nil {
 		return Mocks.DiscussionThreads.Create(ctx, newThread)
 	}
@@ -127,6 +128,7 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 }
 
 func (t *discussionThreads) Get(ctx context.Context, threadID int64) (*types.DiscussionThread, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionThreads", "Get")
 	if Mocks.DiscussionThreads.Get != nil {
 		return Mocks.DiscussionThreads.Get(threadID)
 	}
@@ -156,6 +158,7 @@ type DiscussionThreadsUpdateOptions struct {
 }
 
 func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *DiscussionThreadsUpdateOptions) (*types.DiscussionThread, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionThreads", "Update")
 	if Mocks.DiscussionThreads.Update != nil {
 		return Mocks.DiscussionThreads.Update(ctx, threadID, opts)
 	}
@@ -449,6 +452,7 @@ func (opts *DiscussionThreadsListOptions) SetFromQuery(ctx context.Context, quer
 }
 
 func (t *discussionThreads) List(ctx context.Context, opts *DiscussionThreadsListOptions) ([]*types.DiscussionThread, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionThreads", "List")
 	if Mocks.DiscussionThreads.List != nil {
 		return Mocks.DiscussionThreads.List(ctx, opts)
 	}
@@ -470,6 +474,7 @@ func (t *discussionThreads) List(ctx context.Context, opts *DiscussionThreadsLis
 }
 
 func (t *discussionThreads) Count(ctx context.Context, opts *DiscussionThreadsListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "DiscussionThreads", "Count")
 	if Mocks.DiscussionThreads.Count != nil {
 		return Mocks.DiscussionThreads.Count(ctx, opts)
 	}
@@ -33,6 +33,7 @@ type Event struct {
 }
 
 func (*eventLogs) Insert(ctx context.Context, e *Event) error {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "Insert")
 	argument := e.Argument
 	if argument == nil {
 		argument = json.RawMessage([]byte(`{}`))
@@ -80,26 +81,31 @@ func (*eventLogs) getBySQL(ctx context.Context, querySuffix *sqlf.Query) ([]*typ
 
 // GetAll gets all event logs in descending order of timestamp.
 func (l *eventLogs) GetAll(ctx context.Context) ([]*types.Event, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "GetAll")
 	return l.getBySQL(ctx, sqlf.Sprintf("ORDER BY timestamp DESC"))
 }
 
 // GetByUserID gets all event logs by a given user in descending order of timestamp.
 func (l *eventLogs) GetByUserID(ctx context.Context, userID int32) ([]*types.Event, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "GetByUserID")
 	return l.getBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d ORDER BY timestamp DESC", userID))
 }
 
 // CountByUserIDAndEventName gets a count of events logged by a given user and with a given event name.
 func (l *eventLogs) CountByUserIDAndEventName(ctx context.Context, userID int32, name string) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountByUserIDAndEventName")
 	return l.countBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d AND name = %s", userID, name))
 }
 
 // CountByUserIDAndEventNamePrefix gets a count of events logged by a given user and with a given event name prefix.
 func (l *eventLogs) CountByUserIDAndEventNamePrefix(ctx context.Context, userID int32, namePrefix string) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountByUserIDAndEventNamePrefix")
 	return l.countBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d AND name LIKE %s", userID, namePrefix+"%"))
 }
 
 // CountByUserIDAndEventNames gets a count of events logged by a given user that match a list of given event names.
 func (l *eventLogs) CountByUserIDAndEventNames(ctx context.Context, userID int32, names []string) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountByUserIDAndEventNames")
 	items := []*sqlf.Query{}
 	for _, v := range names {
 		items = append(items, sqlf.Sprintf("%s", v))
@@ -118,11 +124,13 @@ func (*eventLogs) countBySQL(ctx context.Context, querySuffix *sqlf.Query) (int,
 
 // MaxTimestampByUserID gets the max timestamp among event logs for a given user.
 func (l *eventLogs) MaxTimestampByUserID(ctx context.Context, userID int32) (*time.Time, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "MaxTimestampByUserID")
 	return l.maxTimestampBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d", userID))
 }
 
 // MaxTimestampByUserIDAndSource gets the max timestamp among event logs for a given user and event source.
 func (l *eventLogs) MaxTimestampByUserIDAndSource(ctx context.Context, userID int32, source string) (*time.Time, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "MaxTimestampByUserIDAndSource")
 	return l.maxTimestampBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d AND source = %s", userID, source))
 }
 
@@ -230,6 +238,7 @@ type EventFilterOptions struct {
 // a given type. The value of `now` should be the current time in UTC. Returns an array array of length `periods`,
 // with one entry for each period in the time span.
 func (l *eventLogs) CountUniqueUsersPerPeriod(ctx context.Context, periodType PeriodType, now time.Time, periods int, opt *CountUniqueUsersOptions) ([]UsageValue, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountUniqueUsersPerPeriod")
 	startDate, ok := calcStartDate(now, periodType, periods)
 	if !ok {
 		return nil, fmt.Errorf("periodType must be \"daily\", \"weekly\", or \"monthly\". Got %s", periodType)
@@ -243,7 +252,7 @@ func (l *eventLogs) CountUniqueUsersPerPeriod(ctx context.Context, periodType Pe
 	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
 	if opt != nil {
 		if opt.RegisteredOnly {
-			conds = append(conds, sqlf.Sprintf("user_id > 0"))
+			conds = append(conds, sqlf.Sprintf("registered"))
 		}
 		if opt.IntegrationOnly {
 			conds = append(conds, sqlf.Sprintf("source = %s", integrationSource))
@@ -265,12 +274,13 @@ func (l *eventLogs) CountUniqueUsersPerPeriod(ctx context.Context, periodType Pe
 		}
 	}
 
-	return l.countUniqueUsersPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, conds)
+	return l.countUniqueUsersPerPeriodBySQL(ctx, eventLogsUsersRelation(periodType, startDate), intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, conds)
 }
 
 // CountEventsPerPeriod provide a count of events in a given time span, broken up into periods of a given type.
 // The value of `now` should be the current time in UTC.
 func (l *eventLogs) CountEventsPerPeriod(ctx context.Context, periodType PeriodType, now time.Time, periods int, opt *EventFilterOptions) ([]UsageValue, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountEventsPerPeriod")
 	startDate, ok := calcStartDate(now, periodType, periods)
 	if !ok {
 		return nil, fmt.Errorf("periodType must be \"daily\", \"weekly\", or \"monthly\". Got %s", periodType)
@@ -298,7 +308,7 @@ func (l *eventLogs) CountEventsPerPeriod(ctx context.Context, periodType PeriodT
 		}
 	}
 
-	return l.countEventsPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, conds)
+	return l.countEventsPerPeriodBySQL(ctx, eventLogsUsersRelation(periodType, startDate), intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, conds)
 }
 
 // PercentileValue is a slice of Nth percentile values calculated from a field of events
@@ -353,23 +363,25 @@ func (l *eventLogs) PercentilesPerPeriod(
 		}
 	}
 
-	return l.calculatePercentilesPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, field, percentiles, conds)
+	return l.calculatePercentilesPerPeriodBySQL(ctx, eventLogsValuesRelation(periodType, startDate, field), intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, percentiles, conds)
 }
 
-func (l *eventLogs) countUniqueUsersPerPeriodBySQL(ctx context.Context, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
-	return l.countPerPeriodBySQL(ctx, sqlf.Sprintf("DISTINCT CASE WHEN user_id = 0 THEN anonymous_user_id ELSE CAST(user_id AS TEXT) END"), interval, period, startDate, endDate, conds)
+// countUniqueUsersPerPeriodBySQL and countEventsPerPeriodBySQL count over a relation returned by
+// eventLogsUsersRelation.
+func (l *eventLogs) countUniqueUsersPerPeriodBySQL(ctx context.Context, from, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
+	return l.countPerPeriodBySQL(ctx, sqlf.Sprintf("COUNT(DISTINCT user_key)"), from, interval, period, startDate, endDate, conds)
 }
 
-func (l *eventLogs) countEventsPerPeriodBySQL(ctx context.Context, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
-	return l.countPerPeriodBySQL(ctx, sqlf.Sprintf("*"), interval, period, startDate, endDate, conds)
+func (l *eventLogs) countEventsPerPeriodBySQL(ctx context.Context, from, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
+	return l.countPerPeriodBySQL(ctx, sqlf.Sprintf("SUM(count)"), from, interval, period, startDate, endDate, conds)
 }
 
-func (l *eventLogs) countPerPeriodBySQL(ctx context.Context, countExpr, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
+func (l *eventLogs) countPerPeriodBySQL(ctx context.Context, aggregateExpr, from, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
 	allPeriods := sqlf.Sprintf("SELECT generate_series((%s)::timestamp, (%s)::timestamp, (%s)::interval) AS period", startDate, endDate, interval)
-	countByPeriod := sqlf.Sprintf(`SELECT (%s) AS period, COUNT(%s) AS count
-		FROM event_logs
+	countByPeriod := sqlf.Sprintf(`SELECT (%s) AS period, %s AS count
+		FROM %s
 		WHERE (%s)
-		GROUP BY period`, period, countExpr, sqlf.Join(conds, ") AND ("))
+		GROUP BY period`, period, aggregateExpr, from, sqlf.Join(conds, ") AND ("))
 	q := sqlf.Sprintf(`WITH all_periods AS (%s), count_by_period AS (%s)
 		SELECT all_periods.period, COALESCE(count, 0)
 		FROM all_periods
@@ -397,13 +409,15 @@ func (l *eventLogs) countPerPeriodBySQL(ctx context.Context, countExpr, interval
 	return counts, nil
 }
 
+// calculatePercentilesPerPeriodBySQL calculates percentiles over a relation returned by
+// eventLogsValuesRelation.
 func (l *eventLogs) calculatePercentilesPerPeriodBySQL(
 	ctx context.Context,
+	from *sqlf.Query,
 	interval *sqlf.Query,
 	period *sqlf.Query,
 	startDate time.Time,
 	endDate time.Time,
-	field string,
 	percentiles []float64,
 	conds []*sqlf.Query,
 ) ([]PercentileValue, error) {
@@ -411,13 +425,9 @@ func (l *eventLogs) calculatePercentilesPerPeriodBySQL(
 	qExprs := []*sqlf.Query{}
 	for i, p := range percentiles {
 		name := fmt.Sprintf("p%d\n", i)
-		// Note: we can't go directly from jsonb -> integer in postgres 9.6, so we
-		// have to first cast it to a text type, and then to an integer to support
-		// queries on older instances.
 		countByPeriodExprs = append(countByPeriodExprs, sqlf.Sprintf(
-			"percentile_cont(%d) WITHIN GROUP (ORDER BY (argument->%s)::text::integer) AS "+name,
+			"percentile_cont(%d) WITHIN GROUP (ORDER BY value) AS "+name,
 			p,
-			field,
 		))
 
 		qExprs = append(qExprs, sqlf.Sprintf("COALESCE("+name+", 0)"))
@@ -425,9 +435,9 @@ func (l *eventLogs) calculatePercentilesPerPeriodBySQL(
 
 	allPeriods := sqlf.Sprintf("SELECT generate_series((%s)::timestamp, (%s)::timestamp, (%s)::interval) AS period", startDate, endDate, interval)
 	countByPeriod := sqlf.Sprintf(`SELECT (%s) AS period, %s
-		FROM event_logs
+		FROM %s
 		WHERE (%s)
-		GROUP BY period`, period, sqlf.Join(countByPeriodExprs, ", "), sqlf.Join(conds, ") AND ("))
+		GROUP BY period`, period, sqlf.Join(countByPeriodExprs, ", "), from, sqlf.Join(conds, ") AND ("))
 	q := sqlf.Sprintf(`WITH all_periods AS (%s), values_by_period AS (%s)
 		SELECT all_periods.period, %s
 		FROM all_periods
@@ -462,21 +472,25 @@ func (l *eventLogs) calculatePercentilesPerPeriodBySQL(
 
 // CountUniqueUsersAll provides a count of unique active users in a given time span.
 func (l *eventLogs) CountUniqueUsersAll(ctx context.Context, startDate, endDate time.Time) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountUniqueUsersAll")
 	return l.countUniqueUsersBySQL(ctx, startDate, endDate, nil)
 }
 
 // CountUniqueUsersByEventNamePrefix provides a count of unique active users in a given time span that logged an event with a given prefix.
 func (l *eventLogs) CountUniqueUsersByEventNamePrefix(ctx context.Context, startDate, endDate time.Time, namePrefix string) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountUniqueUsersByEventNamePrefix")
 	return l.countUniqueUsersBySQL(ctx, startDate, endDate, sqlf.Sprintf("AND name LIKE %s ", namePrefix+"%"))
 }
 
 // CountUniqueUsersByEventName provides a count of unique active users in a given time span that logged a given event.
 func (l *eventLogs) CountUniqueUsersByEventName(ctx context.Context, startDate, endDate time.Time, name string) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountUniqueUsersByEventName")
 	return l.countUniqueUsersBySQL(ctx, startDate, endDate, sqlf.Sprintf("AND name = %s", name))
 }
 
 // CountUniqueUsersByEventNames provides a count of unique active users in a given time span that logged any event that matches a list of given event names
 func (l *eventLogs) CountUniqueUsersByEventNames(ctx context.Context, startDate, endDate time.Time, names []string) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "CountUniqueUsersByEventNames")
 	items := []*sqlf.Query{}
 	for _, v := range names {
 		items = append(items, sqlf.Sprintf("%s", v))
@@ -488,9 +502,9 @@ func (*eventLogs) countUniqueUsersBySQL(ctx context.Context, startDate, endDate
 	if querySuffix == nil {
 		querySuffix = sqlf.Sprintf("")
 	}
-	q := sqlf.Sprintf(`SELECT COUNT(DISTINCT CASE WHEN user_id = 0 THEN anonymous_user_id ELSE CAST(user_id AS TEXT) END)
-		FROM event_logs
-		WHERE (DATE(TIMEZONE('UTC'::text, timestamp)) >= %s) AND (DATE(TIMEZONE('UTC'::text, timestamp)) <= %s) %s`, startDate, endDate, querySuffix)
+	q := sqlf.Sprintf(`SELECT COUNT(DISTINCT user_key)
+		FROM %s
+		WHERE (DATE(TIMEZONE('UTC'::text, timestamp)) >= %s) AND (DATE(TIMEZONE('UTC'::text, timestamp)) <= %s) %s`, eventLogsUsersRelation(Daily, startDate), startDate, endDate, querySuffix)
 	r := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	var count int
 	err := r.Scan(&count)
@@ -498,10 +512,12 @@ func (*eventLogs) countUniqueUsersBySQL(ctx context.Context, startDate, endDate
 }
 
 func (l *eventLogs) ListUniqueUsersAll(ctx context.Context, startDate, endDate time.Time) ([]int32, error) {
-	rows, err := dbconn.Global.QueryContext(ctx, `SELECT user_id
-		FROM event_logs
-		WHERE user_id > 0 AND DATE(TIMEZONE('UTC'::text, timestamp)) >= $1 AND DATE(TIMEZONE('UTC'::text, timestamp)) <= $2
-		GROUP BY user_id`, startDate, endDate)
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "ListUniqueUsersAll")
+	q := sqlf.Sprintf(`SELECT CAST(user_key AS INTEGER)
+		FROM %s
+		WHERE registered AND DATE(TIMEZONE('UTC'::text, timestamp)) >= %s AND DATE(TIMEZONE('UTC'::text, timestamp)) <= %s
+		GROUP BY user_key`, eventLogsUsersRelation(Daily, startDate), startDate, endDate)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// The usage statistics are computed from rollups of event_logs instead of the raw events, so that
+// computing them doesn't get slower as events accumulate and raw events can be deleted after the
+// retention period. The rollups record every (period, event name, source, user) with the number of
+// events, which is enough to compute exact unique user and event counts with any of the supported
+// filters. Daily rollups are used for daily and monthly statistics, and weekly rollups for weekly
+// statistics.
+//
+// The rollups contain exactly the events with an ID less than or equal to the rolled_up_id of
+// event_logs_rollup_state, and the queries combine them with the raw events with a greater ID. The
+// statistics are therefore identical to those computed from the raw events alone.
+
+// rolledUpValueFields are the argument fields whose values are rolled up for PercentilesPerPeriod.
+// Percentiles of other fields are computed from the raw events.
+var rolledUpValueFields = []string{"durationMs"}
+
+// eventLogsRollupBatchSize is the maximum number of events rolled up in a single transaction.
+const eventLogsRollupBatchSize = 100000
+
+// rollupsByPeriodType maps the period types that have their own rollup tables to the tables' name
+// prefix.
+var rollupsByPeriodType = map[PeriodType]string{
+	Daily:  "event_logs_daily",
+	Weekly: "event_logs_weekly",
+}
+
+// rollupTable returns the name prefix of the rollup tables used to compute statistics for periods of
+// the given type.
+func rollupTable(periodType PeriodType) string {
+	if periodType == Weekly {
+		return rollupsByPeriodType[Weekly]
+	}
+	return rollupsByPeriodType[Daily]
+}
+
+// Rollup aggregates all events that were logged since the last rollup into the rollup tables and
+// returns the number of events rolled up. It is safe to call concurrently.
+func (*eventLogs) Rollup(ctx context.Context) (int64, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "Rollup")
+	// Events are inserted with increasing IDs, but may be committed out of order. Waiting for the
+	// inserts in progress to finish guarantees that no event with an ID less than the maximum is
+	// committed later (and then never rolled up).
+	var maxID int64
+	if err := dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "LOCK TABLE event_logs IN SHARE MODE"); err != nil {
+			return err
+		}
+		return tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM event_logs").Scan(&maxID)
+	}); err != nil {
+		return 0, errors.Wrap(err, "reading max event ID")
+	}
+
+	var total int64
+	for {
+		var done bool
+		err := dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+			var from int64
+			if err := tx.QueryRowContext(ctx, "SELECT rolled_up_id FROM event_logs_rollup_state FOR UPDATE").Scan(&from); err != nil {
+				return err
+			}
+			if from >= maxID {
+				done = true
+				return nil
+			}
+			to := from + eventLogsRollupBatchSize
+			if to > maxID {
+				to = maxID
+			}
+
+			for periodType, table := range rollupsByPeriodType {
+				for _, q := range rollupQueries(table, periodByPeriodType[periodType], from, to) {
+					if _, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+						return err
+					}
+				}
+			}
+
+			var n int64
+			if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM event_logs WHERE id > $1 AND id <= $2", from, to).Scan(&n); err != nil {
+				return err
+			}
+			total += n
+			_, err := tx.ExecContext(ctx, "UPDATE event_logs_rollup_state SET rolled_up_id = $1", to)
+			return err
+		})
+		if err != nil {
+			return total, errors.Wrap(err, "rolling up events")
+		}
+		if done {
+			return total, nil
+		}
+	}
+}
+
+// rollupQueries returns the queries that add the events with an ID in (from, to] to the rollup
+// tables with the given name prefix.
+func rollupQueries(table string, period *sqlf.Query, from, to int64) []*sqlf.Query {
+	usersTable := sqlf.Sprintf(table + "_users")
+	valuesTable := sqlf.Sprintf(table + "_values")
+	return []*sqlf.Query{
+		sqlf.Sprintf(`INSERT INTO %s (period, name, source, user_key, registered, count)
+		SELECT (%s) AS period, name, source, CASE WHEN user_id = 0 THEN anonymous_user_id ELSE CAST(user_id AS TEXT) END, user_id > 0, COUNT(*)
+		FROM event_logs
+		WHERE id > %s AND id <= %s
+		GROUP BY 1, 2, 3, 4, 5
+		ON CONFLICT (period, name, source, user_key, registered) DO UPDATE SET count = %s.count + excluded.count`,
+			usersTable, period, from, to, usersTable),
+		sqlf.Sprintf(`INSERT INTO %s (period, name, source, field, value, count)
+		SELECT (%s) AS period, name, source, field, (argument->>field)::double precision, COUNT(*)
+		FROM event_logs, unnest(%s::text[]) AS field
+		WHERE id > %s AND id <= %s AND jsonb_typeof(argument->field) = 'number'
+		GROUP BY 1, 2, 3, 4, 5
+		ON CONFLICT (period, name, source, field, value) DO UPDATE SET count = %s.count + excluded.count`,
+			valuesTable, period, pq.Array(rolledUpValueFields), from, to, valuesTable),
+	}
+}
+
+// eventLogsUsersRelation returns a relation with the columns (timestamp, name, source, user_key,
+// registered, count) that contains the events since startDate, partially aggregated into the
+// rollup periods used for the period type.
+func eventLogsUsersRelation(periodType PeriodType, startDate time.Time) *sqlf.Query {
+	return sqlf.Sprintf(`(
+		SELECT period AS timestamp, name, source, user_key, registered, count
+		FROM %s
+		WHERE period >= %s
+		UNION ALL
+		SELECT timestamp, name, source, CASE WHEN user_id = 0 THEN anonymous_user_id ELSE CAST(user_id AS TEXT) END, user_id > 0, 1
+		FROM event_logs
+		WHERE timestamp >= %s AND id > (SELECT rolled_up_id FROM event_logs_rollup_state)
+	) AS events`, sqlf.Sprintf(rollupTable(periodType)+"_users"), startDate, startDate)
+}
+
+// eventLogsValuesRelation returns a relation with the columns (timestamp, name, source, value) that
+// contains a row for each event since startDate with the value of the given argument field.
+func eventLogsValuesRelation(periodType PeriodType, startDate time.Time, field string) *sqlf.Query {
+	rolledUp := false
+	for _, f := range rolledUpValueFields {
+		if f == field {
+			rolledUp = true
+		}
+	}
+	// Note: we can't go directly from jsonb -> integer in postgres 9.6, so we have to first cast
+	// it to a text type, and then to an integer to support queries on older instances.
+	if !rolledUp {
+		return sqlf.Sprintf(`(
+			SELECT timestamp, name, source, (argument->%s)::text::integer AS value
+			FROM event_logs
+			WHERE timestamp >= %s
+		) AS events`, field, startDate)
+	}
+	return sqlf.Sprintf(`(
+		SELECT period AS timestamp, name, source, value
+		FROM %s, generate_series(1, count)
+		WHERE period >= %s AND field = %s
+		UNION ALL
+		SELECT timestamp, name, source, (argument->%s)::text::integer
+		FROM event_logs
+		WHERE timestamp >= %s AND id > (SELECT rolled_up_id FROM event_logs_rollup_state)
+	) AS events`, sqlf.Sprintf(rollupTable(periodType)+"_values"), startDate, field, field, startDate)
+}
+
+// DeleteRolledUp deletes at most limit events that were logged before the given time and have been
+// rolled up, and returns the number of events deleted.
+func (*eventLogs) DeleteRolledUp(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "EventLogs", "DeleteRolledUp")
+	res, err := dbconn.Global.ExecContext(ctx, `DELETE FROM event_logs WHERE id IN (
+		SELECT id FROM event_logs
+		WHERE timestamp < $1 AND id <= (SELECT rolled_up_id FROM event_logs_rollup_state)
+		LIMIT $2
+	)`, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
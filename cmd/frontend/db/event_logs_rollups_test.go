@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestEventLogs_Rollup(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	startDate, _ := calcStartDate(now, Daily, 10)
+	insertEvents := func(n int) {
+		for i := 0; i < n; i++ {
+			e := makeTestEvent(&Event{
+				Argument:  json.RawMessage(fmt.Sprintf(`{"durationMs": %d}`, 10*(i%7))),
+				Timestamp: startDate.Add(time.Hour * 24 * time.Duration(i%10)),
+			})
+			e.UserID = uint32(i % 4)
+			if e.UserID == 0 {
+				e.AnonymousUserID = fmt.Sprintf("anon%d", i%3)
+			}
+			if i%5 == 0 {
+				e.Source = integrationSource
+			}
+			if err := EventLogs.Insert(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// stats computes all statistics, which must be the same regardless of which events have been
+	// rolled up.
+	stats := func() []interface{} {
+		var all []interface{}
+		for _, periodType := range []PeriodType{Daily, Weekly, Monthly} {
+			for _, opt := range []*CountUniqueUsersOptions{
+				nil,
+				{RegisteredOnly: true},
+				{IntegrationOnly: true},
+				{EventFilters: &EventFilterOptions{ByEventName: "foo"}},
+			} {
+				v, err := EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, now, 3, opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				all = append(all, v)
+			}
+			v, err := EventLogs.CountEventsPerPeriod(ctx, periodType, now, 3, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, v)
+			p, err := EventLogs.PercentilesPerPeriod(ctx, periodType, now, 3, "durationMs", []float64{0.5, 0.9}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, p)
+		}
+		n, err := EventLogs.CountUniqueUsersAll(ctx, startDate, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, err := EventLogs.ListUniqueUsersAll(ctx, startDate, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(all, n, ids)
+	}
+	rollup := func(want int64) {
+		if n, err := EventLogs.Rollup(ctx); err != nil {
+			t.Fatal(err)
+		} else if n != want {
+			t.Errorf("got %d rolled up events, want %d", n, want)
+		}
+	}
+
+	insertEvents(100)
+	raw := stats()
+	rollup(100)
+	if got := stats(); !reflect.DeepEqual(got, raw) {
+		t.Errorf("rolled up statistics differ from raw statistics\ngot  %+v\nwant %+v", got, raw)
+	}
+
+	// Statistics combine rollups with the events logged since the last rollup.
+	insertEvents(50)
+	partial := stats()
+	rollup(50)
+	if got := stats(); !reflect.DeepEqual(got, partial) {
+		t.Errorf("rolled up statistics differ from partially rolled up statistics\ngot  %+v\nwant %+v", got, partial)
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM event_logs_daily_users; DELETE FROM event_logs_weekly_users; DELETE FROM event_logs_daily_values; DELETE FROM event_logs_weekly_values; UPDATE event_logs_rollup_state SET rolled_up_id = 0"); err != nil {
+		t.Fatal(err)
+	}
+	if got := stats(); !reflect.DeepEqual(got, partial) {
+		t.Errorf("raw statistics differ from rolled up statistics\ngot  %+v\nwant %+v", got, partial)
+	}
+	rollup(150)
+
+	t.Run("DeleteRolledUp", func(t *testing.T) {
+		insertEvents(10)
+		before := now.Add(time.Hour * 24)
+		// The events that haven't been rolled up are never deleted.
+		var wantDeleted int64
+		if err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM event_logs WHERE timestamp < $1 AND id <= (SELECT rolled_up_id FROM event_logs_rollup_state)", before).Scan(&wantDeleted); err != nil {
+			t.Fatal(err)
+		}
+		var deleted int64
+		for {
+			n, err := EventLogs.DeleteRolledUp(ctx, before, 40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n == 0 {
+				break
+			}
+			deleted += n
+		}
+		if deleted != 150 || deleted != wantDeleted {
+			t.Errorf("got %d deleted events, want %d", deleted, wantDeleted)
+		}
+		var remaining int
+		if err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM event_logs").Scan(&remaining); err != nil {
+			t.Fatal(err)
+		}
+		if remaining != 10 {
+			t.Errorf("got %d remaining events, want 10", remaining)
+		}
+	})
+}
@@ -32,6 +32,7 @@ type userExternalAccounts struct{}
 
 // Get gets information about the user external account.
 func (s *userExternalAccounts) Get(ctx context.Context, id int32) (*extsvc.ExternalAccount, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "Get")
 	if Mocks.ExternalAccounts.Get != nil {
 		return Mocks.ExternalAccounts.Get(id)
 	}
@@ -45,6 +46,7 @@ func (s *userExternalAccounts) Get(ctx context.Context, id int32) (*extsvc.Exter
 // found, it updates the account's data and returns the user. It NEVER creates a user; you must call
 // CreateUserAndSave for that.
 func (s *userExternalAccounts) LookupUserAndSave(ctx context.Context, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) (userID int32, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "LookupUserAndSave")
 	if Mocks.ExternalAccounts.LookupUserAndSave != nil {
 		return Mocks.ExternalAccounts.LookupUserAndSave(spec, data)
 	}
@@ -69,6 +71,7 @@ RETURNING user_id
 // - the same user: it updates the data and returns a nil error; or
 // - a different user: it performs no update and returns a non-nil error
 func (s *userExternalAccounts) AssociateUserAndSave(ctx context.Context, userID int32, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) (err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "AssociateUserAndSave")
 	if Mocks.ExternalAccounts.AssociateUserAndSave != nil {
 		return Mocks.ExternalAccounts.AssociateUserAndSave(userID, spec, data)
 	}
@@ -137,6 +140,7 @@ WHERE service_type=$1 AND service_id=$2 AND client_id=$3 AND account_id=$4 AND u
 // It creates a new user and associates it with the specified external account. If the user to
 // create already exists, it returns an error.
 func (s *userExternalAccounts) CreateUserAndSave(ctx context.Context, newUser NewUser, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) (createdUserID int32, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "CreateUserAndSave")
 	if Mocks.ExternalAccounts.CreateUserAndSave != nil {
 		return Mocks.ExternalAccounts.CreateUserAndSave(newUser, spec, data)
 	}
@@ -176,6 +180,7 @@ VALUES($1, $2, $3, $4, $5, $6, $7)
 
 // Delete deletes a user external account.
 func (*userExternalAccounts) Delete(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "Delete")
 	if Mocks.ExternalAccounts.Delete != nil {
 		return Mocks.ExternalAccounts.Delete(id)
 	}
@@ -202,6 +207,7 @@ type ExternalAccountsListOptions struct {
 }
 
 func (s *userExternalAccounts) List(ctx context.Context, opt ExternalAccountsListOptions) (acct []*extsvc.ExternalAccount, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "List")
 	tr, ctx := trace.New(ctx, "userExternalAccounts.List", "")
 	defer func() {
 		if err != nil {
@@ -225,6 +231,7 @@ func (s *userExternalAccounts) List(ctx context.Context, opt ExternalAccountsLis
 }
 
 func (s *userExternalAccounts) Count(ctx context.Context, opt ExternalAccountsListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "Count")
 	if Mocks.ExternalAccounts.Count != nil {
 		return Mocks.ExternalAccounts.Count(opt)
 	}
@@ -239,6 +246,7 @@ func (s *userExternalAccounts) Count(ctx context.Context, opt ExternalAccountsLi
 // TmpMigrate implements the migration described in bg.MigrateExternalAccounts (which is the only
 // func that should call this).
 func (*userExternalAccounts) TmpMigrate(ctx context.Context, serviceType string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalAccounts", "TmpMigrate")
 	// TEMP: Delete all external accounts associated with deleted users. Due to a bug in this
 	// migration code, it was possible for deleted users to be associated with non-deleted external
 	// accounts. This caused unexpected behavior in the UI (although did not pose a security
@@ -41,7 +41,12 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	"GITHUB":          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	"GITLAB":          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	"GITOLITE":        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
+	"GOMODULES":       {CodeHost: true, JSONSchema: schema.GoModulesSchemaJSON},
+	"MAVEN":           {CodeHost: true, JSONSchema: schema.MavenSchemaJSON},
+	"NPM":             {CodeHost: true, JSONSchema: schema.NPMSchemaJSON},
+	"PERFORCE":        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
 	"PHABRICATOR":     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
+	"SVN":             {CodeHost: true, JSONSchema: schema.SVNSchemaJSON},
 	"OTHER":           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
 
@@ -214,6 +219,7 @@ func (e *ExternalServicesStore) validateBitbucketServerConnection(c *schema.Bitb
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) Create(ctx context.Context, confGet func() *conf.Unified, externalService *types.ExternalService) error {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "Create")
 	ps := confGet().AuthProviders
 	if err := c.ValidateConfig(externalService.Kind, externalService.Config, ps); err != nil {
 		return err
@@ -224,21 +230,23 @@ func (c *ExternalServicesStore) Create(ctx context.Context, confGet func() *conf
 
 	return dbconn.Global.QueryRowContext(
 		ctx,
-		"INSERT INTO external_services(kind, display_name, config, created_at, updated_at) VALUES($1, $2, $3, $4, $5) RETURNING id",
-		externalService.Kind, externalService.DisplayName, externalService.Config, externalService.CreatedAt, externalService.UpdatedAt,
+		"INSERT INTO external_services(kind, display_name, config, created_at, updated_at, namespace_org_id) VALUES($1, $2, $3, $4, $5, $6) RETURNING id",
+		externalService.Kind, externalService.DisplayName, externalService.Config, externalService.CreatedAt, externalService.UpdatedAt, nullInt32Column(externalService.NamespaceOrgID),
 	).Scan(&externalService.ID)
 }
 
 // ExternalServiceUpdate contains optional fields to update.
 type ExternalServiceUpdate struct {
-	DisplayName *string
-	Config      *string
+	DisplayName    *string
+	Config         *string
+	NamespaceOrgID *int32 // 0 removes the organization
 }
 
 // Update updates a external service.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) Update(ctx context.Context, ps []schema.AuthProviders, id int64, update *ExternalServiceUpdate) error {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "Update")
 	if update.Config != nil {
 		// Query to get the kind (which is immutable) so we can validate the new config.
 		externalService, err := c.GetByID(ctx, id)
@@ -277,6 +285,11 @@ func (c *ExternalServicesStore) Update(ctx context.Context, ps []schema.AuthProv
 				return err
 			}
 		}
+		if update.NamespaceOrgID != nil {
+			if err := execUpdate(ctx, tx, sqlf.Sprintf("namespace_org_id=%s", nullInt32Column(*update.NamespaceOrgID))); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 }
@@ -297,6 +310,7 @@ func (e externalServiceNotFoundError) NotFound() bool {
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (*ExternalServicesStore) Delete(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "Delete")
 	res, err := dbconn.Global.ExecContext(ctx, "UPDATE external_services SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
@@ -315,6 +329,7 @@ func (*ExternalServicesStore) Delete(ctx context.Context, id int64) error {
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) GetByID(ctx context.Context, id int64) (*types.ExternalService, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "GetByID")
 	if Mocks.ExternalServices.GetByID != nil {
 		return Mocks.ExternalServices.GetByID(id)
 	}
@@ -334,6 +349,7 @@ func (c *ExternalServicesStore) GetByID(ctx context.Context, id int64) (*types.E
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) List(ctx context.Context, opt ExternalServicesListOptions) ([]*types.ExternalService, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "List")
 	if Mocks.ExternalServices.List != nil {
 		return Mocks.ExternalServices.List(opt)
 	}
@@ -374,6 +390,7 @@ func (c *ExternalServicesStore) listConfigs(ctx context.Context, kind string, re
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListAWSCodeCommitConnections(ctx context.Context) ([]*schema.AWSCodeCommitConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListAWSCodeCommitConnections")
 	var connections []*schema.AWSCodeCommitConnection
 	if err := c.listConfigs(ctx, "AWSCODECOMMIT", &connections); err != nil {
 		return nil, err
@@ -385,6 +402,7 @@ func (c *ExternalServicesStore) ListAWSCodeCommitConnections(ctx context.Context
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListBitbucketCloudConnections(ctx context.Context) ([]*schema.BitbucketCloudConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListBitbucketCloudConnections")
 	var connections []*schema.BitbucketCloudConnection
 	if err := c.listConfigs(ctx, "BITBUCKETCLOUD", &connections); err != nil {
 		return nil, err
@@ -396,6 +414,7 @@ func (c *ExternalServicesStore) ListBitbucketCloudConnections(ctx context.Contex
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListBitbucketServerConnections(ctx context.Context) ([]*schema.BitbucketServerConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListBitbucketServerConnections")
 	var connections []*schema.BitbucketServerConnection
 	if err := c.listConfigs(ctx, "BITBUCKETSERVER", &connections); err != nil {
 		return nil, err
@@ -407,6 +426,7 @@ func (c *ExternalServicesStore) ListBitbucketServerConnections(ctx context.Conte
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListGitHubConnections(ctx context.Context) ([]*schema.GitHubConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListGitHubConnections")
 	var connections []*schema.GitHubConnection
 	if err := c.listConfigs(ctx, "GITHUB", &connections); err != nil {
 		return nil, err
@@ -418,6 +438,7 @@ func (c *ExternalServicesStore) ListGitHubConnections(ctx context.Context) ([]*s
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListGitLabConnections(ctx context.Context) ([]*schema.GitLabConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListGitLabConnections")
 	var connections []*schema.GitLabConnection
 	if err := c.listConfigs(ctx, "GITLAB", &connections); err != nil {
 		return nil, err
@@ -429,6 +450,7 @@ func (c *ExternalServicesStore) ListGitLabConnections(ctx context.Context) ([]*s
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListGitoliteConnections(ctx context.Context) ([]*schema.GitoliteConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListGitoliteConnections")
 	var connections []*schema.GitoliteConnection
 	if err := c.listConfigs(ctx, "GITOLITE", &connections); err != nil {
 		return nil, err
@@ -440,6 +462,7 @@ func (c *ExternalServicesStore) ListGitoliteConnections(ctx context.Context) ([]
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListPhabricatorConnections(ctx context.Context) ([]*schema.PhabricatorConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListPhabricatorConnections")
 	var connections []*schema.PhabricatorConnection
 	if err := c.listConfigs(ctx, "PHABRICATOR", &connections); err != nil {
 		return nil, err
@@ -451,6 +474,7 @@ func (c *ExternalServicesStore) ListPhabricatorConnections(ctx context.Context)
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) ListOtherExternalServicesConnections(ctx context.Context) ([]*schema.OtherExternalServiceConnection, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "ListOtherExternalServicesConnections")
 	var connections []*schema.OtherExternalServiceConnection
 	if err := c.listConfigs(ctx, "OTHER", &connections); err != nil {
 		return nil, err
@@ -460,7 +484,7 @@ func (c *ExternalServicesStore) ListOtherExternalServicesConnections(ctx context
 
 func (c *ExternalServicesStore) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*types.ExternalService, error) {
 	q := sqlf.Sprintf(`
-		SELECT id, kind, display_name, config, created_at, updated_at
+		SELECT id, kind, display_name, config, created_at, updated_at, namespace_org_id
 		FROM external_services
 		WHERE (%s)
 		ORDER BY id DESC
@@ -478,7 +502,7 @@ func (c *ExternalServicesStore) list(ctx context.Context, conds []*sqlf.Query, l
 	var results []*types.ExternalService
 	for rows.Next() {
 		var h types.ExternalService
-		if err := rows.Scan(&h.ID, &h.Kind, &h.DisplayName, &h.Config, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		if err := rows.Scan(&h.ID, &h.Kind, &h.DisplayName, &h.Config, &h.CreatedAt, &h.UpdatedAt, &dbutil.NullInt32{N: &h.NamespaceOrgID}); err != nil {
 			return nil, err
 		}
 		results = append(results, &h)
@@ -490,6 +514,7 @@ func (c *ExternalServicesStore) list(ctx context.Context, conds []*sqlf.Query, l
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) Count(ctx context.Context, opt ExternalServicesListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "ExternalServices", "Count")
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM external_services WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
 	var count int
 	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
@@ -503,3 +528,11 @@ type MockExternalServices struct {
 	GetByID func(id int64) (*types.ExternalService, error)
 	List    func(opt ExternalServicesListOptions) ([]*types.ExternalService, error)
 }
+
+// nullInt32Column returns nil for 0, so that an unset ID is stored as NULL.
+func nullInt32Column(n int32) *int32 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
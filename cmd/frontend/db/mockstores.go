@@ -27,4 +27,25 @@ type MockStores struct {
 	ExternalServices MockExternalServices
 
 	Authz MockAuthz
+
+	SubRepoPerms MockSubRepoPerms
+
+	RepoAccessRequests MockRepoAccessRequests
+	RepoAccessGrants   MockRepoAccessGrants
+
+	SnippetShareLinks MockSnippetShareLinks
+
+	CoverageReports MockCoverageReports
+
+	SlackUserLinks MockSlackUserLinks
+
+	Quotas MockQuotas
+
+	Watches MockWatches
+
+	PullRequests MockPullRequests
+
+	CodeClones MockCodeClones
+
+	CodeExpertise MockCodeExpertise
 }
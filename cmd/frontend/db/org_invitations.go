@@ -46,6 +46,7 @@ func (err OrgInvitationNotFoundError) Error() string {
 }
 
 func (*orgInvitations) Create(ctx context.Context, orgID, senderUserID, recipientUserID int32) (*OrgInvitation, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "Create")
 	if Mocks.OrgInvitations.Create != nil {
 		return Mocks.OrgInvitations.Create(orgID, senderUserID, recipientUserID)
 	}
@@ -75,6 +76,7 @@ func (*orgInvitations) Create(ctx context.Context, orgID, senderUserID, recipien
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to view this org invitation.
 func (s *orgInvitations) GetByID(ctx context.Context, id int64) (*OrgInvitation, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "GetByID")
 	if Mocks.OrgInvitations.GetByID != nil {
 		return Mocks.OrgInvitations.GetByID(id)
 	}
@@ -94,6 +96,7 @@ func (s *orgInvitations) GetByID(ctx context.Context, id int64) (*OrgInvitation,
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to view this org invitation.
 func (s *orgInvitations) GetPending(ctx context.Context, orgID, recipientUserID int32) (*OrgInvitation, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "GetPending")
 	results, err := s.list(ctx, []*sqlf.Query{
 		sqlf.Sprintf("org_id=%d AND recipient_user_id=%d AND responded_at IS NULL AND revoked_at IS NULL", orgID, recipientUserID),
 	}, nil)
@@ -132,6 +135,7 @@ func (o OrgInvitationsListOptions) sqlConditions() []*sqlf.Query {
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
 // options.
 func (s *orgInvitations) List(ctx context.Context, opt OrgInvitationsListOptions) ([]*OrgInvitation, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "List")
 	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
 }
 
@@ -166,6 +170,7 @@ ORDER BY id ASC
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to count the invitations.
 func (s *orgInvitations) Count(ctx context.Context, opt OrgInvitationsListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "Count")
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM org_invitations WHERE (%s) AND deleted_at IS NULL", sqlf.Join(opt.sqlConditions(), ") AND ("))
 	var count int
 	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
@@ -177,6 +182,7 @@ func (s *orgInvitations) Count(ctx context.Context, opt OrgInvitationsListOption
 // UpdateEmailSentTimestamp updates the email-sent timestam[ for the org invitation to the current
 // time.
 func (*orgInvitations) UpdateEmailSentTimestamp(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "UpdateEmailSentTimestamp")
 	res, err := dbconn.Global.ExecContext(ctx, "UPDATE org_invitations SET notified_at=now() WHERE id=$1 AND revoked_at IS NULL AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
@@ -195,6 +201,7 @@ func (*orgInvitations) UpdateEmailSentTimestamp(ctx context.Context, id int64) e
 // which the recipient was invited. If the recipient user ID given is incorrect, an
 // OrgInvitationNotFoundError error is returned.
 func (*orgInvitations) Respond(ctx context.Context, id int64, recipientUserID int32, accept bool) (orgID int32, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "Respond")
 	if err := dbconn.Global.QueryRowContext(ctx, "UPDATE org_invitations SET responded_at=now(), response_type=$3 WHERE id=$1 AND recipient_user_id=$2 AND responded_at IS NULL AND revoked_at IS NULL AND deleted_at IS NULL RETURNING org_id", id, recipientUserID, accept).Scan(&orgID); err == sql.ErrNoRows {
 		return 0, OrgInvitationNotFoundError{[]interface{}{fmt.Sprintf("id %d recipient %d", id, recipientUserID)}}
 	} else if err != nil {
@@ -206,6 +213,7 @@ func (*orgInvitations) Respond(ctx context.Context, id int64, recipientUserID in
 // Revoke marks an org invitation as revoked. The recipient is forbidden from responding to it after
 // it has been revoked.
 func (*orgInvitations) Revoke(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgInvitations", "Revoke")
 	if Mocks.OrgInvitations.Revoke != nil {
 		return Mocks.OrgInvitations.Revoke(id)
 	}
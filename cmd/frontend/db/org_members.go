@@ -17,6 +17,7 @@ import (
 type orgMembers struct{}
 
 func (*orgMembers) Create(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgMembers", "Create")
 	m := types.OrgMembership{
 		OrgID:  orgID,
 		UserID: userID,
@@ -37,10 +38,12 @@ func (*orgMembers) Create(ctx context.Context, orgID, userID int32) (*types.OrgM
 }
 
 func (m *orgMembers) GetByUserID(ctx context.Context, userID int32) ([]*types.OrgMembership, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgMembers", "GetByUserID")
 	return m.getBySQL(ctx, "INNER JOIN users ON org_members.user_id=users.id WHERE org_members.user_id=$1 AND users.deleted_at IS NULL", userID)
 }
 
 func (m *orgMembers) GetByOrgIDAndUserID(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgMembers", "GetByOrgIDAndUserID")
 	if Mocks.OrgMembers.GetByOrgIDAndUserID != nil {
 		return Mocks.OrgMembers.GetByOrgIDAndUserID(ctx, orgID, userID)
 	}
@@ -48,12 +51,14 @@ func (m *orgMembers) GetByOrgIDAndUserID(ctx context.Context, orgID, userID int3
 }
 
 func (*orgMembers) Remove(ctx context.Context, orgID, userID int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgMembers", "Remove")
 	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
 	return err
 }
 
 // GetByOrgID returns a list of all members of a given organization.
 func (*orgMembers) GetByOrgID(ctx context.Context, orgID int32) ([]*types.OrgMembership, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgMembers", "GetByOrgID")
 	org, err := Orgs.GetByID(ctx, orgID)
 	if err != nil {
 		return nil, err
@@ -114,6 +119,7 @@ func (*orgMembers) getBySQL(ctx context.Context, query string, args ...interface
 func (*orgMembers) CreateMembershipInOrgsForAllUsers(ctx context.Context, dbh interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }, orgNames []string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "OrgMembers", "CreateMembershipInOrgsForAllUsers")
 	if len(orgNames) == 0 {
 		return nil
 	}
@@ -30,6 +30,10 @@ type orgs struct{}
 // GetByUserID returns a list of all organizations for the user. An empty slice is
 // returned if the user is not authenticated or is not a member of any org.
 func (*orgs) GetByUserID(ctx context.Context, userID int32) ([]*types.Org, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "GetByUserID")
+	if Mocks.Orgs.GetByUserID != nil {
+		return Mocks.Orgs.GetByUserID(ctx, userID)
+	}
 	rows, err := dbconn.Global.QueryContext(ctx, "SELECT orgs.id, orgs.name, orgs.display_name,  orgs.created_at, orgs.updated_at FROM org_members LEFT OUTER JOIN orgs ON org_members.org_id = orgs.id WHERE user_id=$1 AND orgs.deleted_at IS NULL", userID)
 	if err != nil {
 		return []*types.Org{}, err
@@ -54,6 +58,7 @@ func (*orgs) GetByUserID(ctx context.Context, userID int32) ([]*types.Org, error
 }
 
 func (o *orgs) GetByID(ctx context.Context, orgID int32) (*types.Org, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "GetByID")
 	if Mocks.Orgs.GetByID != nil {
 		return Mocks.Orgs.GetByID(ctx, orgID)
 	}
@@ -68,6 +73,7 @@ func (o *orgs) GetByID(ctx context.Context, orgID int32) (*types.Org, error) {
 }
 
 func (o *orgs) GetByName(ctx context.Context, name string) (*types.Org, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "GetByName")
 	if Mocks.Orgs.GetByName != nil {
 		return Mocks.Orgs.GetByName(ctx, name)
 	}
@@ -82,6 +88,7 @@ func (o *orgs) GetByName(ctx context.Context, name string) (*types.Org, error) {
 }
 
 func (o *orgs) Count(ctx context.Context, opt OrgsListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "Count")
 	if Mocks.Orgs.Count != nil {
 		return Mocks.Orgs.Count(ctx, opt)
 	}
@@ -104,6 +111,7 @@ type OrgsListOptions struct {
 }
 
 func (o *orgs) List(ctx context.Context, opt *OrgsListOptions) ([]*types.Org, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "List")
 	if Mocks.Orgs.List != nil {
 		return Mocks.Orgs.List(ctx, opt)
 	}
@@ -148,6 +156,7 @@ func (*orgs) getBySQL(ctx context.Context, query string, args ...interface{}) ([
 }
 
 func (*orgs) Create(ctx context.Context, name string, displayName *string) (*types.Org, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "Create")
 	tx, err := dbconn.Global.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -197,6 +206,7 @@ func (*orgs) Create(ctx context.Context, name string, displayName *string) (*typ
 }
 
 func (o *orgs) Update(ctx context.Context, id int32, displayName *string) (*types.Org, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "Update")
 	org, err := o.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -221,6 +231,7 @@ func (o *orgs) Update(ctx context.Context, id int32, displayName *string) (*type
 }
 
 func (o *orgs) Delete(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "Delete")
 	// Wrap in transaction because we delete from multiple tables.
 	tx, err := dbconn.Global.BeginTx(ctx, nil)
 	if err != nil {
@@ -267,6 +278,7 @@ func (o *orgs) Delete(ctx context.Context, id int32) error {
 // TmpListAllOrgsWithSlackWebhookURL is a temporary method to support migrating
 // orgs.slack_webhook_url to the org's JSON settings. See bg.MigrateOrgSlackWebhookURLs.
 func (o *orgs) TmpListAllOrgsWithSlackWebhookURL(ctx context.Context) (orgIDsToWebhookURL map[int32]string, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "TmpListAllOrgsWithSlackWebhookURL")
 	rows, err := dbconn.Global.QueryContext(ctx, "SELECT id, slack_webhook_url FROM orgs WHERE slack_webhook_url IS NOT NULL")
 	if err != nil {
 		return nil, err
@@ -291,6 +303,7 @@ func (o *orgs) TmpListAllOrgsWithSlackWebhookURL(ctx context.Context) (orgIDsToW
 // TmpRemoveOrgSlackWebhookURL is a temporary method to support migrating
 // orgs.slack_webhook_url to the org's JSON settings. See bg.MigrateOrgSlackWebhookURLs.
 func (o *orgs) TmpRemoveOrgSlackWebhookURL(ctx context.Context, orgID int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Orgs", "TmpRemoveOrgSlackWebhookURL")
 	_, err := dbconn.Global.ExecContext(ctx, "UPDATE orgs SET slack_webhook_url = null WHERE id=$1", orgID)
 	return err
 }
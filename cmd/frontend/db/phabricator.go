@@ -23,6 +23,7 @@ func (err errPhabricatorRepoNotFound) Error() string {
 func (err errPhabricatorRepoNotFound) NotFound() bool { return true }
 
 func (*phabricator) Create(ctx context.Context, callsign string, name api.RepoName, phabURL string) (*types.PhabricatorRepo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Phabricator", "Create")
 	r := &types.PhabricatorRepo{
 		Callsign: callsign,
 		Name:     name,
@@ -39,6 +40,7 @@ func (*phabricator) Create(ctx context.Context, callsign string, name api.RepoNa
 }
 
 func (p *phabricator) CreateOrUpdate(ctx context.Context, callsign string, name api.RepoName, phabURL string) (*types.PhabricatorRepo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Phabricator", "CreateOrUpdate")
 	r := &types.PhabricatorRepo{
 		Callsign: callsign,
 		Name:     name,
@@ -58,6 +60,7 @@ func (p *phabricator) CreateOrUpdate(ctx context.Context, callsign string, name
 }
 
 func (p *phabricator) CreateIfNotExists(ctx context.Context, callsign string, name api.RepoName, phabURL string) (*types.PhabricatorRepo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Phabricator", "CreateIfNotExists")
 	repo, err := p.GetByName(ctx, name)
 	if err != nil {
 		if _, ok := err.(errPhabricatorRepoNotFound); !ok {
@@ -102,6 +105,7 @@ func (p *phabricator) getOneBySQL(ctx context.Context, query string, args ...int
 }
 
 func (p *phabricator) GetByName(ctx context.Context, name api.RepoName) (*types.PhabricatorRepo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Phabricator", "GetByName")
 	if Mocks.Phabricator.GetByName != nil {
 		return Mocks.Phabricator.GetByName(name)
 	}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// A PullRequest is a merged pull request (or merge request) of a repository, as indexed by
+// repo-updater.
+type PullRequest struct {
+	ID            int64
+	RepoID        api.RepoID
+	Number        int64
+	Title         string
+	URL           string
+	MergeCommitID api.CommitID // empty if the code host didn't report a merge commit
+	MergedAt      time.Time
+}
+
+// pullRequests provides access to the `repo_pull_requests` and `repo_pull_request_commits` tables.
+// repo-updater writes to them.
+type pullRequests struct{}
+
+// ListByCommit lists the pull requests of the repository that introduced the commit, that is, those
+// whose head commits or merge commit include it. They are ordered by merge time, oldest first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor may view the repository.
+func (s *pullRequests) ListByCommit(ctx context.Context, repoID api.RepoID, commitID api.CommitID) ([]*PullRequest, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "PullRequests", "ListByCommit")
+	if Mocks.PullRequests.ListByCommit != nil {
+		return Mocks.PullRequests.ListByCommit(ctx, repoID, commitID)
+	}
+
+	return s.list(ctx, sqlf.Sprintf(`
+SELECT pr.id, pr.repo_id, pr.number, pr.title, pr.url, COALESCE(pr.merge_commit_id, ''), pr.merged_at
+FROM repo_pull_requests pr
+JOIN repo_pull_request_commits c ON c.pull_request_id=pr.id
+WHERE c.repo_id=%d AND c.commit_id=%s
+ORDER BY pr.merged_at ASC, pr.number ASC`,
+		repoID, commitID,
+	))
+}
+
+func (*pullRequests) list(ctx context.Context, q *sqlf.Query) ([]*PullRequest, error) {
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*PullRequest
+	for rows.Next() {
+		var pr PullRequest
+		if err := rows.Scan(&pr.ID, &pr.RepoID, &pr.Number, &pr.Title, &pr.URL, &pr.MergeCommitID, &pr.MergedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &pr)
+	}
+	return results, rows.Err()
+}
+
+// ListCommitIDs lists the commits of the repository's pull request with the given number: its
+// head commits and its merge commit (if any).
+//
+// 🚨 SECURITY: The caller must ensure that the actor may view the repository.
+func (*pullRequests) ListCommitIDs(ctx context.Context, repoID api.RepoID, number int64) ([]api.CommitID, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "PullRequests", "ListCommitIDs")
+	if Mocks.PullRequests.ListCommitIDs != nil {
+		return Mocks.PullRequests.ListCommitIDs(ctx, repoID, number)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT c.commit_id FROM repo_pull_request_commits c
+JOIN repo_pull_requests pr ON pr.id=c.pull_request_id
+WHERE pr.repo_id=$1 AND pr.number=$2
+ORDER BY c.commit_id`,
+		repoID, number,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []api.CommitID
+	for rows.Next() {
+		var id api.CommitID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,12 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type MockPullRequests struct {
+	ListByCommit  func(ctx context.Context, repoID api.RepoID, commitID api.CommitID) ([]*PullRequest, error)
+	ListCommitIDs func(ctx context.Context, repoID api.RepoID, number int64) ([]api.CommitID, error)
+}
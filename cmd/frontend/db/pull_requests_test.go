@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestPullRequests(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "github.com/acme/a", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "github.com/acme/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// repo-updater writes these tables.
+	mergedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO repo_pull_requests(id, repo_id, number, title, url, merge_commit_id, merged_at, updated_at) VALUES
+	(1, $1, 7, 'Fix a', 'https://github.com/acme/a/pull/7', 'cccccccccccccccccccccccccccccccccccccccc', $2, $2),
+	(2, $1, 8, 'Revert b', 'https://github.com/acme/a/pull/8', NULL, $2::timestamptz + interval '1 hour', $2);
+INSERT INTO repo_pull_request_commits(pull_request_id, repo_id, commit_id) VALUES
+	(1, $1, 'aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa'),
+	(1, $1, 'cccccccccccccccccccccccccccccccccccccccc'),
+	(2, $1, 'aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa')`,
+		repo.ID, mergedAt,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	prs, err := PullRequests.ListByCommit(ctx, repo.ID, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 2 || prs[0].Number != 7 || prs[1].Number != 8 {
+		t.Fatalf("got %+v, want pull requests 7 and 8", prs)
+	}
+	if prs[0].MergeCommitID != "cccccccccccccccccccccccccccccccccccccccc" || prs[1].MergeCommitID != "" {
+		t.Errorf("got merge commits %q and %q", prs[0].MergeCommitID, prs[1].MergeCommitID)
+	}
+	if !prs[0].MergedAt.Equal(mergedAt) {
+		t.Errorf("got merged at %s, want %s", prs[0].MergedAt, mergedAt)
+	}
+	if prs, err := PullRequests.ListByCommit(ctx, repo.ID, "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"); err != nil || len(prs) != 0 {
+		t.Errorf("got %+v (err %v), want none", prs, err)
+	}
+
+	if ids, err := PullRequests.ListCommitIDs(ctx, repo.ID, 7); err != nil {
+		t.Fatal(err)
+	} else if want := []api.CommitID{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "cccccccccccccccccccccccccccccccccccccccc"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got commits %v, want %v", ids, want)
+	}
+	if ids, err := PullRequests.ListCommitIDs(ctx, repo.ID, 9); err != nil || len(ids) != 0 {
+		t.Errorf("got commits %v (err %v), want none", ids, err)
+	}
+}
@@ -0,0 +1,455 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/segmentio/fasthash/fnv1"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A QuotaNamespace is the organization or user that a quota applies to. The zero value refers to
+// the default quota, which applies to all organizations and users without a quota of their own.
+type QuotaNamespace struct {
+	OrgID  int32
+	UserID int32
+}
+
+// IsDefault reports whether ns refers to the default quota.
+func (ns QuotaNamespace) IsDefault() bool { return ns.OrgID == 0 && ns.UserID == 0 }
+
+func (ns QuotaNamespace) sqlCondition() *sqlf.Query {
+	switch {
+	case ns.OrgID != 0:
+		return sqlf.Sprintf("org_id=%d", ns.OrgID)
+	case ns.UserID != 0:
+		return sqlf.Sprintf("user_id=%d", ns.UserID)
+	default:
+		return sqlf.Sprintf("org_id IS NULL AND user_id IS NULL")
+	}
+}
+
+// A QuotaResource is a resource whose usage is limited by quotas.
+type QuotaResource string
+
+// Quota resources.
+const (
+	QuotaRepositories             QuotaResource = "repositories"             // repositories synced from the organization's external services
+	QuotaSavedSearchNotifications QuotaResource = "savedSearchNotifications" // saved searches that notify by email or Slack
+	QuotaCampaigns                QuotaResource = "campaigns"
+	QuotaChangesets               QuotaResource = "changesets"      // changesets created by campaigns
+	QuotaLSIFUploadBytes          QuotaResource = "lsifUploadBytes" // the size of the LSIF uploads for the organization's repositories
+	QuotaAccessTokens             QuotaResource = "accessTokens"
+)
+
+// QuotaResources are all quota resources, in the order in which they are displayed.
+var QuotaResources = []QuotaResource{
+	QuotaRepositories,
+	QuotaSavedSearchNotifications,
+	QuotaCampaigns,
+	QuotaChangesets,
+	QuotaLSIFUploadBytes,
+	QuotaAccessTokens,
+}
+
+var quotaColumns = map[QuotaResource]string{
+	QuotaRepositories:             "max_repositories",
+	QuotaSavedSearchNotifications: "max_saved_search_notifications",
+	QuotaCampaigns:                "max_campaigns",
+	QuotaChangesets:               "max_changesets",
+	QuotaLSIFUploadBytes:          "max_lsif_upload_bytes",
+	QuotaAccessTokens:             "max_access_tokens",
+}
+
+// AppliesTo reports whether the usage of the resource is counted for the namespace. Repositories and
+// LSIF uploads belong to organizations (through their external services), and access tokens belong
+// to users.
+func (r QuotaResource) AppliesTo(ns QuotaNamespace) bool {
+	switch r {
+	case QuotaRepositories, QuotaLSIFUploadBytes:
+		return ns.OrgID != 0
+	case QuotaAccessTokens:
+		return ns.UserID != 0
+	default:
+		return !ns.IsDefault()
+	}
+}
+
+func (r QuotaResource) describe(n int64) string {
+	switch r {
+	case QuotaSavedSearchNotifications:
+		return fmt.Sprintf("%d saved searches with notifications", n)
+	case QuotaLSIFUploadBytes:
+		return fmt.Sprintf("%d bytes of LSIF uploads", n)
+	case QuotaAccessTokens:
+		return fmt.Sprintf("%d access tokens", n)
+	default:
+		return fmt.Sprintf("%d %s", n, string(r))
+	}
+}
+
+// A Quota limits the resources that an organization or user may use.
+type Quota struct {
+	Namespace QuotaNamespace
+	Limits    map[QuotaResource]int64 // the maximum usage of each resource; missing resources are not limited
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// QuotaExceededError occurs when creating a resource would exceed the quota of an organization or
+// user.
+type QuotaExceededError struct {
+	Namespace QuotaNamespace
+	Resource  QuotaResource
+	Limit     int64
+	Usage     int64
+}
+
+func (e *QuotaExceededError) Error() string {
+	who := "the user"
+	if e.Namespace.OrgID != 0 {
+		who = "the organization"
+	}
+	return fmt.Sprintf("quota exceeded: %s may have at most %s (currently %d). Ask a site admin to raise the quota.", who, e.Resource.describe(e.Limit), e.Usage)
+}
+
+// quotaNotFoundError occurs when a namespace has no quota of its own.
+type quotaNotFoundError struct {
+	ns QuotaNamespace
+}
+
+func (e quotaNotFoundError) Error() string {
+	return fmt.Sprintf("quota not found: %+v", e.ns)
+}
+
+func (e quotaNotFoundError) NotFound() bool { return true }
+
+// quotas provides access to the `quotas` table, and computes the usage that quotas limit.
+//
+// Quotas are checked in the transaction that creates the resource, which holds a lock on the
+// namespace's usage of the resource until it ends (see Check).
+type quotas struct{}
+
+// Get returns the quota of the namespace itself (or the default quota for the zero namespace). It
+// returns an error that satisfies errcode.IsNotFound if there is none.
+func (*quotas) Get(ctx context.Context, ns QuotaNamespace) (*Quota, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "Get")
+	if Mocks.Quotas.Get != nil {
+		return Mocks.Quotas.Get(ctx, ns)
+	}
+
+	return getQuota(ctx, dbconn.Global, ns)
+}
+
+func getQuota(ctx context.Context, dbh dbutil.DB, ns QuotaNamespace) (*Quota, error) {
+	columns := make([]*sqlf.Query, len(QuotaResources))
+	for i, r := range QuotaResources {
+		columns[i] = sqlf.Sprintf(quotaColumns[r])
+	}
+	q := sqlf.Sprintf("SELECT %s, created_at, updated_at FROM quotas WHERE %s", sqlf.Join(columns, ", "), ns.sqlCondition())
+
+	limits := make([]*int64, len(QuotaResources))
+	dest := make([]interface{}, 0, len(QuotaResources)+2)
+	for i := range limits {
+		dest = append(dest, &limits[i])
+	}
+	quota := &Quota{Namespace: ns, Limits: map[QuotaResource]int64{}}
+	dest = append(dest, &quota.CreatedAt, &quota.UpdatedAt)
+	if err := queryQuotaRow(ctx, dbh, dest, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, quotaNotFoundError{ns: ns}
+		}
+		return nil, err
+	}
+	for i, r := range QuotaResources {
+		if limits[i] != nil {
+			quota.Limits[r] = *limits[i]
+		}
+	}
+	return quota, nil
+}
+
+// getEffectiveQuota implements GetEffective on dbh.
+func getEffectiveQuota(ctx context.Context, dbh dbutil.DB, ns QuotaNamespace) (*Quota, error) {
+	for _, ns := range []QuotaNamespace{ns, {}} {
+		quota, err := getQuota(ctx, dbh, ns)
+		if err == nil {
+			return quota, nil
+		}
+		if _, ok := err.(quotaNotFoundError); !ok {
+			return nil, err
+		}
+	}
+	return &Quota{Limits: map[QuotaResource]int64{}}, nil
+}
+
+// queryQuotaRow runs a query that returns a single row on dbh and scans the row into dest. It
+// returns sql.ErrNoRows if the query returns no rows.
+func queryQuotaRow(ctx context.Context, dbh dbutil.DB, dest []interface{}, query string, args ...interface{}) error {
+	rows, err := dbh.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// GetEffective returns the quota that applies to the namespace: its own quota if it has one, and
+// otherwise the default quota. If there is no default quota, the returned quota has no limits.
+func (*quotas) GetEffective(ctx context.Context, ns QuotaNamespace) (*Quota, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "GetEffective")
+	if Mocks.Quotas.GetEffective != nil {
+		return Mocks.Quotas.GetEffective(ctx, ns)
+	}
+
+	return getEffectiveQuota(ctx, dbconn.Global, ns)
+}
+
+// Set creates or replaces the quota of quota.Namespace (or the default quota).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*quotas) Set(ctx context.Context, quota *Quota) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "Set")
+	if Mocks.Quotas.Set != nil {
+		return Mocks.Quotas.Set(ctx, quota)
+	}
+	if quota.Namespace.OrgID != 0 && quota.Namespace.UserID != 0 {
+		return errors.New("a quota applies to an organization or a user, not both")
+	}
+
+	values := make([]*int64, len(QuotaResources))
+	sets := make([]*sqlf.Query, len(QuotaResources))
+	for i, r := range QuotaResources {
+		if limit, ok := quota.Limits[r]; ok {
+			if limit < 0 {
+				return errors.Errorf("invalid quota for %s: %d (must be non-negative)", r, limit)
+			}
+			values[i] = &limit
+		}
+		sets[i] = sqlf.Sprintf(quotaColumns[r]+"=%s", values[i])
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		q := sqlf.Sprintf("UPDATE quotas SET %s, updated_at=now() WHERE %s", sqlf.Join(sets, ", "), quota.Namespace.sqlCondition())
+		res, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n > 0 {
+			return err
+		}
+
+		columns := []*sqlf.Query{sqlf.Sprintf("org_id"), sqlf.Sprintf("user_id")}
+		args := []*sqlf.Query{sqlf.Sprintf("%s", nullInt32Column(quota.Namespace.OrgID)), sqlf.Sprintf("%s", nullInt32Column(quota.Namespace.UserID))}
+		for i, r := range QuotaResources {
+			columns = append(columns, sqlf.Sprintf(quotaColumns[r]))
+			args = append(args, sqlf.Sprintf("%s", values[i]))
+		}
+		q = sqlf.Sprintf("INSERT INTO quotas(%s) VALUES(%s)", sqlf.Join(columns, ", "), sqlf.Join(args, ", "))
+		_, err = tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+		return err
+	})
+}
+
+// Delete deletes the quota of the namespace (or the default quota), so that the default quota (or
+// no quota) applies to it.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*quotas) Delete(ctx context.Context, ns QuotaNamespace) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "Delete")
+	if Mocks.Quotas.Delete != nil {
+		return Mocks.Quotas.Delete(ctx, ns)
+	}
+	q := sqlf.Sprintf("DELETE FROM quotas WHERE %s", ns.sqlCondition())
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return quotaNotFoundError{ns: ns}
+	}
+	return nil
+}
+
+// orgRepoCondition matches the repositories that are synced from an external service of the
+// organization $1.
+const orgRepoCondition = `repo.deleted_at IS NULL AND EXISTS (
+	SELECT 1 FROM external_services es
+	WHERE es.namespace_org_id=$1 AND es.deleted_at IS NULL
+	AND repo.sources ? ('extsvc:' || lower(es.kind) || ':' || es.id)
+)`
+
+var quotaUsageQueries = map[QuotaResource]struct{ org, user string }{
+	QuotaRepositories: {
+		org: `SELECT COUNT(*) FROM repo WHERE ` + orgRepoCondition,
+	},
+	QuotaSavedSearchNotifications: {
+		org:  `SELECT COUNT(*) FROM saved_searches WHERE org_id=$1 AND (notify_owner OR notify_slack)`,
+		user: `SELECT COUNT(*) FROM saved_searches WHERE user_id=$1 AND (notify_owner OR notify_slack)`,
+	},
+	QuotaCampaigns: {
+		org:  `SELECT COUNT(*) FROM campaigns WHERE namespace_org_id=$1`,
+		user: `SELECT COUNT(*) FROM campaigns WHERE namespace_user_id=$1`,
+	},
+	QuotaChangesets: {
+		org:  `SELECT COUNT(*) FROM changeset_jobs JOIN campaigns ON campaigns.id=changeset_jobs.campaign_id WHERE campaigns.namespace_org_id=$1`,
+		user: `SELECT COUNT(*) FROM changeset_jobs JOIN campaigns ON campaigns.id=changeset_jobs.campaign_id WHERE campaigns.namespace_user_id=$1`,
+	},
+	QuotaLSIFUploadBytes: {
+		org: `SELECT COALESCE(SUM(lsif_uploads.upload_size), 0) FROM lsif_uploads JOIN repo ON repo.id=lsif_uploads.repository_id WHERE ` + orgRepoCondition,
+	},
+	QuotaAccessTokens: {
+		user: `SELECT COUNT(*) FROM access_tokens WHERE subject_user_id=$1 AND deleted_at IS NULL`,
+	},
+}
+
+// Usage returns the current usage of the resource by the namespace. It returns 0 if the resource
+// doesn't apply to the namespace.
+func (*quotas) Usage(ctx context.Context, ns QuotaNamespace, resource QuotaResource) (int64, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "Usage")
+	if Mocks.Quotas.Usage != nil {
+		return Mocks.Quotas.Usage(ctx, ns, resource)
+	}
+	return quotaUsage(ctx, dbconn.Global, ns, resource)
+}
+
+func quotaUsage(ctx context.Context, dbh dbutil.DB, ns QuotaNamespace, resource QuotaResource) (int64, error) {
+	if !resource.AppliesTo(ns) {
+		return 0, nil
+	}
+
+	queries, ok := quotaUsageQueries[resource]
+	if !ok {
+		return 0, errors.Errorf("unknown quota resource %q", resource)
+	}
+	query, id := queries.user, ns.UserID
+	if ns.OrgID != 0 {
+		query, id = queries.org, ns.OrgID
+	}
+	var usage int64
+	err := queryQuotaRow(ctx, dbh, []interface{}{&usage}, query, id)
+	return usage, err
+}
+
+// Check returns a *QuotaExceededError if adding n units of the resource would exceed the quota of
+// the namespace.
+//
+// Check must be called in the transaction tx that creates the resource. It locks the namespace's
+// usage of the resource until tx ends, so that concurrent transactions can't all pass the check
+// and together exceed the quota.
+func (*quotas) Check(ctx context.Context, tx dbutil.DB, ns QuotaNamespace, resource QuotaResource, n int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "Check")
+	if Mocks.Quotas.Check != nil {
+		return Mocks.Quotas.Check(ctx, ns, resource, n)
+	}
+	if !resource.AppliesTo(ns) || n <= 0 {
+		return nil
+	}
+	if _, ok := tx.(dbutil.Tx); !ok {
+		return errors.New("Quotas.Check must be called inside a transaction")
+	}
+
+	// Taking the lock before reading the usage means that a concurrent transaction that already
+	// passed the check has committed (or rolled back) the resource it created by the time we count.
+	q := quotaLockQuery(ns, resource)
+	rows, err := tx.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	quota, err := getEffectiveQuota(ctx, tx, ns)
+	if err != nil {
+		return err
+	}
+	limit, ok := quota.Limits[resource]
+	if !ok {
+		return nil
+	}
+	usage, err := quotaUsage(ctx, tx, ns, resource)
+	if err != nil {
+		return err
+	}
+	if usage+n > limit {
+		return &QuotaExceededError{Namespace: ns, Resource: resource, Limit: limit, Usage: usage}
+	}
+	return nil
+}
+
+var quotaLockNamespace = int32(fnv1.HashString32("quotas"))
+
+func quotaLockQuery(ns QuotaNamespace, resource QuotaResource) *sqlf.Query {
+	// Postgres advisory lock ids are a global namespace within one database. If the ids of two
+	// namespaces or resources collide, their quota checks merely wait for each other.
+	lockID := int32(fnv1.HashString32(fmt.Sprintf("%d:%d:%s", ns.OrgID, ns.UserID, resource)))
+	return sqlf.Sprintf("SELECT pg_advisory_xact_lock(%s, %s)", quotaLockNamespace, lockID)
+}
+
+// ListOrgsOfRepo returns the IDs of the organizations whose repository quotas the repository counts
+// against (because it is synced from one of their external services).
+func (*quotas) ListOrgsOfRepo(ctx context.Context, repoID api.RepoID) ([]int32, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "ListOrgsOfRepo")
+	if Mocks.Quotas.ListOrgsOfRepo != nil {
+		return Mocks.Quotas.ListOrgsOfRepo(ctx, repoID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT DISTINCT es.namespace_org_id FROM external_services es JOIN repo ON repo.id=$1
+WHERE es.namespace_org_id IS NOT NULL AND es.deleted_at IS NULL
+AND repo.sources ? ('extsvc:' || lower(es.kind) || ':' || es.id)
+ORDER BY es.namespace_org_id`, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgIDs []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		orgIDs = append(orgIDs, id)
+	}
+	return orgIDs, rows.Err()
+}
+
+// SetLSIFUploadSize records the size of an LSIF upload, which counts against the LSIF upload quota
+// of the organizations of its repository. It runs in tx, which should be the transaction in which
+// the upload's quota was checked.
+func (*quotas) SetLSIFUploadSize(ctx context.Context, tx dbutil.DB, uploadID, size int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Quotas", "SetLSIFUploadSize")
+	if Mocks.Quotas.SetLSIFUploadSize != nil {
+		return Mocks.Quotas.SetLSIFUploadSize(ctx, uploadID, size)
+	}
+	rows, err := tx.QueryContext(ctx, "UPDATE lsif_uploads SET upload_size=$1 WHERE id=$2", size, uploadID)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// IsQuotaExceeded reports whether err is (or wraps) a *QuotaExceededError.
+func IsQuotaExceeded(err error) bool {
+	_, ok := errors.Cause(err).(*QuotaExceededError)
+	return ok
+}
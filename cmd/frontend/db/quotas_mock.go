@@ -0,0 +1,18 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type MockQuotas struct {
+	Get               func(ctx context.Context, ns QuotaNamespace) (*Quota, error)
+	GetEffective      func(ctx context.Context, ns QuotaNamespace) (*Quota, error)
+	Set               func(ctx context.Context, quota *Quota) error
+	Delete            func(ctx context.Context, ns QuotaNamespace) error
+	Usage             func(ctx context.Context, ns QuotaNamespace, resource QuotaResource) (int64, error)
+	Check             func(ctx context.Context, ns QuotaNamespace, resource QuotaResource, n int64) error
+	ListOrgsOfRepo    func(ctx context.Context, repoID api.RepoID) ([]int32, error)
+	SetLSIFUploadSize func(ctx context.Context, uploadID, size int64) error
+}
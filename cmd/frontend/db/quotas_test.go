@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestQuotas_concurrentCreates(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	user, err := Users.Create(ctx, NewUser{DisplayName: "test", Email: "test@test.com", Username: "test", Password: "test", EmailVerificationCode: "c2"})
+	if err != nil {
+		t.Fatal("can't create user", err)
+	}
+	ns := QuotaNamespace{UserID: user.ID}
+	const limit = 2
+	if err := Quotas.Set(ctx, &Quota{Namespace: ns, Limits: map[QuotaResource]int64{QuotaSavedSearchNotifications: limit}}); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := SavedSearches.Create(ctx, &types.SavedSearch{Query: "test", Description: "test", Notify: true, UserID: &user.ID})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created int
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !IsQuotaExceeded(err):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if created != limit {
+		t.Errorf("got %d saved searches created, want %d", created, limit)
+	}
+	if usage, err := Quotas.Usage(ctx, ns, QuotaSavedSearchNotifications); err != nil {
+		t.Fatal(err)
+	} else if usage != limit {
+		t.Errorf("got usage %d, want %d", usage, limit)
+	}
+}
+
+func TestQuotas_checkOutsideTransaction(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	if err := Quotas.Check(context.Background(), dbconn.Global, QuotaNamespace{UserID: 1}, QuotaAccessTokens, 1); err == nil {
+		t.Error("got nil error, want an error for a check outside of a transaction")
+	}
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A RepoAccessRequest is a request by a user for read access to the repositories whose names match
+// a pattern.
+type RepoAccessRequest struct {
+	ID              int64
+	UserID          int32  // the user requesting access
+	RepoPattern     string // a repository name, or a glob pattern matching repository names
+	Justification   string
+	ApproverUserIDs []int32 // the users who were notified of the request
+	CreatedAt       time.Time
+	ReviewerUserID  *int32
+	ReviewedAt      *time.Time
+	Approved        *bool // approved (true), denied (false), not reviewed (nil)
+	ReviewComment   string
+	ExpiresAt       *time.Time // the expiry of the granted access (nil if it never expires)
+}
+
+// Pending reports whether the request has not been reviewed yet.
+func (r *RepoAccessRequest) Pending() bool {
+	return r.ReviewedAt == nil
+}
+
+type repoAccessRequests struct{}
+
+// RepoAccessRequestNotFoundError occurs when a repo access request is not found.
+type RepoAccessRequestNotFoundError struct {
+	args []interface{}
+}
+
+// NotFound implements errcode.NotFounder.
+func (err RepoAccessRequestNotFoundError) NotFound() bool { return true }
+
+func (err RepoAccessRequestNotFoundError) Error() string {
+	return fmt.Sprintf("repo access request not found: %v", err.args)
+}
+
+// Create creates a pending repo access request. At most one request may be pending for a (user,
+// pattern).
+func (*repoAccessRequests) Create(ctx context.Context, r *RepoAccessRequest) error {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessRequests", "Create")
+	if Mocks.RepoAccessRequests.Create != nil {
+		return Mocks.RepoAccessRequests.Create(ctx, r)
+	}
+
+	if err := dbconn.Global.QueryRowContext(
+		ctx,
+		"INSERT INTO repo_access_requests(user_id, repo_pattern, justification, approver_user_ids) VALUES($1, $2, $3, $4) RETURNING id, created_at",
+		r.UserID, r.RepoPattern, r.Justification, pq.Array(int32sToInt64s(r.ApproverUserIDs)),
+	).Scan(&r.ID, &r.CreatedAt); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Constraint {
+			case "repo_access_requests_singleflight":
+				return errors.New("access to these repositories was already requested (and the request has not been reviewed yet)")
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// GetByID retrieves the repo access request (if any) given its ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view this request.
+func (s *repoAccessRequests) GetByID(ctx context.Context, id int64) (*RepoAccessRequest, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessRequests", "GetByID")
+	if Mocks.RepoAccessRequests.GetByID != nil {
+		return Mocks.RepoAccessRequests.GetByID(ctx, id)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("id=%d", id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, RepoAccessRequestNotFoundError{[]interface{}{id}}
+	}
+	return results[0], nil
+}
+
+// RepoAccessRequestsListOptions contains options for listing repo access requests.
+type RepoAccessRequestsListOptions struct {
+	UserID         int32 // only list requests by this user
+	ApproverUserID int32 // only list requests that this user was notified of as an approver
+	OnlyPending    bool  // only list requests that have not been reviewed yet
+	*LimitOffset
+}
+
+func (o RepoAccessRequestsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id=%d", o.UserID))
+	}
+	if o.ApproverUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("%d = ANY(approver_user_ids)", o.ApproverUserID))
+	}
+	if o.OnlyPending {
+		conds = append(conds, sqlf.Sprintf("reviewed_at IS NULL"))
+	}
+	return conds
+}
+
+// List lists all repo access requests that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
+// options.
+func (s *repoAccessRequests) List(ctx context.Context, opt RepoAccessRequestsListOptions) ([]*RepoAccessRequest, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessRequests", "List")
+	if Mocks.RepoAccessRequests.List != nil {
+		return Mocks.RepoAccessRequests.List(ctx, opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (*repoAccessRequests) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*RepoAccessRequest, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, repo_pattern, justification, approver_user_ids, created_at, reviewer_user_id, reviewed_at, approved, review_comment, expires_at FROM repo_access_requests
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*RepoAccessRequest
+	for rows.Next() {
+		r, err := scanRepoAccessRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+func scanRepoAccessRequest(s interface{ Scan(...interface{}) error }) (*RepoAccessRequest, error) {
+	var (
+		r           RepoAccessRequest
+		approverIDs pq.Int64Array
+	)
+	if err := s.Scan(&r.ID, &r.UserID, &r.RepoPattern, &r.Justification, &approverIDs, &r.CreatedAt, &r.ReviewerUserID, &r.ReviewedAt, &r.Approved, &r.ReviewComment, &r.ExpiresAt); err != nil {
+		return nil, err
+	}
+	r.ApproverUserIDs = int64sToInt32s(approverIDs)
+	return &r, nil
+}
+
+// Count counts all repo access requests that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to count the requests.
+func (*repoAccessRequests) Count(ctx context.Context, opt RepoAccessRequestsListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessRequests", "Count")
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM repo_access_requests WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// RepoAccessReview is the review of a repo access request.
+type RepoAccessReview struct {
+	ReviewerUserID int32
+	Approve        bool
+	Comment        string
+	ExpiresAt      *time.Time   // the expiry of the granted access (nil if it never expires)
+	RepoIDs        []api.RepoID // the repositories to grant access to (if approved)
+}
+
+// Review records the review of a pending repo access request and, if it is approved, grants the
+// requesting user read access to the given repositories. An existing grant is only ever extended,
+// never shortened. If the request is not pending, a RepoAccessRequestNotFoundError is returned.
+//
+// 🚨 SECURITY: This method does NOT verify the reviewer's identity. It is the callers
+// responsibility to ensure that the reviewer is permitted to approve access to all of the
+// repositories.
+func (*repoAccessRequests) Review(ctx context.Context, id int64, review RepoAccessReview) (r *RepoAccessRequest, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessRequests", "Review")
+	if Mocks.RepoAccessRequests.Review != nil {
+		return Mocks.RepoAccessRequests.Review(ctx, id, review)
+	}
+
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		var expiresAt *time.Time
+		if review.Approve {
+			expiresAt = review.ExpiresAt
+		}
+		r, err = scanRepoAccessRequest(tx.QueryRowContext(ctx, `
+UPDATE repo_access_requests SET reviewer_user_id=$2, reviewed_at=now(), approved=$3, review_comment=$4, expires_at=$5
+WHERE id=$1 AND reviewed_at IS NULL
+RETURNING id, user_id, repo_pattern, justification, approver_user_ids, created_at, reviewer_user_id, reviewed_at, approved, review_comment, expires_at`,
+			id, review.ReviewerUserID, review.Approve, review.Comment, expiresAt))
+		if err == sql.ErrNoRows {
+			return RepoAccessRequestNotFoundError{[]interface{}{fmt.Sprintf("pending %d", id)}}
+		} else if err != nil {
+			return err
+		}
+		if !review.Approve || len(review.RepoIDs) == 0 {
+			return nil
+		}
+
+		repoIDs := make([]int64, len(review.RepoIDs))
+		for i, id := range review.RepoIDs {
+			repoIDs[i] = int64(id)
+		}
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO repo_access_grants(user_id, repo_id, access_request_id, expires_at)
+SELECT $1, repo_id, $3, $4 FROM unnest($2::integer[]) AS repo_id
+ON CONFLICT (user_id, repo_id) DO UPDATE SET
+	access_request_id = excluded.access_request_id,
+	expires_at = CASE
+		WHEN repo_access_grants.expires_at IS NULL OR excluded.expires_at IS NULL THEN NULL
+		ELSE GREATEST(repo_access_grants.expires_at, excluded.expires_at)
+	END`,
+			r.UserID, pq.Array(repoIDs), r.ID, expiresAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// repoAccessGrants provides access to the `repo_access_grants` table, which stores the explicit
+// read access to repositories granted by approved repo access requests.
+type repoAccessGrants struct{}
+
+// ListRepoIDs returns the IDs of the repositories that the user has been granted (unexpired) read
+// access to.
+//
+// 🚨 SECURITY: The result is used to enforce repository permissions, so it must never include
+// expired grants.
+func (*repoAccessGrants) ListRepoIDs(ctx context.Context, userID int32) ([]api.RepoID, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessGrants", "ListRepoIDs")
+	if Mocks.RepoAccessGrants.ListRepoIDs != nil {
+		return Mocks.RepoAccessGrants.ListRepoIDs(ctx, userID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx,
+		"SELECT repo_id FROM repo_access_grants WHERE user_id=$1 AND (expires_at IS NULL OR expires_at > now()) ORDER BY repo_id",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []api.RepoID
+	for rows.Next() {
+		var id api.RepoID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteExpired deletes all expired grants and returns the number of grants deleted.
+func (*repoAccessGrants) DeleteExpired(ctx context.Context) (int64, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "RepoAccessGrants", "DeleteExpired")
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM repo_access_grants WHERE expires_at <= now()")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
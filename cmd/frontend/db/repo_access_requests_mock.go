@@ -0,0 +1,18 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type MockRepoAccessRequests struct {
+	Create  func(ctx context.Context, r *RepoAccessRequest) error
+	GetByID func(ctx context.Context, id int64) (*RepoAccessRequest, error)
+	List    func(ctx context.Context, opt RepoAccessRequestsListOptions) ([]*RepoAccessRequest, error)
+	Review  func(ctx context.Context, id int64, review RepoAccessReview) (*RepoAccessRequest, error)
+}
+
+type MockRepoAccessGrants struct {
+	ListRepoIDs func(ctx context.Context, userID int32) ([]api.RepoID, error)
+}
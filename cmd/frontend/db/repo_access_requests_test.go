@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// 🚨 SECURITY: This tests the routines that grant users access to repositories.
+func TestRepoAccessRequests(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	requester, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	approver, err := Users.Create(ctx, NewUser{Username: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []api.RepoName{"github.com/acme/a", "github.com/acme/b"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repoA, err := Repos.GetByName(ctx, "github.com/acme/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoB, err := Repos.GetByName(ctx, "github.com/acme/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r1 := &RepoAccessRequest{UserID: requester.ID, RepoPattern: "github.com/acme/*", Justification: "j1", ApproverUserIDs: []int32{approver.ID}}
+	if err := RepoAccessRequests.Create(ctx, r1); err != nil {
+		t.Fatal(err)
+	}
+	if err := RepoAccessRequests.Create(ctx, &RepoAccessRequest{UserID: requester.ID, RepoPattern: "github.com/acme/*", Justification: "again"}); err == nil {
+		t.Fatal("want error creating a second pending request for the same pattern")
+	}
+	r2 := &RepoAccessRequest{UserID: requester.ID, RepoPattern: "github.com/acme/b", Justification: "j2"}
+	if err := RepoAccessRequests.Create(ctx, r2); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("GetByID", func(t *testing.T) {
+		if r, err := RepoAccessRequests.GetByID(ctx, r1.ID); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(r, r1) {
+			t.Errorf("got %+v, want %+v", r, r1)
+		}
+		if _, err := RepoAccessRequests.GetByID(ctx, 12345 /* doesn't exist */); !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+	})
+
+	testListCount := func(t *testing.T, opt RepoAccessRequestsListOptions, want []*RepoAccessRequest) {
+		t.Helper()
+		if rs, err := RepoAccessRequests.List(ctx, opt); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(rs, want) {
+			t.Errorf("got %+v, want %+v", rs, want)
+		}
+		if n, err := RepoAccessRequests.Count(ctx, opt); err != nil {
+			t.Fatal(err)
+		} else if want := len(want); n != want {
+			t.Errorf("got %d, want %d", n, want)
+		}
+	}
+	t.Run("List/Count", func(t *testing.T) {
+		testListCount(t, RepoAccessRequestsListOptions{UserID: requester.ID}, []*RepoAccessRequest{r2, r1})
+		testListCount(t, RepoAccessRequestsListOptions{ApproverUserID: approver.ID}, []*RepoAccessRequest{r1})
+		testListCount(t, RepoAccessRequestsListOptions{UserID: approver.ID}, nil)
+	})
+
+	t.Run("Review", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		r, err := RepoAccessRequests.Review(ctx, r1.ID, RepoAccessReview{
+			ReviewerUserID: approver.ID,
+			Approve:        true,
+			Comment:        "ok",
+			ExpiresAt:      &expiresAt,
+			RepoIDs:        []api.RepoID{repoA.ID},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Pending() || r.Approved == nil || !*r.Approved || r.ReviewComment != "ok" || r.ExpiresAt == nil || !r.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("unexpected reviewed request %+v", r)
+		}
+		if _, err := RepoAccessRequests.Review(ctx, r1.ID, RepoAccessReview{ReviewerUserID: approver.ID}); !errcode.IsNotFound(err) {
+			t.Errorf("reviewing twice: got err %v, want errcode.IsNotFound", err)
+		}
+		testListCount(t, RepoAccessRequestsListOptions{OnlyPending: true}, []*RepoAccessRequest{r2})
+
+		// A permanent grant for repo A replaces the time-limited one.
+		if _, err := RepoAccessRequests.Review(ctx, r2.ID, RepoAccessReview{
+			ReviewerUserID: approver.ID,
+			Approve:        true,
+			RepoIDs:        []api.RepoID{repoA.ID, repoB.ID},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		ids, err := RepoAccessGrants.ListRepoIDs(ctx, requester.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []api.RepoID{repoA.ID, repoB.ID}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got granted repos %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE repo_access_grants SET expires_at=now() - interval '1 minute' WHERE repo_id=$1", repoB.ID); err != nil {
+			t.Fatal(err)
+		}
+		ids, err := RepoAccessGrants.ListRepoIDs(ctx, requester.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []api.RepoID{repoA.ID}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got granted repos %v, want %v (expired grants must not be listed)", ids, want)
+		}
+		if n, err := RepoAccessGrants.DeleteExpired(ctx); err != nil {
+			t.Fatal(err)
+		} else if n != 1 {
+			t.Errorf("got %d deleted grants, want 1", n)
+		}
+	})
+}
@@ -47,6 +47,7 @@ type repos struct{}
 // stale, the caller is responsible for fetching data from any
 // external services.
 func (s *repos) Get(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Repos", "Get")
 	if Mocks.Repos.Get != nil {
 		return Mocks.Repos.Get(ctx, id)
 	}
@@ -70,6 +71,7 @@ func (s *repos) Get(ctx context.Context, id api.RepoID) (*types.Repo, error) {
 // the same as URI, unless the user configures a non-default
 // repositoryPathPattern.
 func (s *repos) GetByName(ctx context.Context, nameOrURI api.RepoName) (*types.Repo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Repos", "GetByName")
 	if Mocks.Repos.GetByName != nil {
 		return Mocks.Repos.GetByName(ctx, nameOrURI)
 	}
@@ -103,6 +105,7 @@ func (s *repos) GetByName(ctx context.Context, nameOrURI api.RepoName) (*types.R
 // 🚨 SECURITY: It is the caller's responsibility to ensure the current authenticated user
 // is the site admin because this method returns all available data from the database.
 func (s *repos) GetByIDs(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Repos", "GetByIDs")
 	if Mocks.Repos.GetByIDs != nil {
 		return Mocks.Repos.GetByIDs(ctx, ids...)
 	}
@@ -120,6 +123,7 @@ func (s *repos) GetByIDs(ctx context.Context, ids ...api.RepoID) ([]*types.Repo,
 }
 
 func (s *repos) Count(ctx context.Context, opt ReposListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Repos", "Count")
 	if Mocks.Repos.Count != nil {
 		return Mocks.Repos.Count(ctx, opt)
 	}
@@ -316,6 +320,7 @@ const (
 // The result list is unsorted and has a fixed maximum limit of 1000 items.
 // Matching is done with fuzzy matching, i.e. "query" will match any repo name that matches the regexp `q.*u.*e.*r.*y`
 func (s *repos) List(ctx context.Context, opt ReposListOptions) (results []*types.Repo, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Repos", "List")
 	tr, ctx := trace.New(ctx, "repos.List", "")
 	defer func() {
 		tr.SetError(err)
@@ -342,6 +347,7 @@ func (s *repos) List(ctx context.Context, opt ReposListOptions) (results []*type
 // indexed-search). We special case just returning enabled names so that we
 // read much less data into memory.
 func (s *repos) ListEnabledNames(ctx context.Context) ([]string, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Repos", "ListEnabledNames")
 	q := sqlf.Sprintf("SELECT name FROM repo WHERE deleted_at IS NULL")
 	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
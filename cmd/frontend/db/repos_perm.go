@@ -11,6 +11,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"gopkg.in/inconshreveable/log15.v2"
@@ -29,6 +31,9 @@ var MockAuthzFilter func(ctx context.Context, repos []*types.Repo, p authz.Perms
 //
 // The enforcement policy:
 //
+// - If repository access requests are enabled, repositories that the user was granted read access
+//   to by an approved request are accessible (in addition to those permitted below).
+//
 // - If permissions user mapping is enabled, directly check permissions against local Postgres.
 //
 // - If there are no authz providers and `authzAllowByDefault` is true, then the repository is
@@ -86,6 +91,61 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		}
 	}
 
+	// 🚨 SECURITY: Repositories that the user was granted read access to by an approved access
+	// request are accessible in addition to those permitted by the authz providers.
+	if currentUser != nil && p == authz.Read && repoAccessRequestsEnabled() {
+		granted, err := RepoAccessGrants.ListRepoIDs(ctx, currentUser.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(granted) > 0 {
+			return authzFilterWithGrants(ctx, tr, currentUser, repos, p, granted)
+		}
+	}
+
+	return authzFilterByProviders(ctx, tr, currentUser, repos, p)
+}
+
+// authzFilterWithGrants returns the repositories (preserving their order) that are either permitted
+// by the authz providers or in the granted set.
+func authzFilterWithGrants(ctx context.Context, tr *trace.Trace, currentUser *types.User, repos []*types.Repo, p authz.Perms, granted []api.RepoID) ([]*types.Repo, error) {
+	grantedSet := make(map[api.RepoID]struct{}, len(granted))
+	for _, id := range granted {
+		grantedSet[id] = struct{}{}
+	}
+
+	// authzFilterByProviders filters in place, so it is given a copy of the repos.
+	permitted, err := authzFilterByProviders(ctx, tr, currentUser, append([]*types.Repo(nil), repos...), p)
+	if err != nil {
+		return nil, err
+	}
+	permittedSet := make(map[api.RepoID]struct{}, len(permitted))
+	for _, r := range permitted {
+		permittedSet[r.ID] = struct{}{}
+	}
+
+	filtered := repos[:0]
+	for _, r := range repos {
+		_, ok1 := grantedSet[r.ID]
+		_, ok2 := permittedSet[r.ID]
+		if ok1 || ok2 {
+			filtered = append(filtered, r) // In-place filtering
+		}
+	}
+	clear(repos[len(filtered):])
+	return filtered, nil
+}
+
+// repoAccessRequestsEnabled reports whether the grants of approved repo access requests are
+// enforced.
+func repoAccessRequestsEnabled() bool {
+	c := conf.Get().PermissionsAccessRequests
+	return c != nil && c.Enabled
+}
+
+// authzFilterByProviders implements authzFilter for the permissions user mapping and the authz
+// providers. NOTE: The repos slice is filtered in place and returned.
+func authzFilterByProviders(ctx context.Context, tr *trace.Trace, currentUser *types.User, repos []*types.Repo, p authz.Perms) (filtered []*types.Repo, err error) {
 	authzAllowByDefault, authzProviders := authz.GetProviders()
 	tr.LogFields(
 		otlog.Bool("authzAllowByDefault", authzAllowByDefault),
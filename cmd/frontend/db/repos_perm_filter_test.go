@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -862,6 +863,51 @@ func Test_authzFilter_permissionsUserMapping(t *testing.T) {
 	})
 }
 
+func Test_authzFilter_repoAccessGrants(t *testing.T) {
+	user := &types.User{ID: 1}
+	Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return user, nil
+	}
+	Mocks.RepoAccessGrants.ListRepoIDs = func(_ context.Context, userID int32) ([]api.RepoID, error) {
+		if userID != user.ID {
+			t.Fatalf("ListRepoIDs: want user %d but got %d", user.ID, userID)
+		}
+		return []api.RepoID{3, 1}, nil
+	}
+	defer func() { Mocks = MockStores{} }()
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: user.ID})
+
+	authz.SetProviders(false, nil)
+	defer authz.SetProviders(true, nil)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		perm    authz.Perms
+		want    []string
+	}{
+		{name: "disabled", enabled: false, perm: authz.Read, want: []string{}},
+		{name: "enabled", enabled: true, perm: authz.Read, want: []string{"r0", "r2"}},
+		{name: "only grants read access", enabled: true, perm: authz.Write, want: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				PermissionsAccessRequests: &schema.PermissionsAccessRequests{Enabled: test.enabled},
+			}})
+			defer conf.Mock(nil)
+
+			filtered, err := authzFilter(ctx, makeRepos("r0", "r1", "r2"), test.perm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getNames(filtered); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want repos %v but got %v", test.want, got)
+			}
+		})
+	}
+}
+
 func acct(userID int32, serviceType, serviceID, accountID string) *extsvc.ExternalAccount {
 	return &extsvc.ExternalAccount{
 		UserID: userID,
@@ -21,6 +21,7 @@ type SavedQueryInfo struct {
 // Get gets the saved query information for the given query. nil
 // is returned if there is no existing saved query info.
 func (s *queryRunnerState) Get(ctx context.Context, query string) (*SavedQueryInfo, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "QueryRunnerState", "Get")
 	info := &SavedQueryInfo{
 		Query: query,
 	}
@@ -45,6 +46,7 @@ func (s *queryRunnerState) Get(ctx context.Context, query string) (*SavedQueryIn
 // It is not safe to call concurrently for the same info.Query, as it uses a
 // poor man's upsert implementation.
 func (s *queryRunnerState) Set(ctx context.Context, info *SavedQueryInfo) error {
+	ctx = dbconn.WithQueryLabels(ctx, "QueryRunnerState", "Set")
 	res, err := dbconn.Global.ExecContext(
 		ctx,
 		"UPDATE query_runner_state SET last_executed=$1, latest_result=$2, exec_duration_ns=$3 WHERE query=$4",
@@ -78,6 +80,7 @@ func (s *queryRunnerState) Set(ctx context.Context, info *SavedQueryInfo) error
 }
 
 func (s *queryRunnerState) Delete(ctx context.Context, query string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "QueryRunnerState", "Delete")
 	_, err := dbconn.Global.ExecContext(
 		ctx,
 		"DELETE FROM query_runner_state WHERE query=$1",
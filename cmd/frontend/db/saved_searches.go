@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
@@ -19,9 +20,10 @@ type savedSearches struct{}
 // IsEmpty tells if there are no saved searches (at all) on this Sourcegraph
 // instance.
 func (s *savedSearches) IsEmpty(ctx context.Context) (bool, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "IsEmpty")
 	q := `SELECT true FROM saved_searches LIMIT 1`
 	var isNotEmpty bool
-	err := dbconn.Global.QueryRow(q).Scan(&isNotEmpty)
+	err := dbconn.Global.QueryRowContext(ctx, q).Scan(&isNotEmpty)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return true, nil
@@ -37,6 +39,7 @@ func (s *savedSearches) IsEmpty(ctx context.Context) (bool, error) {
 // user is an admin. It is the callers responsibility to ensure that only users
 // with the proper permissions can access the returned saved searches.
 func (s *savedSearches) ListAll(ctx context.Context) (savedSearches []api.SavedQuerySpecAndConfig, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "ListAll")
 	if Mocks.SavedSearches.ListAll != nil {
 		return Mocks.SavedSearches.ListAll(ctx)
 	}
@@ -93,6 +96,7 @@ func (s *savedSearches) ListAll(ctx context.Context) (savedSearches []api.SavedQ
 // user is an admin. It is the callers responsibility to ensure this response
 // only makes it to users with proper permissions to access the saved search.
 func (s *savedSearches) GetByID(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "GetByID")
 	if Mocks.SavedSearches.GetByID != nil {
 		return Mocks.SavedSearches.GetByID(ctx, id)
 	}
@@ -136,6 +140,7 @@ func (s *savedSearches) GetByID(ctx context.Context, id int32) (*api.SavedQueryS
 // specified user or users with proper permissions can access the returned
 // saved searches.
 func (s *savedSearches) ListSavedSearchesByUserID(ctx context.Context, userID int32) ([]*types.SavedSearch, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "ListSavedSearchesByUserID")
 	if Mocks.SavedSearches.ListSavedSearchesByUserID != nil {
 		return Mocks.SavedSearches.ListSavedSearchesByUserID(ctx, userID)
 	}
@@ -191,6 +196,7 @@ func (s *savedSearches) ListSavedSearchesByUserID(ctx context.Context, userID in
 // members of the specified organization can access the returned saved
 // searches.
 func (s *savedSearches) ListSavedSearchesByOrgID(ctx context.Context, orgID int32) ([]*types.SavedSearch, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "ListSavedSearchesByOrgID")
 	var savedSearches []*types.SavedSearch
 	conds := sqlf.Sprintf("WHERE org_id=%d", orgID)
 	query := sqlf.Sprintf(`SELECT
@@ -225,6 +231,7 @@ func (s *savedSearches) ListSavedSearchesByOrgID(ctx context.Context, orgID int3
 // user is an admin. It is the callers responsibility to ensure the user has
 // proper permissions to create the saved search.
 func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedSearch) (savedQuery *types.SavedSearch, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "Create")
 	if Mocks.SavedSearches.Create != nil {
 		return Mocks.SavedSearches.Create(ctx, newSavedSearch)
 	}
@@ -248,7 +255,14 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 		OrgID:       newSavedSearch.OrgID,
 	}
 
-	err = dbconn.Global.QueryRowContext(ctx, `INSERT INTO saved_searches(
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if newSavedSearch.Notify || newSavedSearch.NotifySlack {
+			if err := Quotas.Check(ctx, tx, savedSearchQuotaNamespace(newSavedSearch.UserID, newSavedSearch.OrgID), QuotaSavedSearchNotifications, 1); err != nil {
+				return err
+			}
+		}
+
+		return tx.QueryRowContext(ctx, `INSERT INTO saved_searches(
 			description,
 			query,
 			notify_owner,
@@ -256,13 +270,14 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 			user_id,
 			org_id
 		) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
-		newSavedSearch.Description,
-		newSavedSearch.Query,
-		newSavedSearch.Notify,
-		newSavedSearch.NotifySlack,
-		newSavedSearch.UserID,
-		newSavedSearch.OrgID,
-	).Scan(&savedQuery.ID)
+			newSavedSearch.Description,
+			newSavedSearch.Query,
+			newSavedSearch.Notify,
+			newSavedSearch.NotifySlack,
+			newSavedSearch.UserID,
+			newSavedSearch.OrgID,
+		).Scan(&savedQuery.ID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +290,7 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 // user is an admin. It is the callers responsibility to ensure the user has
 // proper permissions to perform the update.
 func (s *savedSearches) Update(ctx context.Context, savedSearch *types.SavedSearch) (savedQuery *types.SavedSearch, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "Update")
 	if Mocks.SavedSearches.Update != nil {
 		return Mocks.SavedSearches.Update(ctx, savedSearch)
 	}
@@ -307,18 +323,50 @@ func (s *savedSearches) Update(ctx context.Context, savedSearch *types.SavedSear
 	}
 
 	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
-	if err := dbconn.Global.QueryRowContext(ctx, updateQuery.Query(sqlf.PostgresBindVar), updateQuery.Args()...).Scan(&savedQuery.ID); err != nil {
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if savedSearch.Notify || savedSearch.NotifySlack {
+			// Enabling notifications (or moving a saved search with notifications to another owner)
+			// counts against the owner's quota.
+			var oldNotify bool
+			var oldUserID, oldOrgID *int32
+			if err := tx.QueryRowContext(ctx, "SELECT notify_owner OR notify_slack, user_id, org_id FROM saved_searches WHERE id=$1", savedSearch.ID).Scan(&oldNotify, &oldUserID, &oldOrgID); err != nil {
+				return err
+			}
+			ns := savedSearchQuotaNamespace(savedSearch.UserID, savedSearch.OrgID)
+			if !oldNotify || savedSearchQuotaNamespace(oldUserID, oldOrgID) != ns {
+				if err := Quotas.Check(ctx, tx, ns, QuotaSavedSearchNotifications, 1); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.QueryRowContext(ctx, updateQuery.Query(sqlf.PostgresBindVar), updateQuery.Args()...).Scan(&savedQuery.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return savedQuery, nil
 }
 
+// savedSearchQuotaNamespace returns the namespace whose quota a saved search counts against.
+func savedSearchQuotaNamespace(userID, orgID *int32) QuotaNamespace {
+	switch {
+	case orgID != nil:
+		return QuotaNamespace{OrgID: *orgID}
+	case userID != nil:
+		return QuotaNamespace{UserID: *userID}
+	default:
+		return QuotaNamespace{}
+	}
+}
+
 // Delete hard-deletes an existing saved search.
 //
 // 🚨 SECURITY: This method does NOT verify the user's identity or that the
 // user is an admin. It is the callers responsibility to ensure the user has
 // proper permissions to perform the delete.
 func (s *savedSearches) Delete(ctx context.Context, id int32) (err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SavedSearches", "Delete")
 	if Mocks.SavedSearches.Delete != nil {
 		return Mocks.SavedSearches.Delete(ctx, id)
 	}
@@ -17,6 +17,7 @@ import (
 type settings struct{}
 
 func (o *settings) CreateIfUpToDate(ctx context.Context, subject api.SettingsSubject, lastID *int32, authorUserID *int32, contents string) (latestSetting *api.Settings, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Settings", "CreateIfUpToDate")
 	if Mocks.Settings.CreateIfUpToDate != nil {
 		return Mocks.Settings.CreateIfUpToDate(ctx, subject, lastID, authorUserID, contents)
 	}
@@ -59,7 +60,7 @@ func (o *settings) CreateIfUpToDate(ctx context.Context, subject api.SettingsSub
 
 	creatorIsUpToDate := latestSetting != nil && lastID != nil && latestSetting.ID == *lastID
 	if latestSetting == nil || creatorIsUpToDate {
-		err := tx.QueryRow(
+		err := tx.QueryRowContext(ctx,
 			"INSERT INTO settings(org_id, user_id, author_user_id, contents) VALUES($1, $2, $3, $4) RETURNING id, created_at",
 			s.Subject.Org, s.Subject.User, s.AuthorUserID, s.Contents).Scan(&s.ID, &s.CreatedAt)
 		if err != nil {
@@ -72,6 +73,7 @@ func (o *settings) CreateIfUpToDate(ctx context.Context, subject api.SettingsSub
 }
 
 func (o *settings) GetLatest(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Settings", "GetLatest")
 	if Mocks.Settings.GetLatest != nil {
 		return Mocks.Settings.GetLatest(ctx, subject)
 	}
@@ -91,6 +93,7 @@ func (o *settings) GetLatest(ctx context.Context, subject api.SettingsSubject) (
 // 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
 // responsible for ensuring this or that the response never makes it to a user.
 func (o *settings) ListAll(ctx context.Context, impreciseSubstring string) (_ []*api.Settings, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Settings", "ListAll")
 	tr, ctx := trace.New(ctx, "db.Settings.ListAll", "")
 	defer func() {
 		tr.SetError(err)
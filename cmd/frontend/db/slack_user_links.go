@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// slackUserLinks provides access to the `slack_user_links` table.
+type slackUserLinks struct{}
+
+// SlackUserLinkNotFoundError occurs when a Slack user is not linked to a Sourcegraph user.
+type SlackUserLinkNotFoundError struct {
+	args []interface{}
+}
+
+// NotFound implements errcode.NotFounder.
+func (err SlackUserLinkNotFoundError) NotFound() bool { return true }
+
+func (err SlackUserLinkNotFoundError) Error() string {
+	return fmt.Sprintf("slack user link not found: %v", err.args)
+}
+
+// Link links the Slack user (of the Slack workspace with the given team ID) to the Sourcegraph user,
+// replacing any existing link of the Slack user.
+//
+// 🚨 SECURITY: The caller must ensure that the Slack user was authenticated (with OAuth) by the
+// Sourcegraph user.
+func (*slackUserLinks) Link(ctx context.Context, teamID, slackUserID string, userID int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "SlackUserLinks", "Link")
+	if Mocks.SlackUserLinks.Link != nil {
+		return Mocks.SlackUserLinks.Link(ctx, teamID, slackUserID, userID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO slack_user_links(team_id, slack_user_id, user_id) VALUES($1, $2, $3)
+ON CONFLICT (team_id, slack_user_id) DO UPDATE SET user_id=EXCLUDED.user_id, created_at=now()`,
+		teamID, slackUserID, userID)
+	return err
+}
+
+// GetUserID returns the ID of the Sourcegraph user that the Slack user is linked to.
+func (*slackUserLinks) GetUserID(ctx context.Context, teamID, slackUserID string) (int32, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SlackUserLinks", "GetUserID")
+	if Mocks.SlackUserLinks.GetUserID != nil {
+		return Mocks.SlackUserLinks.GetUserID(ctx, teamID, slackUserID)
+	}
+
+	var userID int32
+	if err := dbconn.Global.QueryRowContext(ctx, `
+SELECT l.user_id FROM slack_user_links l
+JOIN users u ON u.id=l.user_id
+WHERE l.team_id=$1 AND l.slack_user_id=$2 AND u.deleted_at IS NULL`,
+		teamID, slackUserID).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, SlackUserLinkNotFoundError{[]interface{}{teamID, slackUserID}}
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+// UnlinkUser removes all links of Slack users to the Sourcegraph user.
+func (*slackUserLinks) UnlinkUser(ctx context.Context, userID int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "SlackUserLinks", "UnlinkUser")
+	if Mocks.SlackUserLinks.UnlinkUser != nil {
+		return Mocks.SlackUserLinks.UnlinkUser(ctx, userID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM slack_user_links WHERE user_id=$1", userID)
+	return err
+}
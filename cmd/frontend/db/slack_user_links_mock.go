@@ -0,0 +1,9 @@
+package db
+
+import "context"
+
+type MockSlackUserLinks struct {
+	Link       func(ctx context.Context, teamID, slackUserID string, userID int32) error
+	GetUserID  func(ctx context.Context, teamID, slackUserID string) (int32, error)
+	UnlinkUser func(ctx context.Context, userID int32) error
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestSlackUserLinks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	u1, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u2, err := Users.Create(ctx, NewUser{Username: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SlackUserLinks.GetUserID(ctx, "T1", "U1"); !errcode.IsNotFound(err) {
+		t.Errorf("got err %v, want errcode.IsNotFound", err)
+	}
+
+	// Linking again replaces the existing link.
+	for _, userID := range []int32{u1.ID, u2.ID} {
+		if err := SlackUserLinks.Link(ctx, "T1", "U1", userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if userID, err := SlackUserLinks.GetUserID(ctx, "T1", "U1"); err != nil {
+		t.Fatal(err)
+	} else if userID != u2.ID {
+		t.Errorf("got user ID %d, want %d", userID, u2.ID)
+	}
+
+	// Links of deleted users are ignored.
+	if err := Users.Delete(ctx, u2.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SlackUserLinks.GetUserID(ctx, "T1", "U1"); !errcode.IsNotFound(err) {
+		t.Errorf("got err %v, want errcode.IsNotFound", err)
+	}
+}
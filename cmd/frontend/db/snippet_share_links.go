@@ -0,0 +1,408 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A SnippetShareLink is a link that lets anyone who knows its secret token view a range of lines of
+// a file at a fixed commit, without authentication.
+type SnippetShareLink struct {
+	ID            int64
+	CreatorUserID int32
+	RepoID        api.RepoID
+	CommitID      api.CommitID
+	Path          string
+	StartLine     int32 // 1-based
+	EndLine       int32 // 1-based, inclusive
+	HasPassword   bool
+	MaxViews      *int32 // the maximum number of views (nil if unlimited)
+	ViewCount     int32
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	RevokedAt     *time.Time
+	RevokerUserID *int32
+}
+
+// SnippetShareLinkViewOutcome is the outcome of an attempt to view a snippet share link.
+type SnippetShareLinkViewOutcome string
+
+const (
+	SnippetShareLinkViewed           SnippetShareLinkViewOutcome = "viewed"
+	SnippetShareLinkWrongPassword    SnippetShareLinkViewOutcome = "wrong_password"
+	SnippetShareLinkRevoked          SnippetShareLinkViewOutcome = "revoked"
+	SnippetShareLinkExpired          SnippetShareLinkViewOutcome = "expired"
+	SnippetShareLinkViewLimitReached SnippetShareLinkViewOutcome = "view_limit_reached"
+)
+
+// Inactive returns the reason why the link can no longer be viewed at the given time, or the empty
+// string if it can be viewed.
+func (l *SnippetShareLink) Inactive(now time.Time) SnippetShareLinkViewOutcome {
+	switch {
+	case l.RevokedAt != nil:
+		return SnippetShareLinkRevoked
+	case !now.Before(l.ExpiresAt):
+		return SnippetShareLinkExpired
+	case l.MaxViews != nil && l.ViewCount >= *l.MaxViews:
+		return SnippetShareLinkViewLimitReached
+	}
+	return ""
+}
+
+// A SnippetShareLinkView is an entry of the audit log of attempts to view a snippet share link.
+type SnippetShareLinkView struct {
+	ID         int64
+	LinkID     int64
+	Outcome    SnippetShareLinkViewOutcome
+	RemoteAddr string
+	UserAgent  string
+	CreatedAt  time.Time
+}
+
+// snippetShareLinks provides access to the `snippet_share_links` and `snippet_share_link_views`
+// tables.
+type snippetShareLinks struct{}
+
+// SnippetShareLinkNotFoundError occurs when a snippet share link is not found.
+type SnippetShareLinkNotFoundError struct {
+	args []interface{}
+}
+
+// NotFound implements errcode.NotFounder.
+func (err SnippetShareLinkNotFoundError) NotFound() bool { return true }
+
+func (err SnippetShareLinkNotFoundError) Error() string {
+	return fmt.Sprintf("snippet share link not found: %v", err.args)
+}
+
+// Create creates a snippet share link and returns its secret token. Sourcegraph does not retain the
+// token (only a hash of it), so the caller is responsible for presenting it to the creator. If
+// password is nonempty, viewers must provide it to view the snippet.
+//
+// Like access tokens, the token is a long random string, so its SHA-256 hash is stored (see
+// (*accessTokens).Create). The password is chosen by a user, so it is hashed with bcrypt.
+//
+// 🚨 SECURITY: The caller must ensure that the creator may view the snippet and share it.
+func (*snippetShareLinks) Create(ctx context.Context, l *SnippetShareLink, password string) (token string, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "Create")
+	if Mocks.SnippetShareLinks.Create != nil {
+		return Mocks.SnippetShareLinks.Create(ctx, l, password)
+	}
+
+	var b [20]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	var passwordHash sql.NullString
+	if password != "" {
+		if passwordHash, err = hashPassword(password); err != nil {
+			return "", err
+		}
+	}
+
+	if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO snippet_share_links(token_sha256, creator_user_id, repo_id, commit_id, path, start_line, end_line, password_hash, max_views, expires_at)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, created_at`,
+		toSHA256Bytes(b[:]), l.CreatorUserID, l.RepoID, l.CommitID, l.Path, l.StartLine, l.EndLine, passwordHash, l.MaxViews, l.ExpiresAt,
+	).Scan(&l.ID, &l.CreatedAt); err != nil {
+		return "", err
+	}
+	l.HasPassword = passwordHash.Valid
+	return hex.EncodeToString(b[:]), nil
+}
+
+// GetByID retrieves the snippet share link (if any) given its ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view this link.
+func (s *snippetShareLinks) GetByID(ctx context.Context, id int64) (*SnippetShareLink, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "GetByID")
+	if Mocks.SnippetShareLinks.GetByID != nil {
+		return Mocks.SnippetShareLinks.GetByID(ctx, id)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("id=%d", id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, SnippetShareLinkNotFoundError{[]interface{}{id}}
+	}
+	return results[0], nil
+}
+
+// GetByToken retrieves the snippet share link (if any) given its secret token, regardless of
+// whether it is still active.
+//
+// 🚨 SECURITY: Knowing the token is what permits viewing the snippet, so the caller must check that
+// the link is active (and the password, if any) before revealing it.
+func (s *snippetShareLinks) GetByToken(ctx context.Context, token string) (*SnippetShareLink, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "GetByToken")
+	if Mocks.SnippetShareLinks.GetByToken != nil {
+		return Mocks.SnippetShareLinks.GetByToken(ctx, token)
+	}
+
+	b, err := hex.DecodeString(token)
+	if err != nil {
+		return nil, SnippetShareLinkNotFoundError{[]interface{}{"invalid token"}}
+	}
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("token_sha256=%s", toSHA256Bytes(b))}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, SnippetShareLinkNotFoundError{[]interface{}{"token"}}
+	}
+	return results[0], nil
+}
+
+// SnippetShareLinksListOptions contains options for listing snippet share links.
+type SnippetShareLinksListOptions struct {
+	CreatorUserID int32 // only list links created by this user
+	OnlyActive    bool  // only list links that are not revoked or expired and below their view limit
+	*LimitOffset
+}
+
+func (o SnippetShareLinksListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.CreatorUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("creator_user_id=%d", o.CreatorUserID))
+	}
+	if o.OnlyActive {
+		conds = append(conds, snippetShareLinkActiveCond)
+	}
+	return conds
+}
+
+var snippetShareLinkActiveCond = sqlf.Sprintf("revoked_at IS NULL AND expires_at > now() AND (max_views IS NULL OR view_count < max_views)")
+
+// List lists all snippet share links that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
+// options.
+func (s *snippetShareLinks) List(ctx context.Context, opt SnippetShareLinksListOptions) ([]*SnippetShareLink, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "List")
+	if Mocks.SnippetShareLinks.List != nil {
+		return Mocks.SnippetShareLinks.List(ctx, opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (*snippetShareLinks) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*SnippetShareLink, error) {
+	q := sqlf.Sprintf(`
+SELECT id, creator_user_id, repo_id, commit_id, path, start_line, end_line, password_hash IS NOT NULL, max_views, view_count, expires_at, created_at, revoked_at, revoker_user_id FROM snippet_share_links
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*SnippetShareLink
+	for rows.Next() {
+		var l SnippetShareLink
+		if err := rows.Scan(&l.ID, &l.CreatorUserID, &l.RepoID, &l.CommitID, &l.Path, &l.StartLine, &l.EndLine, &l.HasPassword, &l.MaxViews, &l.ViewCount, &l.ExpiresAt, &l.CreatedAt, &l.RevokedAt, &l.RevokerUserID); err != nil {
+			return nil, err
+		}
+		results = append(results, &l)
+	}
+	return results, rows.Err()
+}
+
+// Count counts all snippet share links that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to count the links.
+func (*snippetShareLinks) Count(ctx context.Context, opt SnippetShareLinksListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "Count")
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM snippet_share_links WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Revoke revokes the snippet share link, so that it can no longer be viewed. Revoking a link that
+// is already revoked has no effect.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to revoke the link.
+func (*snippetShareLinks) Revoke(ctx context.Context, id int64, revokerUserID int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "Revoke")
+	if Mocks.SnippetShareLinks.Revoke != nil {
+		return Mocks.SnippetShareLinks.Revoke(ctx, id, revokerUserID)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE snippet_share_links SET revoked_at=COALESCE(revoked_at, now()), revoker_user_id=COALESCE(revoker_user_id, $2) WHERE id=$1", id, revokerUserID)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return SnippetShareLinkNotFoundError{[]interface{}{id}}
+	}
+	return nil
+}
+
+// ErrSnippetShareLinkPasswordThrottled is returned by CheckPassword when there have been too many
+// incorrect password attempts for the link recently.
+var ErrSnippetShareLinkPasswordThrottled = errors.New("too many incorrect passwords for snippet share link")
+
+const (
+	// snippetShareLinkMaxWrongPasswords is the number of incorrect password attempts for a link
+	// within snippetShareLinkWrongPasswordWindow after which CheckPassword rejects all attempts.
+	snippetShareLinkMaxWrongPasswords   = 5
+	snippetShareLinkWrongPasswordWindow = 15 * time.Minute
+)
+
+// CheckPassword reports whether password is the password of the snippet share link. It is false
+// if the link has no password. An incorrect password is recorded in the link's audit log.
+//
+// 🚨 SECURITY: To prevent guessing passwords, it returns ErrSnippetShareLinkPasswordThrottled
+// without checking the password if the link's audit log has too many recent incorrect attempts.
+// Attempts for the same link are serialized, so concurrent attempts can't exceed the limit.
+func (*snippetShareLinks) CheckPassword(ctx context.Context, id int64, password, remoteAddr, userAgent string) (ok bool, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "CheckPassword")
+	if Mocks.SnippetShareLinks.CheckPassword != nil {
+		return Mocks.SnippetShareLinks.CheckPassword(ctx, id, password, remoteAddr, userAgent)
+	}
+
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		var hash sql.NullString
+		if err := tx.QueryRowContext(ctx, "SELECT password_hash FROM snippet_share_links WHERE id=$1 FOR UPDATE", id).Scan(&hash); err != nil {
+			if err == sql.ErrNoRows {
+				return SnippetShareLinkNotFoundError{[]interface{}{id}}
+			}
+			return err
+		}
+
+		q := sqlf.Sprintf("SELECT COUNT(*) FROM snippet_share_link_views WHERE link_id=%d AND outcome=%s AND created_at>%s", id, SnippetShareLinkWrongPassword, time.Now().Add(-snippetShareLinkWrongPasswordWindow))
+		var wrong int
+		if err := tx.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&wrong); err != nil {
+			return err
+		}
+		if wrong >= snippetShareLinkMaxWrongPasswords {
+			return ErrSnippetShareLinkPasswordThrottled
+		}
+
+		if ok = hash.Valid && validPassword(hash.String, password); !ok {
+			return logSnippetShareLinkView(ctx, tx, id, SnippetShareLinkWrongPassword, remoteAddr, userAgent)
+		}
+		return nil
+	})
+	return ok, err
+}
+
+// View records an attempt to view the snippet share link in its audit log. If the link is active,
+// its view count is incremented and SnippetShareLinkViewed is returned. Otherwise, the reason why it
+// is inactive is returned.
+//
+// 🚨 SECURITY: The caller must only reveal the snippet if SnippetShareLinkViewed is returned. The
+// view count is checked and incremented atomically, so concurrent views can't exceed the limit.
+func (*snippetShareLinks) View(ctx context.Context, id int64, remoteAddr, userAgent string) (outcome SnippetShareLinkViewOutcome, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "View")
+	if Mocks.SnippetShareLinks.View != nil {
+		return Mocks.SnippetShareLinks.View(ctx, id, remoteAddr, userAgent)
+	}
+
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		q := sqlf.Sprintf(`
+UPDATE snippet_share_links SET view_count=view_count+1
+WHERE id=%d AND %s
+RETURNING id`, id, snippetShareLinkActiveCond)
+		var ignore int64
+		switch err := tx.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&ignore); err {
+		case nil:
+			outcome = SnippetShareLinkViewed
+		case sql.ErrNoRows:
+			var l SnippetShareLink
+			if err := tx.QueryRowContext(ctx, "SELECT max_views, view_count, expires_at, revoked_at FROM snippet_share_links WHERE id=$1", id).Scan(&l.MaxViews, &l.ViewCount, &l.ExpiresAt, &l.RevokedAt); err != nil {
+				if err == sql.ErrNoRows {
+					return SnippetShareLinkNotFoundError{[]interface{}{id}}
+				}
+				return err
+			}
+			if outcome = l.Inactive(time.Now()); outcome == "" {
+				return errors.New("snippet share link is active but could not be viewed")
+			}
+		default:
+			return err
+		}
+		return logSnippetShareLinkView(ctx, tx, id, outcome, remoteAddr, userAgent)
+	})
+	return outcome, err
+}
+
+// LogView records an attempt to view the snippet share link that failed with the given outcome in
+// its audit log.
+func (*snippetShareLinks) LogView(ctx context.Context, id int64, outcome SnippetShareLinkViewOutcome, remoteAddr, userAgent string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "LogView")
+	if Mocks.SnippetShareLinks.LogView != nil {
+		return Mocks.SnippetShareLinks.LogView(ctx, id, outcome, remoteAddr, userAgent)
+	}
+	return logSnippetShareLinkView(ctx, dbconn.Global, id, outcome, remoteAddr, userAgent)
+}
+
+func logSnippetShareLinkView(ctx context.Context, dbh interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, id int64, outcome SnippetShareLinkViewOutcome, remoteAddr, userAgent string) error {
+	_, err := dbh.ExecContext(ctx, "INSERT INTO snippet_share_link_views(link_id, outcome, remote_addr, user_agent) VALUES($1, $2, $3, $4)", id, outcome, remoteAddr, userAgent)
+	return err
+}
+
+// ListViews lists the audit log of attempts to view the snippet share link, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view the link's audit log.
+func (*snippetShareLinks) ListViews(ctx context.Context, id int64, limitOffset *LimitOffset) ([]*SnippetShareLinkView, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "ListViews")
+	q := sqlf.Sprintf(`
+SELECT id, link_id, outcome, remote_addr, user_agent, created_at FROM snippet_share_link_views
+WHERE link_id=%d
+ORDER BY id DESC
+%s`, id, limitOffset.SQL())
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []*SnippetShareLinkView
+	for rows.Next() {
+		var v SnippetShareLinkView
+		if err := rows.Scan(&v.ID, &v.LinkID, &v.Outcome, &v.RemoteAddr, &v.UserAgent, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		views = append(views, &v)
+	}
+	return views, rows.Err()
+}
+
+// CountViews counts the entries of the audit log of attempts to view the snippet share link.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view the link's audit log.
+func (*snippetShareLinks) CountViews(ctx context.Context, id int64) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SnippetShareLinks", "CountViews")
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM snippet_share_link_views WHERE link_id=$1", id).Scan(&count)
+	return count, err
+}
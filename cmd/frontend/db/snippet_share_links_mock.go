@@ -0,0 +1,14 @@
+package db
+
+import "context"
+
+type MockSnippetShareLinks struct {
+	Create        func(ctx context.Context, l *SnippetShareLink, password string) (string, error)
+	GetByID       func(ctx context.Context, id int64) (*SnippetShareLink, error)
+	GetByToken    func(ctx context.Context, token string) (*SnippetShareLink, error)
+	List          func(ctx context.Context, opt SnippetShareLinksListOptions) ([]*SnippetShareLink, error)
+	Revoke        func(ctx context.Context, id int64, revokerUserID int32) error
+	CheckPassword func(ctx context.Context, id int64, password, remoteAddr, userAgent string) (bool, error)
+	View          func(ctx context.Context, id int64, remoteAddr, userAgent string) (SnippetShareLinkViewOutcome, error)
+	LogView       func(ctx context.Context, id int64, outcome SnippetShareLinkViewOutcome, remoteAddr, userAgent string) error
+}
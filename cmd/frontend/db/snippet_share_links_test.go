@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// 🚨 SECURITY: This tests the routines that grant anonymous users access to snippets.
+func TestSnippetShareLinks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "github.com/acme/a", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "github.com/acme/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maxViews := int32(2)
+	link := &SnippetShareLink{
+		CreatorUserID: user.ID,
+		RepoID:        repo.ID,
+		CommitID:      "0123456789012345678901234567890123456789",
+		Path:          "a.go",
+		StartLine:     3,
+		EndLine:       5,
+		MaxViews:      &maxViews,
+		ExpiresAt:     time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond),
+	}
+	token, err := SnippetShareLinks.Create(ctx, link, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("GetByToken", func(t *testing.T) {
+		if l, err := SnippetShareLinks.GetByToken(ctx, token); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(l, link) {
+			t.Errorf("got %+v, want %+v", l, link)
+		}
+		if _, err := SnippetShareLinks.GetByToken(ctx, "abcd"); !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+	})
+
+	t.Run("CheckPassword", func(t *testing.T) {
+		for password, want := range map[string]bool{"secret": true, "guess": false, "": false} {
+			if ok, err := SnippetShareLinks.CheckPassword(ctx, link.ID, password, "127.0.0.1", "test"); err != nil {
+				t.Fatal(err)
+			} else if ok != want {
+				t.Errorf("password %q: got %v, want %v", password, ok, want)
+			}
+		}
+
+		// After too many incorrect passwords, even the correct password is rejected.
+		for i := 2; i < snippetShareLinkMaxWrongPasswords; i++ {
+			if _, err := SnippetShareLinks.CheckPassword(ctx, link.ID, "guess", "127.0.0.1", "test"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := SnippetShareLinks.CheckPassword(ctx, link.ID, "secret", "127.0.0.1", "test"); err != ErrSnippetShareLinkPasswordThrottled {
+			t.Errorf("got err %v, want %v", err, ErrSnippetShareLinkPasswordThrottled)
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM snippet_share_link_views WHERE link_id=$1", link.ID); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("View", func(t *testing.T) {
+		// Concurrent views must not exceed the view limit.
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			outcomes = map[SnippetShareLinkViewOutcome]int{}
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				outcome, err := SnippetShareLinks.View(ctx, link.ID, "127.0.0.1", "test")
+				if err != nil {
+					t.Error(err)
+				}
+				mu.Lock()
+				outcomes[outcome]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		if want := map[SnippetShareLinkViewOutcome]int{SnippetShareLinkViewed: 2, SnippetShareLinkViewLimitReached: 3}; !reflect.DeepEqual(outcomes, want) {
+			t.Errorf("got outcomes %v, want %v", outcomes, want)
+		}
+		if n, err := SnippetShareLinks.CountViews(ctx, link.ID); err != nil {
+			t.Fatal(err)
+		} else if n != 5 {
+			t.Errorf("got %d audit log entries, want 5", n)
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		if err := SnippetShareLinks.Revoke(ctx, link.ID, user.ID); err != nil {
+			t.Fatal(err)
+		}
+		if outcome, err := SnippetShareLinks.View(ctx, link.ID, "127.0.0.1", "test"); err != nil {
+			t.Fatal(err)
+		} else if outcome != SnippetShareLinkRevoked {
+			t.Errorf("got outcome %q, want %q", outcome, SnippetShareLinkRevoked)
+		}
+		if links, err := SnippetShareLinks.List(ctx, SnippetShareLinksListOptions{CreatorUserID: user.ID, OnlyActive: true}); err != nil {
+			t.Fatal(err)
+		} else if len(links) != 0 {
+			t.Errorf("got %d active links, want 0", len(links))
+		}
+	})
+}
@@ -25,4 +25,25 @@ var (
 	OrgInvitations = &orgInvitations{}
 
 	Authz AuthzStore = &authzStore{}
+
+	SubRepoPerms = &subRepoPerms{}
+
+	RepoAccessRequests = &repoAccessRequests{}
+	RepoAccessGrants   = &repoAccessGrants{}
+
+	SnippetShareLinks = &snippetShareLinks{}
+
+	CoverageReports = &coverageReports{}
+
+	SlackUserLinks = &slackUserLinks{}
+
+	Quotas = &quotas{}
+
+	Watches = &watches{}
+
+	PullRequests = &pullRequests{}
+
+	CodeClones = &codeClones{}
+
+	CodeExpertise = &codeExpertise{}
 )
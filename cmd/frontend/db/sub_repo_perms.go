@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// subRepoPerms provides access to the `sub_repo_path_rules` table, which stores explicitly
+// configured path-level permission rules within repositories.
+type subRepoPerms struct{}
+
+// Upsert creates or replaces the rule for the given repository and path prefix. The path prefix
+// is normalized before storing it.
+//
+// 🚨 SECURITY: This method does NOT verify the user's identity or that the user is an admin. It
+// is the callers responsibility to ensure only site admins can modify path rules.
+func (*subRepoPerms) Upsert(ctx context.Context, rule *types.SubRepoPathRule) error {
+	ctx = dbconn.WithQueryLabels(ctx, "SubRepoPerms", "Upsert")
+	if Mocks.SubRepoPerms.Upsert != nil {
+		return Mocks.SubRepoPerms.Upsert(ctx, rule)
+	}
+
+	rule.PathPrefix = authz.CleanRulePath(rule.PathPrefix)
+	q := sqlf.Sprintf(`
+INSERT INTO sub_repo_path_rules (repo_id, path_prefix, user_ids, org_ids)
+VALUES (%s, %s, %s, %s)
+ON CONFLICT (repo_id, path_prefix) DO UPDATE SET
+	user_ids = excluded.user_ids,
+	org_ids = excluded.org_ids,
+	updated_at = now()
+RETURNING id, created_at, updated_at
+`, rule.RepoID, rule.PathPrefix, pq.Array(int32sToInt64s(rule.UserIDs)), pq.Array(int32sToInt64s(rule.OrgIDs)))
+
+	return dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).
+		Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// Delete removes the rule for the given repository and path prefix. It is not an error if no
+// such rule exists.
+//
+// 🚨 SECURITY: This method does NOT verify the user's identity or that the user is an admin. It
+// is the callers responsibility to ensure only site admins can modify path rules.
+func (*subRepoPerms) Delete(ctx context.Context, repoID api.RepoID, pathPrefix string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "SubRepoPerms", "Delete")
+	if Mocks.SubRepoPerms.Delete != nil {
+		return Mocks.SubRepoPerms.Delete(ctx, repoID, pathPrefix)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx,
+		"DELETE FROM sub_repo_path_rules WHERE repo_id=$1 AND path_prefix=$2",
+		repoID, authz.CleanRulePath(pathPrefix))
+	return err
+}
+
+// ListByRepo returns all rules of the given repository, ordered by path prefix.
+//
+// 🚨 SECURITY: This method does NOT verify the user's identity or that the user is an admin. The
+// rules themselves reveal the names of restricted paths, so it is the callers responsibility to
+// ensure only site admins can view them.
+func (*subRepoPerms) ListByRepo(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SubRepoPerms", "ListByRepo")
+	if Mocks.SubRepoPerms.ListByRepo != nil {
+		return Mocks.SubRepoPerms.ListByRepo(ctx, repoID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT id, repo_id, path_prefix, user_ids, org_ids, created_at, updated_at
+FROM sub_repo_path_rules
+WHERE repo_id=$1
+ORDER BY path_prefix ASC`, repoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryContext")
+	}
+	defer rows.Close()
+
+	var rules []*types.SubRepoPathRule
+	for rows.Next() {
+		var (
+			r               types.SubRepoPathRule
+			userIDs, orgIDs pq.Int64Array
+		)
+		if err := rows.Scan(&r.ID, &r.RepoID, &r.PathPrefix, &userIDs, &orgIDs, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		r.UserIDs = int64sToInt32s(userIDs)
+		r.OrgIDs = int64sToInt32s(orgIDs)
+		rules = append(rules, &r)
+	}
+	return rules, rows.Err()
+}
+
+func int32sToInt64s(in []int32) []int64 {
+	out := make([]int64, len(in))
+	for i, v := range in {
+		out[i] = int64(v)
+	}
+	return out
+}
+
+func int64sToInt32s(in []int64) []int32 {
+	out := make([]int32, len(in))
+	for i, v := range in {
+		out[i] = int32(v)
+	}
+	return out
+}
@@ -0,0 +1,14 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type MockSubRepoPerms struct {
+	Upsert     func(ctx context.Context, rule *types.SubRepoPathRule) error
+	Delete     func(ctx context.Context, repoID api.RepoID, pathPrefix string) error
+	ListByRepo func(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error)
+}
@@ -21,6 +21,7 @@ type surveyResponses struct{}
 
 // Create creates a survey response.
 func (s *surveyResponses) Create(ctx context.Context, userID *int32, email *string, score int, reason *string, better *string) (id int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "Create")
 	err = dbconn.Global.QueryRowContext(ctx,
 		"INSERT INTO survey_responses(user_id, email, score, reason, better) VALUES($1, $2, $3, $4, $5) RETURNING id",
 		userID, email, score, reason, better,
@@ -51,16 +52,19 @@ func (*surveyResponses) getBySQL(ctx context.Context, query string, args ...inte
 
 // GetAll gets all survey responses.
 func (s *surveyResponses) GetAll(ctx context.Context) ([]*types.SurveyResponse, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "GetAll")
 	return s.getBySQL(ctx, "ORDER BY created_at DESC")
 }
 
 // GetByUserID gets all survey responses by a given user.
 func (s *surveyResponses) GetByUserID(ctx context.Context, userID int32) ([]*types.SurveyResponse, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "GetByUserID")
 	return s.getBySQL(ctx, "WHERE user_id=$1 ORDER BY created_at DESC", userID)
 }
 
 // Count returns the count of all survey responses.
 func (s *surveyResponses) Count(ctx context.Context) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "Count")
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM survey_responses")
 
 	var count int
@@ -70,6 +74,7 @@ func (s *surveyResponses) Count(ctx context.Context) (int, error) {
 
 // Last30DaysAverageScore returns the average score for all surveys submitted in the last 30 days.
 func (s *surveyResponses) Last30DaysAverageScore(ctx context.Context) (float64, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "Last30DaysAverageScore")
 	q := sqlf.Sprintf("SELECT AVG(score) FROM survey_responses WHERE created_at>%s", thirtyDaysAgo())
 
 	var avg sql.NullFloat64
@@ -80,6 +85,7 @@ func (s *surveyResponses) Last30DaysAverageScore(ctx context.Context) (float64,
 // Last30DaysNPS returns the net promoter score for all surveys submitted in the last 30 days.
 // This is calculated as 100*((% of responses that are >= 9) - (% of responses that are <= 6))
 func (s *surveyResponses) Last30DaysNetPromoterScore(ctx context.Context) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "Last30DaysNetPromoterScore")
 	since := thirtyDaysAgo()
 	promotersQ := sqlf.Sprintf("SELECT COUNT(*) FROM survey_responses WHERE created_at>%s AND score>8", since)
 	detractorsQ := sqlf.Sprintf("SELECT COUNT(*) FROM survey_responses WHERE created_at>%s AND score<7", since)
@@ -104,6 +110,7 @@ func (s *surveyResponses) Last30DaysNetPromoterScore(ctx context.Context) (int,
 
 // Last30Count returns the count of surveys submitted in the last 30 days.
 func (s *surveyResponses) Last30DaysCount(ctx context.Context) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "SurveyResponses", "Last30DaysCount")
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM survey_responses WHERE created_at>%s", thirtyDaysAgo())
 
 	var count int
@@ -52,6 +52,7 @@ type userEmails struct{}
 //
 // If the site has not yet been initialized, returns an empty string.
 func (*userEmails) GetInitialSiteAdminEmail(ctx context.Context) (email string, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "GetInitialSiteAdminEmail")
 	if init, err := globalstatedb.SiteInitialized(ctx); err != nil || !init {
 		return "", err
 	}
@@ -64,6 +65,7 @@ func (*userEmails) GetInitialSiteAdminEmail(ctx context.Context) (email string,
 // GetPrimaryEmail gets the oldest email associated with the user, preferring a verified email to an
 // unverified email.
 func (*userEmails) GetPrimaryEmail(ctx context.Context, id int32) (email string, verified bool, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "GetPrimaryEmail")
 	if Mocks.UserEmails.GetPrimaryEmail != nil {
 		return Mocks.UserEmails.GetPrimaryEmail(ctx, id)
 	}
@@ -78,6 +80,7 @@ func (*userEmails) GetPrimaryEmail(ctx context.Context, id int32) (email string,
 
 // Get gets information about the user's associated email address.
 func (*userEmails) Get(ctx context.Context, userID int32, email string) (emailCanonicalCase string, verified bool, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "Get")
 	if Mocks.UserEmails.Get != nil {
 		return Mocks.UserEmails.Get(userID, email)
 	}
@@ -92,12 +95,14 @@ func (*userEmails) Get(ctx context.Context, userID int32, email string) (emailCa
 
 // Add adds new user email. When added, it is always unverified.
 func (*userEmails) Add(ctx context.Context, userID int32, email string, verificationCode *string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "Add")
 	_, err := dbconn.Global.ExecContext(ctx, "INSERT INTO user_emails(user_id, email, verification_code) VALUES($1, $2, $3)", userID, email, verificationCode)
 	return err
 }
 
 // Remove removes a user email. It returns an error if there is no such email associated with the user.
 func (*userEmails) Remove(ctx context.Context, userID int32, email string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "Remove")
 	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_emails WHERE user_id=$1 AND email=$2", userID, email)
 	if err != nil {
 		return err
@@ -116,6 +121,7 @@ func (*userEmails) Remove(ctx context.Context, userID int32, email string) error
 // correct (not the one originally used when creating the user or adding the user email), then it
 // returns false.
 func (*userEmails) Verify(ctx context.Context, userID int32, email, code string) (bool, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "Verify")
 	var dbCode sql.NullString
 	if err := dbconn.Global.QueryRowContext(ctx, "SELECT verification_code FROM user_emails WHERE user_id=$1 AND email=$2", userID, email).Scan(&dbCode); err != nil {
 		return false, err
@@ -137,6 +143,7 @@ func (*userEmails) Verify(ctx context.Context, userID int32, email, code string)
 // SetVerified bypasses the normal email verification code process and manually sets the verified
 // status for an email.
 func (*userEmails) SetVerified(ctx context.Context, userID int32, email string, verified bool) error {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "SetVerified")
 	if Mocks.UserEmails.SetVerified != nil {
 		return Mocks.UserEmails.SetVerified(ctx, userID, email, verified)
 	}
@@ -165,6 +172,7 @@ func (*userEmails) SetVerified(ctx context.Context, userID int32, email string,
 
 // SetLastVerificationSentAt sets the "last_verification_sent_at" column to now() for given email of the user.
 func (*userEmails) SetLastVerificationSentAt(ctx context.Context, userID int32, email string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "SetLastVerificationSentAt")
 	res, err := dbconn.Global.ExecContext(ctx, "UPDATE user_emails SET last_verification_sent_at=now() WHERE user_id=$1 AND email=$2", userID, email)
 	if err != nil {
 		return err
@@ -182,6 +190,7 @@ func (*userEmails) SetLastVerificationSentAt(ctx context.Context, userID int32,
 // GetLatestVerificationSentEmail returns the email with the lastest time of "last_verification_sent_at" column,
 // it excludes rows with "last_verification_sent_at IS NULL".
 func (*userEmails) GetLatestVerificationSentEmail(ctx context.Context, email string) (*UserEmail, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "GetLatestVerificationSentEmail")
 	if Mocks.UserEmails.GetLatestVerificationSentEmail != nil {
 		return Mocks.UserEmails.GetLatestVerificationSentEmail(ctx, email)
 	}
@@ -203,6 +212,7 @@ LIMIT 1
 // GetVerifiedEmails returns a list of verified emails from the candidate list. Some emails are excluded
 // from the results list because of unverified or simply don't exist.
 func (*userEmails) GetVerifiedEmails(ctx context.Context, emails ...string) ([]*UserEmail, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "GetVerifiedEmails")
 	if Mocks.UserEmails.GetVerifiedEmails != nil {
 		return Mocks.UserEmails.GetVerifiedEmails(ctx, emails...)
 	}
@@ -254,6 +264,7 @@ type UserEmailsListOptions struct {
 
 // ListByUser returns a list of emails that are associated to the given user.
 func (*userEmails) ListByUser(ctx context.Context, opt UserEmailsListOptions) ([]*UserEmail, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "UserEmails", "ListByUser")
 	if Mocks.UserEmails.ListByUser != nil {
 		return Mocks.UserEmails.ListByUser(ctx, opt)
 	}
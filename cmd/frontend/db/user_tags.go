@@ -10,6 +10,7 @@ import (
 // error occurs if the user does not exist. Adding a duplicate tag or removing a nonexistent tag is
 // not an error.
 func (*users) SetTag(ctx context.Context, userID int32, tag string, present bool) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "SetTag")
 	var query string
 	if present {
 		// Add tag.
@@ -126,6 +126,7 @@ type NewUser struct {
 // order to avoid a race condition where multiple initial site admins could be created or zero site
 // admins could be created.
 func (u *users) Create(ctx context.Context, info NewUser) (newUser *types.User, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "Create")
 	if Mocks.Users.Create != nil {
 		return Mocks.Users.Create(ctx, info)
 	}
@@ -324,6 +325,7 @@ type UserUpdate struct {
 
 // Update updates a user's profile information.
 func (u *users) Update(ctx context.Context, id int32, update UserUpdate) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "Update")
 	if Mocks.Users.Update != nil {
 		return Mocks.Users.Update(id, update)
 	}
@@ -385,6 +387,7 @@ func (u *users) Update(ctx context.Context, id int32, update UserUpdate) error {
 }
 
 func (u *users) Delete(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "Delete")
 	if Mocks.Users.Delete != nil {
 		return Mocks.Users.Delete(ctx, id)
 	}
@@ -453,6 +456,7 @@ func (u *users) Delete(ctx context.Context, id int32) error {
 }
 
 func (u *users) HardDelete(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "HardDelete")
 	if Mocks.Users.HardDelete != nil {
 		return Mocks.Users.HardDelete(ctx, id)
 	}
@@ -543,6 +547,7 @@ func (u *users) HardDelete(ctx context.Context, id int32) error {
 }
 
 func (u *users) SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "SetIsSiteAdmin")
 	if Mocks.Users.SetIsSiteAdmin != nil {
 		return Mocks.Users.SetIsSiteAdmin(id, isSiteAdmin)
 	}
@@ -556,6 +561,7 @@ func (u *users) SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool)
 // invited too many users, or some other error occurred). If the user has
 // quota remaining, their quota is decremented and ok is true.
 func (u *users) CheckAndDecrementInviteQuota(ctx context.Context, userID int32) (ok bool, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "CheckAndDecrementInviteQuota")
 	if Mocks.Users.CheckAndDecrementInviteQuota != nil {
 		return Mocks.Users.CheckAndDecrementInviteQuota(ctx, userID)
 	}
@@ -577,6 +583,7 @@ func (u *users) CheckAndDecrementInviteQuota(ctx context.Context, userID int32)
 }
 
 func (u *users) GetByID(ctx context.Context, id int32) (*types.User, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "GetByID")
 	if Mocks.Users.GetByID != nil {
 		return Mocks.Users.GetByID(ctx, id)
 	}
@@ -587,6 +594,7 @@ func (u *users) GetByID(ctx context.Context, id int32) (*types.User, error) {
 // has a matching *unverified* email address, they will not be returned by this method. At most one
 // user may have any given verified email address.
 func (u *users) GetByVerifiedEmail(ctx context.Context, email string) (*types.User, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "GetByVerifiedEmail")
 	if Mocks.Users.GetByVerifiedEmail != nil {
 		return Mocks.Users.GetByVerifiedEmail(ctx, email)
 	}
@@ -594,6 +602,7 @@ func (u *users) GetByVerifiedEmail(ctx context.Context, email string) (*types.Us
 }
 
 func (u *users) GetByUsername(ctx context.Context, username string) (*types.User, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "GetByUsername")
 	if Mocks.Users.GetByUsername != nil {
 		return Mocks.Users.GetByUsername(ctx, username)
 	}
@@ -604,6 +613,7 @@ func (u *users) GetByUsername(ctx context.Context, username string) (*types.User
 // GetByUsernames returns a list of users by given usernames. The number of results list could be less
 // than the candidate list due to no user is associated with some usernames.
 func (u *users) GetByUsernames(ctx context.Context, usernames ...string) ([]*types.User, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "GetByUsernames")
 	if Mocks.Users.GetByUsernames != nil {
 		return Mocks.Users.GetByUsernames(ctx, usernames...)
 	}
@@ -623,6 +633,7 @@ func (u *users) GetByUsernames(ctx context.Context, usernames ...string) ([]*typ
 var ErrNoCurrentUser = errors.New("no current user")
 
 func (u *users) GetByCurrentAuthUser(ctx context.Context) (*types.User, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "GetByCurrentAuthUser")
 	if Mocks.Users.GetByCurrentAuthUser != nil {
 		return Mocks.Users.GetByCurrentAuthUser(ctx)
 	}
@@ -639,6 +650,7 @@ func (u *users) GetByCurrentAuthUser(ctx context.Context) (*types.User, error) {
 }
 
 func (u *users) Count(ctx context.Context, opt *UsersListOptions) (int, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "Count")
 	if Mocks.Users.Count != nil {
 		return Mocks.Users.Count(ctx, opt)
 	}
@@ -665,10 +677,13 @@ type UsersListOptions struct {
 
 	Tag string // only include users with this tag
 
+	OnlySiteAdmins bool // only include site admins
+
 	*LimitOffset
 }
 
 func (u *users) List(ctx context.Context, opt *UsersListOptions) (_ []*types.User, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "List")
 	if Mocks.Users.List != nil {
 		return Mocks.Users.List(ctx, opt)
 	}
@@ -710,6 +725,9 @@ func (*users) listSQL(opt UsersListOptions) (conds []*sqlf.Query) {
 	if opt.Tag != "" {
 		conds = append(conds, sqlf.Sprintf("%s::text = ANY(u.tags)", opt.Tag))
 	}
+	if opt.OnlySiteAdmins {
+		conds = append(conds, sqlf.Sprintf("u.site_admin"))
+	}
 	return conds
 }
 
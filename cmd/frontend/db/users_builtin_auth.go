@@ -15,6 +15,7 @@ import (
 )
 
 func (u *users) IsPassword(ctx context.Context, id int32, password string) (bool, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "IsPassword")
 	var passwd sql.NullString
 	if err := dbconn.Global.QueryRowContext(ctx, "SELECT passwd FROM users WHERE deleted_at IS NULL AND id=$1", id).Scan(&passwd); err != nil {
 		return false, err
@@ -31,6 +32,7 @@ var (
 )
 
 func (u *users) RenewPasswordResetCode(ctx context.Context, id int32) (string, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "RenewPasswordResetCode")
 	if _, err := u.GetByID(ctx, id); err != nil {
 		return "", err
 	}
@@ -55,6 +57,7 @@ func (u *users) RenewPasswordResetCode(ctx context.Context, id int32) (string, e
 }
 
 func (u *users) SetPassword(ctx context.Context, id int32, resetCode string, newPassword string) (bool, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "SetPassword")
 	// 🚨 SECURITY: no empty passwords
 	if newPassword == "" {
 		return false, errors.New("new password was empty")
@@ -83,12 +86,14 @@ func (u *users) SetPassword(ctx context.Context, id int32, resetCode string, new
 }
 
 func (u *users) DeletePasswordResetCode(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "DeletePasswordResetCode")
 	_, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET passwd_reset_code=NULL, passwd_reset_time=NULL WHERE id=$1", id)
 	return err
 }
 
 // UpdatePassword updates a user's password given the current password.
 func (u *users) UpdatePassword(ctx context.Context, id int32, oldPassword, newPassword string) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "UpdatePassword")
 	// 🚨 SECURITY: No empty passwords.
 	if oldPassword == "" {
 		return errors.New("old password was empty")
@@ -126,6 +131,7 @@ func (u *users) UpdatePassword(ctx context.Context, id int32, oldPassword, newPa
 // A randomized password is used (instead of an empty password) to avoid bugs where an empty password
 // is considered to be no password. The random password is expected to be irretrievable.
 func (u *users) RandomizePasswordAndClearPasswordResetRateLimit(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Users", "RandomizePasswordAndClearPasswordResetRateLimit")
 	passwd, err := hashPassword(randstring.NewLen(36))
 	if err != nil {
 		return err
@@ -0,0 +1,346 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// A Watch is a user's subscription to the commits that change a repository, or the paths of a
+// repository that match a glob pattern.
+type Watch struct {
+	ID          int32
+	UserID      int32
+	RepoID      api.RepoID
+	PathPattern string // a git pathspec glob (such as "pkg/auth/**"), or empty for the whole repository
+	EmailDigest bool   // whether to email the user a daily digest of the watch's feed entries
+	CreatedAt   time.Time
+}
+
+// A WatchFeedEntry is an entry of a user's activity feed: a commit that changed files matching one
+// of the user's watches.
+type WatchFeedEntry struct {
+	ID         int64
+	WatchID    int32
+	UserID     int32      // the user of the watch (ignored when adding entries)
+	RepoID     api.RepoID // the repository of the watch (ignored when adding entries)
+	CommitID   api.CommitID
+	Subject    string
+	AuthorName string
+	Paths      []string // the files matching the watch that the commit changed
+	CreatedAt  time.Time
+	EmailedAt  *time.Time // when the entry was included in an email digest
+}
+
+// watches provides access to the `watches`, `watch_repo_heads`, and `watch_feed_entries` tables.
+type watches struct{}
+
+// WatchNotFoundError occurs when a watch is not found.
+type WatchNotFoundError struct {
+	args []interface{}
+}
+
+// NotFound implements errcode.NotFounder.
+func (err WatchNotFoundError) NotFound() bool { return true }
+
+func (err WatchNotFoundError) Error() string {
+	return fmt.Sprintf("watch not found: %v", err.args)
+}
+
+// Upsert creates the watch, or updates the email digest setting of the user's existing watch with
+// the same repository and path pattern. The ID and creation time of the watch are set on w.
+//
+// 🚨 SECURITY: The caller must ensure that the user may view the repository.
+func (*watches) Upsert(ctx context.Context, w *Watch) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "Upsert")
+	if Mocks.Watches.Upsert != nil {
+		return Mocks.Watches.Upsert(ctx, w)
+	}
+
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO watches(user_id, repo_id, path_pattern, email_digest) VALUES($1, $2, $3, $4)
+ON CONFLICT (user_id, repo_id, path_pattern) DO UPDATE SET email_digest=EXCLUDED.email_digest
+RETURNING id, created_at`,
+		w.UserID, w.RepoID, w.PathPattern, w.EmailDigest,
+	).Scan(&w.ID, &w.CreatedAt)
+}
+
+// GetByID retrieves the watch (if any) given its ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view this watch.
+func (s *watches) GetByID(ctx context.Context, id int32) (*Watch, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "GetByID")
+	if Mocks.Watches.GetByID != nil {
+		return Mocks.Watches.GetByID(ctx, id)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("id=%d", id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, WatchNotFoundError{[]interface{}{id}}
+	}
+	return results[0], nil
+}
+
+// WatchesListOptions contains options for listing watches.
+type WatchesListOptions struct {
+	UserID int32      // only list watches of this user
+	RepoID api.RepoID // only list watches of this repository
+	*LimitOffset
+}
+
+func (o WatchesListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id=%d", o.UserID))
+	}
+	if o.RepoID != 0 {
+		conds = append(conds, sqlf.Sprintf("repo_id=%d", o.RepoID))
+	}
+	return conds
+}
+
+// List lists all watches that satisfy the options, oldest first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
+// options.
+func (s *watches) List(ctx context.Context, opt WatchesListOptions) ([]*Watch, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "List")
+	if Mocks.Watches.List != nil {
+		return Mocks.Watches.List(ctx, opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (*watches) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*Watch, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, repo_id, path_pattern, email_digest, created_at FROM watches
+WHERE (%s)
+ORDER BY id ASC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Watch
+	for rows.Next() {
+		var w Watch
+		if err := rows.Scan(&w.ID, &w.UserID, &w.RepoID, &w.PathPattern, &w.EmailDigest, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &w)
+	}
+	return results, rows.Err()
+}
+
+// Delete deletes the watch and its feed entries.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to delete the watch.
+func (*watches) Delete(ctx context.Context, id int32) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "Delete")
+	if Mocks.Watches.Delete != nil {
+		return Mocks.Watches.Delete(ctx, id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM watches WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return WatchNotFoundError{[]interface{}{id}}
+	}
+	return nil
+}
+
+// ListWatchedRepoIDs lists the IDs of all repositories that have at least one watch.
+func (*watches) ListWatchedRepoIDs(ctx context.Context) ([]api.RepoID, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "ListWatchedRepoIDs")
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT DISTINCT repo_id FROM watches ORDER BY repo_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []api.RepoID
+	for rows.Next() {
+		var id api.RepoID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetRepoHead returns the last head commit of the repository that new commits were computed for,
+// or the empty string if there is none.
+func (*watches) GetRepoHead(ctx context.Context, repoID api.RepoID) (api.CommitID, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "GetRepoHead")
+	var commitID api.CommitID
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT commit_id FROM watch_repo_heads WHERE repo_id=$1", repoID).Scan(&commitID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return commitID, err
+}
+
+// SetRepoHead records the last head commit of the repository that new commits were computed for.
+func (*watches) SetRepoHead(ctx context.Context, repoID api.RepoID, commitID api.CommitID) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "SetRepoHead")
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO watch_repo_heads(repo_id, commit_id) VALUES($1, $2)
+ON CONFLICT (repo_id) DO UPDATE SET commit_id=EXCLUDED.commit_id, updated_at=now()`,
+		repoID, commitID,
+	)
+	return err
+}
+
+// AddFeedEntries adds entries for the commits to the feed of the watch, ignoring commits that
+// already have an entry. The entries must be ordered newest first (like `git log`), so that the
+// newest commit gets the highest ID.
+func (*watches) AddFeedEntries(ctx context.Context, watchID int32, entries []*WatchFeedEntry) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "AddFeedEntries")
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			if _, err := tx.ExecContext(ctx, `
+INSERT INTO watch_feed_entries(watch_id, commit_id, subject, author_name, paths) VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (watch_id, commit_id) DO NOTHING`,
+				watchID, e.CommitID, e.Subject, e.AuthorName, pq.Array(e.Paths),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WatchFeedEntriesListOptions contains options for listing activity feed entries.
+type WatchFeedEntriesListOptions struct {
+	UserID     int32 // only list entries of this user's watches
+	BeforeID   int64 // only list entries with an ID less than this (for pagination)
+	OnlyDigest bool  // only list entries of watches with email digests that haven't been emailed yet
+	*LimitOffset
+}
+
+func (o WatchFeedEntriesListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("w.user_id=%d", o.UserID))
+	}
+	if o.BeforeID != 0 {
+		conds = append(conds, sqlf.Sprintf("e.id<%d", o.BeforeID))
+	}
+	if o.OnlyDigest {
+		conds = append(conds, sqlf.Sprintf("w.email_digest AND e.emailed_at IS NULL"))
+	}
+	return conds
+}
+
+// ListFeedEntries lists all activity feed entries that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
+// options, and must only reveal the entries (and their paths) that the user of the watch may
+// currently view.
+func (*watches) ListFeedEntries(ctx context.Context, opt WatchFeedEntriesListOptions) ([]*WatchFeedEntry, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "ListFeedEntries")
+	if Mocks.Watches.ListFeedEntries != nil {
+		return Mocks.Watches.ListFeedEntries(ctx, opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT e.id, e.watch_id, w.user_id, w.repo_id, e.commit_id, e.subject, e.author_name, e.paths, e.created_at, e.emailed_at
+FROM watch_feed_entries e JOIN watches w ON w.id=e.watch_id
+WHERE (%s)
+ORDER BY e.id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*WatchFeedEntry
+	for rows.Next() {
+		var e WatchFeedEntry
+		if err := rows.Scan(&e.ID, &e.WatchID, &e.UserID, &e.RepoID, &e.CommitID, &e.Subject, &e.AuthorName, pq.Array(&e.Paths), &e.CreatedAt, &e.EmailedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &e)
+	}
+	return results, rows.Err()
+}
+
+// ListDigestUserIDs lists the IDs of the users who have feed entries for an email digest and who
+// haven't been sent a digest since the given time.
+func (*watches) ListDigestUserIDs(ctx context.Context, since time.Time) ([]int32, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "ListDigestUserIDs")
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT DISTINCT w.user_id FROM watch_feed_entries e JOIN watches w ON w.id=e.watch_id
+WHERE w.email_digest AND e.emailed_at IS NULL AND NOT EXISTS (
+	SELECT 1 FROM watch_feed_entries e2 JOIN watches w2 ON w2.id=e2.watch_id
+	WHERE w2.user_id=w.user_id AND e2.emailed_at>$1
+)
+ORDER BY w.user_id`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// MarkEmailed records that the user's feed entries for an email digest with IDs up to and
+// including maxID were emailed.
+func (*watches) MarkEmailed(ctx context.Context, userID int32, maxID int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "MarkEmailed")
+	_, err := dbconn.Global.ExecContext(ctx, `
+UPDATE watch_feed_entries e SET emailed_at=now()
+FROM watches w
+WHERE w.id=e.watch_id AND w.user_id=$1 AND w.email_digest AND e.emailed_at IS NULL AND e.id<=$2`,
+		userID, maxID,
+	)
+	return err
+}
+
+// DeleteFeedEntriesOlderThan deletes the feed entries created before the given time and returns
+// the number of deleted entries.
+func (*watches) DeleteFeedEntriesOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Watches", "DeleteFeedEntriesOlderThan")
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM watch_feed_entries WHERE created_at<$1", t)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,11 @@
+package db
+
+import "context"
+
+type MockWatches struct {
+	Upsert          func(ctx context.Context, w *Watch) error
+	GetByID         func(ctx context.Context, id int32) (*Watch, error)
+	List            func(ctx context.Context, opt WatchesListOptions) ([]*Watch, error)
+	Delete          func(ctx context.Context, id int32) error
+	ListFeedEntries func(ctx context.Context, opt WatchFeedEntriesListOptions) ([]*WatchFeedEntry, error)
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestWatches(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "github.com/acme/a", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "github.com/acme/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &Watch{UserID: user.ID, RepoID: repo.ID, PathPattern: "pkg/auth/**"}
+	if err := Watches.Upsert(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+
+	// Watching the same path again updates the existing watch.
+	w2 := &Watch{UserID: user.ID, RepoID: repo.ID, PathPattern: "pkg/auth/**", EmailDigest: true}
+	if err := Watches.Upsert(ctx, w2); err != nil {
+		t.Fatal(err)
+	}
+	if w2.ID != w.ID {
+		t.Errorf("got watch ID %d, want %d", w2.ID, w.ID)
+	}
+	if got, err := Watches.List(ctx, WatchesListOptions{UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	} else if want := []*Watch{w2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if ids, err := Watches.ListWatchedRepoIDs(ctx); err != nil {
+		t.Fatal(err)
+	} else if want := []api.RepoID{repo.ID}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got watched repos %v, want %v", ids, want)
+	}
+
+	t.Run("repo heads", func(t *testing.T) {
+		if head, err := Watches.GetRepoHead(ctx, repo.ID); err != nil || head != "" {
+			t.Fatalf("got head %q (err %v), want none", head, err)
+		}
+		for _, commitID := range []api.CommitID{"a", "b"} {
+			if err := Watches.SetRepoHead(ctx, repo.ID, commitID); err != nil {
+				t.Fatal(err)
+			}
+			if head, err := Watches.GetRepoHead(ctx, repo.ID); err != nil || head != commitID {
+				t.Fatalf("got head %q (err %v), want %q", head, err, commitID)
+			}
+		}
+	})
+
+	t.Run("feed entries", func(t *testing.T) {
+		entries := []*WatchFeedEntry{
+			{CommitID: "c2", Subject: "second", AuthorName: "a", Paths: []string{"pkg/auth/b.go"}},
+			{CommitID: "c1", Subject: "first", AuthorName: "a", Paths: []string{"pkg/auth/a.go"}},
+		}
+		// Adding the same commits again has no effect.
+		for i := 0; i < 2; i++ {
+			if err := Watches.AddFeedEntries(ctx, w.ID, entries); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		feed, err := Watches.ListFeedEntries(ctx, WatchFeedEntriesListOptions{UserID: user.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(feed) != 2 || feed[0].CommitID != "c2" || feed[1].CommitID != "c1" {
+			t.Fatalf("got feed %+v, want c2 and c1", feed)
+		}
+		if !reflect.DeepEqual(feed[0].Paths, entries[0].Paths) || feed[0].RepoID != repo.ID || feed[0].UserID != user.ID {
+			t.Errorf("got entry %+v", feed[0])
+		}
+		if older, err := Watches.ListFeedEntries(ctx, WatchFeedEntriesListOptions{UserID: user.ID, BeforeID: feed[0].ID}); err != nil {
+			t.Fatal(err)
+		} else if len(older) != 1 || older[0].CommitID != "c1" {
+			t.Errorf("got older entries %+v, want c1", older)
+		}
+
+		// The digest includes the entries until they are marked as emailed.
+		dayAgo := time.Now().Add(-24 * time.Hour)
+		if ids, err := Watches.ListDigestUserIDs(ctx, dayAgo); err != nil {
+			t.Fatal(err)
+		} else if want := []int32{user.ID}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got digest users %v, want %v", ids, want)
+		}
+		if err := Watches.MarkEmailed(ctx, user.ID, feed[0].ID); err != nil {
+			t.Fatal(err)
+		}
+		if digest, err := Watches.ListFeedEntries(ctx, WatchFeedEntriesListOptions{UserID: user.ID, OnlyDigest: true}); err != nil {
+			t.Fatal(err)
+		} else if len(digest) != 0 {
+			t.Errorf("got %d digest entries, want none", len(digest))
+		}
+
+		// No further digest is sent within a day.
+		if err := Watches.AddFeedEntries(ctx, w.ID, []*WatchFeedEntry{{CommitID: "c3", Subject: "third", AuthorName: "a", Paths: []string{"pkg/auth/c.go"}}}); err != nil {
+			t.Fatal(err)
+		}
+		if ids, err := Watches.ListDigestUserIDs(ctx, dayAgo); err != nil {
+			t.Fatal(err)
+		} else if len(ids) != 0 {
+			t.Errorf("got digest users %v, want none", ids)
+		}
+
+		if n, err := Watches.DeleteFeedEntriesOlderThan(ctx, time.Now().Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		} else if n != 3 {
+			t.Errorf("deleted %d feed entries, want 3", n)
+		}
+	})
+
+	if err := Watches.Delete(ctx, w.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Watches.GetByID(ctx, w.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got err %v, want errcode.IsNotFound", err)
+	}
+	if err := Watches.Delete(ctx, w.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got err %v, want errcode.IsNotFound", err)
+	}
+}
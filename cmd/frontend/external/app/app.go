@@ -11,6 +11,8 @@ type SignOutURL = app.SignOutURL
 
 var RegisterSSOSignOutHandler = app.RegisterSSOSignOutHandler
 
+var RegisterCampaignFeed = app.RegisterCampaignFeed
+
 func SetBillingPublishableKey(value string) {
 	jscontext.BillingPublishableKey = value
 }
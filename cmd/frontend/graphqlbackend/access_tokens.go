@@ -53,6 +53,7 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		switch scope {
 		case authz.ScopeUserAll:
 			hasUserAllScope = true
+		case authz.ScopeFeedsRead:
 		case authz.ScopeSiteAdminSudo:
 			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
 			if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
@@ -67,8 +68,11 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, fmt.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	// 🚨 SECURITY: A token with the "feeds:read" scope may only read feeds, so it must not have
+	// other scopes (such as "site-admin:sudo") that only make sense with "user:all".
+	feedsOnly := len(args.Scopes) == 1 && args.Scopes[0] == authz.ScopeFeedsRead
+	if !hasUserAllScope && !feedsOnly {
+		return nil, fmt.Errorf("all access tokens must have scope %q, or only scope %q", authz.ScopeUserAll, authz.ScopeFeedsRead)
 	}
 
 	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
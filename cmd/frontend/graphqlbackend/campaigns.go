@@ -44,6 +44,11 @@ type CreateCampaignPlanFromPatchesArgs struct {
 	Patches []CampaignPlanPatch
 }
 
+type CreateCampaignPlanFromScriptArgs struct {
+	Script       string
+	Repositories []graphql.ID
+}
+
 type CampaignPlanPatch struct {
 	Repository   graphql.ID
 	BaseRevision string
@@ -102,6 +107,7 @@ type CampaignsResolver interface {
 	AddChangesetsToCampaign(ctx context.Context, args *AddChangesetsToCampaignArgs) (CampaignResolver, error)
 
 	CreateCampaignPlanFromPatches(ctx context.Context, args CreateCampaignPlanFromPatchesArgs) (CampaignPlanResolver, error)
+	CreateCampaignPlanFromScript(ctx context.Context, args CreateCampaignPlanFromScriptArgs) (CampaignPlanResolver, error)
 	CampaignPlanByID(ctx context.Context, id graphql.ID) (CampaignPlanResolver, error)
 
 	ChangesetPlanByID(ctx context.Context, id graphql.ID) (ChangesetPlanResolver, error)
@@ -167,6 +173,10 @@ func (defaultCampaignsResolver) CreateCampaignPlanFromPatches(ctx context.Contex
 	return nil, campaignsOnlyInEnterprise
 }
 
+func (defaultCampaignsResolver) CreateCampaignPlanFromScript(ctx context.Context, args CreateCampaignPlanFromScriptArgs) (CampaignPlanResolver, error) {
+	return nil, campaignsOnlyInEnterprise
+}
+
 func (defaultCampaignsResolver) CampaignPlanByID(ctx context.Context, id graphql.ID) (CampaignPlanResolver, error) {
 	return nil, campaignsOnlyInEnterprise
 }
@@ -302,6 +312,23 @@ type CampaignPlanResolver interface {
 	ChangesetPlans(ctx context.Context, args *graphqlutil.ConnectionArgs) ChangesetPlansConnectionResolver
 
 	PreviewURL() string
+
+	ScriptRuns(ctx context.Context, args *graphqlutil.ConnectionArgs) CampaignScriptRunsConnectionResolver
+}
+
+type CampaignScriptRunsConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CampaignScriptRunResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type CampaignScriptRunResolver interface {
+	Repository(ctx context.Context) (*RepositoryResolver, error)
+	State() campaigns.BackgroundProcessState
+	StartedAt() *DateTime
+	FinishedAt() *DateTime
+	Log() string
+	Error() *string
 }
 
 type PreviewFileDiff interface {
@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func (r *GitTreeEntryResolver) Duplicates(ctx context.Context, args *struct {
+	StartLine *int32
+	EndLine   *int32
+	First     int32
+}) ([]*codeCloneRegionResolver, error) {
+	if !backend.CodeCloneDetectionEnabled() {
+		return []*codeCloneRegionResolver{}, nil
+	}
+	var startLine, endLine int
+	if args.StartLine != nil {
+		startLine = int(*args.StartLine)
+	}
+	if args.EndLine != nil {
+		endLine = int(*args.EndLine)
+	}
+	found, err := backend.FindCodeClones(ctx, r.commit.repo.repo, api.CommitID(r.commit.OID()), r.Path(), startLine, endLine)
+	if err != nil {
+		return nil, err
+	}
+	if args.First >= 0 && len(found) > int(args.First) {
+		found = found[:args.First]
+	}
+	return toCodeCloneRegionResolvers(found), nil
+}
+
+func (r *schemaResolver) CodeCloneClusters(ctx context.Context, args *struct{ First int32 }) ([]*codeCloneClusterResolver, error) {
+	if !backend.CodeCloneDetectionEnabled() {
+		return []*codeCloneClusterResolver{}, nil
+	}
+	clusters, err := backend.LargestCodeCloneClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if args.First >= 0 && len(clusters) > int(args.First) {
+		clusters = clusters[:args.First]
+	}
+	resolvers := make([]*codeCloneClusterResolver, len(clusters))
+	for i, c := range clusters {
+		resolvers[i] = &codeCloneClusterResolver{cluster: c}
+	}
+	return resolvers, nil
+}
+
+type codeCloneClusterResolver struct {
+	cluster *backend.CodeCloneCluster
+}
+
+func (r *codeCloneClusterResolver) SharedFingerprints() int32 { return int32(r.cluster.Fingerprints) }
+
+func (r *codeCloneClusterResolver) Regions() []*codeCloneRegionResolver {
+	return toCodeCloneRegionResolvers(r.cluster.Clones)
+}
+
+type codeCloneRegionResolver struct {
+	clone *backend.CodeClone
+}
+
+func toCodeCloneRegionResolvers(clones []*backend.CodeClone) []*codeCloneRegionResolver {
+	resolvers := make([]*codeCloneRegionResolver, len(clones))
+	for i, c := range clones {
+		resolvers[i] = &codeCloneRegionResolver{clone: c}
+	}
+	return resolvers
+}
+
+func (r *codeCloneRegionResolver) File() *GitTreeEntryResolver {
+	return &GitTreeEntryResolver{
+		commit: &GitCommitResolver{
+			repo: &RepositoryResolver{repo: r.clone.Repo},
+			oid:  GitObjectID(r.clone.CommitID),
+		},
+		stat: CreateFileInfo(r.clone.Path, false),
+	}
+}
+
+func (r *codeCloneRegionResolver) StartLine() int32 { return int32(r.clone.StartLine) }
+
+func (r *codeCloneRegionResolver) EndLine() int32 { return int32(r.clone.EndLine) }
+
+func (r *codeCloneRegionResolver) SharedFingerprints() int32 { return int32(r.clone.Fingerprints) }
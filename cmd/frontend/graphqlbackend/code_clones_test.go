@@ -0,0 +1,100 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/clones"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGitBlob_Duplicates(t *testing.T) {
+	resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{CodeCloneDetection: "enabled"},
+	}})
+	defer conf.Mock(nil)
+	defer git.ResetMocks()
+
+	db.Mocks.Repos.MockGetByName(t, "github.com/gorilla/mux", 2)
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id, Name: "github.com/gorilla/mux"}, nil
+	}
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return exampleCommitSHA1, nil
+	}
+	backend.Mocks.Repos.MockGetCommit_Return_NoCheck(t, &git.Commit{ID: exampleCommitSHA1})
+
+	var src strings.Builder
+	src.WriteString("package mux\n\nfunc f(a, b int) int {\n")
+	for i, op := range []string{"+", "-", "*", "/", "%", "&", "|", "^"} {
+		fmt.Fprintf(&src, "\tif a > %d {\n\t\tb = b %s a\n\t}\n", i, op)
+	}
+	src.WriteString("\treturn b\n}\n")
+	git.Mocks.Stat = func(commit api.CommitID, path string) (os.FileInfo, error) {
+		return &util.FileInfo{Name_: path, Mode_: 0644}, nil
+	}
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		return []byte(src.String()), nil
+	}
+	fps := clones.FileFingerprints("a.go", []byte(src.String()))
+	if len(fps) < 2 {
+		t.Fatalf("got %d fingerprints, want at least 2", len(fps))
+	}
+	db.Mocks.CodeClones.ListMatches = func(context.Context, []int64, int) ([]*db.CloneMatch, error) {
+		region := db.CloneRegion{RepoID: 2, CommitID: "cccccccccccccccccccccccccccccccccccccccc", Path: "b.go", StartLine: 3, EndLine: 9}
+		return []*db.CloneMatch{{CloneRegion: region, Hash: fps[0].Hash}, {CloneRegion: region, Hash: fps[1].Hash}}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repository(name: "github.com/gorilla/mux") {
+						commit(rev: "abc") {
+							blob(path: "a.go") {
+								duplicates {
+									file { path commit { oid } }
+									startLine
+									endLine
+									sharedFingerprints
+								}
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repository": {
+						"commit": {
+							"blob": {
+								"duplicates": [
+									{
+										"file": {"path": "b.go", "commit": {"oid": "cccccccccccccccccccccccccccccccccccccccc"}},
+										"startLine": 3,
+										"endLine": 9,
+										"sharedFingerprints": 2
+									}
+								]
+							}
+						}
+					}
+				}
+			`,
+		},
+	})
+}
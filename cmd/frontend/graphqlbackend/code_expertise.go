@@ -0,0 +1,63 @@
+package graphqlbackend
+
+import (
+	"context"
+	"path"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+)
+
+func (r *GitTreeEntryResolver) Experts(ctx context.Context, args *struct{ First int32 }) ([]*codeExpertResolver, error) {
+	if !backend.CodeExpertiseEnabled() {
+		return []*codeExpertResolver{}, nil
+	}
+	dir := r.Path()
+	if !r.IsDirectory() {
+		dir = path.Dir(dir)
+	}
+	if dir == "." {
+		dir = ""
+	}
+	experts, err := backend.CodeExperts(ctx, r.commit.repo.repo, dir)
+	if err != nil {
+		return nil, err
+	}
+	if args.First >= 0 && len(experts) > int(args.First) {
+		experts = experts[:args.First]
+	}
+	resolvers := make([]*codeExpertResolver, len(experts))
+	for i, e := range experts {
+		resolvers[i] = &codeExpertResolver{expert: e}
+	}
+	return resolvers, nil
+}
+
+type codeExpertResolver struct {
+	expert *backend.CodeExpert
+}
+
+func (r *codeExpertResolver) Name() string { return r.expert.Name }
+
+func (r *codeExpertResolver) Email() *string {
+	if r.expert.Email == "" {
+		return nil
+	}
+	return &r.expert.Email
+}
+
+func (r *codeExpertResolver) User() *UserResolver {
+	if r.expert.User == nil {
+		return nil
+	}
+	return NewUserResolver(r.expert.User)
+}
+
+func (r *codeExpertResolver) Score() float64 { return r.expert.Score }
+
+func (r *codeExpertResolver) AuthoredLines() int32 { return int32(r.expert.AuthoredLines) }
+
+func (r *codeExpertResolver) ReviewedPullRequests() int32 {
+	return int32(r.expert.ReviewedPullRequests)
+}
+
+func (r *codeExpertResolver) LastActiveAt() DateTime { return DateTime{Time: r.expert.LastActiveAt} }
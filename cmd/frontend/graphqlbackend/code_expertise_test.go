@@ -0,0 +1,112 @@
+package graphqlbackend
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestTreeEntry_Experts(t *testing.T) {
+	resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{CodeExpertise: "enabled"},
+	}})
+	defer conf.Mock(nil)
+	defer git.ResetMocks()
+
+	db.Mocks.Repos.MockGetByName(t, "github.com/gorilla/mux", 2)
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return exampleCommitSHA1, nil
+	}
+	backend.Mocks.Repos.MockGetCommit_Return_NoCheck(t, &git.Commit{ID: exampleCommitSHA1})
+	git.Mocks.Stat = func(commit api.CommitID, path string) (os.FileInfo, error) {
+		if path == "pkg/billing" {
+			return &util.FileInfo{Name_: path, Mode_: os.ModeDir}, nil
+		}
+		return &util.FileInfo{Name_: path, Mode_: 0644}, nil
+	}
+
+	lastActiveAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Mocks.CodeExpertise.ListAuthors = func(_ context.Context, repoID api.RepoID, opt db.ExpertiseListOptions) ([]*db.ExpertiseAuthor, error) {
+		if repoID != 2 || opt.Dir != "pkg/billing" {
+			t.Errorf("got repo %d and dir %q", repoID, opt.Dir)
+		}
+		return []*db.ExpertiseAuthor{
+			{Email: "alice@example.com", Name: "Alice", UserID: 1, Lines: 100, Score: 50, LastActiveAt: lastActiveAt},
+			{Email: "carol@example.com", Name: "Carol", Lines: 40, Score: 40, LastActiveAt: lastActiveAt},
+		}, nil
+	}
+	db.Mocks.CodeExpertise.ListReviewers = func(context.Context, api.RepoID, db.ExpertiseListOptions) ([]*db.ExpertiseReviewer, error) {
+		return []*db.ExpertiseReviewer{{AccountID: "1", Login: "alice", UserID: 1, PullRequests: 2, Score: 1, LastActiveAt: lastActiveAt}}, nil
+	}
+	db.Mocks.Users.GetByID = func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repository(name: "github.com/gorilla/mux") {
+						commit(rev: "abc") {
+							tree(path: "pkg/billing") {
+								experts(first: 1) {
+									name
+									email
+									user { username }
+									score
+									authoredLines
+									reviewedPullRequests
+									lastActiveAt
+								}
+							}
+							blob(path: "pkg/billing/a.go") {
+								experts { name }
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repository": {
+						"commit": {
+							"tree": {
+								"experts": [
+									{
+										"name": "Alice",
+										"email": "alice@example.com",
+										"user": { "username": "alice" },
+										"score": 75,
+										"authoredLines": 100,
+										"reviewedPullRequests": 2,
+										"lastActiveAt": "2020-01-02T03:04:05Z"
+									}
+								]
+							},
+							"blob": {
+								"experts": [
+									{ "name": "Alice" },
+									{ "name": "Carol" }
+								]
+							}
+						}
+					}
+				}
+			`,
+		},
+	})
+}
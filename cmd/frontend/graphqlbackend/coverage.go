@@ -0,0 +1,129 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+func (r *RepositoryResolver) Coverage() *repositoryCoverageResolver {
+	return &repositoryCoverageResolver{repo: r}
+}
+
+type repositoryCoverageResolver struct {
+	repo *RepositoryResolver
+}
+
+func (r *repositoryCoverageResolver) Report(ctx context.Context, args *struct{ Rev string }) (*coverageReportResolver, error) {
+	commitID, err := backend.Repos.ResolveRev(ctx, r.repo.repo, args.Rev)
+	if err != nil {
+		if gitserver.IsRevisionNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	report, err := backend.NearestCoverageReport(ctx, r.repo.repo, commitID)
+	if report == nil || err != nil {
+		return nil, err
+	}
+	return &coverageReportResolver{repo: r.repo, report: report}, nil
+}
+
+func (r *repositoryCoverageResolver) History(ctx context.Context, args *struct{ First int32 }) ([]*coverageReportResolver, error) {
+	reports, err := db.CoverageReports.List(ctx, db.CoverageReportsListOptions{
+		RepoID:      r.repo.repo.ID,
+		LimitOffset: &db.LimitOffset{Limit: int(args.First)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*coverageReportResolver, len(reports))
+	for i, report := range reports {
+		resolvers[i] = &coverageReportResolver{repo: r.repo, report: report}
+	}
+	return resolvers, nil
+}
+
+type coverageReportResolver struct {
+	repo   *RepositoryResolver
+	report *db.CoverageReport
+}
+
+func (r *coverageReportResolver) Commit() *GitCommitResolver {
+	return &GitCommitResolver{repo: r.repo, includeUserInfo: true, oid: GitObjectID(r.report.CommitID)}
+}
+
+func (r *coverageReportResolver) LinesFound() int32 { return r.report.LinesFound }
+
+func (r *coverageReportResolver) LinesHit() int32 { return r.report.LinesHit }
+
+func (r *coverageReportResolver) Ratio() *float64 {
+	return coverageRatio(r.report.LinesHit, r.report.LinesFound)
+}
+
+func (r *coverageReportResolver) CreatedAt() DateTime { return DateTime{Time: r.report.CreatedAt} }
+
+func (r *coverageReportResolver) UpdatedAt() DateTime { return DateTime{Time: r.report.UpdatedAt} }
+
+func coverageRatio(hit, found int32) *float64 {
+	if found == 0 {
+		return nil
+	}
+	ratio := float64(hit) / float64(found)
+	return &ratio
+}
+
+func (r *GitTreeEntryResolver) Coverage(ctx context.Context) (*fileCoverageResolver, error) {
+	commitID := api.CommitID(r.commit.OID())
+	report, err := backend.NearestCoverageReport(ctx, r.commit.repo.repo, commitID)
+	if report == nil || err != nil {
+		return nil, err
+	}
+	file, err := db.CoverageReports.GetFile(ctx, report.ID, r.Path())
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &fileCoverageResolver{
+		report: &coverageReportResolver{repo: r.commit.repo, report: report},
+		exact:  report.CommitID == commitID,
+		file:   file,
+	}, nil
+}
+
+type fileCoverageResolver struct {
+	report *coverageReportResolver
+	exact  bool
+	file   *db.CoverageFile
+}
+
+func (r *fileCoverageResolver) Report() *coverageReportResolver { return r.report }
+
+func (r *fileCoverageResolver) Exact() bool { return r.exact }
+
+func (r *fileCoverageResolver) Format() string { return string(r.file.Format) }
+
+func (r *fileCoverageResolver) LinesFound() int32 { return r.file.LinesFound }
+
+func (r *fileCoverageResolver) LinesHit() int32 { return r.file.LinesHit }
+
+func (r *fileCoverageResolver) Lines() []*lineCoverageResolver {
+	lines := make([]*lineCoverageResolver, len(r.file.Lines))
+	for i, l := range r.file.Lines {
+		lines[i] = &lineCoverageResolver{line: int32(l.Number), hits: int32(l.Hits)}
+	}
+	return lines
+}
+
+type lineCoverageResolver struct {
+	line, hits int32
+}
+
+func (r *lineCoverageResolver) Line() int32 { return r.line }
+
+func (r *lineCoverageResolver) Hits() int32 { return r.hits }
@@ -74,6 +74,13 @@ func (r *externalServiceResolver) UpdatedAt() DateTime {
 	return DateTime{Time: r.externalService.UpdatedAt}
 }
 
+func (r *externalServiceResolver) Namespace(ctx context.Context) (*OrgResolver, error) {
+	if r.externalService.NamespaceOrgID == 0 {
+		return nil, nil
+	}
+	return OrgByIDInt32(ctx, r.externalService.NamespaceOrgID)
+}
+
 func (r *externalServiceResolver) Warning() *string {
 	if r.warning == "" {
 		return nil
@@ -28,6 +28,7 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 		Kind        string
 		DisplayName string
 		Config      string
+		Namespace   *graphql.ID
 	}
 }) (*externalServiceResolver, error) {
 	// 🚨 SECURITY: Only site admins may add external services.
@@ -43,6 +44,12 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 		DisplayName: args.Input.DisplayName,
 		Config:      args.Input.Config,
 	}
+	if args.Input.Namespace != nil {
+		var err error
+		if externalService.NamespaceOrgID, err = UnmarshalOrgID(*args.Input.Namespace); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := db.ExternalServices.Create(ctx, conf.Get, externalService); err != nil {
 		return nil, err
@@ -61,6 +68,7 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 		ID          graphql.ID
 		DisplayName *string
 		Config      *string
+		Namespace   *graphql.ID
 	}
 }) (*externalServiceResolver, error) {
 	externalServiceID, err := unmarshalExternalServiceID(args.Input.ID)
@@ -85,6 +93,15 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 		DisplayName: args.Input.DisplayName,
 		Config:      args.Input.Config,
 	}
+	if args.Input.Namespace != nil {
+		var orgID int32
+		if *args.Input.Namespace != "" {
+			if orgID, err = UnmarshalOrgID(*args.Input.Namespace); err != nil {
+				return nil, err
+			}
+		}
+		update.NamespaceOrgID = &orgID
+	}
 	if err := db.ExternalServices.Update(ctx, ps, externalServiceID, update); err != nil {
 		return nil, err
 	}
@@ -10,6 +10,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/externallink"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/svn"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 
@@ -153,6 +155,34 @@ func (r *GitCommitResolver) Body(ctx context.Context) (*string, error) {
 	return &body, nil
 }
 
+func (r *GitCommitResolver) PerforceChangelist(ctx context.Context) (*int32, error) {
+	r.resolveCommit(ctx)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	change, ok := perforce.ChangelistFromCommitMessage(r.message)
+	if !ok {
+		return nil, nil
+	}
+	n := int32(change)
+	return &n, nil
+}
+
+func (r *GitCommitResolver) SubversionRevision(ctx context.Context) (*int32, error) {
+	r.resolveCommit(ctx)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	rev, ok := svn.RevisionFromCommitMessage(r.message)
+	if !ok {
+		return nil, nil
+	}
+	n := int32(rev)
+	return &n, nil
+}
+
 func (r *GitCommitResolver) Parents(ctx context.Context) ([]*GitCommitResolver, error) {
 	r.resolveCommit(ctx)
 	if r.err != nil {
@@ -190,6 +220,11 @@ func (r *GitCommitResolver) Tree(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	// 🚨 SECURITY: Only return directories that the user may browse.
+	// Check before stat-ing the path so that we don't reveal whether it exists.
+	if err := backend.CheckSubRepoPath(ctx, r.repo.repo, args.Path, true); err != nil {
+		return nil, err
+	}
 	stat, err := git.Stat(ctx, *cachedRepo, api.CommitID(r.oid), args.Path)
 	if err != nil {
 		return nil, err
@@ -211,6 +246,11 @@ func (r *GitCommitResolver) Blob(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	// 🚨 SECURITY: Only return files that the user may read.
+	// Check before stat-ing the path so that we don't reveal whether it exists.
+	if err := backend.CheckSubRepoPath(ctx, r.repo.repo, args.Path, false); err != nil {
+		return nil, err
+	}
 	stat, err := git.Stat(ctx, *cachedRepo, api.CommitID(r.oid), args.Path)
 	if err != nil {
 		return nil, err
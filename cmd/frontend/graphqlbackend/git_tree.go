@@ -52,6 +52,21 @@ func (r *GitTreeEntryResolver) entries(ctx context.Context, args *gitTreeEntryCo
 		}
 	}
 
+	// 🚨 SECURITY: Only list the entries that the user may read.
+	perms, err := backend.SubRepoPerms(ctx, r.commit.repo.repo)
+	if err != nil {
+		return nil, err
+	}
+	if perms.Restricted() {
+		allowed := entries[:0]
+		for _, entry := range entries {
+			if (entry.IsDir() && perms.AllowedDir(entry.Name())) || (!entry.IsDir() && perms.Allowed(entry.Name())) {
+				allowed = append(allowed, entry)
+			}
+		}
+		entries = allowed
+	}
+
 	sort.Sort(byDirectory(entries))
 
 	if args.First != nil && len(entries) > int(*args.First) {
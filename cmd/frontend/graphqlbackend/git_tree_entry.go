@@ -128,6 +128,13 @@ func cloneURLToRepoName(ctx context.Context, cloneURL string) (string, error) {
 	return string(repoName), nil
 }
 
+// CloneURLToRepoName maps a Git clone URL to the name of the corresponding repository. It returns
+// the empty string if no code host configuration matches the clone URL (see
+// reposourceCloneURLToRepoName).
+func CloneURLToRepoName(ctx context.Context, cloneURL string) (api.RepoName, error) {
+	return reposourceCloneURLToRepoName(ctx, cloneURL)
+}
+
 // reposourceCloneURLToRepoName maps a Git clone URL (format documented here:
 // https://git-scm.com/docs/git-clone#_git_urls_a_id_urls_a) to the corresponding repo name if there
 // exists a code host configuration that matches the clone URL. Implicitly, it includes a code host
@@ -223,6 +223,21 @@ func (r *NodeResolver) ToOrganizationInvitation() (*organizationInvitationResolv
 	return n, ok
 }
 
+func (r *NodeResolver) ToRepositoryAccessRequest() (*repositoryAccessRequestResolver, bool) {
+	n, ok := r.Node.(*repositoryAccessRequestResolver)
+	return n, ok
+}
+
+func (r *NodeResolver) ToSnippetShareLink() (*snippetShareLinkResolver, bool) {
+	n, ok := r.Node.(*snippetShareLinkResolver)
+	return n, ok
+}
+
+func (r *NodeResolver) ToWatch() (*watchResolver, bool) {
+	n, ok := r.Node.(*watchResolver)
+	return n, ok
+}
+
 func (r *NodeResolver) ToGitCommit() (*GitCommitResolver, bool) {
 	n, ok := r.Node.(*GitCommitResolver)
 	return n, ok
@@ -322,6 +337,12 @@ func (r *schemaResolver) nodeByID(ctx context.Context, id graphql.ID) (Node, err
 		return OrgByID(ctx, id)
 	case "OrganizationInvitation":
 		return orgInvitationByID(ctx, id)
+	case "RepositoryAccessRequest":
+		return repositoryAccessRequestByID(ctx, id)
+	case "SnippetShareLink":
+		return snippetShareLinkByID(ctx, id)
+	case "Watch":
+		return watchByID(ctx, id)
 	case "GitCommit":
 		return gitCommitByID(ctx, id)
 	case "RegistryExtension":
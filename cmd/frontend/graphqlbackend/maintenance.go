@@ -0,0 +1,263 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// maintenanceModeAllowedMutations are the mutations that are allowed while maintenance mode is
+// enabled. All other mutations are rejected.
+var maintenanceModeAllowedMutations = map[string]bool{
+	// Site admins must be able to disable maintenance mode.
+	"setMaintenanceMode": true,
+
+	// These don't write to the database.
+	"reloadSite":                      true,
+	"checkMirrorRepositoryConnection": true,
+}
+
+// CheckMaintenanceMode returns an error if maintenance mode is enabled and the GraphQL request
+// (with the given query and operation name) performs a mutation that isn't allowed while the site
+// is read-only.
+func CheckMaintenanceMode(query, operationName string) error {
+	err := conf.ErrMaintenanceMode()
+	if err == nil {
+		return nil
+	}
+	fields, isMutation := mutationFields(query, operationName)
+	if !isMutation {
+		return nil
+	}
+	for _, f := range fields {
+		if !maintenanceModeAllowedMutations[f] {
+			return err
+		}
+	}
+	return nil
+}
+
+// mutationFields returns the names of the top-level fields of the operation that a GraphQL
+// document executes (the operation with the given name, or the document's only operation), and
+// whether the operation is a mutation. A fragment at the top level of the operation is returned
+// as "...", because its fields are not resolved.
+//
+// The document is only tokenized, not parsed. That is enough to find the top-level fields, and an
+// invalid document fails validation when it's executed anyway.
+func mutationFields(query, operationName string) (fields []string, isMutation bool) {
+	type operation struct {
+		typ, name string
+		fields    []string
+	}
+	var (
+		ops        []*operation
+		cur        *operation
+		braceDepth int
+		parenDepth int
+	)
+	tokens := graphqlTokens(query)
+	for i, tok := range tokens {
+		switch tok {
+		case "{":
+			if braceDepth == 0 && cur == nil {
+				// A query shorthand, such as "{ currentUser { username } }".
+				cur = &operation{typ: "query"}
+				ops = append(ops, cur)
+			}
+			braceDepth++
+			continue
+		case "}":
+			braceDepth--
+			if braceDepth == 0 {
+				cur = nil
+			}
+			continue
+		case "(":
+			parenDepth++
+			continue
+		case ")":
+			parenDepth--
+			continue
+		}
+		if parenDepth > 0 {
+			continue
+		}
+
+		switch braceDepth {
+		case 0:
+			if cur == nil {
+				switch tok {
+				case "query", "mutation", "subscription", "fragment":
+					cur = &operation{typ: tok}
+					if i+1 < len(tokens) && isGraphQLName(tokens[i+1]) {
+						cur.name = tokens[i+1]
+					}
+					if tok != "fragment" {
+						ops = append(ops, cur)
+					}
+				}
+			}
+		case 1:
+			if cur == nil {
+				continue
+			}
+			var prev, next string
+			if i > 0 {
+				prev = tokens[i-1]
+			}
+			if i+1 < len(tokens) {
+				next = tokens[i+1]
+			}
+			inlineFragmentType := prev == "on" && i >= 2 && tokens[i-2] == "..."
+			switch {
+			case tok == "...":
+				cur.fields = append(cur.fields, "...")
+			case isGraphQLName(tok) && prev != "@" && prev != "..." && next != ":" && !inlineFragmentType:
+				cur.fields = append(cur.fields, tok)
+			}
+		}
+	}
+
+	var op *operation
+	if operationName != "" {
+		for _, o := range ops {
+			if o.name == operationName {
+				op = o
+				break
+			}
+		}
+	} else if len(ops) == 1 {
+		op = ops[0]
+	}
+	if op == nil || op.typ != "mutation" {
+		return nil, false
+	}
+	return op.fields, true
+}
+
+// graphqlTokens splits a GraphQL document into names and punctuators. String and number values are
+// returned as `""` and "0", and whitespace, commas, and comments are skipped.
+func graphqlTokens(doc string) (tokens []string) {
+	for i := 0; i < len(doc); {
+		c := doc[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(doc) && doc[i] != '\n' && doc[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(doc[i:], `"""`):
+			i += 3
+			for i < len(doc) && !strings.HasPrefix(doc[i:], `"""`) {
+				if strings.HasPrefix(doc[i:], `\"""`) {
+					i += 4
+				} else {
+					i++
+				}
+			}
+			i += 3
+			tokens = append(tokens, `""`)
+		case c == '"':
+			i++
+			for i < len(doc) && doc[i] != '"' && doc[i] != '\n' {
+				if doc[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			tokens = append(tokens, `""`)
+		case c == '-' || ('0' <= c && c <= '9'):
+			i++
+			for i < len(doc) && (isGraphQLNameChar(doc[i]) || doc[i] == '.' || doc[i] == '+' || doc[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, "0")
+		case isGraphQLNameChar(c):
+			start := i
+			for i < len(doc) && isGraphQLNameChar(doc[i]) {
+				i++
+			}
+			tokens = append(tokens, doc[start:i])
+		case strings.HasPrefix(doc[i:], "..."):
+			i += 3
+			tokens = append(tokens, "...")
+		default:
+			i++
+			tokens = append(tokens, string(c))
+		}
+	}
+	return tokens
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isGraphQLName(tok string) bool {
+	return tok != "" && isGraphQLNameChar(tok[0]) && !('0' <= tok[0] && tok[0] <= '9')
+}
+
+func (r *siteResolver) MaintenanceMode() *maintenanceModeResolver {
+	return &maintenanceModeResolver{conf.Get().MaintenanceMode}
+}
+
+type maintenanceModeResolver struct {
+	m *schema.MaintenanceMode
+}
+
+func (r *maintenanceModeResolver) Enabled() bool { return r.m != nil && r.m.Enabled }
+
+func (r *maintenanceModeResolver) Message() *string {
+	if r.m == nil || r.m.Message == "" {
+		return nil
+	}
+	return &r.m.Message
+}
+
+func (r *schemaResolver) SetMaintenanceMode(ctx context.Context, args *struct {
+	Enabled bool
+	Message *string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may make the site read-only (or make it writable again).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if os.Getenv("CRITICAL_CONFIG_FILE") != "" {
+		return nil, errors.New("setting maintenance mode not allowed when using CRITICAL_CONFIG_FILE (set maintenanceMode in the file instead)")
+	}
+
+	value := schema.MaintenanceMode{Enabled: args.Enabled}
+	if args.Message != nil {
+		value.Message = *args.Message
+	}
+	err := globals.ConfigurationServerFrontendOnly.Edit(ctx, func(_ *conf.Unified, raw conftypes.RawUnified) (conf.Edits, error) {
+		edits, _, err := jsonx.ComputePropertyEdit(raw.Critical, jsonx.MakePath("maintenanceMode"), value, nil, conf.FormatOptions)
+		return conf.Edits{Critical: edits}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	log15.Warn("Set maintenance mode (from API request).", "enabled", args.Enabled)
+	return &EmptyResponse{}, nil
+}
+
+func init() {
+	// Show a banner to all users while maintenance mode is enabled.
+	AlertFuncs = append(AlertFuncs, func(args AlertFuncArgs) []*Alert {
+		if err := conf.ErrMaintenanceMode(); err != nil {
+			return []*Alert{{TypeValue: AlertTypeWarning, MessageValue: err.Error()}}
+		}
+		return nil
+	})
+}
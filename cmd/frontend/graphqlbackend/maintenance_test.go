@@ -0,0 +1,116 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestMutationFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		wantFields    []string
+		wantMutation  bool
+	}{
+		{
+			name:  "query shorthand",
+			query: `{ currentUser { username } }`,
+		},
+		{
+			name:  "named query",
+			query: `query Q($id: ID!) { node(id: $id) { id } }`,
+		},
+		{
+			name:         "mutation",
+			query:        `mutation { logEvent(event: "x", userCookieID: "y", url: "z", source: WEB) { alwaysNil } }`,
+			wantFields:   []string{"logEvent"},
+			wantMutation: true,
+		},
+		{
+			name: "aliases, arguments, directives, and comments",
+			query: `
+# mutation { ignored }
+mutation M($input: String!, $skip: Boolean = false) {
+	a: updateSiteConfiguration(lastID: 1, input: $input)
+	b: createSavedSearch(description: "} { \" }", query: """ ) } """, notifyOwner: true, notifySlack: false, orgID: null, userID: "x") @skip(if: $skip) { id }
+	setMaintenanceMode(enabled: false, message: null) { alwaysNil }
+}`,
+			wantFields:   []string{"updateSiteConfiguration", "createSavedSearch", "setMaintenanceMode"},
+			wantMutation: true,
+		},
+		{
+			name: "input object arguments",
+			query: `mutation {
+	createCampaign(input: { namespace: "x", name: "y", description: "z" }) { id }
+}`,
+			wantFields:   []string{"createCampaign"},
+			wantMutation: true,
+		},
+		{
+			name: "fragments",
+			query: `mutation { ...F ... on Mutation { deleteUser(user: "x") { alwaysNil } } }
+fragment F on Mutation { reloadSite { alwaysNil } }`,
+			wantFields:   []string{"...", "..."},
+			wantMutation: true,
+		},
+		{
+			name:          "operation name selects mutation",
+			query:         `query Q { currentUser { username } } mutation M { reloadSite { alwaysNil } }`,
+			operationName: "M",
+			wantFields:    []string{"reloadSite"},
+			wantMutation:  true,
+		},
+		{
+			name:          "operation name selects query",
+			query:         `query Q { currentUser { username } } mutation M { reloadSite { alwaysNil } }`,
+			operationName: "Q",
+		},
+		{
+			name:  "ambiguous operations",
+			query: `query Q { currentUser { username } } mutation M { reloadSite { alwaysNil } }`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, isMutation := mutationFields(test.query, test.operationName)
+			if !reflect.DeepEqual(fields, test.wantFields) {
+				t.Errorf("got fields %q, want %q", fields, test.wantFields)
+			}
+			if isMutation != test.wantMutation {
+				t.Errorf("got isMutation %v, want %v", isMutation, test.wantMutation)
+			}
+		})
+	}
+}
+
+// TestCheckMaintenanceMode_allMutations checks every mutation in the schema, so that new mutations
+// are rejected in maintenance mode unless they are explicitly allowed.
+func TestCheckMaintenanceMode_allMutations(t *testing.T) {
+	mutations := map[string]bool{}
+	for _, f := range *mustParseGraphQLSchema(t).Inspect().MutationType().Fields(&struct{ IncludeDeprecated bool }{true}) {
+		mutations[f.Name()] = true
+	}
+	for name := range maintenanceModeAllowedMutations {
+		if !mutations[name] {
+			t.Errorf("allowed mutation %q is not in the schema", name)
+		}
+	}
+
+	for _, enabled := range []bool{false, true} {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: enabled}}})
+		for name := range mutations {
+			err := CheckMaintenanceMode("mutation { "+name+" }", "")
+			if wantErr := enabled && !maintenanceModeAllowedMutations[name]; (err != nil) != wantErr {
+				t.Errorf("maintenance mode %v: mutation %q: got error %v, want error %v", enabled, name, err, wantErr)
+			}
+		}
+		if err := CheckMaintenanceMode("{ currentUser { username } }", ""); err != nil {
+			t.Errorf("maintenance mode %v: query: got error %v", enabled, err)
+		}
+	}
+	conf.Mock(nil)
+}
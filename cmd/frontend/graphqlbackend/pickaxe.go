@@ -0,0 +1,155 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const (
+	maxPickaxeFirst   = 100
+	maxPickaxeTimeout = time.Minute
+)
+
+func (r *RepositoryResolver) Pickaxe(ctx context.Context, args *struct {
+	Query           string
+	IsRegExp        bool
+	IsCaseSensitive bool
+	Range           *string
+	Paths           *[]string
+	First           int32
+	Timeout         int32
+}) (*pickaxeResultResolver, error) {
+	opt := git.PickaxeOptions{
+		Query:           args.Query,
+		IsRegExp:        args.IsRegExp,
+		IsCaseSensitive: args.IsCaseSensitive,
+	}
+	if args.Range != nil {
+		opt.Range = *args.Range
+	}
+	if args.Paths != nil {
+		opt.Paths = *args.Paths
+	}
+	return pickaxe(ctx, r, opt, args.First, args.Timeout)
+}
+
+func (r *GitTreeEntryResolver) Pickaxe(ctx context.Context, args *struct {
+	StartLine int32
+	EndLine   int32
+	First     int32
+	Timeout   int32
+}) (*pickaxeResultResolver, error) {
+	content, err := r.Content(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(content, "\n"), "\n")
+	if args.StartLine < 1 || args.EndLine < args.StartLine || int(args.EndLine) > len(lines) {
+		return nil, fmt.Errorf("invalid line range %d-%d (the file has %d lines)", args.StartLine, args.EndLine, len(lines))
+	}
+	query := strings.TrimSpace(strings.Join(lines[args.StartLine-1:args.EndLine], ""))
+	if query == "" {
+		return nil, errors.New("the lines are empty")
+	}
+
+	return pickaxe(ctx, r.commit.repo, git.PickaxeOptions{
+		Query:           query,
+		IsCaseSensitive: true,
+		Range:           string(r.commit.OID()),
+		Paths:           []string{r.Path()},
+		Follow:          true,
+	}, args.First, args.Timeout)
+}
+
+// pickaxe returns the events of the first commits that changed the query, omitting the files that
+// the user may not read.
+func pickaxe(ctx context.Context, repo *RepositoryResolver, opt git.PickaxeOptions, first, timeoutSeconds int32) (*pickaxeResultResolver, error) {
+	if first < 0 || first > maxPickaxeFirst {
+		return nil, fmt.Errorf("first must be between 0 and %d", maxPickaxeFirst)
+	}
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	if timeout <= 0 || timeout > maxPickaxeTimeout {
+		return nil, fmt.Errorf("timeout must be between 1 and %d seconds", maxPickaxeTimeout/time.Second)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo.repo)
+	if err != nil {
+		return nil, err
+	}
+	// Request one more commit to know whether there are more events.
+	opt.MaxCommits = int(first) + 1
+	events, limitHit, complete, err := git.Pickaxe(ctx, *cachedRepo, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Omit the events in files that the user may not read. The commits of omitted
+	// events still count toward the limit, so that limitHit doesn't depend on the permissions.
+	perms, err := backend.SubRepoPerms(ctx, repo.repo)
+	if err != nil {
+		return nil, err
+	}
+	result := &pickaxeResultResolver{limitHit: limitHit, timedOut: !complete}
+	commits := map[api.CommitID]bool{}
+	for _, e := range events {
+		if !commits[e.Commit.ID] {
+			if len(commits) == int(first) {
+				result.limitHit = true
+				break
+			}
+			commits[e.Commit.ID] = true
+		}
+		if !perms.Allowed(e.Path) || (e.OrigPath != "" && !perms.Allowed(e.OrigPath)) {
+			continue
+		}
+		result.events = append(result.events, &pickaxeEventResolver{repo: repo, event: e})
+	}
+	return result, nil
+}
+
+type pickaxeResultResolver struct {
+	events   []*pickaxeEventResolver
+	limitHit bool
+	timedOut bool
+}
+
+func (r *pickaxeResultResolver) Events() []*pickaxeEventResolver { return r.events }
+func (r *pickaxeResultResolver) LimitHit() bool                  { return r.limitHit }
+func (r *pickaxeResultResolver) TimedOut() bool                  { return r.timedOut }
+
+type pickaxeEventResolver struct {
+	repo  *RepositoryResolver
+	event *git.PickaxeEvent
+}
+
+func (r *pickaxeEventResolver) Kind() string { return string(r.event.Kind) }
+
+func (r *pickaxeEventResolver) Commit() *GitCommitResolver {
+	return toGitCommitResolver(r.repo, &r.event.Commit)
+}
+
+func (r *pickaxeEventResolver) Path() string { return r.event.Path }
+
+func (r *pickaxeEventResolver) OldPath() *string {
+	if r.event.OrigPath == "" {
+		return nil
+	}
+	return &r.event.OrigPath
+}
+
+func (r *pickaxeEventResolver) Hunks() []*DiffHunk {
+	hunks := make([]*DiffHunk, len(r.event.Hunks))
+	for i, h := range r.event.Hunks {
+		hunks[i] = NewDiffHunk(h)
+	}
+	return hunks
+}
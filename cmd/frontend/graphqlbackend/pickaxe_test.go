@@ -0,0 +1,103 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestRepository_Pickaxe(t *testing.T) {
+	resetMocks()
+	defer git.ResetMocks()
+
+	db.Mocks.Repos.MockGetByName(t, "github.com/gorilla/mux", 2)
+	backend.Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secret", Allow: false}}}, nil
+	}
+	var gotOpt git.PickaxeOptions
+	git.Mocks.Pickaxe = func(opt git.PickaxeOptions) ([]*git.PickaxeEvent, bool, bool, error) {
+		gotOpt = opt
+		return []*git.PickaxeEvent{
+			{Commit: git.Commit{ID: "cccccccccccccccccccccccccccccccccccccccc"}, Kind: git.PickaxeRemoved, Path: "secret/b.go"},
+			{Commit: git.Commit{ID: "cccccccccccccccccccccccccccccccccccccccc"}, Kind: git.PickaxeRemoved, Path: "a.go"},
+			{Commit: git.Commit{ID: "cccccccccccccccccccccccccccccccccccccccc"}, Kind: git.PickaxeIntroduced, Path: "c.go", OrigPath: "secret/c.go"},
+			{Commit: git.Commit{ID: "dddddddddddddddddddddddddddddddddddddddd"}, Kind: git.PickaxeIntroduced, Path: "a.go"},
+		}, false, true, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repository(name: "github.com/gorilla/mux") {
+						pickaxe(query: "callFoo()", range: "v1..v2", paths: ["a.go"], first: 1) {
+							events { kind path oldPath commit { oid } }
+							limitHit
+							timedOut
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repository": {
+						"pickaxe": {
+							"events": [
+								{"kind": "REMOVED", "path": "a.go", "oldPath": null, "commit": {"oid": "cccccccccccccccccccccccccccccccccccccccc"}}
+							],
+							"limitHit": true,
+							"timedOut": false
+						}
+					}
+				}
+			`,
+		},
+	})
+
+	want := git.PickaxeOptions{Query: "callFoo()", IsCaseSensitive: true, Range: "v1..v2", Paths: []string{"a.go"}, MaxCommits: 2}
+	if !reflect.DeepEqual(gotOpt, want) {
+		t.Errorf("got options %+v, want %+v", gotOpt, want)
+	}
+
+	// The limit is hit if git found more commits than requested, even if all of their events are
+	// omitted.
+	git.Mocks.Pickaxe = func(opt git.PickaxeOptions) ([]*git.PickaxeEvent, bool, bool, error) {
+		return []*git.PickaxeEvent{
+			{Commit: git.Commit{ID: "cccccccccccccccccccccccccccccccccccccccc"}, Kind: git.PickaxeRemoved, Path: "secret/b.go"},
+		}, true, true, nil
+	}
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repository(name: "github.com/gorilla/mux") {
+						pickaxe(query: "callFoo()", first: 1) {
+							events { path }
+							limitHit
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repository": {
+						"pickaxe": {
+							"events": [],
+							"limitHit": true
+						}
+					}
+				}
+			`,
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type pullRequestResolver struct {
+	repo *RepositoryResolver
+	pr   *db.PullRequest
+}
+
+func (r *pullRequestResolver) Number() int32 { return int32(r.pr.Number) }
+
+func (r *pullRequestResolver) Title() string { return r.pr.Title }
+
+func (r *pullRequestResolver) URL() string { return r.pr.URL }
+
+func (r *pullRequestResolver) MergeCommit() *GitCommitResolver {
+	if r.pr.MergeCommitID == "" {
+		return nil
+	}
+	return &GitCommitResolver{repo: r.repo, includeUserInfo: true, oid: GitObjectID(r.pr.MergeCommitID)}
+}
+
+func (r *pullRequestResolver) MergedAt() DateTime { return DateTime{Time: r.pr.MergedAt} }
+
+func (r *GitCommitResolver) AssociatedPullRequests(ctx context.Context) ([]*pullRequestResolver, error) {
+	return associatedPullRequests(ctx, r.repo, api.CommitID(r.oid))
+}
+
+func (r *hunkResolver) PullRequest(ctx context.Context) (*pullRequestResolver, error) {
+	prs, err := associatedPullRequests(ctx, r.repo, r.hunk.CommitID)
+	if err != nil || len(prs) == 0 {
+		return nil, err
+	}
+	return prs[0], nil
+}
+
+func associatedPullRequests(ctx context.Context, repo *RepositoryResolver, commitID api.CommitID) ([]*pullRequestResolver, error) {
+	prs, err := db.PullRequests.ListByCommit(ctx, repo.repo.ID, commitID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*pullRequestResolver, len(prs))
+	for i, pr := range prs {
+		resolvers[i] = &pullRequestResolver{repo: repo, pr: pr}
+	}
+	return resolvers, nil
+}
@@ -0,0 +1,79 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestGitCommit_AssociatedPullRequests(t *testing.T) {
+	resetMocks()
+	db.Mocks.Repos.MockGetByName(t, "github.com/gorilla/mux", 2)
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return exampleCommitSHA1, nil
+	}
+	backend.Mocks.Repos.MockGetCommit_Return_NoCheck(t, &git.Commit{ID: exampleCommitSHA1})
+	mergedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Mocks.PullRequests.ListByCommit = func(_ context.Context, repoID api.RepoID, commitID api.CommitID) ([]*db.PullRequest, error) {
+		if repoID != 2 || commitID != exampleCommitSHA1 {
+			t.Errorf("got repo %d and commit %q", repoID, commitID)
+		}
+		return []*db.PullRequest{
+			{ID: 1, RepoID: 2, Number: 7, Title: "Fix a", URL: "https://github.com/gorilla/mux/pull/7", MergeCommitID: "cccccccccccccccccccccccccccccccccccccccc", MergedAt: mergedAt},
+			{ID: 2, RepoID: 2, Number: 8, Title: "Fix b", URL: "https://github.com/gorilla/mux/pull/8", MergedAt: mergedAt},
+		}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repository(name: "github.com/gorilla/mux") {
+						commit(rev: "abc") {
+							associatedPullRequests {
+								number
+								title
+								url
+								mergeCommit { oid }
+								mergedAt
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repository": {
+						"commit": {
+							"associatedPullRequests": [
+								{
+									"number": 7,
+									"title": "Fix a",
+									"url": "https://github.com/gorilla/mux/pull/7",
+									"mergeCommit": {"oid": "cccccccccccccccccccccccccccccccccccccccc"},
+									"mergedAt": "2020-01-02T03:04:05Z"
+								},
+								{
+									"number": 8,
+									"title": "Fix b",
+									"url": "https://github.com/gorilla/mux/pull/8",
+									"mergeCommit": null,
+									"mergedAt": "2020-01-02T03:04:05Z"
+								}
+							]
+						}
+					}
+				}
+			`,
+		},
+	})
+}
@@ -0,0 +1,173 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+// quotaResourceEnums are the values of the GraphQL enum QuotaResource.
+var quotaResourceEnums = map[db.QuotaResource]string{
+	db.QuotaRepositories:             "REPOSITORIES",
+	db.QuotaSavedSearchNotifications: "SAVED_SEARCH_NOTIFICATIONS",
+	db.QuotaCampaigns:                "CAMPAIGNS",
+	db.QuotaChangesets:               "CHANGESETS",
+	db.QuotaLSIFUploadBytes:          "LSIF_UPLOAD_BYTES",
+	db.QuotaAccessTokens:             "ACCESS_TOKENS",
+}
+
+func quotaResourceFromEnum(enum string) (db.QuotaResource, error) {
+	for r, e := range quotaResourceEnums {
+		if e == enum {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("unknown quota resource %q", enum)
+}
+
+// unmarshalQuotaNamespace returns the namespace of an organization or user ID, or the namespace of
+// the default quota if id is nil.
+func unmarshalQuotaNamespace(id *graphql.ID) (ns db.QuotaNamespace, err error) {
+	if id == nil {
+		return ns, nil
+	}
+	switch relay.UnmarshalKind(*id) {
+	case "Org":
+		ns.OrgID, err = UnmarshalOrgID(*id)
+	case "User":
+		ns.UserID, err = UnmarshalUserID(*id)
+	default:
+		err = errors.New("invalid ID for quota namespace (must be an organization or user ID)")
+	}
+	return ns, err
+}
+
+func (o *OrgResolver) Quota(ctx context.Context) (*quotaResolver, error) {
+	// 🚨 SECURITY: Only org members and site admins may view the organization's quota and usage.
+	if err := backend.CheckOrgAccess(ctx, o.org.ID); err != nil {
+		return nil, err
+	}
+	return newQuotaResolver(ctx, db.QuotaNamespace{OrgID: o.org.ID})
+}
+
+func (r *UserResolver) Quota(ctx context.Context) (*quotaResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins may view the user's quota and usage.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+	return newQuotaResolver(ctx, db.QuotaNamespace{UserID: r.user.ID})
+}
+
+func (r *siteResolver) DefaultQuota(ctx context.Context) (*quotaResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the default quota.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return newQuotaResolver(ctx, db.QuotaNamespace{})
+}
+
+func newQuotaResolver(ctx context.Context, ns db.QuotaNamespace) (*quotaResolver, error) {
+	quota, err := db.Quotas.GetEffective(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &quotaResolver{isDefault: quota.Namespace.IsDefault()}
+	for _, resource := range db.QuotaResources {
+		if !ns.IsDefault() && !resource.AppliesTo(ns) {
+			continue
+		}
+		u := &quotaResourceUsageResolver{resource: resource}
+		if limit, ok := quota.Limits[resource]; ok {
+			f := float64(limit)
+			u.limit = &f
+		}
+		if !ns.IsDefault() {
+			usage, err := db.Quotas.Usage(ctx, ns, resource)
+			if err != nil {
+				return nil, err
+			}
+			f := float64(usage)
+			u.usage = &f
+		}
+		r.resources = append(r.resources, u)
+	}
+	return r, nil
+}
+
+type quotaResolver struct {
+	isDefault bool
+	resources []*quotaResourceUsageResolver
+}
+
+func (r *quotaResolver) IsDefault() bool { return r.isDefault }
+
+func (r *quotaResolver) Resources() []*quotaResourceUsageResolver { return r.resources }
+
+type quotaResourceUsageResolver struct {
+	resource     db.QuotaResource
+	limit, usage *float64
+}
+
+func (r *quotaResourceUsageResolver) Resource() string { return quotaResourceEnums[r.resource] }
+
+func (r *quotaResourceUsageResolver) Limit() *float64 { return r.limit }
+
+func (r *quotaResourceUsageResolver) Usage() *float64 { return r.usage }
+
+func (r *schemaResolver) SetQuota(ctx context.Context, args *struct {
+	Namespace *graphql.ID
+	Limits    []*struct {
+		Resource string
+		Limit    float64
+	}
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may set quotas.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	ns, err := unmarshalQuotaNamespace(args.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	quota := &db.Quota{Namespace: ns, Limits: map[db.QuotaResource]int64{}}
+	for _, l := range args.Limits {
+		resource, err := quotaResourceFromEnum(l.Resource)
+		if err != nil {
+			return nil, err
+		}
+		if l.Limit < 0 || l.Limit != math.Trunc(l.Limit) || l.Limit > math.MaxInt64 {
+			return nil, fmt.Errorf("invalid limit for %s: %v (must be a non-negative integer)", l.Resource, l.Limit)
+		}
+		quota.Limits[resource] = int64(l.Limit)
+	}
+	if err := db.Quotas.Set(ctx, quota); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DeleteQuota(ctx context.Context, args *struct {
+	Namespace *graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may delete quotas.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	ns, err := unmarshalQuotaNamespace(args.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Quotas.Delete(ctx, ns); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
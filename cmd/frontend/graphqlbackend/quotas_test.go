@@ -0,0 +1,148 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestOrgQuota(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.Orgs.GetByName = func(context.Context, string) (*types.Org, error) {
+		return &types.Org{ID: 1, Name: "acme"}, nil
+	}
+	db.Mocks.Quotas.GetEffective = func(_ context.Context, ns db.QuotaNamespace) (*db.Quota, error) {
+		return &db.Quota{Namespace: ns, Limits: map[db.QuotaResource]int64{db.QuotaCampaigns: 3}}, nil
+	}
+	db.Mocks.Quotas.Usage = func(_ context.Context, ns db.QuotaNamespace, resource db.QuotaResource) (int64, error) {
+		if ns.OrgID != 1 {
+			t.Errorf("got org ID %d, want 1", ns.OrgID)
+		}
+		if resource == db.QuotaCampaigns {
+			return 2, nil
+		}
+		return 0, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					organization(name: "acme") {
+						quota {
+							isDefault
+							resources {
+								resource
+								limit
+								usage
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"organization": {
+						"quota": {
+							"isDefault": false,
+							"resources": [
+								{"resource": "REPOSITORIES", "limit": null, "usage": 0},
+								{"resource": "SAVED_SEARCH_NOTIFICATIONS", "limit": null, "usage": 0},
+								{"resource": "CAMPAIGNS", "limit": 3, "usage": 2},
+								{"resource": "CHANGESETS", "limit": null, "usage": 0},
+								{"resource": "LSIF_UPLOAD_BYTES", "limit": null, "usage": 0}
+							]
+						}
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestSetQuota_invalidLimit(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+
+	type limit = struct {
+		Resource string
+		Limit    float64
+	}
+	for _, l := range []*limit{
+		{Resource: "CAMPAIGNS", Limit: -1},
+		{Resource: "CAMPAIGNS", Limit: 1.5},
+		{Resource: "UNKNOWN", Limit: 1},
+	} {
+		_, err := (&schemaResolver{}).SetQuota(context.Background(), &struct {
+			Namespace *graphql.ID
+			Limits    []*struct {
+				Resource string
+				Limit    float64
+			}
+		}{Limits: []*limit{l}})
+		if err == nil {
+			t.Errorf("%+v: got nil error, want error", *l)
+		}
+	}
+}
+
+func TestSetQuota(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	var set *db.Quota
+	db.Mocks.Quotas.Set = func(_ context.Context, quota *db.Quota) error {
+		set = quota
+		return nil
+	}
+	var deleted *db.QuotaNamespace
+	db.Mocks.Quotas.Delete = func(_ context.Context, ns db.QuotaNamespace) error {
+		deleted = &ns
+		return nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				mutation {
+					setQuota(namespace: "T3JnOjE=", limits: [{resource: CAMPAIGNS, limit: 3}, {resource: LSIF_UPLOAD_BYTES, limit: 1000}]) {
+						alwaysNil
+					}
+				}
+			`,
+			ExpectedResult: `{"setQuota": {"alwaysNil": null}}`,
+		},
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				mutation {
+					deleteQuota(namespace: "T3JnOjE=") {
+						alwaysNil
+					}
+				}
+			`,
+			ExpectedResult: `{"deleteQuota": {"alwaysNil": null}}`,
+		},
+	})
+
+	want := &db.Quota{Namespace: db.QuotaNamespace{OrgID: 1}, Limits: map[db.QuotaResource]int64{db.QuotaCampaigns: 3, db.QuotaLSIFUploadBytes: 1000}}
+	if !reflect.DeepEqual(set, want) {
+		t.Errorf("got quota %+v, want %+v", set, want)
+	}
+	if want := (db.QuotaNamespace{OrgID: 1}); deleted == nil || *deleted != want {
+		t.Errorf("got deleted namespace %+v, want %+v", deleted, want)
+	}
+}
@@ -0,0 +1,390 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+var errRepoAccessRequestsDisabled = errors.New("repository access requests are not enabled (site configuration permissions.accessRequests)")
+
+func (*schemaResolver) RequestRepositoryAccess(ctx context.Context, args *struct {
+	Repository    string
+	Justification string
+}) (*repositoryAccessRequestResolver, error) {
+	if !backend.RepoAccessRequestsEnabled() {
+		return nil, errRepoAccessRequestsDisabled
+	}
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := strings.TrimSpace(args.Repository)
+	if pattern == "" {
+		return nil, errors.New("repository must not be empty")
+	}
+	if strings.TrimSpace(args.Justification) == "" {
+		return nil, errors.New("justification must not be empty")
+	}
+
+	// 🚨 SECURITY: The matching repositories are only used to resolve the approvers. Nothing about
+	// them (not even whether any exist) may be revealed to the requesting user.
+	repos, err := backend.ReposMatchingAccessPattern(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	approverIDs, err := backend.RepoAccessApproverIDs(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+	request := &db.RepoAccessRequest{
+		UserID:        user.ID,
+		RepoPattern:   pattern,
+		Justification: args.Justification,
+	}
+	for _, id := range approverIDs {
+		if id != user.ID {
+			request.ApproverUserIDs = append(request.ApproverUserIDs, id)
+		}
+	}
+	if err := db.RepoAccessRequests.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// The request is recorded regardless of whether the approvers can be notified, so failures are
+	// only logged.
+	if conf.CanSendEmail() {
+		for _, id := range request.ApproverUserIDs {
+			if err := sendRepoAccessRequestEmail(ctx, id, repoAccessRequestEmailTemplates, struct {
+				Requester     string
+				Pattern       string
+				Justification string
+				URL           string
+			}{
+				Requester:     user.Username,
+				Pattern:       request.RepoPattern,
+				Justification: request.Justification,
+				URL:           globals.ExternalURL().ResolveReference(repoAccessRequestsURL()).String(),
+			}); err != nil {
+				log15.Warn("Failed to notify approver of repository access request.", "request", request.ID, "approver", id, "error", err)
+			}
+		}
+	}
+
+	return &repositoryAccessRequestResolver{v: request}, nil
+}
+
+func (*schemaResolver) ReviewRepositoryAccessRequest(ctx context.Context, args *struct {
+	Request   graphql.ID
+	Approve   bool
+	Comment   *string
+	ExpiresAt *DateTime
+}) (*repositoryAccessRequestResolver, error) {
+	if !backend.RepoAccessRequestsEnabled() {
+		return nil, errRepoAccessRequestsDisabled
+	}
+	reviewer, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := unmarshalRepositoryAccessRequestID(args.Request)
+	if err != nil {
+		return nil, err
+	}
+	request, err := db.RepoAccessRequests.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the approvers notified of the request may review it, and
+	// nobody may review their own request.
+	if !reviewer.SiteAdmin && !containsUserID(request.ApproverUserIDs, reviewer.ID) {
+		return nil, backend.ErrMustBeSiteAdmin
+	}
+	if request.UserID == reviewer.ID {
+		return nil, errors.New("unable to review your own repository access request")
+	}
+	if !request.Pending() {
+		return nil, errors.New("repository access request was already reviewed")
+	}
+
+	review := db.RepoAccessReview{
+		ReviewerUserID: reviewer.ID,
+		Approve:        args.Approve,
+	}
+	if args.Comment != nil {
+		review.Comment = *args.Comment
+	}
+	if args.Approve {
+		if args.ExpiresAt != nil {
+			if !args.ExpiresAt.Time.After(time.Now()) {
+				return nil, errors.New("expiresAt must be in the future")
+			}
+			review.ExpiresAt = &args.ExpiresAt.Time
+		}
+		maxDuration, err := backend.RepoAccessMaxDuration()
+		if err != nil {
+			return nil, err
+		}
+		if maxDuration > 0 && (review.ExpiresAt == nil || review.ExpiresAt.After(time.Now().Add(maxDuration))) {
+			return nil, fmt.Errorf("expiresAt must be within %s (site configuration permissions.accessRequests.maxDuration)", maxDuration)
+		}
+
+		// 🚨 SECURITY: Only grant access to the repositories that the reviewer may approve access
+		// to. The approvers of a request may differ per repository.
+		repos, err := backend.ReposMatchingAccessPattern(ctx, request.RepoPattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			ok, err := backend.CanApproveRepoAccess(ctx, reviewer, repo)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				review.RepoIDs = append(review.RepoIDs, repo.ID)
+			}
+		}
+		if len(repos) > 0 && len(review.RepoIDs) == 0 {
+			return nil, errors.New("you may not approve access to any of the requested repositories")
+		}
+	}
+
+	request, err = db.RepoAccessRequests.Review(ctx, id, review)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.CanSendEmail() {
+		state := "denied"
+		if args.Approve {
+			state = "approved"
+		}
+		if err := sendRepoAccessRequestEmail(ctx, request.UserID, repoAccessReviewEmailTemplates, struct {
+			Reviewer string
+			Pattern  string
+			State    string
+			Comment  string
+		}{
+			Reviewer: reviewer.Username,
+			Pattern:  request.RepoPattern,
+			State:    state,
+			Comment:  request.ReviewComment,
+		}); err != nil {
+			log15.Warn("Failed to notify user of repository access request review.", "request", request.ID, "error", err)
+		}
+	}
+
+	return &repositoryAccessRequestResolver{v: request}, nil
+}
+
+func (*schemaResolver) RepositoryAccessRequests(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Pending bool
+}) (*repositoryAccessRequestConnectionResolver, error) {
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opt := db.RepoAccessRequestsListOptions{OnlyPending: args.Pending}
+	// 🚨 SECURITY: Site admins can review all requests; other users only the requests that they
+	// were notified of as approvers.
+	if !user.SiteAdmin {
+		opt.ApproverUserID = user.ID
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &repositoryAccessRequestConnectionResolver{opt: opt}, nil
+}
+
+func (r *UserResolver) RepositoryAccessRequests(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*repositoryAccessRequestConnectionResolver, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to access the user's access requests.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+	opt := db.RepoAccessRequestsListOptions{UserID: r.user.ID}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &repositoryAccessRequestConnectionResolver{opt: opt}, nil
+}
+
+type repositoryAccessRequestConnectionResolver struct {
+	opt db.RepoAccessRequestsListOptions
+}
+
+func (r *repositoryAccessRequestConnectionResolver) Nodes(ctx context.Context) ([]*repositoryAccessRequestResolver, error) {
+	requests, err := db.RepoAccessRequests.List(ctx, r.opt)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*repositoryAccessRequestResolver, len(requests))
+	for i, request := range requests {
+		resolvers[i] = &repositoryAccessRequestResolver{v: request}
+	}
+	return resolvers, nil
+}
+
+func (r *repositoryAccessRequestConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.RepoAccessRequests.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+// repositoryAccessRequestResolver implements the GraphQL type RepositoryAccessRequest.
+type repositoryAccessRequestResolver struct {
+	v *db.RepoAccessRequest
+}
+
+func repositoryAccessRequestByID(ctx context.Context, id graphql.ID) (*repositoryAccessRequestResolver, error) {
+	requestID, err := unmarshalRepositoryAccessRequestID(id)
+	if err != nil {
+		return nil, err
+	}
+	request, err := db.RepoAccessRequests.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the requesting user, the approvers and site admins may view the request.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, request.UserID); err != nil {
+		user, err2 := db.Users.GetByCurrentAuthUser(ctx)
+		if err2 != nil || !containsUserID(request.ApproverUserIDs, user.ID) {
+			return nil, err
+		}
+	}
+	return &repositoryAccessRequestResolver{v: request}, nil
+}
+
+func (r *repositoryAccessRequestResolver) ID() graphql.ID {
+	return marshalRepositoryAccessRequestID(r.v.ID)
+}
+
+func marshalRepositoryAccessRequestID(id int64) graphql.ID {
+	return relay.MarshalID("RepositoryAccessRequest", id)
+}
+
+func unmarshalRepositoryAccessRequestID(id graphql.ID) (requestID int64, err error) {
+	err = relay.UnmarshalSpec(id, &requestID)
+	return
+}
+
+func (r *repositoryAccessRequestResolver) User(ctx context.Context) (*UserResolver, error) {
+	return UserByIDInt32(ctx, r.v.UserID)
+}
+
+func (r *repositoryAccessRequestResolver) RepositoryPattern() string { return r.v.RepoPattern }
+
+func (r *repositoryAccessRequestResolver) Justification() string { return r.v.Justification }
+
+func (r *repositoryAccessRequestResolver) State() string {
+	switch {
+	case r.v.Approved == nil:
+		return "PENDING"
+	case *r.v.Approved:
+		return "APPROVED"
+	default:
+		return "DENIED"
+	}
+}
+
+func (r *repositoryAccessRequestResolver) Reviewer(ctx context.Context) (*UserResolver, error) {
+	if r.v.ReviewerUserID == nil {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, *r.v.ReviewerUserID)
+}
+
+func (r *repositoryAccessRequestResolver) ReviewedAt() *DateTime {
+	return DateTimeOrNil(r.v.ReviewedAt)
+}
+
+func (r *repositoryAccessRequestResolver) ReviewComment() string { return r.v.ReviewComment }
+
+func (r *repositoryAccessRequestResolver) ExpiresAt() *DateTime {
+	return DateTimeOrNil(r.v.ExpiresAt)
+}
+
+func (r *repositoryAccessRequestResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.v.CreatedAt}
+}
+
+func containsUserID(ids []int32, id int32) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// repoAccessRequestsURL is the URL where approvers can review repository access requests (via the
+// repositoryAccessRequests query and reviewRepositoryAccessRequest mutation).
+func repoAccessRequestsURL() *url.URL {
+	return &url.URL{Path: "/api/console"}
+}
+
+// sendRepoAccessRequestEmail sends an email to the user's verified primary email address, if any.
+func sendRepoAccessRequestEmail(ctx context.Context, userID int32, template txtypes.Templates, data interface{}) error {
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, userID)
+	if err != nil || !verified {
+		return err
+	}
+	return txemail.Send(ctx, txemail.Message{
+		To:       []string{email},
+		Template: template,
+		Data:     data,
+	})
+}
+
+var repoAccessRequestEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Requester}} requested access to {{.Pattern}} on Sourcegraph`,
+	Text: `
+{{.Requester}} requested read access to the repositories matching {{.Pattern}} on Sourcegraph:
+
+  {{.Justification}}
+
+To review the request, use the reviewRepositoryAccessRequest mutation of the GraphQL API:
+
+  {{.URL}}
+`,
+	HTML: `
+<p>
+  <strong>{{.Requester}}</strong> requested read access to the repositories matching
+  <strong>{{.Pattern}}</strong> on Sourcegraph:
+</p>
+
+<blockquote>{{.Justification}}</blockquote>
+
+<p>To review the request, use the reviewRepositoryAccessRequest mutation of the <a href="{{.URL}}">GraphQL API</a>.</p>
+`,
+})
+
+var repoAccessReviewEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Your request for access to {{.Pattern}} was {{.State}}`,
+	Text: `
+{{.Reviewer}} {{.State}} your request for read access to the repositories matching {{.Pattern}} on Sourcegraph.
+{{if .Comment}}
+  {{.Comment}}
+{{end}}`,
+	HTML: `
+<p>
+  <strong>{{.Reviewer}}</strong> {{.State}} your request for read access to the repositories matching
+  <strong>{{.Pattern}}</strong> on Sourcegraph.
+</p>
+{{if .Comment}}
+<blockquote>{{.Comment}}</blockquote>
+{{end}}`,
+})
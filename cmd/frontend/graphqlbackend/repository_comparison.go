@@ -158,6 +158,10 @@ func (r *fileDiffConnectionResolver) compute(ctx context.Context) ([]*diff.FileD
 		if err != nil {
 			return nil, err
 		}
+		perms, err := backend.SubRepoPerms(ctx, r.cmp.repo.repo)
+		if err != nil {
+			return nil, err
+		}
 		rdr, err := git.ExecReader(ctx, *cachedRepo, []string{
 			"diff",
 			"--find-renames",
@@ -186,14 +190,23 @@ func (r *fileDiffConnectionResolver) compute(ctx context.Context) ([]*diff.FileD
 			if err != nil {
 				return nil, err
 			}
+			// 🚨 SECURITY: Omit diffs of files that the user may not read.
+			if !fileDiffAllowed(perms, fileDiff) {
+				continue
+			}
 			fileDiffs = append(fileDiffs, fileDiff)
 			if r.first != nil && len(fileDiffs) == int(*r.first) {
 				// Check for hasNextPage.
-				_, err := dr.ReadFile()
-				if err != nil && err != io.EOF {
-					return nil, err
+				for {
+					next, err := dr.ReadFile()
+					if err != nil && err != io.EOF {
+						return nil, err
+					}
+					if err == io.EOF || fileDiffAllowed(perms, next) {
+						r.hasNextPage = err != io.EOF
+						break
+					}
 				}
-				r.hasNextPage = err != io.EOF
 				break
 			}
 		}
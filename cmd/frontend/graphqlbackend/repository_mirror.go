@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
@@ -172,6 +173,58 @@ func (r *updateQueueResolver) Total() int32 {
 	return int32(r.queue.Total)
 }
 
+func (r *repositoryMirrorInfoResolver) PushMirrors(ctx context.Context) ([]*pushMirrorResolver, error) {
+	// 🚨 SECURITY: The push mirrors of repositories and their errors are site configuration, so
+	// only site admins may view them.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	info, err := r.gitserverRepoInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*pushMirrorResolver, len(info.PushMirrors))
+	for i, status := range info.PushMirrors {
+		resolvers[i] = &pushMirrorResolver{status: status}
+	}
+	return resolvers, nil
+}
+
+type pushMirrorResolver struct {
+	status *protocol.PushMirrorStatus
+}
+
+func (r *pushMirrorResolver) URL() string { return r.status.URL }
+
+func (r *pushMirrorResolver) Refspecs() []string { return r.status.Refspecs }
+
+func (r *pushMirrorResolver) LastAttemptAt() *DateTime { return DateTimeOrNil(r.status.LastAttempt) }
+
+func (r *pushMirrorResolver) LastSucceededAt() *DateTime {
+	return DateTimeOrNil(r.status.LastSuccess)
+}
+
+func (r *pushMirrorResolver) LastError() *string {
+	if r.status.LastError == "" {
+		return nil
+	}
+	return &r.status.LastError
+}
+
+func (r *pushMirrorResolver) FailedAttempts() int32 { return int32(r.status.FailedAttempts) }
+
+func (r *pushMirrorResolver) NextRetryAt() *DateTime { return DateTimeOrNil(r.status.NextRetry) }
+
+func (r *pushMirrorResolver) PendingSince() *DateTime { return DateTimeOrNil(r.status.PendingSince) }
+
+func (r *pushMirrorResolver) LagSeconds() int32 {
+	if r.status.PendingSince == nil {
+		return 0
+	}
+	return int32(time.Since(*r.status.PendingSince) / time.Second)
+}
+
 func (r *schemaResolver) CheckMirrorRepositoryConnection(ctx context.Context, args *struct {
 	Repository *graphql.ID
 	Name       *string
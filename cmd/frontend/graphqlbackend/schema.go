@@ -48,6 +48,18 @@ type Mutation {
         # created from this campaign plan.
         patches: [CampaignPlanPatch!]!
     ): CampaignPlan!
+    # Create a campaign plan by running a campaign script on the server. The script (one of the
+    # "campaigns.scripts" in the site configuration) runs in a temporary checkout of the default branch
+    # of each repository, and the changes it makes become the campaign plan's patches.
+    #
+    # The script runs in the background. Use the CampaignPlan.status and CampaignPlan.scriptRuns
+    # fields to follow its progress.
+    createCampaignPlanFromScript(
+        # The name of the campaign script.
+        script: String!
+        # The repositories to run the campaign script in.
+        repositories: [ID!]!
+    ): CampaignPlan!
     # Updates a campaign.
     updateCampaign(input: UpdateCampaignInput!): Campaign!
     # Retries creating changesets of the campaign plan that could not be successfully created on the code host.
@@ -197,6 +209,10 @@ type Mutation {
     # - "user:all": Full control of all resources accessible to the user account.
     # - "site-admin:sudo": Ability to perform any action as any other user. (Only site admins may create tokens
     #   with this scope.)
+    # - "feeds:read": Read access to the user's Atom feeds (and nothing else). A token with this scope must
+    #   not have other scopes.
+    #
+    # All other tokens must have the "user:all" scope.
     #
     # Only the user or site admins may perform this mutation.
     createAccessToken(user: ID!, scopes: [String!]!, note: String!): CreateAccessTokenResult!
@@ -356,6 +372,56 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     reloadSite: EmptyResponse
+    # Enables or disables maintenance mode, in which the site is read-only: mutations (other than this
+    # one) and HTTP API endpoints that write are rejected, and background jobs that write to the
+    # database are paused. It sets the maintenanceMode critical configuration property.
+    #
+    # Only site admins may perform this mutation.
+    setMaintenanceMode(
+        # Whether maintenance mode is enabled.
+        enabled: Boolean!
+        # The message shown to users while maintenance mode is enabled.
+        message: String
+    ): EmptyResponse!
+    # Sets the resource quota of an organization or user (overriding the default quota), or the
+    # default quota. Resources that are not listed in limits are not limited.
+    #
+    # Only site admins may perform this mutation.
+    setQuota(
+        # The ID of the organization or user, or null for the default quota.
+        namespace: ID
+        # The limits of the resources.
+        limits: [QuotaLimitInput!]!
+    ): EmptyResponse!
+    # Deletes the resource quota of an organization or user, so that the default quota applies to
+    # it again, or deletes the default quota.
+    #
+    # Only site admins may perform this mutation.
+    deleteQuota(
+        # The ID of the organization or user, or null for the default quota.
+        namespace: ID
+    ): EmptyResponse!
+    # Watches a repository, or the paths of a repository that match a glob pattern, so that the
+    # commits that change them are added to the viewer's activity feed. Watching the same
+    # repository and path pattern again updates the existing watch.
+    watch(
+        # The repository to watch.
+        repository: ID!
+        # A glob pattern of the paths to watch (such as "pkg/auth/**"), relative to the repository
+        # root, or null to watch the whole repository. "*" doesn't match "/", "**" matches any
+        # number of directories, and a path without wildcards matches everything below it.
+        pathPattern: String
+        # Whether to email a daily digest of the watch's new commits to the viewer's verified
+        # primary email address.
+        emailDigest: Boolean = false
+    ): Watch!
+    # Deletes a watch and its activity feed entries.
+    #
+    # Only the watch's user and site admins may perform this mutation.
+    unwatch(
+        # The watch to delete.
+        watch: ID!
+    ): EmptyResponse!
     # Submits a user satisfaction (NPS) survey.
     submitSurvey(input: SurveySubmissionInput!): EmptyResponse
     # Submits a request for a Sourcegraph Enterprise trial license.
@@ -403,6 +469,91 @@ type Mutation {
         # The level of repository permission.
         perm: RepositoryPermission = READ
     ): EmptyResponse!
+    # Restricts read access to the paths under pathPrefix in a repository to the given users and the
+    # members of the given organizations, replacing any existing rule for the same path prefix. The rule
+    # with the longest matching path prefix applies to a path. Rules are only enforced when
+    # "permissions.subRepository" is enabled in site configuration.
+    #
+    # Only site admins may perform this mutation.
+    setSubRepositoryPathRule(
+        # The repository that the mutation is applied to.
+        repository: ID!
+        # The path prefix (a directory or file path relative to the repository root) to restrict.
+        pathPrefix: String!
+        # The users that may read the paths under the prefix.
+        users: [ID!]!
+        # The organizations whose members may read the paths under the prefix.
+        orgs: [ID!]!
+    ): EmptyResponse!
+    # Deletes the path rule of a repository for the given path prefix.
+    #
+    # Only site admins may perform this mutation.
+    deleteSubRepositoryPathRule(
+        # The repository that the mutation is applied to.
+        repository: ID!
+        # The path prefix of the rule to delete.
+        pathPrefix: String!
+    ): EmptyResponse!
+    # Requests read access to the repositories matching a name or pattern for the current user. The
+    # approvers of the matching repositories are notified by email. To avoid revealing which
+    # repositories exist, the mutation succeeds even if no repository matches.
+    #
+    # Only available when "permissions.accessRequests" is enabled in site configuration.
+    requestRepositoryAccess(
+        # A repository name (e.g., "github.com/acme/api"), or a pattern in which * matches any
+        # sequence of characters other than / (e.g., "github.com/acme/payments-*").
+        repository: String!
+        # Why the user needs access, shown to the approvers.
+        justification: String!
+    ): RepositoryAccessRequest!
+    # Approves or denies a pending repository access request. Approving it grants the requesting user
+    # read access to the matching repositories that the viewer can approve access to.
+    #
+    # Only site admins and the approvers configured in "permissions.accessRequests" may perform this
+    # mutation.
+    reviewRepositoryAccessRequest(
+        # The request to review.
+        request: ID!
+        # Whether to approve (true) or deny (false) the request.
+        approve: Boolean!
+        # An optional comment for the requesting user.
+        comment: String
+        # When the granted access expires, or null if it never expires. It is required if
+        # "permissions.accessRequests.maxDuration" is set.
+        expiresAt: DateTime
+    ): RepositoryAccessRequest!
+    # Creates a link that lets anyone who knows it view a range of lines of a file at a fixed commit
+    # without signing in, until the link expires, reaches its view limit or is revoked. The link's URL
+    # is only returned by this mutation.
+    #
+    # Only available for repositories matching "snippetShareLinks.repos" in site configuration.
+    createSnippetShareLink(
+        # The repository of the file.
+        repository: ID!
+        # The revision of the file. It is resolved to a commit when the link is created, so later
+        # commits don't change the snippet.
+        revision: String!
+        # The path of the file.
+        path: String!
+        # The first line of the snippet (1-based).
+        startLine: Int!
+        # The last line of the snippet (1-based, inclusive).
+        endLine: Int!
+        # When the link expires. It must be within "snippetShareLinks.maxDuration" (default 168h).
+        expiresAt: DateTime!
+        # A password that viewers must enter to view the snippet. It is required if
+        # "snippetShareLinks.requirePassword" is set.
+        password: String
+        # The maximum number of times the snippet can be viewed, or null for no limit.
+        maxViews: Int
+    ): CreateSnippetShareLinkResult!
+    # Revokes a snippet share link, so that it can no longer be viewed.
+    #
+    # Only the link's creator and site admins may perform this mutation.
+    revokeSnippetShareLink(
+        # The link to revoke.
+        snippetShareLink: ID!
+    ): EmptyResponse!
 }
 
 # A patch to apply to a repository (in a new branch) when a campaign is created from the parent
@@ -493,6 +644,43 @@ type CampaignPlan implements Node {
 
     # The URL where the plan can be previewed and a campaign can be created from it.
     previewURL: String!
+
+    # The runs of the campaign script in each repository, if the plan was created with
+    # createCampaignPlanFromScript. Otherwise, the list is empty.
+    scriptRuns(first: Int): CampaignScriptRunConnection!
+}
+
+# A paginated list of runs of a campaign script.
+type CampaignScriptRunConnection {
+    # A list of runs of a campaign script.
+    nodes: [CampaignScriptRun!]!
+
+    # The total number of runs in the connection.
+    totalCount: Int!
+
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# The run of a campaign script in a repository.
+type CampaignScriptRun {
+    # The repository the campaign script runs in.
+    repository: Repository!
+
+    # The state of the run. It is PROCESSING until the run has finished.
+    state: BackgroundProcessState!
+
+    # When the run started, or null if it's still waiting for a worker.
+    startedAt: DateTime
+
+    # When the run finished, or null if it hasn't finished yet.
+    finishedAt: DateTime
+
+    # The output of the campaign script (truncated if it's too long).
+    log: String!
+
+    # The error that occurred, if the run failed.
+    error: String
 }
 
 # A paginated list of repository diffs committed to git.
@@ -818,6 +1006,9 @@ input AddExternalServiceInput {
     displayName: String!
     # The JSON configuration of the external service.
     config: String!
+    # The ID of the organization whose repository quota the repositories of the external service
+    # count against, if any.
+    namespace: ID
 }
 
 # Fields to update for an existing external service.
@@ -828,6 +1019,9 @@ input UpdateExternalServiceInput {
     displayName: String
     # The updated config, if provided.
     config: String
+    # The ID of the organization whose repository quota the repositories of the external service
+    # count against, if provided. An empty string removes the organization.
+    namespace: ID
 }
 
 # A selection within a file.
@@ -1085,6 +1279,15 @@ type UpdateSettingsPayload {
 }
 
 # The result for Mutation.createAccessToken.
+# The result for Mutation.createSnippetShareLink.
+type CreateSnippetShareLinkResult {
+    # The newly created link.
+    snippetShareLink: SnippetShareLink!
+    # The URL of the link, which contains its secret token. The caller is responsible for storing this
+    # value.
+    url: String!
+}
+
 type CreateAccessTokenResult {
     # The ID of the newly created access token.
     id: ID!
@@ -1335,6 +1538,14 @@ type Query {
         # Returns the first n survey responses from the list.
         first: Int
     ): SurveyResponseConnection!
+    # Repository access requests that the viewer can review, most recent first. Site admins can
+    # review all requests; other approvers can review the requests they were notified of.
+    repositoryAccessRequests(
+        # Returns the first n requests from the list.
+        first: Int
+        # Only return requests that have not been reviewed yet.
+        pending: Boolean = false
+    ): RepositoryAccessRequestConnection!
     # The extension registry.
     extensionRegistry: ExtensionRegistry!
     # Queries that are only used on Sourcegraph.com.
@@ -1367,6 +1578,15 @@ type Query {
     # Returns a list of usernames or emails that have associated pending permissions.
     # The returned list can be used to query authorizedUserRepositories for pending permissions.
     usersWithPendingPermissions: [String!]!
+
+    # (experimental) The clusters of duplicated code (on the default branches of all repositories)
+    # that share the most code, largest first. Only regions of files that the viewer may view are
+    # included. The clusters are recomputed at most once per hour. This is always empty unless the
+    # "codeCloneDetection" experimental feature is enabled.
+    codeCloneClusters(
+        # Returns the first n clusters.
+        first: Int = 20
+    ): [CodeCloneCluster!]!
 }
 
 # The version of the search syntax.
@@ -1408,6 +1628,37 @@ type Search {
     # cached and thus quicker to query. Useful for e.g. querying sparkline
     # data.
     stats: SearchResultsStats!
+    # Aggregates the file content matches of a regexp search (with a single search pattern) by the
+    # value captured by a capture group of the pattern, for example to find which versions of a
+    # dependency are used. Up to 5,000 results are aggregated unless the query specifies a count.
+    aggregation(
+        # The 1-based index of the capture group in the search pattern.
+        captureGroup: Int = 1
+        # The maximum number of distinct captured values to return (at most 1,000).
+        first: Int = 100
+    ): SearchAggregation!
+}
+
+# The matches of a search aggregated by the value captured by a capture group of the search pattern.
+type SearchAggregation {
+    # The distinct captured values, ordered by decreasing number of matches.
+    groups: [SearchAggregationGroup!]!
+    # The number of matches that were aggregated (including those whose values are not in groups
+    # because of the limit on distinct values).
+    matchCount: Int!
+    # Whether the aggregation is approximate, because the search hit a result limit (so not all
+    # matches were aggregated) or because there are more distinct values than requested.
+    approximate: Boolean!
+}
+
+# The matches of a search that captured a value.
+type SearchAggregationGroup {
+    # The captured value.
+    value: String!
+    # The number of aggregated matches that captured the value.
+    count: Int!
+    # A few of the locations of matches that captured the value.
+    sampleLocations: [Location!]!
 }
 
 # Predefined suggestions for search filters when backfill.
@@ -1715,7 +1966,12 @@ enum ExternalServiceKind {
     GITHUB
     GITLAB
     GITOLITE
+    GOMODULES
+    MAVEN
+    NPM
+    PERFORCE
     PHABRICATOR
+    SVN
     OTHER
 }
 
@@ -1739,6 +1995,9 @@ type ExternalService implements Node {
     # It is a field on ExternalService instead of a separate thing in order to
     # not break the API and stay backwards compatible.
     warning: String
+    # The organization whose repository quota the repositories of the external service count
+    # against, if any.
+    namespace: Org
 }
 
 # A list of repositories.
@@ -1912,6 +2171,9 @@ type Repository implements Node & GenericSearchResultInterface {
         after: String
     ): LSIFUploadConnection!
 
+    # The code coverage of this repository, from the coverage reports uploaded for its commits.
+    coverage: RepositoryCoverage!
+
     # A list of authorized users to access this repository with the given permission.
     # This API currently only returns permissions from the Sourcegraph provider, i.e.
     # "permissions.userMapping" in site configuration.
@@ -1923,6 +2185,76 @@ type Repository implements Node & GenericSearchResultInterface {
         # Opaque pagination cursor.
         after: String
     ): UserConnection!
+    # The path rules that restrict read access to paths within this repository, configured via the
+    # setSubRepositoryPathRule mutation. Only site admins may access this field.
+    subRepositoryPathRules: [SubRepositoryPathRule!]!
+    # The commits that introduced or removed a string (like "git log -S", or "git log -G" for regular
+    # expressions), newest first. A commit has an event for each file in which it changed the string.
+    pickaxe(
+        # The string to find.
+        query: String!
+        # Whether the query is a regular expression (in the POSIX extended syntax of Git), which
+        # matches the lines that commits added or removed.
+        isRegExp: Boolean = false
+        # Whether the query is case-sensitive.
+        isCaseSensitive: Boolean = true
+        # The revision or revision range (such as "v1.0..v2.0") whose commits are searched. It
+        # defaults to the default branch.
+        range: String
+        # Only search the changes to these files and directories.
+        paths: [String!]
+        # Returns the events of the first n commits (at most 100).
+        first: Int = 20
+        # The time limit of the search, in seconds (at most 60).
+        timeout: Int = 10
+    ): PickaxeResult!
+}
+
+# The result of a pickaxe search, which finds the commits that introduced or removed a string.
+type PickaxeResult {
+    # The events of the commits that changed the string, newest first.
+    events: [PickaxeEvent!]!
+    # Whether older commits that changed the string were omitted because of the "first" argument.
+    limitHit: Boolean!
+    # Whether the search timed out before it searched all commits.
+    timedOut: Boolean!
+}
+
+# A change to the occurrences of the query of a pickaxe search in a file.
+type PickaxeEvent {
+    # Whether the commit introduced, removed or modified the query.
+    kind: PickaxeEventKind!
+    # The commit.
+    commit: GitCommit!
+    # The path of the file (before the commit, if the commit deleted it).
+    path: String!
+    # The path of the file before the commit, if the commit renamed it.
+    oldPath: String
+    # The hunks of the commit's diff of the file that changed the query.
+    hunks: [FileDiffHunk!]!
+}
+
+# The kind of a pickaxe event.
+enum PickaxeEventKind {
+    # The commit added occurrences of the query to the file.
+    INTRODUCED
+    # The commit removed occurrences of the query from the file.
+    REMOVED
+    # The commit changed lines that match the query without changing the number of occurrences
+    # (only for regular expressions).
+    MODIFIED
+}
+
+# A rule restricting read access to the paths under a path prefix within a repository.
+type SubRepositoryPathRule {
+    # The path prefix that the rule applies to.
+    pathPrefix: String!
+    # The users that may read the paths under the prefix.
+    users: [User!]!
+    # The organizations whose members may read the paths under the prefix.
+    orgs: [Org!]!
+    # The time when the rule was last updated.
+    updatedAt: DateTime!
 }
 
 # A reference to another Sourcegraph instance.
@@ -1966,6 +2298,33 @@ type MirrorRepositoryInfo {
     updateSchedule: UpdateSchedule
     # The state of this repository in the update queue.
     updateQueue: UpdateQueue
+    # The push mirrors that this repository is pushed to after each update (configured in the
+    # "git.pushMirrors" site configuration property). Only site admins may view push mirrors.
+    pushMirrors: [PushMirror!]!
+}
+
+# A Git remote that a repository is pushed to after each update.
+type PushMirror {
+    # The URL of the mirror's Git remote, without credentials.
+    url: String!
+    # The refspecs that are pushed to the mirror.
+    refspecs: [String!]!
+    # When the last push to the mirror started.
+    lastAttemptAt: DateTime
+    # When the last successful push to the mirror started.
+    lastSucceededAt: DateTime
+    # The error of the last push to the mirror, if it failed.
+    lastError: String
+    # The number of consecutive failed pushes to the mirror.
+    failedAttempts: Int!
+    # When a failed push will be retried. Retries back off exponentially, and a push that failed
+    # too many times is only retried after the next update of the repository.
+    nextRetryAt: DateTime
+    # When the oldest update of the repository that wasn't pushed to the mirror yet occurred, if any.
+    pendingSince: DateTime
+    # How far (in seconds) the mirror lags behind the repository: the time since pendingSince, or 0
+    # if the mirror is up to date.
+    lagSeconds: Int!
 }
 
 # The state of a repository in the update schedule.
@@ -2127,6 +2486,30 @@ type RepositoryComparison {
         # Return the first n file diffs from the list.
         first: Int
     ): FileDiffConnection!
+    # The symbols (such as functions, types, and methods) that were added, removed, or modified
+    # between the merge base of the base and head, and the head. Only the first 200 changed files
+    # are compared.
+    symbolChanges: [SymbolChange!]!
+}
+
+# A change to a symbol between two commits.
+type SymbolChange {
+    # The type of change.
+    type: SymbolChangeType!
+    # The symbol in the base commit, or null if the symbol was added.
+    oldSymbol: Symbol
+    # The symbol in the head commit, or null if the symbol was removed.
+    newSymbol: Symbol
+}
+
+# The type of a change to a symbol. Symbols are matched by their name, kind, and container.
+enum SymbolChangeType {
+    # The symbol was added.
+    ADDED
+    # The symbol was removed.
+    REMOVED
+    # The symbol exists in both commits, and lines in its range were changed.
+    MODIFIED
 }
 
 # A list of file diffs.
@@ -2486,6 +2869,12 @@ type GitCommit implements Node {
     subject: String!
     # The contents of the commit message after the first line.
     body: String
+    # The number of the Perforce changelist that this commit was converted from, if its repository is mirrored
+    # from a Perforce depot.
+    perforceChangelist: Int
+    # The number of the Subversion revision that this commit was converted from, if its repository is mirrored
+    # from a Subversion repository.
+    subversionRevision: Int
     # Parent commits of this commit.
     parents: [GitCommit!]!
     # The URL to this commit (using the input revision specifier, which may not be immutable).
@@ -2537,6 +2926,28 @@ type GitCommit implements Node {
         # file paths returned in the list.
         includePatterns: [String!]
     ): SymbolConnection!
+    # The symbols (such as functions, types, and methods) that this commit added, removed, or
+    # modified, compared to its first parent. Only the first 200 changed files are compared.
+    symbolChanges: [SymbolChange!]!
+    # The merged pull requests (or merge requests) that introduced this commit, oldest first. This is
+    # always empty unless the "pullRequestIndexing" experimental feature is enabled and the
+    # repository is on GitHub, GitLab, or Bitbucket Server.
+    associatedPullRequests: [PullRequest!]!
+}
+
+# A merged pull request (or merge request) on a code host.
+type PullRequest {
+    # The number of the pull request in its repository.
+    number: Int!
+    # The title of the pull request.
+    title: String!
+    # The URL to the pull request on the code host.
+    url: String!
+    # The commit that merged the pull request, or null if the code host didn't report one (such as
+    # when the pull request was fast-forwarded).
+    mergeCommit: GitCommit
+    # When the pull request was merged.
+    mergedAt: DateTime!
 }
 
 # A set of Git behind/ahead counts for one commit relative to another.
@@ -2609,6 +3020,14 @@ interface TreeEntry {
         # Recurse into sub-trees.
         recursive: Boolean = false
     ): Boolean!
+    # (experimental) The people with the most expertise in this tree entry's directory (for a file,
+    # the directory containing it), highest score first, based on the recent history of the
+    # repository's default branch. This is always empty unless the "codeExpertise" experimental
+    # feature is enabled.
+    experts(
+        # Returns the first n people.
+        first: Int = 10
+    ): [CodeExpert!]!
 }
 
 # A Git tree in a repository.
@@ -2673,6 +3092,13 @@ type GitTree implements TreeEntry {
         # Recurse into sub-trees.
         recursive: Boolean = false
     ): Boolean!
+    # (experimental) The people with the most expertise in this tree (including its subtrees),
+    # highest score first, based on the recent history of the repository's default branch. This is
+    # always empty unless the "codeExpertise" experimental feature is enabled.
+    experts(
+        # Returns the first n people.
+        first: Int = 10
+    ): [CodeExpert!]!
 }
 
 # A file.
@@ -2788,6 +3214,147 @@ type GitBlob implements TreeEntry & File2 {
     # A wrapper around LSIF query methods. If no LSIF upload can be used to answer code
     # intelligence queries for this path-at-revision, this resolves to null.
     lsif: LSIFQueryResolver
+
+    # The code coverage of this blob, from the coverage report of this blob's commit or (if it has
+    # none) of the nearest ancestor commit that has one. Null if no such report covers this blob.
+    coverage: FileCoverage
+
+    # (experimental) The regions of other files (on the default branches of all repositories) that
+    # duplicate code of this blob, those that share the most code first. Copies are found even if
+    # their identifiers and literals were changed. This is always empty unless the
+    # "codeCloneDetection" experimental feature is enabled.
+    duplicates(
+        # Only find duplicates of the lines starting at this line (1-based).
+        startLine: Int
+        # Only find duplicates of the lines up to this line (1-based, inclusive).
+        endLine: Int
+        # Returns the first n regions.
+        first: Int = 20
+    ): [CodeCloneRegion!]!
+
+    # (experimental) The people with the most expertise in the directory containing this blob
+    # (including its subdirectories), highest score first, based on the recent history of the
+    # repository's default branch. This is always empty unless the "codeExpertise" experimental
+    # feature is enabled.
+    experts(
+        # Returns the first n people.
+        first: Int = 10
+    ): [CodeExpert!]!
+
+    # The commits that introduced or removed the text of a range of lines of this blob (like "git
+    # log -S --follow"), in the history of this blob's path up to this blob's commit (including its
+    # paths before renames), newest first. The text is the lines from startLine to endLine, without
+    # leading and trailing whitespace.
+    pickaxe(
+        # The first line of the range (1-based).
+        startLine: Int!
+        # The last line of the range (1-based, inclusive).
+        endLine: Int!
+        # Returns the events of the first n commits (at most 100).
+        first: Int = 20
+        # The time limit of the search, in seconds (at most 60).
+        timeout: Int = 10
+    ): PickaxeResult!
+}
+
+# A region of a file that duplicates code of other files.
+type CodeCloneRegion {
+    # The file, at the commit of its repository's default branch that was indexed.
+    file: GitBlob!
+    # The first line of the region (1-based).
+    startLine: Int!
+    # The last line of the region (1-based, inclusive).
+    endLine: Int!
+    # The number of fingerprints (hashes of normalized sequences of tokens) that the region shares
+    # with the duplicated file, or with the other regions of its cluster.
+    sharedFingerprints: Int!
+}
+
+# A set of regions of files that share the same code.
+type CodeCloneCluster {
+    # The number of fingerprints (hashes of normalized sequences of tokens) that the regions share.
+    sharedFingerprints: Int!
+    # The regions, of at least two files.
+    regions: [CodeCloneRegion!]!
+}
+
+# A person with expertise in a directory of a repository, because they authored or reviewed
+# changes to it.
+type CodeExpert {
+    # The person's name (their Git author name or code host login).
+    name: String!
+    # The person's Git author email, if they authored changes.
+    email: String
+    # The Sourcegraph user with the person's verified email or code host account, if any.
+    user: User
+    # The person's score: the number of lines they authored, plus 25 for each pull request they
+    # reviewed, weighted by age (with a half-life of 180 days).
+    score: Float!
+    # The number of lines the person authored in the last 3 years.
+    authoredLines: Int!
+    # The number of merged pull requests the person reviewed in the last 3 years.
+    reviewedPullRequests: Int!
+    # When the person last authored or reviewed a change.
+    lastActiveAt: DateTime!
+}
+
+# The code coverage of a repository.
+type RepositoryCoverage {
+    # The coverage report of the revision or, if it has none, of its nearest ancestor commit that has
+    # one. Null if none of the revision's recent ancestors have a coverage report.
+    report(
+        # The revision (defaults to the default branch).
+        rev: String = ""
+    ): CoverageReport
+    # The most recently uploaded coverage reports, most recent first. This is useful for showing
+    # coverage trends.
+    history(
+        # Returns the first n coverage reports.
+        first: Int = 30
+    ): [CoverageReport!]!
+}
+
+# The code coverage of a commit, merged from all of the coverage reports uploaded for the commit.
+type CoverageReport {
+    # The commit.
+    commit: GitCommit!
+    # The number of instrumented lines.
+    linesFound: Int!
+    # The number of instrumented lines that were executed at least once.
+    linesHit: Int!
+    # The fraction of instrumented lines that were executed (between 0 and 1), or null if no lines
+    # are instrumented.
+    ratio: Float
+    # When the first coverage report for the commit was uploaded.
+    createdAt: DateTime!
+    # When the last coverage report for the commit was uploaded.
+    updatedAt: DateTime!
+}
+
+# The code coverage of a file.
+type FileCoverage {
+    # The commit whose coverage report this is.
+    report: CoverageReport!
+    # Whether the coverage report is of the file's commit. If false, the report is of an ancestor
+    # commit, and the line numbers may be inexact if the file changed since.
+    exact: Boolean!
+    # The format of the uploaded coverage report that covered the file ("lcov", "cobertura", or "go").
+    format: String!
+    # The number of instrumented lines of the file.
+    linesFound: Int!
+    # The number of instrumented lines of the file that were executed at least once.
+    linesHit: Int!
+    # The coverage of the instrumented lines of the file, sorted by line number. Lines that are not
+    # instrumented (such as comments) are omitted.
+    lines: [LineCoverage!]!
+}
+
+# The code coverage of a line.
+type LineCoverage {
+    # The line number (1-based).
+    line: Int!
+    # The number of times the line was executed.
+    hits: Int!
 }
 
 # A wrapper object around LSIF query methods for a particular path-at-revision. When this node is
@@ -2903,6 +3470,9 @@ type Hunk {
     message: String!
     # The commit that contains the hunk.
     commit: GitCommit!
+    # The first merged pull request that introduced the hunk's commit, or null if there is none (see
+    # GitCommit.associatedPullRequests).
+    pullRequest: PullRequest
 }
 
 # A namespace is a container for certain types of data and settings, such as a user or organization.
@@ -2933,6 +3503,10 @@ type UserConnection {
 type User implements Node & SettingsSubject & Namespace {
     # The unique ID for the user.
     id: ID!
+    # The resource quota of the user, and the user's usage of the resources.
+    #
+    # Only the user and site admins may access this field.
+    quota: Quota!
     # The user's username.
     username: String!
     # The user's primary email address.
@@ -3015,6 +3589,36 @@ type User implements Node & SettingsSubject & Namespace {
     #
     # Only the user and site admins can access this field.
     surveyResponses: [SurveyResponse!]!
+    # The repository access requests made by the user, most recent first.
+    #
+    # Only the user and site admins can access this field.
+    repositoryAccessRequests(
+        # Returns the first n requests from the list.
+        first: Int
+    ): RepositoryAccessRequestConnection!
+    # The snippet share links created by the user, most recent first.
+    #
+    # Only the user and site admins can access this field.
+    snippetShareLinks(
+        # Returns the first n links from the list.
+        first: Int
+        # Only return links that can still be viewed.
+        active: Boolean = false
+    ): SnippetShareLinkConnection!
+    # The repositories and paths that the user watches, oldest first.
+    #
+    # Only the user and site admins can access this field.
+    watches: [Watch!]!
+    # The user's activity feed: the commits that changed the repositories and paths that the user
+    # watches, most recent first. Only the commits and files that the viewer may view are returned.
+    #
+    # Only the user and site admins can access this field.
+    activityFeed(
+        # Returns the first n entries from the list (fewer if the viewer may not view some of them).
+        first: Int = 50
+        # Returns the entries after this cursor (the pageInfo.endCursor of the previous page).
+        after: String
+    ): ActivityFeedConnection!
     # The URL to view this user's customer information (for Sourcegraph.com site admins).
     #
     # Only Sourcegraph.com site admins may query this field.
@@ -3184,6 +3788,10 @@ type OrgConnection {
 type Org implements Node & SettingsSubject & Namespace {
     # The unique ID for the organization.
     id: ID!
+    # The resource quota of the organization, and the organization's usage of the resources.
+    #
+    # Only members of the organization and site admins may access this field.
+    quota: Quota!
     # The organization's name. This is unique among all organizations on this Sourcegraph site.
     name: String!
     # The organization's chosen display name.
@@ -3255,6 +3863,169 @@ type OrganizationInvitation implements Node {
     revokedAt: DateTime
 }
 
+# A request by a user for read access to repositories.
+type RepositoryAccessRequest implements Node {
+    # The ID of the request.
+    id: ID!
+    # The user who requested access.
+    user: User!
+    # The requested repository name or pattern.
+    repositoryPattern: String!
+    # Why the user needs access.
+    justification: String!
+    # The state of the request.
+    state: RepositoryAccessRequestState!
+    # The user who reviewed the request, if reviewed (and the user still exists).
+    reviewer: User
+    # The date when the request was reviewed.
+    reviewedAt: DateTime
+    # The reviewer's comment.
+    reviewComment: String!
+    # When the access granted by the approved request expires, or null if it never expires (or the
+    # request was not approved).
+    expiresAt: DateTime
+    # The date when the request was created.
+    createdAt: DateTime!
+}
+
+# The possible states of a repository access request.
+enum RepositoryAccessRequestState {
+    # The request has not been reviewed yet.
+    PENDING
+    # The request was approved.
+    APPROVED
+    # The request was denied.
+    DENIED
+}
+
+# A list of repository access requests.
+type RepositoryAccessRequestConnection {
+    # A list of repository access requests.
+    nodes: [RepositoryAccessRequest!]!
+    # The total count of repository access requests in the connection.
+    totalCount: Int!
+}
+
+# A link that lets anyone who knows it view a range of lines of a file at a fixed commit without
+# signing in.
+type SnippetShareLink implements Node {
+    # The ID of the link.
+    id: ID!
+    # The user who created the link.
+    creator: User!
+    # The repository of the file, or null if the viewer can't view it.
+    repository: Repository
+    # The commit of the file.
+    commit: GitObjectID!
+    # The path of the file.
+    path: String!
+    # The first line of the snippet (1-based).
+    startLine: Int!
+    # The last line of the snippet (1-based, inclusive).
+    endLine: Int!
+    # Whether viewers must enter a password to view the snippet.
+    hasPassword: Boolean!
+    # The maximum number of times the snippet can be viewed, or null if there is no limit.
+    maxViews: Int
+    # The number of times the snippet was viewed.
+    viewCount: Int!
+    # When the link expires.
+    expiresAt: DateTime!
+    # The date when the link was created.
+    createdAt: DateTime!
+    # The date when the link was revoked, or null if it wasn't revoked.
+    revokedAt: DateTime
+    # The user who revoked the link, if revoked (and the user still exists).
+    revokedBy: User
+    # Whether the snippet can still be viewed (i.e., the link isn't revoked or expired and hasn't
+    # reached its view limit).
+    active: Boolean!
+    # The audit log of all attempts to view the snippet, most recent first.
+    views(
+        # Returns the first n entries from the list.
+        first: Int
+    ): SnippetShareLinkViewConnection!
+}
+
+# A user's watch on a repository, or on the paths of a repository that match a glob pattern.
+type Watch implements Node {
+    # The ID of the watch.
+    id: ID!
+    # The user who watches.
+    user: User!
+    # The watched repository, or null if the viewer can't view it.
+    repository: Repository
+    # The glob pattern of the watched paths, or null if the whole repository is watched.
+    pathPattern: String
+    # Whether a daily digest of the watch's new commits is emailed to the user.
+    emailDigest: Boolean!
+    # The date when the watch was created.
+    createdAt: DateTime!
+}
+
+# An entry of a user's activity feed: a commit that changed files that the user watches.
+type ActivityFeedEntry {
+    # The watch whose files the commit changed.
+    watch: Watch!
+    # The commit.
+    commit: GitCommit!
+    # The files matching the watch that the commit changed (at most 100).
+    files: [String!]!
+    # The date when the commit was added to the activity feed.
+    createdAt: DateTime!
+}
+
+# A list of activity feed entries.
+type ActivityFeedConnection {
+    # A list of activity feed entries.
+    nodes: [ActivityFeedEntry!]!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A list of snippet share links.
+type SnippetShareLinkConnection {
+    # A list of snippet share links.
+    nodes: [SnippetShareLink!]!
+    # The total count of snippet share links in the connection.
+    totalCount: Int!
+}
+
+# An attempt to view a snippet share link.
+type SnippetShareLinkView {
+    # The outcome of the attempt.
+    outcome: SnippetShareLinkViewOutcome!
+    # The network address of the viewer, preceded by the addresses in the X-Forwarded-For header of the
+    # request (if any).
+    remoteAddr: String!
+    # The User-Agent header of the request.
+    userAgent: String!
+    # The date of the attempt.
+    createdAt: DateTime!
+}
+
+# The possible outcomes of an attempt to view a snippet share link.
+enum SnippetShareLinkViewOutcome {
+    # The snippet was viewed.
+    VIEWED
+    # The viewer entered a wrong password.
+    WRONG_PASSWORD
+    # The link was revoked.
+    REVOKED
+    # The link has expired.
+    EXPIRED
+    # The link reached its view limit.
+    VIEW_LIMIT_REACHED
+}
+
+# A list of attempts to view a snippet share link.
+type SnippetShareLinkViewConnection {
+    # A list of attempts to view a snippet share link.
+    nodes: [SnippetShareLinkView!]!
+    # The total count of attempts in the connection.
+    totalCount: Int!
+}
+
 # The recipient's possible responses to an invitation to join an organization as a member.
 enum OrganizationInvitationResponseType {
     # The invitation was accepted by the recipient.
@@ -3605,6 +4376,22 @@ type Site implements SettingsSubject {
         @deprecated(reason: "All repositories are enabled by default now. This field is always false.")
     # Alerts to display to the viewer.
     alerts: [Alert!]!
+    # The state of maintenance mode, in which the site is read-only.
+    maintenanceMode: MaintenanceMode!
+    # The default resource quota, which applies to all organizations and users without a quota of
+    # their own.
+    #
+    # Only site admins may access this field.
+    defaultQuota: Quota!
+    # The slowest database queries that this frontend instance ran since it started, ordered by
+    # their maximum duration. Only queries that took longer than the slow query threshold (the
+    # SRC_PGSQL_SLOW_QUERY_THRESHOLD environment variable, 1s by default) are recorded.
+    #
+    # Only site admins may view the slow queries.
+    slowQueries(
+        # Returns the first n slow queries.
+        first: Int = 20
+    ): [SlowDatabaseQuery!]!
     # BACKCOMPAT: Always returns true.
     hasCodeIntelligence: Boolean!
     # Whether we want to show built-in searches on the saved searches page
@@ -3637,6 +4424,78 @@ type Site implements SettingsSubject {
     ): CodeIntelUsageStatistics!
 }
 
+# A database query that took longer than the slow query threshold (one or more times).
+type SlowDatabaseQuery {
+    # The store that ran the query, such as "Users".
+    store: String!
+    # The method of the store that ran the query, such as "GetByID".
+    method: String!
+    # The query, with string literals and whitespace sanitized. Its arguments are not recorded.
+    query: String!
+    # The number of times the query was slower than the threshold.
+    count: Int!
+    # The maximum duration of the query, in milliseconds.
+    maxElapsedMilliseconds: Int!
+    # The average duration of the query (of the runs that were slower than the threshold), in
+    # milliseconds.
+    averageElapsedMilliseconds: Int!
+    # When the query was last slower than the threshold.
+    lastSeenAt: DateTime!
+}
+
+# The state of maintenance mode, in which the site is read-only (for upgrades and database
+# maintenance).
+type MaintenanceMode {
+    # Whether maintenance mode is enabled.
+    enabled: Boolean!
+    # The message shown to users while maintenance mode is enabled.
+    message: String
+}
+
+# A resource whose usage is limited by quotas.
+enum QuotaResource {
+    # Repositories synced from the external services of an organization.
+    REPOSITORIES
+    # Saved searches that send notifications by email or Slack.
+    SAVED_SEARCH_NOTIFICATIONS
+    # Campaigns.
+    CAMPAIGNS
+    # Changesets created by campaigns.
+    CHANGESETS
+    # The total size (in bytes) of the LSIF uploads for the repositories of an organization.
+    LSIF_UPLOAD_BYTES
+    # Access tokens of a user.
+    ACCESS_TOKENS
+}
+
+# The resource quota of an organization or user (or the default quota).
+type Quota {
+    # Whether this is the default quota, because the organization or user has no quota of its own.
+    isDefault: Boolean!
+    # The limit and usage of each resource that applies to the organization or user (or of all
+    # resources, for the default quota).
+    resources: [QuotaResourceUsage!]!
+}
+
+# The limit and usage of a resource.
+type QuotaResourceUsage {
+    # The resource.
+    resource: QuotaResource!
+    # The maximum usage of the resource, or null if it is not limited. This is a Float because sizes
+    # in bytes may exceed the range of Int.
+    limit: Float
+    # The current usage of the resource, or null for the default quota.
+    usage: Float
+}
+
+# The limit of a resource in a quota.
+input QuotaLimitInput {
+    # The resource.
+    resource: QuotaResource!
+    # The maximum usage of the resource.
+    limit: Float!
+}
+
 # The configuration for a site.
 type SiteConfiguration {
     # The unique identifier of this site configuration version.
@@ -62,6 +62,8 @@ type SearchImplementer interface {
 	Suggestions(context.Context, *searchSuggestionsArgs) ([]*searchSuggestionResolver, error)
 	//lint:ignore U1000 is used by graphql via reflection
 	Stats(context.Context) (*searchResultsStats, error)
+	//lint:ignore U1000 is used by graphql via reflection
+	Aggregation(context.Context, *searchAggregationArgs) (*searchAggregationResolver, error)
 }
 
 // NewSearchImplementer returns a SearchImplementer that provides search results and suggestions.
@@ -180,6 +182,11 @@ type searchResolver struct {
 	pagination    *searchPaginationInfo // pagination information, or nil if the request is not paginated.
 	patternType   query.SearchType
 
+	// includeSubmatches and maxResultsOverride are set when the results are aggregated (see
+	// (*searchResolver).Aggregation).
+	includeSubmatches  bool
+	maxResultsOverride int32
+
 	// Cached resolveRepositories results.
 	reposMu                   sync.Mutex
 	repoRevs, missingRepoRevs []*search.RepositoryRevisions
@@ -210,6 +217,9 @@ func (r *searchResolver) maxResults() int32 {
 		// search_pagination.go for details on why this is necessary .
 		return math.MaxInt32
 	}
+	if r.maxResultsOverride > 0 {
+		return r.maxResultsOverride
+	}
 	count, _ := r.query.StringValues(query.FieldCount)
 	if len(count) > 0 {
 		n, _ := strconv.Atoi(count[0])
@@ -0,0 +1,184 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+const (
+	// maxSearchAggregationGroups is the maximum number of distinct captured values of an
+	// aggregation.
+	maxSearchAggregationGroups = 1000
+
+	// searchAggregationMaxResults is the result limit of the search whose matches are aggregated,
+	// unless the query specifies a count.
+	searchAggregationMaxResults = 5000
+
+	// searchAggregationSampleLocations is the number of sample locations of each captured value.
+	searchAggregationSampleLocations = 3
+)
+
+type searchAggregationArgs struct {
+	CaptureGroup int32
+	First        int32
+}
+
+// Aggregation aggregates the matches of a regexp search by the value captured by a capture group of
+// the pattern.
+func (r *searchResolver) Aggregation(ctx context.Context, args *searchAggregationArgs) (*searchAggregationResolver, error) {
+	if r.patternType != query.SearchTypeRegex {
+		return nil, errors.New("aggregating search results by capture group requires a regexp search (patternType:regexp)")
+	}
+	if len(r.query.Values(query.FieldDefault))+len(r.query.Values(query.FieldContent)) != 1 {
+		return nil, errors.New("aggregating search results by capture group requires a query with a single search pattern")
+	}
+	if args.First <= 0 || args.First > maxSearchAggregationGroups {
+		return nil, fmt.Errorf("aggregation: requested 'first' value outside allowed range (1 - %d)", maxSearchAggregationGroups)
+	}
+
+	p, err := r.getPatternInfo(nil)
+	if err != nil {
+		return nil, err
+	}
+	expr := p.Pattern
+	if !p.IsCaseSensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	if args.CaptureGroup < 1 || int(args.CaptureGroup) > re.NumSubexp() {
+		return nil, fmt.Errorf("aggregation: the search pattern has no capture group %d (it has %d capture groups)", args.CaptureGroup, re.NumSubexp())
+	}
+
+	// Search again (with the same query) for more results, including the submatches found by
+	// searcher. A new resolver is used so that the results of r aren't affected.
+	sr := &searchResolver{
+		query:             r.query,
+		parseTree:         r.parseTree,
+		originalQuery:     r.originalQuery,
+		patternType:       r.patternType,
+		zoekt:             r.zoekt,
+		searcherURLs:      r.searcherURLs,
+		includeSubmatches: true,
+	}
+	if !r.countIsSet() {
+		sr.maxResultsOverride = searchAggregationMaxResults
+	}
+	results, err := sr.doResults(ctx, "file")
+	if err != nil {
+		return nil, err
+	}
+	return aggregateByCaptureGroup(results, re, int(args.CaptureGroup), int(args.First)), nil
+}
+
+// aggregateByCaptureGroup groups the line matches of the file match results by the value captured by
+// the capture group of re (with the given 1-based index). At most limit distinct values are
+// returned.
+func aggregateByCaptureGroup(results *SearchResultsResolver, re *regexp.Regexp, group, limit int) *searchAggregationResolver {
+	a := &searchAggregationResolver{approximate: results.LimitHit()}
+	groups := map[string]*searchAggregationGroupResolver{}
+	for _, result := range results.SearchResults {
+		fm, ok := result.ToFileMatch()
+		if !ok {
+			continue
+		}
+		if fm.JLimitHit {
+			a.approximate = true
+		}
+		for _, lm := range fm.JLineMatches {
+			for _, c := range capturedValues(lm, re, group) {
+				a.matchCount++
+				g, ok := groups[c.value]
+				if !ok {
+					if len(groups) >= limit {
+						// Matches of values beyond the limit are counted but not returned.
+						a.approximate = true
+						continue
+					}
+					g = &searchAggregationGroupResolver{value: c.value}
+					groups[c.value] = g
+					a.groups = append(a.groups, g)
+				}
+				g.count++
+				if len(g.sampleLocations) < searchAggregationSampleLocations {
+					g.sampleLocations = append(g.sampleLocations, NewLocationResolver(fm.File(), &lsp.Range{
+						Start: lsp.Position{Line: int(lm.JLineNumber), Character: c.offset},
+						End:   lsp.Position{Line: int(lm.JLineNumber), Character: c.offset + c.length},
+					}))
+				}
+			}
+		}
+	}
+	sort.SliceStable(a.groups, func(i, j int) bool {
+		if a.groups[i].count != a.groups[j].count {
+			return a.groups[i].count > a.groups[j].count
+		}
+		return a.groups[i].value < a.groups[j].value
+	})
+	return a
+}
+
+type capturedValue struct {
+	value          string
+	offset, length int // in characters of the line's preview
+}
+
+// capturedValues returns the values captured by the capture group of re in the line match. Searcher
+// reports the submatches of unindexed search results, and the submatches of indexed search results
+// (which only contain single-line matches) are found by matching re against the line.
+func capturedValues(lm *lineMatch, re *regexp.Regexp, group int) []capturedValue {
+	line := []rune(lm.JPreview)
+	if lm.JSubmatchOffsetAndLengths != nil {
+		if group > len(lm.JSubmatchOffsetAndLengths) {
+			return nil
+		}
+		s := lm.JSubmatchOffsetAndLengths[group-1]
+		offset, length := int(s[0]), int(s[1])
+		if offset < 0 || offset+length > len(line) {
+			return nil
+		}
+		return []capturedValue{{value: string(line[offset : offset+length]), offset: offset, length: length}}
+	}
+
+	var values []capturedValue
+	for _, m := range re.FindAllStringSubmatchIndex(lm.JPreview, -1) {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 {
+			continue
+		}
+		offset := len([]rune(lm.JPreview[:start]))
+		value := lm.JPreview[start:end]
+		values = append(values, capturedValue{value: value, offset: offset, length: len([]rune(value))})
+	}
+	return values
+}
+
+type searchAggregationResolver struct {
+	groups      []*searchAggregationGroupResolver
+	matchCount  int32
+	approximate bool
+}
+
+func (r *searchAggregationResolver) Groups() []*searchAggregationGroupResolver { return r.groups }
+func (r *searchAggregationResolver) MatchCount() int32                         { return r.matchCount }
+func (r *searchAggregationResolver) Approximate() bool                         { return r.approximate }
+
+type searchAggregationGroupResolver struct {
+	value           string
+	count           int32
+	sampleLocations []LocationResolver
+}
+
+func (r *searchAggregationGroupResolver) Value() string { return r.value }
+func (r *searchAggregationGroupResolver) Count() int32  { return r.count }
+func (r *searchAggregationGroupResolver) SampleLocations() []LocationResolver {
+	return r.sampleLocations
+}
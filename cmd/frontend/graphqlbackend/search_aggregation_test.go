@@ -0,0 +1,60 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestAggregateByCaptureGroup(t *testing.T) {
+	re := regexp.MustCompile(`(\S+) (v[\d.]+)`)
+	repo := &types.Repo{ID: 1, Name: "repo"}
+	results := &SearchResultsResolver{
+		SearchResults: []SearchResultResolver{
+			// Unindexed search results include the submatches found by searcher.
+			&FileMatchResolver{JPath: "a/go.mod", Repo: repo, JLineMatches: []*lineMatch{
+				{JPreview: "\tgithub.com/pkg/errors v0.8.1", JLineNumber: 3, JSubmatchOffsetAndLengths: [][2]int32{{1, 21}, {23, 6}}},
+				{JPreview: "\tgithub.com/gorilla/mux v1.7.3", JLineNumber: 4, JSubmatchOffsetAndLengths: [][2]int32{{1, 22}, {24, 6}}},
+				{JPreview: "\tgithub.com/gorilla/mux", JLineNumber: 5, JSubmatchOffsetAndLengths: [][2]int32{{-1, 0}, {-1, 0}}},
+			}},
+			// Indexed search results are matched again.
+			&FileMatchResolver{JPath: "b/go.mod", Repo: repo, JLineMatches: []*lineMatch{
+				{JPreview: "require github.com/pkg/errors v0.8.1", JLineNumber: 2},
+				{JPreview: "\tgithub.com/pkg/errors v0.9.0 golang.org/x/net v0.8.1", JLineNumber: 7},
+			}},
+			&RepositoryResolver{repo: repo},
+		},
+	}
+
+	agg := aggregateByCaptureGroup(results, re, 2, 10)
+	var got []string
+	for _, g := range agg.Groups() {
+		got = append(got, g.Value())
+	}
+	if want := []string{"v0.8.1", "v0.9.0", "v1.7.3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got groups %v, want %v", got, want)
+	}
+	if g := agg.Groups()[0]; g.Count() != 3 || len(g.SampleLocations()) != 3 {
+		t.Errorf("got count %d and %d sample locations, want 3 and 3", g.Count(), len(g.SampleLocations()))
+	}
+	if r := agg.Groups()[0].SampleLocations()[0].Range(); r.Start().Line() != 3 || r.Start().Character() != 23 || r.End().Character() != 29 {
+		t.Errorf("unexpected sample location range %+v", r.lspRange)
+	}
+	if agg.MatchCount() != 5 || agg.Approximate() {
+		t.Errorf("got matchCount %d and approximate %v, want 5 and false", agg.MatchCount(), agg.Approximate())
+	}
+
+	agg = aggregateByCaptureGroup(results, re, 1, 2)
+	got = nil
+	for _, g := range agg.Groups() {
+		got = append(got, g.Value())
+	}
+	if want := []string{"github.com/pkg/errors", "github.com/gorilla/mux"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got groups %v, want %v", got, want)
+	}
+	if agg.MatchCount() != 5 || !agg.Approximate() {
+		t.Errorf("got matchCount %d and approximate %v, want 5 and true (limit hit)", agg.MatchCount(), agg.Approximate())
+	}
+}
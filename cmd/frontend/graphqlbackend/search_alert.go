@@ -518,3 +518,6 @@ func (searchAlert) Suggestions(context.Context, *searchSuggestionsArgs) ([]*sear
 	return nil, nil
 }
 func (searchAlert) Stats(context.Context) (*searchResultsStats, error) { return nil, nil }
+func (searchAlert) Aggregation(context.Context, *searchAggregationArgs) (*searchAggregationResolver, error) {
+	return nil, nil
+}
@@ -15,8 +15,10 @@ import (
 	"github.com/xeonx/timeago"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -124,24 +126,37 @@ func searchCommitsInRepo(ctx context.Context, op search.CommitParameters) (resul
 		args = append(args, "--regexp-ignore-case")
 	}
 
-	for _, rev := range op.RepoRevs.Revs {
-		switch {
-		case rev.RevSpec != "":
-			if strings.HasPrefix(rev.RevSpec, "-") {
-				// A revspec starting with "-" would be interpreted as a `git log` flag.
-				// It would not be a security vulnerability because the flags are checked
-				// against a whitelist, but it could cause unexpected errors by (e.g.)
-				// changing the format of `git log` to a format that our parser doesn't
-				// expect.
-				return nil, false, false, fmt.Errorf("invalid revspec: %q", rev.RevSpec)
-			}
-			args = append(args, rev.RevSpec)
+	// With pr:, only the commits of the pull request are searched, not the history of the revisions.
+	if prValue, _ := op.Query.StringValue(query.FieldPullRequest); prValue != "" {
+		commitIDs, err := pullRequestCommitIDs(ctx, repo, prValue)
+		if err != nil || len(commitIDs) == 0 {
+			return nil, false, false, err
+		}
+		// Commits that gitserver hasn't fetched yet are skipped.
+		args = append(args, "--no-walk", "--ignore-missing")
+		for _, id := range commitIDs {
+			args = append(args, string(id))
+		}
+	} else {
+		for _, rev := range op.RepoRevs.Revs {
+			switch {
+			case rev.RevSpec != "":
+				if strings.HasPrefix(rev.RevSpec, "-") {
+					// A revspec starting with "-" would be interpreted as a `git log` flag.
+					// It would not be a security vulnerability because the flags are checked
+					// against a whitelist, but it could cause unexpected errors by (e.g.)
+					// changing the format of `git log` to a format that our parser doesn't
+					// expect.
+					return nil, false, false, fmt.Errorf("invalid revspec: %q", rev.RevSpec)
+				}
+				args = append(args, rev.RevSpec)
 
-		case rev.RefGlob != "":
-			args = append(args, "--glob="+rev.RefGlob)
+			case rev.RefGlob != "":
+				args = append(args, "--glob="+rev.RefGlob)
 
-		case rev.ExcludeRefGlob != "":
-			args = append(args, "--exclude="+rev.ExcludeRefGlob)
+			case rev.ExcludeRefGlob != "":
+				args = append(args, "--exclude="+rev.ExcludeRefGlob)
+			}
 		}
 	}
 
@@ -235,6 +250,23 @@ func searchCommitsInRepo(ctx context.Context, op search.CommitParameters) (resul
 		return nil, false, false, err
 	}
 
+	// 🚨 SECURITY: Omit diff matches that include files the user may not read.
+	if op.Diff {
+		perms, err := backend.SubRepoPerms(ctx, repo)
+		if err != nil {
+			return nil, false, false, err
+		}
+		if perms.Restricted() {
+			allowed := rawResults[:0]
+			for _, rawResult := range rawResults {
+				if rawResult.Diff == nil || rawDiffAllowed(perms, rawResult.Diff.Raw) {
+					allowed = append(allowed, rawResult)
+				}
+			}
+			rawResults = allowed
+		}
+	}
+
 	// if the result is incomplete, git log timed out and the client should be notified of that
 	timedOut = !complete
 	if len(rawResults) > maxResults {
@@ -608,3 +640,56 @@ func expandUsernamesToEmails(ctx context.Context, values []string) (expandedValu
 	}
 	return expandedValues, nil
 }
+
+// pullRequestCommitIDs returns the commits of the repository's pull request that a pr: value (such
+// as "123" or "#123") refers to. It returns no commits if the pull request hasn't been indexed.
+func pullRequestCommitIDs(ctx context.Context, repo *types.Repo, value string) ([]api.CommitID, error) {
+	number, err := strconv.ParseInt(strings.TrimPrefix(value, "#"), 10, 64)
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("invalid pr: value %q (must be a pull request number)", value)
+	}
+	return db.PullRequests.ListCommitIDs(ctx, repo.ID, number)
+}
+
+// A CommitSearchResult is a commit that a commit or diff search found, for callers other than the
+// GraphQL API (such as Atom feeds).
+type CommitSearchResult struct {
+	Repo        *types.Repo
+	Commit      *git.Commit // only the ID, author and message are set
+	DiffPreview string      // the matching hunks of the commit's diff, for diff searches
+}
+
+// SearchCommits runs the search query (as the actor) and returns the commits that it found. Results
+// that aren't commits are ignored.
+func SearchCommits(ctx context.Context, query string) ([]*CommitSearchResult, error) {
+	search, err := NewSearchImplementer(&SearchArgs{Version: "V1", Query: query})
+	if err != nil {
+		return nil, err
+	}
+	results, err := search.Results(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var commits []*CommitSearchResult
+	for _, r := range results.Results() {
+		c, ok := r.ToCommitSearchResult()
+		if !ok {
+			continue
+		}
+		res := &CommitSearchResult{
+			Repo: c.commit.repo.repo,
+			Commit: &git.Commit{
+				ID:      api.CommitID(c.commit.oid),
+				Message: c.commit.message,
+			},
+		}
+		if a := c.commit.author; a.person != nil {
+			res.Commit.Author = git.Signature{Name: a.person.name, Email: a.person.email, Date: a.date}
+		}
+		if c.diffPreview != nil {
+			res.DiffPreview = c.diffPreview.value
+		}
+		commits = append(commits, res)
+	}
+	return commits, nil
+}
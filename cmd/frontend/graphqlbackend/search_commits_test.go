@@ -14,6 +14,7 @@ import (
 	//"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -97,6 +98,72 @@ func TestSearchCommitsInRepo(t *testing.T) {
 	}
 }
 
+func TestSearchCommitsInRepo_PullRequest(t *testing.T) {
+	ctx := context.Background()
+	defer resetMocks()
+	defer git.ResetMocks()
+
+	db.Mocks.PullRequests.ListCommitIDs = func(_ context.Context, repoID api.RepoID, number int64) ([]api.CommitID, error) {
+		if repoID != 1 {
+			t.Errorf("got repo %d, want 1", repoID)
+		}
+		if number == 7 {
+			return []api.CommitID{"c1", "c2"}, nil
+		}
+		return nil, nil
+	}
+	var gotArgs []string
+	git.Mocks.RawLogDiffSearch = func(opt git.RawLogDiffSearchOptions) ([]*git.LogCommitSearchResult, bool, error) {
+		gotArgs = opt.Args
+		return nil, true, nil
+	}
+
+	run := func(queryString string) error {
+		gotArgs = nil
+		q, err := query.ParseAndCheck(queryString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, _, err = searchCommitsInRepo(ctx, search.CommitParameters{
+			RepoRevs: &search.RepositoryRevisions{
+				Repo: &types.Repo{ID: 1, Name: "repo"},
+				Revs: []search.RevisionSpecifier{{RevSpec: "rev"}},
+			},
+			PatternInfo: &search.CommitPatternInfo{FileMatchLimit: int32(defaultMaxSearchResults)},
+			Query:       q,
+		})
+		return err
+	}
+
+	// Only the commits of the pull request are searched, instead of the history of the revision.
+	if err := run("type:commit pr:#7"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{
+		"--no-prefix",
+		"--max-count=" + strconv.Itoa(defaultMaxSearchResults+1),
+		"--regexp-ignore-case",
+		"--no-walk",
+		"--ignore-missing",
+		"c1",
+		"c2",
+	}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("got %v, want %v", gotArgs, want)
+	}
+
+	// A pull request that hasn't been indexed has no results.
+	if err := run("type:commit pr:8"); err != nil {
+		t.Fatal(err)
+	}
+	if gotArgs != nil {
+		t.Errorf("got git log args %v, want no search", gotArgs)
+	}
+
+	if err := run("type:commit pr:abc"); err == nil {
+		t.Error("got nil error for invalid pr: value")
+	}
+}
+
 func (r *commitSearchResultResolver) String() string {
 	return fmt.Sprintf("{commit: %+v diffPreview: %+v messagePreview: %+v}", r.commit, r.diffPreview, r.messagePreview)
 }
@@ -784,6 +784,7 @@ func getPatternInfo(q *query.Query, opts *getPatternInfoOptions) (*search.TextPa
 	excludePatterns = append(excludePatterns, langExcludePatterns...)
 
 	languages, _ := q.StringValues(query.FieldLang)
+	scope, _ := q.StringValue(query.FieldScope)
 
 	patternInfo := &search.TextPatternInfo{
 		IsRegExp:                     isRegExp,
@@ -798,6 +799,7 @@ func getPatternInfo(q *query.Query, opts *getPatternInfoOptions) (*search.TextPa
 		Languages:                    languages,
 		PathPatternsAreCaseSensitive: q.IsCaseSensitive(),
 		CombyRule:                    strings.Join(combyRule, ""),
+		Scope:                        scope,
 	}
 	if len(excludePatterns) > 0 {
 		patternInfo.ExcludePattern = unionRegExps(excludePatterns)
@@ -998,6 +1000,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 	if err != nil {
 		return nil, err
 	}
+	p.IncludeSubmatches = r.includeSubmatches
 	args := search.TextParameters{
 		PatternInfo:     p,
 		Repos:           repos,
@@ -535,6 +535,12 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 			PathPatternsAreRegExps: true,
 			IncludePatterns:        []string{"f1", "f2"},
 		},
+		"p scope:comment": {
+			Pattern:                "p",
+			IsRegExp:               true,
+			PathPatternsAreRegExps: true,
+			Scope:                  "comment",
+		},
 		"p -file:f": {
 			Pattern:                "p",
 			IsRegExp:               true,
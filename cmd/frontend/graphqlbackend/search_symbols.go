@@ -154,6 +154,10 @@ func searchSymbols(ctx context.Context, args *search.TextParameters, limit int)
 	goroutine.Go(func() {
 		defer run.Release()
 		matches, limitHit, reposLimitHit, searchErr := zoektSearchHEAD(ctx, args, zoektRepos, true, time.Since)
+		if searchErr == nil {
+			// 🚨 SECURITY: Remove symbols in files that the user may not read.
+			matches, searchErr = filterFileMatchesBySubRepoPerms(ctx, matches)
+		}
 		mu.Lock()
 		defer mu.Unlock()
 		if ctx.Err() == nil {
@@ -188,6 +192,10 @@ func searchSymbols(ctx context.Context, args *search.TextParameters, limit int)
 		goroutine.Go(func() {
 			defer run.Release()
 			repoSymbols, repoErr := searchSymbolsInRepo(ctx, repoRevs, args.PatternInfo, args.Query, limit)
+			if repoErr == nil {
+				// 🚨 SECURITY: Remove symbols in files that the user may not read.
+				repoSymbols, repoErr = filterFileMatchesBySubRepoPerms(ctx, repoSymbols)
+			}
 			if repoErr != nil {
 				tr.LogFields(otlog.String("repo", string(repoRevs.Repo.Name)), otlog.String("repoErr", repoErr.Error()), otlog.Bool("timeout", errcode.IsTimeout(repoErr)), otlog.Bool("temporary", errcode.IsTemporary(repoErr)))
 			}
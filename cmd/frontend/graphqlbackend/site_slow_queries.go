@@ -0,0 +1,42 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+func (r *siteResolver) SlowQueries(ctx context.Context, args *struct{ First int32 }) ([]*slowDatabaseQueryResolver, error) {
+	// 🚨 SECURITY: Queries reveal the database schema and how the site is used, so only site
+	// admins may view them.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	queries := dbconn.SlowQueries(int(args.First))
+	resolvers := make([]*slowDatabaseQueryResolver, len(queries))
+	for i := range queries {
+		resolvers[i] = &slowDatabaseQueryResolver{q: queries[i]}
+	}
+	return resolvers, nil
+}
+
+type slowDatabaseQueryResolver struct {
+	q dbconn.SlowQuery
+}
+
+func (r *slowDatabaseQueryResolver) Store() string  { return r.q.Store }
+func (r *slowDatabaseQueryResolver) Method() string { return r.q.Method }
+func (r *slowDatabaseQueryResolver) Query() string  { return r.q.Query }
+func (r *slowDatabaseQueryResolver) Count() int32   { return int32(r.q.Count) }
+
+func (r *slowDatabaseQueryResolver) MaxElapsedMilliseconds() int32 {
+	return int32(r.q.Max / time.Millisecond)
+}
+
+func (r *slowDatabaseQueryResolver) AverageElapsedMilliseconds() int32 {
+	return int32(r.q.Total / time.Duration(r.q.Count) / time.Millisecond)
+}
+
+func (r *slowDatabaseQueryResolver) LastSeenAt() DateTime { return DateTime{Time: r.q.LastSeen} }
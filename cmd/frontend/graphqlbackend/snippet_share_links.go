@@ -0,0 +1,285 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/router"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+type createSnippetShareLinkArgs struct {
+	Repository graphql.ID
+	Revision   string
+	Path       string
+	StartLine  int32
+	EndLine    int32
+	ExpiresAt  DateTime
+	Password   *string
+	MaxViews   *int32
+}
+
+func (*schemaResolver) CreateSnippetShareLink(ctx context.Context, args *createSnippetShareLinkArgs) (*createSnippetShareLinkResult, error) {
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users may only share files that they can read, of repositories whose files the
+	// site configuration permits sharing.
+	repo, err := RepositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := backend.CanShareSnippets(repo.repo.Name); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New("sharing snippets of this repository is not permitted (site configuration snippetShareLinks.repos)")
+	}
+	path := strings.Trim(args.Path, "/")
+	if err := backend.CheckSubRepoPath(ctx, repo.repo, path, false); err != nil {
+		return nil, err
+	}
+
+	maxDuration, err := backend.SnippetShareLinkMaxDuration()
+	if err != nil {
+		return nil, err
+	}
+	if !args.ExpiresAt.Time.After(time.Now()) {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+	if args.ExpiresAt.Time.After(time.Now().Add(maxDuration)) {
+		return nil, fmt.Errorf("expiresAt must be within %s (site configuration snippetShareLinks.maxDuration)", maxDuration)
+	}
+	var password string
+	if args.Password != nil {
+		password = *args.Password
+	}
+	if password == "" && backend.SnippetShareLinkRequirePassword() {
+		return nil, errors.New("a password is required (site configuration snippetShareLinks.requirePassword)")
+	}
+	if args.MaxViews != nil && *args.MaxViews < 1 {
+		return nil, errors.New("maxViews must be at least 1")
+	}
+
+	commitID, err := backend.Repos.ResolveRev(ctx, repo.repo, args.Revision)
+	if err != nil {
+		return nil, err
+	}
+	// Check that the snippet can be viewed, so that broken links aren't created.
+	if _, err := backend.ReadSnippet(ctx, repo.repo, commitID, path, args.StartLine, args.EndLine); err != nil {
+		return nil, err
+	}
+
+	link := &db.SnippetShareLink{
+		CreatorUserID: user.ID,
+		RepoID:        repo.repo.ID,
+		CommitID:      commitID,
+		Path:          path,
+		StartLine:     args.StartLine,
+		EndLine:       args.EndLine,
+		MaxViews:      args.MaxViews,
+		ExpiresAt:     args.ExpiresAt.Time,
+	}
+	token, err := db.SnippetShareLinks.Create(ctx, link, password)
+	if err != nil {
+		return nil, err
+	}
+	u, err := router.Router().Get(router.SnippetShareLink).URLPath("Token", token)
+	if err != nil {
+		return nil, err
+	}
+	return &createSnippetShareLinkResult{
+		link: &snippetShareLinkResolver{link: link},
+		url:  globals.ExternalURL().ResolveReference(u).String(),
+	}, nil
+}
+
+type createSnippetShareLinkResult struct {
+	link *snippetShareLinkResolver
+	url  string
+}
+
+func (r *createSnippetShareLinkResult) SnippetShareLink() *snippetShareLinkResolver { return r.link }
+func (r *createSnippetShareLinkResult) URL() string                                 { return r.url }
+
+func (*schemaResolver) RevokeSnippetShareLink(ctx context.Context, args *struct {
+	SnippetShareLink graphql.ID
+}) (*EmptyResponse, error) {
+	link, err := snippetShareLinkByID(ctx, args.SnippetShareLink)
+	if err != nil {
+		return nil, err
+	}
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SnippetShareLinks.Revoke(ctx, link.link.ID, user.ID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *UserResolver) SnippetShareLinks(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Active bool
+}) (*snippetShareLinkConnectionResolver, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to access the user's snippet share links.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+	opt := db.SnippetShareLinksListOptions{CreatorUserID: r.user.ID, OnlyActive: args.Active}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &snippetShareLinkConnectionResolver{opt: opt}, nil
+}
+
+type snippetShareLinkConnectionResolver struct {
+	opt db.SnippetShareLinksListOptions
+}
+
+func (r *snippetShareLinkConnectionResolver) Nodes(ctx context.Context) ([]*snippetShareLinkResolver, error) {
+	links, err := db.SnippetShareLinks.List(ctx, r.opt)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*snippetShareLinkResolver, len(links))
+	for i, link := range links {
+		resolvers[i] = &snippetShareLinkResolver{link: link}
+	}
+	return resolvers, nil
+}
+
+func (r *snippetShareLinkConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.SnippetShareLinks.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+// snippetShareLinkResolver implements the GraphQL type SnippetShareLink.
+type snippetShareLinkResolver struct {
+	link *db.SnippetShareLink
+}
+
+func snippetShareLinkByID(ctx context.Context, id graphql.ID) (*snippetShareLinkResolver, error) {
+	linkID, err := unmarshalSnippetShareLinkID(id)
+	if err != nil {
+		return nil, err
+	}
+	link, err := db.SnippetShareLinks.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the creator and site admins may view the link.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, link.CreatorUserID); err != nil {
+		return nil, err
+	}
+	return &snippetShareLinkResolver{link: link}, nil
+}
+
+func (r *snippetShareLinkResolver) ID() graphql.ID {
+	return marshalSnippetShareLinkID(r.link.ID)
+}
+
+func marshalSnippetShareLinkID(id int64) graphql.ID {
+	return relay.MarshalID("SnippetShareLink", id)
+}
+
+func unmarshalSnippetShareLinkID(id graphql.ID) (linkID int64, err error) {
+	err = relay.UnmarshalSpec(id, &linkID)
+	return
+}
+
+func (r *snippetShareLinkResolver) Creator(ctx context.Context) (*UserResolver, error) {
+	return UserByIDInt32(ctx, r.link.CreatorUserID)
+}
+
+func (r *snippetShareLinkResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := RepositoryByIDInt32(ctx, r.link.RepoID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return repo, err
+}
+
+func (r *snippetShareLinkResolver) Commit() GitObjectID { return GitObjectID(r.link.CommitID) }
+
+func (r *snippetShareLinkResolver) Path() string { return r.link.Path }
+
+func (r *snippetShareLinkResolver) StartLine() int32 { return r.link.StartLine }
+
+func (r *snippetShareLinkResolver) EndLine() int32 { return r.link.EndLine }
+
+func (r *snippetShareLinkResolver) HasPassword() bool { return r.link.HasPassword }
+
+func (r *snippetShareLinkResolver) MaxViews() *int32 { return r.link.MaxViews }
+
+func (r *snippetShareLinkResolver) ViewCount() int32 { return r.link.ViewCount }
+
+func (r *snippetShareLinkResolver) ExpiresAt() DateTime { return DateTime{Time: r.link.ExpiresAt} }
+
+func (r *snippetShareLinkResolver) CreatedAt() DateTime { return DateTime{Time: r.link.CreatedAt} }
+
+func (r *snippetShareLinkResolver) RevokedAt() *DateTime { return DateTimeOrNil(r.link.RevokedAt) }
+
+func (r *snippetShareLinkResolver) RevokedBy(ctx context.Context) (*UserResolver, error) {
+	if r.link.RevokerUserID == nil {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, *r.link.RevokerUserID)
+}
+
+func (r *snippetShareLinkResolver) Active() bool { return r.link.Inactive(time.Now()) == "" }
+
+func (r *snippetShareLinkResolver) Views(args *struct {
+	graphqlutil.ConnectionArgs
+}) *snippetShareLinkViewConnectionResolver {
+	c := &snippetShareLinkViewConnectionResolver{linkID: r.link.ID}
+	args.ConnectionArgs.Set(&c.limitOffset)
+	return c
+}
+
+type snippetShareLinkViewConnectionResolver struct {
+	linkID      int64
+	limitOffset *db.LimitOffset
+}
+
+func (r *snippetShareLinkViewConnectionResolver) Nodes(ctx context.Context) ([]*snippetShareLinkViewResolver, error) {
+	views, err := db.SnippetShareLinks.ListViews(ctx, r.linkID, r.limitOffset)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*snippetShareLinkViewResolver, len(views))
+	for i, view := range views {
+		resolvers[i] = &snippetShareLinkViewResolver{view: view}
+	}
+	return resolvers, nil
+}
+
+func (r *snippetShareLinkViewConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.SnippetShareLinks.CountViews(ctx, r.linkID)
+	return int32(count), err
+}
+
+type snippetShareLinkViewResolver struct {
+	view *db.SnippetShareLinkView
+}
+
+func (r *snippetShareLinkViewResolver) Outcome() string {
+	return strings.ToUpper(string(r.view.Outcome))
+}
+
+func (r *snippetShareLinkViewResolver) RemoteAddr() string { return r.view.RemoteAddr }
+
+func (r *snippetShareLinkViewResolver) UserAgent() string { return r.view.UserAgent }
+
+func (r *snippetShareLinkViewResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.view.CreatedAt}
+}
@@ -0,0 +1,122 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func (*schemaResolver) SetSubRepositoryPathRule(ctx context.Context, args *struct {
+	Repository graphql.ID
+	PathPrefix string
+	Users      []graphql.ID
+	Orgs       []graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can modify path rules.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &types.SubRepoPathRule{
+		RepoID:     repoID,
+		PathPrefix: args.PathPrefix,
+		UserIDs:    make([]int32, 0, len(args.Users)),
+		OrgIDs:     make([]int32, 0, len(args.Orgs)),
+	}
+	for _, id := range args.Users {
+		userID, err := UnmarshalUserID(id)
+		if err != nil {
+			return nil, err
+		}
+		rule.UserIDs = append(rule.UserIDs, userID)
+	}
+	for _, id := range args.Orgs {
+		orgID, err := UnmarshalOrgID(id)
+		if err != nil {
+			return nil, err
+		}
+		rule.OrgIDs = append(rule.OrgIDs, orgID)
+	}
+
+	if err := db.SubRepoPerms.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (*schemaResolver) DeleteSubRepositoryPathRule(ctx context.Context, args *struct {
+	Repository graphql.ID
+	PathPrefix string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can modify path rules.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SubRepoPerms.Delete(ctx, repoID, args.PathPrefix); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *RepositoryResolver) SubRepositoryPathRules(ctx context.Context) ([]*subRepositoryPathRuleResolver, error) {
+	// 🚨 SECURITY: Only site admins can view path rules, because they reveal the names of
+	// restricted paths.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rules, err := db.SubRepoPerms.ListByRepo(ctx, r.repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*subRepositoryPathRuleResolver, len(rules))
+	for i, rule := range rules {
+		resolvers[i] = &subRepositoryPathRuleResolver{rule: rule}
+	}
+	return resolvers, nil
+}
+
+type subRepositoryPathRuleResolver struct {
+	rule *types.SubRepoPathRule
+}
+
+func (r *subRepositoryPathRuleResolver) PathPrefix() string { return r.rule.PathPrefix }
+
+func (r *subRepositoryPathRuleResolver) Users(ctx context.Context) ([]*UserResolver, error) {
+	users := make([]*UserResolver, 0, len(r.rule.UserIDs))
+	for _, id := range r.rule.UserIDs {
+		u, err := UserByIDInt32(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *subRepositoryPathRuleResolver) Orgs(ctx context.Context) ([]*OrgResolver, error) {
+	orgs := make([]*OrgResolver, 0, len(r.rule.OrgIDs))
+	for _, id := range r.rule.OrgIDs {
+		o, err := OrgByIDInt32(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+	return orgs, nil
+}
+
+func (r *subRepositoryPathRuleResolver) UpdatedAt() DateTime { return DateTime{Time: r.rule.UpdatedAt} }
@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// filterFileMatchesBySubRepoPerms removes the file matches that the current user may not read
+// according to the path rules of their repositories. The matches slice is filtered in place.
+//
+// 🚨 SECURITY: This must be applied to all file and symbol matches before they are returned
+// (including result counts derived from them), regardless of whether they came from searcher,
+// zoekt or the symbols service.
+func filterFileMatchesBySubRepoPerms(ctx context.Context, matches []*FileMatchResolver) ([]*FileMatchResolver, error) {
+	perms := make(map[*types.Repo]*authz.SubRepoPerms)
+	filtered := matches[:0]
+	for _, m := range matches {
+		p, ok := perms[m.Repo]
+		if !ok {
+			var err error
+			p, err = backend.SubRepoPerms(ctx, m.Repo)
+			if err != nil {
+				return nil, err
+			}
+			perms[m.Repo] = p
+		}
+		if p.Allowed(m.JPath) {
+			filtered = append(filtered, m)
+		}
+	}
+	for i := len(filtered); i < len(matches); i++ {
+		matches[i] = nil
+	}
+	return filtered, nil
+}
+
+// filterSymbolsBySubRepoPerms removes the symbols defined in files that the current user may not
+// read according to the path rules of repo.
+func filterSymbolsBySubRepoPerms(ctx context.Context, repo *types.Repo, symbols []*symbolResolver) ([]*symbolResolver, error) {
+	perms, err := backend.SubRepoPerms(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !perms.Restricted() {
+		return symbols, nil
+	}
+
+	filtered := symbols[:0]
+	for _, s := range symbols {
+		if perms.Allowed(s.symbol.Path) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered, nil
+}
+
+// fileDiffAllowed returns true if the user may read both the old and the new version of the file
+// in fileDiff. Paths are expected to be unprefixed (i.e. produced by `git diff --no-prefix`).
+func fileDiffAllowed(perms *authz.SubRepoPerms, fileDiff *diff.FileDiff) bool {
+	for _, name := range []string{fileDiff.OrigName, fileDiff.NewName} {
+		if name != "/dev/null" && !perms.Allowed(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// rawDiffAllowed returns true if the user may read every file in the raw (unprefixed) multi-file
+// diff. Diffs that cannot be parsed are not allowed.
+func rawDiffAllowed(perms *authz.SubRepoPerms, rawDiff string) bool {
+	if !perms.Restricted() {
+		return true
+	}
+	fileDiffs, err := diff.ParseMultiFileDiff([]byte(rawDiff))
+	if err != nil {
+		// 🚨 SECURITY: Fail closed. We don't know which files the diff touches.
+		return false
+	}
+	for _, fileDiff := range fileDiffs {
+		if !fileDiffAllowed(perms, fileDiff) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitservertest"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	symbolsclient "github.com/sourcegraph/sourcegraph/internal/symbols"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestFilterFileMatchesBySubRepoPerms(t *testing.T) {
+	resetMocks()
+	repoA, repoB := &types.Repo{ID: 1, Name: "a"}, &types.Repo{ID: 2, Name: "b"}
+	calls := 0
+	backend.Mocks.SubRepoPerms = func(ctx context.Context, repo *types.Repo) (*authz.SubRepoPerms, error) {
+		calls++
+		if repo == repoA {
+			return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secret", Allow: false}}}, nil
+		}
+		return nil, nil
+	}
+	defer resetMocks()
+
+	matches := []*FileMatchResolver{
+		{JPath: "secret/a.go", Repo: repoA},
+		{JPath: "public/a.go", Repo: repoA},
+		{JPath: "secret/b.go", Repo: repoB},
+		{JPath: "secret/c.go", Repo: repoA},
+	}
+	filtered, err := filterFileMatchesBySubRepoPerms(context.Background(), matches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range filtered {
+		got = append(got, string(m.Repo.Name)+":"+m.JPath)
+	}
+	if want := []string{"a:public/a.go", "b:secret/b.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2 (one per repository)", calls)
+	}
+}
+
+func TestRawDiffAllowed(t *testing.T) {
+	perms := &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secret", Allow: false}}}
+
+	const publicDiff = `diff --git README.md README.md
+index 1..2 100644
+--- README.md
++++ README.md
+@@ -1 +1 @@
+-a
++b
+`
+	const secretDiff = `diff --git secret/key secret/key
+new file mode 100644
+index 0000000..2
+--- /dev/null
++++ secret/key
+@@ -0,0 +1 @@
++k
+`
+	if !rawDiffAllowed(perms, publicDiff) {
+		t.Error("public diff: want allowed")
+	}
+	if rawDiffAllowed(perms, publicDiff+secretDiff) {
+		t.Error("diff touching secret path: want not allowed")
+	}
+	if !rawDiffAllowed(nil, publicDiff+secretDiff) {
+		t.Error("unrestricted: want allowed")
+	}
+}
+
+func TestSubRepoPerms_Resolvers(t *testing.T) {
+	resetMocks()
+	git.ResetMocks()
+	defer resetMocks()
+
+	gitserver := gitservertest.Start(t)
+	defer gitserver.Close()
+	head := gitserver.MakeRepository(t, "example.com/restricted",
+		"mkdir pub secret",
+		"echo 'func A() {}' > pub/a.go",
+		"echo 'func Key() {}' > secret/key.go",
+		"git add . && git commit -qm base && git tag base",
+		"echo 'func B() {}' >> pub/a.go",
+		"echo 'func Key2() {}' >> secret/key.go",
+		"git commit -qam head",
+	)
+
+	db.Mocks.Repos.MockGetByName(t, "example.com/restricted", 1)
+	backend.Mocks.Repos.ResolveRev = func(context.Context, *types.Repo, string) (api.CommitID, error) {
+		return head, nil
+	}
+	backend.Mocks.Repos.MockGetCommit_Return_NoCheck(t, &git.Commit{ID: head})
+	backend.Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secret", Allow: false}}}, nil
+	}
+
+	// The symbols service returns the symbols of all files.
+	symbols := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args search.SymbolsParameters
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(protocol.SearchResult{Symbols: []protocol.Symbol{
+			{Name: "A", Path: "pub/a.go", Line: 1, Kind: "function", Language: "Go"},
+			{Name: "Key", Path: "secret/key.go", Line: 1, Kind: "function", Language: "Go"},
+		}})
+	}))
+	defer symbols.Close()
+	defer func(orig *symbolsclient.Client) { symbolsclient.DefaultClient = orig }(symbolsclient.DefaultClient)
+	symbolsclient.DefaultClient = &symbolsclient.Client{URL: symbols.URL, HTTPClient: http.DefaultClient}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      string // a path in the result that the user may read
+		wantError bool
+	}{
+		{name: "tree", query: `commit(rev: "HEAD") { tree(path: "") { entries(recursive: true) { path } } }`, want: "pub/a.go"},
+		{name: "denied tree", query: `commit(rev: "HEAD") { tree(path: "secret") { entries { path } } }`, wantError: true},
+		{name: "blob", query: `commit(rev: "HEAD") { blob(path: "pub/a.go") { path content } }`, want: "pub/a.go"},
+		{name: "denied blob", query: `commit(rev: "HEAD") { blob(path: "secret/key.go") { path content } }`, wantError: true},
+		{name: "comparison", query: `comparison(base: "base", head: "HEAD") { fileDiffs { nodes { oldPath newPath } totalCount } }`, want: "pub/a.go"},
+		{name: "symbols", query: `commit(rev: "HEAD") { symbols { nodes { name location { resource { path } } } } }`, want: "pub/a.go"},
+		{name: "tree symbols", query: `commit(rev: "HEAD") { tree(path: "") { symbols { nodes { name location { resource { path } } } } } }`, want: "pub/a.go"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := mustParseGraphQLSchema(t).Exec(context.Background(), `{ repository(name: "example.com/restricted") { `+test.query+` } }`, "", nil)
+			if test.wantError != (len(result.Errors) > 0) {
+				t.Fatalf("got errors %v, want error: %v", result.Errors, test.wantError)
+			}
+			for _, err := range result.Errors {
+				if !strings.Contains(err.Message, "file does not exist") {
+					t.Errorf("got error %q, want an error indistinguishable from a nonexistent file", err.Message)
+				}
+			}
+			if got := string(result.Data); !strings.Contains(got, test.want) || strings.Contains(got, `"secret`) || strings.Contains(got, "Key") {
+				t.Errorf("got %s, want %q and nothing under secret/", got, test.want)
+			}
+		})
+	}
+}
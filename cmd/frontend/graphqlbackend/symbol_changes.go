@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func (r *GitCommitResolver) SymbolChanges(ctx context.Context) ([]*symbolChangeResolver, error) {
+	r.resolveCommit(ctx)
+	if r.err != nil {
+		return nil, r.err
+	}
+	var base *GitCommitResolver
+	if len(r.parents) > 0 {
+		base = &GitCommitResolver{repo: r.repo, includeUserInfo: true, oid: GitObjectID(r.parents[0])}
+	}
+	return symbolChanges(ctx, base, r)
+}
+
+func (r *RepositoryComparisonResolver) SymbolChanges(ctx context.Context) ([]*symbolChangeResolver, error) {
+	var base *GitCommitResolver
+	if r.base != nil {
+		cachedRepo, err := backend.CachedGitRepo(ctx, r.repo.repo)
+		if err != nil {
+			return nil, err
+		}
+		// Compare with the merge base, like RepositoryComparison.fileDiffs.
+		mergeBase, err := git.MergeBase(ctx, *cachedRepo, api.CommitID(r.base.OID()), api.CommitID(r.head.OID()))
+		if err != nil {
+			return nil, err
+		}
+		base = &GitCommitResolver{repo: r.repo, includeUserInfo: true, oid: GitObjectID(mergeBase)}
+	}
+	return symbolChanges(ctx, base, r.head)
+}
+
+// symbolChanges returns the symbol changes between the base commit (nil for the empty tree) and the
+// head commit.
+func symbolChanges(ctx context.Context, base, head *GitCommitResolver) ([]*symbolChangeResolver, error) {
+	var baseID api.CommitID
+	if base != nil {
+		baseID = api.CommitID(base.OID())
+	}
+	changes, err := backend.SymbolChanges(ctx, head.repo.repo, baseID, api.CommitID(head.OID()))
+	if err != nil {
+		return nil, err
+	}
+
+	toResolver := func(commit *GitCommitResolver, symbol *protocol.Symbol) (*symbolResolver, error) {
+		if symbol == nil {
+			return nil, nil
+		}
+		baseURI, err := gituri.Parse("git://" + string(commit.repo.repo.Name) + "?" + string(commit.oid))
+		if err != nil {
+			return nil, err
+		}
+		return toSymbolResolver(*symbol, baseURI, strings.ToLower(symbol.Language), commit), nil
+	}
+	resolvers := make([]*symbolChangeResolver, len(changes))
+	for i, c := range changes {
+		oldSymbol, err := toResolver(base, c.OldSymbol)
+		if err != nil {
+			return nil, err
+		}
+		newSymbol, err := toResolver(head, c.NewSymbol)
+		if err != nil {
+			return nil, err
+		}
+		resolvers[i] = &symbolChangeResolver{typ: c.Type, oldSymbol: oldSymbol, newSymbol: newSymbol}
+	}
+	return resolvers, nil
+}
+
+type symbolChangeResolver struct {
+	typ                  string
+	oldSymbol, newSymbol *symbolResolver
+}
+
+func (r *symbolChangeResolver) Type() string { return r.typ }
+
+func (r *symbolChangeResolver) OldSymbol() *symbolResolver { return r.oldSymbol }
+
+func (r *symbolChangeResolver) NewSymbol() *symbolResolver { return r.newSymbol }
@@ -28,6 +28,11 @@ func (r *GitTreeEntryResolver) Symbols(ctx context.Context, args *symbolsArgs) (
 	if err != nil && len(symbols) == 0 {
 		return nil, err
 	}
+	// 🚨 SECURITY: Remove symbols in files that the user may not read.
+	symbols, err = filterSymbolsBySubRepoPerms(ctx, r.commit.repo.repo, symbols)
+	if err != nil {
+		return nil, err
+	}
 	return &symbolConnectionResolver{symbols: symbols, first: args.First}, nil
 }
 
@@ -36,6 +41,11 @@ func (r *GitCommitResolver) Symbols(ctx context.Context, args *symbolsArgs) (*sy
 	if err != nil && len(symbols) == 0 {
 		return nil, err
 	}
+	// 🚨 SECURITY: Remove symbols in files that the user may not read.
+	symbols, err = filterSymbolsBySubRepoPerms(ctx, r.repo.repo, symbols)
+	if err != nil {
+		return nil, err
+	}
 	return &symbolConnectionResolver{symbols: symbols, first: args.First}, nil
 }
 
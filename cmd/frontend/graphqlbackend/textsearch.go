@@ -159,6 +159,10 @@ type lineMatch struct {
 	JOffsetAndLengths [][2]int32 `json:"OffsetAndLengths"`
 	JLineNumber       int32      `json:"LineNumber"`
 	JLimitHit         bool       `json:"LimitHit"`
+
+	// JSubmatchOffsetAndLengths is only set by searcher, and only if the search requested
+	// submatches (see search.TextPatternInfo.IncludeSubmatches).
+	JSubmatchOffsetAndLengths [][2]int32 `json:"SubmatchOffsetAndLengths"`
 }
 
 func (lm *lineMatch) Preview() string {
@@ -206,6 +210,7 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 		"FetchTimeout":    []string{fetchTimeout.String()},
 		"Languages":       p.Languages,
 		"CombyRule":       []string{p.CombyRule},
+		"Scope":           []string{p.Scope},
 	}
 	if deadline, ok := ctx.Deadline(); ok {
 		t, err := deadline.MarshalText()
@@ -227,6 +232,9 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	if p.IsCaseSensitive {
 		q.Set("IsCaseSensitive", "true")
 	}
+	if p.IncludeSubmatches {
+		q.Set("IncludeSubmatches", "true")
+	}
 	if p.PathPatternsAreRegExps {
 		q.Set("PathPatternsAreRegExps", "true")
 	}
@@ -533,6 +541,14 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 		}
 	}
 
+	// Indexed search can't classify matches as comments, strings or code, so scoped searches
+	// use searcher for all repositories.
+	if args.PatternInfo.Scope != "" && !args.PatternInfo.IsStructuralPat && len(zoektRepos) > 0 {
+		tr.LazyPrintf("scope:%s, bypassing zoekt (using searcher) for %d indexed repos", args.PatternInfo.Scope, len(zoektRepos))
+		searcherRepos = append(searcherRepos, zoektRepos...)
+		zoektRepos = nil
+	}
+
 	var (
 		// TODO: convert wg to an errgroup
 		wg                sync.WaitGroup
@@ -644,6 +660,10 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 					defer done()
 
 					matches, repoLimitHit, err := searchFilesInRepo(ctx, args.SearcherURLs, repoRev.Repo, repoRev.GitserverRepo(), repoRev.RevSpecs()[0], args.PatternInfo, fetchTimeout)
+					if err == nil {
+						// 🚨 SECURITY: Remove matches in files that the user may not read.
+						matches, err = filterFileMatchesBySubRepoPerms(ctx, matches)
+					}
 					if err != nil {
 						tr.LogFields(otlog.String("repo", string(repoRev.Repo.Name)), otlog.Error(err), otlog.Bool("timeout", errcode.IsTimeout(err)), otlog.Bool("temporary", errcode.IsTemporary(err)))
 						log15.Warn("searchFilesInRepo failed", "error", err, "repo", repoRev.Repo.Name)
@@ -693,6 +713,10 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 		} else {
 			matches, limitHit, reposLimitHit, err = zoektSearchHEADOnlyFiles(ctx, args, zoektRepos, false, time.Since)
 		}
+		if err == nil {
+			// 🚨 SECURITY: Remove matches in files that the user may not read.
+			matches, err = filterFileMatchesBySubRepoPerms(ctx, matches)
+		}
 		mu.Lock()
 		defer mu.Unlock()
 		if ctx.Err() == nil {
@@ -0,0 +1,220 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// maxActivityFeedPageSize is the maximum number of activity feed entries per page.
+const maxActivityFeedPageSize = 500
+
+func (*schemaResolver) Watch(ctx context.Context, args *struct {
+	Repository  graphql.ID
+	PathPattern *string
+	EmailDigest bool
+}) (*watchResolver, error) {
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users may only watch repositories that they can view. Which files they can view
+	// is checked when the feed entries are read.
+	repo, err := RepositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	var pattern string
+	if args.PathPattern != nil {
+		if pattern, err = backend.NormalizeWatchPathPattern(*args.PathPattern); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &db.Watch{UserID: user.ID, RepoID: repo.repo.ID, PathPattern: pattern, EmailDigest: args.EmailDigest}
+	if err := db.Watches.Upsert(ctx, w); err != nil {
+		return nil, err
+	}
+	return &watchResolver{watch: w}, nil
+}
+
+func (*schemaResolver) Unwatch(ctx context.Context, args *struct {
+	Watch graphql.ID
+}) (*EmptyResponse, error) {
+	w, err := watchByID(ctx, args.Watch)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Watches.Delete(ctx, w.watch.ID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *UserResolver) Watches(ctx context.Context) ([]*watchResolver, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to access the user's watches.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+	watches, err := db.Watches.List(ctx, db.WatchesListOptions{UserID: r.user.ID})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*watchResolver, len(watches))
+	for i, w := range watches {
+		resolvers[i] = &watchResolver{watch: w}
+	}
+	return resolvers, nil
+}
+
+func (r *UserResolver) ActivityFeed(ctx context.Context, args *struct {
+	First int32
+	After *string
+}) (*activityFeedConnectionResolver, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to access the user's activity feed.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+	if args.First < 0 || args.First > maxActivityFeedPageSize {
+		return nil, errors.New("first must be between 0 and 500")
+	}
+
+	opt := db.WatchFeedEntriesListOptions{
+		UserID:      r.user.ID,
+		LimitOffset: &db.LimitOffset{Limit: int(args.First) + 1}, // one more to determine whether there is a next page
+	}
+	if args.After != nil {
+		id, err := strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid activity feed cursor")
+		}
+		opt.BeforeID = id
+	}
+	entries, err := db.Watches.ListFeedEntries(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	pageInfo := graphqlutil.HasNextPage(false)
+	if len(entries) > int(args.First) {
+		entries = entries[:args.First]
+		if len(entries) > 0 {
+			pageInfo = graphqlutil.NextPageCursor(strconv.FormatInt(entries[len(entries)-1].ID, 10))
+		}
+	}
+
+	// 🚨 SECURITY: Only return the entries (and files) that the viewer may view.
+	items, err := backend.FilterWatchFeedEntries(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	watches := map[int32]*watchResolver{}
+	nodes := make([]*activityFeedEntryResolver, len(items))
+	for i, item := range items {
+		w, ok := watches[item.Entry.WatchID]
+		if !ok {
+			watch, err := db.Watches.GetByID(ctx, item.Entry.WatchID)
+			if err != nil {
+				return nil, err
+			}
+			w = &watchResolver{watch: watch}
+			watches[watch.ID] = w
+		}
+		nodes[i] = &activityFeedEntryResolver{
+			watch: w,
+			commit: &GitCommitResolver{
+				repo:            NewRepositoryResolver(item.Repo),
+				includeUserInfo: true,
+				oid:             GitObjectID(item.Entry.CommitID),
+			},
+			entry: item.Entry,
+		}
+	}
+	return &activityFeedConnectionResolver{nodes: nodes, pageInfo: pageInfo}, nil
+}
+
+func watchByID(ctx context.Context, id graphql.ID) (*watchResolver, error) {
+	watchID, err := unmarshalWatchID(id)
+	if err != nil {
+		return nil, err
+	}
+	w, err := db.Watches.GetByID(ctx, watchID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins may view the watch.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, w.UserID); err != nil {
+		return nil, err
+	}
+	return &watchResolver{watch: w}, nil
+}
+
+func marshalWatchID(id int32) graphql.ID {
+	return relay.MarshalID("Watch", id)
+}
+
+func unmarshalWatchID(id graphql.ID) (watchID int32, err error) {
+	err = relay.UnmarshalSpec(id, &watchID)
+	return
+}
+
+// watchResolver implements the GraphQL type Watch.
+type watchResolver struct {
+	watch *db.Watch
+}
+
+func (r *watchResolver) ID() graphql.ID { return marshalWatchID(r.watch.ID) }
+
+func (r *watchResolver) User(ctx context.Context) (*UserResolver, error) {
+	return UserByIDInt32(ctx, r.watch.UserID)
+}
+
+func (r *watchResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := RepositoryByIDInt32(ctx, r.watch.RepoID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return repo, err
+}
+
+func (r *watchResolver) PathPattern() *string {
+	if r.watch.PathPattern == "" {
+		return nil
+	}
+	return &r.watch.PathPattern
+}
+
+func (r *watchResolver) EmailDigest() bool { return r.watch.EmailDigest }
+
+func (r *watchResolver) CreatedAt() DateTime { return DateTime{Time: r.watch.CreatedAt} }
+
+type activityFeedConnectionResolver struct {
+	nodes    []*activityFeedEntryResolver
+	pageInfo *graphqlutil.PageInfo
+}
+
+func (r *activityFeedConnectionResolver) Nodes() []*activityFeedEntryResolver { return r.nodes }
+
+func (r *activityFeedConnectionResolver) PageInfo() *graphqlutil.PageInfo { return r.pageInfo }
+
+// activityFeedEntryResolver implements the GraphQL type ActivityFeedEntry.
+type activityFeedEntryResolver struct {
+	watch  *watchResolver
+	commit *GitCommitResolver
+	entry  *db.WatchFeedEntry
+}
+
+func (r *activityFeedEntryResolver) Watch() *watchResolver { return r.watch }
+
+func (r *activityFeedEntryResolver) Commit() *GitCommitResolver { return r.commit }
+
+func (r *activityFeedEntryResolver) Files() []string { return r.entry.Paths }
+
+func (r *activityFeedEntryResolver) CreatedAt() DateTime { return DateTime{Time: r.entry.CreatedAt} }
@@ -0,0 +1,88 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestUserActivityFeed(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 2, SiteAdmin: true}, nil
+	}
+	db.Mocks.Users.GetByUsername = func(context.Context, string) (*types.User, error) {
+		return &types.User{ID: 1, Username: "alice"}, nil
+	}
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id, Name: "github.com/acme/a"}, nil
+	}
+	backend.Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secrets", Allow: false}}}, nil
+	}
+	db.Mocks.Watches.GetByID = func(_ context.Context, id int32) (*db.Watch, error) {
+		return &db.Watch{ID: id, UserID: 1, RepoID: 1, PathPattern: "pkg/**"}, nil
+	}
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Mocks.Watches.ListFeedEntries = func(_ context.Context, opt db.WatchFeedEntriesListOptions) ([]*db.WatchFeedEntry, error) {
+		if opt.UserID != 1 || opt.BeforeID != 10 || opt.Limit != 3 {
+			t.Errorf("got options %+v", opt)
+		}
+		return []*db.WatchFeedEntry{
+			{ID: 9, WatchID: 1, UserID: 1, RepoID: 1, CommitID: "1111111111111111111111111111111111111111", Paths: []string{"pkg/a.go", "secrets/key"}, CreatedAt: createdAt},
+			{ID: 8, WatchID: 1, UserID: 1, RepoID: 1, CommitID: "2222222222222222222222222222222222222222", Paths: []string{"secrets/key"}, CreatedAt: createdAt},
+			{ID: 7, WatchID: 1, UserID: 1, RepoID: 1, CommitID: "3333333333333333333333333333333333333333", Paths: []string{"pkg/b.go"}, CreatedAt: createdAt},
+		}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					user(username: "alice") {
+						activityFeed(first: 2, after: "10") {
+							nodes {
+								watch { pathPattern }
+								commit { oid }
+								files
+								createdAt
+							}
+							pageInfo {
+								endCursor
+								hasNextPage
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"user": {
+						"activityFeed": {
+							"nodes": [
+								{
+									"watch": {"pathPattern": "pkg/**"},
+									"commit": {"oid": "1111111111111111111111111111111111111111"},
+									"files": ["pkg/a.go"],
+									"createdAt": "2020-01-02T03:04:05Z"
+								}
+							],
+							"pageInfo": {
+								"endCursor": "8",
+								"hasNextPage": true
+							}
+						}
+					}
+				}
+			`,
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package httpapi
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -11,3 +12,10 @@ type LSIFServerProxy struct {
 
 // Set by enterprise frontend
 var NewLSIFServerProxy func() (*LSIFServerProxy, error)
+
+// EnforceUploadAuth checks that the request proves (e.g., with a code host token) that the uploader
+// has write access to the repository. It is used for uploads of LSIF data and coverage reports when
+// the site configuration sets lsifEnforceAuth. If it returns false, it has written an error response.
+//
+// Set by enterprise frontend
+var EnforceUploadAuth func(ctx context.Context, w http.ResponseWriter, r *http.Request, repoName string) bool
@@ -12,7 +12,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/errorutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/router"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/slackapp"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
@@ -37,6 +39,15 @@ func NewHandler() http.Handler {
 
 	r.Get(router.RepoBadge).Handler(trace.TraceRoute(errorutil.Handler(serveRepoBadge)))
 
+	r.Get(router.SnippetShareLink).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(errorutil.Handler(serveSnippetShareLink))))
+
+	r.Get(router.FeedSavedSearch).Handler(trace.TraceRoute(serveFeed(savedSearchFeed)))
+	r.Get(router.FeedRepoCommits).Handler(trace.TraceRoute(serveFeed(repoCommitsFeed)))
+	r.Get(router.FeedCampaign).Handler(trace.TraceRoute(serveFeed(campaignChangesetsFeed)))
+
+	r.Get(router.SlackLink).Handler(trace.TraceRoute(errorutil.Handler(slackapp.ServeLink)))
+	r.Get(router.SlackLinkCallback).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(errorutil.Handler(slackapp.ServeLinkCallback))))
+
 	// Redirects
 	r.Get(router.OldToolsRedirect).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/beta", http.StatusMovedPermanently)
@@ -52,13 +63,13 @@ func NewHandler() http.Handler {
 
 	r.Get(router.UI).Handler(ui.Router())
 
-	r.Get(router.SignUp).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSignUp)))
-	r.Get(router.SiteInit).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSiteInit)))
+	r.Get(router.SignUp).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(http.HandlerFunc(userpasswd.HandleSignUp))))
+	r.Get(router.SiteInit).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(http.HandlerFunc(userpasswd.HandleSiteInit))))
 	r.Get(router.SignIn).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSignIn)))
 	r.Get(router.SignOut).Handler(trace.TraceRoute(http.HandlerFunc(serveSignOut)))
-	r.Get(router.VerifyEmail).Handler(trace.TraceRoute(http.HandlerFunc(serveVerifyEmail)))
-	r.Get(router.ResetPasswordInit).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleResetPasswordInit)))
-	r.Get(router.ResetPasswordCode).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleResetPasswordCode)))
+	r.Get(router.VerifyEmail).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(http.HandlerFunc(serveVerifyEmail))))
+	r.Get(router.ResetPasswordInit).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(http.HandlerFunc(userpasswd.HandleResetPasswordInit))))
+	r.Get(router.ResetPasswordCode).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(http.HandlerFunc(userpasswd.HandleResetPasswordCode))))
 
 	r.Get(router.RegistryExtensionBundle).Handler(trace.TraceRoute(gziphandler.GzipHandler(http.HandlerFunc(registry.HandleRegistryExtensionBundle))))
 
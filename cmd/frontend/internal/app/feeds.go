@@ -0,0 +1,313 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/atom"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/routevar"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// feedMaxEntries is the maximum number of entries in a feed.
+	feedMaxEntries = 50
+
+	// savedSearchFeedWindow is how far back a saved search feed looks for new results.
+	savedSearchFeedWindow = 7 * 24 * time.Hour
+)
+
+// feedCache caches the feeds built for each user for a few minutes, so that feed readers polling
+// often don't run searches and Git commands on every request.
+var feedCache = rcache.NewWithTTL("atom_feeds:v1", 5*60)
+
+type cachedFeed struct {
+	Updated time.Time
+	Body    []byte
+}
+
+// campaignFeed returns the feed of the changeset state changes in the campaign with the given ID,
+// as seen by the actor. It is set by RegisterCampaignFeed, because campaigns are an enterprise
+// feature.
+var campaignFeed func(ctx context.Context, id graphql.ID) (*atom.Feed, error)
+
+// RegisterCampaignFeed registers the function that builds campaign feeds. This function should
+// only be called once on startup.
+func RegisterCampaignFeed(f func(ctx context.Context, id graphql.ID) (*atom.Feed, error)) {
+	if campaignFeed != nil {
+		panic("RegisterCampaignFeed already called")
+	}
+	campaignFeed = f
+}
+
+// searchCommits is graphqlbackend.SearchCommits, which tests may replace.
+var searchCommits = graphqlbackend.SearchCommits
+
+// serveFeed returns a handler that serves the Atom feed built by build for the user of the feed
+// token in the URL. Feeds are cached briefly for each user and URL, and conditional requests
+// (If-None-Match and If-Modified-Since) are supported.
+//
+// 🚨 SECURITY: Feed routes are accessible to anonymous users. Only access tokens with the
+// "feeds:read" scope are accepted, and the feed is built with the permissions of the token's user.
+func serveFeed(build func(ctx context.Context, r *http.Request) (*atom.Feed, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The token is in the URL, so it must not leak via shared caches or the Referer header of
+		// links followed from the feed. Errors are reported here (instead of by errorutil) so that
+		// the URL isn't logged.
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex")
+
+		token := mux.Vars(r)["Token"]
+		if a := conf.AccessTokensAllow(); a != conf.AccessTokensAll && a != conf.AccessTokensAdmin {
+			http.Error(w, "Access tokens are disabled.", http.StatusUnauthorized)
+			return
+		}
+		userID, err := db.AccessTokens.Lookup(r.Context(), token, authz.ScopeFeedsRead)
+		if err != nil {
+			http.Error(w, "Invalid feed token.", http.StatusUnauthorized)
+			return
+		}
+		ctx := actor.WithActor(r.Context(), &actor.Actor{UID: userID})
+
+		path := strings.TrimPrefix(r.URL.Path, "/-/feeds/"+token)
+		key := fmt.Sprintf("%d:%s?%s", userID, path, r.URL.RawQuery)
+		var feed cachedFeed
+		if b, ok := feedCache.Get(key); !ok || json.Unmarshal(b, &feed) != nil {
+			f, err := build(ctx, r)
+			if err != nil {
+				status := errcode.HTTP(err)
+				if status >= http.StatusInternalServerError {
+					log15.Error("Failed to build feed.", "path", path, "user", userID, "error", err)
+				}
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			body, err := f.Marshal()
+			if err != nil {
+				log15.Error("Failed to marshal feed.", "path", path, "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			feed = cachedFeed{Updated: f.Updated, Body: body}
+			if b, err := json.Marshal(feed); err == nil {
+				feedCache.Set(key, b)
+			}
+		}
+
+		sum := sha256.Sum256(feed.Body)
+		w.Header().Set("Content-Type", atom.ContentType)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		http.ServeContent(w, r, "", feed.Updated, bytes.NewReader(feed.Body))
+	})
+}
+
+// feedID returns the ID of the feed served at the given path (relative to the feed token). It is
+// a stable absolute IRI that doesn't contain the token.
+func feedID(path string) string {
+	return globals.ExternalURL().String() + "/-/feeds" + path
+}
+
+func notFound(err error) error {
+	return &errcode.HTTPErr{Status: http.StatusNotFound, Err: err}
+}
+
+// savedSearchFeed builds the feed of the commits and diffs found by a saved type:diff or
+// type:commit search in the last week.
+func savedSearchFeed(ctx context.Context, r *http.Request) (*atom.Feed, error) {
+	id := graphql.ID(mux.Vars(r)["ID"])
+	if relay.UnmarshalKind(id) != "SavedSearch" {
+		return nil, notFound(errors.New("invalid saved search ID"))
+	}
+	var savedSearchID int32
+	if err := relay.UnmarshalSpec(id, &savedSearchID); err != nil {
+		return nil, notFound(err)
+	}
+	ss, err := db.SavedSearches.GetByID(ctx, savedSearchID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	// 🚨 SECURITY: Make sure the user has permission to get the saved search, like the
+	// Query.node GraphQL resolver does.
+	if ss.Config.UserID != nil {
+		err = backend.CheckSiteAdminOrSameUser(ctx, *ss.Config.UserID)
+	} else if ss.Config.OrgID != nil {
+		err = backend.CheckOrgAccess(ctx, *ss.Config.OrgID)
+	} else {
+		err = errors.New("saved search has no user or organization")
+	}
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if !ss.Config.SupportsAfter() {
+		return nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("only type:diff and type:commit saved searches have feeds")}
+	}
+
+	now := time.Now()
+	results, err := searchCommits(ctx, ss.Config.QueryAfter(now.Add(-savedSearchFeedWindow)))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return commitDate(results[i].Commit).After(commitDate(results[j].Commit))
+	})
+	if len(results) > feedMaxEntries {
+		results = results[:feedMaxEntries]
+	}
+
+	searchURL := globals.ExternalURL().ResolveReference(&url.URL{
+		Path:     "/search",
+		RawQuery: url.Values{"q": {ss.Config.Query}}.Encode(),
+	})
+	feed := &atom.Feed{
+		ID:      feedID("/saved-searches/" + string(id)),
+		Title:   "Saved search: " + ss.Config.Description,
+		Updated: now,
+		Links:   []*atom.Link{{Href: searchURL.String(), Type: "text/html"}},
+	}
+	for _, res := range results {
+		feed.Entries = append(feed.Entries, commitFeedEntry(res.Repo.Name, res.Commit, res.DiffPreview))
+	}
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Updated
+	}
+	return feed, nil
+}
+
+// repoCommitsFeed builds the feed of the latest commits on a revision of a repository (the
+// default branch by default), optionally only those that modify a path.
+func repoCommitsFeed(ctx context.Context, r *http.Request) (*atom.Feed, error) {
+	repo, err := backend.Repos.GetByName(ctx, routevar.ToRepo(mux.Vars(r)))
+	if err != nil {
+		return nil, err
+	}
+	rev := r.URL.Query().Get("rev")
+	path := strings.Trim(r.URL.Query().Get("path"), "/")
+
+	// 🚨 SECURITY: Commits may reveal the contents of any file they modify, so the user must be
+	// allowed to read everything below the path.
+	perms, err := backend.SubRepoPerms(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !perms.AllowedTree(path) {
+		return nil, &errcode.HTTPErr{Status: http.StatusForbidden, Err: errors.New("path is not readable")}
+	}
+
+	commitID, err := backend.Repos.ResolveRev(ctx, repo, rev)
+	if err != nil {
+		return nil, err
+	}
+	gitRepo, err := backend.CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := git.Commits(ctx, *gitRepo, git.CommitsOptions{Range: string(commitID), N: feedMaxEntries, Path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	title := string(repo.Name)
+	if rev != "" {
+		title += "@" + rev
+	}
+	if path != "" {
+		title += ": " + path
+	}
+	id := "/" + string(repo.Name) + "/-/commits"
+	q := url.Values{}
+	if rev != "" {
+		q.Set("rev", rev)
+	}
+	if path != "" {
+		q.Set("path", path)
+	}
+	if len(q) > 0 {
+		id += "?" + q.Encode()
+	}
+	historyURL := "/" + string(repo.Name) + "/-/commits"
+	if rev != "" {
+		historyURL = "/" + string(repo.Name) + "@" + rev + "/-/commits"
+	}
+	feed := &atom.Feed{
+		ID:      feedID(id),
+		Title:   "Commits in " + title,
+		Updated: time.Now(),
+		Links:   []*atom.Link{{Href: globals.ExternalURL().String() + historyURL, Type: "text/html"}},
+	}
+	for _, c := range commits {
+		feed.Entries = append(feed.Entries, commitFeedEntry(repo.Name, c, ""))
+	}
+	if len(commits) > 0 {
+		feed.Updated = commitDate(commits[0])
+	}
+	return feed, nil
+}
+
+// campaignChangesetsFeed builds the feed of the changeset state changes in a campaign.
+func campaignChangesetsFeed(ctx context.Context, r *http.Request) (*atom.Feed, error) {
+	if campaignFeed == nil {
+		return nil, notFound(errors.New("campaigns are not available"))
+	}
+	return campaignFeed(ctx, graphql.ID(mux.Vars(r)["ID"]))
+}
+
+// commitFeedEntry returns the feed entry of a commit, whose content is the commit message followed
+// by the diff (if any).
+func commitFeedEntry(repo api.RepoName, c *git.Commit, diff string) *atom.Entry {
+	commitURL := globals.ExternalURL().String() + "/" + string(repo) + "/-/commit/" + string(c.ID)
+	subject := c.Message
+	if i := strings.Index(subject, "\n"); i >= 0 {
+		subject = subject[:i]
+	}
+	content := strings.TrimSpace(c.Message)
+	if diff != "" {
+		content += "\n\n" + diff
+	}
+	author := &atom.Person{Name: c.Author.Name, Email: c.Author.Email}
+	if author.Name == "" {
+		author.Name = c.Author.Email
+	}
+	return &atom.Entry{
+		ID:      commitURL,
+		Title:   fmt.Sprintf("%s: %s", repo, subject),
+		Updated: commitDate(c),
+		Author:  author,
+		Links:   []*atom.Link{{Href: commitURL, Type: "text/html"}},
+		Content: &atom.Text{Body: content},
+	}
+}
+
+// commitDate returns the committer date of the commit, which (unlike the author date) increases
+// along the history of a branch.
+func commitDate(c *git.Commit) time.Time {
+	if c.Committer != nil {
+		return c.Committer.Date
+	}
+	return c.Author.Date
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/atom"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestServeFeed(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	defer func() { backend.Mocks = backend.MockServices{} }()
+	defer func(orig func(context.Context, string) ([]*graphqlbackend.CommitSearchResult, error)) {
+		searchCommits = orig
+	}(searchCommits)
+
+	// Token "t1" is a feed token of user 1, and "t2" one of user 2. Token "all" doesn't have the
+	// feeds scope.
+	db.Mocks.AccessTokens.Lookup = func(token, requiredScope string) (int32, error) {
+		if requiredScope != authz.ScopeFeedsRead {
+			t.Errorf("got scope %q, want %q", requiredScope, authz.ScopeFeedsRead)
+		}
+		switch token {
+		case "t1":
+			return 1, nil
+		case "t2":
+			return 2, nil
+		}
+		return 0, db.ErrAccessTokenNotFound
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	userID := int32(1)
+	db.Mocks.SavedSearches.GetByID = func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		query := "repo:a type:diff foo"
+		if id == 2 {
+			query = "repo:a foo"
+		}
+		return &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{Description: "Foo", Query: query, UserID: &userID}}, nil
+	}
+	date := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	searchCommits = func(ctx context.Context, query string) ([]*graphqlbackend.CommitSearchResult, error) {
+		if !strings.HasPrefix(query, "repo:a type:diff foo after:") {
+			t.Errorf("got query %q", query)
+		}
+		repo := &types.Repo{Name: "github.com/acme/a"}
+		return []*graphqlbackend.CommitSearchResult{
+			{Repo: repo, Commit: &git.Commit{ID: "c1", Message: "Old", Author: git.Signature{Name: "Alice", Date: date}}},
+			{Repo: repo, Commit: &git.Commit{ID: "c2", Message: "New\n\nBody", Author: git.Signature{Email: "bob@example.com", Date: date.Add(time.Hour)}}, DiffPreview: "+foo"},
+		}, nil
+	}
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		return &types.Repo{ID: 1, Name: name}, nil
+	}
+	backend.Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secrets", Allow: false}}}, nil
+	}
+
+	serve := func(build func(context.Context, *http.Request) (*atom.Feed, error), target string, vars map[string]string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req = mux.SetURLVars(req, vars)
+		rec := httptest.NewRecorder()
+		serveFeed(build).ServeHTTP(rec, req)
+		return rec
+	}
+	savedSearch := func(token string, id int32, header http.Header) *httptest.ResponseRecorder {
+		relayID := string(relay.MarshalID("SavedSearch", id))
+		return serve(savedSearchFeed, "/-/feeds/"+token+"/saved-searches/"+relayID, map[string]string{"Token": token, "ID": relayID}, header)
+	}
+
+	t.Run("saved search", func(t *testing.T) {
+		rec := savedSearch("t1", 1, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != atom.ContentType {
+			t.Errorf("got content type %q, want %q", got, atom.ContentType)
+		}
+		if got := rec.Header().Get("Referrer-Policy"); got != "no-referrer" {
+			t.Errorf("got referrer policy %q", got)
+		}
+		body := rec.Body.String()
+		if err := atom.Validate([]byte(body)); err != nil {
+			t.Fatalf("invalid feed: %s\n%s", err, body)
+		}
+		if strings.Contains(body, "t1") {
+			t.Errorf("feed contains the token:\n%s", body)
+		}
+		if i, j := strings.Index(body, "/-/commit/c2"), strings.Index(body, "/-/commit/c1"); i < 0 || j < 0 || i > j {
+			t.Errorf("want the newest commit first:\n%s", body)
+		}
+		for _, want := range []string{"<title>github.com/acme/a: New</title>", "<name>bob@example.com</name>", "+foo", "<updated>2020-03-01T01:00:00Z</updated>"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("feed doesn't contain %q:\n%s", want, body)
+			}
+		}
+
+		// Conditional requests are supported.
+		etag := rec.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("no ETag")
+		}
+		if rec := savedSearch("t1", 1, http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusNotModified)
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		for _, token := range []string{"", "all"} {
+			if rec := savedSearch(token, 1, nil); rec.Code != http.StatusUnauthorized {
+				t.Errorf("%q: got status %d, want %d", token, rec.Code, http.StatusUnauthorized)
+			}
+		}
+	})
+
+	t.Run("saved search of another user", func(t *testing.T) {
+		if rec := savedSearch("t2", 1, nil); rec.Code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("saved search without after support", func(t *testing.T) {
+		if rec := savedSearch("t1", 2, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("commits of an unreadable path", func(t *testing.T) {
+		for _, path := range []string{"", "secrets", "secrets/a"} {
+			rec := serve(repoCommitsFeed, "/-/feeds/t1/github.com/acme/a/-/commits?path="+path, map[string]string{"Token": "t1", "Repo": "github.com/acme/a"}, nil)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("%q: got status %d, want %d", path, rec.Code, http.StatusForbidden)
+			}
+		}
+	})
+
+	t.Run("campaigns unavailable", func(t *testing.T) {
+		rec := serve(campaignChangesetsFeed, "/-/feeds/t1/campaigns/x", map[string]string{"Token": "t1", "ID": "x"}, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+}
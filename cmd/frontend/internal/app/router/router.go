@@ -18,6 +18,15 @@ const (
 
 	RepoBadge = "repo.badge"
 
+	SnippetShareLink = "snippet-share-link"
+
+	FeedSavedSearch = "feed.saved-search"
+	FeedRepoCommits = "feed.repo-commits"
+	FeedCampaign    = "feed.campaign"
+
+	SlackLink         = "slack.link"
+	SlackLinkCallback = "slack.link.callback"
+
 	Logout = "logout"
 
 	SignIn            = "sign-in"
@@ -70,6 +79,15 @@ func newRouter() *mux.Router {
 	base.Path("/-/reset-password-init").Methods("POST").Name(ResetPasswordInit)
 	base.Path("/-/reset-password-code").Methods("POST").Name(ResetPasswordCode)
 
+	base.Path("/-/snippet/{Token}").Methods("GET", "POST").Name(SnippetShareLink)
+
+	base.Path("/-/feeds/{Token}/saved-searches/{ID}").Methods("GET", "HEAD").Name(FeedSavedSearch)
+	base.Path("/-/feeds/{Token}/campaigns/{ID}").Methods("GET", "HEAD").Name(FeedCampaign)
+	base.Path("/-/feeds/{Token}/"+routevar.Repo+"/-/commits").Methods("GET", "HEAD").Name(FeedRepoCommits)
+
+	base.Path("/-/slack/link").Methods("GET").Name(SlackLink)
+	base.Path("/-/slack/link/callback").Methods("GET").Name(SlackLinkCallback)
+
 	base.Path("/-/static/extension/{RegistryExtensionReleaseFilename}").Methods("GET").Name(RegistryExtensionBundle)
 
 	base.Path("/-/godoc/refs").Methods("GET").Name(GDDORefs)
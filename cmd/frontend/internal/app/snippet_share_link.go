@@ -0,0 +1,229 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
+)
+
+var snippetSharePage = template.Must(template.New("").Parse(`<!doctype html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex">
+	<title>{{if .Path}}{{.Path}} - {{end}}Shared snippet - Sourcegraph</title>
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 2rem; color: #2b3750; }
+		.meta { color: #566e9f; margin-bottom: 1rem; }
+		.snippet { border: 1px solid #dee2e6; overflow-x: auto; }
+		.snippet table { border-collapse: collapse; font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 12px; counter-reset: line {{.LineOffset}}; }
+		.snippet td.line { color: #93a9c8; padding: 0 1rem; text-align: right; user-select: none; vertical-align: top; }
+		.snippet td.line::before { counter-increment: line; content: counter(line); }
+		.snippet td.code { white-space: pre; padding-right: 1rem; }
+		.error { color: #c0392b; }
+	</style>
+</head>
+<body>
+	{{if .Message}}
+		<h1>{{.Message}}</h1>
+	{{else if .PasswordRequired}}
+		<h1>This snippet is protected by a password</h1>
+		<form method="POST">
+			{{.CSRFField}}
+			<input type="password" name="password" autofocus required>
+			<button type="submit">View snippet</button>
+		</form>
+		{{if .WrongPassword}}<p class="error">The password is incorrect.</p>{{end}}
+	{{else}}
+		<h1>{{.Path}}</h1>
+		<div class="meta">
+			{{.Repo}} at commit <code>{{.Commit}}</code>, lines {{.StartLine}}&ndash;{{.EndLine}}.
+			This link expires {{.ExpiresAt.Format "2006-01-02 15:04 MST"}}.
+		</div>
+		<div class="snippet">{{.Code}}</div>
+	{{end}}
+</body>
+</html>
+`))
+
+type snippetSharePageData struct {
+	Message string // an error message shown instead of the snippet
+
+	PasswordRequired bool
+	WrongPassword    bool
+	CSRFField        template.HTML
+
+	Repo               string
+	Commit             string
+	Path               string
+	StartLine, EndLine int32
+	LineOffset         int32 // the number of lines preceding the snippet
+	ExpiresAt          time.Time
+	Code               template.HTML
+}
+
+// serveSnippetShareLink serves the snippet of a snippet share link to anyone who knows the link's
+// secret token (and its password, if any), and records the view in the link's audit log.
+//
+// 🚨 SECURITY: This handler is accessible to anonymous users and reveals file contents regardless of
+// the viewer's repository permissions, so it must only do so for active links.
+func serveSnippetShareLink(w http.ResponseWriter, r *http.Request) error {
+	// The token is in the URL, so it must not leak via caches or the Referer header of links on the
+	// page.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	ctx := r.Context()
+	link, err := db.SnippetShareLinks.GetByToken(ctx, mux.Vars(r)["Token"])
+	if errcode.IsNotFound(err) {
+		return renderSnippetSharePage(w, http.StatusNotFound, &snippetSharePageData{Message: "This link doesn't exist."})
+	} else if err != nil {
+		return err
+	}
+
+	remoteAddr := r.RemoteAddr
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		remoteAddr = forwardedFor + ", " + remoteAddr
+	}
+	userAgent := r.UserAgent()
+
+	outcome := link.Inactive(time.Now())
+	if outcome == "" {
+		if ok, err := snippetShareLinkPermitted(ctx, link); err != nil {
+			return err
+		} else if !ok {
+			outcome = db.SnippetShareLinkRevoked
+		}
+	}
+	if outcome != "" {
+		if err := db.SnippetShareLinks.LogView(ctx, link.ID, outcome, remoteAddr, userAgent); err != nil {
+			return err
+		}
+		return renderInactiveSnippetShareLink(w, outcome)
+	}
+
+	if link.HasPassword {
+		data := &snippetSharePageData{PasswordRequired: true, CSRFField: csrf.TemplateField(r)}
+		if r.Method != "POST" {
+			return renderSnippetSharePage(w, http.StatusOK, data)
+		}
+		ok, err := db.SnippetShareLinks.CheckPassword(ctx, link.ID, r.PostFormValue("password"), remoteAddr, userAgent)
+		if err == db.ErrSnippetShareLinkPasswordThrottled {
+			return renderSnippetSharePage(w, http.StatusTooManyRequests, &snippetSharePageData{Message: "Too many incorrect passwords. Try again later."})
+		} else if err != nil {
+			return err
+		}
+		if !ok {
+			data.WrongPassword = true
+			return renderSnippetSharePage(w, http.StatusForbidden, data)
+		}
+	}
+
+	// 🚨 SECURITY: The creator of the link could read the snippet, and the link shares it with the
+	// viewer, so it is read with the internal actor.
+	internalCtx := actor.WithActor(ctx, &actor.Actor{Internal: true})
+	repo, err := db.Repos.Get(internalCtx, link.RepoID)
+	if err != nil {
+		return err
+	}
+	snippet, err := backend.ReadSnippet(internalCtx, repo, link.CommitID, link.Path, link.StartLine, link.EndLine)
+	if err != nil {
+		return err
+	}
+	code, _, err := highlight.Code(ctx, highlight.Params{
+		Content:      snippet,
+		Filepath:     link.Path,
+		IsLightTheme: true,
+		Metadata:     highlight.Metadata{RepoName: string(repo.Name), Revision: string(link.CommitID)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Check and count the view atomically, so that concurrent views can't exceed the view limit.
+	outcome, err = db.SnippetShareLinks.View(ctx, link.ID, remoteAddr, userAgent)
+	if err != nil {
+		return err
+	}
+	if outcome != db.SnippetShareLinkViewed {
+		return renderInactiveSnippetShareLink(w, outcome)
+	}
+	return renderSnippetSharePage(w, http.StatusOK, &snippetSharePageData{
+		Repo:       string(repo.Name),
+		Commit:     string(link.CommitID),
+		Path:       link.Path,
+		StartLine:  link.StartLine,
+		EndLine:    link.EndLine,
+		LineOffset: link.StartLine - 1,
+		ExpiresAt:  link.ExpiresAt,
+		Code:       code,
+	})
+}
+
+// snippetShareLinkPermitted reports whether the link may still be viewed: its creator must still
+// exist and be able to read the snippet's file, and the site configuration must still permit sharing
+// snippets of the repository. Links that aren't permitted are treated as revoked.
+//
+// 🚨 SECURITY: The creator's permissions are checked on every view, so that revoking their access to
+// the repository or the file also revokes the links they created.
+func snippetShareLinkPermitted(ctx context.Context, link *db.SnippetShareLink) (bool, error) {
+	if _, err := db.Users.GetByID(ctx, link.CreatorUserID); errcode.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	creatorCtx := actor.WithActor(ctx, actor.FromUser(link.CreatorUserID))
+	repo, err := db.Repos.Get(creatorCtx, link.RepoID)
+	if errcode.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	perms, err := backend.SubRepoPerms(creatorCtx, repo)
+	if err != nil {
+		return false, err
+	}
+	if !perms.Allowed(link.Path) {
+		return false, nil
+	}
+	ok, err := backend.CanShareSnippets(repo.Name)
+	if err == backend.ErrSnippetShareLinksDisabled {
+		return false, nil
+	}
+	return ok, err
+}
+
+func renderInactiveSnippetShareLink(w http.ResponseWriter, outcome db.SnippetShareLinkViewOutcome) error {
+	var message string
+	switch outcome {
+	case db.SnippetShareLinkExpired:
+		message = "This link has expired."
+	case db.SnippetShareLinkViewLimitReached:
+		message = "This link has reached its view limit."
+	default:
+		message = "This link was revoked."
+	}
+	return renderSnippetSharePage(w, http.StatusGone, &snippetSharePageData{Message: message})
+}
+
+func renderSnippetSharePage(w http.ResponseWriter, status int, data *snippetSharePageData) error {
+	var buf bytes.Buffer
+	if err := snippetSharePage.Execute(&buf, data); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := buf.WriteTo(w)
+	return err
+}
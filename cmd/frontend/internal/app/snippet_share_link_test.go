@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestServeSnippetShareLink(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SnippetShareLinks: &schema.SnippetShareLinks{Repos: []string{"^github\\.com/acme/"}},
+	}})
+	defer conf.Mock(nil)
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id, Name: "github.com/acme/a"}, nil
+	}
+	var logged []db.SnippetShareLinkViewOutcome
+	db.Mocks.SnippetShareLinks.LogView = func(ctx context.Context, id int64, outcome db.SnippetShareLinkViewOutcome, remoteAddr, userAgent string) error {
+		logged = append(logged, outcome)
+		return nil
+	}
+	db.Mocks.SnippetShareLinks.CheckPassword = func(ctx context.Context, id int64, password, remoteAddr, userAgent string) (bool, error) {
+		if id == 7 {
+			return false, db.ErrSnippetShareLinkPasswordThrottled
+		}
+		if password != "secret" {
+			logged = append(logged, db.SnippetShareLinkWrongPassword)
+		}
+		return password == "secret", nil
+	}
+	backend.Mocks.SubRepoPerms = func(ctx context.Context, repo *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secret", Allow: false}}}, nil
+	}
+	defer func() { backend.Mocks = backend.MockServices{} }()
+	db.Mocks.SnippetShareLinks.View = func(ctx context.Context, id int64, remoteAddr, userAgent string) (db.SnippetShareLinkViewOutcome, error) {
+		t.Fatal("View must not be called")
+		return "", nil
+	}
+
+	links := map[string]*db.SnippetShareLink{
+		"active":   {ID: 1, CreatorUserID: 1, RepoID: 1, Path: "a.go", StartLine: 1, EndLine: 2, HasPassword: true, ExpiresAt: time.Now().Add(time.Hour)},
+		"expired":  {ID: 2, CreatorUserID: 1, RepoID: 1, Path: "a.go", StartLine: 1, EndLine: 2, ExpiresAt: time.Now().Add(-time.Hour)},
+		"limit":    {ID: 3, CreatorUserID: 1, RepoID: 1, Path: "a.go", StartLine: 1, EndLine: 2, ExpiresAt: time.Now().Add(time.Hour), MaxViews: int32Ptr(1), ViewCount: 1},
+		"disabled": {ID: 4, CreatorUserID: 1, RepoID: 2, Path: "a.go", StartLine: 1, EndLine: 2, ExpiresAt: time.Now().Add(time.Hour)},
+		"norepo":   {ID: 5, CreatorUserID: 2, RepoID: 1, Path: "a.go", StartLine: 1, EndLine: 2, ExpiresAt: time.Now().Add(time.Hour)},
+		"nofile":   {ID: 6, CreatorUserID: 1, RepoID: 1, Path: "secret/a.go", StartLine: 1, EndLine: 2, ExpiresAt: time.Now().Add(time.Hour)},
+		"guessed":  {ID: 7, CreatorUserID: 1, RepoID: 1, Path: "a.go", StartLine: 1, EndLine: 2, HasPassword: true, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	db.Mocks.SnippetShareLinks.GetByToken = func(ctx context.Context, token string) (*db.SnippetShareLink, error) {
+		if link, ok := links[token]; ok {
+			return link, nil
+		}
+		return nil, db.SnippetShareLinkNotFoundError{}
+	}
+	reposGet := db.Mocks.Repos.Get
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		if id == 2 {
+			return &types.Repo{ID: id, Name: "github.com/other/b"}, nil
+		}
+		// The creator of the "norepo" link lost access to the repository.
+		if a := actor.FromContext(ctx); a.UID == 2 {
+			return nil, &errcode.Mock{Message: "repo not found", IsNotFound: true}
+		}
+		return reposGet(ctx, id)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		password   *string
+		wantStatus int
+		wantBody   string
+		wantLogged db.SnippetShareLinkViewOutcome
+	}{
+		{name: "not found", token: "missing", wantStatus: http.StatusNotFound, wantBody: "link doesn&#39;t exist"},
+		{name: "expired", token: "expired", wantStatus: http.StatusGone, wantBody: "expired", wantLogged: db.SnippetShareLinkExpired},
+		{name: "view limit reached", token: "limit", wantStatus: http.StatusGone, wantBody: "view limit", wantLogged: db.SnippetShareLinkViewLimitReached},
+		{name: "repository no longer permitted", token: "disabled", wantStatus: http.StatusGone, wantBody: "revoked", wantLogged: db.SnippetShareLinkRevoked},
+		{name: "creator can no longer read the repository", token: "norepo", wantStatus: http.StatusGone, wantBody: "revoked", wantLogged: db.SnippetShareLinkRevoked},
+		{name: "creator can no longer read the file", token: "nofile", wantStatus: http.StatusGone, wantBody: "revoked", wantLogged: db.SnippetShareLinkRevoked},
+		{name: "password form", token: "active", wantStatus: http.StatusOK, wantBody: `name="password"`},
+		{name: "wrong password", token: "active", password: strPtr("guess"), wantStatus: http.StatusForbidden, wantBody: "incorrect", wantLogged: db.SnippetShareLinkWrongPassword},
+		{name: "too many wrong passwords", token: "guessed", password: strPtr("secret"), wantStatus: http.StatusTooManyRequests, wantBody: "Too many incorrect passwords"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logged = nil
+			req := httptest.NewRequest("GET", "/-/snippet/"+test.token, nil)
+			if test.password != nil {
+				req = httptest.NewRequest("POST", "/-/snippet/"+test.token, strings.NewReader(url.Values{"password": {*test.password}}.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			req = mux.SetURLVars(req, map[string]string{"Token": test.token})
+			rec := httptest.NewRecorder()
+			if err := serveSnippetShareLink(rec, req); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != test.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, test.wantStatus)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, test.wantBody) {
+				t.Errorf("got body %q, want it to contain %q", body, test.wantBody)
+			}
+			if strings.Contains(rec.Body.String(), "a.go") && rec.Code != http.StatusOK {
+				t.Error("the snippet's file must not be revealed")
+			}
+			var wantLogged []db.SnippetShareLinkViewOutcome
+			if test.wantLogged != "" {
+				wantLogged = []db.SnippetShareLinkViewOutcome{test.wantLogged}
+			}
+			if len(logged) != len(wantLogged) || (len(logged) > 0 && logged[0] != wantLogged[0]) {
+				t.Errorf("got logged views %v, want %v", logged, wantLogged)
+			}
+		})
+	}
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+func strPtr(v string) *string { return &v }
@@ -13,6 +13,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 
@@ -141,6 +143,20 @@ func serveRaw(w http.ResponseWriter, r *http.Request) error {
 			relativePath = "."
 		}
 
+		// 🚨 SECURITY: Archives must not include any path that the user may not read.
+		perms, err := backend.SubRepoPerms(r.Context(), common.Repo)
+		if err != nil {
+			return err
+		}
+		if !perms.AllowedDir(relativePath) {
+			http.Error(w, html.EscapeString((&os.PathError{Op: "open", Path: requestedPath, Err: os.ErrNotExist}).Error()), http.StatusNotFound)
+			return nil // request handled
+		}
+		if !perms.AllowedTree(relativePath) {
+			http.Error(w, "archive contains paths that you do not have access to", http.StatusForbidden)
+			return nil // request handled
+		}
+
 		f, _, err := vfsutil.GitServerFetchArchive(r.Context(), vfsutil.ArchiveOpts{
 			Repo:         common.Repo.Name,
 			Commit:       common.CommitID,
@@ -202,9 +218,18 @@ func serveRaw(w http.ResponseWriter, r *http.Request) error {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.Header().Set("X-Content-Type-Options", "nosniff")
 
+		perms, err := backend.SubRepoPerms(r.Context(), common.Repo)
+		if err != nil {
+			return err
+		}
+
 		archiveFS := vfsutil.NewGitServer(common.Repo.Name, common.CommitID)
 		defer archiveFS.Close()
 		fi, err := archiveFS.Lstat(r.Context(), requestedPath)
+		if err == nil && !subRepoPathAllowed(perms, requestedPath, fi) {
+			// 🚨 SECURITY: Respond as if the path does not exist.
+			err = &os.PathError{Op: "lstat", Path: requestedPath, Err: os.ErrNotExist}
+		}
 		if err != nil {
 			if os.IsNotExist(err) {
 				http.Error(w, html.EscapeString(err.Error()), http.StatusNotFound)
@@ -219,6 +244,9 @@ func serveRaw(w http.ResponseWriter, r *http.Request) error {
 			}
 			var names []string
 			for _, info := range infos {
+				if !subRepoPathAllowed(perms, path.Join(requestedPath, info.Name()), info) {
+					continue
+				}
 				name := info.Name()
 				if info.IsDir() {
 					name = name + "/"
@@ -240,3 +268,12 @@ func serveRaw(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 }
+
+// subRepoPathAllowed returns true if the file or directory described by fi at the given path may
+// be read according to the path rules in perms.
+func subRepoPathAllowed(perms *authz.SubRepoPerms, name string, fi os.FileInfo) bool {
+	if fi.IsDir() {
+		return perms.AllowedDir(name)
+	}
+	return perms.Allowed(name)
+}
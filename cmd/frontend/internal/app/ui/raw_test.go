@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitservertest"
+	"github.com/sourcegraph/sourcegraph/internal/vfsutil"
+)
+
+func TestServeRaw_SubRepoPerms(t *testing.T) {
+	gitserver := gitservertest.Start(t)
+	defer gitserver.Close()
+	head := gitserver.MakeRepository(t, "example.com/restricted",
+		"mkdir pub secret",
+		"echo a > pub/a.txt",
+		"echo s3cr3t > secret/key.txt",
+		"git add . && git commit -qm initial",
+	)
+
+	cacheDir, err := ioutil.TempDir("", "raw_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	defer func(orig string) { vfsutil.ArchiveCacheDir = orig }(vfsutil.ArchiveCacheDir)
+	vfsutil.ArchiveCacheDir = cacheDir
+
+	backend.Mocks.Repos.MockGetByName(t, "example.com/restricted", 1)
+	backend.Mocks.Repos.Get = func(context.Context, api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: 1, Name: "example.com/restricted"}, nil
+	}
+	backend.Mocks.Repos.ResolveRev = func(context.Context, *types.Repo, string) (api.CommitID, error) {
+		return head, nil
+	}
+	backend.Mocks.SubRepoPerms = func(context.Context, *types.Repo) (*authz.SubRepoPerms, error) {
+		return &authz.SubRepoPerms{Rules: []authz.PathRule{{Prefix: "secret", Allow: false}}}, nil
+	}
+	defer func() { backend.Mocks = backend.MockServices{} }()
+
+	serve := func(path, format string) *httptest.ResponseRecorder {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/?format="+format, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"Repo": "example.com/restricted", "Path": path})
+		w := httptest.NewRecorder()
+		if err := serveRaw(w, req); err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+
+	t.Run("directory listing", func(t *testing.T) {
+		if w := serve("/", ""); w.Code != http.StatusOK || w.Body.String() != "pub/" {
+			t.Errorf("got %d %q, want 200 with only pub/", w.Code, w.Body.String())
+		}
+	})
+
+	for _, path := range []string{"/secret", "/secret/key.txt"} {
+		t.Run("denied "+path, func(t *testing.T) {
+			if w := serve(path, ""); w.Code != http.StatusNotFound || strings.Contains(w.Body.String(), "s3cr3t") {
+				t.Errorf("got %d %q, want 404", w.Code, w.Body.String())
+			}
+			if w := serve(path, "zip"); w.Code != http.StatusNotFound || strings.Contains(w.Body.String(), "s3cr3t") {
+				t.Errorf("archive: got %d %q, want 404", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("archive of the repository", func(t *testing.T) {
+		if w := serve("/", "zip"); w.Code != http.StatusForbidden {
+			t.Errorf("got %d, want 403", w.Code)
+		}
+	})
+
+	t.Run("archive of a readable directory", func(t *testing.T) {
+		w := serve("/pub", "zip")
+		if w.Code != http.StatusOK {
+			t.Fatalf("got %d %q, want 200", w.Code, w.Body.String())
+		}
+		zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range zr.File {
+			if !strings.HasPrefix(f.Name, "pub/") {
+				t.Errorf("archive contains %q", f.Name)
+			}
+		}
+	})
+}
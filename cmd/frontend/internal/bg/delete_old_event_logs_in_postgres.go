@@ -4,18 +4,42 @@ import (
 	"context"
 	"time"
 
-	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
+// defaultEventLogsRetentionDays is the number of days that events are kept if the site
+// configuration doesn't specify eventLogs.retentionDays.
+const defaultEventLogsRetentionDays = 93
+
+// deleteOldEventLogsBatchSize is the maximum number of events deleted by a single statement, so
+// that deleting a large backlog of events doesn't hold locks for long.
+const deleteOldEventLogsBatchSize = 10000
+
+// DeleteOldEventLogsInPostgres periodically deletes the events that are older than the retention
+// period. Only events that have been rolled up are deleted, so deleting them doesn't affect the
+// usage statistics. It is paused while maintenance mode is enabled.
 func DeleteOldEventLogsInPostgres(ctx context.Context) {
 	for {
-		_, err := dbconn.Global.ExecContext(
-			ctx,
-			`DELETE FROM event_logs WHERE "timestamp" < now() - interval '93' day`,
-		)
-		if err != nil {
-			log15.Error("deleting expired rows from event_logs table", "error", err)
+		if conf.MaintenanceModeEnabled() {
+			time.Sleep(time.Hour)
+			continue
+		}
+		days := conf.Get().EventLogsRetentionDays
+		if days == 0 {
+			days = defaultEventLogsRetentionDays
+		}
+		before := time.Now().AddDate(0, 0, -days)
+		for {
+			n, err := db.EventLogs.DeleteRolledUp(ctx, before, deleteOldEventLogsBatchSize)
+			if err != nil {
+				log15.Error("deleting expired rows from event_logs table", "error", err)
+				break
+			}
+			if n < deleteOldEventLogsBatchSize {
+				break
+			}
 		}
 		time.Sleep(time.Hour)
 	}
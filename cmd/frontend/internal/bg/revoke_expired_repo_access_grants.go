@@ -0,0 +1,29 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// RevokeExpiredRepoAccessGrants periodically deletes the repository access grants (of approved
+// repository access requests) that have expired. Expired grants are never enforced, so this only
+// keeps the table small. It is paused while maintenance mode is enabled.
+func RevokeExpiredRepoAccessGrants(ctx context.Context) {
+	for {
+		if conf.MaintenanceModeEnabled() {
+			time.Sleep(10 * time.Minute)
+			continue
+		}
+		n, err := db.RepoAccessGrants.DeleteExpired(ctx)
+		if err != nil {
+			log15.Error("deleting expired rows from repo_access_grants table", "error", err)
+		} else if n > 0 {
+			log15.Info("Revoked expired repository access grants.", "count", n)
+		}
+		time.Sleep(10 * time.Minute)
+	}
+}
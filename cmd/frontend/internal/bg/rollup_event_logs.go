@@ -0,0 +1,28 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// RollupEventLogs periodically aggregates new events into the rollup tables that the usage
+// statistics are computed from. It is paused while maintenance mode is enabled.
+func RollupEventLogs(ctx context.Context) {
+	for {
+		if conf.MaintenanceModeEnabled() {
+			time.Sleep(15 * time.Minute)
+			continue
+		}
+		n, err := db.EventLogs.Rollup(ctx)
+		if err != nil {
+			log15.Error("rolling up event_logs", "error", err)
+		} else if n > 0 {
+			log15.Debug("Rolled up event logs.", "count", n)
+		}
+		time.Sleep(15 * time.Minute)
+	}
+}
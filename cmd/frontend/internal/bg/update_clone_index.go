@@ -0,0 +1,49 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// UpdateCloneIndex keeps the index behind the codeCloneClusters and duplicates GraphQL fields
+// current. Every 10 minutes, it indexes the files that changed on the default branch of each
+// repository since the last run. Forks aren't indexed, so that a fork isn't reported as a clone of
+// every file of its parent. Nothing is indexed unless the "codeCloneDetection" experimental feature
+// is enabled, or while maintenance mode is enabled.
+//
+// The run holds a Redis mutex, so that frontend replicas don't index the same files concurrently.
+func UpdateCloneIndex(ctx context.Context) {
+	for {
+		if backend.CodeCloneDetectionEnabled() && !conf.MaintenanceModeEnabled() {
+			if ctx, release, ok := rcache.TryAcquireMutex(ctx, "updateCloneIndex"); ok {
+				updateCloneIndex(ctx)
+				release()
+			}
+		}
+		time.Sleep(10 * time.Minute)
+	}
+}
+
+func updateCloneIndex(ctx context.Context) {
+	// 🚨 SECURITY: All repositories are indexed. The permissions of the actor are checked when
+	// clones are looked up.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	repos, err := db.Repos.List(ctx, db.ReposListOptions{NoForks: true})
+	if err != nil {
+		log15.Error("listing repositories for code clone index", "error", err)
+		return
+	}
+	for _, repo := range repos {
+		if err := backend.UpdateCloneIndex(ctx, repo); err != nil {
+			log15.Error("updating code clone index of repository", "repo", repo.Name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// UpdateCodeExpertise adds the authored lines of new commits to the index that the experts GraphQL
+// fields rank people by. Every 10 minutes, it indexes the commits on the default branch of each
+// repository since the last run, and drops the commits that have become too old to be scored.
+// Forks are skipped, because their history duplicates their parent's. It is idle unless the
+// "codeExpertise" experimental feature is enabled, and while maintenance mode is enabled.
+//
+// Indexing the same commit twice would double its lines, so a Redis mutex ensures that only one
+// frontend replica runs it at a time.
+func UpdateCodeExpertise(ctx context.Context) {
+	for {
+		if backend.CodeExpertiseEnabled() && !conf.MaintenanceModeEnabled() {
+			if ctx, release, ok := rcache.TryAcquireMutex(ctx, "updateCodeExpertise"); ok {
+				updateCodeExpertise(ctx)
+				release()
+			}
+		}
+		time.Sleep(10 * time.Minute)
+	}
+}
+
+func updateCodeExpertise(ctx context.Context) {
+	// 🚨 SECURITY: All repositories are indexed. The permissions of the actor are checked when
+	// experts are looked up.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	repos, err := db.Repos.List(ctx, db.ReposListOptions{NoForks: true})
+	if err != nil {
+		log15.Error("listing repositories for code expertise index", "error", err)
+		return
+	}
+	for _, repo := range repos {
+		if err := backend.UpdateCodeExpertise(ctx, repo); err != nil {
+			log15.Error("updating code expertise index of repository", "repo", repo.Name, "error", err)
+		}
+	}
+}
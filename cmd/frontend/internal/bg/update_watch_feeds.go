@@ -0,0 +1,64 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	watchDigestInterval     = 24 * time.Hour
+	watchFeedEntryRetention = 90 * 24 * time.Hour
+)
+
+// UpdateWatchFeeds periodically adds the new commits of watched repositories (as gitserver fetches
+// them) to the activity feeds of their watches, sends the daily email digests of the feeds, and
+// deletes old feed entries. It is paused while maintenance mode is enabled.
+//
+// Only one frontend process does this at a time, so that digests aren't sent twice.
+func UpdateWatchFeeds(ctx context.Context) {
+	for {
+		if !conf.MaintenanceModeEnabled() {
+			if ctx, release, ok := rcache.TryAcquireMutex(ctx, "updateWatchFeeds"); ok {
+				updateWatchFeeds(ctx)
+				release()
+			}
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
+func updateWatchFeeds(ctx context.Context) {
+	repoIDs, err := db.Watches.ListWatchedRepoIDs(ctx)
+	if err != nil {
+		log15.Error("listing watched repositories", "error", err)
+		return
+	}
+	for _, id := range repoIDs {
+		if err := backend.UpdateWatchFeed(ctx, id); err != nil {
+			log15.Error("updating activity feed of watched repository", "repo", id, "error", err)
+		}
+	}
+
+	userIDs, err := db.Watches.ListDigestUserIDs(ctx, time.Now().Add(-watchDigestInterval))
+	if err != nil {
+		log15.Error("listing users with activity feed email digests", "error", err)
+		return
+	}
+	for _, id := range userIDs {
+		if err := backend.SendWatchDigest(ctx, id); err != nil {
+			log15.Error("sending activity feed email digest", "user", id, "error", err)
+		}
+	}
+
+	if n, err := db.Watches.DeleteFeedEntriesOlderThan(ctx, time.Now().Add(-watchFeedEntryRetention)); err != nil {
+		log15.Error("deleting old rows from watch_feed_entries table", "error", err)
+	} else if n > 0 {
+		log15.Info("Deleted old activity feed entries.", "count", n)
+	}
+}
@@ -197,6 +197,11 @@ func Main(githubWebhook, bitbucketServerWebhook http.Handler) error {
 	goroutine.Go(func() { bg.CheckRedisCacheEvictionPolicy() })
 	goroutine.Go(func() { bg.DeleteOldCacheDataInRedis() })
 	goroutine.Go(func() { bg.DeleteOldEventLogsInPostgres(context.Background()) })
+	goroutine.Go(func() { bg.RollupEventLogs(context.Background()) })
+	goroutine.Go(func() { bg.RevokeExpiredRepoAccessGrants(context.Background()) })
+	goroutine.Go(func() { bg.UpdateWatchFeeds(context.Background()) })
+	goroutine.Go(func() { bg.UpdateCloneIndex(context.Background()) })
+	goroutine.Go(func() { bg.UpdateCodeExpertise(context.Background()) })
 	goroutine.Go(mailreply.StartWorker)
 	go updatecheck.Start()
 
@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/coverage"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// maxCoverageReportBytes is the maximum size of an uploaded (uncompressed) coverage report. It is a
+// variable so that tests can lower it.
+var maxCoverageReportBytes int64 = 256 * 1024 * 1024
+
+// serveCoverageUpload handles uploads of code coverage reports for a commit of a repository. The
+// query parameters are:
+//
+//   - repository: the repository name (required)
+//   - commit: the commit (required)
+//   - format: the report format, one of "lcov", "cobertura", and "go" (required)
+//   - root: the directory (relative to the repository root) that the report's paths are relative to
+//   - stripPrefix: a prefix to remove from the report's paths (defaults to the repository name for
+//     Go coverage profiles, whose paths are import paths)
+//
+// The report may be gzip-compressed (with the header Content-Encoding: gzip).
+//
+// 🚨 SECURITY: Like LSIF uploads, this endpoint is accessible to anonymous users, and uploads are
+// authenticated with a code host token when the site configuration sets lsifEnforceAuth.
+func serveCoverageUpload(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	repoName := q.Get("repository")
+	format := coverage.Format(q.Get("format"))
+	ctx := r.Context()
+
+	if q.Get("commit") == "" {
+		http.Error(w, "must provide commit", http.StatusBadRequest)
+		return
+	}
+	repo, err := backend.Repos.GetByName(ctx, api.RepoName(repoName))
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("unknown repository %q", repoName), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	commitID, err := backend.Repos.ResolveRev(ctx, repo, q.Get("commit"))
+	if err != nil {
+		if gitserver.IsRevisionNotFound(err) {
+			http.Error(w, fmt.Sprintf("unknown commit %q", q.Get("commit")), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 🚨 SECURITY: The uploader must prove write access to the repository before the report is
+	// stored.
+	if conf.Get().LsifEnforceAuth {
+		if httpapi.EnforceUploadAuth == nil {
+			http.Error(w, "upload verification is only available in enterprise", http.StatusUnprocessableEntity)
+			return
+		}
+		if !httpapi.EnforceUploadAuth(ctx, w, r, repoName) {
+			return
+		}
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = gzipReader
+	}
+	// Read one byte more than the limit, so that a report that exceeds it is rejected instead of
+	// being silently truncated.
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxCoverageReportBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) > maxCoverageReportBytes {
+		http.Error(w, fmt.Sprintf("the coverage report is larger than %d bytes (uncompressed)", maxCoverageReportBytes), http.StatusRequestEntityTooLarge)
+		return
+	}
+	files, err := coverage.Parse(format, bytes.NewReader(data))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stripPrefix := q.Get("stripPrefix")
+	if stripPrefix == "" && format == coverage.GoProfile {
+		stripPrefix = repoName
+	}
+	files = coverage.NormalizePaths(files, q.Get("root"), stripPrefix)
+	if len(files) == 0 {
+		http.Error(w, "the coverage report has no files in the repository (check the root and stripPrefix parameters)", http.StatusBadRequest)
+		return
+	}
+
+	report, err := db.CoverageReports.Upload(ctx, repo.ID, commitID, format, files)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"commit":     commitID,
+		"files":      len(files),
+		"linesFound": report.LinesFound,
+		"linesHit":   report.LinesHit,
+	})
+}
@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/coverage"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestServeCoverageUpload(t *testing.T) {
+	defer func() { backend.Mocks = backend.MockServices{}; db.Mocks = db.MockStores{} }()
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		return &types.Repo{ID: 1, Name: name}, nil
+	}
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return "0123456789012345678901234567890123456789", nil
+	}
+	var uploaded []*coverage.File
+	db.Mocks.CoverageReports.Upload = func(ctx context.Context, repoID api.RepoID, commitID api.CommitID, format coverage.Format, files []*coverage.File) (*db.CoverageReport, error) {
+		uploaded = files
+		return &db.CoverageReport{ID: 1, RepoID: repoID, CommitID: commitID, LinesFound: 2, LinesHit: 1}, nil
+	}
+
+	upload := func(query, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		serveCoverageUpload(rec, httptest.NewRequest("POST", "/.api/coverage/upload?"+query, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("go", func(t *testing.T) {
+		rec := upload("repository=github.com/acme/a&commit=master&format=go", "mode: set\ngithub.com/acme/a/b/c.go:3.1,3.9 1 1\ngithub.com/acme/a/b/c.go:4.1,4.9 1 0\n")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d (%s), want 200", rec.Code, rec.Body)
+		}
+		want := []*coverage.File{{Path: "b/c.go", Lines: []coverage.Line{{Number: 3, Hits: 1}, {Number: 4, Hits: 0}}}}
+		if !reflect.DeepEqual(uploaded, want) {
+			t.Errorf("got uploaded files %+v, want %+v", uploaded, want)
+		}
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		if rec := upload("repository=github.com/acme/a&commit=master&format=lcov", "DA:1,1\n"); rec.Code != http.StatusBadRequest {
+			t.Errorf("got status %d, want 400", rec.Code)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		defer func(orig int64) { maxCoverageReportBytes = orig }(maxCoverageReportBytes)
+		maxCoverageReportBytes = 64
+		report := "SF:a.ts\n" + strings.Repeat("DA:1,1\n", 10) + "end_of_record\n"
+
+		if rec := upload("repository=github.com/acme/a&commit=master&format=lcov", report); rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("got status %d, want 413", rec.Code)
+		}
+
+		// A compressed report must not be truncated to the limit.
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write([]byte(report)); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("POST", "/.api/coverage/upload?repository=github.com/acme/a&commit=master&format=lcov", &buf)
+		req.Header.Set("Content-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		serveCoverageUpload(rec, req)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("gzip: got status %d, want 413", rec.Code)
+		}
+	})
+
+	t.Run("enforce auth", func(t *testing.T) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{LsifEnforceAuth: true}})
+		defer conf.Mock(nil)
+		defer func() { httpapi.EnforceUploadAuth = nil }()
+		httpapi.EnforceUploadAuth = func(ctx context.Context, w http.ResponseWriter, r *http.Request, repoName string) bool {
+			http.Error(w, "must provide github_token", http.StatusUnauthorized)
+			return false
+		}
+		uploaded = nil
+		if rec := upload("repository=github.com/acme/a&commit=master&format=lcov", "SF:a.ts\nDA:1,1\nend_of_record\n"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("got status %d, want 401", rec.Code)
+		}
+		if uploaded != nil {
+			t.Error("the report must not be stored")
+		}
+	})
+}
@@ -0,0 +1,443 @@
+package httpapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gosrc"
+	"github.com/sourcegraph/sourcegraph/internal/httputil"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	modzip "golang.org/x/mod/zip"
+)
+
+// goModuleCache caches the info and go.mod files of module versions, and their zips if they are
+// small. The keys contain the commit of the version, so entries never become stale, but they expire
+// to bound the size of the cache.
+var goModuleCache = rcache.NewWithTTL("go_module_proxy:v1", 7*24*60*60)
+
+// maxCachedGoModuleZipSize is the size of the largest module zips that are cached.
+const maxCachedGoModuleZipSize = 10 << 20
+
+func goModuleProxyEnabled() bool {
+	c := conf.Get()
+	return c.ExperimentalFeatures != nil && c.ExperimentalFeatures.GoModuleProxy == "enabled"
+}
+
+// A goModule is a Go module in a repository.
+type goModule struct {
+	path      string // the module path
+	repo      *types.Repo
+	gitRepo   gitserver.Repo
+	dir       string // the module's directory in the repository, without the major version suffix
+	pathMajor string // the major version suffix of the module path (such as "/v2"), if any
+}
+
+// serveGoProxy implements the Go module proxy protocol
+// (https://golang.org/cmd/go/#hdr-Module_proxy_protocol) for the Go modules in repositories. The
+// versions of a module are the semantic version tags of its repository (prefixed with the module's
+// directory, for modules in subdirectories).
+//
+// 🚨 SECURITY: The actor must be able to view the module's repository and everything in the
+// module's directory.
+func serveGoProxy(w http.ResponseWriter, r *http.Request) error {
+	if !goModuleProxyEnabled() {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.New("the Go module proxy is disabled")}
+	}
+
+	modPath, err := module.UnescapePath(mux.Vars(r)["Module"])
+	if err != nil {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: err}
+	}
+	m, err := resolveGoModule(r.Context(), modPath)
+	if err != nil {
+		return err
+	}
+
+	file := mux.Vars(r)["File"]
+	if file == "list" {
+		tags, err := git.ListTags(r.Context(), m.gitRepo)
+		if err != nil {
+			return err
+		}
+		names := make([]string, len(tags))
+		for i, t := range tags {
+			names[i] = t.Name
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, v := range goModuleVersions(names, m.tagPrefix(), m.pathMajor) {
+			fmt.Fprintln(w, v)
+		}
+		return nil
+	}
+
+	ext := path.Ext(file)
+	version, err := module.UnescapeVersion(strings.TrimSuffix(file, ext))
+	if err != nil || !isGoModuleTagVersion(version, m.pathMajor) {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: fmt.Errorf("invalid version %q of module %s (only tagged semantic versions are available)", file, modPath)}
+	}
+	commitID, err := backend.Repos.ResolveRev(r.Context(), m.repo, "refs/tags/"+m.tagPrefix()+version)
+	if err != nil {
+		return err
+	}
+
+	return serveGoModuleFile(r.Context(), w, m, file, version, commitID)
+}
+
+// goModuleFileCacheControl is the Cache-Control header of the .info, .mod and .zip files of module
+// versions, which are immutable (the go command checks that their contents never change). It is
+// only set on successful responses, so that errors aren't cached.
+const goModuleFileCacheControl = "private, max-age=31536000, immutable"
+
+// serveGoModuleFile serves the .info, .mod or .zip file of a version of the module, which is the
+// given commit.
+func serveGoModuleFile(ctx context.Context, w http.ResponseWriter, m *goModule, file, version string, commitID api.CommitID) error {
+	switch path.Ext(file) {
+	case ".info":
+		key := fmt.Sprintf("info:%s:%s@%s", m.repo.Name, commitID, version)
+		data, ok := goModuleCache.Get(key)
+		if !ok {
+			commit, err := backend.Repos.GetCommit(ctx, m.repo, commitID)
+			if err != nil {
+				return err
+			}
+			t := commit.Author.Date
+			if commit.Committer != nil {
+				t = commit.Committer.Date
+			}
+			data, err = json.Marshal(&struct {
+				Version string
+				Time    time.Time
+			}{Version: version, Time: t.UTC()})
+			if err != nil {
+				return err
+			}
+			goModuleCache.Set(key, data)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", goModuleFileCacheControl)
+		_, err := w.Write(data)
+		return err
+
+	case ".mod":
+		key := fmt.Sprintf("mod:%s:%s:%s", m.repo.Name, commitID, m.path)
+		data, ok := goModuleCache.Get(key)
+		if !ok {
+			var err error
+			_, data, err = m.goMod(ctx, commitID)
+			if err != nil {
+				return err
+			}
+			goModuleCache.Set(key, data)
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", goModuleFileCacheControl)
+		_, err := w.Write(data)
+		return err
+
+	case ".zip":
+		key := fmt.Sprintf("zip:%s:%s:%s@%s", m.repo.Name, commitID, m.path, version)
+		if data, ok := goModuleCache.Get(key); ok {
+			w.Header().Set("Content-Type", "application/zip")
+			w.Header().Set("Cache-Control", goModuleFileCacheControl)
+			_, err := w.Write(data)
+			return err
+		}
+
+		// Module zips can be up to 500 MB, so they are written to a temporary file instead of
+		// memory. Only small ones are cached.
+		f, err := ioutil.TempFile("", "go-module-*.zip")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+		if err := m.writeZip(ctx, commitID, version, f); err != nil {
+			return err
+		}
+		size, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if size <= maxCachedGoModuleZipSize {
+			data, err := ioutil.ReadAll(f)
+			if err != nil {
+				return err
+			}
+			goModuleCache.Set(key, data)
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		w.Header().Set("Cache-Control", goModuleFileCacheControl)
+		_, err = io.Copy(w, f)
+		return err
+	}
+	return &errcode.HTTPErr{Status: http.StatusNotFound, Err: fmt.Errorf("unknown file %q", file)}
+}
+
+// resolveGoModule finds the repository of the module with the given path, using the go-import
+// meta tags of vanity import paths like the go command does.
+func resolveGoModule(ctx context.Context, modPath string) (*goModule, error) {
+	notFound := func(err error) error {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.Wrapf(err, "module %s", modPath)}
+	}
+
+	if err := module.CheckPath(modPath); err != nil {
+		return nil, notFound(err)
+	}
+	pathPrefix, pathMajor, ok := module.SplitPathVersion(modPath)
+	if !ok || strings.HasPrefix(pathMajor, ".") {
+		return nil, notFound(errors.New("gopkg.in modules are not supported"))
+	}
+
+	dir, err := gosrc.ResolveImportPath(httputil.CachingClient, modPath)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if dir.VCS != "git" || dir.ProjectRoot == "" {
+		return nil, notFound(errors.New("not in a Git repository"))
+	}
+	repoName, err := graphqlbackend.CloneURLToRepoName(ctx, dir.CloneURL)
+	if err != nil {
+		return nil, err
+	}
+	if repoName == "" {
+		return nil, notFound(fmt.Errorf("no repository matches clone URL %s", dir.CloneURL))
+	}
+	repo, err := backend.Repos.GetByName(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &goModule{path: modPath, repo: repo, pathMajor: pathMajor}
+	switch {
+	case pathPrefix == dir.ProjectRoot || modPath == dir.ProjectRoot:
+		// The module is in the repository root.
+	case strings.HasPrefix(pathPrefix, dir.ProjectRoot+"/"):
+		m.dir = strings.TrimPrefix(pathPrefix, dir.ProjectRoot+"/")
+	default:
+		return nil, notFound(fmt.Errorf("not in the repository of %s", dir.ProjectRoot))
+	}
+
+	// 🚨 SECURITY: Module zips contain all files in the module's directory.
+	perms, err := backend.SubRepoPerms(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !perms.AllowedTree(m.dir) {
+		return nil, notFound(errors.New("not found"))
+	}
+
+	gitRepo, err := backend.CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	m.gitRepo = *gitRepo
+	return m, nil
+}
+
+// tagPrefix returns the prefix of the names of the module's version tags.
+func (m *goModule) tagPrefix() string {
+	if m.dir == "" {
+		return ""
+	}
+	return m.dir + "/"
+}
+
+// goModuleVersions returns the versions of a module listed by the given tag names, in semantic
+// version order. Only canonical semantic versions that match the major version suffix of the
+// module path are versions of the module (so +incompatible versions aren't listed).
+func goModuleVersions(tags []string, tagPrefix, pathMajor string) []string {
+	seen := map[string]bool{}
+	var versions []string
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, tagPrefix) {
+			continue
+		}
+		v := strings.TrimPrefix(tag, tagPrefix)
+		if !isGoModuleTagVersion(v, pathMajor) || seen[v] {
+			continue
+		}
+		seen[v] = true
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return semver.Compare(versions[i], versions[j]) < 0 })
+	return versions
+}
+
+// isGoModuleTagVersion reports whether the version can be the version tag of a module with the
+// major version suffix.
+func isGoModuleTagVersion(v, pathMajor string) bool {
+	return module.CanonicalVersion(v) == v && semver.Build(v) == "" && module.MatchPathMajor(v, pathMajor)
+}
+
+// goMod returns the directory of the module at the commit and its go.mod file, following the
+// rules of the go command: a module with a major version suffix is either in the subdirectory
+// named after its major version (such as "v2"), or in the module's directory on a major version
+// branch. A go.mod file is synthesized for modules without one (which can only be v0 or v1).
+func (m *goModule) goMod(ctx context.Context, commitID api.CommitID) (dir string, data []byte, err error) {
+	var dirs []string
+	if m.pathMajor != "" {
+		dirs = append(dirs, path.Join(m.dir, m.pathMajor[1:]))
+	}
+	dirs = append(dirs, m.dir)
+	var haveGoMod bool
+	for _, dir := range dirs {
+		data, err := git.ReadFile(ctx, m.gitRepo, commitID, path.Join(dir, "go.mod"), modzip.MaxGoMod)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", nil, err
+		}
+		haveGoMod = true
+		if modfile.ModulePath(data) == m.path {
+			return dir, data, nil
+		}
+	}
+	if haveGoMod || m.pathMajor != "" {
+		return "", nil, &errcode.HTTPErr{Status: http.StatusNotFound, Err: fmt.Errorf("no go.mod file declares module %s", m.path)}
+	}
+	return m.dir, []byte(fmt.Sprintf("module %s\n", modfile.AutoQuote(m.path))), nil
+}
+
+// writeZip writes the module zip of the version of the module at the commit, built from a Git
+// archive of the module's directory. Like the go command, it adds the LICENSE file of the
+// repository root to modules in subdirectories that don't have one.
+func (m *goModule) writeZip(ctx context.Context, commitID api.CommitID, version string, w io.Writer) error {
+	dir, _, err := m.goMod(ctx, commitID)
+	if err != nil {
+		return err
+	}
+	opt := gitserver.ArchiveOptions{Treeish: string(commitID), Format: "zip"}
+	if dir != "" {
+		opt.Paths = []string{dir}
+	}
+	rc, err := gitserver.DefaultClient.Archive(ctx, m.gitRepo, opt)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// The archive is read from a temporary file, because reading a zip file needs random access.
+	f, err := ioutil.TempFile("", "go-module-archive-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	size, err := io.Copy(f, io.LimitReader(rc, modzip.MaxZipFile+1))
+	if err != nil {
+		return err
+	}
+	if size > modzip.MaxZipFile {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: fmt.Errorf("module source tree too large (max size is %d bytes)", modzip.MaxZipFile)}
+	}
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return err
+	}
+
+	files := goModuleZipFiles(zr.File, dir)
+	if dir != "" && !hasGoModuleFile(files, "LICENSE") {
+		// 🚨 SECURITY: The actor may not be allowed to read the LICENSE file of the root.
+		perms, err := backend.SubRepoPerms(ctx, m.repo)
+		if err != nil {
+			return err
+		}
+		if perms.Allowed("LICENSE") {
+			data, err := git.ReadFile(ctx, m.gitRepo, commitID, "LICENSE", modzip.MaxLICENSE+1)
+			if err == nil {
+				files = append(files, &goModuleMemFile{path: "LICENSE", data: data})
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	if err := modzip.Create(w, module.Version{Path: m.path, Version: version}, files); err != nil {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: err}
+	}
+	return nil
+}
+
+// goModuleZipFiles returns the files of a Git archive that are in the directory, with paths
+// relative to it. modzip.Create excludes the files that don't belong in a module zip (such as
+// nested modules, vendored packages and symbolic links).
+func goModuleZipFiles(archive []*zip.File, dir string) []modzip.File {
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	var files []modzip.File
+	for _, f := range archive {
+		if !strings.HasPrefix(f.Name, prefix) || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		files = append(files, goModuleArchiveFile{path: strings.TrimPrefix(f.Name, prefix), f: f})
+	}
+	return files
+}
+
+func hasGoModuleFile(files []modzip.File, name string) bool {
+	for _, f := range files {
+		if f.Path() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// goModuleArchiveFile is a file of a Git archive in a module zip.
+type goModuleArchiveFile struct {
+	path string
+	f    *zip.File
+}
+
+func (f goModuleArchiveFile) Path() string                 { return f.path }
+func (f goModuleArchiveFile) Lstat() (os.FileInfo, error)  { return f.f.FileInfo(), nil }
+func (f goModuleArchiveFile) Open() (io.ReadCloser, error) { return f.f.Open() }
+
+// goModuleMemFile is a file in memory in a module zip.
+type goModuleMemFile struct {
+	path string
+	data []byte
+}
+
+func (f *goModuleMemFile) Path() string { return f.path }
+func (f *goModuleMemFile) Lstat() (os.FileInfo, error) {
+	return &util.FileInfo{Name_: path.Base(f.path), Mode_: 0644, Size_: int64(len(f.data))}, nil
+}
+func (f *goModuleMemFile) Open() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(f.data)), nil
+}
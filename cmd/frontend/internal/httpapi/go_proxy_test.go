@@ -0,0 +1,133 @@
+package httpapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"golang.org/x/mod/module"
+	modzip "golang.org/x/mod/zip"
+)
+
+func TestGoModuleVersions(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.10.0", "v1.2.0", "v1.2", "v2.0.0", "v3.0.0+incompatible", "sub/v1.1.0", "latest", "v1.2.0-rc.1"}
+	tests := []struct {
+		tagPrefix, pathMajor string
+		want                 []string
+	}{
+		{"", "", []string{"v1.0.0", "v1.2.0-rc.1", "v1.2.0", "v1.10.0"}},
+		{"", "/v2", []string{"v2.0.0"}},
+		{"sub/", "", []string{"v1.1.0"}},
+		{"other/", "", nil},
+	}
+	for _, test := range tests {
+		if got := goModuleVersions(tags, test.tagPrefix, test.pathMajor); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q %q: got %q, want %q", test.tagPrefix, test.pathMajor, got, test.want)
+		}
+	}
+}
+
+func TestGoModuleZipFiles(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, name := range []string{"go.mod", "root.go", "sub/", "sub/go.mod", "sub/a.go", "sub/vendor/x/x.go", "sub/nested/go.mod", "sub/nested/b.go"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		if _, err := w.Write([]byte("package x\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := goModuleZipFiles(zr.File, "sub")
+	files = append(files, &goModuleMemFile{path: "LICENSE", data: []byte("MIT")})
+	var modZip bytes.Buffer
+	if err := modzip.Create(&modZip, module.Version{Path: "example.com/m/sub", Version: "v1.0.0"}, files); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err = zip.NewReader(bytes.NewReader(modZip.Bytes()), int64(modZip.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range zr.File {
+		got = append(got, f.Name)
+	}
+	sort.Strings(got)
+	want := []string{"example.com/m/sub@v1.0.0/LICENSE", "example.com/m/sub@v1.0.0/a.go", "example.com/m/sub@v1.0.0/go.mod"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got files %q, want %q", got, want)
+	}
+}
+
+func TestServeGoProxy_disabled(t *testing.T) {
+	conf.Mock(&conf.Unified{})
+	defer conf.Mock(nil)
+	c := newTest()
+
+	req, _ := http.NewRequest("GET", "/go-proxy/github.com/gorilla/mux/@v/list", nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestServeGoModuleFile_cacheControl(t *testing.T) {
+	defer func() { backend.Mocks = backend.MockServices{} }()
+	backend.Mocks.Repos.GetCommit = func(ctx context.Context, repo *types.Repo, commitID api.CommitID) (*git.Commit, error) {
+		if commitID == "badbadbadbadbadbadbadbadbadbadbadbadbadb" {
+			return nil, errors.New("commit not found")
+		}
+		return &git.Commit{ID: commitID, Author: git.Signature{Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}}, nil
+	}
+	m := &goModule{path: "github.com/gorilla/mux", repo: &types.Repo{Name: "github.com/gorilla/mux"}}
+
+	t.Run("ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := serveGoModuleFile(context.Background(), rec, m, "v1.0.0.info", "v1.0.0", "0123456789012345678901234567890123456789"); err != nil {
+			t.Fatal(err)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != goModuleFileCacheControl {
+			t.Errorf("got Cache-Control %q, want %q", got, goModuleFileCacheControl)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := serveGoModuleFile(context.Background(), rec, m, "v1.0.0.info", "v1.0.0", "badbadbadbadbadbadbadbadbadbadbadbadbadb"); err == nil {
+			t.Fatal("got nil error, want an error")
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "" {
+			t.Errorf("got Cache-Control %q on an error response, want none", got)
+		}
+	})
+}
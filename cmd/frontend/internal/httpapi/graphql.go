@@ -1,16 +1,18 @@
 package httpapi
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
 	"github.com/graph-gophers/graphql-go"
-	"github.com/graph-gophers/graphql-go/relay"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
 func serveGraphQL(schema *graphql.Schema) func(w http.ResponseWriter, r *http.Request) (err error) {
-	relayHandler := &relay.Handler{Schema: schema}
 	return func(w http.ResponseWriter, r *http.Request) (err error) {
 		if r.Method != "POST" {
 			// The URL router should not have routed to this handler if method is not POST, but just in
@@ -26,7 +28,24 @@ func serveGraphQL(schema *graphql.Schema) func(w http.ResponseWriter, r *http.Re
 		}
 		r = r.WithContext(trace.WithGraphQLRequestName(r.Context(), requestName))
 
-		relayHandler.ServeHTTP(w, r)
-		return nil
+		var params struct {
+			Query         string                 `json:"query"`
+			OperationName string                 `json:"operationName"`
+			Variables     map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+		}
+
+		// Reject mutations while the site is read-only, with an error that clients display like
+		// any other GraphQL error.
+		var response *graphql.Response
+		if err := graphqlbackend.CheckMaintenanceMode(params.Query, params.OperationName); err != nil {
+			response = &graphql.Response{Errors: []*gqlerrors.QueryError{{Message: err.Error()}}}
+		} else {
+			response = schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(response)
 	}
 }
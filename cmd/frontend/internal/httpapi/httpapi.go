@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/pkg/updatecheck"
 	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/slackapp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -46,16 +47,20 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 	// Set handlers for the installed routes.
 	m.Get(apirouter.RepoShield).Handler(trace.TraceRoute(handler(serveRepoShield)))
 
-	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(handler(serveRepoRefresh)))
+	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(handler(serveRepoRefresh))))
 
 	if githubWebhook != nil {
-		m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(githubWebhook))
+		m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(githubWebhook)))
 	}
 
 	if bitbucketServerWebhook != nil {
-		m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.TraceRoute(bitbucketServerWebhook))
+		m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(bitbucketServerWebhook)))
 	}
 
+	slackApp := slackapp.NewHandler(schema)
+	m.Get(apirouter.SlackCommands).Handler(trace.TraceRoute(handler(slackApp.ServeCommand)))
+	m.Get(apirouter.SlackEvents).Handler(trace.TraceRoute(handler(slackApp.ServeEvents)))
+
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.TraceRoute(http.HandlerFunc(updatecheck.Handler)))
 	}
@@ -63,7 +68,7 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(handler(serveGraphQL(schema))))
 
 	if lsifServerProxy != nil {
-		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(lsifServerProxy.UploadHandler))
+		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(lsifServerProxy.UploadHandler)))
 	} else {
 		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
@@ -71,12 +76,16 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 		})))
 	}
 
+	m.Get(apirouter.CoverageUpload).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(http.HandlerFunc(serveCoverageUpload))))
+
 	// Return the minimum src-cli version that's compatible with this instance
 	m.Get(apirouter.SrcCliVersion).Handler(trace.TraceRoute(handler(srcCliVersionServe)))
 	m.Get(apirouter.SrcCliDownload).Handler(trace.TraceRoute(handler(srcCliDownloadServe)))
 
 	m.Get(apirouter.Registry).Handler(trace.TraceRoute(handler(registry.HandleRegistry)))
 
+	m.Get(apirouter.GoProxy).Handler(trace.TraceRoute(handler(serveGoProxy)))
+
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("API no route: %s %s from %s", r.Method, r.URL, r.Referer())
 		http.Error(w, "no route", http.StatusNotFound)
@@ -104,7 +113,7 @@ func NewInternalHandler(m *mux.Router, schema *graphql.Schema) http.Handler {
 
 	m.Get(apirouter.ExternalServiceConfigs).Handler(trace.TraceRoute(handler(serveExternalServiceConfigs)))
 	m.Get(apirouter.ExternalServicesList).Handler(trace.TraceRoute(handler(serveExternalServicesList)))
-	m.Get(apirouter.PhabricatorRepoCreate).Handler(trace.TraceRoute(handler(servePhabricatorRepoCreate)))
+	m.Get(apirouter.PhabricatorRepoCreate).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(handler(servePhabricatorRepoCreate))))
 	reposList := &reposListServer{
 		SourcegraphDotComMode: envvar.SourcegraphDotComMode(),
 		Repos:                 backend.Repos,
@@ -117,8 +126,8 @@ func NewInternalHandler(m *mux.Router, schema *graphql.Schema) http.Handler {
 	m.Get(apirouter.SettingsGetForSubject).Handler(trace.TraceRoute(handler(serveSettingsGetForSubject)))
 	m.Get(apirouter.SavedQueriesListAll).Handler(trace.TraceRoute(handler(serveSavedQueriesListAll)))
 	m.Get(apirouter.SavedQueriesGetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesGetInfo)))
-	m.Get(apirouter.SavedQueriesSetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesSetInfo)))
-	m.Get(apirouter.SavedQueriesDeleteInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesDeleteInfo)))
+	m.Get(apirouter.SavedQueriesSetInfo).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(handler(serveSavedQueriesSetInfo))))
+	m.Get(apirouter.SavedQueriesDeleteInfo).Handler(trace.TraceRoute(handlerutil.RejectInMaintenanceMode(handler(serveSavedQueriesDeleteInfo))))
 	m.Get(apirouter.OrgsListUsers).Handler(trace.TraceRoute(handler(serveOrgsListUsers)))
 	m.Get(apirouter.OrgsGetByName).Handler(trace.TraceRoute(handler(serveOrgsGetByName)))
 	m.Get(apirouter.UsersGetByUsername).Handler(trace.TraceRoute(handler(serveUsersGetByUsername)))
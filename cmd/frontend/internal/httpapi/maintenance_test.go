@@ -0,0 +1,53 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: true, Message: "upgrading"}}})
+	defer conf.Mock(nil)
+	c := newTest()
+
+	t.Run("write endpoint", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/repos/github.com/gorilla/mux/-/refresh", nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+		}
+		if resp.Header.Get("Retry-After") == "" {
+			t.Error("no Retry-After header")
+		}
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/graphql", strings.NewReader(`{"query": "mutation { deleteUser(user: \"x\") { alwaysNil } }"}`))
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if got, want := resp.Header.Get("Content-Type"), "application/json"; got != want {
+			t.Errorf("got Content-Type %q, want %q", got, want)
+		}
+		var body struct {
+			Errors []struct{ Message string }
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if want := "Sourcegraph is in maintenance mode and is read-only: upgrading"; len(body.Errors) != 1 || body.Errors[0].Message != want {
+			t.Errorf("got errors %+v, want %q", body.Errors, want)
+		}
+	})
+}
@@ -7,12 +7,15 @@ import (
 )
 
 const (
-	LSIFUpload = "lsif.upload"
-	GraphQL    = "graphql"
+	LSIFUpload     = "lsif.upload"
+	CoverageUpload = "coverage.upload"
+	GraphQL        = "graphql"
 
 	SrcCliVersion  = "src-cli.version"
 	SrcCliDownload = "src-cli.download"
 
+	GoProxy = "go-proxy"
+
 	Registry = "registry"
 
 	RepoShield  = "repo.shield"
@@ -22,6 +25,9 @@ const (
 	GitHubWebhooks          = "github.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
 
+	SlackCommands = "slack.commands"
+	SlackEvents   = "slack.events"
+
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
 	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
@@ -64,9 +70,13 @@ func New(base *mux.Router) *mux.Router {
 	addGraphQLRoute(base)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
+	base.Path("/slack/commands").Methods("POST").Name(SlackCommands)
+	base.Path("/slack/events").Methods("POST").Name(SlackEvents)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
+	base.Path("/coverage/upload").Methods("POST").Name(CoverageUpload)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
+	base.Path("/go-proxy/{Module:.+}/@v/{File}").Methods("GET").Name(GoProxy)
 
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
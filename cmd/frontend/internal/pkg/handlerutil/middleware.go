@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 
 	"github.com/gorilla/csrf"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 )
 
 // CSRFMiddleware is HTTP middleware that helps prevent cross-site request forgery. To make your
@@ -50,3 +51,17 @@ func CSRFMiddleware(next http.Handler, isSecure func() bool) http.Handler {
 		h.ServeHTTP(w, r)
 	})
 }
+
+// RejectInMaintenanceMode wraps the handler of an endpoint that writes, so that its requests are
+// rejected while maintenance mode is enabled. Clients (such as code hosts that send webhooks) are
+// asked to retry later.
+func RejectInMaintenanceMode(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := conf.ErrMaintenanceMode(); err != nil {
+			w.Header().Set("Retry-After", "300")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
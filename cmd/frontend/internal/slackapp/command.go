@@ -0,0 +1,195 @@
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxSearchResults is the maximum number of search results shown in response to the slash command.
+const maxSearchResults = 5
+
+// ServeCommand handles the /sourcegraph slash command, which runs a search with the permissions of
+// the Sourcegraph user that the Slack user is linked to. The command is acknowledged immediately,
+// and the results are sent to the command's response URL when the search is done.
+//
+// The response is ephemeral (visible only to the Slack user), because the results may include
+// code that the other members of the channel can't view.
+func (h *Handler) ServeCommand(w http.ResponseWriter, r *http.Request) error {
+	_, body, err := h.readRequest(r)
+	if err != nil {
+		return err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+
+	query := strings.TrimSpace(form.Get("text"))
+	if query == "" {
+		return respond(w, "Usage: `"+form.Get("command")+" <query>` searches Sourcegraph, e.g. `"+form.Get("command")+" repo:myrepo lang:go http.Handler`.")
+	}
+	userID, err := db.SlackUserLinks.GetUserID(r.Context(), form.Get("team_id"), form.Get("user_id"))
+	if errcode.IsNotFound(err) {
+		return respond(w, fmt.Sprintf("Link your Slack account to your Sourcegraph account to search: %s", linkURL()))
+	} else if err != nil {
+		return err
+	}
+
+	responseURL := form.Get("response_url")
+	go func() {
+		ctx, cancel := context.WithTimeout(actor.WithActor(context.Background(), actor.FromUser(userID)), timeout)
+		defer cancel()
+		payload, err := h.search(ctx, query)
+		if err != nil {
+			payload = &slack.Payload{Text: fmt.Sprintf("Search for `%s` failed: %s", query, err)}
+		}
+		payload.ResponseType = "ephemeral"
+		if err := h.client.Respond(ctx, responseURL, payload); err != nil {
+			log15.Warn("Unable to send Slack search results.", "error", err)
+		}
+	}()
+	return respond(w, fmt.Sprintf("Searching for `%s`...", query))
+}
+
+// respond writes an ephemeral response to a slash command.
+func respond(w http.ResponseWriter, text string) error {
+	return json.NewEncoder(w).Encode(&slack.Payload{ResponseType: "ephemeral", Text: text})
+}
+
+func linkURL() string {
+	return globals.ExternalURL().ResolveReference(&url.URL{Path: linkPath}).String()
+}
+
+const searchQuery = `
+query SlackSearch($query: String!) {
+	search(query: $query, version: V2, patternType: literal) {
+		results {
+			matchCount
+			limitHit
+			results {
+				__typename
+				... on FileMatch {
+					file { path url }
+					repository { name }
+					lineMatches { lineNumber preview }
+				}
+				... on CommitSearchResult {
+					url
+					label { text }
+				}
+				... on Repository {
+					name
+					url
+				}
+			}
+		}
+	}
+}`
+
+type searchResult struct {
+	Typename string `json:"__typename"`
+
+	// FileMatch
+	File *struct {
+		Path string
+		URL  string
+	}
+	Repository *struct {
+		Name string
+	}
+	LineMatches []struct {
+		LineNumber int
+		Preview    string
+	}
+
+	// CommitSearchResult and Repository
+	URL   string
+	Label *struct {
+		Text string
+	}
+	Name string
+}
+
+// search runs the search query and returns a message that summarizes the top results.
+func (h *Handler) search(ctx context.Context, query string) (*slack.Payload, error) {
+	var data struct {
+		Search *struct {
+			Results struct {
+				MatchCount int
+				LimitHit   bool
+				Results    []searchResult
+			}
+		}
+	}
+	if err := h.graphQL(ctx, searchQuery, map[string]interface{}{"query": query}, &data); err != nil {
+		return nil, err
+	}
+	if data.Search == nil || len(data.Search.Results.Results) == 0 {
+		return &slack.Payload{Text: fmt.Sprintf("No results for `%s`.", query)}, nil
+	}
+
+	results := data.Search.Results
+	count := fmt.Sprint(results.MatchCount)
+	if results.LimitHit {
+		count += "+"
+	}
+	searchURL := globals.ExternalURL().ResolveReference(&url.URL{Path: "/search", RawQuery: url.Values{"q": {query}, "patternType": {"literal"}}.Encode()})
+	payload := &slack.Payload{Text: fmt.Sprintf("%s results for `%s` (<%s|view all>)", count, query, searchURL)}
+	for i, r := range results.Results {
+		if i == maxSearchResults {
+			break
+		}
+		payload.Attachments = append(payload.Attachments, searchResultAttachment(r))
+	}
+	return payload, nil
+}
+
+func searchResultAttachment(r searchResult) *slack.Attachment {
+	a := &slack.Attachment{Color: "#1d85e2", MarkdownIn: []string{"text"}}
+	switch r.Typename {
+	case "FileMatch":
+		a.Title = r.File.Path
+		a.TitleLink = absoluteURL(r.File.URL)
+		a.Footer = r.Repository.Name
+		var lines []string
+		for _, m := range r.LineMatches {
+			if len(lines) == 3 {
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%d: %s", m.LineNumber+1, strings.TrimRight(m.Preview, "\n")))
+		}
+		if len(lines) > 0 {
+			a.Text = "```" + strings.Join(lines, "\n") + "```"
+		}
+	case "CommitSearchResult":
+		if r.Label != nil {
+			a.Title = r.Label.Text
+		}
+		a.TitleLink = absoluteURL(r.URL)
+	case "Repository":
+		a.Title = r.Name
+		a.TitleLink = absoluteURL(r.URL)
+	}
+	a.Fallback = a.Title
+	return a
+}
+
+// absoluteURL resolves a URL (such as the url field of a GraphQL type) relative to the external URL.
+func absoluteURL(u string) string {
+	ref, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return globals.ExternalURL().ResolveReference(ref).String()
+}
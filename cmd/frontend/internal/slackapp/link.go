@@ -0,0 +1,91 @@
+package slackapp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// linkPath is the URL path where users start linking their Slack accounts.
+const linkPath = "/-/slack/link"
+
+// stateCookie is the name of the cookie that holds the OAuth state of an account link in progress.
+const stateCookie = "sg-slack-link-state"
+
+// ServeLink starts linking the signed-in user's Slack account to their Sourcegraph account, by
+// redirecting them to Slack's OAuth authorization page.
+func ServeLink(w http.ResponseWriter, r *http.Request) error {
+	c := conf.Get().SlackApp
+	if c == nil || c.ClientID == "" {
+		return errNotConfigured
+	}
+	if !actor.FromContext(r.Context()).IsAuthenticated() {
+		http.Redirect(w, r, "/sign-in?"+url.Values{"returnTo": {linkPath}}.Encode(), http.StatusFound)
+		return nil
+	}
+
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return err
+	}
+	state := hex.EncodeToString(b[:])
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     linkPath,
+		MaxAge:   10 * 60,
+		HttpOnly: true,
+		Secure:   globals.ExternalURL().Scheme == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, Client.AuthorizeURL(c.ClientID, callbackURL(), state), http.StatusFound)
+	return nil
+}
+
+// ServeLinkCallback finishes linking the signed-in user's Slack account when Slack redirects back
+// after authorization.
+//
+// 🚨 SECURITY: The OAuth state must match the state of the link that the signed-in user started, so
+// that a Slack account can't be linked to a user who didn't start linking it.
+func ServeLinkCallback(w http.ResponseWriter, r *http.Request) error {
+	c := conf.Get().SlackApp
+	if c == nil || c.ClientID == "" {
+		return errNotConfigured
+	}
+	a := actor.FromContext(r.Context())
+	if !a.IsAuthenticated() {
+		return &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: errors.New("must be signed in to link a Slack account")}
+	}
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("invalid OAuth state (try linking your Slack account again)")}
+	}
+	http.SetCookie(w, &http.Cookie{Name: stateCookie, Path: linkPath, MaxAge: -1})
+	if e := r.URL.Query().Get("error"); e != "" {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("Slack authorization failed: " + e)}
+	}
+
+	resp, err := Client.OAuthAccess(r.Context(), c.ClientID, c.ClientSecret, r.URL.Query().Get("code"), callbackURL())
+	if err != nil {
+		return err
+	}
+	if err := db.SlackUserLinks.Link(r.Context(), resp.Team.ID, resp.AuthedUser.ID, a.UID); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err = w.Write([]byte("Your Slack account in the " + resp.Team.Name + " workspace is linked to your Sourcegraph account. You can now use the /sourcegraph command in Slack."))
+	return err
+}
+
+func callbackURL() string {
+	return globals.ExternalURL().ResolveReference(&url.URL{Path: linkPath + "/callback"}).String()
+}
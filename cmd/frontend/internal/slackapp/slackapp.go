@@ -0,0 +1,76 @@
+// Package slackapp implements the Sourcegraph Slack app: the /sourcegraph slash command, unfurling
+// of links to Sourcegraph, and linking of Slack accounts to Sourcegraph accounts.
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// maxRequestBytes is the maximum size of a request from Slack.
+const maxRequestBytes = 1024 * 1024
+
+// timeout is the maximum duration of a search or unfurl. It runs after the request from Slack was
+// acknowledged, because Slack requires a response within 3 seconds.
+const timeout = time.Minute
+
+// Client is the Slack Web API client of the Slack app.
+var Client = slack.DefaultAppClient
+
+// Handler serves the requests that Slack sends to the Slack app.
+type Handler struct {
+	// graphQL executes a GraphQL query with the permissions of the context's actor and decodes the
+	// data of the response into result.
+	graphQL func(ctx context.Context, query string, vars map[string]interface{}, result interface{}) error
+
+	client *slack.AppClient
+	now    func() time.Time
+}
+
+// NewHandler returns a handler that runs searches and unfurls links with the GraphQL schema.
+func NewHandler(schema *graphql.Schema) *Handler {
+	return &Handler{
+		graphQL: func(ctx context.Context, query string, vars map[string]interface{}, result interface{}) error {
+			resp := schema.Exec(ctx, query, "", vars)
+			if len(resp.Errors) > 0 {
+				return resp.Errors[0]
+			}
+			return json.Unmarshal(resp.Data, result)
+		},
+		client: Client,
+		now:    time.Now,
+	}
+}
+
+// errNotConfigured occurs when the Slack app is not configured in site configuration.
+var errNotConfigured = &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.New("the Slack app is not configured (site configuration slackApp)")}
+
+// readRequest reads the body of a request from Slack.
+//
+// 🚨 SECURITY: The request's endpoints are accessible to anonymous users, so the request must be
+// verified to have been sent by Slack (with the Slack app's signing secret) before it is trusted.
+func (h *Handler) readRequest(r *http.Request) (*schema.SlackApp, []byte, error) {
+	c := conf.Get().SlackApp
+	if c == nil {
+		return nil, nil, errNotConfigured
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := slack.VerifyRequest(c.SigningSecret, r.Header, body, h.now()); err != nil {
+		return nil, nil, &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: err}
+	}
+	return c, body, nil
+}
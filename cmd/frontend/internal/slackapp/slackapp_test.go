@@ -0,0 +1,211 @@
+package slackapp
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var now = time.Unix(1531420618, 0)
+
+// fakeSlack is a fake Slack endpoint that records the requests it receives.
+type fakeSlack struct {
+	*httptest.Server
+	requests chan map[string]interface{}
+}
+
+func newFakeSlack(t *testing.T) *fakeSlack {
+	s := &fakeSlack{requests: make(chan map[string]interface{}, 10)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var req map[string]interface{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Error(err)
+		}
+		req["path"] = r.URL.Path
+		s.requests <- req
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	return s
+}
+
+func (s *fakeSlack) next(t *testing.T) map[string]interface{} {
+	select {
+	case req := <-s.requests:
+		return req
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a request to Slack")
+		return nil
+	}
+}
+
+func signedRequest(path, body string) *http.Request {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(slack.Sign("secret", timestamp, []byte(body))))
+	return req
+}
+
+func setup(t *testing.T) (*Handler, *fakeSlack, func()) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SlackApp: &schema.SlackApp{ClientID: "id", ClientSecret: "s", SigningSecret: "secret", BotToken: "xoxb-1"},
+	}})
+	globals.SetExternalURL(&url.URL{Scheme: "https", Host: "sourcegraph.example.com"})
+	db.Mocks.SlackUserLinks.GetUserID = func(ctx context.Context, teamID, slackUserID string) (int32, error) {
+		if teamID == "T1" && slackUserID == "U1" {
+			return 7, nil
+		}
+		return 0, db.SlackUserLinkNotFoundError{}
+	}
+	s := newFakeSlack(t)
+	h := &Handler{client: &slack.AppClient{APIURL: s.URL}, now: func() time.Time { return now }}
+	return h, s, func() {
+		s.Close()
+		conf.Mock(nil)
+		db.Mocks = db.MockStores{}
+	}
+}
+
+func TestServeCommand(t *testing.T) {
+	h, fake, cleanup := setup(t)
+	defer cleanup()
+	h.graphQL = func(ctx context.Context, query string, vars map[string]interface{}, result interface{}) error {
+		if a := actor.FromContext(ctx); a.UID != 7 {
+			t.Errorf("got actor %+v, want the linked user", a)
+		}
+		return json.Unmarshal([]byte(`{"search": {"results": {"matchCount": 1, "results": [
+			{"__typename": "FileMatch", "file": {"path": "a.go", "url": "/r@c/-/blob/a.go"}, "repository": {"name": "r"}, "lineMatches": [{"lineNumber": 2, "preview": "func foo()"}]}
+		]}}}`), result)
+	}
+
+	serve := func(form url.Values) (*httptest.ResponseRecorder, error) {
+		rec := httptest.NewRecorder()
+		return rec, h.ServeCommand(rec, signedRequest("/.api/slack/commands", form.Encode()))
+	}
+
+	t.Run("unverified", func(t *testing.T) {
+		req := signedRequest("/.api/slack/commands", "text=foo")
+		req.Header.Set("X-Slack-Signature", "v0=00")
+		if err := h.ServeCommand(httptest.NewRecorder(), req); errcode.HTTP(err) != http.StatusUnauthorized {
+			t.Errorf("got err %v, want 401", err)
+		}
+	})
+
+	t.Run("unlinked", func(t *testing.T) {
+		rec, err := serve(url.Values{"team_id": {"T1"}, "user_id": {"U2"}, "text": {"foo"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(rec.Body.String(), "https://sourcegraph.example.com/-/slack/link") {
+			t.Errorf("got %q, want a link to link the account", rec.Body.String())
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		rec, err := serve(url.Values{"team_id": {"T1"}, "user_id": {"U1"}, "text": {"foo"}, "response_url": {fake.URL + "/response"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(rec.Body.String(), "Searching for `foo`") {
+			t.Errorf("got %q", rec.Body.String())
+		}
+		resp := fake.next(t)
+		if resp["path"] != "/response" || resp["response_type"] != "ephemeral" {
+			t.Errorf("got response %v", resp)
+		}
+		attachments, _ := resp["attachments"].([]interface{})
+		if len(attachments) != 1 || attachments[0].(map[string]interface{})["title_link"] != "https://sourcegraph.example.com/r@c/-/blob/a.go" {
+			t.Errorf("got attachments %v", attachments)
+		}
+	})
+}
+
+func TestServeEvents(t *testing.T) {
+	h, fake, cleanup := setup(t)
+	defer cleanup()
+	h.graphQL = func(ctx context.Context, query string, vars map[string]interface{}, result interface{}) error {
+		// The linked user can only view the repository "r".
+		if vars["repo"] != "r" {
+			return json.Unmarshal([]byte(`{"repository": null}`), result)
+		}
+		return json.Unmarshal([]byte(`{"repository": {"commit": {"abbreviatedOID": "abc", "blob": {"content": "a\nb\nc\nd\n"}}}}`), result)
+	}
+
+	t.Run("url_verification", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := h.ServeEvents(rec, signedRequest("/.api/slack/events", `{"type": "url_verification", "challenge": "xyz"}`)); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(rec.Body.String(), `"challenge":"xyz"`) {
+			t.Errorf("got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("link_shared", func(t *testing.T) {
+		body := `{"type": "event_callback", "team_id": "T1", "event": {"type": "link_shared", "channel": "C1", "user": "U1", "message_ts": "1.2", "links": [
+			{"url": "https://sourcegraph.example.com/r@master/-/blob/a.go#L2-3"},
+			{"url": "https://sourcegraph.example.com/secret/-/blob/b.go#L1"},
+			{"url": "https://example.com/r/-/blob/a.go"}
+		]}}`
+		if err := h.ServeEvents(httptest.NewRecorder(), signedRequest("/.api/slack/events", body)); err != nil {
+			t.Fatal(err)
+		}
+		req := fake.next(t)
+		unfurls, _ := req["unfurls"].(map[string]interface{})
+		if req["path"] != "/chat.unfurl" || req["channel"] != "C1" || len(unfurls) != 1 {
+			t.Fatalf("got request %v, want only the permitted link to be unfurled", req)
+		}
+		a := unfurls["https://sourcegraph.example.com/r@master/-/blob/a.go#L2-3"].(map[string]interface{})
+		if a["text"] != "```b\nc```" {
+			t.Errorf("got text %q", a["text"])
+		}
+	})
+}
+
+func TestServeLinkCallback(t *testing.T) {
+	_, fake, cleanup := setup(t)
+	defer cleanup()
+	fake.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "team": {"id": "T1", "name": "Acme"}, "authed_user": {"id": "U9"}}`))
+	})
+	defer func(c *slack.AppClient) { Client = c }(Client)
+	Client = &slack.AppClient{APIURL: fake.URL}
+	var linked []string
+	db.Mocks.SlackUserLinks.Link = func(ctx context.Context, teamID, slackUserID string, userID int32) error {
+		linked = append(linked, teamID+"/"+slackUserID+"/"+strconv.Itoa(int(userID)))
+		return nil
+	}
+
+	callback := func(state string) error {
+		req := httptest.NewRequest("GET", "/-/slack/link/callback?code=c&state="+state, nil)
+		req.AddCookie(&http.Cookie{Name: stateCookie, Value: "s1"})
+		req = req.WithContext(actor.WithActor(req.Context(), actor.FromUser(3)))
+		return ServeLinkCallback(httptest.NewRecorder(), req)
+	}
+	if err := callback("s2"); errcode.HTTP(err) != http.StatusBadRequest {
+		t.Errorf("got err %v, want 400 for a mismatched state", err)
+	}
+	if err := callback("s1"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"T1/U9/3"}; len(linked) != 1 || linked[0] != want[0] {
+		t.Errorf("got links %v, want %v", linked, want)
+	}
+}
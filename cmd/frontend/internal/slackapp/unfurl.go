@@ -0,0 +1,261 @@
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxUnfurlLines is the maximum number of lines of a file shown when a link to a range of lines is
+// unfurled.
+const maxUnfurlLines = 10
+
+type event struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	TeamID    string `json:"team_id"`
+	Event     struct {
+		Type      string `json:"type"`
+		Channel   string `json:"channel"`
+		User      string `json:"user"`
+		MessageTS string `json:"message_ts"`
+		Links     []struct {
+			URL string `json:"url"`
+		} `json:"links"`
+	} `json:"event"`
+}
+
+// ServeEvents handles events of the Slack Events API. The only event that is handled is link_shared,
+// which is sent when a link to Sourcegraph is posted in a message. The links are unfurled (with
+// chat.unfurl) with the permissions of the Sourcegraph user that the poster's Slack account is linked
+// to, so that only links that the poster can view are unfurled. Links posted by Slack users who
+// haven't linked their accounts are not unfurled.
+func (h *Handler) ServeEvents(w http.ResponseWriter, r *http.Request) error {
+	c, body, err := h.readRequest(r)
+	if err != nil {
+		return err
+	}
+	var ev event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+
+	switch {
+	case ev.Type == "url_verification":
+		return json.NewEncoder(w).Encode(map[string]string{"challenge": ev.Challenge})
+	case ev.Type == "event_callback" && ev.Event.Type == "link_shared" && c.BotToken != "":
+		userID, err := db.SlackUserLinks.GetUserID(r.Context(), ev.TeamID, ev.Event.User)
+		if errcode.IsNotFound(err) {
+			break
+		} else if err != nil {
+			return err
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(actor.WithActor(context.Background(), actor.FromUser(userID)), timeout)
+			defer cancel()
+			unfurls := map[string]*slack.Attachment{}
+			for _, link := range ev.Event.Links {
+				a, err := h.unfurl(ctx, link.URL)
+				if err != nil {
+					log15.Warn("Unable to unfurl link in Slack.", "url", link.URL, "error", err)
+					continue
+				}
+				if a != nil {
+					unfurls[link.URL] = a
+				}
+			}
+			if len(unfurls) == 0 {
+				return
+			}
+			if err := h.client.Unfurl(ctx, c.BotToken, ev.Event.Channel, ev.Event.MessageTS, unfurls); err != nil {
+				log15.Warn("Unable to unfurl links in Slack.", "error", err)
+			}
+		}()
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+var lineRangePattern = regexp.MustCompile(`^L(\d+)(?::\d+)?(?:-(\d+)(?::\d+)?)?`)
+
+// unfurl returns the preview of a link to Sourcegraph, or nil if the link can't be unfurled (because
+// it's not a link to a file, commit, or campaign, or because the context's actor can't view it).
+func (h *Handler) unfurl(ctx context.Context, link string) (*slack.Attachment, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil
+	}
+	externalURL := globals.ExternalURL()
+	if u.Host != externalURL.Host {
+		return nil, nil
+	}
+	p := strings.TrimPrefix(u.Path, strings.TrimSuffix(externalURL.Path, "/"))
+
+	if strings.HasPrefix(p, "/campaigns/") {
+		return h.unfurlCampaign(ctx, link, strings.TrimPrefix(p, "/campaigns/"))
+	}
+	if i := strings.Index(p, "/-/commit/"); i >= 0 {
+		repo, _ := splitRepoRev(p[:i])
+		return h.unfurlCommit(ctx, link, repo, p[i+len("/-/commit/"):])
+	}
+	if i := strings.Index(p, "/-/blob/"); i >= 0 {
+		repo, rev := splitRepoRev(p[:i])
+		var startLine, endLine int
+		if m := lineRangePattern.FindStringSubmatch(u.Fragment); m != nil {
+			startLine, _ = strconv.Atoi(m[1])
+			endLine = startLine
+			if m[2] != "" {
+				endLine, _ = strconv.Atoi(m[2])
+			}
+		}
+		return h.unfurlBlob(ctx, link, repo, rev, p[i+len("/-/blob/"):], startLine, endLine)
+	}
+	return nil, nil
+}
+
+// splitRepoRev splits the "repo@rev" part of a URL path.
+func splitRepoRev(s string) (repo, rev string) {
+	s = strings.TrimPrefix(s, "/")
+	if i := strings.Index(s, "@"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+func (h *Handler) unfurlBlob(ctx context.Context, link, repo, rev, path string, startLine, endLine int) (*slack.Attachment, error) {
+	var data struct {
+		Repository *struct {
+			Commit *struct {
+				AbbreviatedOID string
+				Blob           *struct {
+					Content string
+				}
+			}
+		}
+	}
+	if err := h.graphQL(ctx, `
+query SlackUnfurlBlob($repo: String!, $rev: String!, $path: String!) {
+	repository(name: $repo) {
+		commit(rev: $rev) {
+			abbreviatedOID
+			blob(path: $path) { content }
+		}
+	}
+}`, map[string]interface{}{"repo": repo, "rev": rev, "path": path}, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil || data.Repository.Commit == nil || data.Repository.Commit.Blob == nil {
+		return nil, nil
+	}
+
+	a := &slack.Attachment{
+		Title:      path,
+		TitleLink:  link,
+		Footer:     fmt.Sprintf("%s@%s", repo, data.Repository.Commit.AbbreviatedOID),
+		Color:      "#1d85e2",
+		MarkdownIn: []string{"text"},
+	}
+	lines := strings.Split(data.Repository.Commit.Blob.Content, "\n")
+	if startLine >= 1 && startLine <= len(lines) && endLine >= startLine {
+		if endLine > len(lines) {
+			endLine = len(lines)
+		}
+		if endLine-startLine+1 > maxUnfurlLines {
+			endLine = startLine + maxUnfurlLines - 1
+		}
+		a.Title = fmt.Sprintf("%s (lines %d-%d)", path, startLine, endLine)
+		a.Text = "```" + strings.Join(lines[startLine-1:endLine], "\n") + "```"
+	}
+	a.Fallback = a.Title
+	return a, nil
+}
+
+func (h *Handler) unfurlCommit(ctx context.Context, link, repo, rev string) (*slack.Attachment, error) {
+	var data struct {
+		Repository *struct {
+			Commit *struct {
+				AbbreviatedOID string
+				Subject        string
+				Author         struct {
+					Person struct {
+						DisplayName string
+					}
+				}
+			}
+		}
+	}
+	if err := h.graphQL(ctx, `
+query SlackUnfurlCommit($repo: String!, $rev: String!) {
+	repository(name: $repo) {
+		commit(rev: $rev) {
+			abbreviatedOID
+			subject
+			author { person { displayName } }
+		}
+	}
+}`, map[string]interface{}{"repo": repo, "rev": rev}, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil || data.Repository.Commit == nil {
+		return nil, nil
+	}
+	commit := data.Repository.Commit
+	return &slack.Attachment{
+		Title:     commit.Subject,
+		TitleLink: link,
+		Fallback:  commit.Subject,
+		Text:      fmt.Sprintf("Commit %s by %s", commit.AbbreviatedOID, commit.Author.Person.DisplayName),
+		Footer:    repo,
+		Color:     "#1d85e2",
+	}, nil
+}
+
+func (h *Handler) unfurlCampaign(ctx context.Context, link, id string) (*slack.Attachment, error) {
+	var data struct {
+		Node *struct {
+			Name        string
+			Description string
+			ClosedAt    *string
+		}
+	}
+	if err := h.graphQL(ctx, `
+query SlackUnfurlCampaign($id: ID!) {
+	node(id: $id) {
+		... on Campaign {
+			name
+			description
+			closedAt
+		}
+	}
+}`, map[string]interface{}{"id": id}, &data); err != nil {
+		return nil, err
+	}
+	if data.Node == nil || data.Node.Name == "" {
+		return nil, nil
+	}
+	footer := "Campaign"
+	if data.Node.ClosedAt != nil {
+		footer = "Closed campaign"
+	}
+	return &slack.Attachment{
+		Title:      data.Node.Name,
+		TitleLink:  link,
+		Fallback:   data.Node.Name,
+		Text:       data.Node.Description,
+		Footer:     footer,
+		Color:      "#1d85e2",
+		MarkdownIn: []string{"text"},
+	}, nil
+}
@@ -64,6 +64,10 @@ type ExternalService struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   *time.Time
+
+	// NamespaceOrgID is the ID of the organization whose repository quota the repositories of this
+	// external service count against (or 0 if none).
+	NamespaceOrgID int32
 }
 
 type GlobalState struct {
@@ -230,3 +234,15 @@ type SearchLatency struct {
 	P90 float64
 	P99 float64
 }
+
+// SubRepoPathRule restricts read access to the paths under PathPrefix in a repository to the
+// listed users and the members of the listed organizations.
+type SubRepoPathRule struct {
+	ID         int32
+	RepoID     api.RepoID
+	PathPrefix string
+	UserIDs    []int32
+	OrgIDs     []int32
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -24,4 +24,5 @@ docker build -f cmd/gitserver/Dockerfile -t $IMAGE $OUTPUT \
     --progress=plain \
     --build-arg COMMIT_SHA \
     --build-arg DATE \
-    --build-arg VERSION
+    --build-arg VERSION \
+    --build-arg P4_SHA256
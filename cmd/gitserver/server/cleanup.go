@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -59,6 +60,7 @@ var reposRecloned = prometheus.NewCounter(prometheus.CounterOpts{
 // 2. Remove stale lock files.
 // 3. Remove inactive repos on sourcegraph.com
 // 4. Reclone repos after a while. (simulate git gc)
+// 5. Remove unused object pools.
 func (s *Server) cleanupRepos() {
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
@@ -79,6 +81,27 @@ func (s *Server) cleanupRepos() {
 		return true, nil
 	}
 
+	maybeRemoveMissingAlternates := func(dir GitDir) (done bool, err error) {
+		// A repo whose object pool is gone is missing objects. Removing it
+		// causes it to be recloned.
+		alternates, err := repoAlternates(dir)
+		if err != nil {
+			return false, err
+		}
+		for _, path := range alternates {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				continue
+			}
+			log15.Info("removing repo with missing alternates", "repo", dir, "alternates", path)
+			if err := s.removeRepoDirectory(dir); err != nil {
+				return true, err
+			}
+			reposRemoved.Inc()
+			return true, nil
+		}
+		return false, nil
+	}
+
 	ensureGitAttributes := func(dir GitDir) (done bool, err error) {
 		return false, setGitAttributes(dir)
 	}
@@ -129,7 +152,14 @@ func (s *Server) cleanupRepos() {
 			return false, errors.Wrap(err, "failed to get remote URL")
 		}
 
-		if _, err := s.cloneRepo(ctx, repo, remoteURL, &cloneOptions{Block: true, Overwrite: true}); err != nil {
+		// Reclone with the same object pool. The pool exists (otherwise the
+		// repo would have been removed for missing its alternates), unless the
+		// repo was never cloned with it.
+		opts := &cloneOptions{Block: true, Overwrite: true}
+		if pool := repoObjectPool(dir); pool != "" {
+			opts.ObjectPool = &protocol.ObjectPool{Name: pool}
+		}
+		if _, err := s.cloneRepo(ctx, repo, remoteURL, opts); err != nil {
 			return true, err
 		}
 		reposRecloned.Inc()
@@ -176,6 +206,7 @@ func (s *Server) cleanupRepos() {
 	cleanups := []cleanupFn{
 		// Do some sanity checks on the repository.
 		{"maybe remove corrupt", maybeRemoveCorrupt},
+		{"maybe remove missing alternates", maybeRemoveMissingAlternates},
 		// If git is interrupted it can leave lock files lying around. It does
 		// not clean these up, and instead fails commands.
 		{"remove stale locks", removeStaleLocks},
@@ -220,6 +251,10 @@ func (s *Server) cleanupRepos() {
 		log15.Error("cleanup: error iterating over repositories", "error", err)
 	}
 
+	if err := s.cleanupObjectPools(); err != nil {
+		log15.Error("cleanup: error cleaning up object pools", "error", err)
+	}
+
 	if s.DiskSizer == nil {
 		s.DiskSizer = &StatDiskSizer{}
 	}
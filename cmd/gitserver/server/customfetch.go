@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/svn"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -39,6 +41,16 @@ func extractDomainPath(cloneURL string) (string, error) {
 }
 
 func customFetchCmd(ctx context.Context, urlVal string) *exec.Cmd {
+	// Repositories mirrored from Perforce can't be fetched with git fetch. They are updated
+	// by importing new changelists with git-p4 instead.
+	if perforce.IsCloneURL(urlVal) {
+		return perforceFetchCmd(ctx, urlVal)
+	}
+	// Likewise, repositories mirrored from Subversion are updated with git-svn.
+	if svn.IsCloneURL(urlVal) {
+		return svnUpdateCmd(ctx, urlVal)
+	}
+
 	cgm := customGitFetch().(map[string][]string)
 	if len(cgm) == 0 {
 		return nil
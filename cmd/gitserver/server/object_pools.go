@@ -0,0 +1,346 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// Forks of the same upstream repository (a fork network) have most of their
+// Git objects in common. Instead of storing those objects once per fork,
+// gitserver clones forks with `git clone --reference` against an object pool:
+// a bare clone of the upstream repository under objectPoolsDirName. The fork
+// then lists the pool's object directory in objects/info/alternates and only
+// stores the objects that the pool doesn't have.
+//
+// A fork depends on the objects of its pool for as long as it exists, so
+// pools are never garbage collected or recloned, and their fetches never
+// prune refs. A pool is removed by cleanupObjectPools once no repository
+// uses it anymore.
+//
+// Pools are local to each gitserver: a gitserver creates the pool of a fork
+// network the first time it clones a fork of that network. The alternates
+// path is relative, so that a repository stays valid if ReposDir is moved or
+// restored as a whole. A repository whose pool is missing is removed by the
+// janitor (and then recloned).
+
+// objectPoolsDirName is the name of the directory under ReposDir that
+// contains the object pools.
+const objectPoolsDirName = ".pools"
+
+// objectPoolConfigKey is the Git config key that records the name of the
+// object pool of a repository, so that the repository is recloned with the
+// same pool.
+const objectPoolConfigKey = "sourcegraph.objectPool"
+
+// objectPoolFetchInterval is the minimum amount of time between two fetches
+// of an object pool.
+const objectPoolFetchInterval = time.Hour
+
+// objectPoolGracePeriod is how long an unused object pool is kept after it
+// was last used, so that a pool isn't removed while a clone that references
+// it is in progress.
+var objectPoolGracePeriod = 2 * longGitCommandTimeout
+
+var (
+	objectPools = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "object_pools",
+		Help:      "number of object pools shared by forks",
+	})
+	objectPoolMembers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "object_pool_members",
+		Help:      "number of repositories that share the objects of an object pool",
+	})
+	objectPoolSavedBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "object_pool_saved_bytes",
+		Help:      "estimated disk space saved by object pools (the size of each pool times its number of members, minus the size of the pools)",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(objectPools)
+	prometheus.MustRegister(objectPoolMembers)
+	prometheus.MustRegister(objectPoolSavedBytes)
+}
+
+// objectPoolDir returns the directory of the object pool with the given name.
+func (s *Server) objectPoolDir(name api.RepoName) GitDir {
+	path := string(protocol.NormalizeRepo(name))
+	return GitDir(filepath.Join(s.ReposDir, objectPoolsDirName, filepath.FromSlash(path), ".git"))
+}
+
+// objectPoolLock returns the mutex that serializes the creation, fetches, and
+// removal of the object pool in dir.
+func (s *Server) objectPoolLock(dir GitDir) *sync.Mutex {
+	mu, _ := s.objectPoolLocks.LoadOrStore(dir, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// ensureObjectPool returns the directory of an object pool, creating the pool
+// by cloning pool.URL if it doesn't exist yet. If pool.URL is empty, the pool
+// must already exist.
+func (s *Server) ensureObjectPool(ctx context.Context, pool *protocol.ObjectPool) (GitDir, error) {
+	dir := s.objectPoolDir(pool.Name)
+	mu := s.objectPoolLock(dir)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if repoCloned(dir) {
+		touchObjectPool(dir)
+		return dir, nil
+	}
+	if pool.URL == "" {
+		return "", errors.Errorf("object pool %s does not exist", pool.Name)
+	}
+
+	tmpPath, err := s.tempDir("pool-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpPath)
+	tmp := GitDir(filepath.Join(tmpPath, ".git"))
+
+	log15.Info("creating object pool", "pool", pool.Name)
+	cmd := exec.CommandContext(ctx, "git", "clone", "--bare", pool.URL, string(tmp))
+	if output, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+		return "", errors.Wrapf(err, "failed to create object pool %s. Output: %s", pool.Name, newURLRedactor(pool.URL).redact(string(output)))
+	}
+
+	// Objects of the pool may be used by its members even after they are no
+	// longer reachable from the pool's refs, so they must never be pruned.
+	for _, kv := range [][2]string{{"gc.auto", "0"}, {"gc.pruneExpire", "never"}} {
+		if err := gitConfigSet(tmp, kv[0], kv[1]); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		return "", err
+	}
+	if err := renameAndSync(string(tmp), string(dir)); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// updateObjectPool fetches the upstream repository of the object pool with
+// the given name, unless it was fetched within objectPoolFetchInterval. It is
+// called before a member is fetched, so that the member doesn't need to fetch
+// the upstream's new objects itself.
+func (s *Server) updateObjectPool(ctx context.Context, name api.RepoName) error {
+	dir := s.objectPoolDir(name)
+	mu := s.objectPoolLock(dir)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !repoCloned(dir) {
+		return nil
+	}
+	touchObjectPool(dir)
+	if lastFetched, err := repoLastFetched(dir); err == nil && time.Since(lastFetched) < objectPoolFetchInterval {
+		return nil
+	}
+
+	url, err := repoRemoteURL(ctx, dir)
+	if err != nil {
+		return err
+	}
+	// No --prune: deleting refs doesn't delete objects (see ensureObjectPool),
+	// but there is no reason to make them unreachable either.
+	cmd := exec.CommandContext(ctx, "git", "fetch", url, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*")
+	cmd.Dir = string(dir)
+	if output, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch object pool %s. Output: %s", name, newURLRedactor(url).redact(string(output)))
+	}
+	return nil
+}
+
+// setObjectPool records the object pool of the repository in dir, so that the
+// repository is recloned with the pool. If the pool exists, its remote URL is
+// updated to pool.URL (which may carry new credentials).
+func (s *Server) setObjectPool(ctx context.Context, dir GitDir, pool *protocol.ObjectPool) error {
+	if current, _ := gitConfigGet(dir, objectPoolConfigKey); strings.TrimSpace(current) != string(pool.Name) {
+		if err := gitConfigSet(dir, objectPoolConfigKey, string(pool.Name)); err != nil {
+			return err
+		}
+	}
+
+	poolDir := s.objectPoolDir(pool.Name)
+	mu := s.objectPoolLock(poolDir)
+	mu.Lock()
+	defer mu.Unlock()
+	if pool.URL == "" || !repoCloned(poolDir) {
+		return nil
+	}
+	if current, _ := repoRemoteURL(ctx, poolDir); current != pool.URL {
+		cmd := exec.Command("git", "remote", "set-url", "origin", "--", pool.URL)
+		cmd.Dir = string(poolDir)
+		if _, err := runCommand(ctx, cmd); err != nil {
+			return errors.Wrapf(err, "failed to update remote URL of object pool %s", pool.Name)
+		}
+	}
+	return nil
+}
+
+// repoObjectPool returns the name of the object pool recorded for the
+// repository in dir, or the empty string if it has none.
+func repoObjectPool(dir GitDir) api.RepoName {
+	name, _ := gitConfigGet(dir, objectPoolConfigKey)
+	return api.RepoName(strings.TrimSpace(name))
+}
+
+// useObjectPool points the alternates of the clone in tmp at the pool in
+// poolDir by a path that is relative to dst, the directory that tmp is going
+// to be renamed to, and records the pool's name in the clone's config.
+func useObjectPool(tmp, dst, poolDir GitDir, name api.RepoName) error {
+	rel, err := filepath.Rel(dst.Path("objects"), poolDir.Path("objects"))
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(tmp.Path("objects", "info", "alternates"), []byte(rel+"\n"), 0644); err != nil {
+		return errors.Wrap(err, "failed to set alternates")
+	}
+	return gitConfigSet(tmp, objectPoolConfigKey, string(name))
+}
+
+// repoAlternates returns the absolute paths of the object directories listed
+// in the alternates of the repository in dir.
+func repoAlternates(dir GitDir) ([]string, error) {
+	f, err := os.Open(dir.Path("objects", "info", "alternates"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			// Relative paths are relative to the objects directory.
+			line = filepath.Join(dir.Path("objects"), line)
+		}
+		paths = append(paths, filepath.Clean(line))
+	}
+	return paths, scanner.Err()
+}
+
+// touchObjectPool marks the object pool in dir as used (see
+// cleanupObjectPools).
+func touchObjectPool(dir GitDir) {
+	now := time.Now()
+	if err := os.Chtimes(string(dir), now, now); err != nil {
+		log15.Warn("failed to mark object pool as used", "pool", dir, "error", err)
+	}
+}
+
+// findObjectPools returns the directories of all object pools.
+func (s *Server) findObjectPools() ([]GitDir, error) {
+	var dirs []GitDir
+	err := bestEffortWalk(filepath.Join(s.ReposDir, objectPoolsDirName), func(path string, fi os.FileInfo) error {
+		if !fi.IsDir() || fi.Name() != ".git" {
+			return nil
+		}
+		dirs = append(dirs, GitDir(path))
+		return filepath.SkipDir
+	})
+	return dirs, err
+}
+
+// cleanupObjectPools removes the object pools that have no members and
+// weren't used within objectPoolGracePeriod, and reports the disk space saved
+// by the others.
+func (s *Server) cleanupObjectPools() error {
+	pools, err := s.findObjectPools()
+	if err != nil {
+		return errors.Wrap(err, "finding object pools")
+	}
+	if len(pools) == 0 {
+		objectPools.Set(0)
+		objectPoolMembers.Set(0)
+		objectPoolSavedBytes.Set(0)
+		return nil
+	}
+
+	gitDirs, err := s.findGitDirs()
+	if err != nil {
+		return err
+	}
+	members := make(map[string]int, len(pools))
+	for _, dir := range gitDirs {
+		alternates, err := repoAlternates(dir)
+		if err != nil {
+			log15.Warn("failed to read alternates", "repo", dir, "error", err)
+			continue
+		}
+		for _, path := range alternates {
+			members[path]++
+		}
+	}
+
+	var numPools, numMembers, savedBytes int64
+	for _, pool := range pools {
+		n := members[pool.Path("objects")]
+		if n == 0 {
+			if err := s.maybeRemoveObjectPool(pool); err != nil {
+				log15.Error("failed to remove unused object pool", "pool", pool, "error", err)
+			}
+			continue
+		}
+
+		size, err := dirSize(pool.Path("objects"))
+		if err != nil {
+			log15.Warn("failed to compute object pool size", "pool", pool, "error", err)
+		}
+		numPools++
+		numMembers += int64(n)
+		savedBytes += size * int64(n-1)
+	}
+	objectPools.Set(float64(numPools))
+	objectPoolMembers.Set(float64(numMembers))
+	objectPoolSavedBytes.Set(float64(savedBytes))
+	return nil
+}
+
+// maybeRemoveObjectPool removes the object pool in dir, which had no members
+// when cleanupObjectPools looked for them, unless it was used since then or
+// within objectPoolGracePeriod.
+func (s *Server) maybeRemoveObjectPool(dir GitDir) error {
+	mu := s.objectPoolLock(dir)
+	mu.Lock()
+	defer mu.Unlock()
+
+	fi, err := os.Stat(string(dir))
+	if err != nil {
+		return err
+	}
+	if time.Since(fi.ModTime()) < objectPoolGracePeriod {
+		return nil
+	}
+	log15.Info("removing unused object pool", "pool", dir)
+	return s.removeRepoDirectory(dir)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestCloneRepo_objectPool(t *testing.T) {
+	remotes, cleanup1 := tmpDir(t)
+	defer cleanup1()
+
+	git := func(dir string, arg ...string) string {
+		t.Helper()
+		c := exec.Command("git", arg...)
+		c.Dir = dir
+		c.Env = append(os.Environ(),
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		)
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %s\n%s", strings.Join(arg, " "), err, b)
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	// The fork has the commit of the upstream repo and one of its own.
+	upstream := filepath.Join(remotes, "upstream")
+	fork := filepath.Join(remotes, "fork")
+	git(remotes, "init", upstream)
+	git(upstream, "commit", "--allow-empty", "-m", "upstream")
+	upstreamCommit := git(upstream, "rev-parse", "HEAD")
+	git(remotes, "clone", upstream, fork)
+	git(fork, "commit", "--allow-empty", "-m", "fork")
+	forkCommit := git(fork, "rev-parse", "HEAD")
+
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+	s := &Server{
+		ReposDir:         reposDir,
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+
+	// file:// URLs prevent git from hardlinking the objects of local clones.
+	repo := api.RepoName("example.com/alice/bar")
+	pool := &protocol.ObjectPool{Name: "example.com/foo/bar", URL: "file://" + upstream}
+	if _, err := s.cloneRepo(context.Background(), repo, "file://"+fork, &cloneOptions{Block: true, ObjectPool: pool}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := s.dir(repo)
+	poolDir := s.objectPoolDir(pool.Name)
+	if have := git(string(dir), "rev-parse", "HEAD"); have != forkCommit {
+		t.Fatalf("got HEAD %s, want %s", have, forkCommit)
+	}
+	git(string(dir), "cat-file", "-e", upstreamCommit)
+	alternates, err := repoAlternates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{poolDir.Path("objects")}; len(alternates) != 1 || alternates[0] != want[0] {
+		t.Fatalf("got alternates %q, want %q", alternates, want)
+	}
+	if have := repoObjectPool(dir); have != pool.Name {
+		t.Errorf("got object pool %q, want %q", have, pool.Name)
+	}
+
+	// The upstream commit (and the empty tree it shares with the fork's
+	// commit) is only stored in the pool.
+	if out := git(string(dir), "count-objects", "-v"); !strings.Contains(out, "count: 0\n") || !strings.Contains(out, "in-pack: 1\n") {
+		t.Errorf("expected the repo to store only its own commit, got:\n%s", out)
+	}
+
+	// The pool isn't removed while it has a member.
+	objectPoolGracePeriod = 0
+	defer func() { objectPoolGracePeriod = 2 * longGitCommandTimeout }()
+	if err := s.cleanupObjectPools(); err != nil {
+		t.Fatal(err)
+	}
+	if !repoCloned(poolDir) {
+		t.Fatal("expected object pool to be kept")
+	}
+
+	// The pool is removed once it has no members.
+	if err := s.deleteRepo(repo); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.cleanupObjectPools(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(reposDir, objectPoolsDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected object pools to be removed: %v", err)
+	}
+}
+
+func TestCleanupMissingAlternates(t *testing.T) {
+	root, cleanup := tmpDir(t)
+	defer cleanup()
+
+	mkFiles(t, root,
+		".pools/github.com/foo/bar/.git/HEAD",
+		".pools/github.com/foo/bar/.git/objects/pack/a.pack",
+		"github.com/alice/bar/.git/HEAD",
+		"github.com/alice/bar/.git/objects/info/alternates",
+		"github.com/bob/bar/.git/HEAD",
+		"github.com/bob/bar/.git/objects/info/alternates",
+	)
+	writeFile(t, filepath.Join(root, "github.com/alice/bar/.git/objects/info/alternates"), []byte("../../../../../.pools/github.com/foo/bar/.git/objects\n"))
+	writeFile(t, filepath.Join(root, "github.com/bob/bar/.git/objects/info/alternates"), []byte("../../../../../.pools/github.com/foo/baz/.git/objects\n"))
+
+	s := &Server{ReposDir: root}
+	s.Handler() // Handler as a side-effect sets up Server
+	s.cleanupRepos()
+
+	// bob/bar is removed because its object pool doesn't exist.
+	assertPaths(t, root,
+		".tmp",
+		".pools/github.com/foo/bar/.git/HEAD",
+		".pools/github.com/foo/bar/.git/objects/pack/a.pack",
+		"github.com/alice/bar/.git/HEAD",
+		"github.com/alice/bar/.git/info/attributes",
+		"github.com/alice/bar/.git/objects/info/alternates",
+	)
+}
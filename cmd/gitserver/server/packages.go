@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/maven"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npm"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// packageBranch is the branch that points at the commit of the latest materialized version of a
+// package.
+const packageBranch = "refs/heads/master"
+
+// packageRegistry returns a client for the registry of the package remote.
+func packageRegistry(remote *pkgs.Remote) (pkgs.Registry, error) {
+	// The shared external HTTP client factory caches responses, which is not useful for the
+	// archives we download.
+	doer, err := httpcli.NewFactory(
+		httpcli.NewMiddleware(httpcli.ContextErrorMiddleware),
+		httpcli.ExternalTransportOpt,
+	).Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	switch remote.ServiceType {
+	case npm.ServiceType:
+		return npm.NewClient(remote.RegistryURL, doer)
+	case maven.ServiceType:
+		return maven.NewClient(remote.RegistryURL, doer)
+	case gomodules.ServiceType:
+		return gomodules.NewClient(remote.RegistryURL, doer)
+	default:
+		return nil, errors.Errorf("unknown package registry type %q", remote.ServiceType)
+	}
+}
+
+// packageVersions returns the versions of the package remote to materialize, in the order they
+// were published.
+func packageVersions(ctx context.Context, registry pkgs.Registry, remote *pkgs.Remote) ([]pkgs.Version, error) {
+	versions, err := registry.Versions(ctx, remote.Package.Name)
+	if err != nil {
+		return nil, err
+	}
+	if remote.Package.Versions == nil {
+		return versions, nil
+	}
+
+	selected := make(map[string]bool, len(remote.Package.Versions))
+	for _, v := range remote.Package.Versions {
+		selected[v] = true
+	}
+	filtered := versions[:0]
+	for _, v := range versions {
+		if selected[v.Number] {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered, nil
+}
+
+// cloneOrUpdatePackage materializes the versions of the package of the clone URL that are not yet
+// in the repository at dir. Each version becomes a commit tagged with its version number, whose
+// tree is the published archive of that version and whose parent is the commit of the version
+// published before it. The master branch points at the commit of the latest version.
+//
+// Versions that can't be downloaded are skipped (and retried on the next update), so that a
+// single broken version doesn't prevent the others from being mirrored.
+func (s *Server) cloneOrUpdatePackage(ctx context.Context, dir GitDir, url string) error {
+	remote, err := pkgs.ParseCloneURL(url)
+	if err != nil {
+		return err
+	}
+	registry, err := packageRegistry(remote)
+	if err != nil {
+		return err
+	}
+	versions, err := packageVersions(ctx, registry, remote)
+	if err != nil {
+		return err
+	}
+
+	tags, err := packageTags(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	var parent string
+	for _, v := range versions {
+		if commit, ok := tags[v.Number]; ok {
+			parent = commit
+			continue
+		}
+
+		commit, err := s.commitPackageVersion(ctx, dir, registry, remote, v, parent)
+		if err != nil {
+			log15.Warn("failed to materialize package version", "package", remote.Package.Name, "version", v.Number, "error", err)
+			continue
+		}
+		parent = commit
+	}
+	if parent == "" {
+		return errors.Errorf("no version of %s package %s could be materialized", remote.ServiceType, remote.Package.Name)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "update-ref", packageBranch, parent)
+	cmd.Dir = string(dir)
+	if out, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "updating %s: %s", packageBranch, out)
+	}
+	return nil
+}
+
+// packageTags returns the commits of the tags of the repository at dir, by tag name.
+func packageTags(ctx context.Context, dir GitDir) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(refname) %(objectname)", "refs/tags/")
+	cmd.Dir = string(dir)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing tags: %s", out)
+	}
+
+	tags := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			tags[strings.TrimPrefix(fields[0], "refs/tags/")] = fields[1]
+		}
+	}
+	return tags, nil
+}
+
+// commitPackageVersion downloads version v of the package remote, commits its contents to the
+// repository at dir (with the given parent commit, if any) and tags the commit with the version
+// number. It returns the commit ID.
+func (s *Server) commitPackageVersion(ctx context.Context, dir GitDir, registry pkgs.Registry, remote *pkgs.Remote, v pkgs.Version, parent string) (string, error) {
+	tag := "refs/tags/" + v.Number
+	if err := exec.CommandContext(ctx, "git", "check-ref-format", tag).Run(); err != nil {
+		return "", errors.Errorf("version %q is not a valid tag name", v.Number)
+	}
+
+	tmp, err := s.tempDir("package-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	workTree := filepath.Join(tmp, "src")
+	if err := registry.Fetch(ctx, remote.Package.Name, v.Number, workTree); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(workTree, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	// Build the tree in a temporary index, so that the repository needs no work tree. Files that
+	// the archive's own .gitignore would exclude are part of the published version, too.
+	git := func(env []string, args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = string(dir)
+		cmd.Env = append(append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(tmp, "index")), env...)
+		out, err := runWith(ctx, cmd, false, nil)
+		if err != nil {
+			return "", errors.Wrapf(err, "git %s: %s", args[0], out)
+		}
+		return string(bytes.TrimSpace(out)), nil
+	}
+	if _, err := git([]string{"GIT_WORK_TREE=" + workTree}, "add", "--all", "--force", "."); err != nil {
+		return "", err
+	}
+	tree, err := git(nil, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	published := v.Published
+	if published.IsZero() {
+		published = time.Now()
+	}
+	ident := remote.ServiceType + " registry"
+	email := remote.ServiceType + "@" + registryHost(remote.RegistryURL)
+	date := fmt.Sprintf("%d +0000", published.Unix())
+	env := []string{
+		"GIT_AUTHOR_NAME=" + ident, "GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=" + ident, "GIT_COMMITTER_EMAIL=" + email, "GIT_COMMITTER_DATE=" + date,
+	}
+	args := []string{"commit-tree", tree, "-m", remote.Package.Name + " " + v.Number}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	commit, err := git(env, args...)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := git(nil, "update-ref", tag, commit); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+func registryHost(registryURL string) string {
+	u, err := url.Parse(registryURL)
+	if err != nil || u.Hostname() == "" {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// clonePackage creates a new bare repository at tmpPath and materializes the versions of the
+// package of the clone URL in it (see cloneOrUpdatePackage).
+func (s *Server) clonePackage(ctx context.Context, url, tmpPath string) error {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+	for _, args := range [][]string{
+		{"init", "--bare", "."},
+		{"config", "--add", "remote.origin.url", url},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpPath
+		if out, err := runWith(ctx, cmd, false, nil); err != nil {
+			return errors.Wrapf(err, "clone setup failed: %s", out)
+		}
+	}
+	return s.cloneOrUpdatePackage(ctx, GitDir(tmpPath), url)
+}
+
+// packageIsCloneable checks that the package of the clone URL exists and has at least one version
+// to materialize.
+func packageIsCloneable(ctx context.Context, url string) error {
+	remote, err := pkgs.ParseCloneURL(url)
+	if err != nil {
+		return err
+	}
+	registry, err := packageRegistry(remote)
+	if err != nil {
+		return err
+	}
+	versions, err := packageVersions(ctx, registry, remote)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return errors.Errorf("%s package %s has no versions to mirror", remote.ServiceType, remote.Package.Name)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeNPMRegistry is an npm registry that serves a single package, left-pad.
+type fakeNPMRegistry struct {
+	mu       sync.Mutex
+	versions map[string]string // index.js by version; "" for versions without a tarball
+}
+
+func (f *fakeNPMRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/left-pad":
+		var versions, times []string
+		for v := range f.versions {
+			versions = append(versions, `"`+v+`": {}`)
+			times = append(times, `"`+v+`": "2020-01-0`+v[len(v)-1:]+`T00:00:00.000Z"`)
+		}
+		_, _ = w.Write([]byte(`{"versions": {` + strings.Join(versions, ",") + `}, "time": {` + strings.Join(times, ",") + `}}`))
+	case strings.HasPrefix(r.URL.Path, "/left-pad/-/left-pad-"):
+		v := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/left-pad/-/left-pad-"), ".tgz")
+		body := f.versions[v]
+		if body == "" {
+			http.NotFound(w, r)
+			return
+		}
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, data := range map[string]string{"package/index.js": body, "package/.gitignore": "index.js\n"} {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+			_, _ = tw.Write([]byte(data))
+		}
+		_ = tw.Close()
+		_ = gz.Close()
+		_, _ = w.Write(buf.Bytes())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestClonePackage(t *testing.T) {
+	registry := &fakeNPMRegistry{versions: map[string]string{
+		"1.0.1": "v1\n",
+		"1.0.2": "", // published without a tarball
+		"1.0.3": "v3\n",
+	}}
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	reposDir, err := ioutil.TempDir("", "packages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(reposDir)
+	s := &Server{ReposDir: reposDir}
+	ctx := context.Background()
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = filepath.Join(reposDir, "left-pad.git")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	url := "npm+" + srv.URL + "/?package=left-pad"
+	if err := s.clonePackage(ctx, url, filepath.Join(reposDir, "left-pad.git")); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1.0.1\n1.0.3", git("tag", "--list")); diff != "" {
+		t.Errorf("tags: %s", diff)
+	}
+	if got := git("show", "master:index.js"); got != "v3" {
+		t.Errorf("got master:index.js %q, want v3", got)
+	}
+	if got := git("rev-parse", "1.0.3^"); got != git("rev-parse", "1.0.1^{commit}") {
+		t.Errorf("got parent of 1.0.3 %s, want 1.0.1", got)
+	}
+	if got := git("log", "-1", "--format=%an <%ae> %at %s", "1.0.1"); got != "npm registry <npm@127.0.0.1> 1577836800 left-pad 1.0.1" {
+		t.Errorf("got commit %q", got)
+	}
+
+	// Updating materializes new versions on top of the existing ones.
+	registry.mu.Lock()
+	registry.versions["1.0.4"] = "v4\n"
+	registry.mu.Unlock()
+	before := git("rev-parse", "1.0.3^{commit}")
+	if err := s.cloneOrUpdatePackage(ctx, GitDir(filepath.Join(reposDir, "left-pad.git")), url); err != nil {
+		t.Fatal(err)
+	}
+	if got := git("rev-parse", "1.0.3^{commit}"); got != before {
+		t.Errorf("1.0.3 changed from %s to %s", before, got)
+	}
+	if got := git("rev-parse", "master^"); got != before {
+		t.Errorf("got parent of master %s, want 1.0.3 (%s)", got, before)
+	}
+	if got := git("show", "master:index.js"); got != "v4" {
+		t.Errorf("got master:index.js %q, want v4", got)
+	}
+
+	// Selecting versions that don't exist fails the clone.
+	url = "npm+" + srv.URL + "/?package=left-pad&version=9.9.9"
+	if err := packageIsCloneable(ctx, url); err == nil {
+		t.Error("packageIsCloneable: got nil error for missing version")
+	}
+}
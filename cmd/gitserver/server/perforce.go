@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// perforceBranch is the ref that git-p4 imports the changelists of a depot path into. It is the
+// only branch of a repository converted from Perforce.
+const perforceBranch = "refs/heads/master"
+
+// perforceCloneCmd returns the command that converts the depot path of the Perforce clone URL
+// into a new bare repository at tmpPath. Like refspecOverridesCloneCmd, it initializes the
+// repository itself and returns the (long-running) import.
+//
+// Every changelist becomes a commit whose message ends with a git-p4 trailer recording the
+// changelist number (see perforce.ChangelistFromCommitMessage).
+func perforceCloneCmd(ctx context.Context, url, tmpPath string) (*exec.Cmd, error) {
+	remote, err := perforce.ParseCloneURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+	for _, args := range [][]string{
+		{"init", "--bare", "."},
+		{"config", "--add", "remote.origin.url", url},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpPath
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "p4", "sync", "--branch="+perforceBranch, remote.DepotPath+"@all")
+	cmd.Dir = tmpPath
+	cmd.Env = append(os.Environ(), remote.Env()...)
+	return cmd, nil
+}
+
+// perforceFetchCmd returns the command that incrementally imports the changelists submitted to
+// the depot path of the Perforce clone URL since the last import. git-p4 determines the depot
+// path and the last imported changelist from the git-p4 trailer of the tip of perforceBranch.
+func perforceFetchCmd(ctx context.Context, url string) *exec.Cmd {
+	remote, err := perforce.ParseCloneURL(url)
+	if err != nil {
+		log15.Error("failed to parse Perforce clone URL", "error", err)
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "p4", "sync", "--branch="+perforceBranch)
+	cmd.Env = append(os.Environ(), remote.Env()...)
+	return cmd
+}
+
+// perforceIsCloneable checks that the depot path of the Perforce clone URL exists and has at
+// least one submitted changelist that the configured user may read.
+func perforceIsCloneable(ctx context.Context, url string) error {
+	remote, err := perforce.ParseCloneURL(url)
+	if err != nil {
+		return err
+	}
+	_, err = perforce.NewClient(remote.Port, remote.User, remote.Password).LatestChange(ctx, remote.DepotPath)
+	return err
+}
@@ -0,0 +1,418 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// Push mirrors are Git remotes that repositories are copied to, configured in
+// the "git.pushMirrors" site configuration property. After each successful
+// clone or update of a repository (see cloneRepo and doRepoUpdate), gitserver
+// pushes the repository to each of its push mirrors in the background.
+//
+// At most one goroutine pushes a repository to a push mirror at a time. An
+// update that occurs while a push is running triggers another push once it
+// is done, unless the running push started after the update. A failed push
+// is retried with exponential backoff, up to pushMirrorMaxAttempts times.
+// After that, the next update of the repository tries again.
+//
+// The status of the push mirrors of a repository is recorded in the
+// pushMirrorStatusFile of its GIT_DIR, so that it survives restarts (but not
+// reclones).
+
+// pushMirrorStatusFile is the name of the file in GIT_DIR that records the
+// status of the push mirrors of a repository, keyed by their URL.
+const pushMirrorStatusFile = "sg_push_mirrors.json"
+
+// pushMirrorMaxAttempts is the maximum number of consecutive attempts to push
+// an update to a push mirror.
+const pushMirrorMaxAttempts = 8
+
+// pushMirrorRetryInterval is the time before the first retry of a failed
+// push. It doubles with each retry, up to pushMirrorMaxRetryInterval.
+var pushMirrorRetryInterval = time.Minute
+
+const pushMirrorMaxRetryInterval = time.Hour
+
+// pushMirrorAskpassScript is the GIT_ASKPASS program that git push asks for
+// the credentials of a push mirror. Like svnAskpassScript, it prints them from
+// the environment so that they never end up on the command line or on disk.
+const pushMirrorAskpassScript = `#!/bin/sh
+case "$1" in
+Username*) echo "$PUSH_MIRROR_USERNAME" ;;
+*) echo "$PUSH_MIRROR_PASSWORD" ;;
+esac
+`
+
+var (
+	pushMirrorAskpassOnce sync.Once
+	pushMirrorAskpassPath string
+	pushMirrorAskpassErr  error
+)
+
+// pushMirrorAskpass returns the path of the askpass program, writing it to a
+// temporary directory the first time it is called.
+func pushMirrorAskpass() (string, error) {
+	pushMirrorAskpassOnce.Do(func() {
+		dir, err := ioutil.TempDir("", "gitserver-push-mirror")
+		if err != nil {
+			pushMirrorAskpassErr = err
+			return
+		}
+		pushMirrorAskpassPath = filepath.Join(dir, "askpass")
+		pushMirrorAskpassErr = ioutil.WriteFile(pushMirrorAskpassPath, []byte(pushMirrorAskpassScript), 0700)
+	})
+	if pushMirrorAskpassErr != nil {
+		return "", errors.Wrap(pushMirrorAskpassErr, "failed to write push mirror askpass program")
+	}
+	return pushMirrorAskpassPath, nil
+}
+
+// defaultPushMirrorRefspecs are the refspecs that are pushed to push mirrors
+// that don't configure any: all branches and tags, overwriting the mirror's.
+var defaultPushMirrorRefspecs = []string{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+
+var pushMirrorConfigs = conf.Cached(func() interface{} {
+	return compilePushMirrorConfigs(conf.Get().GitPushMirrors)
+})
+
+type pushMirrorConfig struct {
+	*schema.PushMirror
+	repos *regexp.Regexp
+}
+
+func compilePushMirrorConfigs(c []*schema.PushMirror) []pushMirrorConfig {
+	var configs []pushMirrorConfig
+	for _, m := range c {
+		repos, err := regexp.Compile(m.Repos)
+		if err != nil {
+			log15.Error("Invalid repos pattern of push mirror", "repos", m.Repos, "error", err)
+			continue
+		}
+		configs = append(configs, pushMirrorConfig{PushMirror: m, repos: repos})
+	}
+	return configs
+}
+
+// A pushMirror is a push mirror of a repository.
+type pushMirror struct {
+	url      string // the remote URL, without credentials
+	username string
+	password string
+	refspecs []string
+}
+
+// pushMirrorsOf returns the push mirrors of the repository. If several
+// configured push mirrors of the repository have the same URL, the first
+// applies.
+func pushMirrorsOf(configs []pushMirrorConfig, repo api.RepoName) []*pushMirror {
+	var mirrors []*pushMirror
+	seen := map[string]bool{}
+	for _, c := range configs {
+		match := c.repos.FindStringSubmatch(string(repo))
+		if match == nil {
+			continue
+		}
+		oldnew := []string{"{repo}", string(repo)}
+		for i, name := range c.repos.SubexpNames() {
+			if name != "" {
+				oldnew = append(oldnew, "{"+name+"}", match[i])
+			}
+		}
+		m := newPushMirror(strings.NewReplacer(oldnew...).Replace(c.Url), c.Username, c.Password)
+		if seen[m.url] {
+			continue
+		}
+		seen[m.url] = true
+		m.refspecs = c.Refspecs
+		if len(m.refspecs) == 0 {
+			m.refspecs = defaultPushMirrorRefspecs
+		}
+		mirrors = append(mirrors, m)
+	}
+	return mirrors
+}
+
+// newPushMirror returns the push mirror with the remote URL. The credentials
+// (from the URL, or the username and password) are only used to push.
+func newPushMirror(rawurl, username, password string) *pushMirror {
+	m := &pushMirror{url: rawurl, username: username, password: password}
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" || u.User == nil {
+		// Local paths and scp-like SSH URLs have no credentials.
+		return m
+	}
+	m.username = u.User.Username()
+	if p, ok := u.User.Password(); ok {
+		m.password = p
+	}
+	u.User = nil
+	m.url = u.String()
+	return m
+}
+
+// redact removes the credentials of the push mirror from the message. A
+// username without a password is usually an access token, so it is redacted
+// too.
+func (m *pushMirror) redact(message string) string {
+	secret := m.password
+	if secret == "" {
+		secret = m.username
+	}
+	if secret != "" {
+		message = strings.Replace(message, secret, "<redacted>", -1)
+	}
+	return message
+}
+
+func pushMirrorKey(repo api.RepoName, m *pushMirror) string {
+	return string(repo) + " " + m.url
+}
+
+// pushMirrorRetryDelay returns the time to wait after the given number of
+// consecutive failed pushes.
+func pushMirrorRetryDelay(failures int) time.Duration {
+	d := pushMirrorRetryInterval
+	for i := 1; i < failures && d < pushMirrorMaxRetryInterval; i++ {
+		d *= 2
+	}
+	if d > pushMirrorMaxRetryInterval {
+		d = pushMirrorMaxRetryInterval
+	}
+	return d
+}
+
+// schedulePushMirrors pushes the repository to its push mirrors in the
+// background. It is called after each successful update of the repository.
+func (s *Server) schedulePushMirrors(repo api.RepoName) {
+	repo = protocol.NormalizeRepo(repo)
+	mirrors := pushMirrorsOf(pushMirrorConfigs().([]pushMirrorConfig), repo)
+	if len(mirrors) == 0 {
+		return
+	}
+	dir := s.dir(repo)
+	now := time.Now()
+
+	s.pushMirrorsMu.Lock()
+	defer s.pushMirrorsMu.Unlock()
+	if s.pushMirrorsRunning == nil {
+		s.pushMirrorsRunning = map[string]bool{}
+		s.pushMirrorsPending = map[string]time.Time{}
+	}
+	for _, m := range mirrors {
+		err := updatePushMirrorStatus(dir, m, func(status *protocol.PushMirrorStatus) {
+			if status.PendingSince == nil {
+				status.PendingSince = &now
+			}
+		})
+		if err != nil {
+			log15.Warn("Failed to update push mirror status", "repo", repo, "mirror", m.url, "error", err)
+		}
+
+		key := pushMirrorKey(repo, m)
+		if s.pushMirrorsRunning[key] {
+			if _, ok := s.pushMirrorsPending[key]; !ok {
+				s.pushMirrorsPending[key] = now
+			}
+			continue
+		}
+		s.pushMirrorsRunning[key] = true
+		go s.runPushMirror(repo, m)
+	}
+}
+
+// runPushMirror pushes the repository to the push mirror until the push
+// succeeds with no update pending, or it failed pushMirrorMaxAttempts times.
+func (s *Server) runPushMirror(repo api.RepoName, m *pushMirror) {
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	dir := s.dir(repo)
+	key := pushMirrorKey(repo, m)
+	done := func() {
+		s.pushMirrorsMu.Lock()
+		delete(s.pushMirrorsRunning, key)
+		delete(s.pushMirrorsPending, key)
+		s.pushMirrorsMu.Unlock()
+	}
+
+	failures := 0
+	for {
+		started := time.Now()
+		err := pushToMirror(ctx, dir, m)
+		if ctx.Err() != nil {
+			// The server is stopping.
+			done()
+			return
+		}
+
+		var retryDelay time.Duration
+		if err != nil {
+			log15.Warn("Failed to push to push mirror", "repo", repo, "mirror", m.url, "error", m.redact(err.Error()))
+			failures++
+			if failures < pushMirrorMaxAttempts {
+				retryDelay = pushMirrorRetryDelay(failures)
+			}
+		} else {
+			failures = 0
+		}
+
+		s.pushMirrorsMu.Lock()
+		// An update that occurred after the push started still needs to be
+		// pushed.
+		pending, ok := s.pushMirrorsPending[key]
+		delete(s.pushMirrorsPending, key)
+		pushAgain := err == nil && ok && !pending.Before(started)
+		uerr := updatePushMirrorStatus(dir, m, func(status *protocol.PushMirrorStatus) {
+			status.LastAttempt = &started
+			status.NextRetry = nil
+			if err != nil {
+				status.LastError = m.redact(err.Error())
+				status.FailedAttempts++
+				if retryDelay > 0 {
+					next := time.Now().Add(retryDelay)
+					status.NextRetry = &next
+				}
+				return
+			}
+			status.LastSuccess = &started
+			status.LastError = ""
+			status.FailedAttempts = 0
+			status.PendingSince = nil
+			if pushAgain {
+				status.PendingSince = &pending
+			}
+		})
+		if uerr != nil {
+			log15.Warn("Failed to update push mirror status", "repo", repo, "mirror", m.url, "error", uerr)
+		}
+		if !pushAgain && retryDelay == 0 {
+			delete(s.pushMirrorsRunning, key)
+			s.pushMirrorsMu.Unlock()
+			return
+		}
+		s.pushMirrorsMu.Unlock()
+
+		if retryDelay > 0 {
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+				done()
+				return
+			}
+		}
+	}
+}
+
+// pushToMirror pushes the refspecs of the push mirror from the repository in
+// dir.
+func pushToMirror(ctx context.Context, dir GitDir, m *pushMirror) error {
+	if !repoCloned(dir) {
+		return errors.New("repository is not cloned")
+	}
+	askpass, err := pushMirrorAskpass()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, longGitCommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", append([]string{"push", m.url}, m.refspecs...)...)
+	cmd.Dir = string(dir)
+	configureRemoteGitCommand(cmd, tlsExternal().(*tlsConfig))
+	// The credentials are passed in the environment, where only the askpass
+	// program reads them. It replaces the GIT_ASKPASS set by the remote options,
+	// because the last value of a variable wins.
+	cmd.Env = append(cmd.Env,
+		"GIT_ASKPASS="+askpass,
+		"PUSH_MIRROR_USERNAME="+m.username,
+		"PUSH_MIRROR_PASSWORD="+m.password,
+	)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "git push failed with output %q", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// pushMirrorStatuses returns the status of the push mirrors of the
+// repository.
+func (s *Server) pushMirrorStatuses(repo api.RepoName) ([]*protocol.PushMirrorStatus, error) {
+	mirrors := pushMirrorsOf(pushMirrorConfigs().([]pushMirrorConfig), protocol.NormalizeRepo(repo))
+	if len(mirrors) == 0 {
+		return nil, nil
+	}
+
+	s.pushMirrorsMu.Lock()
+	statuses, err := readPushMirrorStatus(s.dir(repo))
+	s.pushMirrorsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*protocol.PushMirrorStatus, len(mirrors))
+	for i, m := range mirrors {
+		status := statuses[m.url]
+		if status == nil {
+			status = &protocol.PushMirrorStatus{}
+		}
+		status.URL = m.url
+		status.Refspecs = m.refspecs
+		result[i] = status
+	}
+	return result, nil
+}
+
+func readPushMirrorStatus(dir GitDir) (map[string]*protocol.PushMirrorStatus, error) {
+	statuses := map[string]*protocol.PushMirrorStatus{}
+	data, err := ioutil.ReadFile(dir.Path(pushMirrorStatusFile))
+	if os.IsNotExist(err) {
+		return statuses, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &statuses); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", pushMirrorStatusFile)
+	}
+	return statuses, nil
+}
+
+// updatePushMirrorStatus updates the recorded status of the push mirror of
+// the repository in dir. The caller must hold s.pushMirrorsMu.
+func updatePushMirrorStatus(dir GitDir, m *pushMirror, update func(*protocol.PushMirrorStatus)) error {
+	if !repoCloned(dir) {
+		return nil
+	}
+	statuses, err := readPushMirrorStatus(dir)
+	if err != nil {
+		// Start over rather than never recording the status again.
+		log15.Warn("Resetting push mirror status", "dir", dir, "error", err)
+		statuses = map[string]*protocol.PushMirrorStatus{}
+	}
+	status := statuses[m.url]
+	if status == nil {
+		status = &protocol.PushMirrorStatus{URL: m.url}
+		statuses[m.url] = status
+	}
+	update(status)
+	data, err := json.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+	_, err = updateFileIfDifferent(dir.Path(pushMirrorStatusFile), data)
+	return err
+}
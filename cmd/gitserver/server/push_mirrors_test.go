@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPushMirrorsOf(t *testing.T) {
+	configs := compilePushMirrorConfigs([]*schema.PushMirror{
+		{Repos: "^github\\.com/acme/(?P<name>.+)$", Url: "https://backup.example.com/acme/{name}.git", Username: "u", Password: "p"},
+		{Repos: "^github\\.com/acme/api$", Url: "https://backup.example.com/acme/api.git", Refspecs: []string{"refs/heads/master"}},
+		{Repos: "^github\\.com/acme/api$", Url: "https://token@dr.example.com/{repo}", Refspecs: []string{"refs/heads/master"}},
+		{Repos: "(", Url: "/invalid"},
+	})
+
+	if got := pushMirrorsOf(configs, "github.com/other/api"); len(got) != 0 {
+		t.Errorf("got push mirrors %+v, want none", got)
+	}
+
+	got := pushMirrorsOf(configs, "github.com/acme/api")
+	want := []*pushMirror{
+		{
+			url:      "https://backup.example.com/acme/api.git",
+			username: "u",
+			password: "p",
+			refspecs: defaultPushMirrorRefspecs,
+		},
+		{
+			url:      "https://dr.example.com/github.com/acme/api",
+			username: "token",
+			refspecs: []string{"refs/heads/master"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got push mirrors %+v, want %+v", got, want)
+	}
+
+	message := "fatal: Authentication failed for 'https://dr.example.com/github.com/acme/api/': token expired"
+	if got, want := got[1].redact(message), "fatal: Authentication failed for 'https://dr.example.com/github.com/acme/api/': <redacted> expired"; got != want {
+		t.Errorf("got redacted message %q, want %q", got, want)
+	}
+}
+
+func TestPushMirrorAskpass(t *testing.T) {
+	askpass, err := pushMirrorAskpass()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for prompt, want := range map[string]string{
+		"Username for 'https://backup.example.com': ":   "u",
+		"Password for 'https://u@backup.example.com': ": "p",
+	} {
+		cmd := exec.Command(askpass, prompt)
+		cmd.Env = []string{"PUSH_MIRROR_USERNAME=u", "PUSH_MIRROR_PASSWORD=p"}
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(out)); got != want {
+			t.Errorf("prompt %q: got %q, want %q", prompt, got, want)
+		}
+	}
+}
+
+func TestPushMirrorRetryDelay(t *testing.T) {
+	for failures, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 7: time.Hour, 100: time.Hour} {
+		if got := pushMirrorRetryDelay(failures); got != want {
+			t.Errorf("%d failures: got %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestSchedulePushMirrors(t *testing.T) {
+	defer func(orig func() interface{}) { pushMirrorConfigs = orig }(pushMirrorConfigs)
+	defer func(orig time.Duration) { pushMirrorRetryInterval = orig }(pushMirrorRetryInterval)
+	pushMirrorRetryInterval = 10 * time.Millisecond
+
+	remotes, cleanup1 := tmpDir(t)
+	defer cleanup1()
+	reposDir, cleanup2 := tmpDir(t)
+	defer cleanup2()
+
+	git := func(dir string, arg ...string) string {
+		t.Helper()
+		c := exec.Command("git", arg...)
+		c.Dir = dir
+		c.Env = append(os.Environ(),
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		)
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %s\n%s", strings.Join(arg, " "), err, b)
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	// The push mirrors of repositories are local bare repositories.
+	mirrors := filepath.Join(remotes, "mirrors")
+	pushMirrorConfigs = func() interface{} {
+		return compilePushMirrorConfigs([]*schema.PushMirror{{Repos: "^example\\.com/(?P<name>.+)$", Url: mirrors + "/{name}.git"}})
+	}
+
+	upstream := filepath.Join(remotes, "upstream")
+	git(remotes, "init", upstream)
+	git(upstream, "commit", "--allow-empty", "-m", "first")
+	git(upstream, "tag", "v1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{ReposDir: reposDir, ctx: ctx, cancel: cancel}
+	defer s.Stop()
+	repo := api.RepoName("example.com/foo")
+	dir := s.dir(repo)
+	git(remotes, "clone", "--bare", upstream, string(dir))
+
+	waitForStatus := func(done func(*protocol.PushMirrorStatus) bool) *protocol.PushMirrorStatus {
+		t.Helper()
+		for i := 0; i < 1000; i++ {
+			statuses, err := s.pushMirrorStatuses(repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(statuses) != 1 {
+				t.Fatalf("got %d push mirror statuses, want 1", len(statuses))
+			}
+			s.pushMirrorsMu.Lock()
+			running := s.pushMirrorsRunning[pushMirrorKey(repo, &pushMirror{url: statuses[0].URL})]
+			s.pushMirrorsMu.Unlock()
+			if done(statuses[0]) && !running {
+				return statuses[0]
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("timed out waiting for push mirror")
+		return nil
+	}
+
+	// Pushes to a missing remote fail until they are given up.
+	s.schedulePushMirrors(repo)
+	status := waitForStatus(func(status *protocol.PushMirrorStatus) bool {
+		return status.FailedAttempts == pushMirrorMaxAttempts
+	})
+	if status.URL != mirrors+"/foo.git" || status.LastError == "" || status.LastSuccess != nil || status.NextRetry != nil || status.PendingSince == nil {
+		t.Fatalf("unexpected status after failures: %+v", status)
+	}
+
+	// The next update pushes all branches and tags.
+	mirror := filepath.Join(mirrors, "foo.git")
+	git(remotes, "init", "--bare", mirror)
+	s.schedulePushMirrors(repo)
+	status = waitForStatus(func(status *protocol.PushMirrorStatus) bool { return status.LastSuccess != nil })
+	if status.LastError != "" || status.FailedAttempts != 0 || status.PendingSince != nil {
+		t.Fatalf("unexpected status after push: %+v", status)
+	}
+	if got, want := git(mirror, "rev-parse", "refs/heads/master", "refs/tags/v1"), git(string(dir), "rev-parse", "refs/heads/master", "refs/tags/v1"); got != want {
+		t.Fatalf("got mirror refs %q, want %q", got, want)
+	}
+
+	// Rewritten history is force-pushed.
+	git(upstream, "commit", "--amend", "--allow-empty", "-m", "rewritten")
+	git(string(dir), "fetch", "--force", upstream, "+refs/heads/*:refs/heads/*")
+	lastSuccess := *status.LastSuccess
+	s.schedulePushMirrors(repo)
+	waitForStatus(func(status *protocol.PushMirrorStatus) bool { return status.LastSuccess.After(lastSuccess) })
+	if got, want := git(mirror, "rev-parse", "refs/heads/master"), git(upstream, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("got mirror master %s, want %s", got, want)
+	}
+}
@@ -47,6 +47,12 @@ func (s *Server) repoInfo(ctx context.Context, repo api.RepoName) (*protocol.Rep
 		} else {
 			resp.LastChanged = &lastChanged
 		}
+
+		if pushMirrors, err := s.pushMirrorStatuses(repo); err != nil {
+			log15.Warn("error getting push mirror status", "repo", repo, "err", err)
+		} else {
+			resp.PushMirrors = pushMirrors
+		}
 	}
 	return &resp, nil
 }
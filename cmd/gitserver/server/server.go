@@ -33,6 +33,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/svn"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -138,6 +141,20 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// objectPoolLocks maps the directory of each object pool to the
+	// *sync.Mutex returned by s.objectPoolLock.
+	objectPoolLocks sync.Map
+
+	// pushMirrorsMu protects the maps below and the push mirror status files
+	// of repositories.
+	pushMirrorsMu sync.Mutex
+	// pushMirrorsRunning contains the keys (see pushMirrorKey) of the push
+	// mirrors that a goroutine is pushing to, or waiting to retry.
+	pushMirrorsRunning map[string]bool
+	// pushMirrorsPending maps the keys of running push mirrors to the time of
+	// the first update that occurred while they were running.
+	pushMirrorsPending map[string]time.Time
 }
 
 type locks struct {
@@ -319,11 +336,12 @@ func (s *Server) tempDir(prefix string) (name string, err error) {
 }
 
 func (s *Server) ignorePath(path string) bool {
-	// We ignore any path which starts with .tmp in ReposDir
+	// We ignore any path which starts with .tmp in ReposDir, and the object
+	// pools.
 	if filepath.Dir(path) != s.ReposDir {
 		return false
 	}
-	return strings.HasPrefix(filepath.Base(path), tempDirName)
+	return strings.HasPrefix(filepath.Base(path), tempDirName) || filepath.Base(path) == objectPoolsDirName
 }
 
 func (s *Server) handleIsRepoCloneable(w http.ResponseWriter, r *http.Request) {
@@ -403,7 +421,7 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		// optimistically, we assume that our cloning attempt might
 		// succeed.
 		resp.CloneInProgress = true
-		_, err := s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true})
+		_, err := s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true, ObjectPool: req.ObjectPool})
 		if err != nil {
 			log15.Warn("error cloning repo", "repo", req.Repo, "err", err)
 			resp.Error = err.Error()
@@ -412,6 +430,12 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		resp.Cloned = true
 		var statusErr, updateErr error
 
+		if req.ObjectPool != nil {
+			if err := s.setObjectPool(ctx, dir, req.ObjectPool); err != nil {
+				log15.Warn("failed to set object pool", "repo", req.Repo, "pool", req.ObjectPool.Name, "error", err)
+			}
+		}
+
 		if debounce(req.Repo, req.Since) {
 			updateErr = s.doRepoUpdate(ctx, req.Repo, req.URL)
 		}
@@ -724,6 +748,11 @@ type cloneOptions struct {
 
 	// Overwrite will overwrite the existing clone.
 	Overwrite bool
+
+	// ObjectPool is the object pool of the repository's fork network. If
+	// set, the repository is cloned with the pool as its Git alternate (see
+	// object_pools.go).
+	ObjectPool *protocol.ObjectPool
 }
 
 // cloneRepo issues a git clone command for the given repo. It is
@@ -807,25 +836,58 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
-		var cmd *exec.Cmd
-		if useRefspecOverrides() {
-			cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
-			if err != nil {
-				return err
+		var poolDir GitDir
+
+		if pkgs.IsCloneURL(url) {
+			// Packages are materialized from their registry by gitserver itself rather than
+			// cloned with a git command.
+			log15.Info("materializing package", "repo", repo, "tmp", tmpPath, "dst", dstPath)
+			if err := s.clonePackage(ctx, url, tmpPath); err != nil {
+				return errors.Wrap(err, "clone failed")
 			}
 		} else {
-			cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
-		}
-		// see issue #7322: skip LFS content in repositories with Git LFS configured
-		cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
-		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
-
-		pr, pw := io.Pipe()
-		defer pw.Close()
-		go readCloneProgress(redactor, lock, pr)
-
-		if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
-			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			var cmd *exec.Cmd
+			if perforce.IsCloneURL(url) {
+				cmd, err = perforceCloneCmd(ctx, url, tmpPath)
+				if err != nil {
+					return err
+				}
+			} else if svn.IsCloneURL(url) {
+				cmd, err = svnCloneCmd(ctx, url, tmpPath)
+				if err != nil {
+					return err
+				}
+			} else if useRefspecOverrides() {
+				cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
+				if err != nil {
+					return err
+				}
+			} else {
+				args := []string{"clone", "--mirror", "--progress"}
+				if opts != nil && opts.ObjectPool != nil {
+					// Cloning without the pool is better than not cloning, so
+					// failing to create the pool is not fatal.
+					if poolDir, err = s.ensureObjectPool(ctx, opts.ObjectPool); err != nil {
+						log15.Warn("failed to use object pool, cloning without it", "repo", repo, "pool", opts.ObjectPool.Name, "error", err)
+					} else {
+						args = append(args, "--reference", string(poolDir))
+					}
+				}
+				cmd = exec.CommandContext(ctx, "git", append(args, url, tmpPath)...)
+			}
+			// see issue #7322: skip LFS content in repositories with Git LFS configured
+			cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
+			log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
+
+			pr, pw := io.Pipe()
+			defer pw.Close()
+			go readCloneProgress(redactor, lock, pr)
+
+			// git-svn authenticates with its own GIT_ASKPASS program, which the remote options would
+			// override.
+			if output, err := runWith(ctx, cmd, !svn.IsCloneURL(url), pw); err != nil {
+				return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			}
 		}
 
 		removeBadRefs(ctx, tmp)
@@ -840,6 +902,14 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 			return err
 		}
 
+		// This must be the last step before the rename, since the relative
+		// alternates path is only valid once the clone is at dstPath.
+		if poolDir != "" {
+			if err := useObjectPool(tmp, GitDir(dstPath), poolDir, opts.ObjectPool.Name); err != nil {
+				return err
+			}
+		}
+
 		if overwrite {
 			// remove the current repo by putting it into our temporary directory
 			err := renameAndSync(dstPath, filepath.Join(filepath.Dir(tmpPath), "old"))
@@ -857,6 +927,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 
 		log15.Info("repo cloned", "repo", repo)
 		repoClonedCounter.Inc()
+		s.schedulePushMirrors(repo)
 
 		return nil
 	}
@@ -988,6 +1059,15 @@ func (s *Server) isCloneable(ctx context.Context, url string) error {
 	if testRepoExists != nil {
 		return testRepoExists(ctx, url)
 	}
+	if perforce.IsCloneURL(url) {
+		return perforceIsCloneable(ctx, url)
+	}
+	if svn.IsCloneURL(url) {
+		return svnIsCloneable(ctx, url)
+	}
+	if pkgs.IsCloneURL(url) {
+		return packageIsCloneable(ctx, url)
+	}
 
 	cmd := exec.CommandContext(ctx, "git", args...)
 	out, err := runWithRemoteOpts(ctx, cmd, nil)
@@ -1082,6 +1162,9 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, url string
 			s.repoUpdateLocksMu.Unlock()
 
 			err = s.doRepoUpdate2(repo, url)
+			if err == nil {
+				s.schedulePushMirrors(repo)
+			}
 		})
 	}()
 
@@ -1302,6 +1385,27 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		}
 	}
 
+	if pkgs.IsCloneURL(url) {
+		// Packages have no upstream Git repository to fetch from. New versions are materialized
+		// from their registry instead.
+		if err := s.cloneOrUpdatePackage(ctx, dir, url); err != nil {
+			log15.Error("Failed to update", "repo", repo, "error", err)
+			return errors.Wrap(err, "failed to update")
+		}
+		if err := setLastChanged(dir); err != nil {
+			log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
+		}
+		return nil
+	}
+
+	// Fetch the upstream of the fork network first, so that the objects the
+	// repo has in common with it are stored in the object pool.
+	if pool := repoObjectPool(dir); pool != "" {
+		if err := s.updateObjectPool(ctx, pool); err != nil {
+			log15.Warn("Failed to update object pool", "repo", repo, "pool", pool, "error", err)
+		}
+	}
+
 	configRemoteOpts := true
 	var cmd *exec.Cmd
 	if customCmd := customFetchCmd(ctx, url); customCmd != nil {
@@ -1332,6 +1436,13 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
 	}
 
+	if perforce.IsCloneURL(url) || svn.IsCloneURL(url) {
+		// Repositories converted from Perforce have a single branch (see perforceBranch), and
+		// those converted from Subversion use the master branch for trunk. Neither has a Git
+		// remote that we could ask for its HEAD.
+		return nil
+	}
+
 	headBranch := "master"
 
 	// try to fetch HEAD from origin
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/svn"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// svnRemote is the name of the git-svn remote that the Subversion repository of a converted
+// repository is configured as.
+const svnRemote = "svn"
+
+// svnAskpassScript is the GIT_ASKPASS program that git-svn asks for the password of the
+// Subversion user. It prints the password from the environment so that it never ends up on the
+// command line or on disk.
+const svnAskpassScript = `#!/bin/sh
+echo "$SVN_PASSWORD"
+`
+
+// svnAuthorsProgScript is the git-svn --authors-prog program that maps a Subversion username to
+// a Git identity. It looks the username up in $SVN_AUTHORS (in the format of a git-svn authors
+// file, see svn.FormatAuthorsFile) and falls back to an identity derived from the username, so
+// that revisions by unmapped users don't fail the import.
+const svnAuthorsProgScript = `#!/bin/sh
+identity=$(printf '%s\n' "$SVN_AUTHORS" | awk -v user="$1" 'index($0, user " = ") == 1 { print substr($0, length(user) + 4); exit }')
+echo "${identity:-$1 <$1>}"
+`
+
+// svnConfigs returns the configs of all Subversion external services. It is a variable so that
+// tests can mock it.
+var svnConfigs = conf.SVNConfigs
+
+var (
+	svnHelpersOnce sync.Once
+	svnHelpersDir  string
+	svnHelpersErr  error
+)
+
+// svnHelpers returns the paths of the askpass and authors programs, writing them to a temporary
+// directory the first time it is called.
+func svnHelpers() (askpass, authorsProg string, err error) {
+	svnHelpersOnce.Do(func() {
+		svnHelpersDir, svnHelpersErr = ioutil.TempDir("", "gitserver-svn")
+		if svnHelpersErr != nil {
+			return
+		}
+		for name, script := range map[string]string{
+			"askpass":      svnAskpassScript,
+			"authors-prog": svnAuthorsProgScript,
+		} {
+			if svnHelpersErr = ioutil.WriteFile(filepath.Join(svnHelpersDir, name), []byte(script), 0700); svnHelpersErr != nil {
+				return
+			}
+		}
+	})
+	if svnHelpersErr != nil {
+		return "", "", errors.Wrap(svnHelpersErr, "failed to write git-svn helpers")
+	}
+	return filepath.Join(svnHelpersDir, "askpass"), filepath.Join(svnHelpersDir, "authors-prog"), nil
+}
+
+// svnAuthors returns the author mapping of the Subversion external service that remote belongs
+// to. A repository whose external service can't be determined is imported without a mapping.
+//
+// The repository belongs to the external service with the longest URL that is the repository's URL
+// or one of its parent directories.
+func svnAuthors(ctx context.Context, remote *svn.Remote) map[string]string {
+	configs, err := svnConfigs(ctx)
+	if err != nil {
+		log15.Warn("failed to get Subversion external service configs, importing without author mapping", "error", err)
+		return nil
+	}
+	var (
+		authors map[string]string
+		longest = -1
+	)
+	repoURL := strings.TrimSuffix(remote.URL, "/")
+	for _, c := range configs {
+		root := strings.TrimSuffix(c.Url, "/")
+		if (repoURL == root || strings.HasPrefix(repoURL, root+"/")) && len(root) > longest {
+			authors, longest = c.Authors, len(root)
+		}
+	}
+	return authors
+}
+
+// svnFetchCmd returns the git svn fetch command that imports the revisions of remote that are not
+// yet in the repository.
+func svnFetchCmd(ctx context.Context, remote *svn.Remote) (*exec.Cmd, error) {
+	askpass, authorsProg, err := svnHelpers()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"svn", "fetch", svnRemote, "--authors-prog=" + authorsProg, "--no-auth-cache"}
+	if remote.Username != "" {
+		args = append(args, "--username="+remote.Username)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_ASKPASS="+askpass,
+		"SVN_PASSWORD="+remote.Password,
+		"SVN_AUTHORS="+string(svn.FormatAuthorsFile(svnAuthors(ctx, remote))),
+	)
+	return cmd, nil
+}
+
+// svnRemoteConfig returns the git config entries that configure remote as a git-svn remote.
+// Trunk is imported into the master branch, and each branch and tag directory into a branch or
+// tag of the same name.
+func svnRemoteConfig(remote *svn.Remote) [][2]string {
+	config := [][2]string{
+		{"svn-remote." + svnRemote + ".url", remote.URL},
+		{"svn-remote." + svnRemote + ".fetch", remote.Layout.Trunk + ":refs/heads/master"},
+	}
+	for _, b := range remote.Layout.Branches {
+		config = append(config, [2]string{"svn-remote." + svnRemote + ".branches", b + "/*:refs/heads/*"})
+	}
+	for _, t := range remote.Layout.Tags {
+		config = append(config, [2]string{"svn-remote." + svnRemote + ".tags", t + "/*:refs/tags/*"})
+	}
+	return config
+}
+
+// svnCloneCmd returns the command that converts the Subversion repository of the clone URL into a
+// new bare repository at tmpPath. Like perforceCloneCmd, it initializes the repository itself and
+// returns the (long-running) import.
+//
+// Every revision becomes a commit whose message ends with a git-svn-id trailer recording the
+// revision number (see svn.RevisionFromCommitMessage).
+func svnCloneCmd(ctx context.Context, url, tmpPath string) (*exec.Cmd, error) {
+	remote, err := svn.ParseCloneURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+	setup := [][]string{{"init", "--bare", "."}, {"config", "--add", "remote.origin.url", url}}
+	for _, kv := range svnRemoteConfig(remote) {
+		setup = append(setup, []string{"config", "--add", kv[0], kv[1]})
+	}
+	for _, args := range setup {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpPath
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+	}
+
+	cmd, err := svnFetchCmd(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = tmpPath
+	return cmd, nil
+}
+
+// svnUpdateCmd returns the command that incrementally imports the revisions committed to the
+// Subversion repository of the clone URL since the last import.
+func svnUpdateCmd(ctx context.Context, url string) *exec.Cmd {
+	remote, err := svn.ParseCloneURL(url)
+	if err != nil {
+		log15.Error("failed to parse Subversion clone URL", "error", err)
+		return nil
+	}
+	cmd, err := svnFetchCmd(ctx, remote)
+	if err != nil {
+		log15.Error("failed to create git svn fetch command", "error", err)
+		return nil
+	}
+	return cmd
+}
+
+// svnIsCloneable checks that the Subversion repository of the clone URL exists and that the
+// configured user may read it.
+func svnIsCloneable(ctx context.Context, url string) error {
+	remote, err := svn.ParseCloneURL(url)
+	if err != nil {
+		return err
+	}
+	_, err = svn.NewClient(remote.Username, remote.Password).ListDirs(ctx, remote.URL)
+	return err
+}
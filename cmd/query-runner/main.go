@@ -11,7 +11,6 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -20,6 +19,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/query-runner/queryrunnerapi"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/eventlogger"
@@ -105,6 +105,13 @@ func (e *executorT) run(ctx context.Context) error {
 	// (impossible for new results to exist).
 	var oldList map[api.SavedQueryIDSpec]api.ConfigSavedQuery
 	for {
+		// Don't run saved searches (which records their results and sends notifications) while the
+		// site is read-only.
+		if conf.MaintenanceModeEnabled() {
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
 		allSavedQueries, err := api.InternalClient.SavedQueriesListAll(context.Background())
 		if err != nil {
 			log15.Error("executor: error fetching saved queries list (trying again in 5s", "error", err)
@@ -142,7 +149,7 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		// No need to run this query because there will be nobody to notify.
 		return nil
 	}
-	if !strings.Contains(query.Query, "type:diff") && !strings.Contains(query.Query, "type:commit") {
+	if !query.SupportsAfter() {
 		// TODO(slimsag): we temporarily do not support non-commit search
 		// queries, since those do not support the after:"time" operator.
 		return nil
@@ -185,8 +192,7 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		// time. We'll most certainly find nothing, which is okay.
 		latestKnownResult = time.Now()
 	}
-	afterTime := latestKnownResult.UTC().Format(time.RFC3339)
-	newQuery := strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
+	newQuery := query.QueryAfter(latestKnownResult)
 	if debugPretendSavedQueryResultsExist {
 		debugPretendSavedQueryResultsExist = false
 		newQuery = query.Query
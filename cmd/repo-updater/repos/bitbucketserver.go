@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -102,6 +103,7 @@ func (s BitbucketServerSource) ListRepos(ctx context.Context, results chan Sourc
 }
 
 var _ ChangesetSource = BitbucketServerSource{}
+var _ PullRequestSource = BitbucketServerSource{}
 
 // CreateChangeset creates the given *Changeset in the code host.
 func (s BitbucketServerSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
@@ -237,6 +239,67 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 	return nil
 }
 
+// MergedPullRequests returns the merged pull requests of the given repository that were updated at
+// or after the given time.
+func (s BitbucketServerSource) MergedPullRequests(ctx context.Context, r *Repo, since time.Time) ([]*PullRequest, error) {
+	repo := r.Metadata.(*bitbucketserver.Repo)
+
+	var prs []*PullRequest
+	for t := (&bitbucketserver.PageToken{Limit: 100}); t.HasMore(); {
+		page, next, err := s.client.MergedPullRequests(ctx, repo.Project.Key, repo.Slug, t)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mpr := range page {
+			// Pull requests are listed most recently updated first.
+			updatedAt := unixMilliToTime(int64(mpr.UpdatedDate))
+			if updatedAt.Before(since) {
+				return prs, nil
+			}
+
+			if err := s.client.LoadPullRequestCommits(ctx, &mpr.PullRequest); err != nil {
+				return nil, errors.Wrapf(err, "loading commits of pull request %d", mpr.ID)
+			}
+
+			pr := &PullRequest{
+				Number:    int64(mpr.ID),
+				Title:     mpr.Title,
+				MergedAt:  unixMilliToTime(int64(mpr.ClosedDate)),
+				UpdatedAt: updatedAt,
+			}
+			if len(mpr.Links.Self) > 0 {
+				pr.URL = mpr.Links.Self[0].Href
+			}
+			if mpr.Properties.MergeCommit != nil {
+				pr.MergeCommitID = mpr.Properties.MergeCommit.ID
+			}
+			for _, c := range mpr.Commits {
+				pr.HeadCommitIDs = append(pr.HeadCommitIDs, c.ID)
+			}
+			for _, r := range mpr.Reviewers {
+				// Reviewers who were added but never approved or requested changes didn't
+				// review the pull request.
+				if r.User == nil || (!r.Approved && r.Status != "NEEDS_WORK") {
+					continue
+				}
+				pr.Reviewers = append(pr.Reviewers, &PullRequestReviewer{
+					AccountID: strconv.Itoa(r.User.ID),
+					Login:     r.User.Name,
+				})
+			}
+			prs = append(prs, pr)
+		}
+		t = next
+	}
+	return prs, nil
+}
+
+// unixMilliToTime converts a Bitbucket Server timestamp (in milliseconds since the epoch) to a time.
+func unixMilliToTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s BitbucketServerSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
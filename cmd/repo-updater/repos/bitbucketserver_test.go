@@ -5,13 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
 	"github.com/sourcegraph/sourcegraph/schema"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -495,3 +500,126 @@ func TestBitbucketServerSource_UpdateChangeset(t *testing.T) {
 		})
 	}
 }
+
+func TestBitbucketServerSource_MergedPullRequests(t *testing.T) {
+	// A fake Bitbucket Server API that lists the merged pull requests of SOUR/vegeta, most recently
+	// updated first, in pages of 2, and their commits.
+	const pullRequestsPath = "/rest/api/1.0/projects/SOUR/repos/vegeta/pull-requests"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == pullRequestsPath && q.Get("start") == "":
+			if q.Get("state") != "MERGED" || q.Get("order") != "NEWEST" {
+				t.Errorf("unexpected query %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"size": 2, "limit": 100, "isLastPage": false, "start": 0, "nextPageStart": 2, "values": [
+				{"id": 12, "version": 2, "title": "Add -max-body flag", "state": "MERGED", "toRef": {"repository": {"slug": "vegeta", "project": {"key": "SOUR"}}}, "updatedDate": 1584699300000, "closedDate": 1584699300000, "links": {"self": [{"href": "https://bitbucket.example.com/projects/SOUR/repos/vegeta/pull-requests/12"}]}, "properties": {"mergeCommit": {"displayId": "8192a3b4c5d", "id": "8192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4"}}},
+				{"id": 11, "version": 2, "title": "Fix histogram buckets", "state": "MERGED", "toRef": {"repository": {"slug": "vegeta", "project": {"key": "SOUR"}}}, "updatedDate": 1583400000000, "closedDate": 1583399000000, "links": {"self": [{"href": "https://bitbucket.example.com/projects/SOUR/repos/vegeta/pull-requests/11"}]}, "properties": {}}
+			]}`))
+		case r.URL.Path == pullRequestsPath && q.Get("start") == "2":
+			_, _ = w.Write([]byte(`{"size": 1, "limit": 100, "isLastPage": true, "start": 2, "values": [
+				{"id": 9, "version": 2, "title": "Update README", "state": "MERGED", "toRef": {"repository": {"slug": "vegeta", "project": {"key": "SOUR"}}}, "updatedDate": 1580515200000, "closedDate": 1580515200000, "links": {"self": [{"href": "https://bitbucket.example.com/projects/SOUR/repos/vegeta/pull-requests/9"}]}, "properties": {"mergeCommit": {"displayId": "c5d6e7f8091", "id": "c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8"}}}
+			]}`))
+		case r.URL.Path == pullRequestsPath+"/12/commits":
+			_, _ = w.Write([]byte(`{"size": 2, "limit": 1000, "isLastPage": true, "start": 0, "values": [{"id": "92a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5"}, {"id": "a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6"}]}`))
+		case r.URL.Path == pullRequestsPath+"/11/commits":
+			_, _ = w.Write([]byte(`{"size": 1, "limit": 1000, "isLastPage": true, "start": 0, "values": [{"id": "b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "BITBUCKETSERVER",
+		Config: marshalJSON(t, &schema.BitbucketServerConnection{Url: srv.URL, Token: "secret"}),
+	}
+	bbsSrc, err := NewBitbucketServerSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repo{
+		Metadata: &bitbucketserver.Repo{
+			Slug:    "vegeta",
+			Project: &bitbucketserver.Project{Key: "SOUR"},
+		},
+	}
+	since := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	have, err := bbsSrc.MergedPullRequests(context.Background(), repo, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Older Bitbucket Server versions don't report merge commits. Pull requests updated before
+	// since end the listing, so the commits of pull request 9 aren't requested.
+	want := []*PullRequest{
+		{
+			Number:        12,
+			Title:         "Add -max-body flag",
+			URL:           "https://bitbucket.example.com/projects/SOUR/repos/vegeta/pull-requests/12",
+			MergeCommitID: "8192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4",
+			HeadCommitIDs: []string{"92a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5", "a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6"},
+			MergedAt:      time.Date(2020, 3, 20, 10, 15, 0, 0, time.UTC),
+			UpdatedAt:     time.Date(2020, 3, 20, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			Number:        11,
+			Title:         "Fix histogram buckets",
+			URL:           "https://bitbucket.example.com/projects/SOUR/repos/vegeta/pull-requests/11",
+			HeadCommitIDs: []string{"b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7"},
+			MergedAt:      time.Date(2020, 3, 5, 9, 3, 20, 0, time.UTC),
+			UpdatedAt:     time.Date(2020, 3, 5, 9, 20, 0, 0, time.UTC),
+		},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("pull requests mismatch (-want +have):\n%s", diff)
+	}
+}
+
+func TestBitbucketServerSource_MergedPullRequests_reviewers(t *testing.T) {
+	// A fake Bitbucket Server API with a pull request that milton approved, erik requested changes
+	// to, and thorsten was added to but never reviewed.
+	const pullRequestsPath = "/rest/api/1.0/projects/SOUR/repos/vegeta/pull-requests"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pullRequestsPath:
+			_, _ = w.Write([]byte(`{"size": 1, "limit": 100, "isLastPage": true, "start": 0, "values": [
+				{"id": 12, "version": 2, "title": "Add -max-body flag", "state": "MERGED", "toRef": {"repository": {"slug": "vegeta", "project": {"key": "SOUR"}}}, "updatedDate": 1584699300000, "closedDate": 1584699300000, "reviewers": [
+					{"user": {"name": "milton", "id": 2}, "role": "REVIEWER", "approved": true, "status": "APPROVED"},
+					{"user": {"name": "thorsten", "id": 5}, "role": "REVIEWER", "approved": false, "status": "UNAPPROVED"},
+					{"user": {"name": "erik", "id": 7}, "role": "REVIEWER", "approved": false, "status": "NEEDS_WORK"}
+				]}
+			]}`))
+		case pullRequestsPath + "/12/commits":
+			_, _ = w.Write([]byte(`{"size": 0, "limit": 1000, "isLastPage": true, "start": 0, "values": []}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "BITBUCKETSERVER",
+		Config: marshalJSON(t, &schema.BitbucketServerConnection{Url: srv.URL, Token: "secret"}),
+	}
+	bbsSrc, err := NewBitbucketServerSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repo{Metadata: &bitbucketserver.Repo{Slug: "vegeta", Project: &bitbucketserver.Project{Key: "SOUR"}}}
+	prs, err := bbsSrc.MergedPullRequests(context.Background(), repo, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(prs))
+	}
+	want := []*PullRequestReviewer{{AccountID: "2", Login: "milton"}, {AccountID: "7", Login: "erik"}}
+	if diff := cmp.Diff(want, prs[0].Reviewers); diff != "" {
+		t.Errorf("reviewers mismatch (-want +have):\n%s", diff)
+	}
+}
@@ -146,6 +146,7 @@ func (s GithubSource) ExternalServices() ExternalServices {
 }
 
 var _ ChangesetSource = GithubSource{}
+var _ PullRequestSource = GithubSource{}
 
 // CreateChangeset creates the given *Changeset in the code host.
 func (s GithubSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
@@ -252,6 +253,41 @@ func (s GithubSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
 	return nil
 }
 
+// MergedPullRequests returns the merged pull requests of the given repository that were updated at
+// or after the given time.
+func (s GithubSource) MergedPullRequests(ctx context.Context, r *Repo, since time.Time) ([]*PullRequest, error) {
+	repo := r.Metadata.(*github.Repository)
+	owner, name, err := github.SplitRepositoryNameWithOwner(repo.NameWithOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := s.client.ListMergedPullRequests(ctx, owner, name, since)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]*PullRequest, len(merged))
+	for i, pr := range merged {
+		prs[i] = &PullRequest{
+			Number:        pr.Number,
+			Title:         pr.Title,
+			URL:           pr.URL,
+			MergeCommitID: pr.MergeCommitOID,
+			HeadCommitIDs: pr.HeadCommitOIDs,
+			MergedAt:      pr.MergedAt,
+			UpdatedAt:     pr.UpdatedAt,
+		}
+		for _, r := range pr.Reviewers {
+			prs[i].Reviewers = append(prs[i].Reviewers, &PullRequestReviewer{
+				AccountID: strconv.FormatInt(r.DatabaseID, 10),
+				Login:     r.Login,
+			})
+		}
+	}
+	return prs, nil
+}
+
 // GetRepo returns the Github repository with the given name and owner
 // ("org/repo-name")
 func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo, error) {
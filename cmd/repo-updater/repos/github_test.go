@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -348,6 +350,138 @@ func TestGithubSource_LoadChangesets(t *testing.T) {
 	}
 }
 
+func TestGithubSource_MergedPullRequests(t *testing.T) {
+	// A fake GitHub Enterprise GraphQL API that lists the merged pull requests of tsenart/vegeta,
+	// most recently updated first, in pages of 2.
+	pages := map[string]string{
+		"": `{"data": {"repository": {"pullRequests": {
+			"nodes": [
+				{"number": 530, "title": "Add -max-body flag to attack command", "url": "https://ghe.example.com/tsenart/vegeta/pull/530", "mergedAt": "2020-03-20T10:15:00Z", "updatedAt": "2020-03-20T10:15:02Z", "mergeCommit": {"oid": "4b8e5c6a7b6e0a4c2b0f7a6a2d0d3f0e9c1a2b3c"}, "commits": {"nodes": [{"commit": {"oid": "0f3c2a1b4d5e6f708192a3b4c5d6e7f8091a2b3c"}}, {"commit": {"oid": "1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d"}}]}},
+				{"number": 528, "title": "Fix histogram buckets parsing", "url": "https://ghe.example.com/tsenart/vegeta/pull/528", "mergedAt": "2020-03-09T16:40:11Z", "updatedAt": "2020-03-10T08:00:00Z", "mergeCommit": {"oid": "5c9f6d7b8c7f1b5d3c1a8b7b3e1e4a1f0d2b3c4d"}, "commits": {"nodes": [{"commit": {"oid": "2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e"}}]}}
+			],
+			"pageInfo": {"hasNextPage": true, "endCursor": "page2"}
+		}}}}`,
+		"page2": `{"data": {"repository": {"pullRequests": {
+			"nodes": [
+				{"number": 525, "title": "Update README", "url": "https://ghe.example.com/tsenart/vegeta/pull/525", "mergedAt": "2020-02-28T12:00:00Z", "updatedAt": "2020-03-01T00:00:00Z", "mergeCommit": {"oid": "6d0a7e8c9d8a2c6e4d2b9c8c4f2f5b2a1e3c4d5e"}, "commits": {"nodes": [{"commit": {"oid": "3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f"}}]}},
+				{"number": 520, "title": "Add DNS caching", "url": "https://ghe.example.com/tsenart/vegeta/pull/520", "mergedAt": "2020-02-14T09:30:00Z", "updatedAt": "2020-02-15T11:00:00Z", "mergeCommit": {"oid": "7e1b8f9d0e9b3d7f5e3c0d9d5a3a6c3b2f4d5e6f"}, "commits": {"nodes": [{"commit": {"oid": "4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70"}}]}}
+			],
+			"pageInfo": {"hasNextPage": true, "endCursor": "page3"}
+		}}}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Owner, Name string
+				After       string
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		page, ok := pages[req.Variables.After]
+		if r.URL.Path != "/api/graphql" || req.Variables.Owner != "tsenart" || req.Variables.Name != "vegeta" || !ok {
+			t.Errorf("unexpected request %s %+v", r.URL.Path, req.Variables)
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "GITHUB",
+		Config: marshalJSON(t, &schema.GitHubConnection{Url: srv.URL, Token: "secret"}),
+	}
+	githubSrc, err := NewGithubSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repo{Metadata: &github.Repository{NameWithOwner: "tsenart/vegeta"}}
+	since := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	have, err := githubSrc.MergedPullRequests(context.Background(), repo, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pull requests updated before since end the listing, so the third page isn't requested.
+	want := []*PullRequest{
+		{
+			Number:        530,
+			Title:         "Add -max-body flag to attack command",
+			URL:           "https://ghe.example.com/tsenart/vegeta/pull/530",
+			MergeCommitID: "4b8e5c6a7b6e0a4c2b0f7a6a2d0d3f0e9c1a2b3c",
+			HeadCommitIDs: []string{"0f3c2a1b4d5e6f708192a3b4c5d6e7f8091a2b3c", "1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d"},
+			MergedAt:      time.Date(2020, 3, 20, 10, 15, 0, 0, time.UTC),
+			UpdatedAt:     time.Date(2020, 3, 20, 10, 15, 2, 0, time.UTC),
+		},
+		{
+			Number:        528,
+			Title:         "Fix histogram buckets parsing",
+			URL:           "https://ghe.example.com/tsenart/vegeta/pull/528",
+			MergeCommitID: "5c9f6d7b8c7f1b5d3c1a8b7b3e1e4a1f0d2b3c4d",
+			HeadCommitIDs: []string{"2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e"},
+			MergedAt:      time.Date(2020, 3, 9, 16, 40, 11, 0, time.UTC),
+			UpdatedAt:     time.Date(2020, 3, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			Number:        525,
+			Title:         "Update README",
+			URL:           "https://ghe.example.com/tsenart/vegeta/pull/525",
+			MergeCommitID: "6d0a7e8c9d8a2c6e4d2b9c8c4f2f5b2a1e3c4d5e",
+			HeadCommitIDs: []string{"3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f"},
+			MergedAt:      time.Date(2020, 2, 28, 12, 0, 0, 0, time.UTC),
+			UpdatedAt:     since,
+		},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("pull requests mismatch (-want +have):\n%s", diff)
+	}
+}
+
+func TestGithubSource_MergedPullRequests_reviewers(t *testing.T) {
+	// A fake GitHub Enterprise GraphQL API with a pull request that was reviewed by its author, by
+	// tsenart (twice), by a bot (which has no database ID), and by a deleted user.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+			"nodes": [
+				{"number": 530, "title": "Add -max-body flag to attack command", "url": "https://ghe.example.com/tsenart/vegeta/pull/530", "mergedAt": "2020-03-20T10:15:00Z", "updatedAt": "2020-03-20T10:15:02Z", "commits": {"nodes": []}, "author": {"login": "kakkoyun"}, "reviews": {"nodes": [
+					{"author": {"login": "kakkoyun", "databaseId": 536449}},
+					{"author": {"login": "tsenart", "databaseId": 1039927}},
+					{"author": {"login": "dependabot"}},
+					{"author": null},
+					{"author": {"login": "tsenart", "databaseId": 1039927}}
+				]}}
+			],
+			"pageInfo": {"hasNextPage": false}
+		}}}}`))
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "GITHUB",
+		Config: marshalJSON(t, &schema.GitHubConnection{Url: srv.URL, Token: "secret"}),
+	}
+	githubSrc, err := NewGithubSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prs, err := githubSrc.MergedPullRequests(context.Background(), &Repo{Metadata: &github.Repository{NameWithOwner: "tsenart/vegeta"}}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(prs))
+	}
+	want := []*PullRequestReviewer{{AccountID: "1039927", Login: "tsenart"}}
+	if diff := cmp.Diff(want, prs[0].Reviewers); diff != "" {
+		t.Errorf("reviewers mismatch (-want +have):\n%s", diff)
+	}
+}
+
 func TestGithubSource_GetRepo(t *testing.T) {
 	testCases := []struct {
 		name          string
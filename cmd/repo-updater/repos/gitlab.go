@@ -93,6 +93,8 @@ func (s GitLabSource) ListRepos(ctx context.Context, results chan SourceResult)
 	s.listAllProjects(ctx, results)
 }
 
+var _ PullRequestSource = GitLabSource{}
+
 // GetRepo returns the GitLab repository with the given pathWithNamespace.
 func (s GitLabSource) GetRepo(ctx context.Context, pathWithNamespace string) (*Repo, error) {
 	proj, err := s.client.GetProject(ctx, gitlab.GetProjectOp{
@@ -112,6 +114,68 @@ func (s GitLabSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
 }
 
+// MergedPullRequests returns the merged merge requests of the given project that were updated at or
+// after the given time.
+func (s GitLabSource) MergedPullRequests(ctx context.Context, r *Repo, since time.Time) ([]*PullRequest, error) {
+	proj := r.Metadata.(*gitlab.Project)
+
+	qry := url.Values{
+		"state":    []string{"merged"},
+		"order_by": []string{"updated_at"},
+		"sort":     []string{"asc"},
+		"per_page": []string{"100"},
+	}
+	if !since.IsZero() {
+		qry.Set("updated_after", since.UTC().Format(time.RFC3339))
+	}
+	urlStr := fmt.Sprintf("projects/%d/merge_requests?%s", proj.ID, qry.Encode())
+
+	var prs []*PullRequest
+	for {
+		mrs, nextPageURL, err := s.client.ListMergeRequests(ctx, urlStr)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mr := range mrs {
+			if mr.MergedAt == nil {
+				continue
+			}
+			commits, err := s.client.ListMergeRequestCommits(ctx, proj.ID, mr.IID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "listing commits of merge request %d", mr.IID)
+			}
+
+			pr := &PullRequest{
+				Number:        int64(mr.IID),
+				Title:         mr.Title,
+				URL:           mr.WebURL,
+				MergeCommitID: mr.MergeCommitSHA,
+				MergedAt:      *mr.MergedAt,
+				UpdatedAt:     mr.UpdatedAt,
+			}
+			for _, c := range commits {
+				pr.HeadCommitIDs = append(pr.HeadCommitIDs, c.ID)
+			}
+			for _, u := range mr.Reviewers {
+				if mr.Author != nil && u.ID == mr.Author.ID {
+					continue
+				}
+				pr.Reviewers = append(pr.Reviewers, &PullRequestReviewer{
+					AccountID: strconv.FormatInt(int64(u.ID), 10),
+					Login:     u.Username,
+				})
+			}
+			prs = append(prs, pr)
+		}
+
+		if nextPageURL == nil {
+			return prs, nil
+		}
+		urlStr = *nextPageURL
+	}
+}
+
 func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 	urn := s.svc.URN()
 	return &Repo{
@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -157,6 +161,119 @@ func TestGitLabSource_GetRepo(t *testing.T) {
 	}
 }
 
+func TestGitLabSource_MergedPullRequests(t *testing.T) {
+	// A fake GitLab API that lists the merged merge requests of project 2009901 updated since March
+	// 1, in pages of 2, and their commits.
+	const mergeRequestsPath = "/api/v4/projects/2009901/merge_requests"
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == mergeRequestsPath && q.Get("page") == "":
+			if q.Get("state") != "merged" || q.Get("updated_after") != "2020-03-01T00:00:00Z" {
+				t.Errorf("unexpected query %q", r.URL.RawQuery)
+			}
+			q.Set("page", "2")
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?%s>; rel="next"`, srv.URL, mergeRequestsPath, q.Encode()))
+			_, _ = w.Write([]byte(`[
+				{"id": 50001890, "iid": 1890, "project_id": 2009901, "title": "Remove deprecated RPCs", "state": "merged", "merged_at": "2020-03-02T09:00:00.000Z", "updated_at": "2020-03-02T09:00:01.120Z", "merge_commit_sha": "8f2c9a0e1f0c4e8a6f4d1e0e6b4b7d4c3a5e6f70", "web_url": "https://gitlab.example.com/gitlab-org/gitaly/-/merge_requests/1890"},
+				{"id": 50001301, "iid": 1301, "project_id": 2009901, "title": "Add ref transaction hooks", "state": "merged", "merged_at": null, "updated_at": "2020-03-03T12:30:00.000Z", "merge_commit_sha": null, "web_url": "https://gitlab.example.com/gitlab-org/gitaly/-/merge_requests/1301"}
+			]`))
+		case r.URL.Path == mergeRequestsPath && q.Get("page") == "2":
+			_, _ = w.Write([]byte(`[
+				{"id": 50001902, "iid": 1902, "project_id": 2009901, "title": "Fix flaky test", "state": "merged", "merged_at": "2020-03-05T14:20:00.000Z", "updated_at": "2020-03-05T14:20:00.500Z", "merge_commit_sha": null, "web_url": "https://gitlab.example.com/gitlab-org/gitaly/-/merge_requests/1902"}
+			]`))
+		case r.URL.Path == mergeRequestsPath+"/1890/commits":
+			_, _ = w.Write([]byte(`[{"id": "5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7081"}, {"id": "6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192"}]`))
+		case r.URL.Path == mergeRequestsPath+"/1902/commits":
+			_, _ = w.Write([]byte(`[{"id": "708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "GITLAB",
+		Config: marshalJSON(t, &schema.GitLabConnection{Url: srv.URL, Token: "secret"}),
+	}
+	gitlabSrc, err := NewGitLabSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repo{Metadata: &gitlab.Project{ProjectCommon: gitlab.ProjectCommon{ID: 2009901}}}
+	since := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	have, err := gitlabSrc.MergedPullRequests(context.Background(), repo, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Merge requests without a merge time (merged before GitLab recorded it) are skipped.
+	want := []*PullRequest{
+		{
+			Number:        1890,
+			Title:         "Remove deprecated RPCs",
+			URL:           "https://gitlab.example.com/gitlab-org/gitaly/-/merge_requests/1890",
+			MergeCommitID: "8f2c9a0e1f0c4e8a6f4d1e0e6b4b7d4c3a5e6f70",
+			HeadCommitIDs: []string{"5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7081", "6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192"},
+			MergedAt:      time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC),
+			UpdatedAt:     time.Date(2020, 3, 2, 9, 0, 1, 120000000, time.UTC),
+		},
+		{
+			Number:        1902,
+			Title:         "Fix flaky test",
+			URL:           "https://gitlab.example.com/gitlab-org/gitaly/-/merge_requests/1902",
+			HeadCommitIDs: []string{"708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3"},
+			MergedAt:      time.Date(2020, 3, 5, 14, 20, 0, 0, time.UTC),
+			UpdatedAt:     time.Date(2020, 3, 5, 14, 20, 0, 500000000, time.UTC),
+		},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("pull requests mismatch (-want +have):\n%s", diff)
+	}
+}
+
+func TestGitLabSource_MergedPullRequests_reviewers(t *testing.T) {
+	// A fake GitLab API with a merge request whose reviewers include its author.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/projects/2009901/merge_requests":
+			_, _ = w.Write([]byte(`[
+				{"id": 50001890, "iid": 1890, "project_id": 2009901, "title": "Remove deprecated RPCs", "state": "merged", "merged_at": "2020-03-02T09:00:00.000Z", "updated_at": "2020-03-02T09:00:01.120Z", "web_url": "https://gitlab.example.com/gitlab-org/gitaly/-/merge_requests/1890", "author": {"id": 1786152, "username": "zj"}, "reviewers": [{"id": 3585, "username": "jacobvosmaer"}, {"id": 1786152, "username": "zj"}]}
+			]`))
+		case "/api/v4/projects/2009901/merge_requests/1890/commits":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		Kind:   "GITLAB",
+		Config: marshalJSON(t, &schema.GitLabConnection{Url: srv.URL, Token: "secret"}),
+	}
+	gitlabSrc, err := NewGitLabSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prs, err := gitlabSrc.MergedPullRequests(context.Background(), &Repo{Metadata: &gitlab.Project{ProjectCommon: gitlab.ProjectCommon{ID: 2009901}}}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(prs))
+	}
+	want := []*PullRequestReviewer{{AccountID: "3585", Login: "jacobvosmaer"}}
+	if diff := cmp.Diff(want, prs[0].Reviewers); diff != "" {
+		t.Errorf("reviewers mismatch (-want +have):\n%s", diff)
+	}
+}
+
 func TestGitLabSource_makeRepo(t *testing.T) {
 	b, err := ioutil.ReadFile(filepath.Join("testdata", "gitlab-repos.json"))
 	if err != nil {
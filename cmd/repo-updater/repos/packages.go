@@ -0,0 +1,212 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/maven"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npm"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+)
+
+// A PackageRegistrySource yields repositories from a single package registry connection (npm,
+// Maven or Go module proxy) configured in Sourcegraph via the external services configuration.
+// Each package is materialized as a Git repository by gitserver, with one tagged commit per
+// version.
+type PackageRegistrySource struct {
+	svc         *ExternalService
+	serviceType string
+	registryURL string
+
+	packages       []string
+	lockfile       string
+	pattern        string
+	defaultPattern string
+
+	parseDependency func(string) (pkgs.Dependency, error)
+	parseLockfile   func([]byte) ([]pkgs.Dependency, error)
+	registry        pkgs.Registry
+}
+
+// packageRegistryConnection holds the configuration fields that the package registry external
+// services (schema.NPMConnection, schema.MavenConnection and schema.GoModulesConnection) have in
+// common.
+type packageRegistryConnection struct {
+	Registry              string   `json:"registry"`
+	Packages              []string `json:"packages"`
+	Lockfile              string   `json:"lockfile"`
+	RepositoryPathPattern string   `json:"repositoryPathPattern"`
+}
+
+// NewPackageRegistrySource returns a new PackageRegistrySource from the given external service,
+// which must be of kind NPM, MAVEN or GOMODULES.
+func NewPackageRegistrySource(svc *ExternalService, cf *httpcli.Factory) (*PackageRegistrySource, error) {
+	var c packageRegistryConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+	doer, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	src := &PackageRegistrySource{
+		svc:      svc,
+		packages: c.Packages,
+		lockfile: c.Lockfile,
+		pattern:  c.RepositoryPathPattern,
+	}
+	switch strings.ToLower(svc.Kind) {
+	case "npm":
+		if c.Registry == "" {
+			c.Registry = npm.DefaultRegistryURL
+		}
+		src.serviceType, src.defaultPattern = npm.ServiceType, "npm/{package}"
+		src.parseDependency, src.parseLockfile = npm.ParseDependency, npm.ParseLockfile
+		src.registry, err = npm.NewClient(c.Registry, doer)
+	case "maven":
+		if c.Registry == "" {
+			c.Registry = maven.DefaultRegistryURL
+		}
+		src.serviceType, src.defaultPattern = maven.ServiceType, "maven/{package}"
+		src.parseDependency, src.parseLockfile = maven.ParseDependency, maven.ParseLockfile
+		src.registry, err = maven.NewClient(c.Registry, doer)
+	case "gomodules":
+		if c.Registry == "" {
+			c.Registry = gomodules.DefaultRegistryURL
+		}
+		src.serviceType, src.defaultPattern = gomodules.ServiceType, "go/{package}"
+		src.parseDependency, src.parseLockfile = gomodules.ParseDependency, gomodules.ParseLockfile
+		src.registry, err = gomodules.NewClient(c.Registry, doer)
+	default:
+		return nil, fmt.Errorf("external service id=%d: %q is not a package registry kind", svc.ID, svc.Kind)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	src.registryURL = pkgs.ServiceID(c.Registry)
+	return src, nil
+}
+
+// ListRepos returns a repository for every package selected by the connection configured in
+// Sourcegraph via the external services configuration. Packages that don't exist in the registry
+// are reported as errors.
+func (s PackageRegistrySource) ListRepos(ctx context.Context, results chan SourceResult) {
+	packages, err := s.selectedPackages()
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	for _, pkg := range packages {
+		if _, err := s.registry.Versions(ctx, pkg.Name); err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "%s package %s", s.serviceType, pkg.Name)}
+			continue
+		}
+		repo, err := s.makeRepo(pkg)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: repo}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s PackageRegistrySource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+// selectedPackages returns the packages selected by the packages and lockfile fields of the
+// configuration, sorted by name. A package selected without a version selects all of its
+// versions.
+func (s PackageRegistrySource) selectedPackages() ([]*pkgs.Package, error) {
+	var deps []pkgs.Dependency
+	for _, spec := range s.packages {
+		d, err := s.parseDependency(spec)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, d)
+	}
+	if s.lockfile != "" {
+		locked, err := s.parseLockfile([]byte(s.lockfile))
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, locked...)
+	}
+
+	byName := map[string]*pkgs.Package{}
+	allVersions := map[string]bool{}
+	for _, d := range deps {
+		pkg, ok := byName[d.Name]
+		if !ok {
+			pkg = &pkgs.Package{Name: d.Name}
+			byName[d.Name] = pkg
+		}
+		if d.Version == "" {
+			allVersions[d.Name] = true
+		} else if !containsVersion(pkg.Versions, d.Version) {
+			pkg.Versions = append(pkg.Versions, d.Version)
+		}
+	}
+
+	packages := make([]*pkgs.Package, 0, len(byName))
+	for name, pkg := range byName {
+		if allVersions[name] {
+			pkg.Versions = nil
+		}
+		sort.Strings(pkg.Versions)
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages, nil
+}
+
+func containsVersion(versions []string, v string) bool {
+	for _, w := range versions {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s PackageRegistrySource) makeRepo(pkg *pkgs.Package) (*Repo, error) {
+	remote := pkgs.Remote{
+		ServiceType: s.serviceType,
+		RegistryURL: s.registryURL,
+		Package:     *pkg,
+	}
+	cloneURL, err := remote.CloneURL()
+	if err != nil {
+		return nil, err
+	}
+
+	urn := s.svc.URN()
+	repoName := string(reposource.PackageRepoName(s.pattern, s.defaultPattern, pkg.Name))
+	return &Repo{
+		Name:         repoName,
+		URI:          repoName,
+		ExternalRepo: pkgs.ExternalRepoSpec(pkg, s.serviceType, s.registryURL),
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL,
+			},
+		},
+		Metadata: pkg,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+func TestPackageRegistrySource_ListRepos(t *testing.T) {
+	// A fake npm registry that has the packages left-pad and @acme/widgets.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/left-pad", "/@acme%2Fwidgets":
+			_, _ = w.Write([]byte(`{"versions": {"1.0.0": {}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{ID: 1, Kind: "NPM", Config: `{
+		"registry": "` + srv.URL + `",
+		"packages": ["left-pad", "@acme/widgets@1.0.0", "does-not-exist"],
+		// left-pad is selected with all of its versions, so the locked version doesn't matter.
+		"lockfile": "{\"packages\": {\"node_modules/@acme/widgets\": {\"version\": \"0.9.0\"}, \"node_modules/left-pad\": {\"version\": \"1.3.0\"}}}"
+	}`}
+	src, err := NewPackageRegistrySource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := listAll(context.Background(), src)
+	if err == nil || !strings.Contains(err.Error(), "npm package does-not-exist") {
+		t.Errorf("got error %v, want error for missing package", err)
+	}
+
+	registry := srv.URL + "/"
+	want := []*Repo{
+		{
+			Name: "npm/@acme/widgets",
+			URI:  "npm/@acme/widgets",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "@acme/widgets",
+				ServiceType: "npm",
+				ServiceID:   registry,
+			},
+			Sources: map[string]*SourceInfo{
+				"extsvc:npm:1": {
+					ID:       "extsvc:npm:1",
+					CloneURL: "npm+" + registry + "?package=%40acme%2Fwidgets&version=0.9.0&version=1.0.0",
+				},
+			},
+			Metadata: &pkgs.Package{Name: "@acme/widgets", Versions: []string{"0.9.0", "1.0.0"}},
+		},
+		{
+			Name: "npm/left-pad",
+			URI:  "npm/left-pad",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "left-pad",
+				ServiceType: "npm",
+				ServiceID:   registry,
+			},
+			Sources: map[string]*SourceInfo{
+				"extsvc:npm:1": {
+					ID:       "extsvc:npm:1",
+					CloneURL: "npm+" + registry + "?package=left-pad",
+				},
+			},
+			Metadata: &pkgs.Package{Name: "left-pad"},
+		},
+	}
+	if diff := cmp.Diff(want, repos); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNewPackageRegistrySource(t *testing.T) {
+	for _, tc := range []struct {
+		kind, config string
+		wantErr      bool
+	}{
+		{kind: "MAVEN", config: `{"packages": ["org.apache.commons:commons-lang3"]}`},
+		{kind: "GOMODULES", config: `{"lockfile": "golang.org/x/net v0.1.0 h1:x="}`},
+		{kind: "GITHUB", config: `{}`, wantErr: true},
+	} {
+		_, err := NewPackageRegistrySource(&ExternalService{Kind: tc.kind, Config: tc.config}, httpcli.NewFactory(nil))
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", tc.kind, err, tc.wantErr)
+		}
+	}
+}
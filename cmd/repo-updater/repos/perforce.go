@@ -0,0 +1,123 @@
+package repos
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A PerforceSource yields repositories from a single Perforce connection configured
+// in Sourcegraph via the external services configuration. Each selected depot path or
+// stream is a repository, which gitserver converts into a Git repository.
+type PerforceSource struct {
+	svc  *ExternalService
+	conn *schema.PerforceConnection
+	cli  *perforce.Client
+}
+
+// NewPerforceSource returns a new PerforceSource from the given external service.
+func NewPerforceSource(svc *ExternalService, _ *httpcli.Factory) (*PerforceSource, error) {
+	var c schema.PerforceConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	return &PerforceSource{
+		svc:  svc,
+		conn: &c,
+		cli:  perforce.NewClient(c.P4Port, c.P4User, c.P4Passwd),
+	}, nil
+}
+
+// ListRepos returns all Perforce depot paths and streams selected by the connection
+// configured in Sourcegraph via the external services configuration.
+func (s PerforceSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	depots, err := s.depots(ctx)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+	for _, d := range depots {
+		results <- SourceResult{Source: s, Repo: s.makeRepo(d)}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s PerforceSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+// depots returns the depot paths selected by the configuration. Paths that name the
+// root of a stream depot are expanded to the streams of that depot.
+func (s PerforceSource) depots(ctx context.Context) ([]*perforce.Depot, error) {
+	all, err := s.cli.Depots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*perforce.Depot, len(all))
+	for _, d := range all {
+		byName[strings.Trim(d.Path, "/")] = d
+	}
+
+	var selected []*perforce.Depot
+	for _, path := range s.conn.Depots {
+		path = "//" + strings.Trim(path, "/") + "/"
+		name := strings.SplitN(strings.TrimPrefix(path, "//"), "/", 2)[0]
+
+		d, ok := byName[name]
+		if !ok {
+			return nil, errors.Errorf("depot %q of depot path %q does not exist or is not visible to %q", name, path, s.conn.P4User)
+		}
+
+		if d.Path == path && d.Type == "stream" {
+			streams, err := s.cli.Streams(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, streams...)
+			continue
+		}
+
+		if d.Path == path {
+			selected = append(selected, d)
+		} else {
+			selected = append(selected, &perforce.Depot{Path: path, Type: d.Type})
+		}
+	}
+	return selected, nil
+}
+
+func (s PerforceSource) makeRepo(d *perforce.Depot) *Repo {
+	urn := s.svc.URN()
+	name := string(reposource.PerforceRepoName(
+		s.conn.RepositoryPathPattern,
+		perforce.Host(s.conn.P4Port),
+		d.Path,
+	))
+	remote := perforce.Remote{
+		Port:      s.conn.P4Port,
+		User:      s.conn.P4User,
+		Password:  s.conn.P4Passwd,
+		DepotPath: d.Path,
+	}
+
+	return &Repo{
+		Name:         name,
+		URI:          name,
+		Description:  d.Description,
+		ExternalRepo: perforce.ExternalRepoSpec(d, perforce.ServiceID(s.conn.P4Port)),
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: remote.CloneURL(),
+			},
+		},
+		Metadata: d,
+	}
+}
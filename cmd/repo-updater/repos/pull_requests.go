@@ -0,0 +1,119 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// A PullRequest is a merged pull request (or merge request) of a repository.
+type PullRequest struct {
+	Number int64
+	Title  string
+	URL    string
+	// MergeCommitID is the commit that merged the pull request, if the code host reports one.
+	MergeCommitID string
+	// HeadCommitIDs are the commits of the pull request's head branch.
+	HeadCommitIDs []string
+	// Reviewers are the users (other than the author) who reviewed the pull request, if the code
+	// host reports them.
+	Reviewers []*PullRequestReviewer
+	MergedAt  time.Time
+	UpdatedAt time.Time
+}
+
+// A PullRequestReviewer is a user who reviewed a pull request.
+type PullRequestReviewer struct {
+	// AccountID is the ID of the user's account on the code host, as it is stored in the user's
+	// external account.
+	AccountID string
+	Login     string
+}
+
+// A PullRequestSource can list the merged pull requests of its repositories.
+type PullRequestSource interface {
+	// MergedPullRequests returns the merged pull requests of the given repository that were
+	// updated at or after the given time, or all of them if it is zero.
+	MergedPullRequests(ctx context.Context, r *Repo, since time.Time) ([]*PullRequest, error)
+}
+
+// pullRequestSourceKinds are the kinds of external services whose sources are PullRequestSources.
+var pullRequestSourceKinds = []string{"GITHUB", "GITLAB", "BITBUCKETSERVER"}
+
+// pullRequestSyncInterval is the time between two syncs of the merged pull requests of all
+// repositories.
+const pullRequestSyncInterval = 10 * time.Minute
+
+// RunPullRequestSyncWorker runs the worker that indexes the merged pull requests of GitHub, GitLab
+// and Bitbucket Server repositories, so that commits can be linked to the pull requests that
+// introduced them. Each sync only fetches the pull requests of a repository that were updated
+// since its last sync. The worker is idle unless the "pullRequestIndexing" experimental feature is
+// enabled. Syncs are skipped while maintenance mode is enabled.
+func RunPullRequestSyncWorker(ctx context.Context, s *DBStore, cf *httpcli.Factory) {
+	for {
+		if conf.MaintenanceModeEnabled() {
+			log15.Debug("Skipping pull request sync in maintenance mode.")
+		} else if pullRequestIndexingEnabled() {
+			syncPullRequests(ctx, s, cf)
+		}
+		time.Sleep(pullRequestSyncInterval)
+	}
+}
+
+func pullRequestIndexingEnabled() bool {
+	c := conf.Get()
+	return c.ExperimentalFeatures != nil && c.ExperimentalFeatures.PullRequestIndexing == "enabled"
+}
+
+func syncPullRequests(ctx context.Context, s *DBStore, cf *httpcli.Factory) {
+	svcs, err := s.ListExternalServices(ctx, StoreListExternalServicesArgs{Kinds: pullRequestSourceKinds})
+	if err != nil {
+		log15.Error("unable to list external services for pull request sync", "err", err)
+		return
+	}
+	rs, err := s.ListRepos(ctx, StoreListReposArgs{Kinds: pullRequestSourceKinds})
+	if err != nil {
+		log15.Error("unable to list repos for pull request sync", "err", err)
+		return
+	}
+
+	// A repository that several external services yield is only synced once.
+	synced := make(map[api.RepoID]bool, len(rs))
+	for _, svc := range svcs {
+		src, err := NewSource(svc, cf)
+		if err != nil {
+			log15.Error("failed to instantiate source for pull request sync", "id", svc.ID, "err", err)
+			continue
+		}
+		prs, ok := src.(PullRequestSource)
+		if !ok {
+			continue
+		}
+
+		for _, r := range rs {
+			if _, ok := r.Sources[svc.URN()]; !ok || synced[r.ID] {
+				continue
+			}
+			synced[r.ID] = true
+			if err := syncRepoPullRequests(ctx, s, prs, r); err != nil {
+				log15.Error("failed to sync merged pull requests", "repo", r.Name, "err", err)
+			}
+		}
+	}
+}
+
+func syncRepoPullRequests(ctx context.Context, s *DBStore, src PullRequestSource, r *Repo) error {
+	since, err := s.PullRequestsSyncedUntil(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	prs, err := src.MergedPullRequests(ctx, r, since)
+	if err != nil || len(prs) == 0 {
+		return err
+	}
+	return s.UpsertPullRequests(ctx, r.ID, prs)
+}
@@ -104,9 +104,10 @@ type updateScheduler struct {
 // a configuration source, such as information retrieved from GitHub for a
 // given GitHubConnection.
 type configuredRepo2 struct {
-	URL  string
-	ID   api.RepoID
-	Name api.RepoName
+	URL        string
+	ID         api.RepoID
+	Name       api.RepoName
+	ObjectPool *gitserverprotocol.ObjectPool
 }
 
 // notifyChanBuffer controls the buffer size of notification channels.
@@ -208,7 +209,7 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 
 // requestRepoUpdate sends a request to gitserver to request an update.
 var requestRepoUpdate = func(ctx context.Context, repo *configuredRepo2, since time.Duration) (*gitserverprotocol.RepoUpdateResponse, error) {
-	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL}, since)
+	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL}, since, repo.ObjectPool)
 }
 
 // configuredLimiter returns a mutable limiter that is
@@ -283,8 +284,9 @@ func (s *updateScheduler) remove(r *Repo) {
 
 func configuredRepo2FromRepo(r *Repo) *configuredRepo2 {
 	repo := configuredRepo2{
-		ID:   r.ID,
-		Name: api.RepoName(r.Name),
+		ID:         r.ID,
+		Name:       api.RepoName(r.Name),
+		ObjectPool: r.ObjectPool(),
 	}
 
 	if urls := r.CloneURLs(); len(urls) > 0 {
@@ -302,6 +304,15 @@ func (s *updateScheduler) UpdateOnce(id api.RepoID, name api.RepoName, url strin
 		Name: name,
 		URL:  url,
 	}
+
+	// Reuse the object pool of the scheduled repo, so that a fork that is cloned by a manual fetch
+	// shares the objects of its fork network too.
+	s.schedule.mu.Lock()
+	if update, ok := s.schedule.index[id]; ok {
+		repo.ObjectPool = update.Repo.ObjectPool
+	}
+	s.schedule.mu.Unlock()
+
 	schedManualFetch.Inc()
 	s.updateQueue.enqueue(repo, priorityHigh)
 }
@@ -67,6 +67,12 @@ func NewSource(svc *ExternalService, cf *httpcli.Factory) (Source, error) {
 		return NewPhabricatorSource(svc, cf)
 	case "awscodecommit":
 		return NewAWSCodeCommitSource(svc, cf)
+	case "perforce":
+		return NewPerforceSource(svc, cf)
+	case "svn":
+		return NewSVNSource(svc, cf)
+	case "npm", "maven", "gomodules":
+		return NewPackageRegistrySource(svc, cf)
 	case "other":
 		return NewOtherSource(svc, cf)
 	default:
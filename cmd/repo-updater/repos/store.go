@@ -18,6 +18,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/svn"
 )
 
 // A Store exposes methods to read and write repos and external services.
@@ -162,7 +165,8 @@ SELECT
   config,
   created_at,
   updated_at,
-  deleted_at
+  deleted_at,
+  namespace_org_id
 FROM external_services
 WHERE id > %s
 AND %s
@@ -260,6 +264,7 @@ func upsertExternalServicesQuery(svcs []*ExternalService) *sqlf.Query {
 			s.CreatedAt.UTC(),
 			s.UpdatedAt.UTC(),
 			nullTimeColumn(s.DeletedAt.UTC()),
+			nullInt32Column(s.NamespaceOrgID),
 		))
 	}
 
@@ -270,7 +275,7 @@ func upsertExternalServicesQuery(svcs []*ExternalService) *sqlf.Query {
 }
 
 const upsertExternalServicesQueryValueFmtstr = `
-  (COALESCE(NULLIF(%s, 0), (SELECT nextval('external_services_id_seq'))), UPPER(%s), %s, %s, %s, %s, %s)
+  (COALESCE(NULLIF(%s, 0), (SELECT nextval('external_services_id_seq'))), UPPER(%s), %s, %s, %s, %s, %s, %s)
 `
 
 const upsertExternalServicesQueryFmtstr = `
@@ -282,18 +287,20 @@ INSERT INTO external_services (
   config,
   created_at,
   updated_at,
-  deleted_at
+  deleted_at,
+  namespace_org_id
 )
 VALUES %s
 ON CONFLICT(id) DO UPDATE
 SET
-  kind         = UPPER(excluded.kind),
-  display_name = excluded.display_name,
-  config       = excluded.config,
-  created_at   = excluded.created_at,
-  updated_at   = excluded.updated_at,
-  deleted_at   = excluded.deleted_at
-RETURNING *
+  kind             = UPPER(excluded.kind),
+  display_name     = excluded.display_name,
+  config           = excluded.config,
+  created_at       = excluded.created_at,
+  updated_at       = excluded.updated_at,
+  deleted_at       = excluded.deleted_at,
+  namespace_org_id = excluded.namespace_org_id
+RETURNING id, kind, display_name, config, created_at, updated_at, deleted_at, namespace_org_id
 `
 
 // ListRepos lists all stored repos that match the given arguments.
@@ -412,6 +419,170 @@ func (s DBStore) ListAllRepoNames(ctx context.Context) (names []api.RepoName, _
 	)
 }
 
+// RepoQuotas returns the repository quotas of the organizations that own external services, by
+// organization ID (see Syncer.RepoQuotas). An organization's own quota overrides the default quota.
+func (s DBStore) RepoQuotas(ctx context.Context) (map[int32]int, error) {
+	rows, err := s.db.QueryContext(ctx, repoQuotasQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotas := map[int32]int{}
+	for rows.Next() {
+		var orgID int32
+		var max int
+		if err := rows.Scan(&orgID, &max); err != nil {
+			return nil, err
+		}
+		quotas[orgID] = max
+	}
+	return quotas, rows.Err()
+}
+
+const repoQuotasQuery = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.RepoQuotas
+SELECT org_id, max_repositories FROM (
+  SELECT
+    orgs.id AS org_id,
+    CASE WHEN org_quota.id IS NOT NULL THEN org_quota.max_repositories ELSE default_quota.max_repositories END AS max_repositories
+  FROM (SELECT DISTINCT namespace_org_id AS id FROM external_services WHERE namespace_org_id IS NOT NULL AND deleted_at IS NULL) orgs
+  LEFT JOIN quotas org_quota ON org_quota.org_id = orgs.id
+  LEFT JOIN quotas default_quota ON default_quota.org_id IS NULL AND default_quota.user_id IS NULL
+) q
+WHERE max_repositories IS NOT NULL
+`
+
+// PullRequestsSyncedUntil returns the time up to which the merged pull requests of the repo were
+// synced (see UpsertPullRequests), or the zero time if they were never synced.
+func (s DBStore) PullRequestsSyncedUntil(ctx context.Context, repoID api.RepoID) (t time.Time, err error) {
+	q := sqlf.Sprintf(pullRequestsSyncedUntilQueryFmtstr, repoID)
+	_, _, err = s.list(ctx, q, func(sc scanner) (last, count int64, err error) {
+		return 0, 1, sc.Scan(&t)
+	})
+	return t, err
+}
+
+const pullRequestsSyncedUntilQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.PullRequestsSyncedUntil
+SELECT synced_until FROM repo_pull_request_syncs WHERE repo_id = %s
+`
+
+// UpsertPullRequests updates or inserts the given merged pull requests of the repo along with the
+// commits that they introduced and their reviewers, and records the update time of the most recently updated one as
+// the time up to which the repo's pull requests were synced.
+func (s DBStore) UpsertPullRequests(ctx context.Context, repoID api.RepoID, prs []*PullRequest) error {
+	type reviewer struct {
+		AccountID string `json:"account_id"`
+		Login     string `json:"login"`
+	}
+	type record struct {
+		Number        int64      `json:"number"`
+		Title         string     `json:"title"`
+		URL           string     `json:"url"`
+		MergeCommitID *string    `json:"merge_commit_id,omitempty"`
+		MergedAt      time.Time  `json:"merged_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+		CommitIDs     []string   `json:"commit_ids"`
+		Reviewers     []reviewer `json:"reviewers"`
+	}
+
+	var syncedUntil time.Time
+	seen := make(map[int64]bool, len(prs))
+	records := make([]record, 0, len(prs))
+	for _, pr := range prs {
+		// A pull request that was updated while its source was paginated can be listed twice,
+		// but a row can't be upserted twice in the same statement.
+		if seen[pr.Number] {
+			continue
+		}
+		seen[pr.Number] = true
+
+		commitIDs := make([]string, 0, len(pr.HeadCommitIDs)+1)
+		commitIDs = append(commitIDs, pr.HeadCommitIDs...)
+		if pr.MergeCommitID != "" {
+			commitIDs = append(commitIDs, pr.MergeCommitID)
+		}
+		reviewers := make([]reviewer, 0, len(pr.Reviewers))
+		for _, r := range pr.Reviewers {
+			reviewers = append(reviewers, reviewer{AccountID: r.AccountID, Login: r.Login})
+		}
+		records = append(records, record{
+			Number:        pr.Number,
+			Title:         pr.Title,
+			URL:           pr.URL,
+			MergeCommitID: nullStringColumn(pr.MergeCommitID),
+			MergedAt:      pr.MergedAt.UTC(),
+			UpdatedAt:     pr.UpdatedAt.UTC(),
+			CommitIDs:     commitIDs,
+			Reviewers:     reviewers,
+		})
+		if pr.UpdatedAt.After(syncedUntil) {
+			syncedUntil = pr.UpdatedAt
+		}
+	}
+
+	batch, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	q := sqlf.Sprintf(upsertPullRequestsQueryFmtstr, string(batch), repoID, repoID, repoID, syncedUntil.UTC())
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+const upsertPullRequestsQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.UpsertPullRequests
+WITH batch AS (
+  SELECT * FROM json_to_recordset(%s)
+  AS (
+      number          bigint,
+      title           text,
+      url             text,
+      merge_commit_id text,
+      merged_at       timestamptz,
+      updated_at      timestamptz,
+      commit_ids      jsonb,
+      reviewers       jsonb
+    )
+),
+prs AS (
+  INSERT INTO repo_pull_requests (repo_id, number, title, url, merge_commit_id, merged_at, updated_at)
+  SELECT %s, number, title, url, merge_commit_id, merged_at, updated_at FROM batch
+  ON CONFLICT (repo_id, number) DO UPDATE
+  SET
+    title           = excluded.title,
+    url             = excluded.url,
+    merge_commit_id = excluded.merge_commit_id,
+    merged_at       = excluded.merged_at,
+    updated_at      = excluded.updated_at
+  RETURNING id, number
+),
+commits AS (
+  INSERT INTO repo_pull_request_commits (pull_request_id, repo_id, commit_id)
+  SELECT prs.id, %s, jsonb_array_elements_text(batch.commit_ids)
+  FROM prs JOIN batch ON batch.number = prs.number
+  ON CONFLICT DO NOTHING
+),
+reviewers AS (
+  INSERT INTO repo_pull_request_reviewers (pull_request_id, account_id, login)
+  SELECT prs.id, r.account_id, r.login
+  FROM prs JOIN batch ON batch.number = prs.number,
+  jsonb_to_recordset(batch.reviewers) AS r(account_id text, login text)
+  ON CONFLICT (pull_request_id, account_id) DO UPDATE
+  SET login = excluded.login
+)
+INSERT INTO repo_pull_request_syncs (repo_id, synced_until)
+VALUES (%s, %s)
+ON CONFLICT (repo_id) DO UPDATE
+SET
+  synced_until = GREATEST(repo_pull_request_syncs.synced_until, excluded.synced_until),
+  updated_at   = now()
+`
+
 const listAllRepoNamesQueryFmtstr = `
 -- source: cmd/repo-updater/repos/store.go:DBStore.ListAllRepoNames
 SELECT
@@ -740,6 +911,13 @@ func nullTimeColumn(t time.Time) *time.Time {
 	return &t
 }
 
+func nullInt32Column(n int32) *int32 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
 func nullStringColumn(s string) *string {
 	if s == "" {
 		return nil
@@ -802,6 +980,7 @@ func scanExternalService(svc *ExternalService, s scanner) error {
 		&svc.CreatedAt,
 		&dbutil.NullTime{Time: &svc.UpdatedAt},
 		&dbutil.NullTime{Time: &svc.DeletedAt},
+		&dbutil.NullInt32{N: &svc.NamespaceOrgID},
 	)
 }
 
@@ -847,6 +1026,12 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(awscodecommit.Repository)
 	case "gitolite":
 		r.Metadata = new(gitolite.Repo)
+	case "perforce":
+		r.Metadata = new(perforce.Depot)
+	case "svn":
+		r.Metadata = new(svn.Repo)
+	case "npm", "maven", "gomodules":
+		r.Metadata = new(pkgs.Package)
 	default:
 		return nil
 	}
@@ -1102,6 +1102,77 @@ func testDBStoreTransact(store *repos.DBStore) func(*testing.T) {
 	}
 }
 
+func testDBStoreUpsertPullRequests(store *repos.DBStore) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		txstore, err := store.Transact(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer txstore.Done(&errRollback)
+		tx := txstore.(*repos.DBStore)
+
+		repo := &repos.Repo{
+			Name: "github.com/foo/bar",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "AAAAA==",
+				ServiceType: "github",
+				ServiceID:   "http://github.com",
+			},
+			Sources:  map[string]*repos.SourceInfo{},
+			Metadata: new(github.Repository),
+		}
+		if err := tx.UpsertRepos(ctx, repo); err != nil {
+			t.Fatal(err)
+		}
+
+		syncedUntil, err := tx.PullRequestsSyncedUntil(ctx, repo.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !syncedUntil.IsZero() {
+			t.Errorf("got synced until %s, want zero time", syncedUntil)
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		pr := &repos.PullRequest{
+			Number:        2,
+			Title:         "b",
+			URL:           "https://github.com/foo/bar/pull/2",
+			MergeCommitID: "2222222222222222222222222222222222222222",
+			HeadCommitIDs: []string{"3333333333333333333333333333333333333333"},
+			Reviewers:     []*repos.PullRequestReviewer{{AccountID: "7", Login: "alice"}},
+			MergedAt:      now,
+			UpdatedAt:     now,
+		}
+		prs := []*repos.PullRequest{
+			pr,
+			{Number: 1, Title: "a", MergedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour)},
+			pr, // listed twice
+		}
+		if err := tx.UpsertPullRequests(ctx, repo.ID, prs); err != nil {
+			t.Fatal(err)
+		}
+		if syncedUntil, err = tx.PullRequestsSyncedUntil(ctx, repo.ID); err != nil {
+			t.Fatal(err)
+		} else if !syncedUntil.Equal(now) {
+			t.Errorf("got synced until %s, want %s", syncedUntil, now)
+		}
+
+		// Upserting older pull requests doesn't move the sync time back.
+		prs = []*repos.PullRequest{{Number: 1, Title: "a2", MergedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Minute)}}
+		if err := tx.UpsertPullRequests(ctx, repo.ID, prs); err != nil {
+			t.Fatal(err)
+		}
+		if syncedUntil, err = tx.PullRequestsSyncedUntil(ctx, repo.ID); err != nil {
+			t.Fatal(err)
+		} else if !syncedUntil.Equal(now) {
+			t.Errorf("got synced until %s, want %s", syncedUntil, now)
+		}
+	}
+}
+
 func mkRepos(n int, base ...*repos.Repo) repos.Repos {
 	if len(base) == 0 {
 		return nil
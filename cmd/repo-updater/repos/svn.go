@@ -0,0 +1,109 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/svn"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A SVNSource yields repositories from a single Subversion connection configured
+// in Sourcegraph via the external services configuration. Each repository is
+// converted into a Git repository by gitserver.
+type SVNSource struct {
+	svc  *ExternalService
+	conn *schema.SubversionConnection
+	root *url.URL
+	cli  *svn.Client
+}
+
+// NewSVNSource returns a new SVNSource from the given external service.
+func NewSVNSource(svc *ExternalService, _ *httpcli.Factory) (*SVNSource, error) {
+	var c schema.SubversionConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	root, err := url.Parse(strings.TrimSuffix(c.Url, "/") + "/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	return &SVNSource{
+		svc:  svc,
+		conn: &c,
+		root: root,
+		cli:  svn.NewClient(c.Username, c.Password),
+	}, nil
+}
+
+// ListRepos returns all Subversion repositories selected by the connection configured
+// in Sourcegraph via the external services configuration.
+func (s SVNSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	names := s.conn.Repos
+	if len(names) == 0 {
+		var err error
+		if names, err = s.cli.ListDirs(ctx, s.root.String()); err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+	}
+
+	for _, name := range names {
+		repo, err := s.makeRepo(name)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: repo}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s SVNSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s SVNSource) makeRepo(name string) (*Repo, error) {
+	repoURL, err := s.root.Parse(url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+	meta := &svn.Repo{Name: name, URL: repoURL.String()}
+
+	layout := svn.StandardLayout
+	if l := s.conn.Layout; l != nil {
+		layout = svn.Layout{Trunk: l.Trunk, Branches: l.Branches, Tags: l.Tags}
+	}
+	remote := svn.Remote{
+		URL:      meta.URL,
+		Username: s.conn.Username,
+		Password: s.conn.Password,
+		Layout:   layout,
+	}
+	cloneURL, err := remote.CloneURL()
+	if err != nil {
+		return nil, err
+	}
+
+	urn := s.svc.URN()
+	repoName := string(reposource.SVNRepoName(s.conn.RepositoryPathPattern, s.root.Hostname(), name))
+	return &Repo{
+		Name:         repoName,
+		URI:          repoName,
+		ExternalRepo: svn.ExternalRepoSpec(meta, svn.ServiceID(s.root.String())),
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL,
+			},
+		},
+		Metadata: meta,
+	}, nil
+}
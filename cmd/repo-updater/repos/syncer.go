@@ -11,6 +11,7 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -41,6 +42,11 @@ type Syncer struct {
 	// Now is time.Now. Can be set by tests to get deterministic output.
 	Now func() time.Time
 
+	// RepoQuotas if non-nil returns the maximum number of repositories that may be synced from the
+	// external services of each organization (see ExternalService.NamespaceOrgID), by organization
+	// ID. Organizations without an entry are not limited.
+	RepoQuotas func(context.Context) (map[int32]int, error)
+
 	// lastSyncErr contains the last error returned by the Sourcer in each
 	// Sync. It's reset with each Sync and if the sync produced no error, it's
 	// set to nil.
@@ -50,12 +56,17 @@ type Syncer struct {
 	syncSignal signal
 }
 
-// Run runs the Sync at the specified interval.
+// Run runs the Sync at the specified interval. Syncs are skipped while maintenance mode is
+// enabled.
 func (s *Syncer) Run(pctx context.Context, interval time.Duration) error {
 	for pctx.Err() == nil {
 		ctx, cancel := contextWithSignalCancel(pctx, s.syncSignal.Watch())
 
-		if err := s.Sync(ctx); err != nil && s.Logger != nil {
+		if conf.MaintenanceModeEnabled() {
+			if s.Logger != nil {
+				s.Logger.Debug("Syncer: skipping sync in maintenance mode")
+			}
+		} else if err := s.Sync(ctx); err != nil && s.Logger != nil {
 			s.Logger.Error("Syncer", "error", err)
 		}
 
@@ -139,6 +150,12 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 		return errors.Wrap(err, "syncer.sync.store.list-repos")
 	}
 
+	if s.RepoQuotas != nil {
+		if sourced, err = s.enforceRepoQuotas(ctx, sourced, stored); err != nil {
+			return errors.Wrap(err, "syncer.sync.repo-quotas")
+		}
+	}
+
 	diff = NewDiff(sourced, stored)
 	upserts := s.upserts(diff)
 
@@ -379,11 +396,25 @@ func (s *Syncer) makeNewRepoInserter(ctx context.Context) (func(*Repo), error) {
 		return nil, err
 	}
 
+	// Repositories of organizations with a repository quota are only inserted by the final sync,
+	// which enforces the quota.
+	var limited map[string]int32
+	if s.RepoQuotas != nil {
+		if limited, _, err = s.limitedSources(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	return func(r *Repo) {
 		// We know this won't be an insert.
 		if _, ok := ids[r.ExternalRepo]; ok {
 			return
 		}
+		for urn := range r.Sources {
+			if _, ok := limited[urn]; ok {
+				return
+			}
+		}
 
 		err := s.insertIfNew(ctx, r)
 		if err != nil && s.Logger != nil {
@@ -393,6 +424,110 @@ func (s *Syncer) makeNewRepoInserter(ctx context.Context) (func(*Repo), error) {
 	}, nil
 }
 
+// limitedSources returns the organization of each external service (by URN) whose repositories
+// count against a repository quota, and the quotas by organization ID.
+func (s *Syncer) limitedSources(ctx context.Context) (map[string]int32, map[int32]int, error) {
+	quotas, err := s.RepoQuotas(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	svcs, err := s.Store.ListExternalServices(ctx, StoreListExternalServicesArgs{})
+	if err != nil {
+		return nil, nil, err
+	}
+	limited := map[string]int32{}
+	for _, svc := range svcs {
+		if _, ok := quotas[svc.NamespaceOrgID]; ok && svc.NamespaceOrgID != 0 {
+			limited[svc.URN()] = svc.NamespaceOrgID
+		}
+	}
+	return limited, quotas, nil
+}
+
+// enforceRepoQuotas removes the repositories that exceed the repository quota of an organization
+// from sourced (see applyRepoQuotas).
+func (s *Syncer) enforceRepoQuotas(ctx context.Context, sourced, stored Repos) (Repos, error) {
+	limited, quotas, err := s.limitedSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sourced, skipped := applyRepoQuotas(sourced, stored, limited, quotas)
+	for orgID, n := range skipped {
+		if s.Logger != nil {
+			s.Logger.Warn("Syncer: organization repository quota exceeded", "org", orgID, "quota", quotas[orgID], "skipped", n)
+		}
+	}
+	return sourced, nil
+}
+
+// applyRepoQuotas removes the sources of the external services in limited (by URN, with their
+// organization IDs) from the sourced repos that would exceed their organization's quota, and
+// returns the remaining sourced repos and the number of repos that were skipped per organization.
+//
+// Repos that are already stored are always kept (and count against the quota), so lowering a
+// quota below an organization's usage only prevents new repos from being added. New repos are
+// added in order of their names.
+func applyRepoQuotas(sourced, stored Repos, limited map[string]int32, quotas map[int32]int) (Repos, map[int32]int) {
+	if len(limited) == 0 {
+		return sourced, nil
+	}
+
+	isStored := make(map[api.ExternalRepoSpec]bool, len(stored))
+	for _, r := range stored {
+		isStored[r.ExternalRepo] = true
+	}
+	ordered := make(Repos, len(sourced))
+	copy(ordered, sourced)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if a, b := isStored[ordered[i].ExternalRepo], isStored[ordered[j].ExternalRepo]; a != b {
+			return a
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+
+	type repoOrg struct {
+		repo api.ExternalRepoSpec
+		org  int32
+	}
+	var (
+		accepted = map[repoOrg]bool{}
+		rejected = map[repoOrg]bool{}
+		counts   = map[int32]int{}
+		skipped  = map[int32]int{}
+	)
+	hasLimitedSource := map[*Repo]bool{}
+	for _, r := range ordered {
+		for urn := range r.Sources {
+			orgID, ok := limited[urn]
+			if !ok {
+				continue
+			}
+			hasLimitedSource[r] = true
+			k := repoOrg{repo: r.ExternalRepo, org: orgID}
+			if !accepted[k] && !rejected[k] {
+				if isStored[r.ExternalRepo] || counts[orgID] < quotas[orgID] {
+					accepted[k] = true
+					counts[orgID]++
+				} else {
+					rejected[k] = true
+					skipped[orgID]++
+				}
+			}
+			if rejected[k] {
+				delete(r.Sources, urn)
+			}
+		}
+	}
+
+	kept := sourced[:0:0]
+	for _, r := range sourced {
+		if len(r.Sources) > 0 || !hasLimitedSource[r] {
+			kept = append(kept, r)
+		}
+	}
+	return kept, skipped
+}
+
 func (s *Syncer) storedExternalIDs(ctx context.Context) (map[api.ExternalRepoSpec]struct{}, error) {
 	stored, err := s.Store.ListRepos(ctx, StoreListReposArgs{})
 	if err != nil {
@@ -974,3 +974,74 @@ func TestDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncer_SyncRepoQuotas(t *testing.T) {
+	ctx := context.Background()
+
+	orgSvc := &repos.ExternalService{ID: 1, Kind: "github", NamespaceOrgID: 7}
+	siteSvc := &repos.ExternalService{ID: 2, Kind: "github"}
+	store := new(repos.FakeStore)
+	if err := store.UpsertExternalServices(ctx, orgSvc, siteSvc); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := func(name string) *repos.Repo {
+		return &repos.Repo{
+			Name:     name,
+			Metadata: &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+		}
+	}
+	a, b, c, d := repo("a"), repo("b"), repo("c"), repo("d")
+
+	quota := 2
+	sync := func() []string {
+		t.Helper()
+		syncer := &repos.Syncer{
+			Store: store,
+			Sourcer: repos.NewFakeSourcer(nil,
+				repos.NewFakeSource(orgSvc, nil, d, c, b, a),
+				repos.NewFakeSource(siteSvc, nil, d),
+			),
+			DisableStreaming: true,
+			Now:              time.Now,
+			RepoQuotas: func(context.Context) (map[int32]int, error) {
+				return map[int32]int{7: quota}, nil
+			},
+		}
+		if err := syncer.Sync(ctx); err != nil {
+			t.Fatal(err)
+		}
+		stored, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, r := range stored {
+			names = append(names, r.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// d is over the quota, but is also synced from the site's external service, which has no
+	// quota.
+	if got, want := sync(), []string{"a", "b", "d"}; !cmp.Equal(got, want) {
+		t.Errorf("got repos %v, want %v", got, want)
+	}
+
+	// Lowering the quota doesn't remove stored repos.
+	quota = 1
+	if got, want := sync(), []string{"a", "b", "d"}; !cmp.Equal(got, want) {
+		t.Errorf("after lowering quota: got repos %v, want %v", got, want)
+	}
+
+	quota = 4
+	if got, want := sync(), []string{"a", "b", "c", "d"}; !cmp.Equal(got, want) {
+		t.Errorf("after raising quota: got repos %v, want %v", got, want)
+	}
+}
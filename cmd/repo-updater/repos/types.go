@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"github.com/xeipuuv/gojsonschema"
@@ -44,6 +45,10 @@ type ExternalService struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   time.Time
+
+	// NamespaceOrgID is the ID of the organization whose repository quota the repositories of
+	// this external service count against (or 0 if none).
+	NamespaceOrgID int32
 }
 
 // URN returns a unique resource identifier of this external service,
@@ -82,6 +87,10 @@ func (e *ExternalService) Update(n *ExternalService) (modified bool) {
 		e.DeletedAt, modified = n.DeletedAt, true
 	}
 
+	if e.NamespaceOrgID != n.NamespaceOrgID {
+		e.NamespaceOrgID, modified = n.NamespaceOrgID, true
+	}
+
 	return modified
 }
 
@@ -98,8 +107,18 @@ func (e ExternalService) Configuration() (cfg interface{}, _ error) {
 		cfg = &schema.GitLabConnection{}
 	case "gitolite":
 		cfg = &schema.GitoliteConnection{}
+	case "gomodules":
+		cfg = &schema.GoModulesConnection{}
+	case "maven":
+		cfg = &schema.MavenConnection{}
+	case "npm":
+		cfg = &schema.NPMConnection{}
+	case "perforce":
+		cfg = &schema.PerforceConnection{}
 	case "phabricator":
 		cfg = &schema.PhabricatorConnection{}
+	case "svn":
+		cfg = &schema.SubversionConnection{}
 	case "other":
 		cfg = &schema.OtherExternalServiceConnection{}
 	default:
@@ -468,8 +487,18 @@ func (e ExternalService) schema() string {
 		return schema.GitLabSchemaJSON
 	case "gitolite":
 		return schema.GitoliteSchemaJSON
+	case "gomodules":
+		return schema.GoModulesSchemaJSON
+	case "maven":
+		return schema.MavenSchemaJSON
+	case "npm":
+		return schema.NPMSchemaJSON
+	case "perforce":
+		return schema.PerforceSchemaJSON
 	case "phabricator":
 		return schema.PhabricatorSchemaJSON
+	case "svn":
+		return schema.SVNSchemaJSON
 	case "other":
 		return schema.OtherExternalServiceSchemaJSON
 	default:
@@ -599,6 +628,51 @@ func (r *Repo) CloneURLs() []string {
 	return urls
 }
 
+// ObjectPool returns the object pool that gitserver shares the Git objects of
+// this repo's fork network through, or nil if the repo is not a fork or its
+// parent is unknown. The pool is named after the parent repo, and its remote
+// URL is the repo's first clone URL with the repo's path replaced by the
+// parent's (which carries over the credentials of the clone URL).
+func (r *Repo) ObjectPool() *gitserverprotocol.ObjectPool {
+	if !r.Fork {
+		return nil
+	}
+
+	var path, parentPath string
+	switch m := r.Metadata.(type) {
+	case *github.Repository:
+		if m.Parent == nil {
+			return nil
+		}
+		path, parentPath = m.NameWithOwner, m.Parent.NameWithOwner
+	case *gitlab.Project:
+		if m.ForkedFromProject == nil {
+			return nil
+		}
+		path, parentPath = m.PathWithNamespace, m.ForkedFromProject.PathWithNamespace
+	default:
+		return nil
+	}
+
+	urls := r.CloneURLs()
+	if len(urls) == 0 || path == "" || parentPath == "" {
+		return nil
+	}
+	i := strings.LastIndex(urls[0], path)
+	if i < 0 {
+		return nil
+	}
+	host := strings.SplitN(r.URI, "/", 2)[0]
+	if host == "" {
+		return nil
+	}
+
+	return &gitserverprotocol.ObjectPool{
+		Name: api.RepoName(host + "/" + parentPath),
+		URL:  urls[0][:i] + parentPath + urls[0][i+len(path):],
+	}
+}
+
 // ExternalServiceIDs returns the IDs of the external services this
 // repo belongs to.
 func (r *Repo) ExternalServiceIDs() []int64 {
@@ -1,6 +1,7 @@
 package repos
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 )
 
@@ -459,6 +461,66 @@ func TestPick(t *testing.T) {
 	}
 }
 
+func TestRepo_ObjectPool(t *testing.T) {
+	githubFork := func(parent *github.ParentRepository) *Repo {
+		return &Repo{
+			Name: "github.com/alice/bar",
+			URI:  "github.com/alice/bar",
+			Fork: true,
+			Sources: map[string]*SourceInfo{
+				"extsvc:github:1": {ID: "extsvc:github:1", CloneURL: "https://secret@github.com/alice/bar"},
+			},
+			Metadata: &github.Repository{NameWithOwner: "alice/bar", IsFork: true, Parent: parent},
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		repo *Repo
+		want *gitserverprotocol.ObjectPool
+	}{
+		{
+			name: "github fork",
+			repo: githubFork(&github.ParentRepository{NameWithOwner: "foo/bar"}),
+			want: &gitserverprotocol.ObjectPool{Name: "github.com/foo/bar", URL: "https://secret@github.com/foo/bar"},
+		},
+		{
+			name: "github fork with unknown parent",
+			repo: githubFork(nil),
+		},
+		{
+			name: "gitlab fork over ssh",
+			repo: &Repo{
+				Name: "gitlab/alice/bar",
+				URI:  "gitlab.com/alice/bar",
+				Fork: true,
+				Sources: map[string]*SourceInfo{
+					"extsvc:gitlab:1": {ID: "extsvc:gitlab:1", CloneURL: "git@gitlab.com:alice/bar.git"},
+				},
+				Metadata: &gitlab.Project{
+					ProjectCommon:     gitlab.ProjectCommon{PathWithNamespace: "alice/bar"},
+					ForkedFromProject: &gitlab.ProjectCommon{PathWithNamespace: "foo/sub/bar"},
+				},
+			},
+			want: &gitserverprotocol.ObjectPool{Name: "gitlab.com/foo/sub/bar", URL: "git@gitlab.com:foo/sub/bar.git"},
+		},
+		{
+			name: "not a fork",
+			repo: &Repo{
+				URI:      "github.com/foo/bar",
+				Sources:  map[string]*SourceInfo{"extsvc:github:1": {CloneURL: "https://github.com/foo/bar"}},
+				Metadata: &github.Repository{NameWithOwner: "foo/bar"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := tc.repo.ObjectPool(); !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("got object pool %+v, want %+v", have, tc.want)
+			}
+		})
+	}
+}
+
 func formatJSON(t testing.TB, s string) string {
 	formatted, err := jsonc.Format(s, nil)
 	if err != nil {
@@ -178,6 +178,7 @@ func Main(enterpriseInit EnterpriseInit) {
 		DisableStreaming: !streamingSyncer,
 		Logger:           log15.Root(),
 		Now:              clock,
+		RepoQuotas:       repos.NewDBStore(db, sql.TxOptions{}).RepoQuotas,
 	}
 
 	if envvar.SourcegraphDotComMode() {
@@ -191,6 +192,7 @@ func Main(enterpriseInit EnterpriseInit) {
 	server.Syncer = syncer
 
 	go repos.RunPhabricatorRepositorySyncWorker(ctx, store)
+	go repos.RunPullRequestSyncWorker(ctx, repos.NewDBStore(db, sql.TxOptions{}), cf)
 
 	if !envvar.SourcegraphDotComMode() {
 		// git-server repos purging thread
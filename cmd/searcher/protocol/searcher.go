@@ -105,6 +105,16 @@ type PatternInfo struct {
 
 	// CombyRule is a rule that constrains matching for structural search. It only applies when IsStructuralPat is true.
 	CombyRule string
+
+	// IncludeSubmatches if true will include the offsets of the pattern's capture groups in
+	// LineMatch.SubmatchOffsetAndLengths. It only applies when IsRegExp is true.
+	IncludeSubmatches bool
+
+	// Scope if set restricts content matches to those entirely contained in
+	// comments ("comment"), string literals ("string") or the rest of the
+	// code ("code"), as classified by a lightweight tokenizer for the file's
+	// language. All content of files in unsupported languages is code.
+	Scope string
 }
 
 func (p *PatternInfo) String() string {
@@ -127,6 +137,12 @@ func (p *PatternInfo) String() string {
 	if p.FileMatchLimit > 0 {
 		args = append(args, fmt.Sprintf("filematchlimit:%d", p.FileMatchLimit))
 	}
+	if p.IncludeSubmatches {
+		args = append(args, "submatches")
+	}
+	if p.Scope != "" {
+		args = append(args, fmt.Sprintf("scope:%s", p.Scope))
+	}
 
 	path := "glob"
 	if p.PathPatternsAreRegExps {
@@ -183,4 +199,12 @@ type LineMatch struct {
 
 	// LimitHit is true if OffsetAndLengths may not include all OffsetAndLengths.
 	LimitHit bool
+
+	// SubmatchOffsetAndLengths is a slice of 2-tuples (Offset, Length)
+	// representing the match of each capture group of the pattern, measured
+	// like OffsetAndLengths. The offset is -1 if the capture group did not
+	// participate in the match or is not contained in this line. It is only
+	// set if PatternInfo.IncludeSubmatches is true, in which case it has an
+	// entry for every capture group.
+	SubmatchOffsetAndLengths [][2]int
 }
@@ -0,0 +1,291 @@
+package search
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/pkg/errors"
+)
+
+// scopeClass is the class of a region of a file's content that a search can be scoped to.
+type scopeClass uint8
+
+const (
+	scopeCode scopeClass = iota
+	scopeComment
+	scopeString
+)
+
+// parseScope parses the value of protocol.PatternInfo.Scope. The zero value and ok == false are
+// returned for the empty scope (which matches everything).
+func parseScope(scope string) (class scopeClass, ok bool, err error) {
+	switch scope {
+	case "":
+		return 0, false, nil
+	case "code":
+		return scopeCode, true, nil
+	case "comment":
+		return scopeComment, true, nil
+	case "string":
+		return scopeString, true, nil
+	}
+	return 0, false, errors.Errorf("invalid scope %q (valid values are: comment, string, code)", scope)
+}
+
+// scopeRegion is a region [start, end) of a file's content that is a comment or a string. The
+// content that is not in any region is code.
+type scopeRegion struct {
+	start, end int
+	class      scopeClass
+}
+
+// scopeLexer is a lightweight tokenizer that only recognizes a language's comments and string
+// literals. It doesn't need to understand the rest of the language's syntax, which makes it fast
+// and easy to support many languages, at the expense of misclassifying some unusual constructs
+// (such as heredocs).
+type scopeLexer struct {
+	lineComments  []string    // e.g. "//"
+	blockComments [][2]string // e.g. {"/*", "*/"}
+
+	// strings are the string delimiters. Longer delimiters must come first (e.g. `"""` before
+	// `"`). Strings delimited by a single quote character end at the end of the line if they
+	// aren't terminated, to limit the damage of misclassifying a quote character.
+	strings []string
+
+	// rawStrings are the string delimiters of strings in which \ doesn't escape the delimiter.
+	rawStrings map[string]bool
+}
+
+var (
+	cLikeLexer = &scopeLexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{`"`, "'"},
+	}
+	goLexer = &scopeLexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{`"`, "'", "`"},
+		rawStrings:    map[string]bool{"`": true},
+	}
+	jsLexer = &scopeLexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{`"`, "'", "`"},
+	}
+	rustLexer = &scopeLexer{
+		// ' is omitted because it's ambiguous with lifetimes.
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{`"`},
+	}
+	phpLexer = &scopeLexer{
+		lineComments:  []string{"//", "#"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{`"`, "'"},
+	}
+	cssLexer = &scopeLexer{
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{`"`, "'"},
+	}
+	pythonLexer = &scopeLexer{
+		lineComments: []string{"#"},
+		strings:      []string{`"""`, "'''", `"`, "'"},
+	}
+	hashLexer = &scopeLexer{
+		lineComments: []string{"#"},
+		strings:      []string{`"`, "'"},
+	}
+	sqlLexer = &scopeLexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []string{"'", `"`},
+	}
+	luaLexer = &scopeLexer{
+		blockComments: [][2]string{{"--[[", "]]"}},
+		lineComments:  []string{"--"},
+		strings:       []string{`"`, "'"},
+	}
+	haskellLexer = &scopeLexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"{-", "-}"}},
+		strings:       []string{`"`},
+	}
+	htmlLexer = &scopeLexer{
+		blockComments: [][2]string{{"<!--", "-->"}},
+		strings:       []string{`"`, "'"},
+	}
+)
+
+// scopeLexersByExtension maps file extensions to the lexer for their language.
+var scopeLexersByExtension = map[string]*scopeLexer{
+	".go": goLexer,
+
+	".c": cLikeLexer, ".h": cLikeLexer, ".cc": cLikeLexer, ".cpp": cLikeLexer, ".cxx": cLikeLexer,
+	".hh": cLikeLexer, ".hpp": cLikeLexer, ".m": cLikeLexer, ".mm": cLikeLexer,
+	".java": cLikeLexer, ".cs": cLikeLexer, ".kt": cLikeLexer, ".kts": cLikeLexer,
+	".scala": cLikeLexer, ".swift": cLikeLexer, ".groovy": cLikeLexer, ".dart": cLikeLexer,
+	".proto": cLikeLexer,
+
+	".js": jsLexer, ".jsx": jsLexer, ".mjs": jsLexer, ".ts": jsLexer, ".tsx": jsLexer,
+
+	".rs": rustLexer,
+
+	".php": phpLexer,
+
+	".css": cssLexer, ".scss": cLikeLexer, ".less": cLikeLexer,
+
+	".py": pythonLexer,
+
+	".rb": hashLexer, ".sh": hashLexer, ".bash": hashLexer, ".zsh": hashLexer, ".pl": hashLexer,
+	".yaml": hashLexer, ".yml": hashLexer, ".toml": hashLexer, ".r": hashLexer,
+	".conf": hashLexer, ".ini": hashLexer, ".tf": hashLexer,
+
+	".sql": sqlLexer,
+	".lua": luaLexer,
+	".hs":  haskellLexer,
+
+	".html": htmlLexer, ".htm": htmlLexer, ".xml": htmlLexer,
+}
+
+// scopeLexersByName maps file names (without an extension) to the lexer for their language.
+var scopeLexersByName = map[string]*scopeLexer{
+	"Dockerfile":  hashLexer,
+	"Makefile":    hashLexer,
+	"BUILD":       pythonLexer,
+	"WORKSPACE":   pythonLexer,
+	"CMakeLists":  hashLexer,
+	"Jenkinsfile": cLikeLexer,
+}
+
+// scopeLexerFor returns the lexer for the file, or nil if its language is not supported (in which
+// case all of its content is code).
+func scopeLexerFor(name string) *scopeLexer {
+	base := path.Base(name)
+	if l, ok := scopeLexersByName[base]; ok {
+		return l
+	}
+	return scopeLexersByExtension[strings.ToLower(path.Ext(base))]
+}
+
+// regions returns the comment and string regions of data, in order.
+func (l *scopeLexer) regions(data []byte) []scopeRegion {
+	var regions []scopeRegion
+	i := 0
+next:
+	for i < len(data) {
+		c := data[i]
+		// Fast path: skip bytes that can't start a comment or string.
+		if c == ' ' || c == '\t' || c == '\n' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			i++
+			continue
+		}
+		rest := data[i:]
+
+		for _, bc := range l.blockComments {
+			if bc[0][0] == c && bytes.HasPrefix(rest, []byte(bc[0])) {
+				end := bytes.Index(rest[len(bc[0]):], []byte(bc[1]))
+				if end < 0 {
+					end = len(data)
+				} else {
+					end = i + len(bc[0]) + end + len(bc[1])
+				}
+				regions = append(regions, scopeRegion{start: i, end: end, class: scopeComment})
+				i = end
+				continue next
+			}
+		}
+		for _, lc := range l.lineComments {
+			if lc[0] == c && bytes.HasPrefix(rest, []byte(lc)) {
+				end := bytes.IndexByte(rest, '\n')
+				if end < 0 {
+					end = len(data)
+				} else {
+					end += i
+				}
+				regions = append(regions, scopeRegion{start: i, end: end, class: scopeComment})
+				i = end
+				continue next
+			}
+		}
+		for _, delim := range l.strings {
+			if delim[0] == c && bytes.HasPrefix(rest, []byte(delim)) {
+				end := l.stringEnd(data, i+len(delim), delim)
+				regions = append(regions, scopeRegion{start: i, end: end, class: scopeString})
+				i = end
+				continue next
+			}
+		}
+		i++
+	}
+	return regions
+}
+
+// stringEnd returns the end of the string with the given delimiter whose content starts at i.
+func (l *scopeLexer) stringEnd(data []byte, i int, delim string) int {
+	singleLine := len(delim) == 1 && delim != "`"
+	escapes := !l.rawStrings[delim]
+	for i < len(data) {
+		switch c := data[i]; {
+		case c == '\\' && escapes:
+			i += 2
+			continue
+		case c == '\n' && singleLine:
+			return i
+		case c == delim[0] && bytes.HasPrefix(data[i:], []byte(delim)):
+			return i + len(delim)
+		}
+		i++
+	}
+	return len(data)
+}
+
+// inScope reports whether the match [start, end) is entirely contained in content of the given
+// class.
+func inScope(regions []scopeRegion, class scopeClass, start, end int) bool {
+	if end == start {
+		end = start + 1
+	}
+	// The first region that ends after start.
+	i := sort.Search(len(regions), func(i int) bool { return regions[i].end > start })
+	if class == scopeCode {
+		return i == len(regions) || regions[i].start >= end
+	}
+	return i < len(regions) && regions[i].class == class && regions[i].start <= start && end <= regions[i].end
+}
+
+// scopeRegionsCache caches the regions of recently searched files, because repeated searches of a
+// repository often search the same files.
+var scopeRegionsCache = struct {
+	sync.Mutex
+	*lru.Cache
+}{Cache: lru.New(10000)}
+
+// scopeRegions returns the comment and string regions of the file with the given name and content.
+// The result is cached if cacheKey is nonempty.
+func scopeRegions(cacheKey, name string, data []byte) []scopeRegion {
+	l := scopeLexerFor(name)
+	if l == nil {
+		return nil
+	}
+	if cacheKey == "" {
+		return l.regions(data)
+	}
+
+	key := cacheKey + "\x00" + name
+	scopeRegionsCache.Lock()
+	v, ok := scopeRegionsCache.Get(key)
+	scopeRegionsCache.Unlock()
+	if ok {
+		return v.([]scopeRegion)
+	}
+	regions := l.regions(data)
+	scopeRegionsCache.Lock()
+	scopeRegionsCache.Add(key, regions)
+	scopeRegionsCache.Unlock()
+	return regions
+}
@@ -0,0 +1,154 @@
+package search
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/store"
+)
+
+func TestScopeRegions(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string // the text of each region, prefixed by its class
+	}{
+		{
+			name: "a.go",
+			data: "// TODO: x\nx := \"a \\\" // b\" + `c\n\\` /* d\n*/ + 'e'\n",
+			want: []string{"comment // TODO: x", "string \"a \\\" // b\"", "string `c\n\\`", "comment /* d\n*/", "string 'e'"},
+		},
+		{
+			name: "a.py",
+			data: "x = '''a # b\n''' # c 'd'\ny = \"it's\"\n",
+			want: []string{"string '''a # b\n'''", "comment # c 'd'", "string \"it's\""},
+		},
+		{
+			name: "A.TS",
+			data: "let x = `a ${b}` // c",
+			want: []string{"string `a ${b}`", "comment // c"},
+		},
+		{
+			name: "unterminated.c",
+			data: "x = 'a\ny /* z",
+			want: []string{"string 'a", "comment /* z"},
+		},
+		{
+			name: "Dockerfile",
+			data: "# syntax\nRUN echo \"x\"",
+			want: []string{"comment # syntax", "string \"x\""},
+		},
+		{
+			name: "README",
+			data: "// not a comment",
+			want: nil,
+		},
+	}
+	classes := map[scopeClass]string{scopeComment: "comment", scopeString: "string"}
+	for _, test := range tests {
+		var got []string
+		for _, r := range scopeRegions("", test.name, []byte(test.data)) {
+			got = append(got, classes[r.class]+" "+test.data[r.start:r.end])
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got regions %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestInScope(t *testing.T) {
+	//                    0         1         2
+	//                    0123456789012345678901234
+	data := []byte(`x := "abc" // TODO abc`)
+	regions := scopeRegions("", "a.go", data)
+	tests := []struct {
+		class      scopeClass
+		start, end int
+		want       bool
+	}{
+		{scopeCode, 0, 1, true},
+		{scopeCode, 0, 6, false},
+		{scopeString, 6, 9, true},
+		{scopeString, 5, 10, true},
+		{scopeString, 6, 12, false},
+		{scopeComment, 14, 18, true},
+		{scopeComment, 19, 22, true},
+		{scopeComment, 6, 9, false},
+		{scopeCode, 10, 10, true},
+		{scopeCode, 11, 11, false},
+	}
+	for _, test := range tests {
+		if got := inScope(regions, test.class, test.start, test.end); got != test.want {
+			t.Errorf("inScope(%d, %d, %d) = %v, want %v", test.class, test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestRegexSearchScope(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, data := range map[string]string{
+		"main.go":   "// TODO: remove\nfunc TODO() {\n\tlog.Print(\"TODO\")\n}\n",
+		"README.md": "TODO\n",
+	} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"comment": {"main.go:0"},
+		"string":  {"main.go:2"},
+		"code":    {"README.md:0", "main.go:1"},
+		"":        {"README.md:0", "main.go:0", "main.go:1", "main.go:2"},
+	}
+	for scope, want := range tests {
+		rg, err := compile(&protocol.PatternInfo{Pattern: "TODO", IsCaseSensitive: true, Scope: scope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileMatches, _, err := regexSearch(context.Background(), rg, zf, 0, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, fm := range fileMatches {
+			for _, lm := range fm.LineMatches {
+				got = append(got, fm.Path+":"+strconv.Itoa(lm.LineNumber))
+			}
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("scope:%s: got matches %v, want %v", scope, got, want)
+		}
+	}
+
+	if _, err := compile(&protocol.PatternInfo{Pattern: "TODO", Scope: "docs"}); err == nil {
+		t.Error("expected error for invalid scope")
+	}
+}
+
+func BenchmarkScopeRegions(b *testing.B) {
+	data := []byte(strings.Repeat("// Package foo does things.\nfunc foo(s string) error {\n\treturn fmt.Errorf(\"foo: %q\", s) /* bar */\n}\n", 10000))
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scopeRegions("", "foo.go", data)
+	}
+}
@@ -46,6 +46,20 @@ type readerGrep struct {
 	// ignoreCase if true means we need to do case insensitive matching.
 	ignoreCase bool
 
+	// submatches if true means we need to find the offsets of re's capture
+	// groups in each match.
+	submatches bool
+
+	// scoped if true means we only return matches that are entirely
+	// contained in content of class scope (see scope.go).
+	scoped bool
+	scope  scopeClass
+
+	// scopeCacheKey, if set, is the key under which the scope regions of
+	// the searched files are cached. It must identify the archive being
+	// searched.
+	scopeCacheKey string
+
 	// transformBuf is reused between file searches to avoid
 	// re-allocating. It is only used if we need to transform the input
 	// before matching. For example we lower case the input in the case of
@@ -112,6 +126,11 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		}
 	}
 
+	scope, scoped, err := parseScope(p.Scope)
+	if err != nil {
+		return nil, err
+	}
+
 	pathOptions := pathmatch.CompileOptions{
 		RegExp:        p.PathPatternsAreRegExps,
 		CaseSensitive: p.PathPatternsAreCaseSensitive,
@@ -124,6 +143,9 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 	return &readerGrep{
 		re:               re,
 		ignoreCase:       !p.IsCaseSensitive,
+		submatches:       p.IncludeSubmatches && p.IsRegExp && re != nil,
+		scoped:           scoped && re != nil,
+		scope:            scope,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
 	}, nil
@@ -135,6 +157,10 @@ func (rg *readerGrep) Copy() *readerGrep {
 	return &readerGrep{
 		re:               rg.re,
 		ignoreCase:       rg.ignoreCase,
+		submatches:       rg.submatches,
+		scoped:           rg.scoped,
+		scope:            rg.scope,
+		scopeCacheKey:    rg.scopeCacheKey,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
 	}
@@ -185,7 +211,28 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 		return nil, false, nil
 	}
 
-	locs := rg.re.FindAllIndex(fileMatchBuf, maxLineMatches+1)
+	limit := maxLineMatches + 1
+	if rg.scoped {
+		// Matches that are out of scope are removed below, so we need all of
+		// them to find the first maxLineMatches in scope.
+		limit = -1
+	}
+	var locs [][]int
+	if rg.submatches {
+		locs = rg.re.FindAllSubmatchIndex(fileMatchBuf, limit)
+	} else {
+		locs = rg.re.FindAllIndex(fileMatchBuf, limit)
+	}
+	if rg.scoped && len(locs) > 0 {
+		regions := scopeRegions(rg.scopeCacheKey, f.Name, fileBuf)
+		inScopeLocs := locs[:0]
+		for _, loc := range locs {
+			if inScope(regions, rg.scope, loc[0], loc[1]) {
+				inScopeLocs = append(inScopeLocs, loc)
+			}
+		}
+		locs = inScopeLocs
+	}
 	lastStart := 0
 	lastLineNumber := 0
 	lastMatchIndex := 0
@@ -216,7 +263,11 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 
 		lastMatchIndex = matchIndex
 		lastLineNumber = lineNumber
+		n := len(matches)
 		matches = appendMatches(matches, fileBuf[lineStart:lineEnd], fileMatchBuf[lineStart:lineEnd], lineNumber, start-lineStart, end-lineStart)
+		if rg.submatches {
+			setSubmatches(matches[n:], fileMatchBuf[lineStart:lineEnd], lineStart, match[2:])
+		}
 
 		if len(matches) > maxLineMatches {
 			matches = matches[:maxLineMatches]
@@ -283,6 +334,30 @@ func appendMatches(matches []protocol.LineMatch, fileBuf []byte, matchLineBuf []
 	return matches
 }
 
+// setSubmatches sets the SubmatchOffsetAndLengths of the LineMatches
+// appended for a single match, whose lines are matchLineBuf starting at
+// lineStart in the file. submatch contains the start and end indices of each
+// capture group in the file, as returned by FindAllSubmatchIndex. Only capture
+// groups contained in the first line of the match are reported.
+func setSubmatches(matches []protocol.LineMatch, matchLineBuf []byte, lineStart int, submatch []int) {
+	firstLineEnd := bytes.IndexByte(matchLineBuf, '\n')
+	if firstLineEnd < 0 {
+		firstLineEnd = len(matchLineBuf)
+	}
+	for i := range matches {
+		offsets := make([][2]int, len(submatch)/2)
+		for j := range offsets {
+			start, end := submatch[2*j]-lineStart, submatch[2*j+1]-lineStart
+			if i > 0 || submatch[2*j] < 0 || end > firstLineEnd {
+				offsets[j] = [2]int{-1, 0}
+				continue
+			}
+			offsets[j] = [2]int{utf8.RuneCount(matchLineBuf[:start]), utf8.RuneCount(matchLineBuf[start:end])}
+		}
+		matches[i].SubmatchOffsetAndLengths = offsets
+	}
+}
+
 // FindZip is a convenience function to run Find on f.
 func (rg *readerGrep) FindZip(zf *store.ZipFile, f *store.SrcFile) (protocol.FileMatch, error) {
 	lm, limitHit, err := rg.Find(zf, f)
@@ -475,6 +475,65 @@ func TestMaxMatches(t *testing.T) {
 	}
 }
 
+func TestSubmatches(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "go.mod", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("\tgithub.com/pkg/errors v0.8.1\n\tgithub.com/Foo/bar v1.2.3 // indirect\nx\ny\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rg, err := compile(&protocol.PatternInfo{Pattern: `(\S+) (v[\d.]+)|(x)\n(y)`, IsRegExp: true, IncludeSubmatches: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileMatches, _, err := regexSearch(context.Background(), rg, zf, 0, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []protocol.LineMatch{
+		{
+			Preview:                  "\tgithub.com/pkg/errors v0.8.1",
+			LineNumber:               0,
+			OffsetAndLengths:         [][2]int{{1, 28}},
+			SubmatchOffsetAndLengths: [][2]int{{1, 21}, {23, 6}, {-1, 0}, {-1, 0}},
+		},
+		{
+			Preview:                  "\tgithub.com/Foo/bar v1.2.3 // indirect",
+			LineNumber:               1,
+			OffsetAndLengths:         [][2]int{{1, 25}},
+			SubmatchOffsetAndLengths: [][2]int{{1, 18}, {20, 6}, {-1, 0}, {-1, 0}},
+		},
+		{
+			// Capture groups are only reported on the first line of a match.
+			Preview:                  "x",
+			LineNumber:               2,
+			OffsetAndLengths:         [][2]int{{0, 2}},
+			SubmatchOffsetAndLengths: [][2]int{{-1, 0}, {-1, 0}, {0, 1}, {-1, 0}},
+		},
+		{
+			Preview:                  "y",
+			LineNumber:               3,
+			OffsetAndLengths:         [][2]int{{0, 1}},
+			SubmatchOffsetAndLengths: [][2]int{{-1, 0}, {-1, 0}, {-1, 0}, {-1, 0}},
+		},
+	}
+	if len(fileMatches) != 1 {
+		t.Fatalf("expected 1 file match, got %d", len(fileMatches))
+	}
+	if got := fileMatches[0].LineMatches; !reflect.DeepEqual(got, want) {
+		t.Errorf("got line matches %+v, want %+v", got, want)
+	}
+}
+
 // Tests that:
 //
 // - IncludePatterns can match the path in any order
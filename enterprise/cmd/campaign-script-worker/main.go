@@ -0,0 +1,72 @@
+// Command campaign-script-worker runs the campaign scripts of campaign plans (see
+// campaigns.RunCampaignScriptJobs). It is separate from the frontend, so that campaign scripts
+// and the code they run never share a container with the frontend's configuration, secrets and
+// processes.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/debugserver"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+func main() {
+	ctx := context.Background()
+	env.Lock()
+	env.HandleHelpFlag()
+	tracer.Init()
+
+	go debugserver.Start()
+
+	// Campaign scripts run in checkouts from gitserver, so wait until the frontend and gitserver
+	// started up.
+	if err := api.InternalClient.WaitForFrontend(ctx); err != nil {
+		log.Fatalf("sourcegraph-frontend not reachable: %v", err)
+	}
+	log15.Debug("detected frontend ready")
+
+	if err := gitserver.DefaultClient.WaitForGitServers(ctx); err != nil {
+		log.Fatalf("gitservers not reachable: %v", err)
+	}
+	log15.Debug("detected gitservers ready")
+
+	// Leave the jobs pending instead of failing all of them if the sandbox can't be set up. This
+	// doesn't exit, because that would stop all services of the single-container deployment.
+	for {
+		err := campaigns.CheckScriptSandbox(ctx)
+		if err == nil {
+			break
+		}
+		log15.Error("Campaign scripts can't run in their sandbox, see https://docs.sourcegraph.com/user/campaigns#running-a-campaign-script-on-the-server", "error", err)
+		time.Sleep(time.Minute)
+	}
+
+	dsn := conf.Get().ServiceConnections.PostgresDSN
+	conf.Watch(func() {
+		newDSN := conf.Get().ServiceConnections.PostgresDSN
+		if dsn != newDSN {
+			// Restart to use the new DSN (kubernetes/docker/goreman will start us back up).
+			log.Fatalf("Detected database DSN change, restarting to take effect: %q", newDSN)
+		}
+	})
+	db, err := dbutil.NewDB(dsn, "campaign-script-worker")
+	if err != nil {
+		log.Fatalf("failed to initialize db store: %v", err)
+	}
+
+	clock := func() time.Time {
+		return time.Now().UTC().Truncate(time.Microsecond)
+	}
+	campaigns.RunCampaignScriptJobs(ctx, campaigns.NewStoreWithClock(db, clock), clock, 5*time.Second)
+	select {}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/app"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/shared"
@@ -66,6 +67,7 @@ func main() {
 	}
 
 	campaignsStore := campaigns.NewStoreWithClock(dbconn.Global, clock)
+	app.RegisterCampaignFeed(campaignsResolvers.NewCampaignFeed(campaignsStore))
 	repositories := repos.NewDBStore(dbconn.Global, sql.TxOptions{})
 
 	githubWebhook := campaigns.NewGitHubWebhook(campaignsStore, repositories, clock)
@@ -125,6 +127,7 @@ func initResolvers() {
 
 func initLSIFEndpoints() {
 	httpapi.NewLSIFServerProxy = proxy.NewProxy
+	httpapi.EnforceUploadAuth = proxy.EnforceAuth
 }
 
 type usersStore struct{}
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repoupdater"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/shared"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -44,9 +45,11 @@ func enterpriseInit(db *sql.DB, repoStore repos.Store, cf *httpcli.Factory, serv
 		// Set up syncer
 		go func() {
 			for {
-				err := syncer.Sync(ctx)
-				if err != nil {
-					log15.Error("Syncing Changesets", "err", err)
+				if !conf.MaintenanceModeEnabled() {
+					err := syncer.Sync(ctx)
+					if err != nil {
+						log15.Error("Syncing Changesets", "err", err)
+					}
 				}
 				time.Sleep(2 * time.Minute)
 			}
@@ -55,9 +58,11 @@ func enterpriseInit(db *sql.DB, repoStore repos.Store, cf *httpcli.Factory, serv
 		// Set up expired campaign deletion
 		go func() {
 			for {
-				err := campaignsStore.DeleteExpiredCampaignPlans(ctx)
-				if err != nil {
-					log15.Error("DeleteExpiredCampaignPlans", "error", err)
+				if !conf.MaintenanceModeEnabled() {
+					err := campaignsStore.DeleteExpiredCampaignPlans(ctx)
+					if err != nil {
+						log15.Error("DeleteExpiredCampaignPlans", "error", err)
+					}
 				}
 				time.Sleep(2 * time.Minute)
 			}
@@ -13,5 +13,6 @@ func main() {
 	if debug {
 		log.Println("enterprise edition")
 	}
+	shared.ProcfileAdditions = append(shared.ProcfileAdditions, `campaign-script-worker: campaign-script-worker`)
 	shared.Main()
 }
@@ -10,6 +10,7 @@ import (
 )
 
 var allDockerImages = []string{
+	"campaign-script-worker",
 	"frontend",
 	"github-proxy",
 	"gitserver",
@@ -1,5 +1,14 @@
 package campaigns
 
+import "github.com/sourcegraph/sourcegraph/internal/campaigns"
+
 const (
-	campaignTypePatch = "patch"
+	campaignTypePatch  = "patch"
+	campaignTypeScript = "script"
 )
+
+// IsScriptPlan reports whether the CampaignPlan was created from a campaign
+// script, i.e. whether its CampaignJobs are executed by RunCampaignScriptJobs.
+func IsScriptPlan(p *campaigns.CampaignPlan) bool {
+	return p.CampaignType == campaignTypeScript
+}
@@ -88,6 +88,20 @@ func (r *campaignPlanResolver) PreviewURL() string {
 	return u.String()
 }
 
+func (r *campaignPlanResolver) ScriptRuns(
+	ctx context.Context,
+	args *graphqlutil.ConnectionArgs,
+) graphqlbackend.CampaignScriptRunsConnectionResolver {
+	return &campaignScriptRunsConnectionResolver{
+		store:    r.store,
+		isScript: ee.IsScriptPlan(r.campaignPlan),
+		opts: ee.ListCampaignJobsOpts{
+			CampaignPlanID: r.campaignPlan.ID,
+			Limit:          int(args.GetFirst()),
+		},
+	}
+}
+
 type campaignJobsConnectionResolver struct {
 	store *ee.Store
 	opts  ee.ListCampaignJobsOpts
@@ -400,3 +414,125 @@ func diffPathOrNull(path string) *string {
 	}
 	return &path
 }
+
+type campaignScriptRunsConnectionResolver struct {
+	store    *ee.Store
+	isScript bool
+	opts     ee.ListCampaignJobsOpts
+
+	// cache results because they are used by multiple fields
+	once      sync.Once
+	jobs      []*campaigns.CampaignJob
+	reposByID map[api.RepoID]*repos.Repo
+	next      int64
+	err       error
+}
+
+func (r *campaignScriptRunsConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.CampaignScriptRunResolver, error) {
+	jobs, reposByID, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignScriptRunResolver, 0, len(jobs))
+	for _, j := range jobs {
+		repo, ok := reposByID[j.RepoID]
+		if !ok {
+			return nil, fmt.Errorf("failed to load repo %d", j.RepoID)
+		}
+		resolvers = append(resolvers, &campaignScriptRunResolver{job: j, repo: repo})
+	}
+	return resolvers, nil
+}
+
+func (r *campaignScriptRunsConnectionResolver) compute(ctx context.Context) ([]*campaigns.CampaignJob, map[api.RepoID]*repos.Repo, int64, error) {
+	r.once.Do(func() {
+		if !r.isScript {
+			return
+		}
+
+		r.jobs, r.next, r.err = r.store.ListCampaignJobs(ctx, r.opts)
+		if r.err != nil {
+			return
+		}
+
+		reposStore := repos.NewDBStore(r.store.DB(), sql.TxOptions{})
+		repoIDs := make([]api.RepoID, len(r.jobs))
+		for i, j := range r.jobs {
+			repoIDs[i] = j.RepoID
+		}
+
+		rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+		if err != nil {
+			r.err = err
+			return
+		}
+
+		r.reposByID = make(map[api.RepoID]*repos.Repo, len(rs))
+		for _, repo := range rs {
+			r.reposByID[repo.ID] = repo
+		}
+	})
+	return r.jobs, r.reposByID, r.next, r.err
+}
+
+func (r *campaignScriptRunsConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	if !r.isScript {
+		return 0, nil
+	}
+	count, err := r.store.CountCampaignJobs(ctx, ee.CountCampaignJobsOpts{CampaignPlanID: r.opts.CampaignPlanID})
+	return int32(count), err
+}
+
+func (r *campaignScriptRunsConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, _, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(next != 0), nil
+}
+
+type campaignScriptRunResolver struct {
+	job  *campaigns.CampaignJob
+	repo *repos.Repo
+}
+
+func (r *campaignScriptRunResolver) Repository(ctx context.Context) (*graphqlbackend.RepositoryResolver, error) {
+	return newRepositoryResolver(r.repo), nil
+}
+
+func (r *campaignScriptRunResolver) State() campaigns.BackgroundProcessState {
+	switch {
+	case r.job.FinishedAt.IsZero():
+		return campaigns.BackgroundProcessStateProcessing
+	case r.job.Error != "":
+		return campaigns.BackgroundProcessStateErrored
+	default:
+		return campaigns.BackgroundProcessStateCompleted
+	}
+}
+
+func (r *campaignScriptRunResolver) StartedAt() *graphqlbackend.DateTime {
+	if r.job.StartedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.job.StartedAt}
+}
+
+func (r *campaignScriptRunResolver) FinishedAt() *graphqlbackend.DateTime {
+	if r.job.FinishedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.job.FinishedAt}
+}
+
+func (r *campaignScriptRunResolver) Log() string {
+	return r.job.Log
+}
+
+func (r *campaignScriptRunResolver) Error() *string {
+	if r.job.Error == "" {
+		return nil
+	}
+	return &r.job.Error
+}
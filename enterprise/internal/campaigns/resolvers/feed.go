@@ -0,0 +1,168 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/atom"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// campaignFeedMaxEntries is the maximum number of entries in a campaign feed.
+const campaignFeedMaxEntries = 50
+
+// changesetStateEvents maps the kinds of changeset events that change the state or the review
+// state of a changeset to the title of their feed entries.
+var changesetStateEvents = map[campaigns.ChangesetEventKind]string{
+	campaigns.ChangesetEventKindGitHubClosed:              "Closed",
+	campaigns.ChangesetEventKindGitHubMerged:              "Merged",
+	campaigns.ChangesetEventKindGitHubReopened:            "Reopened",
+	campaigns.ChangesetEventKindGitHubReviewed:            "Reviewed",
+	campaigns.ChangesetEventKindGitHubReviewDismissed:     "Review dismissed",
+	campaigns.ChangesetEventKindBitbucketServerApproved:   "Approved",
+	campaigns.ChangesetEventKindBitbucketServerUnapproved: "Unapproved",
+	campaigns.ChangesetEventKindBitbucketServerDeclined:   "Declined",
+	campaigns.ChangesetEventKindBitbucketServerOpened:     "Opened",
+	campaigns.ChangesetEventKindBitbucketServerReopened:   "Reopened",
+	campaigns.ChangesetEventKindBitbucketServerMerged:     "Merged",
+	campaigns.ChangesetEventKindBitbucketServerReviewed:   "Reviewed",
+}
+
+// NewCampaignFeed returns the function that builds the Atom feed of the changeset state changes in
+// a campaign, for the frontend's campaign feeds.
+func NewCampaignFeed(store *ee.Store) func(ctx context.Context, id graphql.ID) (*atom.Feed, error) {
+	return func(ctx context.Context, id graphql.ID) (*atom.Feed, error) {
+		// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaigns.
+		if err := allowReadAccess(ctx); err != nil {
+			return nil, &errcode.HTTPErr{Status: http.StatusForbidden, Err: err}
+		}
+
+		if relay.UnmarshalKind(id) != "Campaign" {
+			return nil, &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.New("invalid campaign ID")}
+		}
+		campaignID, err := unmarshalCampaignID(id)
+		if err != nil {
+			return nil, &errcode.HTTPErr{Status: http.StatusNotFound, Err: err}
+		}
+		campaign, err := store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+		if err == ee.ErrNoResults {
+			return nil, &errcode.HTTPErr{Status: http.StatusNotFound, Err: err}
+		} else if err != nil {
+			return nil, err
+		}
+
+		changesets, _, err := store.ListChangesets(ctx, ee.ListChangesetsOpts{CampaignID: campaign.ID, Limit: -1})
+		if err != nil {
+			return nil, err
+		}
+
+		// 🚨 SECURITY: Omit the changesets in repositories that the user may not view.
+		repos := map[api.RepoID]*types.Repo{}
+		var visible []*campaigns.Changeset
+		var changesetIDs []int64
+		for _, c := range changesets {
+			repo, ok := repos[c.RepoID]
+			if !ok {
+				repo, err = db.Repos.Get(ctx, c.RepoID)
+				if err != nil && !errcode.IsNotFound(err) {
+					return nil, err
+				}
+				repos[c.RepoID] = repo
+			}
+			if repo != nil {
+				visible = append(visible, c)
+				changesetIDs = append(changesetIDs, c.ID)
+			}
+		}
+
+		var events []*campaigns.ChangesetEvent
+		if len(changesetIDs) > 0 {
+			events, _, err = store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{ChangesetIDs: changesetIDs, Limit: -1})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return campaignFeed(id, campaign, visible, repos, events), nil
+	}
+}
+
+// campaignFeed returns the feed of the state changes of the given changesets of the campaign. The
+// events of other changesets are ignored.
+func campaignFeed(id graphql.ID, campaign *campaigns.Campaign, changesets []*campaigns.Changeset, repos map[api.RepoID]*types.Repo, events []*campaigns.ChangesetEvent) *atom.Feed {
+	campaignURL := globals.ExternalURL().String() + "/campaigns/" + string(id)
+	feed := &atom.Feed{
+		ID:      campaignURL,
+		Title:   "Campaign: " + campaign.Name,
+		Updated: campaign.UpdatedAt,
+		Author:  &atom.Person{Name: "Sourcegraph"},
+		Links:   []*atom.Link{{Href: campaignURL, Type: "text/html"}},
+	}
+
+	changesetsByID := make(map[int64]*campaigns.Changeset, len(changesets))
+	for _, c := range changesets {
+		changesetsByID[c.ID] = c
+	}
+	var stateEvents []*campaigns.ChangesetEvent
+	for _, e := range events {
+		if _, ok := changesetStateEvents[e.Kind]; ok && changesetsByID[e.ChangesetID] != nil {
+			stateEvents = append(stateEvents, e)
+		}
+	}
+	sort.SliceStable(stateEvents, func(i, j int) bool {
+		return changesetEventTime(stateEvents[i]).After(changesetEventTime(stateEvents[j]))
+	})
+	if len(stateEvents) > campaignFeedMaxEntries {
+		stateEvents = stateEvents[:campaignFeedMaxEntries]
+	}
+
+	for _, e := range stateEvents {
+		c := changesetsByID[e.ChangesetID]
+		title, err := c.Title()
+		if err != nil {
+			title = fmt.Sprintf("Changeset %d", c.ID)
+		}
+		var repoName api.RepoName
+		if repo := repos[c.RepoID]; repo != nil {
+			repoName = repo.Name
+		}
+		entry := &atom.Entry{
+			ID:      campaignURL + "#changeset-event-" + strconv.FormatInt(e.ID, 10),
+			Title:   fmt.Sprintf("%s: %s", changesetStateEvents[e.Kind], title),
+			Updated: changesetEventTime(e),
+			Content: &atom.Text{Body: fmt.Sprintf("%s: %s (%s)", changesetStateEvents[e.Kind], title, repoName)},
+		}
+		if actor := e.Actor(); actor != "" {
+			entry.Author = &atom.Person{Name: actor}
+		}
+		if u, err := c.URL(); err == nil {
+			entry.Links = []*atom.Link{{Href: u, Type: "text/html"}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	if len(feed.Entries) > 0 && feed.Entries[0].Updated.After(feed.Updated) {
+		feed.Updated = feed.Entries[0].Updated
+	}
+	return feed
+}
+
+// changesetEventTime returns when the event happened on the code host, or when it was recorded if
+// that is unknown.
+func changesetEventTime(e *campaigns.ChangesetEvent) time.Time {
+	if t := e.Timestamp(); !t.IsZero() {
+		return t
+	}
+	return e.CreatedAt
+}
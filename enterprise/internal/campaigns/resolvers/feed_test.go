@@ -0,0 +1,55 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/atom"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestCampaignFeed(t *testing.T) {
+	now := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	campaign := &campaigns.Campaign{ID: 1, Name: "Fix typos", UpdatedAt: now.Add(-48 * time.Hour)}
+	changesets := []*campaigns.Changeset{
+		{ID: 1, RepoID: 1, Metadata: &github.PullRequest{Title: "Fix typo in a", URL: "https://github.com/acme/a/pull/1"}},
+		{ID: 2, RepoID: 1, Metadata: &github.PullRequest{Title: "Fix typo in b", URL: "https://github.com/acme/a/pull/2"}},
+	}
+	repos := map[api.RepoID]*types.Repo{1: {ID: 1, Name: "github.com/acme/a"}}
+	events := []*campaigns.ChangesetEvent{
+		{ID: 1, ChangesetID: 1, Kind: campaigns.ChangesetEventKindGitHubClosed, Metadata: &github.ClosedEvent{Actor: github.Actor{Login: "alice"}, CreatedAt: now.Add(-time.Hour)}},
+		// Comments don't change the state of a changeset.
+		{ID: 2, ChangesetID: 1, Kind: campaigns.ChangesetEventKindGitHubCommented, Metadata: &github.IssueComment{UpdatedAt: now}},
+		{ID: 3, ChangesetID: 2, Kind: campaigns.ChangesetEventKindGitHubMerged, Metadata: &github.MergedEvent{Actor: github.Actor{Login: "bob"}, CreatedAt: now}},
+		// The changeset of this event isn't visible to the user.
+		{ID: 4, ChangesetID: 3, Kind: campaigns.ChangesetEventKindGitHubMerged, Metadata: &github.MergedEvent{CreatedAt: now}},
+	}
+
+	feed := campaignFeed("Q2FtcGFpZ246MQ==", campaign, changesets, repos, events)
+	data, err := feed.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := atom.Validate(data); err != nil {
+		t.Fatalf("invalid feed: %s\n%s", err, data)
+	}
+
+	var got []string
+	for _, e := range feed.Entries {
+		got = append(got, e.ID+" "+e.Title+" by "+e.Author.Name+" "+e.Links[0].Href)
+	}
+	want := []string{
+		"http://example.com/campaigns/Q2FtcGFpZ246MQ==#changeset-event-3 Merged: Fix typo in b by bob https://github.com/acme/a/pull/2",
+		"http://example.com/campaigns/Q2FtcGFpZ246MQ==#changeset-event-1 Closed: Fix typo in a by alice https://github.com/acme/a/pull/1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got entries %q, want %q", got, want)
+	}
+	if !feed.Updated.Equal(now) {
+		t.Errorf("got updated %s, want %s", feed.Updated, now)
+	}
+}
@@ -538,6 +538,43 @@ func (r *Resolver) CreateCampaignPlanFromPatches(ctx context.Context, args graph
 	return &campaignPlanResolver{store: r.store, campaignPlan: plan}, nil
 }
 
+func (r *Resolver) CreateCampaignPlanFromScript(ctx context.Context, args graphqlbackend.CreateCampaignPlanFromScriptArgs) (graphqlbackend.CampaignPlanResolver, error) {
+	var err error
+	tr, ctx := trace.New(ctx, "Resolver.CreateCampaignPlanFromScript", fmt.Sprintf("Script: %q", args.Script))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may create campaign plans for now
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	user, err := backend.CurrentUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+	if user == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	repoIDs := make([]api.RepoID, len(args.Repositories))
+	for i, id := range args.Repositories {
+		if repoIDs[i], err = graphqlbackend.UnmarshalRepositoryID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	plan, err := svc.CreateCampaignPlanFromScript(ctx, args.Script, repoIDs, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignPlanResolver{store: r.store, campaignPlan: plan}, nil
+}
+
 func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.CloseCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
 	tr, ctx := trace.New(ctx, "Resolver.CloseCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
 	defer func() {
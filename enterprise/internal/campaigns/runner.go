@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -17,7 +18,8 @@ var maxWorkers = env.Get("A8N_MAX_WORKERS", "8", "maximum number of repository j
 const defaultWorkerCount = 8
 
 // RunChangesetJobs should run in a background goroutine and is responsible
-// for finding pending jobs and running them.
+// for finding pending jobs and running them. Jobs are not run while
+// maintenance mode is enabled.
 // ctx should be canceled to terminate the function
 func RunChangesetJobs(ctx context.Context, s *Store, clock func() time.Time, gitClient GitserverClient, backoffDuration time.Duration) {
 	workerCount, err := strconv.Atoi(maxWorkers)
@@ -43,6 +45,10 @@ func RunChangesetJobs(ctx context.Context, s *Store, clock func() time.Time, git
 			case <-ctx.Done():
 				return
 			default:
+				if conf.MaintenanceModeEnabled() {
+					time.Sleep(backoffDuration)
+					continue
+				}
 				didRun, err := s.ProcessPendingChangesetJobs(context.Background(), process)
 				if err != nil {
 					log15.Error("Running changeset job", "err", err)
@@ -0,0 +1,447 @@
+package campaigns
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxScriptWorkers defines the maximum number of campaign scripts to run in parallel.
+var maxScriptWorkers = env.Get("CAMPAIGNS_MAX_SCRIPT_WORKERS", "2", "maximum number of campaign scripts to run in parallel")
+
+const defaultScriptWorkerCount = 2
+
+// Defaults of the limits of a campaign script (see the "campaigns.scripts" site configuration).
+const (
+	defaultScriptTimeout    = 5 * time.Minute
+	defaultScriptCPUSeconds = 300
+	defaultScriptMemoryMB   = 2048
+)
+
+// maxScriptLogSize is the number of bytes of a campaign script's output that are kept in the
+// CampaignJob's log.
+const maxScriptLogSize = 512 * 1024
+
+// lookupCampaignScript returns the campaign script with the given name from the site
+// configuration.
+func lookupCampaignScript(name string) (*schema.CampaignScript, error) {
+	for _, s := range conf.Get().CampaignsScripts {
+		if s.Name == name {
+			if _, err := scriptTimeout(s); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("no campaign script named %q in the site configuration", name)
+}
+
+func scriptTimeout(s *schema.CampaignScript) (time.Duration, error) {
+	if s.Timeout == "" {
+		return defaultScriptTimeout, nil
+	}
+	d, err := time.ParseDuration(s.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q of campaign script %q", s.Timeout, s.Name)
+	}
+	return d, nil
+}
+
+// repoArchive returns a tar archive of the tree of a commit in a repository.
+type repoArchive func(ctx context.Context, repo *repos.Repo, commit api.CommitID) (io.ReadCloser, error)
+
+// defaultRepoArchive is an implementation of repoArchive that fetches the archive from gitserver.
+var defaultRepoArchive = func(ctx context.Context, repo *repos.Repo, commit api.CommitID) (io.ReadCloser, error) {
+	return gitserver.DefaultClient.Archive(ctx,
+		gitserver.Repo{Name: api.RepoName(repo.Name)},
+		gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar"},
+	)
+}
+
+// RunCampaignScriptJobs should run in a background goroutine and is responsible
+// for finding pending CampaignJobs of campaign plans created from a campaign
+// script and running them.
+// ctx should be canceled to terminate the function
+func RunCampaignScriptJobs(ctx context.Context, s *Store, clock func() time.Time, backoffDuration time.Duration) {
+	workerCount, err := strconv.Atoi(maxScriptWorkers)
+	if err != nil {
+		log15.Error("Parsing max script worker count failed. Falling back to default.", "default", defaultScriptWorkerCount, "err", err)
+		workerCount = defaultScriptWorkerCount
+	}
+	process := func(ctx context.Context, s *Store, job campaigns.CampaignJob) error {
+		plan, err := s.GetCampaignPlan(ctx, GetCampaignPlanOpts{ID: job.CampaignPlanID})
+		if err != nil {
+			return errors.Wrap(err, "getting campaign plan")
+		}
+		// The error is saved in the job row, so we don't roll back the
+		// transaction unless saving it failed.
+		return RunCampaignScriptJob(ctx, clock, s, defaultRepoArchive, plan, &job)
+	}
+	processPending := func(ctx context.Context) (bool, error) {
+		return s.ProcessPendingCampaignJob(ctx, process)
+	}
+	for i := 0; i < workerCount; i++ {
+		go runCampaignScriptWorker(ctx, backoffDuration, processPending)
+	}
+}
+
+// runCampaignScriptWorker runs pending CampaignJobs with processPending until ctx is canceled.
+// Like the changeset job workers, it pauses while maintenance mode is enabled.
+func runCampaignScriptWorker(ctx context.Context, backoffDuration time.Duration, processPending func(context.Context) (didRun bool, err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if conf.MaintenanceModeEnabled() {
+				time.Sleep(backoffDuration)
+				continue
+			}
+			didRun, err := processPending(context.Background())
+			if err != nil {
+				log15.Error("Running campaign script job", "err", err)
+			}
+			// Back off on error or when no jobs available
+			if err != nil || !didRun {
+				time.Sleep(backoffDuration)
+			}
+		}
+	}
+}
+
+// RunCampaignScriptJob runs the campaign script of the plan in a checkout of the job's revision
+// and saves the resulting diff, the script's output and any error in the job.
+func RunCampaignScriptJob(
+	ctx context.Context,
+	clock func() time.Time,
+	s *Store,
+	archive repoArchive,
+	plan *campaigns.CampaignPlan,
+	job *campaigns.CampaignJob,
+) error {
+	job.Diff, job.Log, job.Error = "", "", ""
+	if err := runCampaignScriptJob(ctx, s, archive, plan, job); err != nil {
+		job.Error = err.Error()
+	}
+	job.FinishedAt = clock()
+	return s.UpdateCampaignJob(ctx, job)
+}
+
+func runCampaignScriptJob(ctx context.Context, s *Store, archive repoArchive, plan *campaigns.CampaignPlan, job *campaigns.CampaignJob) error {
+	if plan.CampaignType != campaignTypeScript {
+		return fmt.Errorf("campaign plans of type %q can't be executed on the server", plan.CampaignType)
+	}
+	var script schema.CampaignScript
+	if err := json.Unmarshal([]byte(plan.Arguments), &script); err != nil {
+		return errors.Wrap(err, "parsing campaign script")
+	}
+
+	rs, err := repos.NewDBStore(s.DB(), sql.TxOptions{}).ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{job.RepoID}})
+	if err != nil {
+		return errors.Wrap(err, "getting repository")
+	}
+	if len(rs) != 1 {
+		return fmt.Errorf("repository ID %d not found", job.RepoID)
+	}
+
+	tree, err := archive(ctx, rs[0], job.Rev)
+	if err != nil {
+		return errors.Wrap(err, "checking out repository")
+	}
+	defer tree.Close()
+
+	job.Diff, job.Log, err = runCampaignScript(ctx, &script, tree)
+	return err
+}
+
+// runCampaignScript extracts the tar archive tree into a temporary work tree, runs the script in
+// it (see sandboxCommand) and returns the changes the script made as a unified diff (without
+// prefixes, like 'git diff --no-prefix') and the script's output.
+//
+// The Git directory used to compute the diff is kept outside of the work tree, so that the script
+// doesn't see a repository it could commit to. New files that the work tree's .gitignore excludes (e.g.,
+// dependencies the script installed) are not part of the diff.
+func runCampaignScript(ctx context.Context, script *schema.CampaignScript, tree io.Reader) (diff, log string, err error) {
+	tmp, err := ioutil.TempDir("", "campaign-script")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	workTree := filepath.Join(tmp, "src")
+	if err := extractTar(tree, workTree); err != nil {
+		return "", "", errors.Wrap(err, "checking out repository")
+	}
+
+	git := func(args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = workTree
+		cmd.Env = append(os.Environ(), "GIT_DIR="+filepath.Join(tmp, "git"), "GIT_WORK_TREE="+workTree)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", errors.Wrapf(err, "git %s: %s", args[0], bytes.TrimSpace(stderr.Bytes()))
+		}
+		return string(out), nil
+	}
+	if _, err := git("init", "--quiet"); err != nil {
+		return "", "", err
+	}
+	if _, err := git("add", "--all", "--force", "."); err != nil {
+		return "", "", err
+	}
+	base, err := git("write-tree")
+	if err != nil {
+		return "", "", err
+	}
+
+	root, home := filepath.Join(tmp, "root"), filepath.Join(tmp, "home")
+	for _, d := range []string{root, home} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			return "", "", err
+		}
+	}
+	log, err = runSandboxed(ctx, script, root, workTree, home)
+	if err != nil {
+		return "", log, err
+	}
+
+	if _, err := git("add", "--all", "."); err != nil {
+		return "", log, err
+	}
+	diff, err = git("diff", "--cached", "--no-prefix", "--no-renames", "--no-color", "--no-ext-diff", strings.TrimSpace(base))
+	return diff, log, err
+}
+
+// sandboxSetupScript is the program that sandboxCommand runs in new mount, PID and network
+// namespaces to give the script its own root filesystem in $1: read-only binds of the system's
+// programs and libraries, the work tree $2 at /work and the home directory $3 at /home, a
+// private /tmp, a /proc of its own PID namespace and a minimal /dev. The rest of the host's
+// filesystem (such as configuration files, mounted secrets and the /proc of the worker) is
+// unmounted, and the script runs without capabilities, so it can't mount it again.
+const sandboxSetupScript = `set -e
+root=$1 work=$2 home=$3
+shift 3
+mount -t tmpfs -o mode=755 sandbox "$root"
+for d in /bin /sbin /lib /lib64 /usr /etc/alternatives; do
+	if [ -L "$d" ]; then
+		ln -s "$(readlink "$d")" "$root$d"
+	elif [ -d "$d" ]; then
+		mkdir -p "$root$d"
+		mount --rbind "$d" "$root$d"
+		mount -o remount,bind,ro "$root$d"
+	fi
+done
+mkdir -p "$root/etc" "$root/work" "$root/home" "$root/tmp" "$root/proc" "$root/dev" "$root/.old"
+for f in /etc/passwd /etc/group; do
+	if [ -f "$f" ]; then cp "$f" "$root$f"; fi
+done
+for f in null zero random urandom; do
+	touch "$root/dev/$f"
+	mount --bind "/dev/$f" "$root/dev/$f"
+done
+mount --bind "$work" "$root/work"
+mount --bind "$home" "$root/home"
+mount -t tmpfs -o mode=1777 sandbox "$root/tmp"
+mount -t proc proc "$root/proc"
+cd "$root"
+PATH="$PATH:/usr/sbin:/sbin" pivot_root . .old
+umount -l /.old
+rmdir /.old
+cd /work
+exec setpriv --no-new-privs --bounding-set=-all --inh-caps=-all -- "$@"
+`
+
+// sandboxCommand returns the command that runs the script in the work tree dir, using root as the
+// mount point of the sandbox's root filesystem (see sandboxSetupScript). The script runs in the
+// work tree at /work as the root user of a new user namespace (which is the worker's user outside
+// of it), with no network interfaces but loopback, with CPU time and virtual memory rlimits, and
+// with an environment that contains nothing but PATH and HOME.
+func sandboxCommand(script *schema.CampaignScript, root, dir, home string) *exec.Cmd {
+	cpuSeconds, memoryMB := defaultScriptCPUSeconds, defaultScriptMemoryMB
+	if script.CpuSeconds != 0 {
+		cpuSeconds = script.CpuSeconds
+	}
+	if script.MemoryMB != 0 {
+		memoryMB = script.MemoryMB
+	}
+	limits := fmt.Sprintf(`ulimit -t %d && ulimit -v %d && exec /bin/sh -c "$1"`, cpuSeconds, memoryMB*1024)
+
+	cmd := exec.Command("unshare", "--mount", "--pid", "--net", "--fork", "--kill-child", "--map-root-user", "--",
+		"/bin/sh", "-c", sandboxSetupScript, "sandbox", root, dir, home,
+		"/bin/sh", "-c", limits, "sandbox", script.Run)
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=/home"}
+	// Ensure forked child processes are killed
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// CheckScriptSandbox returns an error if campaign scripts can't be run in their sandbox (see
+// sandboxCommand), for example because the container's seccomp profile doesn't allow creating
+// user namespaces.
+func CheckScriptSandbox(ctx context.Context) error {
+	tmp, err := ioutil.TempDir("", "campaign-script-check")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	for _, d := range []string{"root", "src", "home"} {
+		if err := os.Mkdir(filepath.Join(tmp, d), 0700); err != nil {
+			return err
+		}
+	}
+
+	script := &schema.CampaignScript{Name: "check", Run: "true", Timeout: "30s"}
+	out, err := runSandboxed(ctx, script, filepath.Join(tmp, "root"), filepath.Join(tmp, "src"), filepath.Join(tmp, "home"))
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// runSandboxed runs the script (see sandboxCommand) and returns its combined output, truncated to
+// maxScriptLogSize. The script and all processes it started are killed when it exceeds its
+// timeout.
+func runSandboxed(ctx context.Context, script *schema.CampaignScript, root, dir, home string) (string, error) {
+	timeout, err := scriptTimeout(script)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var out limitedBuffer
+	cmd := sandboxCommand(script, root, dir, home)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Start(); err != nil {
+		return "", errors.Wrap(err, "starting campaign script")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// "no such process" error should be suppressed
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("campaign script timed out after %s", timeout)
+		} else {
+			err = ctx.Err()
+		}
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		err = fmt.Errorf("campaign script failed: %s", exitErr)
+	}
+	return out.String(), err
+}
+
+// limitedBuffer is an io.Writer that keeps the first maxScriptLogSize bytes written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := maxScriptLogSize - b.Len(); len(p) > n {
+		b.Buffer.Write(p[:n])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.Buffer.String() + "\n[output truncated]\n"
+	}
+	return b.Buffer.String()
+}
+
+// extractTar extracts the regular files, directories and symbolic links of the tar archive r into
+// dir. Symbolic links are created last, so that no file is written through one.
+func extractTar(r io.Reader, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var symlinks []*tar.Header
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in archive", hdr.Name)
+		}
+		hdr.Name = filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(hdr.Name, 0755)
+		case tar.TypeReg:
+			err = writeFile(hdr.Name, tr, os.FileMode(hdr.Mode)&0755|0644)
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, hdr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, hdr := range symlinks {
+		if err := os.MkdirAll(filepath.Dir(hdr.Name), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(hdr.Linkname, hdr.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
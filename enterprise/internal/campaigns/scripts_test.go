@@ -0,0 +1,201 @@
+package campaigns
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRunCampaignScript(t *testing.T) {
+	if err := exec.Command("unshare", "--mount", "--pid", "--net", "--fork", "--map-root-user", "true").Run(); err != nil {
+		t.Skip("unshare is not available:", err)
+	}
+	if err := CheckScriptSandbox(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file outside of the work tree, which the script must not be able to read.
+	outside, err := ioutil.TempDir("", "campaign-script-outside")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+	secretPath := filepath.Join(outside, "secret.txt")
+	if err := ioutil.WriteFile(secretPath, []byte("secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := func() *bytes.Buffer {
+		return tarArchive(t, map[string]string{
+			"README.md":  "hello world\n",
+			"go/main.go": "package main\n",
+			".gitignore": "node_modules/\n",
+		})
+	}
+
+	tests := []struct {
+		name     string
+		script   schema.CampaignScript
+		wantDiff string
+		wantLog  string
+		wantErr  string
+	}{
+		{
+			name:   "diff",
+			script: schema.CampaignScript{Name: "sed", Run: "echo replacing; sed -i s/world/campaign/ README.md; mkdir node_modules; touch node_modules/x new.txt"},
+			wantDiff: `diff --git README.md README.md
+index 3b18e51..d0509ff 100644
+--- README.md
++++ README.md
+@@ -1 +1 @@
+-hello world
++hello campaign
+diff --git new.txt new.txt
+new file mode 100644
+index 0000000..e69de29
+`,
+			wantLog: "replacing\n",
+		},
+		{
+			name:    "no network",
+			script:  schema.CampaignScript{Name: "net", Run: "tail -n +3 /proc/net/dev | cut -d: -f1 | tr -d ' '"},
+			wantLog: "lo\n",
+		},
+		{
+			name:    "clean environment",
+			script:  schema.CampaignScript{Name: "env", Run: "echo \"${CAMPAIGNS_TEST_SECRET:-unset}\""},
+			wantLog: "unset\n",
+		},
+		{
+			name:    "no files outside the work tree",
+			script:  schema.CampaignScript{Name: "cat", Run: "cat " + secretPath + " " + filepath.Join(cwd, "scripts.go") + " 2>/dev/null; pwd"},
+			wantLog: "/work\n",
+		},
+		{
+			name:    "no other processes",
+			script:  schema.CampaignScript{Name: "ps", Run: "grep -l CAMPAIGNS_TEST_SECRET /proc/[0-9]*/environ 2>/dev/null; echo $$"},
+			wantLog: "1\n",
+		},
+		{
+			name:    "read-only system directories",
+			script:  schema.CampaignScript{Name: "touch", Run: "touch /usr/campaign-script 2>/dev/null || echo read-only"},
+			wantLog: "read-only\n",
+		},
+		{
+			name:    "failure",
+			script:  schema.CampaignScript{Name: "fail", Run: "echo oops >&2; exit 3"},
+			wantLog: "oops\n",
+			wantErr: "campaign script failed: exit status 3",
+		},
+		{
+			name:    "timeout",
+			script:  schema.CampaignScript{Name: "sleep", Run: "sleep 10 & sleep 10", Timeout: "100ms"},
+			wantErr: "campaign script timed out after 100ms",
+		},
+	}
+
+	os.Setenv("CAMPAIGNS_TEST_SECRET", "secret")
+	defer os.Unsetenv("CAMPAIGNS_TEST_SECRET")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, log, err := runCampaignScript(context.Background(), &tc.script, tree())
+			if have, want := errString(err), tc.wantErr; have != want {
+				t.Fatalf("error: have %q, want %q", have, want)
+			}
+			if diff != tc.wantDiff {
+				t.Errorf("diff: have %q, want %q", diff, tc.wantDiff)
+			}
+			if log != tc.wantLog {
+				t.Errorf("log: have %q, want %q", log, tc.wantLog)
+			}
+		})
+	}
+}
+
+func TestRunCampaignScriptWorker_maintenanceMode(t *testing.T) {
+	defer conf.Mock(nil)
+
+	var calls int32
+	processPending := func(context.Context) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		return false, nil
+	}
+	run := func() int32 {
+		atomic.StoreInt32(&calls, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		runCampaignScriptWorker(ctx, time.Millisecond, processPending)
+		return atomic.LoadInt32(&calls)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: true}}})
+	if n := run(); n != 0 {
+		t.Errorf("got %d job runs in maintenance mode, want none", n)
+	}
+
+	conf.Mock(&conf.Unified{})
+	if n := run(); n == 0 {
+		t.Error("got no job runs outside of maintenance mode")
+	}
+}
+
+func TestExtractTar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract-tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractTar(tarArchive(t, map[string]string{"a/b.txt": "b"}), filepath.Join(dir, "ok")); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "ok", "a", "b.txt")); err != nil || string(b) != "b" {
+		t.Errorf("unexpected contents %q (error %v)", b, err)
+	}
+
+	err = extractTar(tarArchive(t, map[string]string{"../evil.txt": "x"}), filepath.Join(dir, "evil"))
+	if err == nil || !strings.Contains(err.Error(), "invalid path") {
+		t.Errorf("expected invalid path error, got %v", err)
+	}
+}
+
+func tarArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, contents := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -1,8 +1,10 @@
 package campaigns
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -40,6 +43,7 @@ func NewServiceWithClock(store *Store, git GitserverClient, repoResolveRevision
 	if svc.repoResolveRevision == nil {
 		svc.repoResolveRevision = defaultRepoResolveRevision
 	}
+	svc.repoDefaultBranch = defaultRepoDefaultBranch
 
 	return svc
 }
@@ -52,6 +56,7 @@ type Service struct {
 	store               *Store
 	git                 GitserverClient
 	repoResolveRevision repoResolveRevision
+	repoDefaultBranch   repoDefaultBranch
 	cf                  *httpcli.Factory
 
 	clock func() time.Time
@@ -69,6 +74,27 @@ var defaultRepoResolveRevision = func(ctx context.Context, repo *repos.Repo, rev
 	)
 }
 
+// repoDefaultBranch returns the full name of the default branch of a repository (e.g.,
+// "refs/heads/master").
+type repoDefaultBranch func(context.Context, *repos.Repo) (string, error)
+
+// defaultRepoDefaultBranch is an implementation of repoDefaultBranch that asks gitserver for the
+// ref that HEAD points to.
+var defaultRepoDefaultBranch = func(ctx context.Context, repo *repos.Repo) (string, error) {
+	cachedRepo, err := backend.CachedGitRepo(ctx, &types.Repo{Name: api.RepoName(repo.Name), ExternalRepo: repo.ExternalRepo})
+	if err != nil {
+		return "", err
+	}
+	ref, _, exitCode, err := git.ExecSafe(ctx, *cachedRepo, []string{"symbolic-ref", "HEAD"})
+	if err != nil {
+		return "", err
+	}
+	if exitCode != 0 {
+		return "", errors.New("HEAD is not a branch")
+	}
+	return string(bytes.TrimSpace(ref)), nil
+}
+
 // CreateCampaignPlanFromPatches creates a CampaignPlan and its associated CampaignJobs from patches
 // computed by the caller. There is no diff execution or computation performed during creation of
 // the CampaignJobs in this case (unlike when using Runner to create a CampaignPlan from a
@@ -142,6 +168,83 @@ func (s *Service) CreateCampaignPlanFromPatches(ctx context.Context, patches []c
 	return plan, nil
 }
 
+// CreateCampaignPlanFromScript creates a CampaignPlan that runs the campaign script with the given
+// name (from the site configuration) in each of the given repositories, at the current commit of
+// their default branch. It creates one pending CampaignJob per repository, which the
+// campaign-script-worker service executes (see RunCampaignScriptJobs).
+//
+// The script is copied into the CampaignPlan's arguments, so that changes to the site
+// configuration don't affect plans that were already created.
+func (s *Service) CreateCampaignPlanFromScript(ctx context.Context, scriptName string, repoIDs []api.RepoID, userID int32) (plan *campaigns.CampaignPlan, err error) {
+	if userID == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+	script, err := lookupCampaignScript(scriptName)
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := json.Marshal(script)
+	if err != nil {
+		return nil, err
+	}
+
+	reposStore := repos.NewDBStore(s.store.DB(), sql.TxOptions{})
+	allRepos, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+	reposByID := make(map[api.RepoID]*repos.Repo, len(allRepos))
+	for _, repo := range allRepos {
+		reposByID[repo.ID] = repo
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	plan = &campaigns.CampaignPlan{
+		CampaignType: campaignTypeScript,
+		Arguments:    string(arguments),
+		UserID:       userID,
+	}
+	if err = tx.CreateCampaignPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	for _, id := range repoIDs {
+		repo := reposByID[id]
+		if repo == nil {
+			return nil, fmt.Errorf("repository ID %d not found", id)
+		}
+		if !campaigns.IsRepoSupported(&repo.ExternalRepo) {
+			continue
+		}
+
+		ref, err := s.repoDefaultBranch(ctx, repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "repository %q", repo.Name)
+		}
+		commit, err := s.repoResolveRevision(ctx, repo, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "repository %q", repo.Name)
+		}
+
+		job := &campaigns.CampaignJob{
+			CampaignPlanID: plan.ID,
+			RepoID:         repo.ID,
+			BaseRef:        ref,
+			Rev:            commit,
+		}
+		if err := tx.CreateCampaignJob(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
 // CreateCampaign creates the Campaign. When a CampaignPlanID is set on the
 // Campaign and the Campaign is not created as a draft, it calls
 // CreateChangesetJobs inside the same transaction in which it creates the
@@ -164,6 +267,10 @@ func (s *Service) CreateCampaign(ctx context.Context, c *campaigns.Campaign, dra
 	}
 	defer tx.Done(&err)
 
+	if err = db.Quotas.Check(ctx, tx.DB(), campaignQuotaNamespace(c), db.QuotaCampaigns, 1); err != nil {
+		return err
+	}
+
 	c.CreatedAt = s.clock()
 	c.UpdatedAt = c.CreatedAt
 
@@ -182,6 +289,12 @@ func (s *Service) CreateCampaign(ctx context.Context, c *campaigns.Campaign, dra
 	return s.createChangesetJobsWithStore(ctx, tx, c)
 }
 
+// campaignQuotaNamespace returns the namespace whose quotas the campaign and its changesets count
+// against.
+func campaignQuotaNamespace(c *campaigns.Campaign) db.QuotaNamespace {
+	return db.QuotaNamespace{OrgID: c.NamespaceOrgID, UserID: c.NamespaceUserID}
+}
+
 // ErrNoCampaignJobs is returned by CreateCampaign or UpdateCampaign if a
 // CampaignPlanID was specified but the CampaignPlan does not have any
 // (finished) CampaignJobs.
@@ -207,6 +320,10 @@ func (s *Service) createChangesetJobsWithStore(ctx context.Context, store *Store
 		return ErrNoCampaignJobs
 	}
 
+	if err := db.Quotas.Check(ctx, store.DB(), campaignQuotaNamespace(c), db.QuotaChangesets, int64(len(jobs))); err != nil {
+		return err
+	}
+
 	for _, job := range jobs {
 		changesetJob := &campaigns.ChangesetJob{
 			CampaignID:    c.ID,
@@ -718,6 +835,9 @@ func (s *Service) CreateChangesetJobForCampaignJob(ctx context.Context, campaign
 		// Already exists
 		return nil
 	}
+	if err := db.Quotas.Check(ctx, tx.DB(), campaignQuotaNamespace(campaign), db.QuotaChangesets, 1); err != nil {
+		return err
+	}
 	changesetJob := &campaigns.ChangesetJob{
 		CampaignID:    campaign.ID,
 		CampaignJobID: job.ID,
@@ -866,6 +986,11 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 	// have already been published, we don't want to create new ChangesetJobs,
 	// since they would be processed and publish the other Changesets.
 	if !partiallyPublished {
+		if n := len(diff.Create) - len(diff.Delete); n > 0 {
+			if err := db.Quotas.Check(ctx, tx.DB(), campaignQuotaNamespace(campaign), db.QuotaChangesets, int64(n)); err != nil {
+				return nil, nil, err
+			}
+		}
 		for _, c := range diff.Create {
 			err := tx.CreateChangesetJob(ctx, c)
 			if err != nil {
@@ -15,11 +15,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func init() {
@@ -112,6 +114,74 @@ func TestService(t *testing.T) {
 		}
 	})
 
+	t.Run("CreateCampaignPlanFromScript", func(t *testing.T) {
+		const commit = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		repoResolveRevision := func(_ context.Context, _ *repos.Repo, rev string) (api.CommitID, error) {
+			if rev != "refs/heads/main" {
+				return "", fmt.Errorf("unexpected revision %q", rev)
+			}
+			return commit, nil
+		}
+
+		script := &schema.CampaignScript{Name: "gofmt", Run: "gofmt -s -w .", Timeout: "1m"}
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			CampaignsScripts: []*schema.CampaignScript{script},
+		}})
+		defer conf.Mock(nil)
+
+		svc := NewServiceWithClock(store, nil, repoResolveRevision, nil, clock)
+		svc.repoDefaultBranch = func(context.Context, *repos.Repo) (string, error) {
+			return "refs/heads/main", nil
+		}
+
+		repoIDs := []api.RepoID{rs[0].ID, rs[1].ID}
+
+		if _, err := svc.CreateCampaignPlanFromScript(ctx, "unknown", repoIDs, user.ID); err == nil {
+			t.Fatal("expected error for unknown campaign script")
+		}
+
+		plan, err := svc.CreateCampaignPlanFromScript(ctx, "gofmt", repoIDs, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.CampaignType != campaignTypeScript {
+			t.Errorf("plan.CampaignType = %q, want %q", plan.CampaignType, campaignTypeScript)
+		}
+		if want := `{"name":"gofmt","run":"gofmt -s -w .","timeout":"1m"}`; plan.Arguments != want {
+			t.Errorf("plan.Arguments = %q, want %q", plan.Arguments, want)
+		}
+
+		jobs, _, err := store.ListCampaignJobs(ctx, ListCampaignJobsOpts{CampaignPlanID: plan.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, job := range jobs {
+			job.ID = 0 // ignore database ID when checking for expected output
+		}
+		wantJobs := make([]*campaigns.CampaignJob, len(repoIDs))
+		for i, id := range repoIDs {
+			wantJobs[i] = &campaigns.CampaignJob{
+				CampaignPlanID: plan.ID,
+				RepoID:         id,
+				BaseRef:        "refs/heads/main",
+				Rev:            commit,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+		}
+		if !cmp.Equal(jobs, wantJobs) {
+			t.Error("jobs != wantJobs", cmp.Diff(jobs, wantJobs))
+		}
+
+		status, err := store.GetCampaignPlanStatus(ctx, plan.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.ProcessState != campaigns.BackgroundProcessStateProcessing || status.Pending != int32(len(repoIDs)) {
+			t.Errorf("unexpected status %+v", status)
+		}
+	})
+
 	t.Run("CreateCampaign", func(t *testing.T) {
 		plan := &campaigns.CampaignPlan{CampaignType: "test", Arguments: `{}`, UserID: user.ID}
 		err = store.CreateCampaignPlan(ctx, plan)
@@ -14,6 +14,7 @@ import (
 	"github.com/segmentio/fasthash/fnv1"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -70,6 +71,7 @@ func (s *Store) Transact(ctx context.Context) (*Store, error) {
 // Returning an error will roll back the transaction.
 // NOTE: It should not be called from within an existing transaction
 func (s *Store) ProcessPendingChangesetJobs(ctx context.Context, process func(ctx context.Context, s *Store, job campaigns.ChangesetJob) error) (didRun bool, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ProcessPendingChangesetJobs")
 	tx, err := s.Transact(ctx)
 	if err != nil {
 		return false, errors.Wrap(err, "starting transaction")
@@ -122,6 +124,7 @@ RETURNING j.id,
 // Returning an error will roll back the transaction.
 // NOTE: It should not be called from within an existing transaction
 func (s *Store) ProcessPendingCampaignJob(ctx context.Context, process func(ctx context.Context, s *Store, job campaigns.CampaignJob) error) (didRun bool, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ProcessPendingCampaignJob")
 	tx, err := s.Transact(ctx)
 	if err != nil {
 		return false, errors.Wrap(err, "starting transaction")
@@ -163,6 +166,7 @@ RETURNING c.id,
   c.diff,
   c.description,
   c.error,
+  c.log,
   c.started_at,
   c.finished_at,
   c.created_at,
@@ -197,6 +201,7 @@ var lockNamespace = int32(fnv1.HashString32("campaigns"))
 // and is non blocking. If a lock is acquired, "true, nil" will be returned.
 // It must be called from within a transaction or "false, NoTransactionError" is returned
 func (s *Store) TryAcquireAdvisoryLock(ctx context.Context, key string) (bool, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "TryAcquireAdvisoryLock")
 	_, ok := s.db.(dbutil.Tx)
 	if !ok {
 		return false, NoTransactionError
@@ -264,6 +269,7 @@ func (e AlreadyExistError) Error() string {
 // database, it overwrites the fields of the affected changeset pointers with
 // the values contained in the database and returns an AlreadyExistError.
 func (s *Store) CreateChangesets(ctx context.Context, cs ...*campaigns.Changeset) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CreateChangesets")
 	q, err := s.createChangesetsQuery(cs)
 	if err != nil {
 		return err
@@ -446,6 +452,7 @@ type CountChangesetsOpts struct {
 
 // CountChangesets returns the number of changesets in the database.
 func (s *Store) CountChangesets(ctx context.Context, opts CountChangesetsOpts) (count int64, _ error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CountChangesets")
 	q := countChangesetsQuery(&opts)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
@@ -485,6 +492,7 @@ var ErrNoResults = errors.New("no results")
 
 // GetChangeset gets a changeset matching the given options.
 func (s *Store) GetChangeset(ctx context.Context, opts GetChangesetOpts) (*campaigns.Changeset, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetChangeset")
 	q := getChangesetQuery(&opts)
 
 	var c campaigns.Changeset
@@ -552,6 +560,7 @@ type ListChangesetsOpts struct {
 
 // ListChangesets lists Changesets with the given filters.
 func (s *Store) ListChangesets(ctx context.Context, opts ListChangesetsOpts) (cs []*campaigns.Changeset, next int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ListChangesets")
 	q := listChangesetsQuery(&opts)
 
 	cs = make([]*campaigns.Changeset, 0, opts.Limit)
@@ -633,6 +642,7 @@ func listChangesetsQuery(opts *ListChangesetsOpts) *sqlf.Query {
 
 // UpdateChangesets updates the given Changesets.
 func (s *Store) UpdateChangesets(ctx context.Context, cs ...*campaigns.Changeset) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "UpdateChangesets")
 	q, err := s.updateChangesetsQuery(cs)
 	if err != nil {
 		return err
@@ -702,6 +712,7 @@ type GetChangesetEventOpts struct {
 
 // GetChangesetEvent gets a changeset matching the given options.
 func (s *Store) GetChangesetEvent(ctx context.Context, opts GetChangesetEventOpts) (*campaigns.ChangesetEvent, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetChangesetEvent")
 	q := getChangesetEventQuery(&opts)
 
 	var c campaigns.ChangesetEvent
@@ -765,6 +776,7 @@ type ListChangesetEventsOpts struct {
 
 // ListChangesetEvents lists ChangesetEvents with the given filters.
 func (s *Store) ListChangesetEvents(ctx context.Context, opts ListChangesetEventsOpts) (cs []*campaigns.ChangesetEvent, next int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ListChangesetEvents")
 	q := listChangesetEventsQuery(&opts)
 
 	cs = make([]*campaigns.ChangesetEvent, 0, opts.Limit)
@@ -840,6 +852,7 @@ type CountChangesetEventsOpts struct {
 
 // CountChangesetEvents returns the number of changeset events in the database.
 func (s *Store) CountChangesetEvents(ctx context.Context, opts CountChangesetEventsOpts) (count int64, _ error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CountChangesetEvents")
 	q := countChangesetEventsQuery(&opts)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
@@ -869,6 +882,7 @@ func countChangesetEventsQuery(opts *CountChangesetEventsOpts) *sqlf.Query {
 
 // UpsertChangesetEvents creates or updates the given ChangesetEvents.
 func (s *Store) UpsertChangesetEvents(ctx context.Context, cs ...*campaigns.ChangesetEvent) (err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "UpsertChangesetEvents")
 	q, err := s.upsertChangesetEventsQuery(cs)
 	if err != nil {
 		return err
@@ -1000,6 +1014,7 @@ func batchChangesetEventsQuery(fmtstr string, es []*campaigns.ChangesetEvent) (*
 
 // CreateCampaign creates the given Campaign.
 func (s *Store) CreateCampaign(ctx context.Context, c *campaigns.Campaign) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CreateCampaign")
 	q, err := s.createCampaignQuery(c)
 	if err != nil {
 		return err
@@ -1102,6 +1117,7 @@ func nullStringColumn(s string) *string {
 
 // UpdateCampaign updates the given Campaign.
 func (s *Store) UpdateCampaign(ctx context.Context, c *campaigns.Campaign) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "UpdateCampaign")
 	q, err := s.updateCampaignQuery(c)
 	if err != nil {
 		return err
@@ -1170,6 +1186,7 @@ func (s *Store) updateCampaignQuery(c *campaigns.Campaign) (*sqlf.Query, error)
 
 // DeleteCampaign deletes the Campaign with the given ID.
 func (s *Store) DeleteCampaign(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "DeleteCampaign")
 	q := sqlf.Sprintf(deleteCampaignQueryFmtstr, id)
 
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -1193,6 +1210,7 @@ type CountCampaignsOpts struct {
 
 // CountCampaigns returns the number of campaigns in the database.
 func (s *Store) CountCampaigns(ctx context.Context, opts CountCampaignsOpts) (count int64, _ error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CountCampaigns")
 	q := countCampaignsQuery(&opts)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
@@ -1235,6 +1253,7 @@ type GetCampaignOpts struct {
 
 // GetCampaign gets a campaign matching the given options.
 func (s *Store) GetCampaign(ctx context.Context, opts GetCampaignOpts) (*campaigns.Campaign, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetCampaign")
 	q := getCampaignQuery(&opts)
 
 	var c campaigns.Campaign
@@ -1300,6 +1319,7 @@ type ListCampaignsOpts struct {
 
 // ListCampaigns lists Campaigns with the given filters.
 func (s *Store) ListCampaigns(ctx context.Context, opts ListCampaignsOpts) (cs []*campaigns.Campaign, next int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ListCampaigns")
 	q := listCampaignsQuery(&opts)
 
 	cs = make([]*campaigns.Campaign, 0, opts.Limit)
@@ -1371,6 +1391,7 @@ func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
 
 // CreateCampaignPlan creates the given CampaignPlan.
 func (s *Store) CreateCampaignPlan(ctx context.Context, c *campaigns.CampaignPlan) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CreateCampaignPlan")
 	q, err := s.createCampaignPlanQuery(c)
 	if err != nil {
 		return err
@@ -1430,6 +1451,7 @@ func (s *Store) createCampaignPlanQuery(c *campaigns.CampaignPlan) (*sqlf.Query,
 
 // UpdateCampaignPlan updates the given CampaignPlan.
 func (s *Store) UpdateCampaignPlan(ctx context.Context, c *campaigns.CampaignPlan) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "UpdateCampaignPlan")
 	q, err := s.updateCampaignPlanQuery(c)
 	if err != nil {
 		return err
@@ -1483,6 +1505,7 @@ func (s *Store) updateCampaignPlanQuery(c *campaigns.CampaignPlan) (*sqlf.Query,
 
 // DeleteCampaignPlan deletes the CampaignPlan with the given ID.
 func (s *Store) DeleteCampaignPlan(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "DeleteCampaignPlan")
 	q := sqlf.Sprintf(deleteCampaignPlanQueryFmtstr, id)
 
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -1502,6 +1525,7 @@ const CampaignPlanTTL = 1 * time.Hour
 // DeleteExpiredCampaignPlans deletes CampaignPlans that have finished execution
 // but have not been attached to a Campaign within CampaignPlanTTL.
 func (s *Store) DeleteExpiredCampaignPlans(ctx context.Context) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "DeleteExpiredCampaignPlans")
 	expirationTime := s.now().Add(-CampaignPlanTTL)
 	q := sqlf.Sprintf(deleteExpiredCampaignPlansQueryFmtstr, expirationTime)
 
@@ -1542,6 +1566,7 @@ NOT EXISTS (
 
 // CountCampaignPlans returns the number of code mods in the database.
 func (s *Store) CountCampaignPlans(ctx context.Context) (count int64, _ error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CountCampaignPlans")
 	q := sqlf.Sprintf(countCampaignPlansQueryFmtstr)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
@@ -1562,6 +1587,7 @@ type GetCampaignPlanOpts struct {
 
 // GetCampaignPlan gets a code mod matching the given options.
 func (s *Store) GetCampaignPlan(ctx context.Context, opts GetCampaignPlanOpts) (*campaigns.CampaignPlan, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetCampaignPlan")
 	q := getCampaignPlanQuery(&opts)
 
 	var c campaigns.CampaignPlan
@@ -1609,6 +1635,7 @@ func getCampaignPlanQuery(opts *GetCampaignPlanOpts) *sqlf.Query {
 
 // GetCampaignPlanStatus gets the campaigns.BackgroundProcessStatus for a CampaignPlan
 func (s *Store) GetCampaignPlanStatus(ctx context.Context, id int64) (*campaigns.BackgroundProcessStatus, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetCampaignPlanStatus")
 	return s.queryBackgroundProcessStatus(ctx, sqlf.Sprintf(
 		getCampaignPlanStatusQueryFmtstr,
 		id,
@@ -1631,6 +1658,7 @@ LIMIT 1;
 
 // GetCampaignStatus gets the campaigns.BackgroundProcessStatus for a Campaign
 func (s *Store) GetCampaignStatus(ctx context.Context, id int64) (*campaigns.BackgroundProcessStatus, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetCampaignStatus")
 	return s.queryBackgroundProcessStatus(ctx, sqlf.Sprintf(
 		getCampaignStatusQueryFmtstr,
 		sqlf.Sprintf("campaign_id = %s", id),
@@ -1683,6 +1711,7 @@ type ListCampaignPlansOpts struct {
 
 // ListCampaignPlans lists CampaignPlans with the given filters.
 func (s *Store) ListCampaignPlans(ctx context.Context, opts ListCampaignPlansOpts) (cs []*campaigns.CampaignPlan, next int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ListCampaignPlans")
 	q := listCampaignPlansQuery(&opts)
 
 	cs = make([]*campaigns.CampaignPlan, 0, opts.Limit)
@@ -1740,6 +1769,7 @@ func listCampaignPlansQuery(opts *ListCampaignPlansOpts) *sqlf.Query {
 // Due to a unique constraint in the DB it is safe to call this more than once
 // with the same input. Only one job will be added and the other calls will return an error
 func (s *Store) CreateCampaignJob(ctx context.Context, c *campaigns.CampaignJob) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CreateCampaignJob")
 	q, err := s.createCampaignJobQuery(c)
 	if err != nil {
 		return err
@@ -1761,12 +1791,13 @@ INSERT INTO campaign_jobs (
   diff,
   description,
   error,
+  log,
   started_at,
   finished_at,
   created_at,
   updated_at
 )
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
   id,
   campaign_plan_id,
@@ -1776,6 +1807,7 @@ RETURNING
   diff,
   description,
   error,
+  log,
   started_at,
   finished_at,
   created_at,
@@ -1800,6 +1832,7 @@ func (s *Store) createCampaignJobQuery(c *campaigns.CampaignJob) (*sqlf.Query, e
 		c.Diff,
 		c.Description,
 		c.Error,
+		c.Log,
 		nullTimeColumn(c.StartedAt),
 		nullTimeColumn(c.FinishedAt),
 		c.CreatedAt,
@@ -1809,6 +1842,7 @@ func (s *Store) createCampaignJobQuery(c *campaigns.CampaignJob) (*sqlf.Query, e
 
 // UpdateCampaignJob updates the given CampaignJob.
 func (s *Store) UpdateCampaignJob(ctx context.Context, c *campaigns.CampaignJob) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "UpdateCampaignJob")
 	q, err := s.updateCampaignJobQuery(c)
 	if err != nil {
 		return err
@@ -1831,10 +1865,11 @@ SET (
   diff,
   description,
   error,
+  log,
   started_at,
   finished_at,
   updated_at
-) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   id,
@@ -1845,6 +1880,7 @@ RETURNING
   diff,
   description,
   error,
+  log,
   started_at,
   finished_at,
   created_at,
@@ -1863,6 +1899,7 @@ func (s *Store) updateCampaignJobQuery(c *campaigns.CampaignJob) (*sqlf.Query, e
 		c.Diff,
 		c.Description,
 		c.Error,
+		c.Log,
 		c.StartedAt,
 		c.FinishedAt,
 		c.UpdatedAt,
@@ -1872,6 +1909,7 @@ func (s *Store) updateCampaignJobQuery(c *campaigns.CampaignJob) (*sqlf.Query, e
 
 // DeleteCampaignJob deletes the CampaignJob with the given ID.
 func (s *Store) DeleteCampaignJob(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "DeleteCampaignJob")
 	q := sqlf.Sprintf(deleteCampaignJobQueryFmtstr, id)
 
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -1901,6 +1939,7 @@ type CountCampaignJobsOpts struct {
 
 // CountCampaignJobs returns the number of CampaignJobs in the database.
 func (s *Store) CountCampaignJobs(ctx context.Context, opts CountCampaignJobsOpts) (count int64, _ error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CountCampaignJobs")
 	q := countCampaignJobsQuery(&opts)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
@@ -1947,6 +1986,7 @@ type GetCampaignJobOpts struct {
 
 // GetCampaignJob gets a code mod matching the given options.
 func (s *Store) GetCampaignJob(ctx context.Context, opts GetCampaignJobOpts) (*campaigns.CampaignJob, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetCampaignJob")
 	q := getCampaignJobQuery(&opts)
 
 	var c campaigns.CampaignJob
@@ -1975,6 +2015,7 @@ SELECT
   diff,
   description,
   error,
+  log,
   started_at,
   finished_at,
   created_at,
@@ -2014,6 +2055,7 @@ type ListCampaignJobsOpts struct {
 
 // ListCampaignJobs lists CampaignJobs with the given filters.
 func (s *Store) ListCampaignJobs(ctx context.Context, opts ListCampaignJobsOpts) (cs []*campaigns.CampaignJob, next int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ListCampaignJobs")
 	q := listCampaignJobsQuery(&opts)
 
 	cs = make([]*campaigns.CampaignJob, 0, opts.Limit)
@@ -2045,6 +2087,7 @@ SELECT
   diff,
   description,
   error,
+  log,
   started_at,
   finished_at,
   created_at,
@@ -2110,6 +2153,7 @@ func onlyUnpublishedInCampaignQuery(campaignID int64) *sqlf.Query {
 
 // CreateChangesetJob creates the given ChangesetJob.
 func (s *Store) CreateChangesetJob(ctx context.Context, c *campaigns.ChangesetJob) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CreateChangesetJob")
 	q, err := s.createChangesetJobQuery(c)
 	if err != nil {
 		return err
@@ -2173,6 +2217,7 @@ func (s *Store) createChangesetJobQuery(c *campaigns.ChangesetJob) (*sqlf.Query,
 
 // UpdateChangesetJob updates the given ChangesetJob.
 func (s *Store) UpdateChangesetJob(ctx context.Context, c *campaigns.ChangesetJob) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "UpdateChangesetJob")
 	q, err := s.updateChangesetJobQuery(c)
 	if err != nil {
 		return err
@@ -2230,6 +2275,7 @@ func (s *Store) updateChangesetJobQuery(c *campaigns.ChangesetJob) (*sqlf.Query,
 
 // DeleteChangesetJob deletes the ChangesetJob with the given ID.
 func (s *Store) DeleteChangesetJob(ctx context.Context, id int64) error {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "DeleteChangesetJob")
 	q := sqlf.Sprintf(deleteChangesetJobQueryFmtstr, id)
 
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -2252,6 +2298,7 @@ type CountChangesetJobsOpts struct {
 
 // CountChangesetJobs returns the number of code mods in the database.
 func (s *Store) CountChangesetJobs(ctx context.Context, opts CountChangesetJobsOpts) (count int64, _ error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "CountChangesetJobs")
 	q := countChangesetJobsQuery(&opts)
 	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
 		err = sc.Scan(&count)
@@ -2282,6 +2329,7 @@ func countChangesetJobsQuery(opts *CountChangesetJobsOpts) *sqlf.Query {
 // GetLatestChangesetJobCreatedAt returns the most recent created_at time for all changeset jobs
 // for a campaign. But only if they have all been created, one for each CampaignJob belonging to the CampaignPlan attached to the Campaign. If not, it returns a zero time.Time.
 func (s *Store) GetLatestChangesetJobCreatedAt(ctx context.Context, campaignID int64) (time.Time, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetLatestChangesetJobCreatedAt")
 	q := sqlf.Sprintf(getLatestChangesetJobPublishedAtFmtstr, campaignID)
 	var createdAt time.Time
 	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
@@ -2317,6 +2365,7 @@ type GetChangesetJobOpts struct {
 
 // GetChangesetJob gets a ChangesetJob matching the given options.
 func (s *Store) GetChangesetJob(ctx context.Context, opts GetChangesetJobOpts) (*campaigns.ChangesetJob, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetChangesetJob")
 	q := getChangesetJobQuery(&opts)
 
 	var c campaigns.ChangesetJob
@@ -2388,6 +2437,7 @@ type ListChangesetJobsOpts struct {
 
 // ListChangesetJobs lists ChangesetJobs with the given filters.
 func (s *Store) ListChangesetJobs(ctx context.Context, opts ListChangesetJobsOpts) (cs []*campaigns.ChangesetJob, next int64, err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ListChangesetJobs")
 	q := listChangesetJobsQuery(&opts)
 
 	cs = make([]*campaigns.ChangesetJob, 0, opts.Limit)
@@ -2465,6 +2515,7 @@ func listChangesetJobsQuery(opts *ListChangesetJobsOpts) *sqlf.Query {
 // of the ChangesetJobs belonging to the Campaign with the given ID that
 // resulted in an error.
 func (s *Store) ResetFailedChangesetJobs(ctx context.Context, campaignID int64) (err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ResetFailedChangesetJobs")
 	q := resetChangesetJobsQuery(campaignID, true)
 
 	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
@@ -2475,6 +2526,7 @@ func (s *Store) ResetFailedChangesetJobs(ctx context.Context, campaignID int64)
 // ResetChangesetJobs resets the Error, StartedAt and FinishedAt fields
 // of all ChangesetJobs belonging to the Campaign with the given ID.
 func (s *Store) ResetChangesetJobs(ctx context.Context, campaignID int64) (err error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "ResetChangesetJobs")
 	q := resetChangesetJobsQuery(campaignID, false)
 
 	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
@@ -2511,6 +2563,7 @@ WHERE %s
 // a slice of head refs. We need this in order to match incoming status webhooks to pull requests as
 // the only information they provide is the remote branch
 func (s *Store) GetGithubExternalIDForRefs(ctx context.Context, refs []string) ([]string, error) {
+	ctx = dbconn.WithQueryLabels(ctx, "Campaigns", "GetGithubExternalIDForRefs")
 	queryFmtString := `
 SELECT external_id FROM changesets
 WHERE external_service_type = 'github'
@@ -2686,6 +2739,7 @@ func scanCampaignJob(c *campaigns.CampaignJob, s scanner) error {
 		&c.Diff,
 		&c.Description,
 		&c.Error,
+		&c.Log,
 		&dbutil.NullTime{Time: &c.StartedAt},
 		&dbutil.NullTime{Time: &c.FinishedAt},
 		&c.CreatedAt,
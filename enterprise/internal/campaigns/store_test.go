@@ -1180,6 +1180,7 @@ func testStore(db *sql.DB) func(*testing.T) {
 						Diff:           "+ foobar - barfoo",
 						Description:    "- Removed 3 instances of foobar\n",
 						Error:          "only set on error",
+						Log:            "+ sed -i s/foobar/barfoo/g\n",
 					}
 
 					want := c.Clone()
@@ -1529,6 +1530,7 @@ func testStore(db *sql.DB) func(*testing.T) {
 					c.Diff += "-updated"
 					c.Description += "-updated"
 					c.Error += "-updated"
+					c.Log += "-updated"
 
 					want := c
 					want.UpdatedAt = now
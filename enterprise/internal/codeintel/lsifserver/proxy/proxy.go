@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -19,8 +20,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 func NewProxy() (*httpapi.LSIFServerProxy, error) {
@@ -53,10 +57,25 @@ func uploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter, *htt
 		// 🚨 SECURITY: Ensure we return before proxying to the lsif-server upload
 		// endpoint. This endpoint is unprotected, so we need to make sure the user
 		// provides a valid token proving contributor access to the repository.
-		if conf.Get().LsifEnforceAuth && !enforceAuth(ctx, w, r, repoName) {
+		if conf.Get().LsifEnforceAuth && !EnforceAuth(ctx, w, r, repoName) {
 			return
 		}
 
+		// The quota check and the recording of the upload's size happen in one transaction, so that
+		// concurrent uploads wait for each other (see db.Quotas.Check) and can't together exceed
+		// the quota.
+		tx, err := dbconn.Global.BeginTx(ctx, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if !checkUploadQuotas(ctx, w, tx, repo, r.ContentLength) {
+			return
+		}
+		body := &countingReadCloser{ReadCloser: r.Body}
+
 		uploadID, queued, err := client.DefaultClient.Upload(ctx, &struct {
 			RepoID      api.RepoID
 			Commit      graphqlbackend.GitObjectID
@@ -70,7 +89,7 @@ func uploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter, *htt
 			Commit:      graphqlbackend.GitObjectID(commit),
 			Root:        root,
 			IndexerName: indexerName,
-			Body:        r.Body,
+			Body:        body,
 		})
 
 		if err != nil {
@@ -78,6 +97,12 @@ func uploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter, *htt
 			return
 		}
 
+		if err := db.Quotas.SetLSIFUploadSize(ctx, tx, uploadID, body.n); err != nil {
+			log15.Warn("Failed to record LSIF upload size.", "id", uploadID, "error", err)
+		} else if err := tx.Commit(); err != nil {
+			log15.Warn("Failed to record LSIF upload size.", "id", uploadID, "error", err)
+		}
+
 		// Return id as a string to maintain backwards compatibility with src-cli
 		payload, err := json.Marshal(map[string]string{"id": strconv.FormatInt(uploadID, 10)})
 		if err != nil {
@@ -120,7 +145,54 @@ func ensureRepoAndCommitExist(ctx context.Context, w http.ResponseWriter, repoNa
 	return repo, true
 }
 
-func enforceAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, repoName string) bool {
+// checkUploadQuotas checks that an upload of the given size (or of unknown size, if it's negative)
+// doesn't exceed the LSIF upload quota of the organizations whose external services sync the
+// repository. If it returns false, it has written an error response.
+//
+// Uploads of unknown size are rejected if the repository counts against any organization's quota.
+// The size of other uploads can be trusted, because the HTTP server doesn't read more of a request
+// body than its Content-Length.
+//
+// The quotas are checked in tx, which holds their locks until the caller has recorded the size of
+// the upload in it and committed.
+func checkUploadQuotas(ctx context.Context, w http.ResponseWriter, tx dbutil.DB, repo *types.Repo, size int64) bool {
+	orgIDs, err := db.Quotas.ListOrgsOfRepo(ctx, repo.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if len(orgIDs) > 0 && size < 0 {
+		http.Error(w, "the Content-Length header is required to check the LSIF upload quota", http.StatusLengthRequired)
+		return false
+	}
+	for _, orgID := range orgIDs {
+		if err := db.Quotas.Check(ctx, tx, db.QuotaNamespace{OrgID: orgID}, db.QuotaLSIFUploadBytes, size); err != nil {
+			status := http.StatusInternalServerError
+			if db.IsQuotaExceeded(err) {
+				status = http.StatusForbidden
+			}
+			http.Error(w, err.Error(), status)
+			return false
+		}
+	}
+	return true
+}
+
+// countingReadCloser counts the bytes that are read from the wrapped io.ReadCloser.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// EnforceAuth checks that the request's code host token (e.g., the github_token query parameter)
+// grants write access to the repository. If it returns false, it has written an error response.
+func EnforceAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, repoName string) bool {
 	validatorByCodeHost := map[string]func(context.Context, http.ResponseWriter, *http.Request, string) (int, error){
 		"github.com": enforceAuthGithub,
 	}
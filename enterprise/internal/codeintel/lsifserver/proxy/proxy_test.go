@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestCheckUploadQuotas(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.Quotas.ListOrgsOfRepo = func(ctx context.Context, repoID api.RepoID) ([]int32, error) {
+		if repoID == 1 {
+			return []int32{1}, nil
+		}
+		return nil, nil
+	}
+	db.Mocks.Quotas.Check = func(ctx context.Context, ns db.QuotaNamespace, resource db.QuotaResource, n int64) error {
+		if n > 100 {
+			return &db.QuotaExceededError{Namespace: ns, Resource: resource, Limit: 100}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		repoID     api.RepoID
+		size       int64
+		wantStatus int
+	}{
+		{name: "within quota", repoID: 1, size: 100, wantStatus: http.StatusOK},
+		{name: "over quota", repoID: 1, size: 101, wantStatus: http.StatusForbidden},
+		{name: "unknown size", repoID: 1, size: -1, wantStatus: http.StatusLengthRequired},
+		{name: "unknown size without quota", repoID: 2, size: -1, wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ok := checkUploadQuotas(context.Background(), rec, nil, &types.Repo{ID: test.repoID}, test.size)
+			if ok != (test.wantStatus == http.StatusOK) || rec.Code != test.wantStatus {
+				t.Errorf("got %v with status %d, want status %d", ok, rec.Code, test.wantStatus)
+			}
+		})
+	}
+}
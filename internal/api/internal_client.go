@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -108,6 +109,18 @@ func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
 	return bytes.Equal(a, b)
 }
 
+// SupportsAfter reports whether the saved query is a commit or diff search. Other searches do not
+// support the after:"time" operator, so their new results can't be found.
+func (sq ConfigSavedQuery) SupportsAfter() bool {
+	return strings.Contains(sq.Query, "type:diff") || strings.Contains(sq.Query, "type:commit")
+}
+
+// QueryAfter returns the saved query, restricted to the results introduced after t with the
+// after:"time" operator.
+func (sq ConfigSavedQuery) QueryAfter(t time.Time) string {
+	return strings.Join([]string{sq.Query, fmt.Sprintf(`after:"%s"`, t.UTC().Format(time.RFC3339))}, " ")
+}
+
 // PartialConfigSavedQueries is the JSON configuration shape, including only the
 // search.savedQueries section.
 type PartialConfigSavedQueries struct {
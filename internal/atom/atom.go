@@ -0,0 +1,168 @@
+// Package atom writes and validates Atom feeds (RFC 4287).
+package atom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ContentType is the media type of Atom feeds.
+const ContentType = "application/atom+xml; charset=utf-8"
+
+const namespace = "http://www.w3.org/2005/Atom"
+
+// A Feed is an Atom feed document.
+type Feed struct {
+	XMLName xml.Name  `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string    `xml:"id"` // an absolute IRI that never changes
+	Title   string    `xml:"title"`
+	Updated time.Time `xml:"updated"`
+	Author  *Person   `xml:"author,omitempty"` // required unless all entries have an author
+	Links   []*Link   `xml:"link"`
+	Entries []*Entry  `xml:"entry"`
+}
+
+// An Entry is an entry of a feed.
+type Entry struct {
+	ID      string    `xml:"id"` // an absolute IRI that never changes
+	Title   string    `xml:"title"`
+	Updated time.Time `xml:"updated"`
+	Author  *Person   `xml:"author,omitempty"`
+	Links   []*Link   `xml:"link"`
+	Summary *Text     `xml:"summary,omitempty"`
+	Content *Text     `xml:"content,omitempty"`
+}
+
+// A Person is the author of a feed or an entry.
+type Person struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+	URI   string `xml:"uri,omitempty"`
+}
+
+// A Link is a reference from a feed or an entry to a web resource. The default relation is
+// "alternate".
+type Link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// A Text is the summary or content of an entry. Its type is "text" (the default), "html" or
+// "xhtml".
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// Marshal returns the XML document of the feed. The times of the feed and its entries are written in
+// UTC with second precision.
+func (f *Feed) Marshal() ([]byte, error) {
+	g := *f
+	g.Updated = g.Updated.UTC().Truncate(time.Second)
+	g.Entries = make([]*Entry, len(f.Entries))
+	for i, e := range f.Entries {
+		ee := *e
+		ee.Updated = ee.Updated.UTC().Truncate(time.Second)
+		g.Entries[i] = &ee
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&g); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Validate checks that data is an Atom feed document that conforms to the constraints of RFC 4287
+// that this package relies on: the feed and each entry have exactly one ID (an absolute IRI), title
+// and updated time (an RFC 3339 timestamp), entry IDs are unique, each entry has an author (or the
+// feed has one), each entry has content or an alternate link, and each link has an href.
+func Validate(data []byte) error {
+	type person struct {
+		Names []string `xml:"name"`
+	}
+	type element struct {
+		IDs      []string  `xml:"id"`
+		Titles   []string  `xml:"title"`
+		Updated  []string  `xml:"updated"`
+		Authors  []person  `xml:"author"`
+		Links    []Link    `xml:"link"`
+		Contents []Text    `xml:"content"`
+		Entries  []element `xml:"entry"`
+	}
+	var feed struct {
+		XMLName xml.Name
+		element
+	}
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return err
+	}
+	if feed.XMLName.Space != namespace || feed.XMLName.Local != "feed" {
+		return fmt.Errorf("root element is {%s}%s, want {%s}feed", feed.XMLName.Space, feed.XMLName.Local, namespace)
+	}
+
+	check := func(what string, e *element) (id string, err error) {
+		for _, c := range []struct {
+			name   string
+			values []string
+		}{{"id", e.IDs}, {"title", e.Titles}, {"updated", e.Updated}} {
+			if len(c.values) != 1 {
+				return "", fmt.Errorf("%s has %d %s elements, want 1", what, len(c.values), c.name)
+			}
+		}
+		if u, err := url.Parse(e.IDs[0]); err != nil || !u.IsAbs() {
+			return "", fmt.Errorf("%s id %q is not an absolute IRI", what, e.IDs[0])
+		}
+		if _, err := time.Parse(time.RFC3339, e.Updated[0]); err != nil {
+			return "", fmt.Errorf("%s updated %q is not an RFC 3339 timestamp", what, e.Updated[0])
+		}
+		for _, a := range e.Authors {
+			if len(a.Names) != 1 || a.Names[0] == "" {
+				return "", fmt.Errorf("%s has an author without a name", what)
+			}
+		}
+		for _, l := range e.Links {
+			if l.Href == "" {
+				return "", fmt.Errorf("%s has a link without an href", what)
+			}
+		}
+		return e.IDs[0], nil
+	}
+
+	if _, err := check("feed", &feed.element); err != nil {
+		return err
+	}
+	ids := map[string]bool{}
+	for i := range feed.Entries {
+		e := &feed.Entries[i]
+		id, err := check(fmt.Sprintf("entry %d", i), e)
+		if err != nil {
+			return err
+		}
+		if ids[id] {
+			return fmt.Errorf("entry id %q is not unique", id)
+		}
+		ids[id] = true
+		if len(e.Authors) == 0 && len(feed.Authors) == 0 {
+			return fmt.Errorf("entry %q has no author, and neither does the feed", id)
+		}
+		if len(e.Contents) == 0 {
+			var alternate bool
+			for _, l := range e.Links {
+				alternate = alternate || l.Rel == "" || l.Rel == "alternate"
+			}
+			if !alternate {
+				return fmt.Errorf("entry %q has neither content nor an alternate link", id)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package atom
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeed_Marshal(t *testing.T) {
+	updated := time.Date(2020, 3, 4, 5, 6, 7, 891, time.FixedZone("", 3600))
+	f := &Feed{
+		ID:      "https://sourcegraph.example.com/feeds/1",
+		Title:   "Commits <& more>",
+		Updated: updated,
+		Author:  &Person{Name: "Sourcegraph"},
+		Links:   []*Link{{Href: "https://sourcegraph.example.com/"}},
+		Entries: []*Entry{
+			{
+				ID:      "https://sourcegraph.example.com/-/commit/a",
+				Title:   "Fix the <script> tag",
+				Updated: updated,
+				Author:  &Person{Name: "Alice", Email: "alice@example.com"},
+				Links:   []*Link{{Href: "https://sourcegraph.example.com/-/commit/a"}},
+				Content: &Text{Body: "a\n\tb & c"},
+			},
+		},
+	}
+	data, err := f.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(data); err != nil {
+		t.Fatalf("invalid feed: %s\n%s", err, data)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>https://sourcegraph.example.com/feeds/1</id>
+  <title>Commits &lt;&amp; more&gt;</title>
+  <updated>2020-03-04T04:06:07Z</updated>
+  <author>
+    <name>Sourcegraph</name>
+  </author>
+  <link href="https://sourcegraph.example.com/"></link>
+  <entry>
+    <id>https://sourcegraph.example.com/-/commit/a</id>
+    <title>Fix the &lt;script&gt; tag</title>
+    <updated>2020-03-04T04:06:07Z</updated>
+    <author>
+      <name>Alice</name>
+      <email>alice@example.com</email>
+    </author>
+    <link href="https://sourcegraph.example.com/-/commit/a"></link>
+    <content>a&#xA;&#x9;b &amp; c</content>
+  </entry>
+</feed>
+`
+	if string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+
+	// Marshal doesn't modify the feed.
+	if !f.Updated.Equal(updated) || f.Updated.Nanosecond() == 0 {
+		t.Errorf("feed was modified: %s", f.Updated)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	const (
+		head = `<feed xmlns="http://www.w3.org/2005/Atom"><id>urn:a</id><title>t</title><updated>2020-01-01T00:00:00Z</updated>`
+		tail = `</feed>`
+	)
+	tests := map[string]struct {
+		feed    string
+		wantErr string
+	}{
+		"valid": {
+			feed: head + `<author><name>a</name></author><entry><id>urn:b</id><title>t</title><updated>2020-01-01T00:00:00Z</updated><link href="https://x"/></entry>` + tail,
+		},
+		"valid with entry author and content": {
+			feed: head + `<entry><id>urn:b</id><title>t</title><updated>2020-01-01T00:00:00Z</updated><author><name>a</name></author><content>c</content></entry>` + tail,
+		},
+		"wrong namespace": {
+			feed:    `<feed><id>urn:a</id><title>t</title><updated>2020-01-01T00:00:00Z</updated></feed>`,
+			wantErr: "root element",
+		},
+		"missing title": {
+			feed:    `<feed xmlns="http://www.w3.org/2005/Atom"><id>urn:a</id><updated>2020-01-01T00:00:00Z</updated></feed>`,
+			wantErr: "feed has 0 title elements",
+		},
+		"relative id": {
+			feed:    `<feed xmlns="http://www.w3.org/2005/Atom"><id>a</id><title>t</title><updated>2020-01-01T00:00:00Z</updated></feed>`,
+			wantErr: "not an absolute IRI",
+		},
+		"invalid updated": {
+			feed:    `<feed xmlns="http://www.w3.org/2005/Atom"><id>urn:a</id><title>t</title><updated>yesterday</updated></feed>`,
+			wantErr: "not an RFC 3339 timestamp",
+		},
+		"duplicate entry id": {
+			feed: head + `<author><name>a</name></author>` +
+				`<entry><id>urn:b</id><title>t</title><updated>2020-01-01T00:00:00Z</updated><content>c</content></entry>` +
+				`<entry><id>urn:b</id><title>t</title><updated>2020-01-01T00:00:00Z</updated><content>c</content></entry>` + tail,
+			wantErr: "not unique",
+		},
+		"entry without author": {
+			feed:    head + `<entry><id>urn:b</id><title>t</title><updated>2020-01-01T00:00:00Z</updated><content>c</content></entry>` + tail,
+			wantErr: "has no author",
+		},
+		"entry without content or alternate link": {
+			feed:    head + `<author><name>a</name></author><entry><id>urn:b</id><title>t</title><updated>2020-01-01T00:00:00Z</updated><link rel="related" href="https://x"/></entry>` + tail,
+			wantErr: "neither content nor an alternate link",
+		},
+		"link without href": {
+			feed:    head + `<link/>` + tail,
+			wantErr: "link without an href",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Validate([]byte(test.feed))
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("got error %q, want none", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -76,6 +76,10 @@ type CampaignJob struct {
 
 	Error string
 
+	// Log is the output of the campaign script that produced Diff, if the
+	// CampaignJob was executed on the server.
+	Log string
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
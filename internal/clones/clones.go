@@ -0,0 +1,130 @@
+// Package clones fingerprints source files for the detection of duplicated code (clones) across
+// files and repositories.
+//
+// Files are tokenized with identifiers and literals normalized, so that copies whose variables were
+// renamed or whose constants were changed are still detected. The token sequences are fingerprinted
+// with winnowing (Schleimer, Wilkerson and Aiken, "Winnowing: Local Algorithms for Document
+// Fingerprinting", SIGMOD 2003): of the hashes of all k-token sequences (k-grams), only the minimum
+// hash of each window of w consecutive k-grams is kept. Two files that share a sequence of at least
+// GuaranteeThreshold tokens are guaranteed to share a fingerprint, and sequences shorter than
+// NoiseThreshold tokens never cause a match.
+package clones
+
+const (
+	// NoiseThreshold is the number of tokens of a k-gram. Shorter matches are never detected.
+	NoiseThreshold = 25
+
+	// GuaranteeThreshold is the length (in tokens) of the shortest match that is always detected.
+	GuaranteeThreshold = 50
+
+	// window is the number of consecutive k-grams that winnowing selects a fingerprint from.
+	window = GuaranteeThreshold - NoiseThreshold + 1
+)
+
+// A Fingerprint is the hash of a sequence of NoiseThreshold normalized tokens of a file.
+type Fingerprint struct {
+	Hash      int64
+	StartLine int // the line of the first token of the sequence (1-based)
+	EndLine   int // the line of the last token of the sequence (1-based)
+}
+
+// FileFingerprints returns the winnowed fingerprints of a source file, in the order of their
+// position in the file. It returns nil if the file's language isn't supported (see Supported) or
+// if the file has fewer than NoiseThreshold tokens.
+func FileFingerprints(path string, content []byte) []Fingerprint {
+	lang := languageOf(path)
+	if lang == nil {
+		return nil
+	}
+	return fingerprints(tokenize(lang, content))
+}
+
+// FilterLines returns the fingerprints whose token sequences lie within the given lines (1-based,
+// inclusive).
+func FilterLines(fps []Fingerprint, startLine, endLine int) []Fingerprint {
+	var filtered []Fingerprint
+	for _, fp := range fps {
+		if fp.StartLine >= startLine && fp.EndLine <= endLine {
+			filtered = append(filtered, fp)
+		}
+	}
+	return filtered
+}
+
+// Constants of the 64-bit FNV-1a hash (used for token texts) and the base of the polynomial rolling
+// hash of k-grams.
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+	base      = 1000003
+)
+
+func hashText(s string) uint64 {
+	h := uint64(fnvOffset)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+// fingerprints returns the winnowed fingerprints of the tokens.
+func fingerprints(tokens []token) []Fingerprint {
+	n := len(tokens) - NoiseThreshold + 1 // the number of k-grams
+	if n <= 0 {
+		return nil
+	}
+
+	// Compute the hashes of all k-grams with a rolling hash (modulo 2^64).
+	var pow uint64 = 1 // base^(k-1)
+	for i := 1; i < NoiseThreshold; i++ {
+		pow *= base
+	}
+	hashes := make([]uint64, n)
+	var h uint64
+	for i, t := range tokens {
+		if i >= NoiseThreshold {
+			h -= hashText(tokens[i-NoiseThreshold].text) * pow
+		}
+		h = h*base + hashText(t.text)
+		if i >= NoiseThreshold-1 {
+			hashes[i-NoiseThreshold+1] = h
+		}
+	}
+
+	kgram := func(i int) Fingerprint {
+		return Fingerprint{
+			Hash:      int64(hashes[i]),
+			StartLine: tokens[i].line,
+			EndLine:   tokens[i+NoiseThreshold-1].line,
+		}
+	}
+
+	// Files with fewer k-grams than a window have a single window.
+	w := window
+	if n < w {
+		w = n
+	}
+
+	// Select the minimum hash of each window (the rightmost one in case of ties), and record it
+	// unless it was already selected for the previous window.
+	var fps []Fingerprint
+	selected := -1
+	for start := 0; start+w <= n; start++ {
+		if selected < start {
+			// The previous minimum left the window, so find the minimum of the whole window.
+			selected = start
+			for i := start + 1; i < start+w; i++ {
+				if hashes[i] <= hashes[selected] {
+					selected = i
+				}
+			}
+			fps = append(fps, kgram(selected))
+		} else if last := start + w - 1; hashes[last] <= hashes[selected] {
+			// Only the k-gram that entered the window can be a new minimum.
+			selected = last
+			fps = append(fps, kgram(selected))
+		}
+	}
+	return fps
+}
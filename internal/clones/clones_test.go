@@ -0,0 +1,154 @@
+package clones
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		path string
+		src  string
+		want []token
+	}{
+		{
+			path: "a.go",
+			src:  "// c\nx := f(\"a\\\"b\", 1.5) /* c\nc */ + `r\nr`",
+			want: []token{
+				{identToken, 2}, {":", 2}, {"=", 2}, {identToken, 2}, {"(", 2}, {literalToken, 2}, {",", 2},
+				{literalToken, 2}, {")", 2}, {"+", 3}, {literalToken, 3},
+			},
+		},
+		{
+			path: "a.py",
+			src:  "def f(s):\n    '''doc\n    '''\n    return s # c\n",
+			want: []token{
+				{"def", 1}, {identToken, 1}, {"(", 1}, {identToken, 1}, {")", 1}, {":", 1},
+				{literalToken, 2}, {"return", 4}, {identToken, 4},
+			},
+		},
+		{
+			// An unterminated string literal ends at the end of the line.
+			path: "a.c",
+			src:  "s = \"a\nint",
+			want: []token{{identToken, 1}, {"=", 1}, {literalToken, 1}, {"int", 2}},
+		},
+	}
+	for _, test := range tests {
+		got := tokenize(languageOf(test.path), []byte(test.src))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.path, got, test.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := map[string]bool{
+		"a.go":                     true,
+		"src/a.ts":                 true,
+		"a.py":                     true,
+		"README.md":                false,
+		"vendor/github.com/x/a.go": false,
+		"node_modules/x/index.js":  false,
+	}
+	for path, want := range tests {
+		if got := Supported(path); got != want {
+			t.Errorf("%s: got %v, want %v", path, got, want)
+		}
+	}
+}
+
+// goFunc returns the source of a Go function with the given name that is long enough to be
+// fingerprinted.
+func goFunc(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "func %s(a, b int) int {\n", name)
+	for i, op := range []string{"+", "-", "*", "/", "%", "&", "|", "^"} {
+		fmt.Fprintf(&b, "\tif a > %d {\n\t\tb = b %s a\n\t}\n", i, op)
+	}
+	b.WriteString("\treturn b\n}\n")
+	return b.String()
+}
+
+func hashes(fps []Fingerprint) map[int64]bool {
+	m := map[int64]bool{}
+	for _, fp := range fps {
+		m[fp.Hash] = true
+	}
+	return m
+}
+
+func TestFileFingerprints(t *testing.T) {
+	orig := FileFingerprints("a.go", []byte("package a\n\n"+goFunc("sum")))
+	if len(orig) == 0 {
+		t.Fatal("got no fingerprints")
+	}
+
+	// A copy with renamed identifiers, other comments and another position in a file has the same
+	// fingerprints.
+	renamed := strings.Replace(goFunc("total"), "a", "x", -1)
+	other := "func other(s []string) {\n\tswitch len(s) {\n\tcase 0:\n\t\tpanic(s)\n\tdefault:\n\t\tfmt.Println(s[0], s[1:])\n\t}\n}\n"
+	copied := FileFingerprints("b/b.go", []byte("package b\n\n// Total is a copy.\n"+other+"\n"+renamed))
+	copiedHashes := hashes(copied)
+	for _, fp := range orig {
+		if !copiedHashes[fp.Hash] {
+			t.Errorf("fingerprint %+v of the original is not in the copy", fp)
+		}
+	}
+
+	// The lines of the fingerprints are where the copy is.
+	offset := strings.Count(other, "\n") + 2
+	for _, fp := range orig {
+		for _, c := range copied {
+			if c.Hash == fp.Hash && (c.StartLine != fp.StartLine+offset || c.EndLine != fp.EndLine+offset) {
+				t.Errorf("got copied fingerprint at lines %d-%d, want %d-%d", c.StartLine, c.EndLine, fp.StartLine+offset, fp.EndLine+offset)
+			}
+		}
+	}
+
+	if fps := FileFingerprints("short.go", []byte("package a\n\nfunc f() {}\n")); fps != nil {
+		t.Errorf("got fingerprints %v for a file with fewer tokens than the noise threshold", fps)
+	}
+	if fps := FileFingerprints("a.txt", []byte(goFunc("sum"))); fps != nil {
+		t.Errorf("got fingerprints %v for an unsupported file", fps)
+	}
+}
+
+func TestFingerprints_guarantee(t *testing.T) {
+	// Any shared sequence of GuaranteeThreshold tokens has a shared fingerprint, no matter what
+	// precedes and follows it.
+	shared := make([]token, GuaranteeThreshold)
+	for i := range shared {
+		shared[i] = token{text: fmt.Sprint("s", i), line: 1}
+	}
+	for prefix := 0; prefix < 2*window; prefix += 7 {
+		var tokens []token
+		for i := 0; i < prefix; i++ {
+			tokens = append(tokens, token{text: fmt.Sprint("p", i), line: 1})
+		}
+		tokens = append(tokens, shared...)
+		tokens = append(tokens, token{text: "end", line: 1})
+
+		if got := hashes(fingerprints(tokens)); !hasAny(got, hashes(fingerprints(shared))) {
+			t.Errorf("prefix %d: no shared fingerprint", prefix)
+		}
+	}
+}
+
+func hasAny(a, b map[int64]bool) bool {
+	for h := range b {
+		if a[h] {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFilterLines(t *testing.T) {
+	fps := []Fingerprint{{Hash: 1, StartLine: 1, EndLine: 3}, {Hash: 2, StartLine: 3, EndLine: 5}, {Hash: 3, StartLine: 5, EndLine: 8}}
+	if got, want := FilterLines(fps, 2, 6), []Fingerprint{{Hash: 2, StartLine: 3, EndLine: 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package clones
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/src-d/enry/v2"
+)
+
+// Normalized token texts. All identifiers (except keywords) and all literals are replaced by these,
+// so that copies whose variables were renamed or whose constants were changed tokenize the same.
+const (
+	identToken   = "$id"
+	literalToken = "$lit"
+)
+
+// A token is a normalized token of a source file.
+type token struct {
+	text string
+	line int // 1-based
+}
+
+// A language describes the lexical syntax of a programming language, as much as is needed to
+// tokenize it for clone detection.
+type language struct {
+	lineComments  []string    // line comment prefixes
+	blockComments [][2]string // block comment start and end delimiters
+	quotes        string      // string and character literal delimiters (which end at a newline)
+	rawQuotes     string      // raw string literal delimiters (which may span lines and have no escapes)
+	tripleQuotes  bool        // whether tripled quotes delimit multiline strings (as in Python)
+	keywords      map[string]bool
+}
+
+func keywords(s string) map[string]bool {
+	m := map[string]bool{}
+	for _, k := range strings.Fields(s) {
+		m[k] = true
+	}
+	return m
+}
+
+var (
+	cLineComments  = []string{"//"}
+	cBlockComments = [][2]string{{"/*", "*/"}}
+
+	cKeywords = "auto break case char const continue default do double else enum extern float for goto if int long register return short signed sizeof static struct switch typedef union unsigned void volatile while"
+
+	javaKeywords = "abstract assert boolean break byte case catch char class const continue default do double else enum extends final finally float for goto if implements import instanceof int interface long native new package private protected public return short static super switch synchronized this throw throws transient try void volatile while"
+
+	jsKeywords = "async await break case catch class const continue debugger default delete do else enum export extends finally for function if implements import in instanceof interface let new return super switch this throw try type typeof var void while with yield"
+)
+
+// languages are the languages that are tokenized for clone detection, by their enry name. Files in
+// other languages are not indexed.
+var languages = map[string]*language{
+	"C": {
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"'`,
+		keywords: keywords(cKeywords),
+	},
+	"C++": {
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"'`,
+		keywords: keywords(cKeywords + " bool catch class delete explicit friend inline namespace new operator private protected public template this throw try typename using virtual"),
+	},
+	"C#": {
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"'`,
+		keywords: keywords(javaKeywords + " as base bool foreach in internal is namespace object out override readonly ref sealed string struct using var virtual"),
+	},
+	"Go": {
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"'`, rawQuotes: "`",
+		keywords: keywords("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var"),
+	},
+	"Java": {
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"'`,
+		keywords: keywords(javaKeywords),
+	},
+	"JavaScript": {
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"'`, rawQuotes: "`",
+		keywords: keywords(jsKeywords),
+	},
+	"Python": {
+		lineComments: []string{"#"}, quotes: `"'`, tripleQuotes: true,
+		keywords: keywords("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield"),
+	},
+	"Ruby": {
+		lineComments: []string{"#"}, blockComments: [][2]string{{"=begin", "=end"}}, quotes: `"'`,
+		keywords: keywords("and begin break case class def do else elsif end ensure for if in module next not or redo rescue retry return self super then unless until when while yield"),
+	},
+	"Rust": {
+		// Single quotes are not quotes, because they also start lifetimes.
+		lineComments: cLineComments, blockComments: cBlockComments, quotes: `"`,
+		keywords: keywords("as break const continue crate else enum extern fn for if impl in let loop match mod move mut pub ref return self static struct super trait type unsafe use where while"),
+	},
+}
+
+func init() {
+	languages["TypeScript"] = languages["JavaScript"]
+	languages["TSX"] = languages["JavaScript"]
+}
+
+// languageOf returns the language of the file, or nil if it is not supported.
+func languageOf(path string) *language {
+	name, _ := enry.GetLanguageByExtension(path)
+	return languages[name]
+}
+
+// Supported reports whether files with the given path are tokenized for clone detection. Files in
+// unsupported languages and vendored files are not.
+func Supported(path string) bool {
+	return languageOf(path) != nil && !enry.IsVendor(path)
+}
+
+// tokenize returns the normalized tokens of a source file. Whitespace and comments are omitted,
+// identifiers and literals are normalized, and every other character that is not part of an
+// identifier, keyword or literal is a token of its own.
+func tokenize(lang *language, src []byte) []token {
+	var (
+		tokens []token
+		line   = 1
+		s      = string(src)
+		i      = 0
+	)
+	// skipTo advances i to the end of the first occurrence of delim at or after i (or to the end of
+	// the input), counting lines.
+	skipTo := func(delim string) {
+		end := strings.Index(s[i:], delim)
+		if end < 0 {
+			end = len(s) - i
+		} else {
+			end += len(delim)
+		}
+		line += strings.Count(s[i:i+end], "\n")
+		i += end
+	}
+
+next:
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		}
+
+		for _, prefix := range lang.lineComments {
+			if strings.HasPrefix(s[i:], prefix) {
+				if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+					i += end
+				} else {
+					i = len(s)
+				}
+				continue next
+			}
+		}
+		for _, delims := range lang.blockComments {
+			if strings.HasPrefix(s[i:], delims[0]) {
+				i += len(delims[0])
+				skipTo(delims[1])
+				continue next
+			}
+		}
+
+		startLine := line
+		switch {
+		case lang.tripleQuotes && (strings.HasPrefix(s[i:], `"""`) || strings.HasPrefix(s[i:], `'''`)):
+			delim := s[i : i+3]
+			i += 3
+			skipTo(delim)
+			tokens = append(tokens, token{text: literalToken, line: startLine})
+
+		case strings.IndexByte(lang.rawQuotes, c) >= 0:
+			i++
+			skipTo(string(c))
+			tokens = append(tokens, token{text: literalToken, line: startLine})
+
+		case strings.IndexByte(lang.quotes, c) >= 0:
+			for i++; i < len(s) && s[i] != c && s[i] != '\n'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && s[i+1] != '\n' {
+					i++
+				}
+			}
+			if i < len(s) && s[i] == c {
+				i++
+			}
+			tokens = append(tokens, token{text: literalToken, line: startLine})
+
+		case c >= '0' && c <= '9':
+			for i < len(s) && (isIdentByte(s[i]) || s[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{text: literalToken, line: startLine})
+
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == '_' || r == '$' || unicode.IsLetter(r) {
+				start := i
+				for i < len(s) {
+					r, size := utf8.DecodeRuneInString(s[i:])
+					if r != '_' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+						break
+					}
+					i += size
+				}
+				text := identToken
+				if word := s[start:i]; lang.keywords[word] {
+					text = word
+				}
+				tokens = append(tokens, token{text: text, line: startLine})
+			} else {
+				tokens = append(tokens, token{text: s[i : i+size], line: startLine})
+				i += size
+			}
+		}
+	}
+	return tokens
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -76,6 +77,14 @@ func GitoliteConfigs(ctx context.Context) ([]*schema.GitoliteConnection, error)
 	return config, nil
 }
 
+func SVNConfigs(ctx context.Context) ([]*schema.SubversionConnection, error) {
+	var config []*schema.SubversionConnection
+	if err := api.InternalClient.ExternalServiceConfigs(ctx, "SVN", &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 func PhabricatorConfigs(ctx context.Context) ([]*schema.PhabricatorConnection, error) {
 	var config []*schema.PhabricatorConnection
 	if err := api.InternalClient.ExternalServiceConfigs(ctx, "PHABRICATOR", &config); err != nil {
@@ -314,3 +323,24 @@ func AuthMinPasswordLength() int {
 	}
 	return val
 }
+
+// MaintenanceModeEnabled reports whether maintenance mode is enabled. While it is enabled, the site
+// is read-only: writes are rejected and background jobs that write to the database are paused.
+func MaintenanceModeEnabled() bool {
+	m := Get().MaintenanceMode
+	return m != nil && m.Enabled
+}
+
+// ErrMaintenanceMode returns the error for a write that is rejected because maintenance mode is
+// enabled, or nil if maintenance mode is disabled.
+func ErrMaintenanceMode() error {
+	m := Get().MaintenanceMode
+	if m == nil || !m.Enabled {
+		return nil
+	}
+	msg := "Sourcegraph is in maintenance mode and is read-only"
+	if m.Message != "" {
+		msg += ": " + m.Message
+	}
+	return errors.New(msg)
+}
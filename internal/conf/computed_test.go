@@ -113,3 +113,41 @@ func setenv(t *testing.T, keyval string) func() {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestErrMaintenanceMode(t *testing.T) {
+	defer Mock(nil)
+	tests := []struct {
+		name string
+		sc   *Unified
+		want string
+	}{{
+		name: "unset",
+		sc:   &Unified{},
+	}, {
+		name: "disabled",
+		sc:   &Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Message: "m"}}},
+	}, {
+		name: "enabled",
+		sc:   &Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: true}}},
+		want: "Sourcegraph is in maintenance mode and is read-only",
+	}, {
+		name: "enabled with message",
+		sc:   &Unified{SiteConfiguration: schema.SiteConfiguration{MaintenanceMode: &schema.MaintenanceMode{Enabled: true, Message: "back at 18:00 UTC"}}},
+		want: "Sourcegraph is in maintenance mode and is read-only: back at 18:00 UTC",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Mock(test.sc)
+			var got string
+			if err := ErrMaintenanceMode(); err != nil {
+				got = err.Error()
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+			if enabled := MaintenanceModeEnabled(); enabled != (test.want != "") {
+				t.Errorf("got MaintenanceModeEnabled %v", enabled)
+			}
+		})
+	}
+}
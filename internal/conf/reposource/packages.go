@@ -0,0 +1,20 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// PackageRepoName returns the Sourcegraph name for a repository mirrored from the named package of
+// a package registry, according to the repositoryPathPattern of the registry's external service
+// configuration (or defaultPattern, if it has none). The colon of Maven artifact names
+// ("group:artifact") is replaced with a slash.
+func PackageRepoName(repositoryPathPattern, defaultPattern, name string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = defaultPattern
+	}
+	return api.RepoName(strings.NewReplacer(
+		"{package}", strings.Replace(name, ":", "/", -1),
+	).Replace(repositoryPathPattern))
+}
@@ -0,0 +1,19 @@
+package reposource
+
+import "testing"
+
+func TestPackageRepoName(t *testing.T) {
+	tests := []struct {
+		pattern, defaultPattern, name string
+		want                          string
+	}{
+		{"", "npm/{package}", "@babel/core", "npm/@babel/core"},
+		{"", "maven/{package}", "org.apache.commons:commons-lang3", "maven/org.apache.commons/commons-lang3"},
+		{"deps/go/{package}", "go/{package}", "golang.org/x/net", "deps/go/golang.org/x/net"},
+	}
+	for _, test := range tests {
+		if got := PackageRepoName(test.pattern, test.defaultPattern, test.name); string(got) != test.want {
+			t.Errorf("PackageRepoName(%q, %q, %q): got %q, want %q", test.pattern, test.defaultPattern, test.name, got, test.want)
+		}
+	}
+}
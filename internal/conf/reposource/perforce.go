@@ -0,0 +1,20 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// PerforceRepoName returns the Sourcegraph name for a repository mirrored from a Perforce depot
+// path (e.g., "//depot/engine/") on the given host, according to the repositoryPathPattern of
+// the Perforce external service configuration.
+func PerforceRepoName(repositoryPathPattern, host, depotPath string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{depot}"
+	}
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{depot}", strings.Trim(depotPath, "/"),
+	).Replace(repositoryPathPattern))
+}
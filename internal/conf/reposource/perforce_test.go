@@ -0,0 +1,19 @@
+package reposource
+
+import "testing"
+
+func TestPerforceRepoName(t *testing.T) {
+	tests := []struct {
+		pattern, host, depotPath string
+		want                     string
+	}{
+		{"", "perforce.example.com", "//depot/engine/", "perforce.example.com/depot/engine"},
+		{"p4/{depot}", "perforce.example.com", "//streams/main/", "p4/streams/main"},
+		{"{host}/mirror/{depot}", "localhost", "//depot/", "localhost/mirror/depot"},
+	}
+	for _, test := range tests {
+		if got := PerforceRepoName(test.pattern, test.host, test.depotPath); string(got) != test.want {
+			t.Errorf("PerforceRepoName(%q, %q, %q): got %q, want %q", test.pattern, test.host, test.depotPath, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// SVNRepoName returns the Sourcegraph name for a repository mirrored from the Subversion
+// repository with the given name on the given host, according to the repositoryPathPattern of
+// the Subversion external service configuration.
+func SVNRepoName(repositoryPathPattern, host, name string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{repo}"
+	}
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{repo}", strings.Trim(name, "/"),
+	).Replace(repositoryPathPattern))
+}
@@ -0,0 +1,19 @@
+package reposource
+
+import "testing"
+
+func TestSVNRepoName(t *testing.T) {
+	tests := []struct {
+		pattern, host, name string
+		want                string
+	}{
+		{"", "svn.example.com", "engine", "svn.example.com/engine"},
+		{"svn/{repo}", "svn.example.com", "tools", "svn/tools"},
+		{"{host}/legacy/{repo}", "", "engine", "/legacy/engine"},
+	}
+	for _, test := range tests {
+		if got := SVNRepoName(test.pattern, test.host, test.name); string(got) != test.want {
+			t.Errorf("SVNRepoName(%q, %q, %q): got %q, want %q", test.pattern, test.host, test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// parseCobertura parses a Cobertura XML report (as produced by coverage.py, gcovr, JaCoCo converters,
+// istanbul/nyc, and others). The report is decoded as a stream, because reports of large
+// repositories can be large.
+//
+// The filename of each class is relative to one of the report's sources, which are usually absolute
+// paths on the machine that produced the report. The sources are ignored, so the filenames are
+// assumed to be relative to the root directory of the upload.
+func parseCobertura(r io.Reader) ([]*File, error) {
+	var (
+		b     builder
+		file  string
+		found bool
+	)
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "coverage":
+				found = true
+			case "class":
+				file = path.Clean(attr(el, "filename"))
+				if file == "." {
+					return nil, errors.New("class without a filename")
+				}
+			case "line":
+				if file == "" {
+					continue
+				}
+				number, err := strconv.Atoi(attr(el, "number"))
+				if err != nil {
+					return nil, errors.Wrapf(err, "line of %q", file)
+				}
+				hits, err := strconv.ParseFloat(attr(el, "hits"), 64)
+				if err != nil {
+					return nil, errors.Wrapf(err, "line %d of %q", number, file)
+				}
+				if err := b.add(file, number, int(hits)); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if el.Name.Local == "class" {
+				file = ""
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("no <coverage> element")
+	}
+	return b.result(), nil
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+// Package coverage parses code coverage reports and encodes per-line coverage data.
+package coverage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Format is a code coverage report format.
+type Format string
+
+const (
+	LCOV      Format = "lcov"
+	Cobertura Format = "cobertura"
+	GoProfile Format = "go"
+)
+
+// maxLineNumber is the maximum line number of a file in a report.
+const maxLineNumber = 1 << 24
+
+// Formats is the list of supported report formats.
+var Formats = []Format{LCOV, Cobertura, GoProfile}
+
+// A Line is the coverage of a single line of a file.
+type Line struct {
+	Number int // 1-based
+	Hits   int // the number of times the line was executed
+}
+
+// A File is the coverage of the instrumented lines of a file. Lines that are not instrumented (such
+// as comments) are omitted.
+type File struct {
+	Path  string // relative to the repository root
+	Lines []Line // sorted by line number, without duplicates
+}
+
+// LinesHit returns the number of lines of the file that were executed at least once.
+func (f *File) LinesHit() int {
+	n := 0
+	for _, l := range f.Lines {
+		if l.Hits > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Parse parses a code coverage report in the given format.
+func Parse(format Format, r io.Reader) ([]*File, error) {
+	var (
+		files []*File
+		err   error
+	)
+	switch format {
+	case LCOV:
+		files, err = parseLCOV(r)
+	case Cobertura:
+		files, err = parseCobertura(r)
+	case GoProfile:
+		files, err = parseGoProfile(r)
+	default:
+		return nil, fmt.Errorf("unsupported coverage report format %q (supported formats are %q)", format, Formats)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s coverage report", format)
+	}
+	return files, nil
+}
+
+// builder accumulates the coverage of files. Reports may list a file (or a line) more than once,
+// e.g. once per test binary, in which case the hits are added up.
+type builder struct {
+	files map[string]map[int]int
+}
+
+func (b *builder) add(file string, line, hits int) error {
+	if line < 1 || line > maxLineNumber {
+		return fmt.Errorf("invalid line number %d of file %q", line, file)
+	}
+	if hits < 0 {
+		return fmt.Errorf("invalid hit count %d of line %d of file %q", hits, line, file)
+	}
+	if b.files == nil {
+		b.files = map[string]map[int]int{}
+	}
+	lines, ok := b.files[file]
+	if !ok {
+		lines = map[int]int{}
+		b.files[file] = lines
+	}
+	lines[line] += hits
+	return nil
+}
+
+func (b *builder) result() []*File {
+	files := make([]*File, 0, len(b.files))
+	for p, lines := range b.files {
+		f := &File{Path: p, Lines: make([]Line, 0, len(lines))}
+		for number, hits := range lines {
+			f.Lines = append(f.Lines, Line{Number: number, Hits: hits})
+		}
+		sort.Slice(f.Lines, func(i, j int) bool { return f.Lines[i].Number < f.Lines[j].Number })
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// NormalizePaths rewrites the paths of the files in a report to be relative to the repository root.
+// The prefix (if any) is removed from each path, and then the path is joined to root, which is the
+// directory (relative to the repository root) that the report's paths are relative to. Files whose
+// paths lie outside of the repository are omitted.
+func NormalizePaths(files []*File, root, prefix string) []*File {
+	prefix = strings.TrimSuffix(prefix, "/")
+	normalized := files[:0]
+	for _, f := range files {
+		p := f.Path
+		if prefix != "" && strings.HasPrefix(p, prefix+"/") {
+			p = strings.TrimPrefix(p, prefix+"/")
+		} else if path.IsAbs(p) {
+			// Absolute paths are machine-specific (e.g., /home/ci/src/...), so there's no way to
+			// know which part of them is inside the repository.
+			continue
+		}
+		p = path.Join(strings.Trim(root, "/"), p)
+		if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+			continue
+		}
+		f.Path = p
+		normalized = append(normalized, f)
+	}
+	return normalized
+}
+
+// EncodeLines encodes lines (which must be sorted by line number) compactly. Each line is encoded as
+// the difference between its line number and the previous line number, followed by its hit count,
+// both as unsigned varints.
+func EncodeLines(lines []Line) []byte {
+	buf := make([]byte, 0, 2*len(lines))
+	var tmp [binary.MaxVarintLen64]byte
+	prev := 0
+	for _, l := range lines {
+		n := binary.PutUvarint(tmp[:], uint64(l.Number-prev))
+		buf = append(buf, tmp[:n]...)
+		n = binary.PutUvarint(tmp[:], uint64(l.Hits))
+		buf = append(buf, tmp[:n]...)
+		prev = l.Number
+	}
+	return buf
+}
+
+// DecodeLines decodes lines encoded by EncodeLines.
+func DecodeLines(b []byte) ([]Line, error) {
+	var lines []Line
+	prev := 0
+	for len(b) > 0 {
+		delta, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid encoded coverage line number")
+		}
+		b = b[n:]
+		hits, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid encoded coverage hit count")
+		}
+		b = b[n:]
+		prev += int(delta)
+		lines = append(lines, Line{Number: prev, Hits: int(hits)})
+	}
+	return lines, nil
+}
@@ -0,0 +1,114 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		format Format
+		report string
+		want   []*File
+	}{
+		"lcov": {
+			format: LCOV,
+			report: `TN:
+SF:src/a.ts
+FN:1,f
+DA:1,3
+DA:2,0
+DA:4,1,abc
+end_of_record
+SF:src/b.ts
+DA:7,2
+end_of_record
+SF:src/a.ts
+DA:2,1
+end_of_record
+`,
+			want: []*File{
+				{Path: "src/a.ts", Lines: []Line{{1, 3}, {2, 1}, {4, 1}}},
+				{Path: "src/b.ts", Lines: []Line{{7, 2}}},
+			},
+		},
+		"cobertura": {
+			format: Cobertura,
+			report: `<?xml version="1.0" ?>
+<coverage line-rate="0.5" version="5.0">
+	<sources><source>/home/ci/project</source></sources>
+	<packages><package name="pkg"><classes>
+		<class filename="pkg/a.py" name="a.py">
+			<methods/>
+			<lines><line hits="1" number="1"/><line hits="0" number="3"/></lines>
+		</class>
+	</classes></package></packages>
+</coverage>`,
+			want: []*File{{Path: "pkg/a.py", Lines: []Line{{1, 1}, {3, 0}}}},
+		},
+		"go": {
+			format: GoProfile,
+			report: `mode: count
+github.com/acme/a/b.go:3.13,5.2 1 4
+github.com/acme/a/b.go:5.2,6.3 1 0
+github.com/acme/a/b.go:3.13,5.2 1 1
+`,
+			want: []*File{{Path: "github.com/acme/a/b.go", Lines: []Line{{3, 5}, {4, 5}, {5, 5}, {6, 0}}}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			files, err := Parse(test.format, strings.NewReader(test.report))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(files, test.want) {
+				t.Errorf("got %+v, want %+v", files, test.want)
+			}
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for format, report := range map[Format]string{
+		LCOV:      "DA:1,1\n",
+		Cobertura: "<html></html>",
+		GoProfile: "github.com/acme/a/b.go:3.13,5.2 1 4\n",
+		"jacoco":  "",
+	} {
+		if _, err := Parse(format, strings.NewReader(report)); err == nil {
+			t.Errorf("%s: got no error", format)
+		}
+	}
+}
+
+func TestNormalizePaths(t *testing.T) {
+	files := []*File{
+		{Path: "github.com/acme/a/b.go"},
+		{Path: "./src/c.ts"},
+		{Path: "/home/ci/d.py"},
+		{Path: "../e.py"},
+	}
+	var got []string
+	for _, f := range NormalizePaths(files, "web", "github.com/acme/a") {
+		got = append(got, f.Path)
+	}
+	if want := []string{"web/b.go", "web/src/c.ts", "e.py"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEncodeLines(t *testing.T) {
+	lines := []Line{{1, 0}, {2, 5}, {300, 1000000}}
+	got, err := DecodeLines(EncodeLines(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, lines) {
+		t.Errorf("got %+v, want %+v", got, lines)
+	}
+	if _, err := DecodeLines([]byte{0x80}); err == nil {
+		t.Error("got no error decoding truncated data")
+	}
+}
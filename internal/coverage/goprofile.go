@@ -0,0 +1,83 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// parseGoProfile parses a Go coverage profile (as produced by `go test -coverprofile`). Each line
+// after the "mode:" line describes a block of statements:
+//
+//	<file>:<startLine>.<startCol>,<endLine>.<endCol> <numStatements> <count>
+//
+// The files are identified by import path (e.g., github.com/foo/bar/baz.go), so the caller must
+// remove the module path to obtain paths relative to the repository root (see NormalizePaths).
+//
+// A line's hit count is the maximum count of the blocks that span it. Profiles that were merged from
+// several test runs may list a block more than once, in which case its counts are added up.
+func parseGoProfile(r io.Reader) ([]*File, error) {
+	type block struct {
+		file                                 string
+		startLine, startCol, endLine, endCol int
+	}
+	var (
+		counts = map[block]int{}
+		order  []block
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if lineNumber == 1 {
+			if !strings.HasPrefix(line, "mode: ") {
+				return nil, fmt.Errorf(`line 1: expected "mode: " line, got %q`, line)
+			}
+			continue
+		}
+		i := strings.LastIndex(line, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: invalid block %q", lineNumber, line)
+		}
+		blk := block{file: line[:i]}
+		var numStmts, count int
+		if _, err := fmt.Sscanf(line[i+1:], "%d.%d,%d.%d %d %d", &blk.startLine, &blk.startCol, &blk.endLine, &blk.endCol, &numStmts, &count); err != nil {
+			return nil, fmt.Errorf("line %d: invalid block %q: %s", lineNumber, line, err)
+		}
+		if blk.startLine < 1 || blk.endLine < blk.startLine || blk.endLine > maxLineNumber || count < 0 {
+			return nil, fmt.Errorf("line %d: invalid block %q", lineNumber, line)
+		}
+		if _, seen := counts[blk]; !seen {
+			order = append(order, blk)
+		}
+		counts[blk] += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	lines := map[string]map[int]int{}
+	for _, blk := range order {
+		if lines[blk.file] == nil {
+			lines[blk.file] = map[int]int{}
+		}
+		for l := blk.startLine; l <= blk.endLine; l++ {
+			if c, ok := lines[blk.file][l]; !ok || counts[blk] > c {
+				lines[blk.file][l] = counts[blk]
+			}
+		}
+	}
+	var b builder
+	for file, fileLines := range lines {
+		for l, hits := range fileLines {
+			if err := b.add(file, l, hits); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.result(), nil
+}
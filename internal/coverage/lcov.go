@@ -0,0 +1,54 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// parseLCOV parses an LCOV tracefile (as produced by lcov, istanbul/nyc, c8, grcov, and others). Only
+// the line coverage records are used: "SF:<path>" starts a file's section, "DA:<line>,<hits>[,<checksum>]"
+// records the hits of a line, and "end_of_record" ends the section.
+func parseLCOV(r io.Reader) ([]*File, error) {
+	var b builder
+	var file string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = strings.TrimPrefix(line, "SF:")
+		case line == "end_of_record":
+			file = ""
+		case strings.HasPrefix(line, "DA:"):
+			if file == "" {
+				return nil, fmt.Errorf("line %d: DA record outside of a file section", lineNumber)
+			}
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: invalid DA record %q", lineNumber, line)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+			// Some tools emit hit counts in floating point notation (e.g., "1e+06").
+			hits, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+			if err := b.add(file, number, int(hits)); err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b.result(), nil
+}
@@ -144,6 +144,8 @@ type hook struct{}
 
 // Before implements sqlhooks.Hooks
 func (h *hook) Before(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
+	ctx = context.WithValue(ctx, queryStartKey{}, time.Now())
+
 	parent := opentracing.SpanFromContext(ctx)
 	if parent == nil {
 		return ctx, nil
@@ -162,6 +164,8 @@ func (h *hook) Before(ctx context.Context, query string, args ...interface{}) (c
 
 // After implements sqlhooks.Hooks
 func (h *hook) After(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
+	observeQuery(ctx, query, nil)
+
 	span := opentracing.SpanFromContext(ctx)
 	if span != nil {
 		span.Finish()
@@ -171,6 +175,8 @@ func (h *hook) After(ctx context.Context, query string, args ...interface{}) (co
 
 // After implements sqlhooks.OnErroer
 func (h *hook) OnError(ctx context.Context, err error, query string, args ...interface{}) error {
+	observeQuery(ctx, query, err)
+
 	span := opentracing.SpanFromContext(ctx)
 	if span != nil {
 		ext.Error.Set(span, true)
@@ -0,0 +1,179 @@
+package dbconn
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+var slowQueryThreshold = func() time.Duration {
+	str := env.Get("SRC_PGSQL_SLOW_QUERY_THRESHOLD", "1s", "log database queries that take longer than this (0 disables the slow query log)")
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		log.Fatalln("SRC_PGSQL_SLOW_QUERY_THRESHOLD:", err)
+	}
+	return d
+}()
+
+var queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "src",
+	Subsystem: "pgsql_app",
+	Name:      "query_duration_seconds",
+	Help:      "Time spent running queries against the pgsql DB, by the store and method that ran them.",
+	Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+}, []string{"store", "method", "error"})
+
+func init() {
+	prometheus.MustRegister(queryDuration)
+}
+
+type queryLabelsKey struct{}
+
+type queryLabels struct{ store, method string }
+
+// WithQueryLabels returns a copy of ctx that labels the queries run with it with the store and
+// method that run them, in the query metrics and the slow query log. Each store method sets its
+// labels before it runs queries, e.g.:
+//
+//	ctx = dbconn.WithQueryLabels(ctx, "Users", "GetByID")
+func WithQueryLabels(ctx context.Context, store, method string) context.Context {
+	return context.WithValue(ctx, queryLabelsKey{}, queryLabels{store: store, method: method})
+}
+
+func queryLabelsFromContext(ctx context.Context) queryLabels {
+	if l, ok := ctx.Value(queryLabelsKey{}).(queryLabels); ok {
+		return l
+	}
+	return queryLabels{store: "unknown", method: "unknown"}
+}
+
+type queryStartKey struct{}
+
+// observeQuery records the duration of a query that was started with the context returned by the
+// hook's Before method.
+func observeQuery(ctx context.Context, query string, err error) {
+	start, ok := ctx.Value(queryStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	d := time.Since(start)
+	l := queryLabelsFromContext(ctx)
+	errLabel := "false"
+	if err != nil {
+		errLabel = "true"
+	}
+	queryDuration.WithLabelValues(l.store, l.method, errLabel).Observe(d.Seconds())
+
+	if slowQueryThreshold > 0 && d >= slowQueryThreshold {
+		sanitized := sanitizeQuery(query)
+		log15.Warn("Slow database query.", "store", l.store, "method", l.method, "duration", d, "query", sanitized)
+		slowQueries.add(l, sanitized, d, time.Now())
+	}
+}
+
+// maxSanitizedQueryLength is the maximum length of a query in the slow query log.
+const maxSanitizedQueryLength = 2000
+
+var (
+	stringLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+	whitespacePattern    = regexp.MustCompile(`\s+`)
+)
+
+// sanitizeQuery returns the query for the slow query log. String literals are replaced, because
+// they may contain user data (the query's arguments are never logged), and whitespace is collapsed
+// so that the same query from different call sites is logged the same way.
+func sanitizeQuery(query string) string {
+	query = stringLiteralPattern.ReplaceAllString(query, "'?'")
+	query = strings.TrimSpace(whitespacePattern.ReplaceAllString(query, " "))
+	if len(query) > maxSanitizedQueryLength {
+		query = query[:maxSanitizedQueryLength] + "..."
+	}
+	return query
+}
+
+// SlowQuery describes a query that was slower than the slow query threshold (one or more times)
+// since the process started.
+type SlowQuery struct {
+	Store, Method string
+	Query         string // the sanitized query
+	Count         int
+	Total, Max    time.Duration
+	LastSeen      time.Time
+}
+
+// maxSlowQueries is the maximum number of distinct slow queries that are kept. When it's reached,
+// the slow query with the lowest maximum duration is dropped to make room for a new one.
+const maxSlowQueries = 1000
+
+type slowQueryLog struct {
+	mu      sync.Mutex
+	queries map[slowQueryKey]*SlowQuery
+}
+
+type slowQueryKey struct{ store, method, query string }
+
+var slowQueries = &slowQueryLog{}
+
+func (l *slowQueryLog) add(labels queryLabels, query string, d time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k := slowQueryKey{store: labels.store, method: labels.method, query: query}
+	q, ok := l.queries[k]
+	if !ok {
+		if l.queries == nil {
+			l.queries = map[slowQueryKey]*SlowQuery{}
+		}
+		if len(l.queries) >= maxSlowQueries {
+			var minKey slowQueryKey
+			var min *SlowQuery
+			for k, q := range l.queries {
+				if min == nil || q.Max < min.Max {
+					minKey, min = k, q
+				}
+			}
+			if d < min.Max {
+				return
+			}
+			delete(l.queries, minKey)
+		}
+		q = &SlowQuery{Store: labels.store, Method: labels.method, Query: query}
+		l.queries[k] = q
+	}
+	q.Count++
+	q.Total += d
+	if d > q.Max {
+		q.Max = d
+	}
+	q.LastSeen = now
+}
+
+func (l *slowQueryLog) top(n int) []SlowQuery {
+	l.mu.Lock()
+	queries := make([]SlowQuery, 0, len(l.queries))
+	for _, q := range l.queries {
+		queries = append(queries, *q)
+	}
+	l.mu.Unlock()
+
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Max > queries[j].Max })
+	if n >= 0 && len(queries) > n {
+		queries = queries[:n]
+	}
+	return queries
+}
+
+// SlowQueries returns the (at most n) slowest queries that this process ran since it started, by
+// their maximum duration. Only queries that were slower than the slow query threshold
+// (SRC_PGSQL_SLOW_QUERY_THRESHOLD) are recorded.
+func SlowQueries(n int) []SlowQuery {
+	return slowQueries.top(n)
+}
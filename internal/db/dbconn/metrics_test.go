@@ -0,0 +1,60 @@
+package dbconn
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSanitizeQuery(t *testing.T) {
+	tests := map[string]string{
+		"SELECT id FROM users WHERE id=$1":                            "SELECT id FROM users WHERE id=$1",
+		"SELECT id\n\tFROM users\n\tWHERE username='alice'  AND x=$1": "SELECT id FROM users WHERE username='?' AND x=$1",
+		"SELECT 'it''s', 'a'":                                         "SELECT '?', '?'",
+	}
+	for query, want := range tests {
+		if got := sanitizeQuery(query); got != want {
+			t.Errorf("sanitizeQuery(%q): got %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	l := &slowQueryLog{}
+	now := time.Now()
+	users := queryLabels{store: "Users", method: "GetByID"}
+	repos := queryLabels{store: "Repos", method: "List"}
+	l.add(users, "q1", 2*time.Second, now)
+	l.add(users, "q1", 4*time.Second, now.Add(time.Minute))
+	l.add(repos, "q2", 3*time.Second, now)
+
+	top := l.top(10)
+	if len(top) != 2 {
+		t.Fatalf("got %d slow queries, want 2", len(top))
+	}
+	want := SlowQuery{Store: "Users", Method: "GetByID", Query: "q1", Count: 2, Total: 6 * time.Second, Max: 4 * time.Second, LastSeen: now.Add(time.Minute)}
+	if top[0] != want {
+		t.Errorf("got %+v, want %+v", top[0], want)
+	}
+	if top[1].Query != "q2" {
+		t.Errorf("got second slowest query %q, want q2", top[1].Query)
+	}
+	if top := l.top(1); len(top) != 1 || top[0].Query != "q1" {
+		t.Errorf("got top 1 %+v", top)
+	}
+
+	// When the log is full, the query with the lowest maximum duration is dropped.
+	l = &slowQueryLog{}
+	for i := 0; i < maxSlowQueries; i++ {
+		l.add(users, fmt.Sprintf("q%d", i), time.Duration(i+1)*time.Second, now)
+	}
+	l.add(users, "faster", time.Millisecond, now)
+	l.add(users, "slower", time.Hour, now)
+	top = l.top(-1)
+	if len(top) != maxSlowQueries {
+		t.Fatalf("got %d slow queries, want %d", len(top), maxSlowQueries)
+	}
+	if top[0].Query != "slower" || top[len(top)-1].Query != "q1" {
+		t.Errorf("got slowest %q and fastest %q, want slower and q1", top[0].Query, top[len(top)-1].Query)
+	}
+}
@@ -625,6 +625,20 @@ func (c *Client) LoadPullRequestBuildStatuses(ctx context.Context, pr *PullReque
 	return nil
 }
 
+// MergedPullRequests returns a page of the merged pull requests of the given repository, most
+// recently updated first.
+func (c *Client) MergedPullRequests(ctx context.Context, projectKey, repoSlug string, pageToken *PageToken) ([]*MergedPullRequest, *PageToken, error) {
+	path := fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s/pull-requests", projectKey, repoSlug)
+	qry := url.Values{
+		"state": []string{"MERGED"},
+		"order": []string{"NEWEST"},
+	}
+
+	var prs []*MergedPullRequest
+	next, err := c.page(ctx, path, qry, pageToken, &prs)
+	return prs, next, err
+}
+
 func (c *Client) Repo(ctx context.Context, projectKey, repoSlug string) (*Repo, error) {
 	u := fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s", projectKey, repoSlug)
 	req, err := http.NewRequest("GET", u, nil)
@@ -1061,6 +1075,16 @@ type PullRequest struct {
 	BuildStatuses []BuildStatus `json:"buildstatuses,omitempty"`
 }
 
+// A MergedPullRequest is a merged PullRequest along with the commit that merged it.
+type MergedPullRequest struct {
+	PullRequest
+	ClosedDate int `json:"closedDate"`
+	Properties struct {
+		// MergeCommit is not set by older versions of Bitbucket Server.
+		MergeCommit *Commit `json:"mergeCommit,omitempty"`
+	} `json:"properties"`
+}
+
 // Activity is a union type of all supported pull request activity items.
 type Activity struct {
 	ID          int            `json:"id"`
@@ -712,6 +712,149 @@ func (c *Client) GetOpenPullRequestByRefs(ctx context.Context, owner, name, base
 	return &pr, nil
 }
 
+// A MergedPullRequest is a merged pull request along with the commits that it introduced.
+type MergedPullRequest struct {
+	Number    int64
+	Title     string
+	URL       string
+	MergedAt  time.Time
+	UpdatedAt time.Time
+	// MergeCommitOID is the OID of the commit that merged the pull request (which may be a squash
+	// or rebase commit).
+	MergeCommitOID string
+	// HeadCommitOIDs are the OIDs of the first 250 commits of the pull request's head branch.
+	HeadCommitOIDs []string
+	// Reviewers are the users (other than the author) who submitted the first 100 reviews of the
+	// pull request.
+	Reviewers []*Reviewer
+}
+
+// A Reviewer is a user who reviewed a pull request.
+type Reviewer struct {
+	DatabaseID int64 // the ID of the user in the REST API
+	Login      string
+}
+
+// ListMergedPullRequests returns the merged pull requests of the repository that were updated at or
+// after the given time (or all of them if it is zero), most recently updated first.
+func (c *Client) ListMergedPullRequests(ctx context.Context, owner, name string, since time.Time) ([]*MergedPullRequest, error) {
+	const pageSize = 50
+
+	var prs []*MergedPullRequest
+	vars := map[string]interface{}{"owner": owner, "name": name, "first": pageSize}
+	for {
+		var result struct {
+			Repository struct {
+				PullRequests struct {
+					Nodes []struct {
+						Number      int64
+						Title       string
+						URL         string
+						MergedAt    time.Time
+						UpdatedAt   time.Time
+						MergeCommit *struct{ OID string }
+						Commits     struct {
+							Nodes []struct{ Commit struct{ OID string } }
+						}
+						Author  *struct{ Login string }
+						Reviews struct {
+							Nodes []struct {
+								// Author is nil for deleted users, and its DatabaseID is
+								// zero for bots.
+								Author *Reviewer
+							}
+						}
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				}
+			}
+		}
+		if err := c.requestGraphQL(ctx, "", mergedPullRequestsQuery, vars, &result); err != nil {
+			return nil, err
+		}
+
+		page := result.Repository.PullRequests
+		for _, n := range page.Nodes {
+			if n.UpdatedAt.Before(since) {
+				return prs, nil
+			}
+			pr := &MergedPullRequest{
+				Number:    n.Number,
+				Title:     n.Title,
+				URL:       n.URL,
+				MergedAt:  n.MergedAt,
+				UpdatedAt: n.UpdatedAt,
+			}
+			if n.MergeCommit != nil {
+				pr.MergeCommitOID = n.MergeCommit.OID
+			}
+			for _, commit := range n.Commits.Nodes {
+				pr.HeadCommitOIDs = append(pr.HeadCommitOIDs, commit.Commit.OID)
+			}
+			seen := map[int64]bool{}
+			for _, review := range n.Reviews.Nodes {
+				r := review.Author
+				if r == nil || r.DatabaseID == 0 || seen[r.DatabaseID] || (n.Author != nil && r.Login == n.Author.Login) {
+					continue
+				}
+				seen[r.DatabaseID] = true
+				pr.Reviewers = append(pr.Reviewers, r)
+			}
+			prs = append(prs, pr)
+		}
+		if !page.PageInfo.HasNextPage {
+			return prs, nil
+		}
+		vars["after"] = page.PageInfo.EndCursor
+	}
+}
+
+const mergedPullRequestsQuery = `
+query MergedPullRequests($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: MERGED, first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes {
+        number
+        title
+        url
+        mergedAt
+        updatedAt
+        mergeCommit {
+          oid
+        }
+        commits(first: 250) {
+          nodes {
+            commit {
+              oid
+            }
+          }
+        }
+        author {
+          login
+        }
+        reviews(first: 100) {
+          nodes {
+            author {
+              login
+              ... on User {
+                databaseId
+              }
+            }
+          }
+        }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}
+`
+
 // This fragment was formatted using the "prettify" button in the GitHub API explorer:
 // https://developer.github.com/v4/explorer/
 const pullRequestFragments = `
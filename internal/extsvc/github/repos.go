@@ -35,6 +35,15 @@ type Repository struct {
 	IsFork           bool   // whether the repository is a fork of another repository
 	IsArchived       bool   // whether the repository is archived on the code host
 	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+
+	// Parent is the repository that this repository was forked from, or nil if it is not a fork
+	// (or if its parent is not visible to the client).
+	Parent *ParentRepository `json:",omitempty"`
+}
+
+// ParentRepository is the repository that a GitHub repository was forked from.
+type ParentRepository struct {
+	NameWithOwner string // full name of repository ("owner/name")
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -52,6 +61,9 @@ fragment RepositoryFields on Repository {
 	isFork
 	isArchived
 	viewerPermission
+	parent {
+		nameWithOwner
+	}
 }
 	`
 	}
@@ -68,6 +80,9 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	parent {
+		nameWithOwner
+	}
 }
 	`
 }
@@ -252,6 +267,9 @@ type restRepository struct {
 	Fork        bool
 	Archived    bool
 	Permissions restRepositoryPermissions `json:"permissions"`
+	Parent      *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"` // only returned when getting a single repository
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -273,6 +291,10 @@ func (c *Client) getRepositoryFromAPI(ctx context.Context, owner, name string) (
 // convertRestRepo converts repo information returned by the rest API
 // to a standard format.
 func convertRestRepo(restRepo restRepository) *Repository {
+	var parent *ParentRepository
+	if restRepo.Parent != nil {
+		parent = &ParentRepository{NameWithOwner: restRepo.Parent.FullName}
+	}
 	return &Repository{
 		ID:               restRepo.ID,
 		DatabaseID:       restRepo.DatabaseID,
@@ -283,6 +305,7 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsFork:           restRepo.Fork,
 		IsArchived:       restRepo.Archived,
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
+		Parent:           parent,
 	}
 }
 
@@ -0,0 +1,70 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/peterhellberg/link"
+)
+
+// MergeRequest is a GitLab merge request (equivalent to a GitHub pull request).
+type MergeRequest struct {
+	IID            int        `json:"iid"`              // ID of the merge request within its project
+	Title          string     `json:"title"`            // title of the merge request
+	WebURL         string     `json:"web_url"`          // the web URL of this merge request
+	MergeCommitSHA string     `json:"merge_commit_sha"` // the merge commit, or empty if the merge request was fast-forwarded
+	MergedAt       *time.Time `json:"merged_at"`        // when the merge request was merged, if it was merged
+	UpdatedAt      time.Time  `json:"updated_at"`       // when the merge request was last updated
+	Author         *User      `json:"author"`           // the author of the merge request
+	Reviewers      []*User    `json:"reviewers"`        // the reviewers of the merge request (only reported by GitLab 13.8 and later)
+}
+
+// Commit is a commit of a GitLab merge request.
+type Commit struct {
+	ID string `json:"id"`
+}
+
+// ListMergeRequests lists GitLab merge requests. The URL is typically of the form
+// "projects/:id/merge_requests?state=merged".
+func (c *Client) ListMergeRequests(ctx context.Context, urlStr string) (mrs []*MergeRequest, nextPageURL *string, err error) {
+	req, err := http.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	respHeader, err := c.do(ctx, req, &mrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get URL to next page. See https://docs.gitlab.com/ee/api/README.html#pagination-link-header.
+	if l := link.Parse(respHeader.Get("Link"))["next"]; l != nil {
+		nextPageURL = &l.URI
+	}
+	return mrs, nextPageURL, nil
+}
+
+// ListMergeRequestCommits lists the commits of a GitLab merge request.
+func (c *Client) ListMergeRequestCommits(ctx context.Context, projectID, iid int) ([]*Commit, error) {
+	var commits []*Commit
+	urlStr := fmt.Sprintf("projects/%d/merge_requests/%d/commits?per_page=100", projectID, iid)
+	for {
+		req, err := http.NewRequest("GET", urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []*Commit
+		respHeader, err := c.do(ctx, req, &page)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, page...)
+
+		l := link.Parse(respHeader.Get("Link"))["next"]
+		if l == nil {
+			return commits, nil
+		}
+		urlStr = l.URI
+	}
+}
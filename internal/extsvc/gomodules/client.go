@@ -0,0 +1,154 @@
+package gomodules
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// ServiceType is the (api.ExternalRepoSpec).ServiceType value for Go modules. The ServiceID value
+// is the URL of the module proxy (e.g., "https://proxy.golang.org/").
+const ServiceType = "gomodules"
+
+// DefaultRegistryURL is the URL of the public Go module proxy.
+const DefaultRegistryURL = "https://proxy.golang.org/"
+
+// Client is a client for a Go module proxy. It implements pkgs.Registry. Packages are named by
+// their module path.
+type Client struct {
+	proxy *url.URL
+	doer  httpcli.Doer
+}
+
+var _ pkgs.Registry = &Client{}
+
+// NewClient returns a client for the Go module proxy at proxyURL. If doer is nil,
+// http.DefaultClient is used.
+func NewClient(proxyURL string, doer httpcli.Doer) (*Client, error) {
+	u, err := url.Parse(pkgs.ServiceID(proxyURL))
+	if err != nil {
+		return nil, err
+	}
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	return &Client{proxy: u, doer: doer}, nil
+}
+
+// Versions returns the published versions of the module with the given path, in semantic version
+// order. The module proxy protocol does not list pseudo-versions, and the time of each version
+// isn't listed either.
+func (c *Client) Versions(ctx context.Context, module string) ([]pkgs.Version, error) {
+	escaped, err := escapePath(module)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.get(ctx, escaped+"/@v/list")
+	if err != nil {
+		return nil, err
+	}
+
+	type version struct {
+		number string
+		semver *semver.Version
+	}
+	var vs []version
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		sv, err := semver.NewVersion(line)
+		if err != nil {
+			continue
+		}
+		vs = append(vs, version{number: line, semver: sv})
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].semver.LessThan(vs[j].semver) })
+
+	versions := make([]pkgs.Version, 0, len(vs))
+	for _, v := range vs {
+		versions = append(versions, pkgs.Version{Number: v.number})
+	}
+	return versions, nil
+}
+
+// Fetch downloads the zip of the given version of the module with the given path and extracts it
+// into dir.
+func (c *Client) Fetch(ctx context.Context, module, version, dir string) error {
+	escaped, err := escapePath(module)
+	if err != nil {
+		return err
+	}
+	escapedVersion, err := escapePath(version)
+	if err != nil {
+		return err
+	}
+	data, err := c.get(ctx, escaped+"/@v/"+escapedVersion+".zip")
+	if err != nil {
+		return err
+	}
+
+	// All files of a module zip are in the directory "module@version/".
+	prefix := strings.Count(module, "/") + 1
+	return errors.Wrapf(pkgs.ExtractZip(data, dir, prefix), "extracting zip of %s@%s", module, version)
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	u, err := c.proxy.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pkgs.Get(ctx, c.doer, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// escapePath escapes a module path or version for use in a module proxy URL, by replacing every
+// uppercase letter with an exclamation mark followed by its lowercase equivalent (see
+// https://golang.org/cmd/go/#hdr-Module_proxy_protocol).
+func escapePath(s string) (string, error) {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '!' || r >= unicode.MaxASCII:
+			return "", fmt.Errorf("invalid Go module path or version %q", s)
+		case 'A' <= r && r <= 'Z':
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+// ParseDependency parses a dependency specification of the form "path" or "path@version".
+func ParseDependency(spec string) (pkgs.Dependency, error) {
+	var d pkgs.Dependency
+	if i := strings.Index(spec, "@"); i >= 0 {
+		d.Name, d.Version = spec[:i], spec[i+1:]
+		if _, err := semver.NewVersion(d.Version); err != nil || !strings.HasPrefix(d.Version, "v") {
+			return d, fmt.Errorf("invalid Go module dependency %q: version must be a semantic version starting with \"v\"", spec)
+		}
+	} else {
+		d.Name = spec
+	}
+	if d.Name == "" || strings.HasPrefix(d.Name, "/") || strings.HasSuffix(d.Name, "/") {
+		return d, fmt.Errorf("invalid Go module path %q", d.Name)
+	}
+	_, err := escapePath(d.Name)
+	return d, err
+}
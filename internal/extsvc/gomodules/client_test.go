@@ -0,0 +1,93 @@
+package gomodules
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+func TestClient(t *testing.T) {
+	var modZip bytes.Buffer
+	zw := zip.NewWriter(&modZip)
+	w, err := zw.Create("github.com/!acme/widgets@v1.10.0/widgets.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("package widgets\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/!acme/widgets/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("v1.10.0\nv1.2.0\nv1.9.0-rc.1\n"))
+	})
+	mux.HandleFunc("/github.com/!acme/widgets/@v/v1.10.0.zip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(modZip.Bytes())
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cli, err := NewClient(srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	versions, err := cli.Versions(ctx, "github.com/Acme/widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pkgs.Version{{Number: "v1.2.0"}, {Number: "v1.9.0-rc.1"}, {Number: "v1.10.0"}}
+	if diff := cmp.Diff(want, versions); diff != "" {
+		t.Error(diff)
+	}
+
+	dir, err := ioutil.TempDir("", "gomodules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := cli.Fetch(ctx, "github.com/Acme/widgets", "v1.10.0", dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "widgets.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "package widgets\n" {
+		t.Errorf("got widgets.go %q", data)
+	}
+}
+
+func TestParseDependency(t *testing.T) {
+	for spec, want := range map[string]pkgs.Dependency{
+		"golang.org/x/net":             {Name: "golang.org/x/net"},
+		"github.com/pkg/errors@v0.9.1": {Name: "github.com/pkg/errors", Version: "v0.9.1"},
+	} {
+		got, err := ParseDependency(spec)
+		if err != nil {
+			t.Errorf("%s: %s", spec, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %+v, want %+v", spec, got, want)
+		}
+	}
+
+	for _, spec := range []string{"", "/x", "github.com/pkg/errors@0.9.1", "github.com/pkg/errors@latest"} {
+		if _, err := ParseDependency(spec); err == nil {
+			t.Errorf("%s: got nil error", spec)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package gomodules implements a Go module proxy client and parsers for Go module dependency
+// specifications.
+package gomodules
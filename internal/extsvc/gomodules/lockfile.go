@@ -0,0 +1,37 @@
+package gomodules
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+// ParseLockfile returns the module versions recorded in a go.sum file. A go.sum file records a
+// checksum of the go.mod file of every module version in the build list, and a checksum of the
+// sources of those versions whose packages are actually needed:
+//
+//	github.com/pkg/errors v0.9.1 h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=
+//	github.com/pkg/errors v0.9.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=
+//
+// Only the latter are returned.
+func ParseLockfile(data []byte) ([]pkgs.Dependency, error) {
+	var deps []pkgs.Dependency
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid go.sum: line %d: want 3 fields, got %d", line, len(fields))
+		}
+		if strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		deps = append(deps, pkgs.Dependency{Name: fields[0], Version: fields[1]})
+	}
+	return deps, s.Err()
+}
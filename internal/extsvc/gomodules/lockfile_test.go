@@ -0,0 +1,31 @@
+package gomodules
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+func TestParseLockfile(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps, err := ParseLockfile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pkgs.Dependency{
+		{Name: "github.com/Masterminds/semver", Version: "v1.5.0"},
+		{Name: "github.com/pkg/errors", Version: "v0.9.1"},
+	}
+	if diff := cmp.Diff(want, deps); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ParseLockfile([]byte("github.com/pkg/errors v0.9.1\n")); err == nil {
+		t.Error("got nil error for line without checksum")
+	}
+}
@@ -0,0 +1,124 @@
+package maven
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// ServiceType is the (api.ExternalRepoSpec).ServiceType value for Maven artifacts. The ServiceID
+// value is the URL of the repository (e.g., "https://repo1.maven.org/maven2/").
+const ServiceType = "maven"
+
+// DefaultRegistryURL is the URL of Maven Central.
+const DefaultRegistryURL = "https://repo1.maven.org/maven2/"
+
+// Client is a client for a Maven repository. It implements pkgs.Registry. Artifacts are named
+// "group:artifact".
+type Client struct {
+	registry *url.URL
+	doer     httpcli.Doer
+}
+
+var _ pkgs.Registry = &Client{}
+
+// NewClient returns a client for the Maven repository at registryURL. If doer is nil,
+// http.DefaultClient is used.
+func NewClient(registryURL string, doer httpcli.Doer) (*Client, error) {
+	u, err := url.Parse(pkgs.ServiceID(registryURL))
+	if err != nil {
+		return nil, err
+	}
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	return &Client{registry: u, doer: doer}, nil
+}
+
+// Versions returns the published versions of the named artifact, in the order they are listed in
+// its maven-metadata.xml (which is the order they were deployed in). Maven repositories do not
+// record when each version was published.
+func (c *Client) Versions(ctx context.Context, name string) ([]pkgs.Version, error) {
+	dir, err := artifactPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.get(ctx, dir+"/maven-metadata.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata struct {
+		Versions []string `xml:"versioning>versions>version"`
+	}
+	if err := xml.Unmarshal(data, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "invalid maven-metadata.xml of %s", name)
+	}
+	versions := make([]pkgs.Version, 0, len(metadata.Versions))
+	for _, v := range metadata.Versions {
+		versions = append(versions, pkgs.Version{Number: v})
+	}
+	return versions, nil
+}
+
+// Fetch downloads the sources JAR of the given version of the named artifact and extracts it into
+// dir. Artifacts that were deployed without a sources JAR can't be fetched.
+func (c *Client) Fetch(ctx context.Context, name, version, dir string) error {
+	path, err := artifactPath(name)
+	if err != nil {
+		return err
+	}
+	artifact := name[strings.Index(name, ":")+1:]
+	data, err := c.get(ctx, path+"/"+url.PathEscape(version)+"/"+url.PathEscape(artifact+"-"+version+"-sources.jar"))
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(pkgs.ExtractZip(data, dir, 0), "extracting sources JAR of %s:%s", name, version)
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	u, err := c.registry.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pkgs.Get(ctx, c.doer, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// artifactPath returns the path of the directory of the named artifact in a Maven repository
+// (e.g. "org/apache/commons/commons-lang3" for "org.apache.commons:commons-lang3").
+func artifactPath(name string) (string, error) {
+	i := strings.Index(name, ":")
+	if i <= 0 || i == len(name)-1 || strings.Count(name, ":") != 1 {
+		return "", fmt.Errorf("invalid Maven artifact name %q (must be group:artifact)", name)
+	}
+	group, artifact := name[:i], name[i+1:]
+	return strings.Replace(group, ".", "/", -1) + "/" + url.PathEscape(artifact), nil
+}
+
+// ParseDependency parses a dependency specification of the form "group:artifact" or
+// "group:artifact:version".
+func ParseDependency(spec string) (pkgs.Dependency, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 2 && len(parts) != 3 || len(parts) == 3 && parts[2] == "" {
+		return pkgs.Dependency{}, fmt.Errorf("invalid Maven dependency %q (must be group:artifact or group:artifact:version)", spec)
+	}
+	d := pkgs.Dependency{Name: parts[0] + ":" + parts[1]}
+	if len(parts) == 3 {
+		d.Version = parts[2]
+	}
+	_, err := artifactPath(d.Name)
+	return d, err
+}
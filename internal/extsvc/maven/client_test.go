@@ -0,0 +1,109 @@
+package maven
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+func TestClient(t *testing.T) {
+	var jar bytes.Buffer
+	zw := zip.NewWriter(&jar)
+	w, err := zw.Create("com/example/Widget.java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("class Widget {}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/maven2/com/example/widgets/maven-metadata.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.example</groupId>
+  <artifactId>widgets</artifactId>
+  <versioning>
+    <latest>1.1</latest>
+    <release>1.1</release>
+    <versions>
+      <version>1.0</version>
+      <version>1.1</version>
+    </versions>
+  </versioning>
+</metadata>`))
+	})
+	mux.HandleFunc("/maven2/com/example/widgets/1.1/widgets-1.1-sources.jar", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jar.Bytes())
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cli, err := NewClient(srv.URL+"/maven2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	versions, err := cli.Versions(ctx, "com.example:widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]pkgs.Version{{Number: "1.0"}, {Number: "1.1"}}, versions); diff != "" {
+		t.Error(diff)
+	}
+
+	dir, err := ioutil.TempDir("", "maven")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := cli.Fetch(ctx, "com.example:widgets", "1.1", dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "com/example/Widget.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "class Widget {}\n" {
+		t.Errorf("got Widget.java %q", data)
+	}
+
+	// Version 1.0 was deployed without sources.
+	if err := cli.Fetch(ctx, "com.example:widgets", "1.0", dir); err == nil {
+		t.Error("got nil error for version without sources JAR")
+	}
+}
+
+func TestParseDependency(t *testing.T) {
+	for spec, want := range map[string]pkgs.Dependency{
+		"org.apache.commons:commons-lang3":     {Name: "org.apache.commons:commons-lang3"},
+		"org.apache.commons:commons-lang3:3.9": {Name: "org.apache.commons:commons-lang3", Version: "3.9"},
+	} {
+		got, err := ParseDependency(spec)
+		if err != nil {
+			t.Errorf("%s: %s", spec, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %+v, want %+v", spec, got, want)
+		}
+	}
+
+	for _, spec := range []string{"", "commons-lang3", ":commons-lang3", "org.apache.commons:", "a:b:", "a:b:c:d"} {
+		if _, err := ParseDependency(spec); err == nil {
+			t.Errorf("%s: got nil error", spec)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package maven implements a Maven repository client and parsers for Maven dependency
+// specifications.
+package maven
@@ -0,0 +1,37 @@
+package maven
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+// ParseLockfile returns the artifact versions locked by a Gradle dependency lock file
+// (gradle.lockfile). Each line of such a file locks an artifact version for a list of
+// configurations:
+//
+//	# This is a Gradle generated file for dependency locking.
+//	com.google.guava:guava:28.2-jre=compileClasspath,runtimeClasspath
+//	empty=annotationProcessor
+func ParseLockfile(data []byte) ([]pkgs.Dependency, error) {
+	var deps []pkgs.Dependency
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "empty=") {
+			continue
+		}
+		if i := strings.Index(text, "="); i >= 0 {
+			text = text[:i]
+		}
+		d, err := ParseDependency(text)
+		if err != nil || d.Version == "" {
+			return nil, fmt.Errorf("invalid gradle.lockfile: line %d: %q is not group:artifact:version", line, text)
+		}
+		deps = append(deps, d)
+	}
+	return deps, s.Err()
+}
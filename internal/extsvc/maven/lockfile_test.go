@@ -0,0 +1,32 @@
+package maven
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+func TestParseLockfile(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/gradle.lockfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps, err := ParseLockfile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pkgs.Dependency{
+		{Name: "com.google.guava:failureaccess", Version: "1.0.1"},
+		{Name: "com.google.guava:guava", Version: "28.2-jre"},
+		{Name: "org.apache.commons:commons-lang3", Version: "3.9"},
+	}
+	if diff := cmp.Diff(want, deps); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ParseLockfile([]byte("com.google.guava:guava=compileClasspath\n")); err == nil {
+		t.Error("got nil error for lockfile entry without version")
+	}
+}
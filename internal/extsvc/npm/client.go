@@ -0,0 +1,158 @@
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// ServiceType is the (api.ExternalRepoSpec).ServiceType value for npm packages. The ServiceID
+// value is the URL of the registry (e.g., "https://registry.npmjs.org/").
+const ServiceType = "npm"
+
+// DefaultRegistryURL is the URL of the public npm registry.
+const DefaultRegistryURL = "https://registry.npmjs.org/"
+
+// Client is a client for an npm registry. It implements pkgs.Registry.
+type Client struct {
+	registry *url.URL
+	doer     httpcli.Doer
+
+	mu       sync.Mutex
+	tarballs map[string]string // tarball URLs by "name@version", as listed by Versions
+}
+
+var _ pkgs.Registry = &Client{}
+
+// NewClient returns a client for the npm registry at registryURL. If doer is nil,
+// http.DefaultClient is used.
+func NewClient(registryURL string, doer httpcli.Doer) (*Client, error) {
+	u, err := url.Parse(pkgs.ServiceID(registryURL))
+	if err != nil {
+		return nil, err
+	}
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	return &Client{registry: u, doer: doer, tarballs: map[string]string{}}, nil
+}
+
+// packument is the subset of the registry document of a package that we use.
+type packument struct {
+	Versions map[string]struct {
+		Dist struct {
+			Tarball string `json:"tarball"`
+		} `json:"dist"`
+	} `json:"versions"`
+	Time map[string]time.Time `json:"time"`
+}
+
+// Versions returns the published versions of the named package, in the order they were
+// published. It remembers the tarball URL of each version for Fetch.
+func (c *Client) Versions(ctx context.Context, name string) ([]pkgs.Version, error) {
+	var doc packument
+	if err := c.get(ctx, packagePath(name), &doc); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	versions := make([]pkgs.Version, 0, len(doc.Versions))
+	for v, meta := range doc.Versions {
+		versions = append(versions, pkgs.Version{Number: v, Published: doc.Time[v]})
+		if meta.Dist.Tarball != "" {
+			c.tarballs[name+"@"+v] = meta.Dist.Tarball
+		}
+	}
+	c.mu.Unlock()
+	sort.Slice(versions, func(i, j int) bool {
+		if !versions[i].Published.Equal(versions[j].Published) {
+			return versions[i].Published.Before(versions[j].Published)
+		}
+		return versions[i].Number < versions[j].Number
+	})
+	return versions, nil
+}
+
+// Fetch downloads the tarball of the given version of the named package and extracts it into dir.
+func (c *Client) Fetch(ctx context.Context, name, version, dir string) error {
+	c.mu.Lock()
+	tarball, ok := c.tarballs[name+"@"+version]
+	c.mu.Unlock()
+	if !ok {
+		// Registries serve tarballs at this conventional location, relative to the package.
+		tarball = packagePath(name) + "/-/" + path.Base(name) + "-" + version + ".tgz"
+	}
+	u, err := c.registry.Parse(tarball)
+	if err != nil {
+		return err
+	}
+
+	resp, err := pkgs.Get(ctx, c.doer, u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Tarballs contain a single top-level directory (usually "package/").
+	return errors.Wrapf(pkgs.ExtractTarGz(resp.Body, dir, 1), "extracting tarball of %s@%s", name, version)
+}
+
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	u, err := c.registry.Parse(path)
+	if err != nil {
+		return err
+	}
+	resp, err := pkgs.Get(ctx, c.doer, u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// packagePath returns the registry path of the named package. The slash of scoped package names
+// (e.g. "@babel/core") is escaped.
+func packagePath(name string) string {
+	return url.PathEscape(name)
+}
+
+// ParseDependency parses a dependency specification of the form "name" or "name@version".
+func ParseDependency(spec string) (pkgs.Dependency, error) {
+	// The name of scoped packages starts with "@".
+	i := strings.LastIndex(spec, "@")
+	if i <= 0 {
+		return pkgs.Dependency{Name: spec}, validateName(spec)
+	}
+	d := pkgs.Dependency{Name: spec[:i], Version: spec[i+1:]}
+	// Version ranges (e.g. "^7.8.4") are not supported, only the exact versions that are
+	// published.
+	if _, err := semver.NewVersion(d.Version); err != nil {
+		return d, fmt.Errorf("invalid npm dependency %q: %s", spec, err)
+	}
+	return d, validateName(d.Name)
+}
+
+// validateName checks that name is either a plain ("react") or a scoped ("@babel/core") package
+// name.
+func validateName(name string) error {
+	slashes := 0
+	if strings.HasPrefix(name, "@") {
+		slashes = 1
+	}
+	if name == "" || name == "@" || strings.Count(name, "/") != slashes || strings.HasSuffix(name, "/") {
+		return fmt.Errorf("invalid npm package name %q", name)
+	}
+	return nil
+}
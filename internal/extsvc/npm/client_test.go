@@ -0,0 +1,139 @@
+package npm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newFakeRegistry returns a registry that serves the package @acme/widgets with the versions
+// 1.0.0 and 0.9.0, published in that order.
+func newFakeRegistry(t *testing.T) *httptest.Server {
+	tgz := tarball(t, map[string]string{"package/index.js": "module.exports = 1\n"})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/@acme%2Fwidgets", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"name": "@acme/widgets",
+			"versions": {
+				"0.9.0": {"dist": {"tarball": "http://` + r.Host + `/files/widgets-0.9.0.tgz"}},
+				"1.0.0": {}
+			},
+			"time": {
+				"created": "2019-12-01T00:00:00.000Z",
+				"1.0.0": "2020-01-01T00:00:00.000Z",
+				"0.9.0": "2020-02-01T00:00:00.000Z"
+			}
+		}`))
+	})
+	mux.HandleFunc("/files/widgets-0.9.0.tgz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(tgz) })
+	mux.HandleFunc("/@acme%2Fwidgets/-/widgets-1.0.0.tgz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(tgz) })
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The mux matches the unescaped path, which would lose the escaped slash of scoped names.
+		r.URL.Path = r.URL.EscapedPath()
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestClient(t *testing.T) {
+	srv := newFakeRegistry(t)
+	defer srv.Close()
+
+	cli, err := NewClient(srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	versions, err := cli.Versions(ctx, "@acme/widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pkgs.Version{
+		{Number: "1.0.0", Published: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Number: "0.9.0", Published: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(want, versions); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := cli.Versions(ctx, "does-not-exist"); err == nil {
+		t.Error("got nil error for missing package")
+	}
+
+	// 0.9.0 is fetched from the tarball URL listed by the registry, 1.0.0 from the conventional
+	// location.
+	for _, v := range []string{"0.9.0", "1.0.0"} {
+		dir, err := ioutil.TempDir("", "npm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := cli.Fetch(ctx, "@acme/widgets", v, dir); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, "index.js"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "module.exports = 1\n" {
+			t.Errorf("got index.js %q", data)
+		}
+	}
+}
+
+func TestParseDependency(t *testing.T) {
+	for spec, want := range map[string]pkgs.Dependency{
+		"react":             {Name: "react"},
+		"react@16.13.1":     {Name: "react", Version: "16.13.1"},
+		"@babel/core":       {Name: "@babel/core"},
+		"@babel/core@7.8.4": {Name: "@babel/core", Version: "7.8.4"},
+	} {
+		got, err := ParseDependency(spec)
+		if err != nil {
+			t.Errorf("%s: %s", spec, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %+v, want %+v", spec, got, want)
+		}
+	}
+
+	for _, spec := range []string{"", "react@", "@babel", "a/b", "@babel/core/x", "@babel/core@^7.8.4"} {
+		if _, err := ParseDependency(spec); err == nil {
+			t.Errorf("%s: got nil error", spec)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package npm implements an npm registry client and parsers for npm dependency specifications.
+package npm
@@ -0,0 +1,82 @@
+package npm
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+// lockfileDependency is an entry of the (nested) dependencies of a version 1 package-lock.json.
+type lockfileDependency struct {
+	Version      string                        `json:"version"`
+	Dependencies map[string]lockfileDependency `json:"dependencies"`
+}
+
+// ParseLockfile returns the package versions locked by a package-lock.json file (in the format
+// of lockfileVersion 1, 2 or 3). Dependencies that are not installed from the registry, such as
+// local directories or Git repositories, are omitted.
+func ParseLockfile(data []byte) ([]pkgs.Dependency, error) {
+	var lock struct {
+		Dependencies map[string]lockfileDependency `json:"dependencies"`
+		Packages     map[string]struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Link    bool   `json:"link"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, errors.Wrap(err, "invalid package-lock.json")
+	}
+
+	seen := map[pkgs.Dependency]bool{}
+	add := func(name, version string) {
+		// Versions of packages that are not from the registry are paths or URLs (e.g.
+		// "file:../lib" or "git+https://...").
+		if name == "" || version == "" || strings.ContainsAny(version, ":/") {
+			return
+		}
+		seen[pkgs.Dependency{Name: name, Version: version}] = true
+	}
+
+	// Version 2 and 3 lockfiles list every installed package by its path in node_modules.
+	for path, p := range lock.Packages {
+		if path == "" || p.Link {
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			i := strings.LastIndex(path, "node_modules/")
+			if i < 0 {
+				continue
+			}
+			name = path[i+len("node_modules/"):]
+		}
+		add(name, p.Version)
+	}
+
+	// Version 1 lockfiles (and version 2 lockfiles, for backwards compatibility) nest
+	// dependencies that could not be hoisted.
+	var walk func(map[string]lockfileDependency)
+	walk = func(deps map[string]lockfileDependency) {
+		for name, d := range deps {
+			add(name, d.Version)
+			walk(d.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+
+	deps := make([]pkgs.Dependency, 0, len(seen))
+	for d := range seen {
+		deps = append(deps, d)
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Name != deps[j].Name {
+			return deps[i].Name < deps[j].Name
+		}
+		return deps[i].Version < deps[j].Version
+	})
+	return deps, nil
+}
@@ -0,0 +1,32 @@
+package npm
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/pkgs"
+)
+
+func TestParseLockfile(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/package-lock.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps, err := ParseLockfile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pkgs.Dependency{
+		{Name: "@babel/core", Version: "7.8.4"},
+		{Name: "left-pad", Version: "1.1.3"},
+		{Name: "left-pad", Version: "1.3.0"},
+	}
+	if diff := cmp.Diff(want, deps); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ParseLockfile([]byte("not json")); err == nil {
+		t.Error("got nil error for invalid lockfile")
+	}
+}
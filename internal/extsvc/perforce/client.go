@@ -0,0 +1,142 @@
+package perforce
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Client runs p4 commands against a single Perforce server.
+type Client struct {
+	remote Remote
+
+	// Command is the p4 executable to run. It defaults to "p4" on $PATH.
+	Command string
+}
+
+// NewClient returns a client for the Perforce server at p4port that authenticates as user.
+func NewClient(p4port, user, password string) *Client {
+	return &Client{
+		remote:  Remote{Port: p4port, User: user, Password: password},
+		Command: "p4",
+	}
+}
+
+// Depot is a depot path that is mirrored as a repository.
+type Depot struct {
+	// Path is the depot path, with a trailing slash (e.g., "//depot/engine/").
+	Path string
+	// Type is the depot type (e.g., "local" or "stream") or, for a stream, the stream type (e.g.,
+	// "mainline" or "development").
+	Type string
+	// Stream is whether Path is a stream of a stream depot.
+	Stream bool
+	// Description is the description of the depot or stream.
+	Description string `json:",omitempty"`
+}
+
+// Depots returns all depots on the server that are visible to the user.
+func (c *Client) Depots(ctx context.Context) ([]*Depot, error) {
+	records, err := c.run(ctx, "depots")
+	if err != nil {
+		return nil, err
+	}
+	depots := make([]*Depot, 0, len(records))
+	for _, r := range records {
+		depots = append(depots, &Depot{
+			Path:        "//" + r["name"] + "/",
+			Type:        r["type"],
+			Description: strings.TrimSpace(r["desc"]),
+		})
+	}
+	return depots, nil
+}
+
+// Streams returns all streams of the stream depot with the given name.
+func (c *Client) Streams(ctx context.Context, depot string) ([]*Depot, error) {
+	records, err := c.run(ctx, "streams", "//"+depot+"/...")
+	if err != nil {
+		return nil, err
+	}
+	streams := make([]*Depot, 0, len(records))
+	for _, r := range records {
+		streams = append(streams, &Depot{
+			Path:        r["Stream"] + "/",
+			Type:        r["Type"],
+			Stream:      true,
+			Description: strings.TrimSpace(r["desc"]),
+		})
+	}
+	return streams, nil
+}
+
+// LatestChange returns the number of the most recent submitted changelist that affects a file
+// under depotPath. It returns an error if there is none, e.g. because the path does not exist
+// or the user may not read it.
+func (c *Client) LatestChange(ctx context.Context, depotPath string) (int64, error) {
+	records, err := c.run(ctx, "changes", "-m", "1", "-s", "submitted", strings.TrimSuffix(depotPath, "/")+"/...")
+	if err != nil {
+		return 0, err
+	}
+	if len(records) == 0 {
+		return 0, errors.Errorf("no submitted changelists found under %s", depotPath)
+	}
+	return strconv.ParseInt(records[0]["change"], 10, 64)
+}
+
+// run runs p4 with tagged output (-ztag) and returns the records it printed.
+func (c *Client) run(ctx context.Context, args ...string) ([]map[string]string, error) {
+	cmd := exec.CommandContext(ctx, c.Command, append([]string{"-ztag"}, args...)...)
+	cmd.Env = append(os.Environ(), c.remote.Env()...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "p4 %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return parseTaggedOutput(out)
+}
+
+// parseTaggedOutput parses the output of a p4 command run with -ztag. Each record consists of
+// lines of the form "... key value" and is terminated by an empty line.
+func parseTaggedOutput(out []byte) ([]map[string]string, error) {
+	var (
+		records []map[string]string
+		current map[string]string
+	)
+	s := bufio.NewScanner(bytes.NewReader(out))
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			if current != nil {
+				records = append(records, current)
+				current = nil
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "... ") {
+			return nil, errors.Errorf("unexpected p4 output line %q", line)
+		}
+		kv := strings.SplitN(strings.TrimPrefix(line, "... "), " ", 2)
+		if current == nil {
+			current = map[string]string{}
+		}
+		if len(kv) == 2 {
+			current[kv[0]] = kv[1]
+		} else {
+			current[kv[0]] = ""
+		}
+	}
+	if current != nil {
+		records = append(records, current)
+	}
+	return records, s.Err()
+}
@@ -0,0 +1,76 @@
+package perforce
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newFakeClient(t *testing.T, password string) *Client {
+	t.Helper()
+	fakeP4, err := filepath.Abs("testdata/fake-p4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("ssl:perforce.example.com:1666", "admin", password)
+	c.Command = fakeP4
+	return c
+}
+
+func TestClient_Depots(t *testing.T) {
+	c := newFakeClient(t, "secret")
+	depots, err := c.Depots(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Depot{
+		{Path: "//depot/", Type: "local", Description: "Default depot"},
+		{Path: "//streams/", Type: "stream", Description: "Stream depot for the game engine"},
+		{Path: "//spec/", Type: "spec", Description: "Specification depot"},
+	}
+	if !reflect.DeepEqual(depots, want) {
+		t.Errorf("got %+v, want %+v", depots, want)
+	}
+}
+
+func TestClient_Streams(t *testing.T) {
+	c := newFakeClient(t, "secret")
+	streams, err := c.Streams(context.Background(), "streams")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Depot{
+		{Path: "//streams/main/", Type: "mainline", Stream: true, Description: "Mainline of the game engine"},
+		{Path: "//streams/release-1.0/", Type: "release", Stream: true, Description: "Release 1.0"},
+	}
+	if !reflect.DeepEqual(streams, want) {
+		t.Errorf("got %+v, want %+v", streams, want)
+	}
+}
+
+func TestClient_LatestChange(t *testing.T) {
+	c := newFakeClient(t, "secret")
+	change, err := c.LatestChange(context.Background(), "//depot/engine/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change != 48213 {
+		t.Errorf("got change %d, want 48213", change)
+	}
+
+	if _, err := c.LatestChange(context.Background(), "//depot/empty/"); err == nil {
+		t.Error("got nil error for depot path without changelists")
+	}
+}
+
+func TestClient_authError(t *testing.T) {
+	c := newFakeClient(t, "wrong")
+	_, err := c.Depots(context.Background())
+	if err == nil {
+		t.Fatal("got nil error")
+	}
+	if want := "p4 depots: Perforce password (P4PASSWD) invalid or unset.: exit status 1"; err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+}
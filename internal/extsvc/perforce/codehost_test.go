@@ -0,0 +1,62 @@
+package perforce
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemote_CloneURL(t *testing.T) {
+	for _, r := range []*Remote{
+		{Port: "ssl:perforce.example.com:1666", User: "admin", Password: "p@ss/word", DepotPath: "//depot/engine/"},
+		{Port: "perforce.example.com:1666", User: "admin", Password: "secret", DepotPath: "//streams/main/"},
+		{Port: "tcp6:[::1]:1666", User: "admin", Password: "", DepotPath: "//depot/"},
+	} {
+		cloneURL := r.CloneURL()
+		if !IsCloneURL(cloneURL) {
+			t.Errorf("IsCloneURL(%q) = false", cloneURL)
+		}
+		got, err := ParseCloneURL(cloneURL)
+		if err != nil {
+			t.Errorf("ParseCloneURL(%q): %s", cloneURL, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, r) {
+			t.Errorf("ParseCloneURL(%q): got %+v, want %+v", cloneURL, got, r)
+		}
+	}
+
+	if got, want := (&Remote{Port: "ssl:perforce.example.com:1666", User: "u", Password: "p", DepotPath: "//depot/engine/"}).CloneURL(), "perforce+ssl://u:p@perforce.example.com:1666//depot/engine/"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, u := range []string{"https://github.com/foo/bar", "perforce://host:1666/depot"} {
+		if _, err := ParseCloneURL(u); err == nil {
+			t.Errorf("ParseCloneURL(%q): got nil error", u)
+		}
+	}
+}
+
+func TestServiceIDAndHost(t *testing.T) {
+	for _, tc := range []struct{ port, serviceID, host string }{
+		{"ssl:perforce.example.com:1666", "perforce://perforce.example.com:1666/", "perforce.example.com"},
+		{"perforce.example.com:1666", "perforce://perforce.example.com:1666/", "perforce.example.com"},
+		{"1666", "perforce://localhost:1666/", "localhost"},
+	} {
+		if got := ServiceID(tc.port); got != tc.serviceID {
+			t.Errorf("ServiceID(%q): got %q, want %q", tc.port, got, tc.serviceID)
+		}
+		if got := Host(tc.port); got != tc.host {
+			t.Errorf("Host(%q): got %q, want %q", tc.port, got, tc.host)
+		}
+	}
+}
+
+func TestChangelistFromCommitMessage(t *testing.T) {
+	change, ok := ChangelistFromCommitMessage("Fix renderer crash on startup\n\n[git-p4: depot-paths = \"//depot/engine/\": change = 48213]\n")
+	if !ok || change != 48213 {
+		t.Errorf("got (%d, %v), want (48213, true)", change, ok)
+	}
+	if _, ok := ChangelistFromCommitMessage("Fix renderer crash on startup"); ok {
+		t.Error("got ok for a commit that was not converted by git-p4")
+	}
+}
@@ -0,0 +1,3 @@
+// Package perforce implements a Perforce client (backed by the p4 command) and the conventions
+// for mirroring Perforce depots as Git repositories.
+package perforce
@@ -0,0 +1,118 @@
+package pkgs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxExtractedSize is the maximum total size of the files extracted from an archive. Archives are
+// downloaded from package registries, so a small compressed archive could otherwise fill the disk.
+var MaxExtractedSize int64 = 1 << 30 // 1 GiB
+
+// ErrArchiveTooLarge is returned when the files of an archive exceed MaxExtractedSize.
+var ErrArchiveTooLarge = errors.New("archive exceeds the maximum extracted size")
+
+// ExtractTarGz extracts the regular files of the gzipped tar archive r into dir, removing the
+// first n components of their paths (like tar --strip-components).
+func ExtractTarGz(r io.Reader, dir string, n int) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	remaining := MaxExtractedSize
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.Typeflag != tar.TypeReg && h.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if err := writeFile(dir, h.Name, n, os.FileMode(h.Mode), tr, &remaining); err != nil {
+			return err
+		}
+	}
+}
+
+// ExtractZip extracts the regular files of the zip archive data into dir, removing the first n
+// components of their paths.
+func ExtractZip(data []byte, dir string, n int) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	remaining := MaxExtractedSize
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(dir, f.Name, n, f.Mode(), rc, &remaining)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile writes the archive entry name (after removing the first n components of its path)
+// below dir. Entries that would end up outside of dir or inside a .git directory are rejected.
+// The size of the entry is subtracted from *remaining, and ErrArchiveTooLarge is returned if it is
+// larger.
+func writeFile(dir, name string, n int, mode os.FileMode, r io.Reader, remaining *int64) error {
+	parts := strings.Split(path.Clean("/" + name)[1:], "/")
+	if len(parts) <= n {
+		return nil
+	}
+	parts = parts[n:]
+	for _, p := range parts {
+		if p == ".." || p == ".git" {
+			return errors.Errorf("invalid path in archive: %q", name)
+		}
+	}
+
+	dst := filepath.Join(append([]string{dir}, parts...)...)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	// Only the executable bit of the mode is meaningful to Git.
+	perm := os.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	// Sizes in archive headers can't be trusted, so count the bytes that are actually written.
+	written, err := io.CopyN(f, r, *remaining+1)
+	if err != nil && err != io.EOF {
+		f.Close()
+		return err
+	}
+	if written > *remaining {
+		f.Close()
+		return ErrArchiveTooLarge
+	}
+	*remaining -= written
+	return f.Close()
+}
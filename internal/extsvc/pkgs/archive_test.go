@@ -0,0 +1,165 @@
+package pkgs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestExtractTarGz(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{
+		"package/package.json":  `{"name":"left-pad"}`,
+		"package/lib/index.js":  "module.exports = 1",
+		"package/../../outside": "x",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ExtractTarGz(&buf, filepath.Join(dir, "out"), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Paths that try to escape the destination are confined to it.
+	want := map[string]string{
+		"out/package.json": `{"name":"left-pad"}`,
+		"out/lib/index.js": "module.exports = 1",
+	}
+	if diff := cmp.Diff(want, readTree(t, dir)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	newZip := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, body := range files {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte(body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := newZip(map[string]string{"golang.org/x/net@v0.1.0/go.mod": "module golang.org/x/net"})
+	if err := ExtractZip(data, dir, 3); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"go.mod": "module golang.org/x/net"}, readTree(t, dir)); diff != "" {
+		t.Error(diff)
+	}
+
+	if err := ExtractZip(newZip(map[string]string{".git/config": ""}), dir, 0); err == nil {
+		t.Error("got nil error for archive with .git directory")
+	}
+}
+
+func TestExtractSizeLimit(t *testing.T) {
+	defer func(orig int64) { MaxExtractedSize = orig }(MaxExtractedSize)
+	MaxExtractedSize = 1 << 20
+
+	// Each file is 768 KiB of zeros, which compresses to a few KiB.
+	zeros := make([]byte, 768<<10)
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	var tarBuf bytes.Buffer
+	gz := gzip.NewWriter(&tarBuf)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"a", "b"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(zeros); err != nil {
+			t.Fatal(err)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(zeros)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(zeros); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []interface{ Close() error }{zw, tw, gz} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if zipBuf.Len() > 64<<10 || tarBuf.Len() > 64<<10 {
+		t.Fatalf("got compressed sizes %d and %d, want small archives", zipBuf.Len(), tarBuf.Len())
+	}
+
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ExtractZip(zipBuf.Bytes(), filepath.Join(dir, "zip"), 0); err != ErrArchiveTooLarge {
+		t.Errorf("zip: got error %v, want ErrArchiveTooLarge", err)
+	}
+	if err := ExtractTarGz(&tarBuf, filepath.Join(dir, "tar"), 0); err != ErrArchiveTooLarge {
+		t.Errorf("tar: got error %v, want ErrArchiveTooLarge", err)
+	}
+}
@@ -0,0 +1,127 @@
+package pkgs
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// Dependency is a package of a registry, optionally pinned to a single version.
+type Dependency struct {
+	// Name is the name of the package in the registry (e.g. "react", "org.apache.commons:commons-lang3"
+	// or "golang.org/x/net").
+	Name string
+	// Version is the pinned version, or "" for all versions.
+	Version string
+}
+
+// Version is a published version of a package.
+type Version struct {
+	// Number is the version number as the registry reports it (e.g. "16.13.1" or "v0.1.0"). It is
+	// the name of the tag that the version is materialized as.
+	Number string
+	// Published is when the version was published, or the zero time if the registry does not
+	// record it.
+	Published time.Time
+}
+
+// Registry is a package registry whose packages can be mirrored.
+type Registry interface {
+	// Versions returns the published versions of the named package, oldest first. It returns an
+	// error if the package does not exist.
+	Versions(ctx context.Context, name string) ([]Version, error)
+
+	// Fetch downloads the published sources of the given version of the named package and
+	// extracts them into dir.
+	Fetch(ctx context.Context, name, version, dir string) error
+}
+
+// Package is a package of a registry that is mirrored as a repository.
+type Package struct {
+	// Name is the name of the package in the registry.
+	Name string
+	// Versions are the versions of the package that are mirrored, or nil if all versions are.
+	Versions []string `json:",omitempty"`
+}
+
+// ExternalRepoSpec returns an api.ExternalRepoSpec that refers to the specified package of a
+// registry of the given service type.
+func ExternalRepoSpec(pkg *Package, serviceType, serviceID string) api.ExternalRepoSpec {
+	return api.ExternalRepoSpec{
+		ID:          pkg.Name,
+		ServiceType: serviceType,
+		ServiceID:   serviceID,
+	}
+}
+
+// ServiceID returns the external service ID of the packages of the registry at the given URL.
+func ServiceID(registryURL string) string {
+	return strings.TrimSuffix(registryURL, "/") + "/"
+}
+
+// serviceTypes are the service types of the package registry external services. They are the
+// scheme prefixes of clone URLs.
+var serviceTypes = []string{"npm", "maven", "gomodules"}
+
+// Remote is a package of a registry along with the versions to mirror. It is encoded in the clone
+// URL of the mirrored repository.
+type Remote struct {
+	ServiceType string // the service type of the registry, e.g. "npm"
+	RegistryURL string
+	Package     Package
+}
+
+// CloneURL returns the clone URL gitserver uses to mirror r. It is the registry URL with the
+// scheme prefixed by the service type and the package encoded in the query string (e.g.,
+// "npm+https://registry.npmjs.org/?package=react&version=16.13.1").
+func (r *Remote) CloneURL() (string, error) {
+	u, err := url.Parse(ServiceID(r.RegistryURL))
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = r.ServiceType + "+" + u.Scheme
+
+	q := url.Values{"package": {r.Package.Name}}
+	for _, v := range r.Package.Versions {
+		q.Add("version", v)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// IsCloneURL reports whether rawurl is a clone URL of a package, as returned by
+// (*Remote).CloneURL.
+func IsCloneURL(rawurl string) bool {
+	for _, t := range serviceTypes {
+		if strings.HasPrefix(rawurl, t+"+") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCloneURL parses a clone URL returned by (*Remote).CloneURL.
+func ParseCloneURL(rawurl string) (*Remote, error) {
+	if !IsCloneURL(rawurl) {
+		return nil, errors.New("not a package clone URL")
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	if q.Get("package") == "" {
+		return nil, errors.New("package clone URL has no package")
+	}
+
+	r := &Remote{Package: Package{Name: q.Get("package"), Versions: q["version"]}}
+	i := strings.Index(u.Scheme, "+")
+	r.ServiceType, u.Scheme = u.Scheme[:i], u.Scheme[i+1:]
+	u.RawQuery = ""
+	r.RegistryURL = u.String()
+	return r, nil
+}
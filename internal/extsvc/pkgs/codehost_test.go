@@ -0,0 +1,54 @@
+package pkgs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRemote_CloneURL(t *testing.T) {
+	for _, tc := range []struct {
+		remote Remote
+		want   string
+	}{
+		{
+			remote: Remote{ServiceType: "npm", RegistryURL: "https://registry.npmjs.org", Package: Package{Name: "@babel/core"}},
+			want:   "npm+https://registry.npmjs.org/?package=%40babel%2Fcore",
+		},
+		{
+			remote: Remote{
+				ServiceType: "maven",
+				RegistryURL: "https://repo1.maven.org/maven2/",
+				Package:     Package{Name: "org.apache.commons:commons-lang3", Versions: []string{"3.9", "3.10"}},
+			},
+			want: "maven+https://repo1.maven.org/maven2/?package=org.apache.commons%3Acommons-lang3&version=3.9&version=3.10",
+		},
+	} {
+		got, err := tc.remote.CloneURL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("got clone URL %q, want %q", got, tc.want)
+		}
+		if !IsCloneURL(got) {
+			t.Errorf("IsCloneURL(%q) = false", got)
+		}
+
+		remote, err := ParseCloneURL(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := tc.remote
+		want.RegistryURL = ServiceID(want.RegistryURL)
+		if diff := cmp.Diff(&want, remote); diff != "" {
+			t.Errorf("ParseCloneURL(%q): %s", got, diff)
+		}
+	}
+
+	for _, rawurl := range []string{"https://registry.npmjs.org/?package=react", "npm+https://registry.npmjs.org/"} {
+		if _, err := ParseCloneURL(rawurl); err == nil {
+			t.Errorf("ParseCloneURL(%q): got nil error", rawurl)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package pkgs contains code shared by the package registry external services (npm, Maven and Go
+// module proxies), whose packages are mirrored as Git repositories with one commit per version.
+package pkgs
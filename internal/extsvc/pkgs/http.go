@@ -0,0 +1,27 @@
+package pkgs
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// Get sends a GET request for url with doer and returns the response if its status is 200 OK.
+// The caller must close the response body.
+func Get(ctx context.Context, doer httpcli.Doer, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
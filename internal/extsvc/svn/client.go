@@ -0,0 +1,66 @@
+package svn
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Client runs svn commands.
+type Client struct {
+	username, password string
+
+	// Command is the svn executable to run. It defaults to "svn" on $PATH.
+	Command string
+}
+
+// NewClient returns a client that authenticates with the given credentials. Both may be empty.
+func NewClient(username, password string) *Client {
+	return &Client{username: username, password: password, Command: "svn"}
+}
+
+// ListDirs returns the names of the directories that are direct children of the given URL.
+func (c *Client) ListDirs(ctx context.Context, url string) ([]string, error) {
+	out, err := c.run(ctx, "list", "--xml", url)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Entries []struct {
+			Kind string `xml:"kind,attr"`
+			Name string `xml:"name"`
+		} `xml:"list>entry"`
+	}
+	if err := xml.Unmarshal(out, &list); err != nil {
+		return nil, errors.Wrap(err, "parsing svn list output")
+	}
+
+	var dirs []string
+	for _, e := range list.Entries {
+		if e.Kind == "dir" {
+			dirs = append(dirs, e.Name)
+		}
+	}
+	return dirs, nil
+}
+
+func (c *Client) run(ctx context.Context, args ...string) ([]byte, error) {
+	flags := []string{"--non-interactive", "--no-auth-cache"}
+	if c.username != "" {
+		flags = append(flags, "--username", c.username, "--password", c.password)
+	}
+	cmd := exec.CommandContext(ctx, c.Command, append(args, flags...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "svn %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
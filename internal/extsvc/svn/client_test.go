@@ -0,0 +1,35 @@
+package svn
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newFakeClient(t *testing.T, password string) *Client {
+	t.Helper()
+	fakeSVN, err := filepath.Abs("testdata/fake-svn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("admin", password)
+	c.Command = fakeSVN
+	return c
+}
+
+func TestClient_ListDirs(t *testing.T) {
+	c := newFakeClient(t, "secret")
+	dirs, err := c.ListDirs(context.Background(), "https://svn.example.com/repos/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"engine", "tools"}; !reflect.DeepEqual(dirs, want) {
+		t.Errorf("got %v, want %v", dirs, want)
+	}
+
+	c = newFakeClient(t, "wrong")
+	if _, err := c.ListDirs(context.Background(), "https://svn.example.com/repos/"); err == nil {
+		t.Error("got nil error for bad credentials")
+	}
+}
@@ -0,0 +1,62 @@
+package svn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemote_CloneURL(t *testing.T) {
+	for _, r := range []*Remote{
+		{URL: "https://svn.example.com/repos/engine", Username: "admin", Password: "p@ss/word", Layout: StandardLayout},
+		{URL: "svn://svn.example.com/tools", Layout: Layout{Trunk: "main", Branches: []string{"branches", "releases"}}},
+		{URL: "file:///var/svn/legacy", Layout: Layout{Trunk: ""}},
+	} {
+		cloneURL, err := r.CloneURL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsCloneURL(cloneURL) {
+			t.Errorf("IsCloneURL(%q) = false", cloneURL)
+		}
+		got, err := ParseCloneURL(cloneURL)
+		if err != nil {
+			t.Errorf("ParseCloneURL(%q): %s", cloneURL, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, r) {
+			t.Errorf("ParseCloneURL(%q): got %+v, want %+v", cloneURL, got, r)
+		}
+	}
+
+	cloneURL, _ := (&Remote{URL: "https://svn.example.com/repos/engine", Username: "u", Password: "p", Layout: StandardLayout}).CloneURL()
+	if want := "svn+https://u:p@svn.example.com/repos/engine?branches=branches&tags=tags&trunk=trunk"; cloneURL != want {
+		t.Errorf("got %q, want %q", cloneURL, want)
+	}
+
+	for _, u := range []string{"https://github.com/foo/bar", "svn+https://svn.example.com/repos/engine"} {
+		if _, err := ParseCloneURL(u); err == nil {
+			t.Errorf("ParseCloneURL(%q): got nil error", u)
+		}
+	}
+}
+
+func TestFormatAuthorsFile(t *testing.T) {
+	got := string(FormatAuthorsFile(map[string]string{
+		"jdoe":   "Jane Doe <jane@example.com>",
+		"asmith": "Alex Smith <alex@example.com>",
+	}))
+	want := "asmith = Alex Smith <alex@example.com>\njdoe = Jane Doe <jane@example.com>\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRevisionFromCommitMessage(t *testing.T) {
+	rev, ok := RevisionFromCommitMessage("Fix build\n\ngit-svn-id: https://svn.example.com/repos/engine/trunk@1482 6a4ff4a1-3c2e-0410-8bbc-d7e2b0e2c5d4\n")
+	if !ok || rev != 1482 {
+		t.Errorf("got (%d, %v), want (1482, true)", rev, ok)
+	}
+	if _, ok := RevisionFromCommitMessage("Fix build"); ok {
+		t.Error("got ok for a commit that was not converted by git-svn")
+	}
+}
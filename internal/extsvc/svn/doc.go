@@ -0,0 +1,3 @@
+// Package svn implements a Subversion client (backed by the svn command) and the conventions for
+// mirroring Subversion repositories as Git repositories with git-svn.
+package svn
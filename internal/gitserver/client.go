@@ -561,11 +561,15 @@ func (c *Client) doListOne(ctx context.Context, urlSuffix, addr string) ([]strin
 // Repo updates are not guaranteed to occur. If a repo has been updated
 // recently (within the Since duration specified in the request), the
 // update won't happen.
-func (c *Client) RequestRepoUpdate(ctx context.Context, repo Repo, since time.Duration) (*protocol.RepoUpdateResponse, error) {
+//
+// pool is the object pool of the repo's fork network, or nil if it is not a
+// fork.
+func (c *Client) RequestRepoUpdate(ctx context.Context, repo Repo, since time.Duration, pool *protocol.ObjectPool) (*protocol.RepoUpdateResponse, error) {
 	req := &protocol.RepoUpdateRequest{
-		Repo:  repo.Name,
-		URL:   repo.URL,
-		Since: since,
+		Repo:       repo.Name,
+		URL:        repo.URL,
+		Since:      since,
+		ObjectPool: pool,
 	}
 	resp, err := c.httpPost(ctx, repo.Name, "repo-update", req)
 	if err != nil {
@@ -101,7 +101,7 @@ func TestClient_Archive(t *testing.T) {
 	for name, test := range tests {
 		t.Run(string(name), func(t *testing.T) {
 			if test.remote != "" {
-				if _, err := cli.RequestRepoUpdate(ctx, gitserver.Repo{Name: name, URL: test.remote}, 0); err != nil {
+				if _, err := cli.RequestRepoUpdate(ctx, gitserver.Repo{Name: name, URL: test.remote}, 0, nil); err != nil {
 					t.Fatal(err)
 				}
 			}
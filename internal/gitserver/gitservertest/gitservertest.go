@@ -0,0 +1,94 @@
+// Package gitservertest runs a gitserver for the tests of packages that read Git repositories
+// through gitserver.DefaultClient.
+package gitservertest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// Server is a gitserver that stores its repositories in a temporary directory.
+type Server struct {
+	root      string
+	srv       *httptest.Server
+	origAddrs func(context.Context) []string
+}
+
+// Start starts a gitserver and points gitserver.DefaultClient at it until Close is called.
+func Start(t testing.TB) *Server {
+	t.Helper()
+	root, err := ioutil.TempDir("", "gitservertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		root:      root,
+		srv:       httptest.NewServer((&server.Server{ReposDir: filepath.Join(root, "repos")}).Handler()),
+		origAddrs: gitserver.DefaultClient.Addrs,
+	}
+	u, err := url.Parse(s.srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitserver.DefaultClient.Addrs = func(context.Context) []string { return []string{u.Host} }
+	return s
+}
+
+// Close stops the gitserver, removes its repositories and restores gitserver.DefaultClient.
+func (s *Server) Close() {
+	gitserver.DefaultClient.Addrs = s.origAddrs
+	s.srv.Close()
+	os.RemoveAll(s.root)
+}
+
+// MakeRepository runs cmds with bash in a new Git repository and clones it to the gitserver as the
+// repository with the given name. It returns the ID of the repository's HEAD commit. Commits are
+// made by a fixed author and committer.
+func (s *Server) MakeRepository(t testing.TB, name api.RepoName, cmds ...string) api.CommitID {
+	t.Helper()
+	dir, err := ioutil.TempDir(s.root, "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(cmd string) string {
+		c := exec.Command("bash", "-c", cmd)
+		c.Dir = dir
+		c.Env = append(os.Environ(),
+			"GIT_CONFIG_NOSYSTEM=true",
+			"HOME=/dev/null",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@example.com",
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@example.com",
+		)
+		out, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %q failed: %s\n%s", cmd, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	for _, cmd := range append([]string{"git init -q"}, cmds...) {
+		run(cmd)
+	}
+
+	repo := gitserver.Repo{Name: name, URL: dir}
+	resp, err := gitserver.DefaultClient.RequestRepoUpdate(context.Background(), repo, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != "" {
+		t.Fatalf("cloning %s: %s", name, resp.Error)
+	}
+	return api.CommitID(run("git rev-parse HEAD"))
+}
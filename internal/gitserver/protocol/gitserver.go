@@ -45,9 +45,18 @@ type HTTPSConfig struct {
 
 // RepoUpdateRequest is a request to update the contents of a given repo, or clone it if it doesn't exist.
 type RepoUpdateRequest struct {
-	Repo  api.RepoName  `json:"repo"`  // identifying URL for repo
-	URL   string        `json:"url"`   // repo's remote URL
-	Since time.Duration `json:"since"` // debounce interval for queries, used only with request-repo-update
+	Repo       api.RepoName  `json:"repo"`                 // identifying URL for repo
+	URL        string        `json:"url"`                  // repo's remote URL
+	Since      time.Duration `json:"since"`                // debounce interval for queries, used only with request-repo-update
+	ObjectPool *ObjectPool   `json:"objectPool,omitempty"` // the object pool of the repo's fork network, if any
+}
+
+// ObjectPool identifies the fork network that a repository belongs to. Gitserver clones the
+// repositories of a fork network with a shared pool repository as their Git alternate, so that the
+// objects they have in common are stored only once.
+type ObjectPool struct {
+	Name api.RepoName `json:"name"` // the name of the upstream repository of the fork network
+	URL  string       `json:"url"`  // the Git remote URL of the upstream repository
 }
 
 // RepoUpdateResponse returns meta information of the repo enqueued for
@@ -122,6 +131,23 @@ type RepoInfo struct {
 	// recloned automatically, so this time is likely to move forward
 	// periodically.
 	CloneTime *time.Time
+
+	// PushMirrors is the status of the push mirrors of the repository (see the
+	// "git.pushMirrors" site configuration property).
+	PushMirrors []*PushMirrorStatus
+}
+
+// PushMirrorStatus is the status of pushing a repository to one of its push
+// mirrors.
+type PushMirrorStatus struct {
+	URL            string     // the mirror's Git remote URL, without credentials
+	Refspecs       []string   // the refspecs that are pushed
+	LastAttempt    *time.Time // when the last push started
+	LastSuccess    *time.Time // when the last successful push started
+	LastError      string     // the error of the last push, if it failed
+	FailedAttempts int        // the number of consecutive failed pushes
+	NextRetry      *time.Time // when a failed push is retried, if it will be
+	PendingSince   *time.Time // when the oldest update that wasn't pushed yet occurred
 }
 
 // RepoInfoResponse is the response to a repository information request
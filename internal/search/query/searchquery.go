@@ -25,13 +25,15 @@ const (
 	FieldRepoHasCommitAfter = "repohascommitafter"
 	FieldPatternType        = "patterntype"
 	FieldContent            = "content"
+	FieldScope              = "scope"
 
 	// For diff and commit search only:
-	FieldBefore    = "before"
-	FieldAfter     = "after"
-	FieldAuthor    = "author"
-	FieldCommitter = "committer"
-	FieldMessage   = "message"
+	FieldBefore      = "before"
+	FieldAfter       = "after"
+	FieldAuthor      = "author"
+	FieldCommitter   = "committer"
+	FieldMessage     = "message"
+	FieldPullRequest = "pr"
 
 	// Temporary experimental fields:
 	FieldIndex     = "index"
@@ -59,15 +61,17 @@ var (
 			FieldType:        stringFieldType,
 			FieldPatternType: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldContent:     {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldScope:       {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
 			FieldRepoHasFile:        regexpNegatableFieldType,
 			FieldRepoHasCommitAfter: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
-			FieldBefore:    stringFieldType,
-			FieldAfter:     stringFieldType,
-			FieldAuthor:    regexpNegatableFieldType,
-			FieldCommitter: regexpNegatableFieldType,
-			FieldMessage:   regexpNegatableFieldType,
+			FieldBefore:      stringFieldType,
+			FieldAfter:       stringFieldType,
+			FieldAuthor:      regexpNegatableFieldType,
+			FieldCommitter:   regexpNegatableFieldType,
+			FieldMessage:     regexpNegatableFieldType,
+			FieldPullRequest: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
 			// Experimental fields:
 			FieldIndex:     {Literal: types.StringType, Quoted: types.StringType, Singular: true},
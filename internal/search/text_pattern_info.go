@@ -2,6 +2,7 @@
 package search
 
 import (
+	"fmt"
 	"regexp/syntax"
 )
 
@@ -29,5 +30,11 @@ func (p *TextPatternInfo) Validate() error {
 		}
 	}
 
+	switch p.Scope {
+	case "", "comment", "string", "code":
+	default:
+		return fmt.Errorf("invalid scope:%q (valid values are: comment, string, code)", p.Scope)
+	}
+
 	return nil
 }
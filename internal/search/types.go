@@ -126,6 +126,14 @@ type TextPatternInfo struct {
 	PatternMatchesPath    bool
 
 	Languages []string
+
+	// Scope restricts matches to comments, strings or code (see
+	// pkg/searcher/protocol.PatternInfo.Scope). Only unindexed search supports it.
+	Scope string
+
+	// IncludeSubmatches is whether to include the offsets of the pattern's capture groups in the
+	// results of unindexed searches.
+	IncludeSubmatches bool
 }
 
 // CommitPatternInfo is the data type that describes the properties of
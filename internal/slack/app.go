@@ -0,0 +1,205 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// maxRequestAge is the maximum age of a request from Slack. Older requests are rejected to prevent
+// replay attacks.
+const maxRequestAge = 5 * time.Minute
+
+// VerifyRequest verifies that a request (with the given header and body) was sent by Slack, by
+// checking its signature with the Slack app's signing secret. See
+// https://api.slack.com/authentication/verifying-requests-from-slack.
+func VerifyRequest(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("slack: invalid request timestamp")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return errors.New("slack: request timestamp is too old")
+	}
+
+	signature := header.Get("X-Slack-Signature")
+	if !strings.HasPrefix(signature, "v0=") {
+		return errors.New("slack: missing request signature")
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "v0="))
+	if err != nil {
+		return errors.New("slack: invalid request signature")
+	}
+	if !hmac.Equal(got, Sign(signingSecret, timestamp, body)) {
+		return errors.New("slack: request signature mismatch")
+	}
+	return nil
+}
+
+// Sign returns the signature of a request (as in the X-Slack-Signature header, without the "v0="
+// prefix) with the given timestamp and body.
+func Sign(signingSecret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// AppClient calls the Slack Web API on behalf of a Slack app.
+type AppClient struct {
+	// APIURL is the base URL of the Slack Web API (https://slack.com/api/ by default).
+	APIURL string
+
+	// OAuthURL is the URL of the Slack OAuth authorization page (https://slack.com/oauth/v2/authorize
+	// by default).
+	OAuthURL string
+
+	HTTPClient *http.Client
+}
+
+// DefaultAppClient is the client for the Slack Web API.
+var DefaultAppClient = &AppClient{}
+
+func (c *AppClient) apiURL(method string) string {
+	if c.APIURL == "" {
+		return "https://slack.com/api/" + method
+	}
+	return strings.TrimSuffix(c.APIURL, "/") + "/" + method
+}
+
+// AuthorizeURL returns the URL of the Slack OAuth page where a Slack user authorizes the Slack app to
+// identify them. After authorization, Slack redirects to redirectURI with a code (to exchange with
+// OAuthAccess) and the state.
+func (c *AppClient) AuthorizeURL(clientID, redirectURI, state string) string {
+	u := c.OAuthURL
+	if u == "" {
+		u = "https://slack.com/oauth/v2/authorize"
+	}
+	return u + "?" + url.Values{
+		"client_id":    {clientID},
+		"user_scope":   {"identity.basic"},
+		"redirect_uri": {redirectURI},
+		"state":        {state},
+	}.Encode()
+}
+
+// OAuthAccessResponse is the response of the oauth.v2.access method.
+type OAuthAccessResponse struct {
+	Team struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+	AuthedUser struct {
+		ID string `json:"id"`
+	} `json:"authed_user"`
+}
+
+// OAuthAccess exchanges the code of an OAuth authorization for the identity of the Slack user who
+// authorized it.
+func (c *AppClient) OAuthAccess(ctx context.Context, clientID, clientSecret, code, redirectURI string) (*OAuthAccessResponse, error) {
+	req, err := http.NewRequest("POST", c.apiURL("oauth.v2.access"), strings.NewReader(url.Values{
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	var resp OAuthAccessResponse
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Team.ID == "" || resp.AuthedUser.ID == "" {
+		return nil, errors.New("slack: oauth.v2.access response has no team or user")
+	}
+	return &resp, nil
+}
+
+// Unfurl sets the previews of links in a message (identified by its channel and timestamp), with the
+// chat.unfurl method. The keys of unfurls are the URLs of the links.
+func (c *AppClient) Unfurl(ctx context.Context, botToken, channel, ts string, unfurls map[string]*Attachment) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"channel": channel,
+		"ts":      ts,
+		"unfurls": unfurls,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", c.apiURL("chat.unfurl"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+botToken)
+	return c.do(ctx, req, nil)
+}
+
+// Respond sends a (delayed) response to a slash command to the command's response URL.
+func (c *AppClient) Respond(ctx context.Context, responseURL string, payload *Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := ctxhttp.Do(ctx, c.HTTPClient, req)
+	if err != nil {
+		return errors.Wrap(err, "slack: respond")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack: respond failed with %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// do performs a Slack Web API request and decodes its response into result (if non-nil). The Slack
+// Web API reports errors in the "error" field of the response.
+func (c *AppClient) do(ctx context.Context, req *http.Request, result interface{}) error {
+	resp, err := ctxhttp.Do(ctx, c.HTTPClient, req)
+	if err != nil {
+		return errors.Wrapf(err, "slack: %s", req.URL.Path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack: %s failed with %d", req.URL.Path, resp.StatusCode)
+	}
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return err
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body.Bytes(), &status); err != nil {
+		return errors.Wrapf(err, "slack: %s", req.URL.Path)
+	}
+	if !status.OK {
+		return fmt.Errorf("slack: %s failed: %s", req.URL.Path, status.Error)
+	}
+	if result != nil {
+		return json.Unmarshal(body.Bytes(), result)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package slack
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	now := time.Unix(1531420618, 0)
+	body := []byte("token=x&team_id=T1&text=foo")
+	header := func(timestamp time.Time, signature string) http.Header {
+		h := http.Header{}
+		h.Set("X-Slack-Request-Timestamp", strconv.FormatInt(timestamp.Unix(), 10))
+		h.Set("X-Slack-Signature", signature)
+		return h
+	}
+	valid := "v0=" + hex.EncodeToString(Sign("secret", strconv.FormatInt(now.Unix(), 10), body))
+
+	tests := map[string]struct {
+		header  http.Header
+		body    []byte
+		wantErr bool
+	}{
+		"valid":             {header: header(now, valid), body: body},
+		"wrong secret":      {header: header(now, "v0="+hex.EncodeToString(Sign("other", strconv.FormatInt(now.Unix(), 10), body))), body: body, wantErr: true},
+		"modified body":     {header: header(now, valid), body: []byte("token=x&team_id=T2&text=foo"), wantErr: true},
+		"replayed":          {header: header(now.Add(-10*time.Minute), valid), body: body, wantErr: true},
+		"missing signature": {header: header(now, ""), body: body, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyRequest("secret", test.header, test.body, now)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got err %v, want error: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestAppClient(t *testing.T) {
+	var gotAuth string
+	var gotUnfurl map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/chat.unfurl":
+			gotAuth = r.Header.Get("Authorization")
+			body, _ := ioutil.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &gotUnfurl)
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		case "/api/oauth.v2.access":
+			if user, password, _ := r.BasicAuth(); user != "id" || password != "secret" || r.FormValue("code") != "c" {
+				_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_code"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok": true, "team": {"id": "T1", "name": "Acme"}, "authed_user": {"id": "U1"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := &AppClient{APIURL: srv.URL + "/api/"}
+	ctx := context.Background()
+
+	if err := c.Unfurl(ctx, "xoxb-1", "C1", "1.2", map[string]*Attachment{"https://example.com": {Title: "t"}}); err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer xoxb-1" || gotUnfurl["channel"] != "C1" || gotUnfurl["ts"] != "1.2" {
+		t.Errorf("got Authorization %q and request %v", gotAuth, gotUnfurl)
+	}
+
+	resp, err := c.OAuthAccess(ctx, "id", "secret", "c", "https://sourcegraph.example.com/-/slack/link/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Team.ID != "T1" || resp.AuthedUser.ID != "U1" {
+		t.Errorf("got %+v", resp)
+	}
+	if _, err := c.OAuthAccess(ctx, "id", "secret", "bad", ""); err == nil || err.Error() != "slack: /api/oauth.v2.access failed: invalid_code" {
+		t.Errorf("got err %v, want invalid_code", err)
+	}
+}
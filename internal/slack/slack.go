@@ -32,12 +32,13 @@ func New(webhookURL string, alsoSendToSourcegraph bool) *Client {
 // Payload is the wrapper for a Slack message, defined at:
 // https://api.slack.com/docs/message-formatting
 type Payload struct {
-	Username    string        `json:"username,omitempty"`
-	IconEmoji   string        `json:"icon_emoji,omitempty"`
-	UnfurlLinks bool          `json:"unfurl_links,omitempty"`
-	UnfurlMedia bool          `json:"unfurl_media,omitempty"`
-	Text        string        `json:"text,omitempty"`
-	Attachments []*Attachment `json:"attachments,omitempty"`
+	ResponseType string        `json:"response_type,omitempty"` // for responses to slash commands ("ephemeral" or "in_channel")
+	Username     string        `json:"username,omitempty"`
+	IconEmoji    string        `json:"icon_emoji,omitempty"`
+	UnfurlLinks  bool          `json:"unfurl_links,omitempty"`
+	UnfurlMedia  bool          `json:"unfurl_media,omitempty"`
+	Text         string        `json:"text,omitempty"`
+	Attachments  []*Attachment `json:"attachments,omitempty"`
 }
 
 // Attachment is a Slack message attachment, defined at:
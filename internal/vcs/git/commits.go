@@ -249,6 +249,95 @@ func CommitCount(ctx context.Context, repo gitserver.Repo, opt CommitsOptions) (
 	return uint(n), err
 }
 
+// A CommitWithFiles is a commit and the names of the files it changed.
+type CommitWithFiles struct {
+	*Commit
+	Files []string
+}
+
+// CommitsWithFiles returns the commits in rangeSpec (such as "A..B") that changed a file matching
+// one of the pathspecs, newest first, along with the names of the matching files that each of them
+// changed. If there are no pathspecs, all commits in the range are returned. Pathspecs may use
+// pathspec magic (such as ":(glob)pkg/**"). Merge commits are skipped. At most n commits are
+// returned (0 means no limit).
+//
+// The range must already exist in the repository on gitserver; it is not fetched from the remote.
+func CommitsWithFiles(ctx context.Context, repo gitserver.Repo, rangeSpec string, pathspecs []string, n uint) ([]*CommitWithFiles, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: CommitsWithFiles")
+	span.SetTag("Range", rangeSpec)
+	span.SetTag("Pathspecs", pathspecs)
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(rangeSpec); err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "--no-merges", "-z", "--name-only", commitWithFilesLogFormat}
+	if n != 0 {
+		args = append(args, "-n", strconv.FormatUint(uint64(n), 10))
+	}
+	args = append(args, rangeSpec, "--")
+	args = append(args, pathspecs...)
+
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+	data, stderr, err := cmd.DividedOutput(ctx)
+	if err != nil {
+		if isBadObjectErr(string(bytes.TrimSpace(stderr)), rangeSpec) || isInvalidRevisionRangeError(string(stderr), rangeSpec) {
+			return nil, &gitserver.RevisionNotFoundError{Repo: repo.Name, Spec: rangeSpec}
+		}
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, stderr))
+	}
+	return parseCommitsWithFilesFromLog(data)
+}
+
+const (
+	commitWithFilesFields = 8 // number of \x00-separated fields per commit (before its files)
+
+	commitWithFilesLogFormat = "--format=format:%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00"
+)
+
+// parseCommitsWithFilesFromLog parses the output of `git log -z --name-only` with the format
+// commitWithFilesLogFormat. Each commit's fields are followed by its (newline-prefixed) list of
+// NUL-terminated file names, if any, and commits are separated by an additional NUL.
+func parseCommitsWithFilesFromLog(data []byte) ([]*CommitWithFiles, error) {
+	parts := bytes.Split(data, []byte{'\x00'})
+	var commits []*CommitWithFiles
+	for i := 0; i < len(parts); {
+		if len(parts[i]) == 0 {
+			i++ // separator between commits, or the end of the output
+			continue
+		}
+		if len(parts)-i < commitWithFilesFields {
+			return nil, fmt.Errorf("invalid commit log entry: %q", parts[i:])
+		}
+		f := parts[i : i+commitWithFilesFields]
+		i += commitWithFilesFields
+
+		authorTime, err := strconv.ParseInt(string(f[3]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing git commit author time: %s", err)
+		}
+		committerTime, err := strconv.ParseInt(string(f[6]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing git commit committer time: %s", err)
+		}
+		c := &CommitWithFiles{Commit: &Commit{
+			ID:        api.CommitID(f[0]),
+			Author:    Signature{Name: string(f[1]), Email: string(f[2]), Date: time.Unix(authorTime, 0).UTC()},
+			Committer: &Signature{Name: string(f[4]), Email: string(f[5]), Date: time.Unix(committerTime, 0).UTC()},
+			Message:   string(bytes.TrimSuffix(f[7], []byte{'\n'})),
+		}}
+		if i < len(parts) && bytes.HasPrefix(parts[i], []byte{'\n'}) {
+			for parts[i] = parts[i][1:]; i < len(parts) && len(parts[i]) > 0; i++ {
+				c.Files = append(c.Files, string(parts[i]))
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
 const (
 	partsPerCommit = 10 // number of \x00-separated fields per commit
 
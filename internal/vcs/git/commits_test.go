@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -376,3 +377,70 @@ func TestRepository_Commits_options_path(t *testing.T) {
 		}
 	}
 }
+
+func TestRepository_CommitsWithFiles(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"mkdir -p pkg/auth other",
+		"echo a > pkg/auth/a.go",
+		"echo b > other/b",
+		"git add pkg other",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"echo c > pkg/auth/c.go",
+		"git add pkg",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit2 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git commit --allow-empty -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:08Z",
+	)
+
+	tests := map[string]struct {
+		rangeSpec string
+		pathspecs []string
+		want      map[string][]string // commit message -> files
+	}{
+		"all": {
+			rangeSpec: "master",
+			want:      map[string][]string{"commit3": nil, "commit2": {"pkg/auth/c.go"}, "commit1": {"other/b", "pkg/auth/a.go"}},
+		},
+		"glob": {
+			rangeSpec: "master",
+			pathspecs: []string{":(glob)pkg/**"},
+			want:      map[string][]string{"commit2": {"pkg/auth/c.go"}, "commit1": {"pkg/auth/a.go"}},
+		},
+		"no match": {
+			rangeSpec: "master",
+			pathspecs: []string{":(glob)*.md"},
+			want:      map[string][]string{},
+		},
+	}
+	for label, test := range tests {
+		commits, err := CommitsWithFiles(ctx, repo, test.rangeSpec, test.pathspecs, 0)
+		if err != nil {
+			t.Errorf("%s: CommitsWithFiles: %s", label, err)
+			continue
+		}
+		got := map[string][]string{}
+		for _, c := range commits {
+			got[c.Message] = c.Files
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", label, got, test.want)
+		}
+	}
+
+	// The commits of a range are newest first.
+	commits, err := CommitsWithFiles(ctx, repo, "master~2..master", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Message != "commit3" || commits[1].Message != "commit2" {
+		t.Errorf("got commits %+v, want commit3 and commit2", commits)
+	}
+	if want := "c@c.com"; commits[1].Committer.Email != want {
+		t.Errorf("got committer email %q, want %q", commits[1].Committer.Email, want)
+	}
+
+	if _, err := CommitsWithFiles(ctx, repo, "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef..master", nil, 0); !gitserver.IsRevisionNotFound(err) {
+		t.Errorf("got error %v, want revision not found", err)
+	}
+}
@@ -129,6 +129,7 @@ var (
 		"--find-copies",
 		"--find-renames",
 		"--inter-hunk-context",
+		"--no-walk", "--ignore-missing",
 	}
 )
 
@@ -0,0 +1,254 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// PickaxeOptions specifies options for Pickaxe.
+type PickaxeOptions struct {
+	// Query is the string to find. If IsRegExp, it is a regular expression instead (in the POSIX
+	// extended syntax of git, which mostly agrees with Go's).
+	Query           string
+	IsRegExp        bool
+	IsCaseSensitive bool
+
+	// Range is the revision or revision range (such as "v1.0..v2.0") whose commits are searched.
+	// It defaults to HEAD.
+	Range string
+
+	// Paths are the files and directories whose changes are searched (all if empty).
+	Paths []string
+
+	// Follow continues the search in the history of the file before it was renamed, like `git log
+	// --follow`. It requires Paths to be a single file.
+	Follow bool
+
+	// MaxCommits is the maximum number of commits whose events are returned (0 for no limit).
+	MaxCommits int
+}
+
+// PickaxeEventKind is the kind of a PickaxeEvent.
+type PickaxeEventKind string
+
+const (
+	// PickaxeIntroduced means that the commit added occurrences of the query to the file.
+	PickaxeIntroduced PickaxeEventKind = "INTRODUCED"
+	// PickaxeRemoved means that the commit removed occurrences of the query from the file.
+	PickaxeRemoved PickaxeEventKind = "REMOVED"
+	// PickaxeModified means that the commit changed lines matching the query in the file without
+	// changing the number of occurrences (only for regular expressions).
+	PickaxeModified PickaxeEventKind = "MODIFIED"
+)
+
+// A PickaxeEvent is a change to the occurrences of the query of a pickaxe search in a file.
+type PickaxeEvent struct {
+	Commit Commit
+	Kind   PickaxeEventKind
+	Path   string       // the path of the file (before the commit, if the commit deleted it)
+	Hunks  []*diff.Hunk // the hunks of the commit's diff of the file that change occurrences
+
+	// OrigPath is the path of the file before the commit, if it differs from Path (for renamed
+	// files).
+	OrigPath string
+}
+
+// Pickaxe returns the events of the commits in the range that introduced or removed the query,
+// like `git log -S` (or `git log -G` for regular expressions), newest first. Commits can have an
+// event for each file they change.
+//
+// limitHit is true if git found opt.MaxCommits commits, even if some of them have no events. If
+// the context has a deadline, Pickaxe returns the events found before it with complete == false.
+func Pickaxe(ctx context.Context, repo gitserver.Repo, opt PickaxeOptions) (events []*PickaxeEvent, limitHit, complete bool, err error) {
+	if Mocks.Pickaxe != nil {
+		return Mocks.Pickaxe(opt)
+	}
+
+	tr, ctx := trace.New(ctx, "Git: Pickaxe", fmt.Sprintf("%+v", opt))
+	defer func() {
+		tr.LazyPrintf("%d events, limitHit=%v, complete=%v", len(events), limitHit, complete)
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if opt.Query == "" {
+		return nil, false, false, errors.New("empty pickaxe query")
+	}
+	if opt.Range == "" {
+		opt.Range = "HEAD"
+	}
+	if err := checkSpecArgSafety(opt.Range); err != nil {
+		return nil, false, false, err
+	}
+
+	pattern := opt.Query
+	if !opt.IsRegExp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !opt.IsCaseSensitive {
+		pattern = "(?i:" + pattern + ")"
+	}
+	query, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, false, false, errors.Wrap(err, "invalid regular expression")
+	}
+
+	args := []string{"log", "-z", "--no-merges", "--no-color", "--patch", logFormatWithoutRefs}
+	if opt.IsRegExp {
+		// -G matches the changed lines, which is more useful than the number of occurrences of
+		// regular expressions.
+		args = append(args, "-G"+opt.Query)
+	} else {
+		args = append(args, "-S"+opt.Query)
+	}
+	if !opt.IsCaseSensitive {
+		args = append(args, "--regexp-ignore-case")
+	}
+	if opt.MaxCommits > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(opt.MaxCommits))
+	}
+	if opt.Follow {
+		if len(opt.Paths) != 1 {
+			return nil, false, false, errors.New("following renames requires a single path")
+		}
+		args = append(args, "--follow")
+	}
+	args = append(args, opt.Range)
+	if !isWhitelistedGitCmd(args) {
+		return nil, false, false, fmt.Errorf("command failed: %q is not a whitelisted git command", args)
+	}
+	args = append(args, "--")
+	for _, p := range opt.Paths {
+		args = append(args, ":(literal)"+p)
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+	data, complete, err := readUntilTimeout(ctx, cmd)
+	if err != nil {
+		// Don't fail if the repository is empty.
+		if strings.Contains(err.Error(), "does not have any commits yet") {
+			return nil, false, true, nil
+		}
+		return nil, false, complete, err
+	}
+
+	var commits int
+	for len(data) > 0 {
+		var commit *Commit
+		commit, _, data, err = parseCommitFromLog(data)
+		if err != nil {
+			if !complete {
+				// Partial output can yield parse errors, but the events of the previous commits
+				// are complete.
+				return events, false, false, nil
+			}
+			return nil, false, complete, err
+		}
+
+		var rawDiff []byte
+		if len(data) >= 1 && data[0] == '\x00' {
+			// No diff patch (such as for commits that only changed binary files).
+			data = data[1:]
+		} else if len(data) >= 1 && data[0] == '\n' {
+			data = data[1:]
+			if patchEnd := bytes.Index(data, []byte("\n\x00")); patchEnd != -1 {
+				rawDiff = data[:patchEnd+1]
+				data = data[patchEnd+2:]
+			} else {
+				if !complete {
+					// The patch may be truncated.
+					return events, false, false, nil
+				}
+				rawDiff = data
+				data = nil
+			}
+		}
+
+		commits++
+		commitEvents, err := pickaxeEvents(*commit, rawDiff, query)
+		if err != nil {
+			return nil, false, complete, err
+		}
+		events = append(events, commitEvents...)
+	}
+	return events, opt.MaxCommits > 0 && commits == opt.MaxCommits, complete, nil
+}
+
+// pickaxeEvents returns the events of the commit with the diff, for the files whose changed lines
+// match the query.
+func pickaxeEvents(commit Commit, rawDiff []byte, query *regexp.Regexp) ([]*PickaxeEvent, error) {
+	var events []*PickaxeEvent
+	dr := diff.NewMultiFileDiffReader(bytes.NewReader(rawDiff))
+	for {
+		fileDiff, err := dr.ReadFile()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		event := &PickaxeEvent{Commit: commit, Path: strings.TrimPrefix(fileDiff.NewName, "b/")}
+		if origPath := strings.TrimPrefix(fileDiff.OrigName, "a/"); fileDiff.NewName == "/dev/null" {
+			event.Path = origPath
+		} else if fileDiff.OrigName != "/dev/null" && origPath != event.Path {
+			event.OrigPath = origPath
+		}
+		var origCount, newCount int
+		for _, hunk := range fileDiff.Hunks {
+			hunkOrigCount, hunkNewCount, matching := pickaxeHunkMatches(hunk.Body, query)
+			if hunkOrigCount == hunkNewCount && !matching {
+				continue
+			}
+			origCount += hunkOrigCount
+			newCount += hunkNewCount
+			event.Hunks = append(event.Hunks, hunk)
+		}
+		switch {
+		case len(event.Hunks) == 0:
+			continue
+		case newCount > origCount:
+			event.Kind = PickaxeIntroduced
+		case newCount < origCount:
+			event.Kind = PickaxeRemoved
+		default:
+			event.Kind = PickaxeModified
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// pickaxeHunkMatches returns the number of occurrences of the query in the original and new
+// contents of the diff hunk, and whether any of its changed lines match. Occurrences can span
+// several lines.
+func pickaxeHunkMatches(body []byte, query *regexp.Regexp) (origCount, newCount int, changedLineMatches bool) {
+	var origText, newText bytes.Buffer
+	for _, line := range bytes.SplitAfter(body, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		switch line[0] {
+		case ' ':
+			origText.Write(line[1:])
+			newText.Write(line[1:])
+		case '-':
+			origText.Write(line[1:])
+			changedLineMatches = changedLineMatches || query.Match(line[1:])
+		case '+':
+			newText.Write(line[1:])
+			changedLineMatches = changedLineMatches || query.Match(line[1:])
+		}
+	}
+	return len(query.FindAllIndex(origText.Bytes(), -1)), len(query.FindAllIndex(newText.Bytes(), -1)), changedLineMatches
+}
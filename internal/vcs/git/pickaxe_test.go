@@ -0,0 +1,170 @@
+package git
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPickaxe(t *testing.T) {
+	t.Parallel()
+
+	commit := func(date, message string) string {
+		return "GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=" + date + " git commit -m " + message + " --author='a <a@a.com>' --date " + date
+	}
+	repo := MakeGitRepository(t,
+		"printf 'package a\\n\\nfunc a() {\\n\\tcallFoo()\\n}\\n' > a.go",
+		"echo readme > README",
+		"git add a.go README",
+		commit("2006-01-02T15:04:05Z", "add-a"),
+
+		"printf 'callFoo()\\n' > b.go",
+		"git add b.go",
+		commit("2006-01-02T15:04:06Z", "add-b"),
+
+		"printf 'package a\\n\\nfunc a() {\\n\\tcallFoo(1)\\n}\\n' > a.go",
+		"git add a.go",
+		commit("2006-01-02T15:04:07Z", "change-a"),
+
+		"git rm -q b.go",
+		commit("2006-01-02T15:04:08Z", "remove-b"),
+
+		"git tag v1 HEAD~2",
+	)
+
+	type event struct {
+		Message string
+		Kind    PickaxeEventKind
+		Path    string
+		Hunks   int
+	}
+	tests := []struct {
+		name         string
+		opt          PickaxeOptions
+		want         []event
+		wantLimitHit bool
+	}{{
+		name: "exact string",
+		opt:  PickaxeOptions{Query: "callFoo()", IsCaseSensitive: true},
+		want: []event{
+			{"remove-b", PickaxeRemoved, "b.go", 1},
+			{"change-a", PickaxeRemoved, "a.go", 1},
+			{"add-b", PickaxeIntroduced, "b.go", 1},
+			{"add-a", PickaxeIntroduced, "a.go", 1},
+		},
+	}, {
+		name: "case-sensitive",
+		opt:  PickaxeOptions{Query: "CALLFOO()", IsCaseSensitive: true},
+	}, {
+		name: "case-insensitive",
+		opt:  PickaxeOptions{Query: "CALLFOO()", Paths: []string{"b.go"}},
+		want: []event{
+			{"remove-b", PickaxeRemoved, "b.go", 1},
+			{"add-b", PickaxeIntroduced, "b.go", 1},
+		},
+	}, {
+		name: "multi-line string",
+		opt:  PickaxeOptions{Query: "func a() {\n\tcallFoo(1)", IsCaseSensitive: true},
+		want: []event{
+			{"change-a", PickaxeIntroduced, "a.go", 1},
+		},
+	}, {
+		name: "regexp",
+		opt:  PickaxeOptions{Query: "callFoo\\(.*\\)", IsRegExp: true, IsCaseSensitive: true, Paths: []string{"a.go"}},
+		want: []event{
+			{"change-a", PickaxeModified, "a.go", 1},
+			{"add-a", PickaxeIntroduced, "a.go", 1},
+		},
+	}, {
+		name: "range",
+		opt:  PickaxeOptions{Query: "callFoo", IsCaseSensitive: true, Range: "v1"},
+		want: []event{
+			{"add-b", PickaxeIntroduced, "b.go", 1},
+			{"add-a", PickaxeIntroduced, "a.go", 1},
+		},
+	}, {
+		name: "max commits",
+		opt:  PickaxeOptions{Query: "callFoo", IsCaseSensitive: true, MaxCommits: 1},
+		want: []event{
+			{"remove-b", PickaxeRemoved, "b.go", 1},
+		},
+		wantLimitHit: true,
+	}, {
+		name: "max commits not reached",
+		opt:  PickaxeOptions{Query: "callFoo", IsCaseSensitive: true, Paths: []string{"b.go"}, MaxCommits: 3},
+		want: []event{
+			{"remove-b", PickaxeRemoved, "b.go", 1},
+			{"add-b", PickaxeIntroduced, "b.go", 1},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events, limitHit, complete, err := Pickaxe(context.Background(), repo, test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !complete {
+				t.Error("got incomplete results")
+			}
+			if limitHit != test.wantLimitHit {
+				t.Errorf("got limitHit %v, want %v", limitHit, test.wantLimitHit)
+			}
+			var got []event
+			for _, e := range events {
+				got = append(got, event{e.Commit.Message, e.Kind, e.Path, len(e.Hunks)})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got events %+v, want %+v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("invalid range", func(t *testing.T) {
+		if _, _, _, err := Pickaxe(context.Background(), repo, PickaxeOptions{Query: "x", Range: "--all"}); err == nil {
+			t.Error("got no error for range starting with '-'")
+		}
+	})
+}
+
+func TestPickaxe_follow(t *testing.T) {
+	t.Parallel()
+
+	commit := func(date, message string) string {
+		return "GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=" + date + " git commit -m " + message + " --author='a <a@a.com>' --date " + date
+	}
+	repo := MakeGitRepository(t,
+		"printf 'callFoo()\\n' > a.go",
+		"git add a.go",
+		commit("2006-01-02T15:04:05Z", "add-a"),
+
+		"git mv a.go c.go",
+		commit("2006-01-02T15:04:06Z", "rename-a"),
+
+		"printf 'callFoo()\\ncallFoo()\\n' > c.go",
+		"git add c.go",
+		commit("2006-01-02T15:04:07Z", "change-c"),
+	)
+
+	for _, follow := range []bool{false, true} {
+		events, _, _, err := Pickaxe(context.Background(), repo, PickaxeOptions{Query: "callFoo()", IsCaseSensitive: true, Paths: []string{"c.go"}, Follow: follow})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Commit.Message+" "+e.Path)
+		}
+		want := []string{"change-c c.go", "rename-a c.go"}
+		if follow {
+			// The rename doesn't change the number of occurrences, so the search continues in a.go.
+			want = []string{"change-c c.go", "add-a a.go"}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("follow=%v: got events %q, want %q", follow, got, want)
+		}
+	}
+
+	if _, _, _, err := Pickaxe(context.Background(), repo, PickaxeOptions{Query: "callFoo()", Follow: true}); err == nil {
+		t.Error("got no error for following renames without a path")
+	}
+}
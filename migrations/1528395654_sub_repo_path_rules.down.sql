@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS sub_repo_path_rules;
+
+COMMIT;
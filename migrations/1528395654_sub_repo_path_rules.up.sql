@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Path-level permission rules within a repository. Paths under path_prefix are only readable by
+-- the users in user_ids and the members of the orgs in org_ids. Rules with longer prefixes take
+-- precedence over rules with shorter ones.
+CREATE TABLE IF NOT EXISTS sub_repo_path_rules (
+    id          SERIAL PRIMARY KEY,
+    repo_id     INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    path_prefix TEXT NOT NULL,
+    user_ids    INTEGER[] NOT NULL DEFAULT '{}',
+    org_ids     INTEGER[] NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE sub_repo_path_rules
+    DROP CONSTRAINT IF EXISTS sub_repo_path_rules_repo_prefix_unique,
+    ADD CONSTRAINT sub_repo_path_rules_repo_prefix_unique
+        UNIQUE (repo_id, path_prefix);
+
+COMMIT;
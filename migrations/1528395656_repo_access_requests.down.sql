@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS repo_access_grants;
+DROP TABLE IF EXISTS repo_access_requests;
+
+COMMIT;
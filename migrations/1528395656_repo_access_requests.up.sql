@@ -0,0 +1,37 @@
+BEGIN;
+
+-- Requests by users for read access to the repositories whose names match repo_pattern (a
+-- repository name, or a glob pattern in which * matches any sequence of characters other than /).
+-- approver_user_ids are the users who were notified of the request when it was created.
+CREATE TABLE IF NOT EXISTS repo_access_requests (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_pattern      TEXT NOT NULL CHECK (repo_pattern <> ''),
+    justification     TEXT NOT NULL,
+    approver_user_ids INTEGER[] NOT NULL DEFAULT '{}',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewer_user_id  INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at       TIMESTAMPTZ,
+    approved          BOOLEAN,
+    review_comment    TEXT NOT NULL DEFAULT '',
+    expires_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS repo_access_requests_user_id ON repo_access_requests(user_id);
+CREATE INDEX IF NOT EXISTS repo_access_requests_approver_user_ids ON repo_access_requests USING GIN (approver_user_ids);
+CREATE UNIQUE INDEX IF NOT EXISTS repo_access_requests_singleflight ON repo_access_requests(user_id, repo_pattern) WHERE reviewed_at IS NULL;
+
+-- Explicit read access of a user to a repository, granted by approving a repo access request. A
+-- grant with a NULL expires_at never expires.
+CREATE TABLE IF NOT EXISTS repo_access_grants (
+    user_id           INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_id           INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    access_request_id BIGINT REFERENCES repo_access_requests(id) ON DELETE SET NULL,
+    expires_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, repo_id)
+);
+
+CREATE INDEX IF NOT EXISTS repo_access_grants_expires_at ON repo_access_grants(expires_at) WHERE expires_at IS NOT NULL;
+
+COMMIT;
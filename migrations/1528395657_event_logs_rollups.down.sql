@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS event_logs_rollup_state;
+DROP TABLE IF EXISTS event_logs_weekly_values;
+DROP TABLE IF EXISTS event_logs_daily_values;
+DROP TABLE IF EXISTS event_logs_weekly_users;
+DROP TABLE IF EXISTS event_logs_daily_users;
+
+COMMIT;
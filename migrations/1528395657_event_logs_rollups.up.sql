@@ -0,0 +1,38 @@
+BEGIN;
+
+-- Rollups of event_logs that the usage statistics are computed from. The *_users tables have a row
+-- per (period, event name, source, user) with the number of events, and the *_values tables have
+-- a row per (period, event name, source, argument field, value) with the number of events with
+-- that value. Each period is named by its start time.
+CREATE TABLE IF NOT EXISTS event_logs_daily_users (
+    period     TIMESTAMPTZ NOT NULL,
+    name       TEXT NOT NULL,
+    source     TEXT NOT NULL,
+    user_key   TEXT NOT NULL,
+    registered BOOLEAN NOT NULL,
+    count      INTEGER NOT NULL,
+    PRIMARY KEY (period, name, source, user_key, registered)
+);
+
+CREATE TABLE IF NOT EXISTS event_logs_weekly_users (LIKE event_logs_daily_users INCLUDING ALL);
+
+CREATE TABLE IF NOT EXISTS event_logs_daily_values (
+    period TIMESTAMPTZ NOT NULL,
+    name   TEXT NOT NULL,
+    source TEXT NOT NULL,
+    field  TEXT NOT NULL,
+    value  DOUBLE PRECISION NOT NULL,
+    count  INTEGER NOT NULL,
+    PRIMARY KEY (period, name, source, field, value)
+);
+
+CREATE TABLE IF NOT EXISTS event_logs_weekly_values (LIKE event_logs_daily_values INCLUDING ALL);
+
+-- The rollups contain exactly the events with an ID less than or equal to rolled_up_id.
+CREATE TABLE IF NOT EXISTS event_logs_rollup_state (
+    rolled_up_id BIGINT NOT NULL
+);
+
+INSERT INTO event_logs_rollup_state (rolled_up_id) VALUES (0);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS snippet_share_link_views;
+DROP TABLE IF EXISTS snippet_share_links;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+-- Links that let anyone who knows the secret token view a range of lines of a file at a fixed
+-- commit without authentication. Only the SHA-256 hash of the token is stored. A link is inactive
+-- once it is revoked, has expired, or has been viewed max_views times (if max_views is set).
+CREATE TABLE IF NOT EXISTS snippet_share_links (
+    id                 BIGSERIAL PRIMARY KEY,
+    token_sha256       BYTEA NOT NULL UNIQUE,
+    creator_user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_id            INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id          TEXT NOT NULL CHECK (char_length(commit_id) = 40),
+    path               TEXT NOT NULL CHECK (path <> ''),
+    start_line         INTEGER NOT NULL CHECK (start_line >= 1),
+    end_line           INTEGER NOT NULL CHECK (end_line >= start_line),
+    password_hash      TEXT,
+    max_views          INTEGER CHECK (max_views >= 1),
+    view_count         INTEGER NOT NULL DEFAULT 0,
+    expires_at         TIMESTAMPTZ NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at         TIMESTAMPTZ,
+    revoker_user_id    INTEGER REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS snippet_share_links_creator_user_id ON snippet_share_links(creator_user_id);
+
+-- The audit log of all attempts to view a snippet share link.
+CREATE TABLE IF NOT EXISTS snippet_share_link_views (
+    id          BIGSERIAL PRIMARY KEY,
+    link_id     BIGINT NOT NULL REFERENCES snippet_share_links(id) ON DELETE CASCADE,
+    outcome     TEXT NOT NULL CHECK (outcome IN ('viewed', 'wrong_password', 'revoked', 'expired', 'view_limit_reached')),
+    remote_addr TEXT NOT NULL,
+    user_agent  TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS snippet_share_link_views_link_id ON snippet_share_link_views(link_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS coverage_files;
+DROP TABLE IF EXISTS coverage_reports;
+
+COMMIT;
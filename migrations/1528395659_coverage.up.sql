@@ -0,0 +1,32 @@
+BEGIN;
+
+-- Code coverage reports uploaded for a commit of a repository. Several reports (e.g., one per
+-- language) may be uploaded for the same commit; their files are merged into a single report.
+-- lines_found and lines_hit are the totals of the report's files.
+CREATE TABLE IF NOT EXISTS coverage_reports (
+    id          BIGSERIAL PRIMARY KEY,
+    repo_id     INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id   TEXT NOT NULL CHECK (char_length(commit_id) = 40),
+    lines_found INTEGER NOT NULL DEFAULT 0,
+    lines_hit   INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (repo_id, commit_id)
+);
+
+CREATE INDEX IF NOT EXISTS coverage_reports_repo_id_created_at ON coverage_reports(repo_id, created_at DESC);
+
+-- The per-line coverage of a file of a coverage report. The instrumented lines are encoded in the
+-- lines column as pairs of varints (the line number delta and the hit count); see
+-- coverage.EncodeLines.
+CREATE TABLE IF NOT EXISTS coverage_files (
+    report_id   BIGINT NOT NULL REFERENCES coverage_reports(id) ON DELETE CASCADE,
+    path        TEXT NOT NULL CHECK (path <> ''),
+    format      TEXT NOT NULL,
+    lines       BYTEA NOT NULL,
+    lines_found INTEGER NOT NULL,
+    lines_hit   INTEGER NOT NULL,
+    PRIMARY KEY (report_id, path)
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS slack_user_links;
+
+COMMIT;
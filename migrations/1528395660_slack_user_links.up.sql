@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Links between Slack users and Sourcegraph users, created when a Slack user links their account
+-- with OAuth. Slash commands and link unfurling of a Slack user run as the linked Sourcegraph user.
+CREATE TABLE IF NOT EXISTS slack_user_links (
+    team_id       TEXT NOT NULL,
+    slack_user_id TEXT NOT NULL,
+    user_id       INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_id, slack_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS slack_user_links_user_id ON slack_user_links(user_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE lsif_uploads DROP COLUMN IF EXISTS upload_size;
+ALTER TABLE external_services DROP COLUMN IF EXISTS namespace_org_id;
+DROP TABLE IF EXISTS quotas;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+-- Resource quotas. A row with an org_id or user_id overrides the quota of that organization or
+-- user, and the row with neither is the default quota of all other organizations and users. A NULL
+-- limit means that the resource is not limited.
+CREATE TABLE IF NOT EXISTS quotas (
+    id                             SERIAL PRIMARY KEY,
+    org_id                         INTEGER REFERENCES orgs(id) ON DELETE CASCADE,
+    user_id                        INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    max_repositories               INTEGER,
+    max_saved_search_notifications INTEGER,
+    max_campaigns                  INTEGER,
+    max_changesets                 INTEGER,
+    max_lsif_upload_bytes          BIGINT,
+    max_access_tokens              INTEGER,
+    created_at                     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at                     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT quotas_single_namespace CHECK (org_id IS NULL OR user_id IS NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS quotas_org_id ON quotas(org_id) WHERE org_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS quotas_user_id ON quotas(user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS quotas_default ON quotas((true)) WHERE org_id IS NULL AND user_id IS NULL;
+
+-- The organization whose quota the repositories of an external service count against.
+ALTER TABLE external_services ADD COLUMN IF NOT EXISTS namespace_org_id INTEGER REFERENCES orgs(id) ON DELETE SET NULL;
+
+-- The size of an LSIF upload, recorded by the frontend when the upload is accepted.
+ALTER TABLE lsif_uploads ADD COLUMN IF NOT EXISTS upload_size BIGINT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS watch_feed_entries;
+DROP TABLE IF EXISTS watch_repo_heads;
+DROP TABLE IF EXISTS watches;
+
+COMMIT;
@@ -0,0 +1,40 @@
+BEGIN;
+
+-- A user's watch on a repository, or on the paths of a repository matching a glob pattern (an
+-- empty path_pattern watches the whole repository).
+CREATE TABLE IF NOT EXISTS watches (
+    id           SERIAL PRIMARY KEY,
+    user_id      INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_id      INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    path_pattern TEXT NOT NULL DEFAULT '',
+    email_digest BOOLEAN NOT NULL DEFAULT false,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS watches_user_id_repo_id_path_pattern ON watches(user_id, repo_id, path_pattern);
+CREATE INDEX IF NOT EXISTS watches_repo_id ON watches(repo_id);
+
+-- The last head commit of each watched repository's default branch that new commits were computed
+-- for.
+CREATE TABLE IF NOT EXISTS watch_repo_heads (
+    repo_id    INTEGER PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id  TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The activity feed: commits that changed files matching a watch.
+CREATE TABLE IF NOT EXISTS watch_feed_entries (
+    id          BIGSERIAL PRIMARY KEY,
+    watch_id    INTEGER NOT NULL REFERENCES watches(id) ON DELETE CASCADE,
+    commit_id   TEXT NOT NULL,
+    subject     TEXT NOT NULL,
+    author_name TEXT NOT NULL,
+    paths       TEXT[] NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    emailed_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS watch_feed_entries_watch_id_commit_id ON watch_feed_entries(watch_id, commit_id);
+CREATE INDEX IF NOT EXISTS watch_feed_entries_created_at ON watch_feed_entries(created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS repo_pull_request_syncs;
+DROP TABLE IF EXISTS repo_pull_request_commits;
+DROP TABLE IF EXISTS repo_pull_requests;
+
+COMMIT;
@@ -0,0 +1,36 @@
+BEGIN;
+
+-- The merged pull requests (or merge requests) of repositories on code hosts, as indexed by
+-- repo-updater.
+CREATE TABLE IF NOT EXISTS repo_pull_requests (
+    id              BIGSERIAL PRIMARY KEY,
+    repo_id         INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    number          BIGINT NOT NULL,
+    title           TEXT NOT NULL,
+    url             TEXT NOT NULL,
+    merge_commit_id TEXT,
+    merged_at       TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS repo_pull_requests_repo_id_number ON repo_pull_requests(repo_id, number);
+
+-- The commits that a merged pull request introduced: its merge commit and its head commits.
+CREATE TABLE IF NOT EXISTS repo_pull_request_commits (
+    pull_request_id BIGINT NOT NULL REFERENCES repo_pull_requests(id) ON DELETE CASCADE,
+    repo_id         INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id       TEXT NOT NULL,
+    PRIMARY KEY (pull_request_id, commit_id)
+);
+
+CREATE INDEX IF NOT EXISTS repo_pull_request_commits_repo_id_commit_id ON repo_pull_request_commits(repo_id, commit_id);
+
+-- The time up to which the merged pull requests of each repository were indexed (i.e., the last
+-- update time of the most recently updated pull request).
+CREATE TABLE IF NOT EXISTS repo_pull_request_syncs (
+    repo_id      INTEGER PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    synced_until TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMIT;
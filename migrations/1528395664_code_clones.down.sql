@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS clone_fingerprints;
+DROP TABLE IF EXISTS clone_index_files;
+DROP TABLE IF EXISTS clone_index_repos;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+-- The repositories whose default branch is indexed for code clone detection, and the indexed commit.
+CREATE TABLE IF NOT EXISTS clone_index_repos (
+    repo_id    INTEGER PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id  TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The indexed files of repositories (only those that have fingerprints).
+CREATE TABLE IF NOT EXISTS clone_index_files (
+    id      BIGSERIAL PRIMARY KEY,
+    repo_id INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    path    TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS clone_index_files_repo_id_path ON clone_index_files(repo_id, path);
+
+-- The winnowed fingerprints of the indexed files (see package internal/clones).
+CREATE TABLE IF NOT EXISTS clone_fingerprints (
+    file_id    BIGINT NOT NULL REFERENCES clone_index_files(id) ON DELETE CASCADE,
+    hash       BIGINT NOT NULL,
+    start_line INTEGER NOT NULL,
+    end_line   INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS clone_fingerprints_file_id ON clone_fingerprints(file_id);
+CREATE INDEX IF NOT EXISTS clone_fingerprints_hash ON clone_fingerprints(hash);
+
+COMMIT;
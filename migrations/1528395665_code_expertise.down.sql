@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS code_expertise_lines;
+DROP TABLE IF EXISTS code_expertise_repos;
+DROP TABLE IF EXISTS repo_pull_request_reviewers;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+-- The users (other than the author) who reviewed the merged pull requests of repositories, as
+-- indexed by repo-updater. The account ID is the ID of the user's account on the code host.
+CREATE TABLE IF NOT EXISTS repo_pull_request_reviewers (
+    pull_request_id BIGINT NOT NULL REFERENCES repo_pull_requests(id) ON DELETE CASCADE,
+    account_id      TEXT NOT NULL,
+    login           TEXT NOT NULL,
+    PRIMARY KEY (pull_request_id, account_id)
+);
+
+-- Sync all merged pull requests again, so that the reviewers of those that were already indexed
+-- are indexed too.
+DELETE FROM repo_pull_request_syncs;
+
+-- The repositories whose default branch is indexed for code expertise, and the indexed commit.
+CREATE TABLE IF NOT EXISTS code_expertise_repos (
+    repo_id    INTEGER PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id  TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The number of lines that each indexed commit added to the files directly in each directory
+-- ("" for the root directory). Author emails are lowercase.
+CREATE TABLE IF NOT EXISTS code_expertise_lines (
+    repo_id      INTEGER NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id    TEXT NOT NULL,
+    dir          TEXT NOT NULL,
+    author_name  TEXT NOT NULL,
+    author_email TEXT NOT NULL,
+    authored_at  TIMESTAMPTZ NOT NULL,
+    lines        INTEGER NOT NULL,
+    PRIMARY KEY (repo_id, commit_id, dir)
+);
+
+CREATE INDEX IF NOT EXISTS code_expertise_lines_repo_id_dir ON code_expertise_lines(repo_id, dir text_pattern_ops);
+
+COMMIT;
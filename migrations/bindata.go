@@ -74,6 +74,30 @@
 // 1528395652_add_lsif_indexer.up.sql (611B)
 // 1528395653_repo_normalize_visibility_metadata.down.sql (65B)
 // 1528395653_repo_normalize_visibility_metadata.up.sql (1.035kB)
+// 1528395654_sub_repo_path_rules.down.sql (59B)
+// 1528395654_sub_repo_path_rules.up.sql (847B)
+// 1528395655_campaign_jobs_log.down.sql (70B)
+// 1528395655_campaign_jobs_log.up.sql (84B)
+// 1528395656_repo_access_requests.down.sql (101B)
+// 1528395656_repo_access_requests.up.sql (1.948kB)
+// 1528395657_event_logs_rollups.down.sql (247B)
+// 1528395657_event_logs_rollups.up.sql (1.402kB)
+// 1528395658_snippet_share_links.down.sql (106B)
+// 1528395658_snippet_share_links.up.sql (1.908kB)
+// 1528395659_coverage.down.sql (93B)
+// 1528395659_coverage.up.sql (1.389kB)
+// 1528395660_slack_user_links.down.sql (56B)
+// 1528395660_slack_user_links.up.sql (584B)
+// 1528395661_quotas.down.sql (176B)
+// 1528395661_quotas.up.sql (1.668kB)
+// 1528395662_watches.down.sql (127B)
+// 1528395662_watches.up.sql (1.672kB)
+// 1528395663_repo_pull_requests.down.sql (152B)
+// 1528395663_repo_pull_requests.up.sql (1.521kB)
+// 1528395664_code_clones.down.sql (138B)
+// 1528395664_code_clones.up.sql (1.16kB)
+// 1528395665_code_expertise.down.sql (153B)
+// 1528395665_code_expertise.up.sql (1.572kB)
 
 package migrations
 
@@ -1622,6 +1646,486 @@ func _1528395653_repo_normalize_visibility_metadataUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395654_sub_repo_path_rulesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3b\x00\xc4\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x75\x62\x5f\x72\x65\x70\x6f\x5f\x70\x61\x74\x68\x5f\x72\x75\x6c\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xa3\x76\xc3\x56\x3b\x00\x00\x00")
+
+func _1528395654_sub_repo_path_rulesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395654_sub_repo_path_rulesDownSql,
+		"1528395654_sub_repo_path_rules.down.sql",
+	)
+}
+
+func _1528395654_sub_repo_path_rulesDownSql() (*asset, error) {
+	bytes, err := _1528395654_sub_repo_path_rulesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395654_sub_repo_path_rules.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x54, 0x21, 0x4b, 0x39, 0x27, 0xcb, 0x26, 0xf0, 0xd2, 0x60, 0x78, 0x48, 0xf5, 0x3c, 0xa0, 0xf3, 0x71, 0x8f, 0x6a, 0x92, 0xdf, 0x27, 0xd8, 0xec, 0x33, 0xa5, 0x8, 0x12, 0xc0, 0x90, 0x1b, 0x4b}}
+	return a, nil
+}
+
+var __1528395654_sub_repo_path_rulesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x51\x5d\x6f\xda\x30\x14\x7d\xcf\xaf\x38\x6f\xa5\x52\xe1\x0f\xf0\xe4\x26\x97\x2a\x5a\x08\xcc\x31\x52\xbb\x69\x8a\x02\xb9\x05\x6b\xc1\xce\xec\xa4\x1d\x9a\xf6\xdf\xa7\x98\x8c\x22\x6d\x93\xba\x3c\xc5\xba\xf7\x7c\xdc\x73\xee\xe9\x21\xcd\xe7\x51\x34\x9d\x62\x5d\x75\x87\x69\xc3\x2f\xdc\xa0\x65\x77\xd4\xde\x6b\x6b\xe0\xfa\x86\x3d\x5e\x75\x77\xd0\x06\x15\x1c\xb7\xd6\xeb\xce\xba\xd3\x2c\x00\x3c\x7a\x53\xb3\x43\x5b\x75\x87\xb2\x75\xfc\xac\xbf\xa3\x72\x0c\x6b\x9a\x13\x1c\x57\x75\xb5\x6d\x18\xdb\xd3\x20\xd0\x1d\x18\xbd\x67\xe7\xa1\x4d\xf8\x29\x75\xed\x51\x99\x3a\x4c\x8e\x7c\xdc\x0e\x33\xfb\x1c\x9e\xd6\xed\xc3\x9e\x75\xfb\x61\x6d\x06\x79\x31\x82\xc6\x9a\xfd\xa0\x19\xe4\xd8\xa3\xab\xbe\xf2\x20\xd0\x3a\xde\x71\xcd\x66\xc7\xb0\x2f\xec\xae\xbc\xc3\x1f\xac\xeb\xd8\xc1\x1a\xf6\xb3\x28\x96\x24\x14\x41\x89\xfb\x8c\x90\x2e\x90\xaf\x14\xe8\x31\x2d\x54\x01\xdf\x6f\xcb\xe1\xc8\x32\x5c\x74\x66\x98\x44\x00\xa0\x6b\x5c\xbe\x82\x64\x2a\x32\xac\x65\xba\x14\xf2\x09\x1f\xe8\xe9\x2e\xec\x04\xe4\xb8\x98\xe6\x8a\x1e\x48\x06\xf2\x7c\x93\x65\x90\xb4\x20\x49\x79\x4c\x45\x88\x71\xa2\xeb\x5b\xac\x72\x24\x94\x91\x22\xc4\xa2\x88\x45\x42\x67\x9e\xeb\x38\x15\x3d\xaa\x0b\xc9\x79\x7c\x09\xef\x4d\xe6\xf3\x97\x37\xa1\x84\x16\x62\x93\x29\xdc\xfc\xf8\x79\x73\x06\x8c\x29\xe2\xdd\x80\x9d\xe3\xaa\xe3\xba\xac\x3a\x40\xa5\x4b\x2a\x94\x58\xae\xd5\xa7\x3f\x21\xc6\xbe\x4e\x6e\x47\x57\x6d\xfd\x1f\x98\xe8\x76\x1e\x45\x22\x53\x24\xc7\x1e\xfe\x92\x7c\xa0\x4d\xe4\x6a\x8d\x78\x95\x17\x4a\x8a\x34\x57\x43\x5f\xff\xee\x6a\x7c\x87\xe4\xca\xde\xe8\x6f\x3d\x9f\xcd\x89\x24\xb9\x26\x79\x1f\x34\xfa\xdd\xf7\x26\x4f\x3f\x6e\x08\x93\xb1\xdf\xbb\xeb\x82\x86\x3b\xe2\xd5\x72\x99\xaa\x79\xf4\x6b\x00\xff\x35\xbd\x0b\x4f\x03\x00\x00")
+
+func _1528395654_sub_repo_path_rulesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395654_sub_repo_path_rulesUpSql,
+		"1528395654_sub_repo_path_rules.up.sql",
+	)
+}
+
+func _1528395654_sub_repo_path_rulesUpSql() (*asset, error) {
+	bytes, err := _1528395654_sub_repo_path_rulesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395654_sub_repo_path_rules.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xaa, 0x37, 0x92, 0xdb, 0xc0, 0xb3, 0x83, 0x6c, 0xa1, 0x4b, 0x8f, 0xbf, 0xc3, 0xcd, 0x46, 0x45, 0x3b, 0xc1, 0x0, 0xec, 0xb0, 0x99, 0x58, 0xf7, 0x86, 0x7, 0x11, 0xc1, 0x88, 0x11, 0x3, 0xe8}}
+	return a, nil
+}
+
+var __1528395655_campaign_jobs_logDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x46\x00\xb9\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x5f\x6a\x6f\x62\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x6f\x67\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x2e\xe4\x1d\xcc\x46\x00\x00\x00")
+
+func _1528395655_campaign_jobs_logDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395655_campaign_jobs_logDownSql,
+		"1528395655_campaign_jobs_log.down.sql",
+	)
+}
+
+func _1528395655_campaign_jobs_logDownSql() (*asset, error) {
+	bytes, err := _1528395655_campaign_jobs_logDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395655_campaign_jobs_log.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2b, 0x21, 0xae, 0x30, 0xca, 0xea, 0xc3, 0xc8, 0xb2, 0x1c, 0x82, 0x82, 0xf6, 0xda, 0xe9, 0x4a, 0x22, 0x82, 0x35, 0xc7, 0x15, 0xe, 0x50, 0xe9, 0x63, 0x87, 0x5f, 0x9b, 0x61, 0xe2, 0x14, 0xd2}}
+	return a, nil
+}
+
+var __1528395655_campaign_jobs_logUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x54\x00\xab\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x5f\x6a\x6f\x62\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x6c\x6f\x67\x20\x74\x65\x78\x74\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x27\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xca\x5e\xcc\x25\x54\x00\x00\x00")
+
+func _1528395655_campaign_jobs_logUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395655_campaign_jobs_logUpSql,
+		"1528395655_campaign_jobs_log.up.sql",
+	)
+}
+
+func _1528395655_campaign_jobs_logUpSql() (*asset, error) {
+	bytes, err := _1528395655_campaign_jobs_logUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395655_campaign_jobs_log.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1d, 0x68, 0xfe, 0xf5, 0x5a, 0x16, 0xa2, 0x4, 0xc8, 0x28, 0xa5, 0x6f, 0x27, 0x87, 0x18, 0x42, 0x45, 0x1f, 0x38, 0xd8, 0xb6, 0xcc, 0x53, 0xad, 0xa9, 0x6a, 0x71, 0xfc, 0xeb, 0x30, 0xae, 0xec}}
+	return a, nil
+}
+
+var __1528395656_repo_access_requestsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x65\x00\x9a\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x61\x63\x63\x65\x73\x73\x5f\x67\x72\x61\x6e\x74\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x61\x63\x63\x65\x73\x73\x5f\x72\x65\x71\x75\x65\x73\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x7a\xd0\x7e\xcb\x65\x00\x00\x00")
+
+func _1528395656_repo_access_requestsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395656_repo_access_requestsDownSql,
+		"1528395656_repo_access_requests.down.sql",
+	)
+}
+
+func _1528395656_repo_access_requestsDownSql() (*asset, error) {
+	bytes, err := _1528395656_repo_access_requestsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395656_repo_access_requests.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x76, 0x67, 0x8f, 0x43, 0xec, 0xbe, 0x1b, 0x9d, 0x31, 0x31, 0x18, 0x14, 0xbd, 0x4c, 0x78, 0x8f, 0xdb, 0xcb, 0x54, 0x5b, 0xb6, 0x8f, 0xbc, 0x59, 0xcd, 0xd8, 0xff, 0x2d, 0xf6, 0xe, 0xfe, 0xae}}
+	return a, nil
+}
+
+var __1528395656_repo_access_requestsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x54\xed\x6e\xe2\x48\x10\xfc\xef\xa7\xa8\x7f\x98\x13\xc9\x3d\x00\xa7\x93\x0c\x4c\xc8\x28\x60\x72\xb6\xd1\x25\x77\x3a\xa1\x89\xdd\xe0\x39\x81\xcd\xce\x4c\xe2\x44\xab\x7d\xf7\xd5\xf8\x03\x0c\xce\xe6\x43\xbb\xfe\xe9\xee\xae\xae\xae\xee\xa9\x11\x9b\x72\x7f\xe8\x38\x17\x17\x08\xe8\xcb\x23\x69\xa3\xf1\xf0\x82\x47\x4d\x4a\x63\x9d\x2b\x28\x12\x09\x44\x1c\x93\xd6\x30\x39\x4c\x4a\x50\xb4\xcf\xb5\x34\xb9\x92\xa4\x51\xa4\xb9\x26\x64\x62\x47\x1a\x3b\x61\xe2\xb4\x0c\xaf\xf6\xc2\x18\x52\x19\x5c\x61\xa1\x0f\x15\x2f\x65\xe6\x00\xb9\x82\xc0\x66\x9b\x3f\xa0\x49\x94\x19\x8a\x54\xc6\x29\x7e\xab\x60\x48\x43\x64\x2f\xd0\x96\x54\x16\x13\xf2\x35\xe2\x54\x28\x11\x1b\xcb\x2c\x37\x29\x29\x98\x54\x64\xf8\xbd\x7f\x69\x5b\x88\xfd\x5e\xe5\x4f\xa4\x56\x96\xfa\x4a\x26\x1a\x42\x51\x49\xd7\xfe\x28\x79\xa2\x20\x45\xc8\x72\x23\xd7\x92\x12\x8b\x68\xc3\xca\x76\xd0\x06\x45\x4a\x19\xa4\x41\x21\x34\x62\x45\xc2\x50\x72\xe9\x8c\x03\xe6\x45\x0c\x91\x37\x9a\x31\xf0\x2b\xf8\x8b\x08\xec\x8e\x87\x51\x58\x8d\x59\x09\xb3\xaa\x31\x34\x5c\x07\x00\x64\x82\xb3\x6f\xc4\xa7\x21\x0b\xb8\x37\xc3\x6d\xc0\xe7\x5e\x70\x8f\x1b\x76\x3f\x28\x93\x6b\xbe\x4d\x26\x00\xee\x47\x6c\xca\x82\xb2\x99\xbf\x9c\xcd\x10\xb0\x2b\x16\x30\x7f\xcc\xc2\x6a\x31\xae\x4c\xfa\x58\xf8\x98\xb0\x19\x8b\x18\xc6\x5e\x38\xf6\x26\xac\x82\x3b\x51\xdf\xfe\x40\xc4\xee\xa2\x23\xd6\xf8\x9a\x8d\x6f\xe0\x9e\xa4\xfd\xf1\x27\x7a\xbd\x7e\x55\xff\xff\xa3\xb6\xfa\xc4\xc2\xc8\x3c\xeb\xd6\x57\x49\x5d\xb1\x6b\xce\xff\xfe\x77\xec\x34\x61\x57\xde\x72\x16\xa1\xf7\xf5\x5b\xaf\x2a\xab\x65\x5d\x09\xd3\x8c\x1a\xf1\x39\x0b\x23\x6f\x7e\x1b\xfd\xd3\x2d\xcc\xf2\xc2\xad\x59\x29\x7a\x92\x54\x1c\x1b\x1e\x45\x7a\x47\x9b\x90\xb5\x79\xd7\x30\x2d\x06\x2d\x02\x27\xa3\xb5\x36\x38\x5a\x2c\x66\xcc\xf3\xdb\x08\xab\x38\xdf\xed\x28\x33\x5d\x79\x0f\x43\xd7\x23\xd3\xf3\x5e\x2a\xd2\xaf\x8f\xec\xf4\x87\x4e\x73\x62\xdc\x9f\xb0\xbb\x0f\x9c\xd8\x41\x81\x85\xff\x6a\xdc\xad\xe3\xfd\xe1\xa7\x91\xbb\x5b\xfd\x41\x0f\x2c\x43\xee\x4f\x31\xe5\x3e\xdc\x4e\xd1\xb1\xf1\xd2\xe7\x7f\x2d\x3f\xd1\x5f\xcb\x6c\xb3\xa5\xf5\x56\x6e\x52\xf3\xde\x78\x83\x93\x43\xef\xe3\xef\x6b\x16\xb0\x93\xfd\xf2\xb0\x5c\x7c\xe5\x6d\xec\x79\xbf\x95\xb1\x34\x27\x76\x96\xaf\x21\xca\xa3\xb1\xc6\x26\x5a\x26\x35\xc0\x46\x89\xcc\x50\x62\xad\xb0\x1a\x50\x66\x9b\x3a\xa5\xa9\xae\x19\x5d\xc2\xb3\x0d\xca\x02\x14\xd2\xa4\x10\x65\xdf\xf6\xe6\x33\x7a\x22\xd5\xfc\xf8\xb0\xa9\x94\x90\x8d\xa5\xd4\x63\xff\x52\x97\xf8\x30\x9c\xcd\x7e\x0b\xed\x74\x47\x96\xe6\x88\x4f\xb9\x1f\x9d\x63\x74\x96\xf9\xd6\x5b\x6d\xe9\xd7\x7d\x39\x3f\xeb\x27\x2d\x17\xc6\xd9\x49\xc9\xa4\xff\x99\x77\x59\x6d\x69\xd5\x62\x7b\x76\xb9\x55\x82\x7b\x4c\x68\x8e\xb5\x55\xc2\xc3\x03\xdb\xa1\xe3\x8c\x17\xf3\x39\x8f\x86\xce\xf7\x01\x00\x80\xe5\x1e\x4f\x9c\x07\x00\x00")
+
+func _1528395656_repo_access_requestsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395656_repo_access_requestsUpSql,
+		"1528395656_repo_access_requests.up.sql",
+	)
+}
+
+func _1528395656_repo_access_requestsUpSql() (*asset, error) {
+	bytes, err := _1528395656_repo_access_requestsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395656_repo_access_requests.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3a, 0x3a, 0x98, 0x60, 0xf6, 0x79, 0x2d, 0x89, 0x43, 0x16, 0x9f, 0x0, 0x2f, 0x9e, 0xb1, 0x87, 0xf3, 0x7, 0xca, 0x3a, 0xb2, 0x1b, 0xcb, 0xac, 0xab, 0xf4, 0x10, 0xa8, 0x66, 0x8a, 0x45, 0xcc}}
+	return a, nil
+}
+
+var __1528395657_event_logs_rollupsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2d\x4b\xcd\x2b\x89\xcf\xc9\x4f\x2f\x8e\x2f\xca\xcf\xc9\x29\x2d\x88\x2f\x2e\x49\x2c\x49\xb5\x26\xa8\xba\x3c\x35\x35\x3b\xa7\x32\xbe\x2c\x31\xa7\x34\xb5\x98\xb0\xf2\x94\xc4\x4c\x12\x54\x43\x0d\x2f\x2d\x4e\x2d\x22\xda\x6c\xa8\x62\x2e\x67\x7f\x5f\x5f\xcf\x10\x6b\x2e\xc0\x00\x7a\x2c\x1b\x7c\xf7\x00\x00\x00")
+
+func _1528395657_event_logs_rollupsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395657_event_logs_rollupsDownSql,
+		"1528395657_event_logs_rollups.down.sql",
+	)
+}
+
+func _1528395657_event_logs_rollupsDownSql() (*asset, error) {
+	bytes, err := _1528395657_event_logs_rollupsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395657_event_logs_rollups.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x48, 0x11, 0xf, 0x9a, 0x58, 0x6b, 0x96, 0xa8, 0x48, 0x64, 0x2, 0xc7, 0xe9, 0xb0, 0xa9, 0x7c, 0x29, 0x8, 0x86, 0x48, 0xb7, 0x61, 0xca, 0x88, 0xf7, 0xc3, 0xc0, 0xfc, 0xad, 0x73, 0xff, 0x9f}}
+	return a, nil
+}
+
+var __1528395657_event_logs_rollupsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x54\x4f\x4f\xe3\x3e\x14\xbc\xe7\x53\xcc\x91\xa2\x14\xfd\xee\x9c\xd2\xe2\x5f\x65\x91\x26\x28\x75\x57\xb0\x97\xc8\x24\x8f\xd6\x22\x8d\xb3\xfe\x03\xdb\x6f\xbf\xb2\x29\x4b\x81\x76\xe9\xee\xa9\x55\xe6\xbd\x19\x7b\xc6\xef\x4d\xd8\x8c\x17\x97\x49\x32\x1e\xa3\xd2\x5d\xe7\x07\x0b\xfd\x00\x7a\xa2\xde\xd5\x9d\x5e\x59\xb8\xb5\x74\x70\x6b\x82\xb7\x72\x45\xb0\x4e\x3a\x65\x9d\x6a\x2c\xa4\x21\x34\x7a\x33\x78\x47\x2d\x1e\x8c\xde\x5c\x40\xac\x09\xe7\xb5\xb7\x64\x2c\x9c\xbc\xef\xc8\x62\x2d\x9f\x08\x12\x46\x3f\x07\x8d\x81\x0c\xce\x06\x32\x4a\xb7\xe9\x8b\x0a\x7a\xb9\xa1\x14\x56\x7b\xd3\x50\x8a\xd0\x3b\xc2\xb3\x72\xeb\x28\xda\xfb\xcd\x3d\x99\xdf\x47\xb2\x29\x64\xdf\x46\xe4\xbc\x7e\x92\x9d\xa7\x77\x3a\x41\x21\x4a\x7d\xad\x23\xcd\xca\x6f\xc2\xe7\x07\x45\x5d\x9b\x22\x92\xfd\x41\x38\x22\x81\x3f\xfa\x11\xab\x2f\xc0\x64\xb3\x0e\x52\x4a\xb7\x50\x36\xde\xa4\xc5\xfd\x16\xca\xd9\x60\x94\x71\x70\x6a\x43\x17\xc9\xb4\x62\x99\x60\x10\xd9\x24\x67\xe0\xff\xa3\x28\x05\xd8\x2d\x5f\x88\xc5\x9e\xd3\x75\x2b\x55\xb7\xdd\x99\x77\x96\x00\x78\xa5\x0e\x7f\x05\x9f\xb3\x85\xc8\xe6\x37\xe2\x7b\x6c\x2f\x96\x79\x9e\xc6\xa2\xa0\x1a\x7e\x43\x11\xbb\x15\x1f\xd0\x97\xfb\x1e\x43\x83\x58\xfd\x48\xdb\xc3\xa8\xa1\x95\xb2\x8e\x0c\xb5\x98\x94\x65\xce\xb2\xe2\x43\x41\xa3\x7d\xef\x42\x25\xc0\x0b\xc1\x66\xac\xfa\x50\x70\x53\xf1\x79\x56\xdd\xe1\x9a\xdd\xbd\xc5\xf1\x39\xf0\x70\x84\x74\x4f\x6e\x94\x8c\x2e\x93\x13\x5d\x7b\x26\x7a\x7c\xb3\x2d\xe7\xd7\xec\x98\xa7\xbc\x98\xe6\xcb\x2b\x5e\xcc\x90\xe5\xf9\xe9\x02\x2f\x14\xbb\xc7\xf6\x2e\x97\x2f\x33\x39\x9e\xc7\x01\x24\x3e\xc4\x83\x3d\x51\x1b\xb8\x2a\x97\xc1\x89\x9b\x8a\x4d\xf9\x82\x97\x47\xc2\xf8\xe7\x20\xde\x0d\xc2\xdf\x07\xf0\x6a\xd0\xe1\x04\x76\xe8\xa7\x08\xc6\xe3\xb8\x33\xcc\x6e\xf5\x34\xba\x77\x52\xf5\xa0\x9f\xb2\x71\xdd\x36\x4e\xe2\xde\xfc\x41\xf6\xe0\x57\xe8\xc8\xc6\xbd\xd4\x43\x1b\xd0\x0f\x2f\x3b\x38\x1d\x39\xa8\xad\xfd\x50\xab\xf6\xd4\x91\x0b\x3d\x7e\xa8\xc3\x52\xa3\x5d\xb6\xfb\x34\x98\xf0\x19\x2f\xde\xe2\x88\xae\xf0\x62\xc1\x2a\x11\x1e\x7c\x79\x9c\x6a\x9f\x65\x84\x6f\x59\xbe\x64\x0b\x9c\xfd\x17\xfa\xa7\xe5\x7c\xce\xc5\x65\xf2\x6b\x00\x2c\x49\x58\x3b\x7a\x05\x00\x00")
+
+func _1528395657_event_logs_rollupsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395657_event_logs_rollupsUpSql,
+		"1528395657_event_logs_rollups.up.sql",
+	)
+}
+
+func _1528395657_event_logs_rollupsUpSql() (*asset, error) {
+	bytes, err := _1528395657_event_logs_rollupsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395657_event_logs_rollups.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x83, 0xd, 0xb9, 0x8, 0xfa, 0xf3, 0xad, 0x7e, 0xbb, 0x7f, 0x43, 0xf4, 0x20, 0x12, 0x18, 0x82, 0xe1, 0x3d, 0x8, 0x28, 0x4, 0x9d, 0x2b, 0xf, 0xbf, 0x90, 0x7c, 0x2c, 0x3c, 0xae, 0xc4, 0x7d}}
+	return a, nil
+}
+
+var __1528395658_snippet_share_linksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6a\x00\x95\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x6e\x69\x70\x70\x65\x74\x5f\x73\x68\x61\x72\x65\x5f\x6c\x69\x6e\x6b\x5f\x76\x69\x65\x77\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x6e\x69\x70\x70\x65\x74\x5f\x73\x68\x61\x72\x65\x5f\x6c\x69\x6e\x6b\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x7b\x99\xd4\x2d\x6a\x00\x00\x00")
+
+func _1528395658_snippet_share_linksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395658_snippet_share_linksDownSql,
+		"1528395658_snippet_share_links.down.sql",
+	)
+}
+
+func _1528395658_snippet_share_linksDownSql() (*asset, error) {
+	bytes, err := _1528395658_snippet_share_linksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395658_snippet_share_links.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xaf, 0x24, 0xe, 0x31, 0xd7, 0x9a, 0x55, 0x9f, 0x16, 0xa3, 0x22, 0xb4, 0x6d, 0x70, 0x46, 0xf9, 0x95, 0x6a, 0xc2, 0x55, 0xc4, 0x84, 0xe8, 0x1a, 0x48, 0xf1, 0x2c, 0x26, 0xd1, 0xb7, 0xb3, 0x24}}
+	return a, nil
+}
+
+var __1528395658_snippet_share_linksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x54\x5d\x6f\xea\x38\x10\x7d\xe7\x57\xcc\x1b\x41\x2a\xd5\xdd\xd5\xee\xbe\x74\x5b\x29\x80\xcb\x8d\x2e\x84\xbb\xc4\x48\xed\xbe\x44\xde\x64\x4a\x2c\x12\x1b\xd9\x43\xe9\xfd\xf7\x2b\x27\x0e\x50\x3e\xd5\xf6\xc5\xb1\xcf\x9c\x33\xc3\x9c\x99\x01\x1b\x47\xf1\x43\xa7\xd3\xef\xc3\x44\xaa\x95\x05\x2a\x04\x41\x89\x04\x42\xfd\xd2\x0a\x61\x5b\x68\x58\x29\xbd\x75\x2f\x08\x16\x33\x83\x04\xa4\x57\xa8\xe0\x5d\xe2\x16\x04\x18\xa1\x96\x08\xfa\x0d\x4a\xa9\xd0\xba\x83\x80\x37\x59\x22\x08\xaa\x4f\x1f\x98\x3b\xfa\x4c\x57\x95\x24\xd8\x4a\x2a\xf4\x86\x40\x6c\xa8\x40\x45\x32\x13\x24\xb5\xba\x87\x99\x2a\x7f\xd5\x12\xc9\xf7\xb0\xff\xfb\x9f\x7f\x41\x21\x6c\xe1\xc8\xdc\x5d\xa3\x27\x2d\x58\xd2\x06\xf3\x7b\x08\x9d\xd8\x0a\xa4\x05\xa9\x44\x46\xf2\x1d\x9d\x84\x56\x19\x82\x24\x77\x6d\xf0\x5d\xaf\x30\xbf\x73\x34\x80\x1f\x6b\x69\xdc\x87\x36\xf5\xf7\x7f\xe8\xb3\xc7\x1c\x2a\xf1\x91\xba\x42\x2c\x90\xac\xd0\x42\x20\xdf\x0e\xee\x9c\x24\x52\xef\xbe\x33\x9c\xb3\x90\x33\xe0\xe1\x60\xc2\x20\x7a\x86\x78\xc6\x81\xbd\x44\x09\x4f\xc0\x2a\xb9\x5e\x23\xa5\xb6\x10\x06\x53\x97\x97\x85\xa0\x03\x00\x20\x73\x38\xfe\x1b\x44\xe3\x84\xcd\xa3\x70\x02\x3f\xe7\xd1\x34\x9c\xbf\xc2\x0f\xf6\x7a\x57\xa3\xeb\x22\x1d\x8b\xab\xde\xa3\x5f\x39\x0b\x6b\xad\x78\x31\x99\xc0\x22\x8e\xfe\x59\xb0\x06\x9d\x19\x14\xa4\x4d\xba\xb1\x68\xd2\x46\x28\x8a\x39\x1b\xb3\xf9\x1e\x3f\x67\xcf\x6c\xce\xe2\x21\x4b\xc0\xc1\x6c\x20\xf3\x1e\xcc\x62\x18\xb1\x09\xe3\x0c\x86\x61\x32\x0c\x47\x9e\xcf\xe0\x5a\x7b\x9e\xf6\xff\x1a\x9f\x83\x5f\xa3\x6b\xba\xfd\x89\x90\xb3\x17\xbe\xe7\x1a\x7e\x67\xc3\x1f\x10\x64\x85\x30\x69\x89\x6a\x49\x45\xb0\x8b\xe9\xc1\x23\xfc\xf1\xad\xd7\x30\xad\x05\x15\x2d\xc5\x35\xa6\x1a\xf7\xf7\x13\x74\xbb\x3e\xd0\x92\x30\xe4\xfa\x81\x97\x2b\xf2\xb1\x07\xd0\xa7\x47\xf8\xcd\x13\xa0\xca\x3f\x87\x5f\x26\xd8\x41\x9f\x1e\x61\x4f\xe6\x79\xd6\xc2\xda\xad\x36\x79\x5a\x5b\x7a\x57\x41\xf3\xb8\xf7\xda\x89\x88\xe7\xde\x23\x0e\x72\x73\x17\x69\xa6\x37\x8a\x4e\xc2\x76\xb9\x8d\xd8\x73\xb8\x98\x70\xf8\xe6\xcb\xa9\xa7\xc0\xa6\x62\x1f\xc2\xa3\x29\x4b\x78\x38\xfd\xc9\xff\xdd\x95\x74\x60\x2f\xcc\x6f\x81\x77\x1a\x4a\x6f\x03\x9f\x9a\x1f\xbd\x0b\xa1\x87\x98\xb3\xee\xbd\x61\xda\x84\x35\xca\x9d\xde\x43\xa7\x9d\xca\x28\x1e\xb1\x97\xdb\x53\x99\x1e\xcf\xcc\x2c\x3e\x07\x0b\x8e\x60\x4e\xa8\xdf\x07\x5e\x20\x88\x4d\x2e\x09\x4a\xbd\x74\x8b\x49\x94\x25\x08\x22\xac\xd6\x64\x81\x74\xbb\x0e\x3d\x23\xd4\xeb\xa0\x5e\x53\x5f\xdc\x1e\xbe\xd9\xa7\x2b\xe4\xca\xee\x70\x32\xed\xb0\x0d\xa2\x71\x14\xf3\xb3\x53\x7b\x2a\x76\x75\x27\xe8\x0d\x65\xba\xc2\xcb\x33\xd7\x02\xa2\x18\x82\xae\x4b\x1b\xf3\xee\x1d\x74\xb7\x46\xab\x65\xda\xda\xde\xdd\x78\x4f\xb8\xa3\x5f\xc6\xee\xe8\x22\xd2\x52\x56\x92\x52\x83\x22\x2b\x30\xef\xf6\x76\x26\xaa\x34\x61\x2a\xf2\xdc\x7c\x96\x6e\x9e\xeb\xd6\x88\x25\x2a\x3a\xca\xec\xd4\xbd\xb7\x6d\xfb\x75\x2b\x35\x2d\x4a\xdb\x5f\xfd\xac\x8f\x1a\x4c\xe0\x31\xb5\xc4\x6c\x3a\x8d\xf8\x43\xe7\xff\x01\x00\x29\x3f\x3b\xb7\x74\x07\x00\x00")
+
+func _1528395658_snippet_share_linksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395658_snippet_share_linksUpSql,
+		"1528395658_snippet_share_links.up.sql",
+	)
+}
+
+func _1528395658_snippet_share_linksUpSql() (*asset, error) {
+	bytes, err := _1528395658_snippet_share_linksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395658_snippet_share_links.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5a, 0x4e, 0x3d, 0x58, 0xc8, 0x80, 0x7a, 0x3d, 0x5b, 0x9d, 0xe1, 0x93, 0x40, 0xac, 0xe4, 0x4, 0xb7, 0x79, 0xf6, 0x26, 0x42, 0x47, 0x34, 0x9d, 0xff, 0x6b, 0x69, 0x63, 0x85, 0xcf, 0x69, 0x1e}}
+	return a, nil
+}
+
+var __1528395659_coverageDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5d\x00\xa2\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x6f\x76\x65\x72\x61\x67\x65\x5f\x66\x69\x6c\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x6f\x76\x65\x72\x61\x67\x65\x5f\x72\x65\x70\x6f\x72\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x15\xf7\x8e\x9e\x5d\x00\x00\x00")
+
+func _1528395659_coverageDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395659_coverageDownSql,
+		"1528395659_coverage.down.sql",
+	)
+}
+
+func _1528395659_coverageDownSql() (*asset, error) {
+	bytes, err := _1528395659_coverageDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395659_coverage.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6a, 0x71, 0x31, 0xc8, 0x62, 0x54, 0xa1, 0x8d, 0x7b, 0x6c, 0x4d, 0x20, 0x2a, 0xe, 0xd9, 0xe, 0xfe, 0x84, 0x26, 0xc1, 0x67, 0xf2, 0xf1, 0x40, 0xc4, 0xc7, 0x89, 0xc0, 0xcd, 0x57, 0xd0, 0x23}}
+	return a, nil
+}
+
+var __1528395659_coverageUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x53\x41\x6f\xe2\x3a\x18\xbc\xf3\x2b\xe6\x56\x90\x00\xf5\xf0\x6e\xbc\xf7\xa4\x10\x5c\x36\x2a\x84\x2e\x31\x52\xbb\x97\xc8\x4d\x3e\x12\x4b\x89\x8d\x1c\xa7\xab\xfe\xfb\x95\x9d\x90\xb2\x82\xdd\xee\x72\x22\xc9\xcc\x7c\x9f\xc7\x33\x4b\xb6\x8e\xe2\xc5\x68\x34\x9b\x21\xd4\x39\x21\xd3\x6f\x64\x44\x41\x30\x74\xd2\xc6\x36\x68\x4f\x95\x16\x39\xe5\x38\x6a\x03\x81\x4c\xd7\xb5\xb4\xd0\x47\x08\x0f\x69\xa4\xd5\xe6\x7d\x8e\x84\x1c\xaf\x1a\x68\x63\x9a\x17\xf3\x29\xb4\x22\x9c\xc8\x38\xf9\x4a\xa8\xa2\x15\x05\x4d\x50\x8b\x77\xbc\xd2\xcf\xca\xb6\x24\x34\xa2\xa6\x7e\xc0\x02\xb6\x24\x69\x70\x94\x15\x35\x10\x86\x50\x93\x29\x28\x87\x54\x56\x43\xa0\x91\xaa\xa8\xce\x4b\xce\xbd\xbc\x54\xd4\xa4\x47\xdd\xaa\x1c\x42\xe5\xfd\x73\x29\xad\x67\x3b\x79\xab\xad\xa8\x1a\xb7\xba\x7b\xea\xa8\x77\x4d\x37\x62\x3e\x0a\xf7\x2c\xe0\x0c\x3c\x58\x6e\x18\xa2\x07\xc4\x3b\x0e\xf6\x1c\x25\x3c\x19\x3c\x49\x87\xc3\x8d\x00\x40\xe6\x18\x7e\xcb\x68\x9d\xb0\x7d\x14\x6c\xf0\xb4\x8f\xb6\xc1\xfe\x05\x8f\xec\x65\xea\x61\x8e\x94\xf6\xd8\x28\xe6\x6c\xcd\xf6\x5e\x3c\x3e\x6c\x36\xd8\xb3\x07\xb6\x67\x71\xc8\x12\x7f\x96\xb1\xcc\x27\xd8\xc5\x58\xb1\x0d\xe3\x0c\x61\x90\x84\xc1\x8a\x75\x3a\x9d\x31\x9d\x12\x67\xcf\xfc\x43\x24\xfc\xc2\xc2\x47\x8c\xb3\x52\x98\xb4\x22\x55\xd8\x72\x3c\x80\x27\xf8\x0f\xff\xdc\x4f\x3a\x89\x4b\x8f\xae\x56\x59\xb1\x87\xe0\xb0\xe1\xb8\xbf\xc4\x3a\xff\xf0\x29\x36\x33\x24\x2c\xe5\xa9\xb0\x00\x8f\xb6\x2c\xe1\xc1\xf6\x89\x7f\xbb\xc6\x2b\xfd\x7d\xdc\xef\xd2\x9e\xf2\xbf\xe6\x1c\xe2\xe8\xeb\x81\x61\xdc\x5b\x3a\xfd\xf0\x64\x32\x9a\x2c\x46\xe7\x3b\x8c\xe2\x15\x7b\xfe\xe4\x0e\xd3\x5e\x23\xbd\xd8\x7d\x17\x5f\xc1\x2e\x46\x7d\xe0\x56\x2c\x09\xdd\xb8\xd9\x0c\xbc\xf4\xf9\x9e\x39\xb7\x06\xb2\x8b\x98\xf0\xb9\xea\xfe\x0d\xef\xfb\xb8\x7a\x96\x54\x8d\x35\x6d\x4d\xca\x52\x1f\x56\x1f\x54\x52\x99\x76\x9d\x90\xca\x35\x60\x08\x36\x32\x5d\xb5\xb5\x82\x68\x70\x12\xd2\x34\x4e\xf8\x4d\x18\xa9\x5c\xd5\x5c\x9c\x1d\x0a\xaa\xad\x5f\xc9\x20\xa7\xca\x0a\x5f\x02\xf7\xc5\x5d\x61\xa6\x5b\x65\x27\x0b\x34\xe4\x25\xcf\x0b\xcd\x99\x9f\xb6\x91\xea\x4f\x1b\xe0\x0e\x75\xce\x7f\x77\x9a\x2e\x90\xcb\x68\x1d\xc5\xfc\x66\xae\xaf\x2c\xfd\x4d\xc6\x4f\xc2\x96\xe7\x4a\xdd\xcc\xb8\x07\xfc\xfb\x3f\xee\xee\xfa\x48\x1c\xb5\xa9\x85\xbd\xc1\xb8\x48\x71\x2f\xb8\x7c\xe1\x2c\xb8\xf5\xfd\x17\x8d\xf8\xbc\x07\x1d\xe2\xa2\xf1\x5d\x34\xbd\x29\x53\xb8\x5d\xfb\x5c\xee\xb6\xdb\x88\x2f\x46\x3f\x06\x00\x39\x07\xf3\xa9\x6d\x05\x00\x00")
+
+func _1528395659_coverageUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395659_coverageUpSql,
+		"1528395659_coverage.up.sql",
+	)
+}
+
+func _1528395659_coverageUpSql() (*asset, error) {
+	bytes, err := _1528395659_coverageUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395659_coverage.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf8, 0xea, 0xb, 0xe5, 0xc5, 0xf3, 0x1d, 0x30, 0x92, 0x64, 0xcf, 0x1, 0x1d, 0xf8, 0x1e, 0xa1, 0xfe, 0xcd, 0xce, 0x6e, 0xf1, 0xb8, 0xf8, 0x69, 0x87, 0xee, 0x47, 0xc4, 0x28, 0x99, 0x2f, 0x53}}
+	return a, nil
+}
+
+var __1528395660_slack_user_linksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x38\x00\xc7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x6c\x61\x63\x6b\x5f\x75\x73\x65\x72\x5f\x6c\x69\x6e\x6b\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x41\x76\xa1\x1a\x38\x00\x00\x00")
+
+func _1528395660_slack_user_linksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395660_slack_user_linksDownSql,
+		"1528395660_slack_user_links.down.sql",
+	)
+}
+
+func _1528395660_slack_user_linksDownSql() (*asset, error) {
+	bytes, err := _1528395660_slack_user_linksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395660_slack_user_links.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x49, 0xea, 0x94, 0xff, 0x8, 0xc6, 0xa6, 0x7e, 0xa7, 0x15, 0x88, 0x34, 0xd2, 0xa5, 0x48, 0x5e, 0x76, 0xc8, 0xd, 0x9d, 0xf6, 0x89, 0xfb, 0x55, 0x49, 0x8, 0xb3, 0xb8, 0xbc, 0x57, 0xbd, 0xe8}}
+	return a, nil
+}
+
+var __1528395660_slack_user_linksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\x41\x6f\x9c\x30\x14\x84\xef\xfe\x15\x73\x04\x69\x93\x3f\xc0\xc9\x81\xb7\x91\x55\x30\x11\x38\xd2\xa6\x17\xe4\x82\x13\xac\xec\x9a\x0a\x1b\xf1\xf7\x2b\x43\x36\xdd\x2a\x95\x72\x7b\xf0\x66\xbe\xe7\x99\x07\x7a\x14\x32\x63\xec\xee\x0e\xa5\x75\xef\x1e\xbf\x4c\x58\x8d\x71\x68\xcf\xba\x7f\xc7\xe2\xcd\xec\xa1\xdd\x80\x76\x5a\xe6\xde\xbc\xcd\xfa\xf7\xb8\xff\x3d\xa0\x9f\x8d\x0e\x66\xc0\x3a\x1a\x07\x7d\xe3\xc0\x79\x43\x85\xd1\xd8\x19\xba\xef\xa7\xc5\x85\x78\x61\xb5\x61\x44\xcd\x97\x30\xde\x47\xb5\x1f\xd1\x4f\x97\x8b\x76\xc3\x7e\x22\xba\xb0\xb8\xd7\x65\x3e\x5b\xf7\x86\xe9\xf5\x5f\xe8\xbc\x38\x68\x8f\x30\x9a\x8d\x6f\xbe\xbe\xe9\x9e\xe5\x0d\x71\x45\x50\xfc\xa1\x24\x88\x23\x64\xad\x40\x27\xd1\xaa\x16\x3e\x92\xba\x28\xeb\xa2\xdd\x23\x61\x00\x10\x8c\xbe\x74\x76\x88\x23\x00\x45\x27\xb5\x99\xe4\x73\x59\x1e\x36\xc1\x8d\xcf\x0e\xff\x13\x5c\x57\x71\x06\x84\x54\xf4\x48\xcd\xa7\x06\x0d\x1d\xa9\x21\x99\x53\xbb\xc5\xf0\x89\x1d\x52\xd4\x12\x05\x95\xa4\x08\x39\x6f\x73\x5e\xd0\x8e\xfa\x68\xb4\xd3\x21\x7e\x29\x51\x51\xab\x78\xf5\xa4\x7e\xfe\xc5\x15\x74\xe4\xcf\xa5\x82\x9b\xd6\x24\xdd\x5d\x4f\x8d\xa8\x78\xf3\x82\x1f\xf4\x82\xe4\x23\xcf\xe1\x36\xaf\x1d\x52\x96\x66\xec\xda\x8e\x90\x05\x9d\xbe\x69\xe7\x33\x70\x2d\xbf\xec\x92\x2b\x34\x63\x2c\xaf\xab\x4a\xa8\x8c\xfd\x19\x00\xec\x59\x1f\x0e\x48\x02\x00\x00")
+
+func _1528395660_slack_user_linksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395660_slack_user_linksUpSql,
+		"1528395660_slack_user_links.up.sql",
+	)
+}
+
+func _1528395660_slack_user_linksUpSql() (*asset, error) {
+	bytes, err := _1528395660_slack_user_linksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395660_slack_user_links.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa2, 0x4, 0xc2, 0x8a, 0x90, 0xbd, 0x8c, 0x74, 0x52, 0x90, 0xeb, 0x5a, 0x20, 0xa8, 0x59, 0xb4, 0xe3, 0x49, 0x16, 0x57, 0xae, 0x79, 0x3d, 0x86, 0x85, 0x69, 0x82, 0x5f, 0x9b, 0xd1, 0xe6, 0x3b}}
+	return a, nil
+}
+
+var __1528395661_quotasDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\xc8\x29\xce\x4c\x8b\x2f\x2d\xc8\xc9\x4f\x4c\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x80\xc8\xc6\x17\x67\x56\xa5\x5a\xa3\xe8\x4d\xad\x28\x49\x2d\xca\x4b\xcc\x89\x2f\x4e\x2d\x2a\xcb\x4c\x4e\xc5\x65\x40\x5e\x62\x6e\x6a\x71\x41\x62\x72\x6a\x7c\x7e\x51\x7a\x7c\x66\x8a\x35\x17\x58\x1d\xc4\x01\x08\x65\x85\xa5\xf9\x25\x89\xc5\xd6\x5c\x5c\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x80\x01\x00\x16\x36\xf5\xb3\xb0\x00\x00\x00")
+
+func _1528395661_quotasDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395661_quotasDownSql,
+		"1528395661_quotas.down.sql",
+	)
+}
+
+func _1528395661_quotasDownSql() (*asset, error) {
+	bytes, err := _1528395661_quotasDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395661_quotas.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcd, 0x22, 0x5e, 0x78, 0x17, 0x44, 0xe7, 0x69, 0x5d, 0xce, 0xcf, 0x9d, 0xe3, 0x5f, 0x7d, 0xda, 0x18, 0xe, 0x66, 0xb5, 0x63, 0x6e, 0x34, 0x50, 0xe, 0x3, 0x16, 0x5a, 0x1b, 0x39, 0xd, 0xb0}}
+	return a, nil
+}
+
+var __1528395661_quotasUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x94\xdf\x6e\xea\x38\x10\xc6\xef\xf3\x14\x73\x09\x52\xdb\x17\xe0\x2a\x0d\xa6\xb5\x1a\x42\x37\x31\xda\x76\x6f\x22\x37\x1e\x88\xb5\xc1\x66\x6d\xa7\xb4\x7d\xfa\x55\x12\x9b\x7f\x87\xc3\xa9\x8e\x8e\xaf\x90\xf9\xe6\x97\x99\xf1\xcc\x77\x4f\x1e\x68\x36\x89\xa2\xdb\x5b\xc8\xd1\xea\xd6\x54\x08\xff\xb5\xda\x71\x7b\x07\x31\x18\xbd\x83\x9d\x74\x35\x70\x05\xda\xac\x4b\x29\x40\x1b\x68\x2d\x9a\xfe\xe7\x3b\x1a\x23\x05\x5a\x70\xb5\x8f\x02\xbd\x02\x57\x73\xd7\xa9\xb9\x92\x5f\xdc\x49\xdd\x85\x76\xfc\x2e\xec\x06\xb8\x12\xbd\x7c\x8f\x56\x28\x5d\x8d\x06\xe4\x80\x11\xb8\xe2\x6d\xe3\x0e\x38\xde\x34\xa0\x7b\xc5\x31\xd3\xf6\xa0\x0e\xd9\x27\x9a\x2d\xd3\xb4\xfb\x46\x23\x37\xd2\xc1\x06\xb9\xea\x68\xdc\xf5\x48\x13\x0a\x93\x16\x94\x76\x83\x08\xc5\x5d\x94\xe4\x24\x66\x04\x58\x7c\x9f\x12\xa0\x33\xc8\x16\x0c\xc8\x0b\x2d\x58\xe1\x7b\x00\xa3\x08\x00\x40\x0a\xb8\x76\x0a\x92\xd3\x38\x85\xe7\x9c\xce\xe3\xfc\x15\x9e\xc8\xeb\x4d\x1f\xe6\x3b\x16\x64\xe7\x87\x66\x8c\x3c\x90\x1c\x72\x32\x23\x39\xc9\x12\x52\x74\x5d\xb3\x23\x29\xc6\xb0\xc8\x60\x4a\x52\xc2\x08\x24\x71\x91\xc4\x53\x32\x10\x43\xe7\xbf\x4f\xec\x22\xae\x22\x37\xfc\xa3\x34\xb8\xd5\x56\x3a\x6d\x24\xda\xc0\x3a\x45\x1e\xb4\x96\xbf\xa3\x28\x2d\x72\x53\xd5\xa5\xd2\x4e\xae\x64\xe5\x5f\xe4\x07\x6d\xc5\x37\x5b\x2e\xd7\xca\xfe\x34\xd5\x23\x6d\xcd\xd5\x1a\x2d\x3a\xfb\x6b\x6d\x63\xe5\xaa\x6c\xb7\x8d\xe6\xa2\x7c\xfb\x74\xc7\x49\xdf\xd3\x07\x9a\xb1\x83\x94\x57\x15\x5a\x5b\x3a\xfd\x2f\x2a\x7b\x05\x5b\x19\xe4\x0e\x45\xc9\x5d\xf8\xfb\xe4\x30\x3a\x27\x05\x8b\xe7\xcf\xec\x9f\x7e\x4a\xba\x79\x83\x29\x99\xc5\xcb\x94\x81\xd2\xbb\xd1\xd8\x3f\xd0\x56\xfc\x09\x4c\xb2\xc8\x0a\x96\xc7\x34\x63\x7e\x10\x4b\x2b\xd5\xba\xc1\x52\xf1\x0d\xda\x2d\xaf\x10\x92\x47\x92\x3c\xc1\xc8\xcf\x18\x2d\x06\xd6\x22\xdf\xcf\x88\xbf\x1a\x47\xe3\x49\x14\x26\x7d\x99\xd1\xbf\x96\x04\x68\x36\x25\x2f\x17\x07\xbe\xf4\xbc\x45\xe6\x2f\xfc\x07\xc6\xf0\xf7\x23\xc9\x49\x30\x01\x5a\xec\xd3\x9f\x7c\x9f\x1d\x32\x3b\xc0\xfd\x4d\xa0\x07\xc1\xef\xe1\x83\x73\x1c\xf0\x23\x67\x5a\x1c\x5f\xc8\xbd\x6b\x55\x9c\x4d\xcf\x7b\x35\xf8\x20\xab\xf1\xd4\xbe\x76\xb5\xb6\xc1\xdf\x06\x3f\x39\x5a\x17\xbd\x02\xae\x00\x3f\x1c\x1a\xc5\x1b\xb0\x68\xde\x65\x85\x50\xe9\x56\x39\xe0\x6b\x2e\x95\x75\x77\x51\x9c\x32\x92\x7b\xa3\x09\xda\xd2\x6b\x2d\xc4\xd3\x29\x24\x8b\x74\x39\xcf\xce\x2a\xdb\x3f\x77\x78\x97\x0b\x1b\x7e\xc1\x33\x0a\xc2\xce\xea\xb1\xf2\x0b\x7b\x33\x55\x90\x16\x74\x06\xc3\xf2\xdc\x80\xc1\x4a\x1b\x81\x02\xde\x3e\x7b\xab\x5c\x19\xad\x1c\x2a\x01\xbb\x1a\x55\x7f\x33\x28\x3b\x77\xee\x56\x69\xeb\x50\x9c\x56\x73\xb4\x8b\x57\x0a\xf1\xcb\xda\xa7\x31\xac\xe8\x24\x8a\x92\xc5\x7c\x4e\xd9\x24\xfa\x7f\x00\x73\x65\x08\x31\x84\x06\x00\x00")
+
+func _1528395661_quotasUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395661_quotasUpSql,
+		"1528395661_quotas.up.sql",
+	)
+}
+
+func _1528395661_quotasUpSql() (*asset, error) {
+	bytes, err := _1528395661_quotasUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395661_quotas.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2e, 0x87, 0x14, 0x43, 0x3d, 0x3c, 0x8b, 0xa7, 0xb4, 0xa2, 0xe3, 0xe5, 0xe9, 0xfa, 0xc9, 0xf5, 0xa3, 0x76, 0x9c, 0x84, 0xb4, 0x64, 0x23, 0x9f, 0x84, 0xde, 0xf, 0xb3, 0x6d, 0xae, 0x9f, 0xc7}}
+	return a, nil
+}
+
+var __1528395662_watchesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x7f\x00\x80\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x77\x61\x74\x63\x68\x5f\x66\x65\x65\x64\x5f\x65\x6e\x74\x72\x69\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x77\x61\x74\x63\x68\x5f\x72\x65\x70\x6f\x5f\x68\x65\x61\x64\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x77\x61\x74\x63\x68\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x84\x3e\xfd\x6d\x7f\x00\x00\x00")
+
+func _1528395662_watchesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395662_watchesDownSql,
+		"1528395662_watches.down.sql",
+	)
+}
+
+func _1528395662_watchesDownSql() (*asset, error) {
+	bytes, err := _1528395662_watchesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395662_watches.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x83, 0x68, 0xad, 0x8e, 0xaa, 0x95, 0xc1, 0x78, 0x3c, 0xed, 0x7a, 0xad, 0xc9, 0x65, 0x9, 0x2f, 0x58, 0x8a, 0x9e, 0x5, 0xc, 0x7d, 0x10, 0xf8, 0xcf, 0x5b, 0x1f, 0x6a, 0xdf, 0xb4, 0x7c, 0x14}}
+	return a, nil
+}
+
+var __1528395662_watchesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\xdf\x8e\xa2\x4c\x10\xc5\xef\x79\x8a\xba\x13\x13\xfd\x1e\xe0\xf3\x0a\xb5\x66\x42\x16\x61\x16\xda\xc4\xd9\xcd\x86\xb4\x50\xd8\x6c\xf8\x63\xe8\x76\x8d\x6f\xbf\x69\xa4\x47\x18\xd9\x19\xc7\x3b\xe9\x53\xa7\xba\x7e\x7d\x6a\x89\xcf\xae\xbf\xb0\xac\xf9\x1c\x1c\x38\x49\x6a\x26\x12\xce\x5c\x25\x02\xea\x0a\x38\x34\x74\xac\x65\xae\xea\xe6\x32\x83\xba\xd1\xdf\x94\x20\x38\x72\x25\x24\xd4\xd9\x40\x00\xa5\x2e\xcb\xab\x03\x70\x38\x14\xf5\x5e\xab\x14\x35\x15\xd8\xbc\xd2\xf6\x54\x1e\xd5\x45\x7f\x14\xb1\x39\x69\x1b\x91\x6c\x3d\xcf\xa2\x2e\xa8\x67\x37\xfd\xcf\x5a\x85\xe8\x30\x04\xe6\x2c\x3d\x04\xf7\x09\xfc\x80\x01\xee\xdc\x88\x45\x6f\x95\xb6\x05\x00\x90\xa7\x70\xfb\x45\x18\xba\x8e\x07\x2f\xa1\xbb\x71\xc2\x57\xf8\x86\xaf\xb3\x56\xa4\x87\x8b\x8d\xd2\xf5\x19\x3e\x63\xd8\x5a\xfa\x5b\xcf\x83\x10\x9f\x30\x44\x7f\x85\x51\x4b\x41\xda\x79\x3a\x85\xc0\x87\x35\x7a\xc8\x10\x56\x4e\xb4\x72\xd6\x78\x75\xd2\x97\x7c\xc8\x49\x0b\x3f\x32\x1a\xc0\x60\xb8\x63\x37\x97\x35\x3e\x39\x5b\x8f\xc1\x64\x72\x95\x52\xc9\xf3\x22\x4e\xf3\x03\x49\x05\xcb\x20\xf0\xd0\xf1\xef\xd5\x19\x2f\x24\x5d\x0b\x92\x86\xb8\xa2\x34\xe6\x0a\x00\x98\xbb\xc1\x88\x39\x9b\x17\xf6\xe3\xbe\xa8\xaa\xcf\xf6\xd4\x9a\x2e\x2c\x03\x7c\xeb\xbb\xdf\xb7\x08\xae\xbf\xc6\xdd\x38\xf7\xb8\x83\x19\x77\x28\xe2\xc1\x24\x81\x6f\x74\x76\xa7\x9b\x19\x66\xb3\xc1\xcc\xd3\x85\x69\xf9\x51\xaf\xae\xb4\x6f\xdb\x7d\xd2\x77\x9e\xcf\x81\x09\x82\x82\x4b\x05\x82\x78\x0a\x49\x5d\x96\xb9\xd2\xe9\x24\x9e\x88\xae\x24\xed\x25\x6b\x22\x21\xa5\x8c\x9f\x0a\x05\xfb\x86\x57\x89\x00\x25\xb8\x82\x8a\xce\x5d\xad\x84\x33\x35\xa4\xff\x1c\x4f\x8a\x52\x1d\xde\xac\x6e\x3e\xcf\xe3\x95\x86\xbe\x84\x09\xa6\xb9\x7a\x2f\x27\xbd\x5c\x7e\x25\x2a\xd7\x9b\xb5\x5e\x83\xa0\x74\xd9\x3e\xa6\xe6\xb1\x1f\x7c\xea\x0e\x1b\x4f\x54\xfe\x27\x57\x17\xc8\x88\xd2\xff\xdf\xe6\x6f\x81\x24\x82\x57\x07\x4a\x21\xcb\x0b\x92\xfd\xed\x6e\x91\x3e\x80\x43\x7b\xc6\x54\xa9\x26\x1f\xdd\xd4\xa5\xfb\xfc\xaf\x5d\x6d\x3b\xbc\x03\x37\xb6\x60\x26\x0f\x8f\x81\x1b\x23\x27\x4f\xfb\xdf\x94\xe8\x1d\x19\x3d\xe6\x27\x25\xea\x26\xae\x78\x49\x63\xc7\x3a\xcc\xb2\x1b\x47\x1f\xff\xfc\xf5\x4e\xd0\xdf\xc2\xcf\x5f\xa6\xb7\xea\xf7\x35\x5f\xd9\xd0\x01\xfa\xd8\xd0\x8c\x6f\x2c\x02\x7f\x44\x68\x1b\xe1\xec\x46\xed\x81\x15\x1d\x36\xeb\x4d\x3c\xde\xe4\x26\x68\x07\x0a\x36\x1b\x97\x2d\xac\xbf\x03\x00\x3b\x4c\xf4\x70\x88\x06\x00\x00")
+
+func _1528395662_watchesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395662_watchesUpSql,
+		"1528395662_watches.up.sql",
+	)
+}
+
+func _1528395662_watchesUpSql() (*asset, error) {
+	bytes, err := _1528395662_watchesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395662_watches.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe3, 0xfb, 0xe2, 0x4c, 0xa7, 0x30, 0x91, 0x11, 0x86, 0x47, 0x84, 0x84, 0xff, 0x3b, 0x3d, 0xb9, 0xc0, 0x53, 0xdb, 0xa5, 0x1c, 0x3f, 0x44, 0x89, 0x13, 0xe8, 0xaa, 0x71, 0xfd, 0x3, 0xa3, 0xc2}}
+	return a, nil
+}
+
+var __1528395663_repo_pull_requestsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\x2f\x28\xcd\xc9\x89\x2f\x4a\x2d\x2c\x4d\x2d\x2e\x89\x2f\xae\xcc\x4b\x2e\xb6\x26\x56\x75\x72\x7e\x6e\x6e\x66\x09\xd1\xea\x8b\xad\xb9\xb8\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x03\x00\x36\xd6\x8f\xc5\x98\x00\x00\x00")
+
+func _1528395663_repo_pull_requestsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395663_repo_pull_requestsDownSql,
+		"1528395663_repo_pull_requests.down.sql",
+	)
+}
+
+func _1528395663_repo_pull_requestsDownSql() (*asset, error) {
+	bytes, err := _1528395663_repo_pull_requestsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395663_repo_pull_requests.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x0, 0x39, 0x10, 0x33, 0x6b, 0xb2, 0x90, 0x8, 0xba, 0xde, 0x52, 0xd1, 0xcf, 0xcc, 0x7b, 0x2e, 0x2e, 0x5a, 0x22, 0xa9, 0xcd, 0xce, 0x86, 0xf0, 0xb3, 0x44, 0x59, 0x84, 0xd4, 0x60, 0xc6, 0xbd}}
+	return a, nil
+}
+
+var __1528395663_repo_pull_requestsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x54\xd1\xae\xa2\x30\x14\x7c\xe7\x2b\xe6\x11\x12\xf5\x03\xd6\x27\xd4\x6a\x9a\x45\xbc\x8b\x35\xf1\xee\x0b\xe1\xd2\xba\x34\x01\xea\xd2\x12\xd7\xbf\xdf\x58\x40\x94\xe8\xdd\x6b\xb2\x3c\xf6\x4c\xcf\x70\x66\xa6\x67\x46\x56\x34\x9c\x3a\xce\x78\x0c\x96\x09\x14\xa2\xfa\x25\x38\x8e\x75\x9e\xa3\x12\xbf\x6b\xa1\x8d\x86\xab\xaa\xa6\x70\x3d\xf2\xa0\x0e\xa8\xc4\x51\x69\x69\x54\x25\x85\x86\x2a\x91\x2a\x2e\x90\x29\x6d\xf4\x08\x89\x86\x2c\xb9\xf8\x23\x38\x3e\xce\x97\xe6\x17\xf0\xb8\x3e\xf2\xc4\x88\x6a\xe2\xcc\x23\xe2\x33\x02\xe6\xcf\x02\x02\xba\x44\xb8\x61\x20\x7b\xba\x65\x5b\xdb\x35\xbe\xf0\xc7\x3d\xbf\x03\x00\x92\xe3\xee\x9b\xd1\xd5\x96\x44\xd4\x0f\xf0\x16\xd1\xb5\x1f\xbd\xe3\x3b\x79\x1f\x59\xa8\xed\x71\x83\xa7\x21\x23\x2b\x12\x59\x9a\x70\x17\x04\x88\xc8\x92\x44\x24\x9c\x93\x86\xcf\x95\xdc\xc3\x26\xc4\x82\x04\x84\x11\xcc\xfd\xed\xdc\x5f\x90\xa6\x57\x59\x17\x1f\xa2\xea\x3a\x59\x5a\x1a\xb2\x6b\xab\x06\x64\xa4\xc9\x45\x87\x00\xc0\xc8\x7e\x08\xa9\xab\x1c\xf8\x1c\x62\x35\x8e\x53\x55\x14\xd2\xc4\x92\x5b\xc8\x4d\x85\xc7\x89\xe9\x2e\xd3\x35\xd9\x32\x7f\xfd\xc6\x7e\x0e\x69\xac\xc4\x3d\xf4\x11\xd2\xf1\xa6\x4e\x67\xc1\x2e\xa4\x3f\x76\x04\x34\x5c\x90\xfd\x3f\x9d\x88\x5b\x61\xe3\x56\x94\x4d\xf8\x00\xe4\xb6\xa0\x51\x2b\x9d\xd7\xa7\xab\x19\x4d\xc3\x64\x89\x41\xf2\x28\x6c\x90\xa5\xa9\x14\xaf\x53\xc1\xbf\xe1\x02\xb5\x98\xf6\x22\x92\x92\xdb\xc3\x4c\x24\xbc\x3d\xd3\xaf\x85\xa9\x55\xb7\xcb\xd4\x5d\x49\xf2\xa1\xb9\xc3\x9c\x0c\x06\xfd\x24\x35\xad\x06\xff\x25\x81\x7d\x20\x9e\x26\xe7\xe6\x05\xc0\x1d\x0c\x35\x6a\x95\x8a\x25\xf7\x6e\x9d\xff\x92\xe5\x9d\x5e\x57\xe7\xfb\x9f\xd9\x84\xcf\xe1\x7d\x06\x7a\xee\x3e\x06\x46\x16\x02\xf5\x11\x46\xe1\x94\xc9\x34\x83\x79\xb6\x79\xd4\x01\x22\x49\xb3\x7e\xd5\x9c\x71\x12\x95\xb8\xee\x16\x57\x4e\xc4\x64\x64\xef\xe7\x89\x36\x17\x82\x66\xc7\x34\x1c\xea\x60\x4b\x85\xd2\x06\x95\x48\x45\x69\xf2\x73\xf7\x42\xee\x98\xbc\x17\x53\xa4\xcf\x65\xda\x65\xa8\x1d\xf5\xde\xe7\x5b\x43\x5e\xb0\x5a\x9f\xcb\x54\xf0\xb8\x2e\x8d\xcc\xbf\xfa\xc8\x1f\xc1\xb0\x20\x4b\x7f\x17\x30\x94\xea\xe4\xb6\xae\x6f\xd6\x6b\xca\xa6\xce\xdf\x01\x00\xd8\xbb\x49\x08\xf1\x05\x00\x00")
+
+func _1528395663_repo_pull_requestsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395663_repo_pull_requestsUpSql,
+		"1528395663_repo_pull_requests.up.sql",
+	)
+}
+
+func _1528395663_repo_pull_requestsUpSql() (*asset, error) {
+	bytes, err := _1528395663_repo_pull_requestsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395663_repo_pull_requests.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0xe8, 0xe5, 0x5a, 0xad, 0x71, 0x73, 0xf4, 0xfc, 0x18, 0xee, 0x47, 0x1f, 0x4a, 0x59, 0xda, 0x4f, 0x79, 0xea, 0xc, 0x99, 0x78, 0xd1, 0x32, 0x4, 0xc5, 0x3, 0xaa, 0x63, 0x36, 0xe6, 0x51}}
+	return a, nil
+}
+
+var __1528395664_code_clonesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\xc9\xcf\x4b\x8d\x4f\xcb\xcc\x4b\x4f\x2d\x2a\x28\xca\xcc\x2b\x29\xb6\xc6\xa7\x30\x33\x2f\x25\xb5\x22\x3e\x2d\x33\x27\x95\x18\x75\x45\xa9\x05\xf9\xc5\xd6\x5c\x5c\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x80\x01\x00\x87\x28\xf7\xa5\x8a\x00\x00\x00")
+
+func _1528395664_code_clonesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395664_code_clonesDownSql,
+		"1528395664_code_clones.down.sql",
+	)
+}
+
+func _1528395664_code_clonesDownSql() (*asset, error) {
+	bytes, err := _1528395664_code_clonesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395664_code_clones.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd2, 0x66, 0x80, 0x5a, 0xeb, 0x14, 0x94, 0x90, 0x43, 0x40, 0x15, 0xb1, 0xe, 0xc, 0xf1, 0x81, 0xa0, 0xef, 0x7b, 0x27, 0x57, 0x2, 0xe, 0x5, 0x24, 0xaa, 0x40, 0x2b, 0xd3, 0xa8, 0x6a, 0xa4}}
+	return a, nil
+}
+
+var __1528395664_code_clonesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x92\xd1\x6e\x9b\x4c\x10\x85\xef\x79\x8a\xb9\x04\xc9\xf9\xff\x07\xf0\x15\xb6\xc7\xd6\xaa\xb0\xa4\xb0\x96\x9c\xde\xa0\x2d\x3b\x0e\xab\x92\x5d\x04\x9b\xba\x7d\xfb\x8a\x85\x38\xb8\xa4\x6d\x22\xdf\x58\x9a\xc3\x99\x73\xbe\xd9\x0d\x1e\x18\x5f\x07\xc1\xdd\x1d\x88\x9a\xa0\xa3\xd6\xf6\xda\xd9\x4e\x53\x0f\x97\xda\xf6\x04\x8a\xce\xf2\xb9\x71\xf0\xb5\x93\xa6\xaa\x41\xf7\xa0\x8d\xa2\x1f\xa4\xe0\x6c\x3b\xa8\xac\x22\xa8\x1a\x6b\x06\xa1\xa3\xca\x69\x6b\x56\x20\x8d\x02\x57\xd3\x55\x59\xd9\xa7\x27\xed\xfe\x0b\xb6\x39\xc6\x02\x41\xc4\x9b\x04\x81\xed\x81\x67\x02\xf0\xc4\x0a\x51\x8c\x1e\xa5\xff\xa0\xf4\x29\x20\x0c\x00\xc0\x27\x2a\xb5\x1a\xfe\x32\x2e\xf0\x80\x39\xdc\xe7\x2c\x8d\xf3\x07\xf8\x84\x0f\x90\xe3\x1e\x73\xe4\x5b\x2c\xbc\x32\xd4\x2a\x82\x8c\xc3\x0e\x13\x14\x08\xdb\xb8\xd8\xc6\x3b\x5c\x79\xa7\x31\x84\xf7\x12\x78\x12\x7e\x39\x3f\x26\xc9\x38\x7d\x6e\x95\x74\xa4\x4a\xe9\x40\xb0\x14\x0b\x11\xa7\xf7\xe2\xcb\x55\x04\x3b\xdc\xc7\xc7\x44\x80\xb1\x97\x30\x0a\xa2\x57\x64\x57\x1a\xba\xa1\x1e\xec\xf9\x96\x61\x68\x4d\xf3\x13\x9c\x27\xe9\x6a\xe9\xa0\x96\xdf\x09\xce\xda\x3c\x52\xd7\x76\xda\xb8\x3e\x7a\x37\x96\x71\xc3\x88\x65\x68\x31\xfc\x36\xec\x50\x60\xce\xe2\x64\x4e\x65\x75\x43\xee\x05\xdb\xb5\xca\x07\x98\xb5\xd2\xd5\x00\xbf\x01\xf3\xed\xa7\xcc\x47\xce\x3e\x1f\x11\x18\xdf\xe1\xe9\x5f\xd1\xcb\x29\x51\xe9\x5d\x33\xbe\x54\x84\x93\x62\x05\x83\x64\x06\xf9\xa2\x8d\xb1\x17\x52\x37\xe4\x06\xd8\xf3\x57\x36\xf1\xe9\x89\xa0\x95\xd5\x37\xf9\x38\x8c\x1c\x75\x46\x36\xff\xfb\x55\xef\x62\x7d\xb3\x61\x84\x3d\x18\x4f\x6f\x70\xc3\x0e\x8c\xbf\xa2\x98\xbf\xbf\x65\x9b\xbf\x80\xad\x65\xef\xc1\x2e\x2d\xc7\xe3\xf5\x4e\x76\xae\x6c\xb4\xa1\xc5\xfd\x46\x01\x19\x35\x8e\x61\x21\x98\xdf\xe7\xcf\x87\x99\xf7\x2c\x5f\x1a\x66\xfc\x8d\x69\x38\x4d\xa3\xf5\x07\x5d\x7d\xc9\xb7\x2d\x87\x91\x8f\x99\xa5\x29\x13\xeb\xe0\xd7\x00\x94\xde\xd8\x8f\x88\x04\x00\x00")
+
+func _1528395664_code_clonesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395664_code_clonesUpSql,
+		"1528395664_code_clones.up.sql",
+	)
+}
+
+func _1528395664_code_clonesUpSql() (*asset, error) {
+	bytes, err := _1528395664_code_clonesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395664_code_clones.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa2, 0xda, 0x54, 0xa1, 0xd2, 0x71, 0xfe, 0xe8, 0x33, 0xb1, 0x5d, 0x13, 0xfb, 0xd4, 0xd2, 0x1d, 0x2b, 0xac, 0x3, 0x33, 0xd7, 0x44, 0x63, 0x18, 0xa3, 0x7d, 0xc5, 0x94, 0xe6, 0x6f, 0x81, 0x3c}}
+	return a, nil
+}
+
+var __1528395665_code_expertiseDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\x4f\x49\x8d\x4f\xad\x28\x48\x2d\x2a\xc9\x2c\x4e\x8d\xcf\xc9\xcc\x4b\x2d\xb6\x26\x4a\x69\x51\x6a\x41\x3e\x2e\xa5\x20\xb9\xf8\x82\xd2\x9c\x9c\xf8\xa2\xd4\xc2\xd2\xd4\xe2\x92\xf8\xa2\xd4\xb2\xcc\xd4\xf2\xd4\xa2\x62\x6b\x2e\x2e\x67\x7f\x5f\x5f\xcf\x10\x6b\x2e\xc0\x00\xfb\xc1\x31\x10\x99\x00\x00\x00")
+
+func _1528395665_code_expertiseDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395665_code_expertiseDownSql,
+		"1528395665_code_expertise.down.sql",
+	)
+}
+
+func _1528395665_code_expertiseDownSql() (*asset, error) {
+	bytes, err := _1528395665_code_expertiseDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395665_code_expertise.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x70, 0x8e, 0xdc, 0x94, 0xf, 0xde, 0x5f, 0xa8, 0x3, 0x48, 0x33, 0x38, 0xa0, 0x4c, 0x98, 0xcc, 0xde, 0xc9, 0xa4, 0xac, 0x86, 0xed, 0x67, 0xb1, 0x9a, 0x99, 0x73, 0xba, 0x44, 0xa3, 0x25, 0x41}}
+	return a, nil
+}
+
+var __1528395665_code_expertiseUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x94\xc1\x6e\xe2\x3c\x10\xc7\xef\x79\x8a\x51\x2f\x1f\x48\x29\x2f\xd0\x53\x0a\xa6\x8a\x3e\x08\x55\x70\xa5\x76\x2f\x96\x1b\x0f\x8d\x25\x63\xb3\xb6\xb3\x94\xb7\x5f\xd9\x0e\x64\xdb\x4d\x51\x97\x13\x30\x93\xff\xcc\xfc\x7f\x33\xb9\x27\x0f\x65\x75\x97\x65\xb7\xb7\x40\x5b\x84\xce\xa1\x75\x30\x31\xbe\x45\x0b\xbe\xe5\x1a\x7c\x8b\xc0\x3b\xdf\x1a\x3b\x85\x63\x6b\xc0\xe2\x2f\x89\x47\x14\x31\xb0\x47\xfb\x86\x02\x0e\x9d\x52\x60\xf1\x67\x87\xce\x3b\x30\x3b\xb0\x78\x30\x4e\x7a\x63\x25\xba\x1c\xb8\x0b\xf2\x52\x0b\x7c\x47\x01\xaf\xa7\x18\xbe\xed\x0e\x82\x7b\xb4\xb3\x58\x97\x37\x8d\xe9\xb4\x87\x72\x01\xd2\x45\xe9\x72\x11\x84\x7c\xdf\xd3\x7f\xee\x92\x62\x52\x4f\x8d\x11\x08\xad\x71\x7e\x96\xcd\x6b\x52\x50\x02\xb4\xb8\x5f\x11\x28\x97\x50\x6d\x28\x90\xe7\x72\x4b\xb7\xb1\x12\x0b\xed\xb1\xbe\x3d\xd6\xf7\x1f\xa6\xcc\x00\x00\x3e\x04\xa5\x80\xfb\xf2\xa1\xac\x68\xd4\xa8\x9e\x56\x2b\xa8\xc9\x92\xd4\xa4\x9a\x93\x11\x31\x37\x91\x62\x0a\x9b\x0a\x16\x64\x45\x28\x81\x79\xb1\x9d\x17\x0b\x92\x47\xe1\xbe\x5f\x26\x45\xf8\x05\x40\xc9\xf3\x20\x9b\x52\x94\x79\x93\x1a\x86\xcf\x48\xca\x63\x5d\xae\x8b\xfa\x05\xfe\x27\x2f\x30\xf9\xd4\x6b\x7e\xf6\x84\x49\x31\xcd\xa6\x89\xe2\xf6\xa4\x1b\xe0\x4a\x8d\xb3\xe1\x6f\x5c\xea\x1c\x9c\x09\x70\x7d\x34\x72\x30\x24\xfa\x6d\x1c\xa6\xd8\x11\x2d\x02\x57\x16\xb9\x38\x9d\xe1\x85\x02\xdc\xe2\x85\xa5\x37\x66\x96\xf5\xc3\x2f\xeb\xcd\x7a\xc4\x6f\x77\xd2\x8d\x1b\x16\xec\xcf\xcd\x08\xfb\xe4\x10\x04\xee\x78\xa7\x3c\xbc\x5a\xae\x9b\x36\xf0\x3f\xcb\xef\x8c\x4d\x9c\xf1\xfd\x80\xd6\x4b\x87\x39\x70\x9d\x56\xef\x9c\xd3\x98\xfd\x5e\x5e\x5f\x82\x20\xc1\x2e\x12\x2c\xb6\xd0\xd3\x0f\xdf\x7b\x42\x65\x45\xc9\x03\xa9\x3f\x38\xfe\x09\xfe\x35\xdc\xa9\x8f\xa8\x35\x82\x31\x2d\xbb\x60\xdc\x03\x2d\xd7\x64\x4b\x8b\xf5\x23\xfd\x71\x49\x82\x05\x59\x16\x4f\x2b\x0a\xda\x1c\x27\x17\x94\xc1\x2f\xdd\xed\x5f\xd1\x86\x53\x50\x52\x63\x38\x0d\xee\x01\x79\xd3\x7e\x32\x00\xb8\x10\x11\x48\x34\x67\x27\x15\x3a\x10\xd2\x62\xe3\x55\xa0\x97\x1e\x49\x7f\x18\x7b\x0a\x34\x26\x37\x37\xd1\xe0\x90\x6f\x8d\xf1\x43\x74\x3a\x83\x22\x5e\x3c\xe0\x9e\x4b\xe5\x22\x72\x65\x8e\x68\x1b\xee\xf0\x5f\xac\x4e\x3d\xff\x65\xf5\x60\xf6\x57\x67\xf6\x4d\xa7\x47\xbd\x16\xd2\x9e\x0f\x6a\x34\x9e\xde\x66\x4c\xf3\x3d\x5e\x8b\xc7\xd9\xbf\x8e\x27\x98\xa3\x34\xfb\xe3\x8e\xb3\xc3\xf8\xbc\x23\xc7\xdd\xfb\x93\x0f\xf3\xe5\x01\x49\xda\x86\xde\xf2\xb2\x5a\x90\xe7\x6f\x58\xce\x7a\x31\x16\xbc\xd8\x54\xa3\x39\x43\xc1\x90\xe4\xf1\xdd\xb3\x03\xf7\x1e\xad\x66\xe6\xe0\x62\xd1\xcd\x7a\x5d\xd2\xbb\xec\xf7\x00\xf4\x69\x0a\xc8\x24\x06\x00\x00")
+
+func _1528395665_code_expertiseUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395665_code_expertiseUpSql,
+		"1528395665_code_expertise.up.sql",
+	)
+}
+
+func _1528395665_code_expertiseUpSql() (*asset, error) {
+	bytes, err := _1528395665_code_expertiseUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395665_code_expertise.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x72, 0x3d, 0x9d, 0x49, 0x0, 0x74, 0x68, 0x52, 0x41, 0x38, 0x45, 0x40, 0x5, 0xd3, 0x45, 0x84, 0xb0, 0xea, 0x48, 0x45, 0xdb, 0xe6, 0x15, 0x4c, 0x5a, 0xaf, 0xc3, 0xb1, 0x4, 0x33, 0xd2, 0xdb}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -1787,6 +2291,30 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395652_add_lsif_indexer.up.sql":                               _1528395652_add_lsif_indexerUpSql,
 	"1528395653_repo_normalize_visibility_metadata.down.sql":           _1528395653_repo_normalize_visibility_metadataDownSql,
 	"1528395653_repo_normalize_visibility_metadata.up.sql":             _1528395653_repo_normalize_visibility_metadataUpSql,
+	"1528395654_sub_repo_path_rules.down.sql":                          _1528395654_sub_repo_path_rulesDownSql,
+	"1528395654_sub_repo_path_rules.up.sql":                            _1528395654_sub_repo_path_rulesUpSql,
+	"1528395655_campaign_jobs_log.down.sql":                            _1528395655_campaign_jobs_logDownSql,
+	"1528395655_campaign_jobs_log.up.sql":                              _1528395655_campaign_jobs_logUpSql,
+	"1528395656_repo_access_requests.down.sql":                         _1528395656_repo_access_requestsDownSql,
+	"1528395656_repo_access_requests.up.sql":                           _1528395656_repo_access_requestsUpSql,
+	"1528395657_event_logs_rollups.down.sql":                           _1528395657_event_logs_rollupsDownSql,
+	"1528395657_event_logs_rollups.up.sql":                             _1528395657_event_logs_rollupsUpSql,
+	"1528395658_snippet_share_links.down.sql":                          _1528395658_snippet_share_linksDownSql,
+	"1528395658_snippet_share_links.up.sql":                            _1528395658_snippet_share_linksUpSql,
+	"1528395659_coverage.down.sql":                                     _1528395659_coverageDownSql,
+	"1528395659_coverage.up.sql":                                       _1528395659_coverageUpSql,
+	"1528395660_slack_user_links.down.sql":                             _1528395660_slack_user_linksDownSql,
+	"1528395660_slack_user_links.up.sql":                               _1528395660_slack_user_linksUpSql,
+	"1528395661_quotas.down.sql":                                       _1528395661_quotasDownSql,
+	"1528395661_quotas.up.sql":                                         _1528395661_quotasUpSql,
+	"1528395662_watches.down.sql":                                      _1528395662_watchesDownSql,
+	"1528395662_watches.up.sql":                                        _1528395662_watchesUpSql,
+	"1528395663_repo_pull_requests.down.sql":                           _1528395663_repo_pull_requestsDownSql,
+	"1528395663_repo_pull_requests.up.sql":                             _1528395663_repo_pull_requestsUpSql,
+	"1528395664_code_clones.down.sql":                                  _1528395664_code_clonesDownSql,
+	"1528395664_code_clones.up.sql":                                    _1528395664_code_clonesUpSql,
+	"1528395665_code_expertise.down.sql":                               _1528395665_code_expertiseDownSql,
+	"1528395665_code_expertise.up.sql":                                 _1528395665_code_expertiseUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -1904,6 +2432,30 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395652_add_lsif_indexer.up.sql":                               {_1528395652_add_lsif_indexerUpSql, map[string]*bintree{}},
 	"1528395653_repo_normalize_visibility_metadata.down.sql":           {_1528395653_repo_normalize_visibility_metadataDownSql, map[string]*bintree{}},
 	"1528395653_repo_normalize_visibility_metadata.up.sql":             {_1528395653_repo_normalize_visibility_metadataUpSql, map[string]*bintree{}},
+	"1528395654_sub_repo_path_rules.down.sql":                          {_1528395654_sub_repo_path_rulesDownSql, map[string]*bintree{}},
+	"1528395654_sub_repo_path_rules.up.sql":                            {_1528395654_sub_repo_path_rulesUpSql, map[string]*bintree{}},
+	"1528395655_campaign_jobs_log.down.sql":                            {_1528395655_campaign_jobs_logDownSql, map[string]*bintree{}},
+	"1528395655_campaign_jobs_log.up.sql":                              {_1528395655_campaign_jobs_logUpSql, map[string]*bintree{}},
+	"1528395656_repo_access_requests.down.sql":                         {_1528395656_repo_access_requestsDownSql, map[string]*bintree{}},
+	"1528395656_repo_access_requests.up.sql":                           {_1528395656_repo_access_requestsUpSql, map[string]*bintree{}},
+	"1528395657_event_logs_rollups.down.sql":                           {_1528395657_event_logs_rollupsDownSql, map[string]*bintree{}},
+	"1528395657_event_logs_rollups.up.sql":                             {_1528395657_event_logs_rollupsUpSql, map[string]*bintree{}},
+	"1528395658_snippet_share_links.down.sql":                          {_1528395658_snippet_share_linksDownSql, map[string]*bintree{}},
+	"1528395658_snippet_share_links.up.sql":                            {_1528395658_snippet_share_linksUpSql, map[string]*bintree{}},
+	"1528395659_coverage.down.sql":                                     {_1528395659_coverageDownSql, map[string]*bintree{}},
+	"1528395659_coverage.up.sql":                                       {_1528395659_coverageUpSql, map[string]*bintree{}},
+	"1528395660_slack_user_links.down.sql":                             {_1528395660_slack_user_linksDownSql, map[string]*bintree{}},
+	"1528395660_slack_user_links.up.sql":                               {_1528395660_slack_user_linksUpSql, map[string]*bintree{}},
+	"1528395661_quotas.down.sql":                                       {_1528395661_quotasDownSql, map[string]*bintree{}},
+	"1528395661_quotas.up.sql":                                         {_1528395661_quotasUpSql, map[string]*bintree{}},
+	"1528395662_watches.down.sql":                                      {_1528395662_watchesDownSql, map[string]*bintree{}},
+	"1528395662_watches.up.sql":                                        {_1528395662_watchesUpSql, map[string]*bintree{}},
+	"1528395663_repo_pull_requests.down.sql":                           {_1528395663_repo_pull_requestsDownSql, map[string]*bintree{}},
+	"1528395663_repo_pull_requests.up.sql":                             {_1528395663_repo_pull_requestsUpSql, map[string]*bintree{}},
+	"1528395664_code_clones.down.sql":                                  {_1528395664_code_clonesDownSql, map[string]*bintree{}},
+	"1528395664_code_clones.up.sql":                                    {_1528395664_code_clonesUpSql, map[string]*bintree{}},
+	"1528395665_code_expertise.down.sql":                               {_1528395665_code_expertiseDownSql, map[string]*bintree{}},
+	"1528395665_code_expertise.up.sql":                                 {_1528395665_code_expertiseUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -45,6 +45,24 @@ const CriticalSchemaJSON = `{
       "examples": [{ "sentry": { "dsn": "https://mykey@sentry.io/myproject" } }],
       "group": "Misc."
     },
+    "maintenanceMode": {
+      "description": "Maintenance mode makes the site read-only during upgrades and database maintenance. While it is enabled, mutations and HTTP API endpoints that write are rejected, background jobs that write to the database are paused, and a banner with the message is shown to all users. Searching and browsing code keep working. Site admins can also toggle it with the ` + "`" + `setMaintenanceMode` + "`" + ` GraphQL mutation (unless the critical configuration is set with CRITICAL_CONFIG_FILE).",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether maintenance mode is enabled.",
+          "type": "boolean",
+          "default": false
+        },
+        "message": {
+          "description": "The message shown to users while maintenance mode is enabled, such as when the maintenance is expected to end.",
+          "type": "string"
+        }
+      },
+      "examples": [{ "enabled": true, "message": "Sourcegraph is being upgraded and is read-only until 18:00 UTC." }],
+      "group": "Misc."
+    },
     "externalURL": {
       "description": "The externally accessible URL for Sourcegraph (i.e., what you type into your browser). Previously called ` + "`" + `appURL` + "`" + `.",
       "type": "string",
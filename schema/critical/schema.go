@@ -120,6 +120,8 @@ type CriticalConfiguration struct {
 	LightstepProject string `json:"lightstepProject,omitempty"`
 	// Log description: Configuration for logging and alerting, including to external services.
 	Log *Log `json:"log,omitempty"`
+	// MaintenanceMode description: Maintenance mode makes the site read-only during upgrades and database maintenance. While it is enabled, mutations and HTTP API endpoints that write are rejected, background jobs that write to the database are paused, and a banner with the message is shown to all users. Searching and browsing code keep working. Site admins can also toggle it with the `setMaintenanceMode` GraphQL mutation (unless the critical configuration is set with CRITICAL_CONFIG_FILE).
+	MaintenanceMode *MaintenanceMode `json:"maintenanceMode,omitempty"`
 	// Migrated description: Whether the critical configuration has been migrated to the site configuration yet.
 	Migrated bool `json:"migrated,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
@@ -181,6 +183,14 @@ type Log struct {
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
 
+// MaintenanceMode description: Maintenance mode makes the site read-only during upgrades and database maintenance. While it is enabled, mutations and HTTP API endpoints that write are rejected, background jobs that write to the database are paused, and a banner with the message is shown to all users. Searching and browsing code keep working. Site admins can also toggle it with the `setMaintenanceMode` GraphQL mutation (unless the critical configuration is set with CRITICAL_CONFIG_FILE).
+type MaintenanceMode struct {
+	// Enabled description: Whether maintenance mode is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// Message description: The message shown to users while maintenance mode is enabled, such as when the maintenance is expected to end.
+	Message string `json:"message,omitempty"`
+}
+
 // OpenIDConnectAuthProvider description: Configures the OpenID Connect authentication provider for SSO.
 type OpenIDConnectAuthProvider struct {
 	// ClientID description: The client ID for the OpenID Connect client for this site.
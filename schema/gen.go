@@ -1,7 +1,7 @@
 package schema
 
 //go:generate env GOBIN=$PWD/.bin GO111MODULE=on go install github.com/sourcegraph/go-jsonschema/cmd/go-jsonschema-compiler
-//go:generate $PWD/.bin/go-jsonschema-compiler -o schema.go -pkg schema aws_codecommit.schema.json bitbucket_cloud.schema.json bitbucket_server.schema.json site.schema.json settings.schema.json github.schema.json gitlab.schema.json gitolite.schema.json other_external_service.schema.json phabricator.schema.json
+//go:generate $PWD/.bin/go-jsonschema-compiler -o schema.go -pkg schema aws_codecommit.schema.json bitbucket_cloud.schema.json bitbucket_server.schema.json site.schema.json settings.schema.json github.schema.json gitlab.schema.json gitolite.schema.json gomodules.schema.json maven.schema.json npm.schema.json other_external_service.schema.json perforce.schema.json phabricator.schema.json svn.schema.json
 //go:generate $PWD/.bin/go-jsonschema-compiler -o critical/schema.go -pkg critical critical/critical.schema.json
 
 //go:generate env GO111MODULE=on go run stringdata.go -i aws_codecommit.schema.json -name AWSCodeCommitSchemaJSON -pkg schema -o aws_codecommit_stringdata.go
@@ -13,6 +13,11 @@ package schema
 //go:generate env GO111MODULE=on go run stringdata.go -i github.schema.json -name GitHubSchemaJSON -pkg schema -o github_stringdata.go
 //go:generate env GO111MODULE=on go run stringdata.go -i gitlab.schema.json -name GitLabSchemaJSON -pkg schema -o gitlab_stringdata.go
 //go:generate env GO111MODULE=on go run stringdata.go -i gitolite.schema.json -name GitoliteSchemaJSON -pkg schema -o gitolite_stringdata.go
+//go:generate env GO111MODULE=on go run stringdata.go -i gomodules.schema.json -name GoModulesSchemaJSON -pkg schema -o gomodules_stringdata.go
+//go:generate env GO111MODULE=on go run stringdata.go -i maven.schema.json -name MavenSchemaJSON -pkg schema -o maven_stringdata.go
+//go:generate env GO111MODULE=on go run stringdata.go -i npm.schema.json -name NPMSchemaJSON -pkg schema -o npm_stringdata.go
 //go:generate env GO111MODULE=on go run stringdata.go -i other_external_service.schema.json -name OtherExternalServiceSchemaJSON -pkg schema -o other_external_service_stringdata.go
+//go:generate env GO111MODULE=on go run stringdata.go -i perforce.schema.json -name PerforceSchemaJSON -pkg schema -o perforce_stringdata.go
 //go:generate env GO111MODULE=on go run stringdata.go -i phabricator.schema.json -name PhabricatorSchemaJSON -pkg schema -o phabricator_stringdata.go
+//go:generate env GO111MODULE=on go run stringdata.go -i svn.schema.json -name SVNSchemaJSON -pkg schema -o svn_stringdata.go
 //go:generate gofmt -s -w critical/critical_stringdata.go site_stringdata.go settings_stringdata.go
@@ -288,6 +288,18 @@ type BuiltinAuthProvider struct {
 	AllowSignup bool   `json:"allowSignup,omitempty"`
 	Type        string `json:"type"`
 }
+type CampaignScript struct {
+	// CpuSeconds description: The maximum CPU time, in seconds, the script may use in each repository.
+	CpuSeconds int `json:"cpuSeconds,omitempty"`
+	// MemoryMB description: The maximum virtual memory, in megabytes, that each process of the script may use.
+	MemoryMB int `json:"memoryMB,omitempty"`
+	// Name description: The unique name that identifies the script when creating a campaign plan.
+	Name string `json:"name"`
+	// Run description: The shell script to run in the root directory of the checkout.
+	Run string `json:"run"`
+	// Timeout description: The maximum wall-clock time the script may run for in each repository, as a duration (e.g., "90s" or "5m").
+	Timeout string `json:"timeout,omitempty"`
+}
 
 // CloneURLToRepositoryName description: Describes a mapping from clone URL to repository name. The `from` field contains a regular expression with named capturing groups. The `to` field contains a template string that references capturing group names. For instance, if `from` is "^../(?P<name>\w+)$" and `to` is "github.com/user/{name}", the clone URL "../myRepository" would be mapped to the repository name "github.com/user/myRepository".
 type CloneURLToRepositoryName struct {
@@ -365,6 +377,10 @@ type ExperimentalFeatures struct {
 	Automation string `json:"automation,omitempty"`
 	// BitbucketServerFastPerm description: DEPRECATED: Configure in Bitbucket Server config.
 	BitbucketServerFastPerm string `json:"bitbucketServerFastPerm,omitempty"`
+	// CodeCloneDetection description: Enables indexing the default branches of repositories for code clone detection, so that near-duplicate regions of files across repositories can be found.
+	CodeCloneDetection string `json:"codeCloneDetection,omitempty"`
+	// CodeExpertise description: Enables indexing the history of the default branches of repositories, so that the people with the most expertise in a directory can be listed.
+	CodeExpertise string `json:"codeExpertise,omitempty"`
 	// CustomGitFetch description: JSON array of configuration that maps from Git clone URL domain/path to custom git fetch command.
 	CustomGitFetch []*CustomGitFetchMapping `json:"customGitFetch,omitempty"`
 	// DebugLog description: Turns on debug logging for specific debugging scenarios.
@@ -373,6 +389,10 @@ type ExperimentalFeatures struct {
 	Discussions string `json:"discussions,omitempty"`
 	// EventLogging description: Enables user event logging inside of the Sourcegraph instance. This will allow admins to have greater visibility of user activity, such as frequently viewed pages, frequent searches, and more. These event logs (and any specific user actions) are only stored locally, and never leave this Sourcegraph instance.
 	EventLogging string `json:"eventLogging,omitempty"`
+	// GoModuleProxy description: Enables the Go module proxy at /.api/go-proxy, which serves the tagged versions of the Go modules in mirrored repositories to the go command (with GOPROXY).
+	GoModuleProxy string `json:"goModuleProxy,omitempty"`
+	// PullRequestIndexing description: Enables indexing the merged pull requests (and merge requests) of GitHub, GitLab and Bitbucket Server repositories, so that commits and blame hunks link to the pull requests that introduced them.
+	PullRequestIndexing string `json:"pullRequestIndexing,omitempty"`
 	// SearchMultipleRevisionsPerRepository description: Enables searching multiple revisions of the same repository (using `repo:myrepo@branch1:branch2`).
 	SearchMultipleRevisionsPerRepository *bool `json:"searchMultipleRevisionsPerRepository,omitempty"`
 	// StructuralSearch description: Enables structural search.
@@ -583,6 +603,20 @@ type GitoliteConnection struct {
 	Prefix string `json:"prefix"`
 }
 
+// GoModulesConnection description: Configuration for a connection to a Go module proxy. Each selected module is mirrored as a Git repository with one tagged commit per published version, containing the sources of the module zip served by the proxy.
+type GoModulesConnection struct {
+	// Lockfile description: The contents of a go.sum file. Every module version it records a source checksum for is mirrored, in addition to the versions selected by packages.
+	Lockfile string `json:"lockfile,omitempty"`
+	// Packages description: Modules to mirror, in the form "path" (all published versions) or "path@version" (only that version).
+	Packages []string `json:"packages,omitempty"`
+	// Registry description: The URL of the Go module proxy (see https://golang.org/cmd/go/#hdr-Module_proxy_protocol).
+	Registry string `json:"registry,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Go module. In the pattern, the variable "{package}" is replaced with the module path.
+	//
+	// For example, a pattern of "go/{package}" yields the repository name "go/golang.org/x/net" for the module "golang.org/x/net".
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+}
+
 // HTTPHeaderAuthProvider description: Configures the HTTP header authentication provider (which authenticates users by consulting an HTTP request header set by an authentication proxy such as https://github.com/bitly/oauth2_proxy).
 type HTTPHeaderAuthProvider struct {
 	// StripUsernameHeaderPrefix description: The prefix that precedes the username portion of the HTTP header specified in `usernameHeader`. If specified, the prefix will be stripped from the header value and the remainder will be used as the username. For example, if using Google Identity-Aware Proxy (IAP) with Google Sign-In, set this value to `accounts.google.com:`.
@@ -646,6 +680,42 @@ type Log struct {
 	// Sentry description: Configuration for Sentry
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
+
+// MaintenanceMode description: Maintenance mode makes the site read-only during upgrades and database maintenance. While it is enabled, mutations and HTTP API endpoints that write are rejected, background jobs that write to the database are paused, and a banner with the message is shown to all users. Searching and browsing code keep working. Site admins can also toggle it with the `setMaintenanceMode` GraphQL mutation (unless the critical configuration is set with CRITICAL_CONFIG_FILE).
+type MaintenanceMode struct {
+	// Enabled description: Whether maintenance mode is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// Message description: The message shown to users while maintenance mode is enabled, such as when the maintenance is expected to end.
+	Message string `json:"message,omitempty"`
+}
+
+// MavenConnection description: Configuration for a connection to a Maven repository (such as Maven Central). Each selected artifact is mirrored as a Git repository with one tagged commit per published version, containing the sources of the published sources JAR.
+type MavenConnection struct {
+	// Lockfile description: The contents of a Gradle dependency lock file (gradle.lockfile). Every artifact version it locks is mirrored, in addition to the versions selected by packages.
+	Lockfile string `json:"lockfile,omitempty"`
+	// Packages description: Artifacts to mirror, in the form "group:artifact" (all published versions) or "group:artifact:version" (only that version).
+	Packages []string `json:"packages,omitempty"`
+	// Registry description: The URL of the Maven repository.
+	Registry string `json:"registry,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Maven artifact. In the pattern, the variable "{package}" is replaced with the group and artifact ID of the artifact, separated by a slash.
+	//
+	// For example, a pattern of "maven/{package}" yields the repository name "maven/org.apache.commons/commons-lang3" for the artifact "org.apache.commons:commons-lang3".
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+}
+
+// NPMConnection description: Configuration for a connection to an npm registry. Each selected package is mirrored as a Git repository with one tagged commit per published version, containing the sources of the published tarball.
+type NPMConnection struct {
+	// Lockfile description: The contents of a package-lock.json file. Every package version it locks is mirrored, in addition to the versions selected by packages.
+	Lockfile string `json:"lockfile,omitempty"`
+	// Packages description: Packages to mirror, in the form "name" (all published versions) or "name@version" (only that version).
+	Packages []string `json:"packages,omitempty"`
+	// Registry description: The URL of the npm registry.
+	Registry string `json:"registry,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for an npm package. In the pattern, the variable "{package}" is replaced with the name of the package.
+	//
+	// For example, a pattern of "npm/{package}" yields the repository name "npm/@babel/core" for the package "@babel/core".
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+}
 type Notice struct {
 	// Dismissible description: Whether this notice can be dismissed (closed) by the user.
 	Dismissible bool `json:"dismissible,omitempty"`
@@ -701,6 +771,40 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PerforceConnection description: Configuration for a connection to a Perforce server. Each selected depot path (or stream) is mirrored as a Git repository by converting its changelists into commits.
+type PerforceConnection struct {
+	// Depots description: Depot paths to mirror. Each path becomes a repository. A path that names the root of a stream depot (e.g., "//streams/") selects every stream of that depot instead, each of which becomes a repository.
+	Depots []string `json:"depots"`
+	// P4Passwd description: The password or ticket of p4.user (P4PASSWD).
+	P4Passwd string `json:"p4.passwd"`
+	// P4Port description: The Perforce server address (P4PORT) to connect to, optionally prefixed with "ssl:" or "tcp:".
+	P4Port string `json:"p4.port"`
+	// P4User description: The user to authenticate to the Perforce server as (P4USER). It must be able to read all selected depot paths.
+	P4User string `json:"p4.user"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Perforce depot path. In the pattern, the variable "{host}" is replaced with the Perforce server host name, and "{depot}" is replaced with the depot path without its leading and trailing slashes.
+	//
+	// For example, if the server is perforce.example.com:1666 and the depot path is "//depot/engine/", then a pattern of "{host}/{depot}" yields the repository name "perforce.example.com/depot/engine".
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+}
+
+// PermissionsAccessRequests description: Settings for repository access requests, which let users request read access to repositories they can't view. An approved request grants the user explicit (optionally time-limited) read access to the matching repositories, in addition to the permissions of the authorization providers. Site admins can review all requests.
+type PermissionsAccessRequests struct {
+	// Approvers description: The users and organizations (in addition to site admins) who can review access requests for repositories.
+	Approvers []*RepoAccessApprovers `json:"approvers,omitempty"`
+	// Enabled description: Whether users can request access to repositories and whether the access granted by approved requests is enforced. Disabling it suspends (but does not delete) all grants.
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxDuration description: The maximum duration of the access granted by an approved request, as a duration (e.g., "720h"). If set, approvers must choose an expiry within this duration. If not set, access may also be granted permanently.
+	MaxDuration string `json:"maxDuration,omitempty"`
+}
+
+// PermissionsSubRepository description: Settings for path-level (sub-repository) permissions, which restrict read access to paths within repositories that a user can otherwise view. Path rules never grant access to a repository the user cannot view, and site admins are never restricted.
+type PermissionsSubRepository struct {
+	// Enabled description: Whether path rules are enforced. When enabled, the rules configured via the GraphQL API are always applied.
+	Enabled bool `json:"enabled,omitempty"`
+	// RulesFile description: Whether to also apply the path rules in the `.sourcegraph/path-permissions` file on the default branch of each repository.
+	RulesFile bool `json:"rulesFile,omitempty"`
+}
+
 // PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's `authorization` field is set).
 type PermissionsUserMapping struct {
 	// BindID description: The type of identifier to identify a user. The default is "email", which uses the email address to identify a user. Use "username" to identify a user by their username. Changing this setting will erase any permissions created for users that do not yet exist.
@@ -726,6 +830,20 @@ type PhabricatorConnection struct {
 	// Url description: URL of a Phabricator instance, such as https://phabricator.example.com
 	Url string `json:"url,omitempty"`
 }
+
+// PushMirror description: Describes the push mirrors of a set of repositories. The `repos` field contains a regular expression that matches repository names, with optional named capturing groups. The `url` field contains a template of the mirror's Git remote URL that references the capturing groups (and `{repo}` for the whole repository name). For instance, if `repos` is "^github\.com/acme/(?P<name>.+)$" and `url` is "https://backup.example.com/acme/{name}.git", the repository "github.com/acme/api" is pushed to "https://backup.example.com/acme/api.git".
+type PushMirror struct {
+	// Password description: The password or access token to push to the mirror with (if it isn't in the URL).
+	Password string `json:"password,omitempty"`
+	// Refspecs description: The refspecs to push. Refspecs starting with "+" overwrite the refs of the mirror even if they aren't fast-forwards. By default, all branches and tags are pushed and overwritten.
+	Refspecs []string `json:"refspecs,omitempty"`
+	// Repos description: A regular expression that matches the names of the repositories to push. The regular expression should use the Go regular expression syntax (https://golang.org/pkg/regexp/). It matches partially, so use "^...$" to match a single repository.
+	Repos string `json:"repos"`
+	// Url description: The URL of the push mirror's Git remote. It can reference the named capturing groups of `repos` (such as `{name}`) and the repository name (`{repo}`).
+	Url string `json:"url"`
+	// Username description: The username to push to the mirror with (if it isn't in the URL).
+	Username string `json:"username,omitempty"`
+}
 type QuickLink struct {
 	// Description description: A description for this quick link
 	Description string `json:"description,omitempty"`
@@ -734,6 +852,14 @@ type QuickLink struct {
 	// Url description: The URL of this quick link (absolute or relative)
 	Url string `json:"url"`
 }
+type RepoAccessApprovers struct {
+	// Orgs description: Names of organizations whose members are approvers.
+	Orgs []string `json:"orgs,omitempty"`
+	// Repos description: Regular expression matching the names of the repositories that the approvers can review requests for.
+	Repos string `json:"repos"`
+	// Users description: Usernames of the approvers (e.g., the maintainers of the repositories).
+	Users []string `json:"users,omitempty"`
+}
 type Repos struct {
 	// Callsign description: The unique Phabricator identifier for the repository, like 'MUX'.
 	Callsign string `json:"callsign"`
@@ -901,6 +1027,10 @@ type SiteConfiguration struct {
 	Branding *Branding `json:"branding,omitempty"`
 	// CampaignsReadAccessEnabled description: Enables read-only access to campaigns for non-site-admin users. This is a setting for the experimental campaigns feature. These will only have an effect when campaigns is enabled with `{"experimentalFeatures": {"automation": "enabled"}}`.
 	CampaignsReadAccessEnabled *bool `json:"campaigns.readAccess.enabled,omitempty"`
+	// CampaignsScripts description: Transformation scripts that may be run on the server to generate campaign plans (with the createCampaignPlanFromScript GraphQL mutation). Each script runs in a temporary checkout of the default branch of every target repository, without network access and under the configured resource limits, and the changes it makes to the checkout become the campaign plan's patches. This is a setting for the experimental campaigns feature.
+	CampaignsScripts []*CampaignScript `json:"campaigns.scripts,omitempty"`
+	// CodeExpertiseExcludePatterns description: Regular expressions (RE2 syntax) matching the names, emails and code host usernames of people (such as bots) who are excluded from code expertise rankings. Defaults to patterns matching GitHub App bots and common dependency update bots. Requires the "codeExpertise" experimental feature.
+	CodeExpertiseExcludePatterns []string `json:"codeExpertise.excludePatterns,omitempty"`
 	// CorsOrigin description: Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.
 	CorsOrigin string `json:"corsOrigin,omitempty"`
 	// DebugSearchSymbolsParallelism description: (debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.
@@ -923,6 +1053,8 @@ type SiteConfiguration struct {
 	EmailImap *IMAPServerConfig `json:"email.imap,omitempty"`
 	// EmailSmtp description: The SMTP server used to send transactional emails (such as email verifications, reset-password emails, and notifications).
 	EmailSmtp *SMTPServerConfig `json:"email.smtp,omitempty"`
+	// EventLogsRetentionDays description: The number of days for which raw user events are retained. Older events are deleted once they have been aggregated into the daily and weekly usage statistics rollups, which are retained indefinitely.
+	EventLogsRetentionDays int `json:"eventLogs.retentionDays,omitempty"`
 	// ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
 	ExperimentalFeatures *ExperimentalFeatures `json:"experimentalFeatures,omitempty"`
 	// Extensions description: Configures Sourcegraph extensions.
@@ -931,6 +1063,8 @@ type SiteConfiguration struct {
 	ExternalURL string `json:"externalURL,omitempty"`
 	// GitCloneURLToRepositoryName description: JSON array of configuration that maps from Git clone URL to repository name. Sourcegraph automatically resolves remote clone URLs to their proper code host. However, there may be non-remote clone URLs (e.g., in submodule declarations) that Sourcegraph cannot automatically map to a code host. In this case, use this field to specify the mapping. The mappings are tried in the order they are specified and take precedence over automatic mappings.
 	GitCloneURLToRepositoryName []*CloneURLToRepositoryName `json:"git.cloneURLToRepositoryName,omitempty"`
+	// GitPushMirrors description: JSON array of push mirrors, which are Git remotes (such as repositories on a second code host, for disaster recovery) that repositories are copied to. After each successful update of a repository, gitserver pushes the repository to the push mirrors that match its name. Failed pushes are retried with exponential backoff.
+	GitPushMirrors []*PushMirror `json:"git.pushMirrors,omitempty"`
 	// GitMaxConcurrentClones description: Maximum number of git clone processes that will be run concurrently to update repositories.
 	GitMaxConcurrentClones int `json:"gitMaxConcurrentClones,omitempty"`
 	// GithubClientID description: Client ID for GitHub. (DEPRECATED)
@@ -955,10 +1089,16 @@ type SiteConfiguration struct {
 	Log *Log `json:"log,omitempty"`
 	// LsifEnforceAuth description: Whether or not LSIF uploads will be blocked unless a valid LSIF upload token is provided.
 	LsifEnforceAuth bool `json:"lsifEnforceAuth,omitempty"`
+	// MaintenanceMode description: Maintenance mode makes the site read-only during upgrades and database maintenance. While it is enabled, mutations and HTTP API endpoints that write are rejected, background jobs that write to the database are paused, and a banner with the message is shown to all users. Searching and browsing code keep working. Site admins can also toggle it with the `setMaintenanceMode` GraphQL mutation (unless the critical configuration is set with CRITICAL_CONFIG_FILE).
+	MaintenanceMode *MaintenanceMode `json:"maintenanceMode,omitempty"`
 	// MaxReposToSearch description: The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxReposToSearch int `json:"maxReposToSearch,omitempty"`
 	// ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
 	ParentSourcegraph *ParentSourcegraph `json:"parentSourcegraph,omitempty"`
+	// PermissionsAccessRequests description: Settings for repository access requests, which let users request read access to repositories they can't view. An approved request grants the user explicit (optionally time-limited) read access to the matching repositories, in addition to the permissions of the authorization providers. Site admins can review all requests.
+	PermissionsAccessRequests *PermissionsAccessRequests `json:"permissions.accessRequests,omitempty"`
+	// PermissionsSubRepository description: Settings for path-level (sub-repository) permissions, which restrict read access to paths within repositories that a user can otherwise view. Path rules never grant access to a repository the user cannot view, and site admins are never restricted.
+	PermissionsSubRepository *PermissionsSubRepository `json:"permissions.subRepository,omitempty"`
 	// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's `authorization` field is set).
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
@@ -969,6 +1109,10 @@ type SiteConfiguration struct {
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. The glob pattern syntax can be found here: https://golang.org/pkg/path/filepath/#Match.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
+	// SlackApp description: The credentials of a Slack app (created at https://api.slack.com/apps) that lets Slack users search Sourcegraph with the /sourcegraph slash command and unfurls links to Sourcegraph in Slack messages. Slack users link their Slack account to their Sourcegraph account (with OAuth) before using the app, and searches and unfurls run with the permissions of the linked Sourcegraph user.
+	SlackApp *SlackApp `json:"slackApp,omitempty"`
+	// SnippetShareLinks description: Settings for snippet share links, which let users share a range of lines of a file at a fixed commit with people who don't have an account. Anyone with a link can view its snippet without signing in (after entering the link's password, if any) until the link expires, reaches its view limit or is revoked. All views are recorded in the link's audit log. If not set, snippet share links can't be created.
+	SnippetShareLinks *SnippetShareLinks `json:"snippetShareLinks,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: Use local Jaeger instance for tracing. Kubernetes cluster deployments only.
@@ -987,6 +1131,58 @@ type SiteConfiguration struct {
 	UseJaeger bool `json:"useJaeger,omitempty"`
 }
 
+// SlackApp description: The credentials of a Slack app (created at https://api.slack.com/apps) that lets Slack users search Sourcegraph with the /sourcegraph slash command and unfurls links to Sourcegraph in Slack messages. Slack users link their Slack account to their Sourcegraph account (with OAuth) before using the app, and searches and unfurls run with the permissions of the linked Sourcegraph user.
+type SlackApp struct {
+	// BotToken description: The bot user OAuth access token (xoxb-...) of the Slack app's installation in the workspace, used to unfurl links. If not set, links are not unfurled.
+	BotToken string `json:"botToken,omitempty"`
+	// ClientID description: The client ID of the Slack app, used to link Slack accounts.
+	ClientID string `json:"clientID,omitempty"`
+	// ClientSecret description: The client secret of the Slack app, used to link Slack accounts.
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// SigningSecret description: The signing secret of the Slack app, used to verify that requests come from Slack.
+	SigningSecret string `json:"signingSecret"`
+}
+
+// SnippetShareLinks description: Settings for snippet share links, which let users share a range of lines of a file at a fixed commit with people who don't have an account. Anyone with a link can view its snippet without signing in (after entering the link's password, if any) until the link expires, reaches its view limit or is revoked. All views are recorded in the link's audit log. If not set, snippet share links can't be created.
+type SnippetShareLinks struct {
+	// MaxDuration description: The maximum duration until a snippet share link expires, as a duration (e.g., "168h").
+	MaxDuration string `json:"maxDuration,omitempty"`
+	// Repos description: Regular expressions matching the names of the repositories whose files may be shared. Users can only share files of repositories that they can view.
+	Repos []string `json:"repos,omitempty"`
+	// RequirePassword description: Whether snippet share links must have a password.
+	RequirePassword bool `json:"requirePassword,omitempty"`
+}
+
+// SubversionConnection description: Configuration for a connection to Subversion repositories. Each repository is mirrored as a Git repository with git-svn.
+type SubversionConnection struct {
+	// Authors description: Maps Subversion usernames to the Git author identities used for the commits they made, in the form "Name <email>". Commits by users that are not listed use the Subversion username as both name and email.
+	Authors map[string]string `json:"authors,omitempty"`
+	// Layout description: The directory layout of the repositories. If omitted, the standard layout (trunk/, branches/*, tags/*) is used. Trunk becomes the master branch, each branch directory becomes a branch and each tag directory becomes a tag.
+	Layout *SubversionLayout `json:"layout,omitempty"`
+	// Password description: The password of username.
+	Password string `json:"password,omitempty"`
+	// Repos description: The names of the repositories (children of url) to mirror. If omitted, all repositories that `svn list` returns for url are mirrored.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Subversion repository. In the pattern, the variable "{host}" is replaced with the host name of url, and "{repo}" is replaced with the name of the repository.
+	//
+	// For example, if url is "https://svn.example.com/repos/" and the repository is "engine", then a pattern of "{host}/{repo}" yields the repository name "svn.example.com/engine".
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: URL of the directory that contains the Subversion repositories (e.g., the SVNParentPath of an Apache server or the root of an svnserve server). Each repository is a child of this URL.
+	Url string `json:"url"`
+	// Username description: The username to authenticate to the Subversion server with.
+	Username string `json:"username,omitempty"`
+}
+
+// SubversionLayout description: The directory layout of the repositories. If omitted, the standard layout (trunk/, branches/*, tags/*) is used. Trunk becomes the master branch, each branch directory becomes a branch and each tag directory becomes a tag.
+type SubversionLayout struct {
+	// Branches description: The paths of the directories that contain branches, relative to the repository root.
+	Branches []string `json:"branches,omitempty"`
+	// Tags description: The paths of the directories that contain tags, relative to the repository root.
+	Tags []string `json:"tags,omitempty"`
+	// Trunk description: The path of the trunk, relative to the repository root. Use "" to mirror the whole repository as a single branch.
+	Trunk string `json:"trunk"`
+}
+
 // TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 type TlsExternal struct {
 	// Certificates description: TLS certificates to accept. This is only necessary if you are using self-signed certificates or an internal CA. Can be an internal CA certificate or a self-signed certificate. To get the certificate of a webserver run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
@@ -90,6 +90,30 @@ const SiteSchemaJSON = `{
           "enum": ["enabled", "disabled"],
           "default": "enabled"
         },
+        "pullRequestIndexing": {
+          "description": "Enables indexing the merged pull requests (and merge requests) of GitHub, GitLab and Bitbucket Server repositories, so that commits and blame hunks link to the pull requests that introduced them.",
+          "type": "string",
+          "enum": ["enabled", "disabled"],
+          "default": "disabled"
+        },
+        "codeCloneDetection": {
+          "description": "Enables indexing the default branches of repositories for code clone detection, so that near-duplicate regions of files across repositories can be found.",
+          "type": "string",
+          "enum": ["enabled", "disabled"],
+          "default": "disabled"
+        },
+        "codeExpertise": {
+          "description": "Enables indexing the history of the default branches of repositories, so that the people with the most expertise in a directory can be listed.",
+          "type": "string",
+          "enum": ["enabled", "disabled"],
+          "default": "disabled"
+        },
+        "goModuleProxy": {
+          "description": "Enables the Go module proxy at /.api/go-proxy, which serves the tagged versions of the Go modules in mirrored repositories to the go command (with GOPROXY).",
+          "type": "string",
+          "enum": ["enabled", "disabled"],
+          "default": "disabled"
+        },
         "bitbucketServerFastPerm": {
           "description": "DEPRECATED: Configure in Bitbucket Server config.",
           "type": "string",
@@ -173,6 +197,55 @@ const SiteSchemaJSON = `{
       "!go": { "pointer": true },
       "group": "Campaigns"
     },
+    "campaigns.scripts": {
+      "description": "Transformation scripts that may be run on the server to generate campaign plans (with the createCampaignPlanFromScript GraphQL mutation). Each script runs in a temporary checkout of the default branch of every target repository, without network access and under the configured resource limits, and the changes it makes to the checkout become the campaign plan's patches. This is a setting for the experimental campaigns feature.",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "title": "CampaignScript",
+        "additionalProperties": false,
+        "required": ["name", "run"],
+        "properties": {
+          "name": {
+            "description": "The unique name that identifies the script when creating a campaign plan.",
+            "type": "string",
+            "pattern": "^[\\w.-]+$"
+          },
+          "run": {
+            "description": "The shell script to run in the root directory of the checkout.",
+            "type": "string",
+            "minLength": 1
+          },
+          "timeout": {
+            "description": "The maximum wall-clock time the script may run for in each repository, as a duration (e.g., \"90s\" or \"5m\").",
+            "type": "string",
+            "default": "5m"
+          },
+          "cpuSeconds": {
+            "description": "The maximum CPU time, in seconds, the script may use in each repository.",
+            "type": "integer",
+            "minimum": 1,
+            "default": 300
+          },
+          "memoryMB": {
+            "description": "The maximum virtual memory, in megabytes, that each process of the script may use.",
+            "type": "integer",
+            "minimum": 16,
+            "default": 2048
+          }
+        }
+      },
+      "examples": [
+        [
+          {
+            "name": "gofmt",
+            "run": "gofmt -s -w .",
+            "timeout": "2m"
+          }
+        ]
+      ],
+      "group": "Campaigns"
+    },
     "corsOrigin": {
       "description": "Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.",
       "type": "string",
@@ -192,6 +265,23 @@ const SiteSchemaJSON = `{
       "default": false,
       "group": "Misc."
     },
+    "eventLogs.retentionDays": {
+      "description": "The number of days for which raw user events are retained. Older events are deleted once they have been aggregated into the daily and weekly usage statistics rollups, which are retained indefinitely.",
+      "type": "integer",
+      "minimum": 14,
+      "default": 93,
+      "group": "Misc."
+    },
+    "codeExpertise.excludePatterns": {
+      "description": "Regular expressions (RE2 syntax) matching the names, emails and code host usernames of people (such as bots) who are excluded from code expertise rankings. Defaults to patterns matching GitHub App bots and common dependency update bots. Requires the \"codeExpertise\" experimental feature.",
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "default": ["\\[bot\\]", "(?i)^(dependabot|renovate|greenkeeper)"],
+      "group": "Misc.",
+      "examples": [["\\[bot\\]", "^ci@example\\.com$"]]
+    },
     "disableAutoGitUpdates": {
       "description": "Disable periodically fetching git contents for existing repositories.",
       "type": "boolean",
@@ -225,6 +315,57 @@ const SiteSchemaJSON = `{
       },
       "group": "External services"
     },
+    "git.pushMirrors": {
+      "description": "JSON array of push mirrors, which are Git remotes (such as repositories on a second code host, for disaster recovery) that repositories are copied to. After each successful update of a repository, gitserver pushes the repository to the push mirrors that match its name. Failed pushes are retried with exponential backoff.",
+      "type": "array",
+      "items": {
+        "title": "PushMirror",
+        "description": "Describes the push mirrors of a set of repositories. The ` + "`" + `repos` + "`" + ` field contains a regular expression that matches repository names, with optional named capturing groups. The ` + "`" + `url` + "`" + ` field contains a template of the mirror's Git remote URL that references the capturing groups (and ` + "`" + `{repo}` + "`" + ` for the whole repository name). For instance, if ` + "`" + `repos` + "`" + ` is \"^github\\.com/acme/(?P<name>.+)$\" and ` + "`" + `url` + "`" + ` is \"https://backup.example.com/acme/{name}.git\", the repository \"github.com/acme/api\" is pushed to \"https://backup.example.com/acme/api.git\".",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["repos", "url"],
+        "properties": {
+          "repos": {
+            "description": "A regular expression that matches the names of the repositories to push. The regular expression should use the Go regular expression syntax (https://golang.org/pkg/regexp/). It matches partially, so use \"^...$\" to match a single repository.",
+            "type": "string",
+            "minLength": 1
+          },
+          "url": {
+            "description": "The URL of the push mirror's Git remote. It can reference the named capturing groups of ` + "`" + `repos` + "`" + ` (such as ` + "`" + `{name}` + "`" + `) and the repository name (` + "`" + `{repo}` + "`" + `).",
+            "type": "string",
+            "minLength": 1
+          },
+          "username": {
+            "description": "The username to push to the mirror with (if it isn't in the URL).",
+            "type": "string"
+          },
+          "password": {
+            "description": "The password or access token to push to the mirror with (if it isn't in the URL).",
+            "type": "string"
+          },
+          "refspecs": {
+            "description": "The refspecs to push. Refspecs starting with \"+\" overwrite the refs of the mirror even if they aren't fast-forwards. By default, all branches and tags are pushed and overwritten.",
+            "type": "array",
+            "items": {
+              "type": "string",
+              "minLength": 1
+            },
+            "default": ["+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"]
+          }
+        }
+      },
+      "examples": [
+        [
+          {
+            "repos": "^github\\.com/acme/(?P<name>.+)$",
+            "url": "https://backup.example.com/acme/{name}.git",
+            "username": "sourcegraph",
+            "password": "secret-token"
+          }
+        ]
+      ],
+      "group": "External services"
+    },
     "githubClientID": {
       "description": "Client ID for GitHub. (DEPRECATED)",
       "type": "string",
@@ -314,6 +455,127 @@ const SiteSchemaJSON = `{
       "examples": [{ "bindID": "email" }, { "bindID": "username" }],
       "group": "Security"
     },
+    "permissions.subRepository": {
+      "description": "Settings for path-level (sub-repository) permissions, which restrict read access to paths within repositories that a user can otherwise view. Path rules never grant access to a repository the user cannot view, and site admins are never restricted.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether path rules are enforced. When enabled, the rules configured via the GraphQL API are always applied.",
+          "type": "boolean",
+          "default": false
+        },
+        "rulesFile": {
+          "description": "Whether to also apply the path rules in the ` + "`" + `.sourcegraph/path-permissions` + "`" + ` file on the default branch of each repository.",
+          "type": "boolean",
+          "default": false
+        }
+      },
+      "examples": [{ "enabled": true, "rulesFile": true }],
+      "group": "Security"
+    },
+    "permissions.accessRequests": {
+      "description": "Settings for repository access requests, which let users request read access to repositories they can't view. An approved request grants the user explicit (optionally time-limited) read access to the matching repositories, in addition to the permissions of the authorization providers. Site admins can review all requests.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether users can request access to repositories and whether the access granted by approved requests is enforced. Disabling it suspends (but does not delete) all grants.",
+          "type": "boolean",
+          "default": false
+        },
+        "approvers": {
+          "description": "The users and organizations (in addition to site admins) who can review access requests for repositories.",
+          "type": "array",
+          "items": {
+            "type": "object",
+            "title": "RepoAccessApprovers",
+            "additionalProperties": false,
+            "required": ["repos"],
+            "properties": {
+              "repos": {
+                "description": "Regular expression matching the names of the repositories that the approvers can review requests for.",
+                "type": "string",
+                "format": "regex"
+              },
+              "users": {
+                "description": "Usernames of the approvers (e.g., the maintainers of the repositories).",
+                "type": "array",
+                "items": { "type": "string" }
+              },
+              "orgs": {
+                "description": "Names of organizations whose members are approvers.",
+                "type": "array",
+                "items": { "type": "string" }
+              }
+            }
+          }
+        },
+        "maxDuration": {
+          "description": "The maximum duration of the access granted by an approved request, as a duration (e.g., \"720h\"). If set, approvers must choose an expiry within this duration. If not set, access may also be granted permanently.",
+          "type": "string"
+        }
+      },
+      "examples": [
+        {
+          "enabled": true,
+          "approvers": [{ "repos": "^github\\.com/acme/payments-", "users": ["alice"], "orgs": ["payments-team"] }],
+          "maxDuration": "2160h"
+        }
+      ],
+      "group": "Security"
+    },
+    "snippetShareLinks": {
+      "description": "Settings for snippet share links, which let users share a range of lines of a file at a fixed commit with people who don't have an account. Anyone with a link can view its snippet without signing in (after entering the link's password, if any) until the link expires, reaches its view limit or is revoked. All views are recorded in the link's audit log. If not set, snippet share links can't be created.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "repos": {
+          "description": "Regular expressions matching the names of the repositories whose files may be shared. Users can only share files of repositories that they can view.",
+          "type": "array",
+          "items": { "type": "string", "format": "regex" }
+        },
+        "maxDuration": {
+          "description": "The maximum duration until a snippet share link expires, as a duration (e.g., \"168h\").",
+          "type": "string",
+          "default": "168h"
+        },
+        "requirePassword": {
+          "description": "Whether snippet share links must have a password.",
+          "type": "boolean",
+          "default": false
+        }
+      },
+      "examples": [{ "repos": ["^github\\.com/acme/sdk-"], "maxDuration": "72h", "requirePassword": true }],
+      "group": "Security"
+    },
+    "slackApp": {
+      "description": "The credentials of a Slack app (created at https://api.slack.com/apps) that lets Slack users search Sourcegraph with the /sourcegraph slash command and unfurls links to Sourcegraph in Slack messages. Slack users link their Slack account to their Sourcegraph account (with OAuth) before using the app, and searches and unfurls run with the permissions of the linked Sourcegraph user.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["signingSecret"],
+      "properties": {
+        "clientID": {
+          "description": "The client ID of the Slack app, used to link Slack accounts.",
+          "type": "string"
+        },
+        "clientSecret": {
+          "description": "The client secret of the Slack app, used to link Slack accounts.",
+          "type": "string"
+        },
+        "signingSecret": {
+          "description": "The signing secret of the Slack app, used to verify that requests come from Slack.",
+          "type": "string",
+          "minLength": 1
+        },
+        "botToken": {
+          "description": "The bot user OAuth access token (xoxb-...) of the Slack app's installation in the workspace, used to unfurl links. If not set, links are not unfurled.",
+          "type": "string"
+        }
+      },
+      "examples": [{ "clientID": "123.456", "clientSecret": "abc", "signingSecret": "def", "botToken": "xoxb-123" }],
+      "group": "Misc."
+    },
     "branding": {
       "description": "Customize Sourcegraph homepage logo and search icon.\n\nOnly available in Sourcegraph Enterprise.",
       "type": "object",
@@ -532,6 +794,24 @@ const SiteSchemaJSON = `{
       "examples": [{ "sentry": { "dsn": "https://mykey@sentry.io/myproject" } }],
       "group": "Misc."
     },
+    "maintenanceMode": {
+      "description": "Maintenance mode makes the site read-only during upgrades and database maintenance. While it is enabled, mutations and HTTP API endpoints that write are rejected, background jobs that write to the database are paused, and a banner with the message is shown to all users. Searching and browsing code keep working. Site admins can also toggle it with the ` + "`" + `setMaintenanceMode` + "`" + ` GraphQL mutation (unless the critical configuration is set with CRITICAL_CONFIG_FILE).",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether maintenance mode is enabled.",
+          "type": "boolean",
+          "default": false
+        },
+        "message": {
+          "description": "The message shown to users while maintenance mode is enabled, such as when the maintenance is expected to end.",
+          "type": "string"
+        }
+      },
+      "examples": [{ "enabled": true, "message": "Sourcegraph is being upgraded and is read-only until 18:00 UTC." }],
+      "group": "Misc."
+    },
     "externalURL": {
       "description": "The externally accessible URL for Sourcegraph (i.e., what you type into your browser). Previously called ` + "`" + `appURL` + "`" + `.",
       "type": "string",
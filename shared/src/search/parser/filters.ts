@@ -126,6 +126,11 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         description: negated =>
             `${negated ? 'Exclude' : 'Include only'} results from repos that contain a matching file`,
     },
+    [FilterType.scope]: {
+        description: 'Only match comments, strings or the rest of the code',
+        discreteValues: ['comment', 'string', 'code'],
+        singular: true,
+    },
     [FilterType.timeout]: {
         description: 'Duration before timeout',
         singular: true,
@@ -80,4 +80,5 @@ export const FilterTypeToProseNames: Record<FilterType, string> = {
     type: 'Type',
     content: 'Content',
     patterntype: 'Pattern type',
+    scope: 'Scope',
 }
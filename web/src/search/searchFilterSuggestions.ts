@@ -71,6 +71,10 @@ export const searchFilterSuggestions: SearchFilterSuggestions = {
                 value: 'archived:',
                 description: 'no | only | yes (default)',
             },
+            {
+                value: 'scope:',
+                description: 'comment | string | code (only match comments, strings or the rest of the code)',
+            },
             {
                 value: 'count:',
                 description: 'integer (number of results to fetch)',
@@ -200,4 +204,9 @@ export const searchFilterSuggestions: SearchFilterSuggestions = {
             assign({ type: FilterType.patterntype })
         ),
     },
+    scope: {
+        values: [{ value: 'comment' }, { value: 'string' }, { value: 'code' }].map(
+            assign({ type: FilterType.scope })
+        ),
+    },
 }
@@ -8,10 +8,15 @@ import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.j
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import goModulesSchemaJSON from '../../../schema/gomodules.schema.json'
+import mavenSchemaJSON from '../../../schema/maven.schema.json'
+import npmSchemaJSON from '../../../schema/npm.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
+import perforceSchemaJSON from '../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import settingsSchemaJSON from '../../../schema/settings.schema.json'
 import siteSchemaJSON from '../../../schema/site.schema.json'
+import svnSchemaJSON from '../../../schema/svn.schema.json'
 import { PageTitle } from '../components/PageTitle'
 import { ExternalServiceKind } from '../../../shared/src/graphql/schema'
 import { useObservable } from '../util/useObservable'
@@ -33,8 +38,13 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     GITHUB: githubSchemaJSON,
     GITLAB: gitlabSchemaJSON,
     GITOLITE: gitoliteSchemaJSON,
+    GOMODULES: goModulesSchemaJSON,
+    MAVEN: mavenSchemaJSON,
+    NPM: npmSchemaJSON,
     OTHER: otherExternalServiceSchemaJSON,
+    PERFORCE: perforceSchemaJSON,
     PHABRICATOR: phabricatorSchemaJSON,
+    SVN: svnSchemaJSON,
 }
 
 const allConfigSchema = {
@@ -12,8 +12,13 @@ import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.j
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import goModulesSchemaJSON from '../../../schema/gomodules.schema.json'
+import mavenSchemaJSON from '../../../schema/maven.schema.json'
+import npmSchemaJSON from '../../../schema/npm.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
+import perforceSchemaJSON from '../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
+import svnSchemaJSON from '../../../schema/svn.schema.json'
 import { PhabricatorIcon } from '../../../shared/src/components/icons'
 import * as GQL from '../../../shared/src/graphql/schema'
 import { EditorAction } from './configHelpers'
@@ -991,6 +996,253 @@ const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
         },
     ],
 }
+const PERFORCE: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.PERFORCE,
+    title: 'Perforce',
+    icon: GitIcon,
+    jsonSchema: perforceSchemaJSON,
+    defaultDisplayName: 'Perforce',
+    defaultConfig: `{
+  "p4.port": "ssl:perforce.example.com:1666",
+  "p4.user": "<user>",
+  "p4.passwd": "<password>",
+  "depots": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>p4.port</Field> to the address of the Perforce server, and
+                    set <Field>p4.user</Field> and <Field>p4.passwd</Field> to the credentials of a user that can read
+                    the depots you want to mirror.
+                </li>
+                <li>
+                    Add the depot paths to mirror to the <Field>depots</Field> field. The root of a stream depot (e.g.,{' '}
+                    <Value>//streams/</Value>) selects every stream of that depot.
+                </li>
+            </ol>
+            <p>
+                Each depot path is converted into a Git repository. See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/perforce#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addDepot',
+            label: 'Add a depot path',
+            run: config => {
+                const value = '//depot/path/'
+                const edits = setProperty(config, ['depots', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+const SVN: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.SVN,
+    title: 'Subversion',
+    icon: GitIcon,
+    jsonSchema: svnSchemaJSON,
+    defaultDisplayName: 'Subversion',
+    defaultConfig: `{
+  "url": "https://svn.example.com/repos/",
+  "username": "<username>",
+  "password": "<password>"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of the directory that contains your
+                    Subversion repositories.
+                </li>
+                <li>
+                    Set <Field>username</Field> and <Field>password</Field> to the credentials of a user that can read
+                    the repositories, or remove them if the repositories allow anonymous access.
+                </li>
+            </ol>
+            <p>
+                Each repository is converted into a Git repository. By default all repositories under{' '}
+                <Field>url</Field> with the standard trunk/branches/tags layout are mirrored. See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/svn#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addRepo',
+            label: 'Add a repository',
+            run: config => {
+                const value = '<repository name>'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'mapAuthor',
+            label: 'Map an author',
+            run: config => {
+                const value = 'Jane Doe <jane@example.com>'
+                const edits = setProperty(config, ['authors', '<svn username>'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+
+const NPM: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.NPM,
+    title: 'npm',
+    icon: GitIcon,
+    jsonSchema: npmSchemaJSON,
+    defaultDisplayName: 'npm',
+    defaultConfig: `{
+  "packages": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, add the npm packages to mirror to the <Field>packages</Field> field, or
+                    set <Field>lockfile</Field> to the contents of a <code>package-lock.json</code> file to mirror every version it locks.
+                </li>
+                <li>
+                    To mirror from a private registry instead of the public npm registry, set <Field>registry</Field> to its URL.
+                </li>
+            </ol>
+            <p>
+                Each package is mirrored as a repository with one tagged commit per published version. See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/package_registries#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addPackage',
+            label: 'Add a package',
+            run: config => {
+                const value = '<package name>@<version>'
+                const edits = setProperty(config, ['packages', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+
+const MAVEN: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.MAVEN,
+    title: 'Maven',
+    icon: GitIcon,
+    jsonSchema: mavenSchemaJSON,
+    defaultDisplayName: 'Maven',
+    defaultConfig: `{
+  "packages": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, add the Maven artifacts to mirror to the <Field>packages</Field> field, or
+                    set <Field>lockfile</Field> to the contents of a <code>gradle.lockfile</code> file to mirror every version it locks.
+                </li>
+                <li>
+                    To mirror from a Maven repository other than Maven Central, set <Field>registry</Field> to its URL.
+                </li>
+            </ol>
+            <p>
+                Each package is mirrored as a repository with one tagged commit per published version. See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/package_registries#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addPackage',
+            label: 'Add a package',
+            run: config => {
+                const value = '<group>:<artifact>:<version>'
+                const edits = setProperty(config, ['packages', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+
+const GO_MODULES: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.GOMODULES,
+    title: 'Go modules',
+    icon: GitIcon,
+    jsonSchema: goModulesSchemaJSON,
+    defaultDisplayName: 'Go modules',
+    defaultConfig: `{
+  "packages": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, add the Go modules to mirror to the <Field>packages</Field> field, or
+                    set <Field>lockfile</Field> to the contents of a <code>go.sum</code> file to mirror every version it locks.
+                </li>
+                <li>
+                    To mirror from a module proxy other than proxy.golang.org, set <Field>registry</Field> to its URL.
+                </li>
+            </ol>
+            <p>
+                Each package is mirrored as a repository with one tagged commit per published version. See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/package_registries#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addPackage',
+            label: 'Add a package',
+            run: config => {
+                const value = '<module path>@<version>'
+                const edits = setProperty(config, ['packages', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+
 const GENERIC_GIT: AddExternalServiceOptions = {
     kind: GQL.ExternalServiceKind.OTHER,
     title: 'Generic Git host',
@@ -1056,6 +1308,11 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
     gitolite: GITOLITE,
+    perforce: PERFORCE,
+    svn: SVN,
+    npm: NPM,
+    maven: MAVEN,
+    gomodules: GO_MODULES,
     git: GENERIC_GIT,
 }
 
@@ -1077,4 +1334,9 @@ export const defaultExternalServices: Record<GQL.ExternalServiceKind, AddExterna
     [GQL.ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [GQL.ExternalServiceKind.OTHER]: GENERIC_GIT,
     [GQL.ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
+    [GQL.ExternalServiceKind.PERFORCE]: PERFORCE,
+    [GQL.ExternalServiceKind.SVN]: SVN,
+    [GQL.ExternalServiceKind.NPM]: NPM,
+    [GQL.ExternalServiceKind.MAVEN]: MAVEN,
+    [GQL.ExternalServiceKind.GOMODULES]: GO_MODULES,
 }